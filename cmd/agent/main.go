@@ -0,0 +1,199 @@
+// Package main реализует агента, который выполняет операции оркестратора в отдельном процессе.
+//
+// Агент регистрируется в gRPC сервисе агентов оркестратора (ORCHESTRATOR_AGENT_GRPC_PORT)
+// с общим токеном ORCHESTRATOR_AGENT_TOKEN, периодически забирает назначенные ему операции, выполняет их и сообщает результаты.
+// Представление чисел и округление берутся из конфигурации оркестратора.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	agentclient "github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/grpc/clients/agent"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/app/agent/worker"
+	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/setup"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/config"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	ErrInitLogger = "failed to initialize logger"
+	ErrLoadConfig = "failed to load configuration"
+	ErrConnect    = "failed to connect to orchestrator agent service"
+	ErrRegister   = "failed to register agent"
+	ErrFetch      = "failed to fetch operations"
+	ErrReport     = "failed to report operation result"
+)
+
+const (
+	LogAgentStarted    = "agent started"
+	LogAgentRegistered = "agent registered"
+	LogAgentStopped    = "agent stopped"
+)
+
+const (
+	defaultCapacity     = 3
+	defaultPollInterval = 500 * time.Millisecond
+)
+
+func main() {
+	address := flag.String("address", "", "orchestrator agent service address (default from ORCHESTRATOR_AGENT_GRPC_HOST and ORCHESTRATOR_AGENT_GRPC_PORT)")
+	agentID := flag.String("id", "", "agent ID (generated by the orchestrator when empty)")
+	capacity := flag.Int("capacity", defaultCapacity, "agent capacity in operation cost units")
+	poll := flag.Duration("poll", defaultPollInterval, "interval between operation fetches")
+	flag.Parse()
+
+	os.Exit(run(*address, *agentID, *capacity, *poll))
+}
+
+func run(address, agentID string, capacity int, poll time.Duration) int {
+	log, err := logger.Development()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", ErrInitLogger, err)
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx = logger.WithLogger(ctx, log)
+
+	cfg, err := config.Load[setup.OrchestratorConfig](ctx)
+	if err != nil {
+		logger.Error(ctx, log, ErrLoadConfig, zap.Error(err))
+		return 1
+	}
+
+	if address == "" {
+		address = cfg.GetOrchestratorAgentGRPCAddress()
+	}
+
+	client, err := agentclient.NewClient(ctx, address, cfg.OrchGrpc.AgentToken)
+	if err != nil {
+		logger.Error(ctx, log, ErrConnect, zap.String("address", address), zap.Error(err))
+		return 1
+	}
+	defer func() {
+		if err := client.Close(); err != nil {
+			logger.Warn(ctx, log, "Failed to close connection", zap.Error(err))
+		}
+	}()
+
+	logger.Info(ctx, log, LogAgentStarted, zap.String("address", address), zap.Int("capacity", capacity))
+
+	a := &remoteAgent{
+		client:     client,
+		capacity:   capacity,
+		arithmetic: cfg.GetArithmetic(),
+	}
+	if err := a.register(ctx, agentID); err != nil {
+		logger.Error(ctx, log, ErrRegister, zap.Error(err))
+		return 1
+	}
+
+	a.loop(ctx, poll)
+
+	logger.Info(ctx, log, LogAgentStopped, zap.String("agent_id", a.id))
+	return 0
+}
+
+// remoteAgent выполняет операции, полученные от оркестратора.
+type remoteAgent struct {
+	client     *agentclient.Client
+	id         string
+	capacity   int
+	arithmetic orchestrator.Arithmetic
+}
+
+// register регистрирует агента и запоминает назначенный оркестратором ID.
+func (a *remoteAgent) register(ctx context.Context, agentID string) error {
+	registered, err := a.client.RegisterRemote(ctx, agentID, a.capacity)
+	if err != nil {
+		return fmt.Errorf("register agent %q: %w", agentID, err)
+	}
+
+	a.id = registered.ID
+	logger.ContextLogger(ctx, nil).Info(LogAgentRegistered,
+		zap.String("agent_id", a.id),
+		zap.Int("capacity", registered.MaxCapacity))
+	return nil
+}
+
+// loop забирает операции каждые poll до завершения ctx. Операции одной выборки
+// выполняются параллельно; следующая выборка начинается после их завершения.
+// Если оркестратор забыл агента (перезапуск или вытеснение), агент регистрируется заново.
+func (a *remoteAgent) loop(ctx context.Context, poll time.Duration) {
+	log := logger.ContextLogger(ctx, nil)
+
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		tasks, err := a.client.FetchOperations(ctx, a.id, a.capacity)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Warn(ErrFetch, zap.Error(err))
+			if errors.Is(err, domainerrors.ErrAgentNotFound) {
+				if err := a.register(ctx, a.id); err != nil {
+					log.Warn(ErrRegister, zap.Error(err))
+				}
+			}
+			continue
+		}
+
+		var wg sync.WaitGroup
+		for _, task := range tasks {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				a.execute(ctx, task)
+			}()
+		}
+		wg.Wait()
+	}
+}
+
+// execute выполняет операцию и сообщает результат. Операция, прерванная остановкой
+// агента, не сообщается: оркестратор вернет ее в очередь.
+func (a *remoteAgent) execute(ctx context.Context, task orchestrator.AgentTask) {
+	op := task.Operation
+	log := logger.ContextLogger(ctx, nil).With(zap.String("operation_id", op.ID.String()))
+
+	result, err := worker.Compute(op.OperationType, op.Operand1, op.Operand2, a.arithmetic)
+	errMsg := ""
+	if err != nil {
+		result, errMsg = "", err.Error()
+	} else if task.Duration > 0 {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(task.Duration):
+		}
+	}
+
+	if err := a.client.ReportResult(ctx, a.id, op.ID, result, errMsg); err != nil {
+		log.Warn(ErrReport, zap.Error(err))
+		return
+	}
+	log.Debug("Operation executed",
+		zap.String("operation", op.OperationType.Name()),
+		zap.String("result", result),
+		zap.String("error", errMsg))
+}
@@ -9,30 +9,52 @@ import (
 	"strings"
 	"time"
 
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/db/postgres"
 	pgauth "github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/db/postgres/auth"
 	grpcserver "github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/grpc"
 	grpcauth "github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/grpc/auth"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/services/jwt"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/services/password"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/app/auth/cleanup"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/app/auth/usecase"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/setup"
+	jwtsetup "github.com/flexer2006/y.lms-final-task-calc-go/internal/setup/jwt"
 	authv1 "github.com/flexer2006/y.lms-final-task-calc-go/pkg/api/proto/v1/auth"
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/config"
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/database"
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/database/migrate"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/health"
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/shutdown"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/tracing"
 	"go.uber.org/zap"
 )
 
+// tracingServiceName - имя сервиса в трассах.
+const tracingServiceName = "auth"
+
 const (
-	ErrInitLogger     = "failed to initialize logger"
-	ErrSyncLogger     = "failed to sync logger"
-	ErrLoadConfig     = "failed to load configuration"
-	ErrInitDB         = "failed to initialize database"
-	ErrRunMigrations  = "failed to run migrations"
-	ErrInitGRPCServer = "failed to initialize gRPC server"
-	ErrStartGRPC      = "failed to start gRPC server"
+	// pathMetrics - путь метрик Prometheus на сервере проверок.
+	pathMetrics = "/metrics"
+	// metricsNamespace - префикс метрик сервиса.
+	metricsNamespace = "calc_auth"
+)
+
+const (
+	ErrInitLogger       = "failed to initialize logger"
+	ErrSyncLogger       = "failed to sync logger"
+	ErrLoadConfig       = "failed to load configuration"
+	ErrInitDB           = "failed to initialize database"
+	ErrRunMigrations    = "failed to run migrations"
+	ErrInitGRPCServer   = "failed to initialize gRPC server"
+	ErrStartGRPC        = "failed to start gRPC server"
+	ErrInitHealthServer = "failed to start health server"
+	ErrStopHealthServer = "failed to stop health server"
+	ErrInitPassword     = "failed to initialize password service"
+	ErrInitJWT          = "failed to initialize JWT service"
+	ErrInitTracing      = "failed to initialize tracing"
+	ErrStopTracing      = "failed to shut down tracing"
+	ErrStopCleanup      = "failed to stop token cleanup"
 )
 
 const (
@@ -52,6 +74,7 @@ const (
 	LogClosingDB           = "closing database connections"
 	LogInitGRPCServer      = "initializing gRPC server"
 	LogGRPCListening       = "gRPC server listening"
+	LogHealthListening     = "health server listening"
 	LogGRPCShutdown        = "shutting down gRPC server"
 	LogRegisteringService  = "registering auth gRPC service"
 	LogInitServices        = "initializing services"
@@ -108,19 +131,33 @@ func main() {
 	logger.Info(ctx, log, LogConfigLoaded)
 
 	var logImpl logger.ZapLogger
+	sampling := logger.WithSampling(cfg.Logger.SamplingInitial, cfg.Logger.SamplingThereafter)
 	if cfg.Logger.Model == "production" {
-		logImpl, err = logger.Production()
+		logImpl, err = logger.Production(sampling)
 	} else {
-		logImpl, err = logger.Development()
+		logImpl, err = logger.Development(sampling)
 	}
 	if err != nil {
 		logger.Error(ctx, log, ErrInitLogger, zap.Error(err))
 		exitCode = 1
 		return
 	}
-	log = logImpl
+	log = logger.Redacted(logImpl, cfg.Logger.RedactFields)
 	ctx = logger.WithLogger(ctx, log)
 
+	tracingConfig := cfg.GetTracingConfig()
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Config{
+		ServiceName: tracingServiceName,
+		Endpoint:    tracingConfig.Endpoint,
+		Insecure:    tracingConfig.Insecure,
+		SampleRatio: tracingConfig.SampleRatio,
+	})
+	if err != nil {
+		logger.Error(ctx, log, ErrInitTracing, zap.Error(err))
+		exitCode = 1
+		return
+	}
+
 	logger.Info(ctx, log, LogInitDB)
 
 	dbConfig := cfg.ToPostgresConfig()
@@ -151,23 +188,56 @@ func main() {
 
 	logger.Info(ctx, log, "Initializing repositories")
 	userRepo := pgauth.NewUserRepository(dbHandler)
+	userRepo.SetStatementTimeout(cfg.AuthDbPostgres.StatementTimeout)
 	tokenRepo := pgauth.NewTokenRepository(dbHandler)
+	tokenRepo.SetStatementTimeout(cfg.AuthDbPostgres.StatementTimeout)
 	logger.Info(ctx, log, "Repositories initialized")
 
 	logger.Info(ctx, log, LogInitServices)
 	jwtConfig := cfg.GetJWTConfig()
-	passwordService := password.NewService(jwtConfig.BCryptCost)
-	jwtService := jwt.NewService(
-		jwtConfig.SecretKey,
-		jwtConfig.AccessTokenTTL,
-		jwtConfig.RefreshTokenTTL,
-	)
+	passwordConfig := cfg.GetAuthPasswordConfig()
+	passwordService, err := password.NewService(password.Algorithm(passwordConfig.Algorithm), jwtConfig.BCryptCost)
+	if err != nil {
+		logger.Error(ctx, log, ErrInitPassword, zap.Error(err))
+		exitCode = 1
+		return
+	}
+	if err := passwordService.SetArgon2Params(password.Argon2Params{
+		Memory:      passwordConfig.Argon2Memory,
+		Time:        passwordConfig.Argon2Time,
+		Parallelism: passwordConfig.Argon2Parallelism,
+	}); err != nil {
+		logger.Error(ctx, log, ErrInitPassword, zap.Error(err))
+		exitCode = 1
+		return
+	}
+	jwtService, err := newJWTService(jwtConfig)
+	if err != nil {
+		logger.Error(ctx, log, ErrInitJWT, zap.Error(err))
+		exitCode = 1
+		return
+	}
 	logger.Info(ctx, log, LogServicesInitialized)
 
 	logger.Info(ctx, log, "Initializing use cases")
-	authUseCase := usecase.NewAuthUseCase(userRepo, tokenRepo, passwordService, jwtService)
+	authUseCase := usecase.NewAuthUseCase(userRepo, tokenRepo, passwordService, jwtService, jwtConfig.RefreshReuseDetection)
+	authUseCase.SetRevokeOnPasswordChange(jwtConfig.RevokeOnPasswordChange)
+	authUseCase.SetTxManager(database.NewTxManager(dbHandler))
+	authUseCase.SetRefreshTokenMaxAge(jwtConfig.RefreshMaxAge)
+	authUseCase.SetDBPoolStatsProvider(postgres.NewPoolStatsProvider(dbHandler))
+	loginConfig := cfg.GetAuthLoginConfig()
+	authUseCase.SetLoginLimit(loginConfig.MaxFailures, loginConfig.FailureWindow, loginConfig.DelayBase, loginConfig.MaxDelay)
 	logger.Info(ctx, log, "Use cases initialized")
 
+	cleanupConfig := cfg.GetAuthCleanupConfig()
+	tokenCleanup := cleanup.NewScheduler(authUseCase, cleanupConfig.Interval, cleanupConfig.Jitter)
+	tokenCleanup.Start(ctx)
+	if cleanupConfig.Interval > 0 {
+		logger.Info(ctx, log, "Expired token cleanup enabled",
+			zap.Duration("interval", cleanupConfig.Interval),
+			zap.Float64("jitter", cleanupConfig.Jitter))
+	}
+
 	logger.Info(ctx, log, LogInitGRPCServer)
 	grpcConfig := cfg.GetAuthGRPCConfig()
 
@@ -192,16 +262,66 @@ func main() {
 		}
 	}()
 
+	var healthServer *health.Server
+	if grpcConfig.HealthPort > 0 {
+		readiness := health.NewChecker()
+		readiness.AddPinger("database", dbHandler)
+
+		healthAddress := fmt.Sprintf("%s:%d", grpcConfig.Host, grpcConfig.HealthPort)
+		healthServer = health.NewServer(healthAddress, readiness)
+		healthServer.Handle(pathMetrics, database.MetricsHandler(dbHandler, metricsNamespace))
+		if err := healthServer.Start(ctx); err != nil {
+			logger.Error(ctx, log, ErrInitHealthServer, zap.Error(err))
+			exitCode = 1
+			return
+		}
+		logger.Info(ctx, log, LogHealthListening, zap.String("address", healthAddress))
+	}
+
 	shutdown.Wait(ctx, cfg.GetShutdownTimeout(),
 		func(ctx context.Context) error {
+			if healthServer != nil {
+				if err := healthServer.Stop(ctx); err != nil {
+					logger.Error(ctx, log, ErrStopHealthServer, zap.Error(err))
+				}
+			}
+
 			logger.Info(ctx, log, LogGRPCShutdown)
 			grpcServer.GracefulStop()
 
+			if err := tokenCleanup.Stop(ctx); err != nil {
+				logger.Error(ctx, log, ErrStopCleanup, zap.Error(err))
+			}
+
 			logger.Info(ctx, log, LogClosingDB)
 			dbHandler.Close(ctx)
+
+			if err := shutdownTracing(ctx); err != nil {
+				logger.Error(ctx, log, ErrStopTracing, zap.Error(err))
+			}
 			return nil
 		},
 	)
 
 	logger.Info(ctx, log, LogServiceShutdownDone)
 }
+
+// newJWTService создает JWT сервис с алгоритмом подписи из конфигурации.
+func newJWTService(cfg jwtsetup.Config) (*jwt.Service, error) {
+	switch strings.ToUpper(cfg.SigningMethod) {
+	case "", "HS256":
+		return jwt.NewService(cfg.SecretKey, cfg.AccessTokenTTL, cfg.RefreshTokenTTL), nil
+	case "RS256":
+		privateKey, err := os.ReadFile(cfg.PrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("read private key: %w", err)
+		}
+		publicKey, err := os.ReadFile(cfg.PublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("read public key: %w", err)
+		}
+		return jwt.NewServiceRSA(privateKey, publicKey, cfg.AccessTokenTTL, cfg.RefreshTokenTTL)
+	default:
+		return nil, fmt.Errorf("%w: %s", jwt.ErrInvalidSigningMethod, cfg.SigningMethod)
+	}
+}
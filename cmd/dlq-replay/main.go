@@ -0,0 +1,97 @@
+// Package main реализует команду повторного запуска операций из таблицы dead_letter_operations.
+//
+// Команда использует конфигурацию оркестратора и возвращает записи в обработку:
+// операции получают статус PENDING и подхватываются работающим оркестратором.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	pgorch "github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/db/postgres/orchestrator"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/app/orchestrator/deadletter"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/setup"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/config"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/database"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	ErrInitLogger = "failed to initialize logger"
+	ErrLoadConfig = "failed to load configuration"
+	ErrInitDB     = "failed to initialize database"
+	ErrReplay     = "failed to replay dead letters"
+)
+
+const (
+	LogReplayStart = "replaying dead letters"
+	LogReplayDone  = "dead letters replayed"
+)
+
+const (
+	defaultLimit  = 100
+	replayTimeout = 5 * time.Minute
+	dbConnTimeout = 10 * time.Second
+)
+
+func main() {
+	limit := flag.Int("limit", defaultLimit, "maximum number of dead letters to replay")
+	flag.Parse()
+
+	os.Exit(run(*limit))
+}
+
+func run(limit int) int {
+	log, err := logger.Development()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", ErrInitLogger, err)
+		return 1
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), replayTimeout)
+	defer cancel()
+	ctx = logger.WithLogger(ctx, log)
+
+	cfg, err := config.Load[setup.OrchestratorConfig](ctx)
+	if err != nil {
+		logger.Error(ctx, log, ErrLoadConfig, zap.Error(err))
+		return 1
+	}
+
+	dbConfig := cfg.ToPostgresConfig()
+	dbConfig.ConnTimeout = dbConnTimeout
+
+	db, err := database.NewPostgres(ctx, dbConfig)
+	if err != nil {
+		logger.Error(ctx, log, ErrInitDB, zap.Error(err))
+		return 1
+	}
+	defer db.Close(ctx)
+
+	dbHandler := &database.Handler{DB: db}
+
+	replayer := deadletter.NewReplayer(
+		pgorch.NewDeadLetterRepository(dbHandler),
+		pgorch.NewOperationRepository(dbHandler),
+		pgorch.NewCalculationRepository(dbHandler),
+	)
+
+	logger.Info(ctx, log, LogReplayStart, zap.Int("limit", limit))
+	result, err := replayer.Replay(ctx, limit)
+	if err != nil {
+		logger.Error(ctx, log, ErrReplay,
+			zap.Error(err),
+			zap.Int("replayed", result.Replayed),
+			zap.Int("skipped", result.Skipped))
+		return 1
+	}
+
+	logger.Info(ctx, log, LogReplayDone,
+		zap.Int("replayed", result.Replayed),
+		zap.Int("skipped", result.Skipped))
+	return 0
+}
@@ -9,36 +9,69 @@ import (
 	"strings"
 	"time"
 
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/db/postgres"
 	pgorch "github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/db/postgres/orchestrator"
 	grpcserver "github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/grpc"
+	grpcagent "github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/grpc/agent"
 	grpcorch "github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/grpc/orchestrator"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/services/jwt"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/services/parser"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/app/orchestrator/calculation"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/app/orchestrator/compaction"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/app/orchestrator/processor"
 
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/setup"
+	jwtsetup "github.com/flexer2006/y.lms-final-task-calc-go/internal/setup/jwt"
+	redissetup "github.com/flexer2006/y.lms-final-task-calc-go/internal/setup/orchestrator/redis"
+	agentv1 "github.com/flexer2006/y.lms-final-task-calc-go/pkg/api/proto/v1/agent"
 	orchv1 "github.com/flexer2006/y.lms-final-task-calc-go/pkg/api/proto/v1/orchestrator"
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/config"
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/database"
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/database/migrate"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/health"
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/shutdown"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/tracing"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
 
 	memAgent "github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/db/memory/agent"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/db/memory/cache"
+	rediscache "github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/db/redis"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/app/agent/executor"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/app/agent/pool"
 	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// tracingServiceName - имя сервиса в трассах.
+const tracingServiceName = "orchestrator"
+
+// processorReadinessInterval - интервал повторной проверки схемы базы перед запуском процессора.
+const processorReadinessInterval = time.Second
+
+const (
+	// pathMetrics - путь метрик Prometheus на сервере проверок.
+	pathMetrics = "/metrics"
+	// metricsNamespace - префикс метрик сервиса.
+	metricsNamespace = "calc_orchestrator"
 )
 
 const (
-	ErrInitLogger     = "failed to initialize logger"
-	ErrSyncLogger     = "failed to sync logger"
-	ErrLoadConfig     = "failed to load configuration"
-	ErrInitDB         = "failed to initialize database"
-	ErrRunMigrations  = "failed to run migrations"
-	ErrInitGRPCServer = "failed to initialize gRPC server"
-	ErrStartGRPC      = "failed to start gRPC server"
+	ErrInitLogger       = "failed to initialize logger"
+	ErrSyncLogger       = "failed to sync logger"
+	ErrLoadConfig       = "failed to load configuration"
+	ErrInitDB           = "failed to initialize database"
+	ErrRunMigrations    = "failed to run migrations"
+	ErrInitGRPCServer   = "failed to initialize gRPC server"
+	ErrInitJWTVerifier  = "failed to initialize JWT verifier"
+	ErrInitTracing      = "failed to initialize tracing"
+	ErrStopTracing      = "failed to shut down tracing"
+	ErrStartGRPC        = "failed to start gRPC server"
+	ErrInitHealthServer = "failed to start health server"
+	ErrStopHealthServer = "failed to stop health server"
+	ErrCloseRedis       = "failed to close redis client"
+	ErrAgentToken       = "ORCHESTRATOR_AGENT_TOKEN is required when ORCHESTRATOR_AGENT_GRPC_PORT is set"
 )
 
 const (
@@ -56,9 +89,12 @@ const (
 	LogRunMigrations       = "running database migrations"
 	LogMigrationsCompleted = "database migrations completed"
 	LogClosingDB           = "closing database connections"
+	LogRedisUnavailable    = "redis is unavailable, results are not cached until it recovers"
 	LogAgentsInfo          = "agent configuration loaded"
 	LogInitGRPCServer      = "initializing gRPC server"
 	LogGRPCListening       = "gRPC server listening"
+	LogAgentGRPCListening  = "agent gRPC server listening"
+	LogHealthListening     = "health server listening"
 	LogGRPCShutdown        = "shutting down gRPC server"
 	LogRegisteringService  = "registering orchestrator gRPC service"
 	LogInitServices        = "initializing services"
@@ -121,7 +157,9 @@ func main() {
 		zap.Duration("time_addition", agentConfig.TimeAddition),
 		zap.Duration("time_subtraction", agentConfig.TimeSubtraction),
 		zap.Duration("time_multiplication", agentConfig.TimeMultiplications),
-		zap.Duration("time_division", agentConfig.TimeDivisions))
+		zap.Duration("time_division", agentConfig.TimeDivisions),
+		zap.Duration("time_modulo", agentConfig.TimeModulo),
+		zap.Duration("time_factorial", agentConfig.TimeFactorial))
 
 	grpcConfig := cfg.GetOrchestratorGRPCConfig()
 	logger.Info(ctx, log, "gRPC configuration loaded",
@@ -131,19 +169,33 @@ func main() {
 	logger.Info(ctx, log, LogConfigLoaded)
 
 	var logImpl logger.ZapLogger
+	sampling := logger.WithSampling(cfg.Logger.SamplingInitial, cfg.Logger.SamplingThereafter)
 	if cfg.Logger.Model == "production" {
-		logImpl, err = logger.Production()
+		logImpl, err = logger.Production(sampling)
 	} else {
-		logImpl, err = logger.Development()
+		logImpl, err = logger.Development(sampling)
 	}
 	if err != nil {
 		logger.Error(ctx, log, ErrInitLogger, zap.Error(err))
 		exitCode = 1
 		return
 	}
-	log = logImpl
+	log = logger.Redacted(logImpl, cfg.Logger.RedactFields)
 	ctx = logger.WithLogger(ctx, log)
 
+	tracingConfig := cfg.GetTracingConfig()
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Config{
+		ServiceName: tracingServiceName,
+		Endpoint:    tracingConfig.Endpoint,
+		Insecure:    tracingConfig.Insecure,
+		SampleRatio: tracingConfig.SampleRatio,
+	})
+	if err != nil {
+		logger.Error(ctx, log, ErrInitTracing, zap.Error(err))
+		exitCode = 1
+		return
+	}
+
 	logger.Info(ctx, log, LogInitDB)
 
 	// Get base config from environment
@@ -163,24 +215,31 @@ func main() {
 		zap.Duration("max_lifetime", dbConfig.MaxConnLifetime),
 		zap.Duration("idle_timeout", dbConfig.MaxConnIdleTime))
 
-	db, err := database.NewPostgres(ctx, dbConfig)
+	replicaConfigs, err := cfg.ToReplicaPostgresConfigs()
 	if err != nil {
 		logger.Error(ctx, log, ErrInitDB, zap.Error(err))
 		exitCode = 1
 		return
 	}
-	logger.Info(ctx, log, LogDBInitialized)
-
-	logger.Info(ctx, log, LogRunMigrations)
-
-	dbHandler := &database.Handler{
-		DB:       db,
-		Migrator: database.NewMigrator(),
+	for i := range replicaConfigs {
+		replicaConfigs[i].ConnTimeout = dbConfig.ConnTimeout
+		replicaConfigs[i].HealthPeriod = dbConfig.HealthPeriod
 	}
 
 	migrateConfig := migrate.Config{
 		Path: cfg.GetOrchestratorPgxConfig().MigratePath,
 	}
+
+	dbHandler, err := database.NewHandlerWithReplicas(ctx, dbConfig, replicaConfigs, migrateConfig)
+	if err != nil {
+		logger.Error(ctx, log, ErrInitDB, zap.Error(err))
+		exitCode = 1
+		return
+	}
+	logger.Info(ctx, log, LogDBInitialized, zap.Int("read_replicas", len(replicaConfigs)))
+
+	logger.Info(ctx, log, LogRunMigrations)
+
 	if err := dbHandler.MigrateUp(ctx, migrateConfig); err != nil {
 		logger.Error(ctx, log, ErrRunMigrations, zap.Error(err))
 		exitCode = 1
@@ -190,26 +249,80 @@ func main() {
 
 	logger.Info(ctx, log, "Initializing repositories")
 	calculationRepo := pgorch.NewCalculationRepository(dbHandler)
+	calculationRepo.SetStatementTimeout(cfg.OrchDbPostgres.StatementTimeout)
 	operationRepo := pgorch.NewOperationRepository(dbHandler)
+	operationRepo.SetStatementTimeout(cfg.OrchDbPostgres.StatementTimeout)
 	logger.Info(ctx, log, "Repositories initialized")
 
 	logger.Info(ctx, log, LogInitServices)
 	parserService := parser.NewService(cfg.GetMaxOperations())
+	parserService.SetImplicitZeroOperand(agentConfig.ImplicitZeroOperand)
+	parserService.SetMaxExpressionLength(cfg.GetMaxExpressionLength())
+	parserService.SetDivisionByZeroPolicy(cfg.GetArithmetic().DivisionByZero)
 	logger.Info(ctx, log, LogServicesInitialized)
 
 	logger.Info(ctx, log, "Initializing use cases")
 	calculationUseCase := calculation.NewUseCase(calculationRepo, operationRepo, parserService)
+	calculationUseCase.SetStaleRecompute(agentConfig.StaleRecomputeAfter)
+	calculationUseCase.SetEmptyOperationsGrace(agentConfig.EmptyOperationsGrace)
+	calculationUseCase.SetParsingTimeout(agentConfig.ParsingTimeout)
+	calculationUseCase.SetPartialBatchInsert(agentConfig.PartialBatchInsert)
+	calculationUseCase.SetRejectDuplicateOperationIDs(agentConfig.RejectDuplicateOperationIDs)
+	calculationUseCase.SetAgentEstimate(agentConfig.AgentEstimate)
+	calculationUseCase.SetCalculationReferences(agentConfig.CalculationReferences)
+	calculationUseCase.SetSupportedOperations(agentConfig.SupportedOperations)
+	calculationUseCase.SetListResultCap(agentConfig.ListResultCap)
+	calculationUseCase.SetPendingOperationsBudget(agentConfig.PendingOperationsBudget)
+	// Округление задается до прогрева кэша: результаты прогрева округляются так же
+	rounding := cfg.GetResultRounding()
+	if !rounding.Mode.IsValid() {
+		logger.Warn(ctx, log, "Unknown result rounding mode, using half_even", zap.String("mode", string(rounding.Mode)))
+	}
+	calculationUseCase.SetResultRounding(rounding)
+
+	redisConfig := cfg.GetOrchestratorRedisConfig()
+	var redisClient *goredis.Client
+	switch {
+	case redisConfig.Addr != "":
+		redisClient = newRedisClient(redisConfig)
+		calculationUseCase.SetResultCache(
+			rediscache.NewResultCache(redisClient, redisConfig.KeyPrefix, redisConfig.TTL))
+		if err := pingRedis(ctx, redisClient, redisConfig.Timeout); err != nil {
+			logger.Warn(ctx, log, LogRedisUnavailable, zap.String("address", redisConfig.Addr), zap.Error(err))
+		} else {
+			calculationUseCase.WarmResultCache(ctx, agentConfig.ResultCacheWarmup)
+		}
+	case agentConfig.ResultCacheSize > 0:
+		calculationUseCase.SetResultCache(cache.NewResultCache(agentConfig.ResultCacheSize))
+		calculationUseCase.WarmResultCache(ctx, agentConfig.ResultCacheWarmup)
+	}
+	calculationUseCase.SetTxManager(database.NewTxManager(dbHandler))
+	calculationUseCase.SetDBPoolStatsProvider(postgres.NewPoolStatsProvider(dbHandler))
+	dbMonitor := health.NewMonitor(dbHandler, agentConfig.DBHealthInterval, agentConfig.DBHealthFailureThreshold)
+	if agentConfig.DBHealthInterval > 0 {
+		calculationUseCase.SetDBHealth(dbMonitor)
+		dbMonitor.Start(ctx)
+	}
 	logger.Info(ctx, log, "Use cases initialized")
 
 	logger.Info(ctx, log, "Initializing agent components")
 
-	agentStorage := memAgent.NewAgentStorage()
+	agentStorage := memAgent.NewShardedAgentStorage(agentConfig.StorageShards)
+	agentStorage.SetLockWaitLogging(log.RawLogger(), agentConfig.StorageLockWarnThreshold)
 
 	operationTimes := map[string]time.Duration{
 		"addition":       agentConfig.TimeAddition,
 		"subtraction":    agentConfig.TimeSubtraction,
 		"multiplication": agentConfig.TimeMultiplications,
 		"division":       agentConfig.TimeDivisions,
+		"modulo":         agentConfig.TimeModulo,
+		"factorial":      agentConfig.TimeFactorial,
+	}
+
+	// По времени операций оценивается, когда клиенту стоит запросить результат.
+	// В детерминированном режиме задержек нет, и оценка не нужна.
+	if !agentConfig.Deterministic {
+		calculationUseCase.SetOperationTimes(operationTimes)
 	}
 
 	agentPool, err := pool.NewAgentPool(agentStorage, operationRepo, operationTimes, agentConfig.ComputerPower)
@@ -218,9 +331,36 @@ func main() {
 		exitCode = 1
 		return
 	}
+	agentPool.SetDeterministic(agentConfig.Deterministic)
+	if err := agentPool.SetOperationCosts(cfg.GetAgentOperationCosts()); err != nil {
+		logger.Error(ctx, log, "Invalid agent operation costs", zap.Error(err))
+		exitCode = 1
+		return
+	}
+	agentPool.SetOperationTimeouts(cfg.GetAgentOperationTimeouts())
+	arithmetic := cfg.GetArithmetic()
+	if !arithmetic.Backend.IsValid() {
+		logger.Warn(ctx, log, "Unknown arithmetic backend, using float", zap.String("backend", string(arithmetic.Backend)))
+	}
+	agentPool.SetArithmetic(arithmetic)
+	calculationUseCase.SetDisplayRounding(cfg.GetDisplayRounding())
+	agentPool.SetStatusBatching(agentConfig.StatusFlushInterval, agentConfig.StatusFlushBatchSize)
+	agentPool.SetHeartbeat(agentConfig.HeartbeatInterval, agentConfig.HeartbeatTimeout)
+	agentPool.SetAutoscale(pool.AutoscaleConfig{
+		MinWorkers:       agentConfig.AutoscaleMinWorkers,
+		MaxWorkers:       agentConfig.AutoscaleMaxWorkers,
+		BacklogThreshold: agentConfig.AutoscaleBacklogThreshold,
+		SustainFor:       agentConfig.AutoscaleSustain,
+		IdleFor:          agentConfig.AutoscaleIdle,
+		Interval:         agentConfig.AutoscaleInterval,
+	})
+	if agentConfig.Deterministic {
+		logger.Warn(ctx, log, "Deterministic mode enabled: operation times are ignored")
+	}
 	agentPool.Start(ctx)
 
-	operationExecutor := executor.NewOperationExecutor(agentPool, 3, 500*time.Millisecond)
+	retryPolicy := cfg.GetRetryPolicy()
+	operationExecutor := executor.NewOperationExecutor(agentPool, retryPolicy)
 
 	logger.Info(ctx, log, "Agent components initialized")
 
@@ -232,9 +372,14 @@ func main() {
 		TimeSubtraction:     agentConfig.TimeSubtraction,
 		TimeMultiplications: agentConfig.TimeMultiplications,
 		TimeDivisions:       agentConfig.TimeDivisions,
+		TimeModulo:          agentConfig.TimeModulo,
+		TimeFactorial:       agentConfig.TimeFactorial,
+		OperationTimeout:    agentConfig.OperationTimeout,
+		OperationTimeouts:   cfg.GetAgentOperationTimeouts(),
+		AttemptTimeout:      agentConfig.OperationAttemptTimeout,
 	}
 
-	operationProcessor := processor.NewProcessor(
+	operationProcessor, err := processor.NewProcessorSafe(
 		operationRepo,
 		calculationRepo,
 		calculationUseCase,
@@ -242,6 +387,45 @@ func main() {
 		operationExecutor,
 		agentPool,
 	)
+	if err != nil {
+		logger.Error(ctx, log, "Failed to create operation processor", zap.Error(err))
+		exitCode = 1
+		return
+	}
+
+	if agentConfig.DeadLetterEnabled {
+		deadLetterRepo := pgorch.NewDeadLetterRepository(dbHandler)
+		deadLetterRepo.SetStatementTimeout(cfg.OrchDbPostgres.StatementTimeout)
+		operationProcessor.SetDeadLetterRepository(deadLetterRepo)
+	}
+	operationProcessor.SetRetryPolicy(retryPolicy)
+	if agentConfig.DispatchDryRun {
+		logger.Warn(ctx, log, "Dispatch dry run enabled: pending operations will be logged, not executed")
+		operationProcessor.SetDryRun(true)
+	}
+
+	// Процессор не опрашивает таблицы, пока схема не мигрирована до последней известной версии
+	if schemaVersion, err := migrate.LatestVersion(migrateConfig); err != nil {
+		logger.Warn(ctx, log, "Failed to determine expected schema version, processor starts without readiness check", zap.Error(err))
+	} else {
+		operationProcessor.SetReadinessCheck(func(ctx context.Context) error {
+			return dbHandler.CheckSchemaVersion(ctx, migrateConfig, schemaVersion)
+		}, processorReadinessInterval)
+	}
+
+	if agentConfig.OperationsCompactionDelay > 0 {
+		compactor := compaction.NewCompactor(calculationRepo, operationRepo, database.NewTxManager(dbHandler))
+		go compactor.Run(ctx, agentConfig.OperationsCompactionInterval,
+			agentConfig.OperationsCompactionDelay, agentConfig.OperationsCompactionBatchSize)
+		logger.Info(ctx, log, "Operations compaction enabled",
+			zap.Duration("delay", agentConfig.OperationsCompactionDelay),
+			zap.Duration("interval", agentConfig.OperationsCompactionInterval))
+	}
+
+	// Процессор хранит отмененные операции: сервис вычислений сообщает о них, агенты пропускают их.
+	calculationUseCase.SetOperationCancellation(operationProcessor)
+	agentPool.SetCancellation(operationProcessor)
+	calculationUseCase.SetAgentPool(agentPool)
 
 	if err := operationProcessor.Start(ctx); err != nil {
 		logger.Error(ctx, log, "Failed to start operation processor", zap.Error(err))
@@ -252,9 +436,17 @@ func main() {
 
 	logger.Info(ctx, log, LogInitGRPCServer)
 
-	grpcServer := grpcserver.NewServerOrchestrator()
+	tokenVerifier, err := newJWTVerifier(cfg.GetJWTConfig())
+	if err != nil {
+		logger.Error(ctx, log, ErrInitJWTVerifier, zap.Error(err))
+		exitCode = 1
+		return
+	}
+
+	grpcServer := grpcserver.NewServerOrchestrator(tokenVerifier, grpcConfig.AdminUserIDs)
 
 	orchestratorServer := grpcorch.NewServer(calculationUseCase)
+	orchestratorServer.SetServiceLogger(log)
 	logger.Info(ctx, log, LogRegisteringService)
 	orchv1.RegisterOrchestratorServiceServer(grpcServer, orchestratorServer)
 
@@ -273,10 +465,64 @@ func main() {
 		}
 	}()
 
+	// Сервис для агентов в отдельных процессах слушает свой порт и принимает только
+	// запросы с общим токеном агентов
+	var agentServer *grpc.Server
+	if grpcConfig.AgentPort > 0 {
+		if grpcConfig.AgentToken == "" {
+			logger.Error(ctx, log, ErrAgentToken)
+			exitCode = 1
+			return
+		}
+
+		agentServer = grpcserver.NewServerAgent(grpcConfig.AgentToken)
+		agentv1.RegisterAgentServiceServer(agentServer, grpcagent.NewServer(agentPool))
+
+		agentAddress := cfg.GetOrchestratorAgentGRPCAddress()
+		agentListener, err := net.Listen("tcp", agentAddress)
+		if err != nil {
+			logger.Error(ctx, log, ErrInitGRPCServer, zap.Error(err))
+			exitCode = 1
+			return
+		}
+
+		go func() {
+			logger.Info(ctx, log, LogAgentGRPCListening, zap.String("address", agentAddress))
+			if err := agentServer.Serve(agentListener); err != nil {
+				logger.Error(ctx, log, ErrStartGRPC, zap.Error(err))
+			}
+		}()
+	}
+
+	var healthServer *health.Server
+	if grpcConfig.HealthPort > 0 {
+		readiness := health.NewChecker()
+		readiness.AddPinger("database", dbMonitor)
+
+		healthAddress := fmt.Sprintf("%s:%d", grpcConfig.Host, grpcConfig.HealthPort)
+		healthServer = health.NewServer(healthAddress, readiness)
+		healthServer.Handle(pathMetrics, database.MetricsHandler(dbHandler, metricsNamespace))
+		if err := healthServer.Start(ctx); err != nil {
+			logger.Error(ctx, log, ErrInitHealthServer, zap.Error(err))
+			exitCode = 1
+			return
+		}
+		logger.Info(ctx, log, LogHealthListening, zap.String("address", healthAddress))
+	}
+
 	shutdown.Wait(ctx, cfg.GetShutdownTimeout(),
 		func(ctx context.Context) error {
+			if healthServer != nil {
+				if err := healthServer.Stop(ctx); err != nil {
+					logger.Error(ctx, log, ErrStopHealthServer, zap.Error(err))
+				}
+			}
+
 			logger.Info(ctx, log, LogGRPCShutdown)
 			grpcServer.GracefulStop()
+			if agentServer != nil {
+				agentServer.GracefulStop()
+			}
 
 			logger.Info(ctx, log, LogProcessorShutdown)
 			operationProcessor.Stop()
@@ -285,10 +531,61 @@ func main() {
 			agentPool.Stop(ctx) // Pass context here
 
 			logger.Info(ctx, log, LogClosingDB)
-			db.Close(ctx)
+			dbHandler.Close(ctx)
+
+			if redisClient != nil {
+				if err := redisClient.Close(); err != nil {
+					logger.Error(ctx, log, ErrCloseRedis, zap.Error(err))
+				}
+			}
+
+			if err := shutdownTracing(ctx); err != nil {
+				logger.Error(ctx, log, ErrStopTracing, zap.Error(err))
+			}
 			return nil
 		},
 	)
 
 	logger.Info(ctx, log, LogServiceShutdownDone)
 }
+
+// newRedisClient создает клиента Redis для общего кэша результатов. Повторы команд
+// отключены: при недоступном Redis вычисление должно сразу продолжиться без кэша.
+func newRedisClient(cfg redissetup.Config) *goredis.Client {
+	return goredis.NewClient(&goredis.Options{
+		Addr:         cfg.Addr,
+		Password:     cfg.Password,
+		DB:           cfg.DB,
+		DialTimeout:  cfg.Timeout,
+		ReadTimeout:  cfg.Timeout,
+		WriteTimeout: cfg.Timeout,
+		MaxRetries:   -1,
+	})
+}
+
+// pingRedis проверяет доступность Redis при запуске.
+func pingRedis(ctx context.Context, client *goredis.Client, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return client.Ping(ctx).Err()
+}
+
+// newJWTVerifier создает JWT сервис для проверки токенов доступа, выпущенных сервисом
+// авторизации. Для RS256 нужен только открытый ключ, и выпускать токены сервис не может.
+func newJWTVerifier(cfg jwtsetup.Config) (*jwt.Service, error) {
+	switch strings.ToUpper(cfg.SigningMethod) {
+	case "", "HS256":
+		return jwt.NewService(cfg.SecretKey, cfg.AccessTokenTTL, cfg.RefreshTokenTTL), nil
+	case "RS256":
+		publicKey, err := os.ReadFile(cfg.PublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("read public key: %w", err)
+		}
+		return jwt.NewVerifierRSA(publicKey, cfg.AccessTokenTTL, cfg.RefreshTokenTTL)
+	default:
+		return nil, fmt.Errorf("%w: %s", jwt.ErrInvalidSigningMethod, cfg.SigningMethod)
+	}
+}
@@ -11,15 +11,22 @@ import (
 	httpserver "github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/http"
 
 	authclient "github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/grpc/clients/auth"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/grpc/clients/dial"
 	orchclient "github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/grpc/clients/orchestrator"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/http/midleware"
 
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/setup"
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/config"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/health"
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/shutdown"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/tracing"
 	"go.uber.org/zap"
 )
 
+// tracingServiceName - имя сервиса в трассах.
+const tracingServiceName = "gateway"
+
 const (
 	ErrInitLogger     = "failed to initialize logger"
 	ErrSyncLogger     = "failed to sync logger"
@@ -28,6 +35,8 @@ const (
 	ErrConnectOrch    = "failed to connect to orchestrator service"
 	ErrInitHTTPServer = "failed to initialize HTTP server"
 	ErrStartHTTP      = "failed to start HTTP server"
+	ErrInitTracing    = "failed to initialize tracing"
+	ErrStopTracing    = "failed to shut down tracing"
 )
 
 const (
@@ -88,6 +97,16 @@ func main() {
 	}
 
 	serverConfig := cfg.GetServerConfig()
+	if _, err := midleware.ParseTrustedProxies(serverConfig.TrustedProxies); err != nil {
+		logger.Error(ctx, log, ErrLoadConfig, zap.Error(err))
+		exitCode = 1
+		return
+	}
+	if _, err := midleware.ParseSource(serverConfig.DefaultSource); err != nil {
+		logger.Error(ctx, log, ErrLoadConfig, zap.Error(err), zap.String("default_source", serverConfig.DefaultSource))
+		exitCode = 1
+		return
+	}
 	logger.Info(ctx, log, "HTTP server configuration loaded",
 		zap.String("host", serverConfig.Host),
 		zap.Int("port", serverConfig.Port))
@@ -105,23 +124,44 @@ func main() {
 	logger.Info(ctx, log, LogConfigLoaded)
 
 	var logImpl logger.ZapLogger
+	sampling := logger.WithSampling(cfg.Logger.SamplingInitial, cfg.Logger.SamplingThereafter)
 	if cfg.Logger.Model == "production" {
-		logImpl, err = logger.Production()
+		logImpl, err = logger.Production(sampling)
 	} else {
-		logImpl, err = logger.Development()
+		logImpl, err = logger.Development(sampling)
 	}
 	if err != nil {
 		logger.Error(ctx, log, ErrInitLogger, zap.Error(err))
 		exitCode = 1
 		return
 	}
-	log = logImpl
+	log = logger.Redacted(logImpl, cfg.Logger.RedactFields)
 	ctx = logger.WithLogger(ctx, log)
 
+	tracingConfig := cfg.GetTracingConfig()
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Config{
+		ServiceName: tracingServiceName,
+		Endpoint:    tracingConfig.Endpoint,
+		Insecure:    tracingConfig.Insecure,
+		SampleRatio: tracingConfig.SampleRatio,
+	})
+	if err != nil {
+		logger.Error(ctx, log, ErrInitTracing, zap.Error(err))
+		exitCode = 1
+		return
+	}
+
+	dialOpts, err := dial.Options(serverConfig.GRPCClient)
+	if err != nil {
+		logger.Error(ctx, log, ErrLoadConfig, zap.Error(err))
+		exitCode = 1
+		return
+	}
+
 	logger.Info(ctx, log, LogConnectingToAuth)
 	authAddress := fmt.Sprintf("%s:%d", authConfig.Host, authConfig.Port)
 
-	authUseCase, err := authclient.NewAuthUseCase(ctx, authAddress)
+	authUseCase, err := authclient.NewAuthUseCase(ctx, authAddress, dialOpts...)
 	if err != nil {
 		logger.Error(ctx, log, ErrConnectAuth, zap.Error(err))
 		exitCode = 1
@@ -139,7 +179,7 @@ func main() {
 	logger.Info(ctx, log, LogConnectingToOrch)
 	orchAddress := fmt.Sprintf("%s:%d", orchConfig.Host, orchConfig.Port)
 
-	orchUseCase, err := orchclient.NewCalculationUseCase(ctx, orchAddress)
+	orchUseCase, err := orchclient.NewCalculationUseCase(ctx, orchAddress, dialOpts...)
 	if err != nil {
 		logger.Error(ctx, log, ErrConnectOrch, zap.Error(err))
 		exitCode = 1
@@ -157,6 +197,15 @@ func main() {
 	logger.Info(ctx, log, LogInitHTTPServer)
 	server := httpserver.NewServer(serverConfig, authUseCase, orchUseCase)
 
+	readiness := health.NewChecker()
+	if pinger, ok := authUseCase.(health.Pinger); ok {
+		readiness.AddPinger("auth", pinger)
+	}
+	if pinger, ok := orchUseCase.(health.Pinger); ok {
+		readiness.AddPinger("orchestrator", pinger)
+	}
+	server.SetHealthChecker(readiness)
+
 	if err := server.Start(ctx); err != nil {
 		logger.Error(ctx, log, ErrStartHTTP, zap.Error(err))
 		exitCode = 1
@@ -169,7 +218,11 @@ func main() {
 	shutdown.Wait(ctx, cfg.GetShutdownTimeout(),
 		func(ctx context.Context) error {
 			logger.Info(ctx, log, LogHTTPShutdown)
-			return server.Stop(ctx)
+			err := server.Stop(ctx)
+			if tracingErr := shutdownTracing(ctx); tracingErr != nil {
+				logger.Error(ctx, log, ErrStopTracing, zap.Error(tracingErr))
+			}
+			return err
 		},
 	)
 
@@ -2,10 +2,14 @@ package agent
 
 import (
 	"errors"
+	"hash/fnv"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	agentModel "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/agent"
 	agentRepo "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/repository/agent"
+	"go.uber.org/zap"
 )
 
 var (
@@ -13,18 +17,126 @@ var (
 	ErrNoAgentAvailable = errors.New("no agent available")
 )
 
-type MemoryAgentStorage struct {
+// DefaultShards - количество сегментов хранилища по умолчанию.
+const DefaultShards = 16
+
+// agentShard - сегмент хранилища со своей блокировкой. Агент попадает в сегмент по хешу ID,
+// поэтому операции над разными агентами, как правило, не блокируют друг друга.
+type agentShard struct {
 	agents       map[string]*agentModel.Agent
 	onlineAgents map[string]*agentModel.Agent
 	mu           sync.RWMutex
 }
 
+// LockWaitStats содержит статистику ожидания блокировок хранилища.
+type LockWaitStats struct {
+	// Acquisitions - количество захватов блокировок сегментов.
+	Acquisitions uint64
+	// TotalWait - суммарное время ожидания блокировок.
+	TotalWait time.Duration
+	// MaxWait - наибольшее время ожидания одной блокировки.
+	MaxWait time.Duration
+	// Slow - количество ожиданий, превысивших порог SetLockWaitLogging.
+	Slow uint64
+}
+
+type MemoryAgentStorage struct {
+	shards []*agentShard
+
+	acquisitions  atomic.Uint64
+	totalWait     atomic.Int64
+	maxWait       atomic.Int64
+	slowWaits     atomic.Uint64
+	slowThreshold atomic.Int64
+	log           atomic.Pointer[zap.Logger]
+}
+
 var _ agentRepo.AgentStorage = (*MemoryAgentStorage)(nil)
 
+// NewAgentStorage создает хранилище агентов с DefaultShards сегментами.
 func NewAgentStorage() *MemoryAgentStorage {
-	return &MemoryAgentStorage{
-		agents:       make(map[string]*agentModel.Agent),
-		onlineAgents: make(map[string]*agentModel.Agent),
+	return NewShardedAgentStorage(DefaultShards)
+}
+
+// NewShardedAgentStorage создает хранилище агентов, разбитое на shards сегментов.
+// Неположительное значение заменяется на один сегмент с общей блокировкой.
+func NewShardedAgentStorage(shards int) *MemoryAgentStorage {
+	shards = max(shards, 1)
+
+	s := &MemoryAgentStorage{shards: make([]*agentShard, shards)}
+	for i := range s.shards {
+		s.shards[i] = &agentShard{
+			agents:       make(map[string]*agentModel.Agent),
+			onlineAgents: make(map[string]*agentModel.Agent),
+		}
+	}
+	return s
+}
+
+// SetLockWaitLogging включает предупреждение в журнал, когда ожидание блокировки сегмента
+// превышает threshold. Нулевой порог или nil журнал отключают предупреждения,
+// статистика LockWaitStats собирается всегда.
+func (s *MemoryAgentStorage) SetLockWaitLogging(log *zap.Logger, threshold time.Duration) {
+	s.log.Store(log)
+	s.slowThreshold.Store(int64(max(threshold, 0)))
+}
+
+// LockWaitStats возвращает статистику ожидания блокировок с момента создания хранилища.
+func (s *MemoryAgentStorage) LockWaitStats() LockWaitStats {
+	return LockWaitStats{
+		Acquisitions: s.acquisitions.Load(),
+		TotalWait:    time.Duration(s.totalWait.Load()),
+		MaxWait:      time.Duration(s.maxWait.Load()),
+		Slow:         s.slowWaits.Load(),
+	}
+}
+
+func (s *MemoryAgentStorage) shardFor(id string) *agentShard {
+	if len(s.shards) == 1 {
+		return s.shards[0]
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(id))
+	return s.shards[h.Sum32()%uint32(len(s.shards))]
+}
+
+// lock захватывает блокировку сегмента на запись и учитывает время ожидания.
+func (s *MemoryAgentStorage) lock(shard *agentShard, op string) {
+	start := time.Now()
+	shard.mu.Lock()
+	s.recordWait(op, time.Since(start))
+}
+
+// rlock захватывает блокировку сегмента на чтение и учитывает время ожидания.
+func (s *MemoryAgentStorage) rlock(shard *agentShard, op string) {
+	start := time.Now()
+	shard.mu.RLock()
+	s.recordWait(op, time.Since(start))
+}
+
+func (s *MemoryAgentStorage) recordWait(op string, wait time.Duration) {
+	s.acquisitions.Add(1)
+	s.totalWait.Add(int64(wait))
+
+	for {
+		current := s.maxWait.Load()
+		if int64(wait) <= current || s.maxWait.CompareAndSwap(current, int64(wait)) {
+			break
+		}
+	}
+
+	threshold := time.Duration(s.slowThreshold.Load())
+	if threshold <= 0 || wait < threshold {
+		return
+	}
+
+	s.slowWaits.Add(1)
+	if log := s.log.Load(); log != nil {
+		log.Warn("Slow agent storage lock acquisition",
+			zap.String("op", op),
+			zap.Duration("wait", wait),
+			zap.Duration("threshold", threshold))
 	}
 }
 
@@ -33,14 +145,15 @@ func (s *MemoryAgentStorage) Add(agent *agentModel.Agent) {
 		return
 	}
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	shard := s.shardFor(agent.ID)
+	s.lock(shard, "Add")
+	defer shard.mu.Unlock()
 
 	agentCopy := *agent
-	s.agents[agent.ID] = &agentCopy
+	shard.agents[agent.ID] = &agentCopy
 
 	if agent.Status == agentModel.AgentStatusOnline {
-		s.onlineAgents[agent.ID] = &agentCopy
+		shard.onlineAgents[agent.ID] = &agentCopy
 	}
 }
 
@@ -49,10 +162,11 @@ func (s *MemoryAgentStorage) GetByID(id string) (*agentModel.Agent, error) {
 		return nil, ErrAgentNotFound
 	}
 
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	shard := s.shardFor(id)
+	s.rlock(shard, "GetByID")
+	defer shard.mu.RUnlock()
 
-	a, exists := s.agents[id]
+	a, exists := shard.agents[id]
 	if !exists {
 		return nil, ErrAgentNotFound
 	}
@@ -61,30 +175,32 @@ func (s *MemoryAgentStorage) GetByID(id string) (*agentModel.Agent, error) {
 	return &agentCopy, nil
 }
 
+// GetAvailable выбирает онлайн-агента с наименьшей нагрузкой среди всех сегментов.
+// Сегменты блокируются по очереди, поэтому выбор не атомарен относительно
+// параллельных обновлений других сегментов.
 func (s *MemoryAgentStorage) GetAvailable() (*agentModel.Agent, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
 	var bestAgent *agentModel.Agent
-	lowestLoad := -1
-
-	for _, a := range s.onlineAgents {
-		if a.CurrentLoad >= a.MaxCapacity {
-			continue
-		}
 
-		if lowestLoad == -1 || a.CurrentLoad < lowestLoad {
-			bestAgent = a
-			lowestLoad = a.CurrentLoad
+	for _, shard := range s.shards {
+		s.rlock(shard, "GetAvailable")
+		for _, a := range shard.onlineAgents {
+			if a.CurrentLoad >= a.MaxCapacity {
+				continue
+			}
+
+			if bestAgent == nil || a.CurrentLoad < bestAgent.CurrentLoad {
+				agentCopy := *a
+				bestAgent = &agentCopy
+			}
 		}
+		shard.mu.RUnlock()
 	}
 
 	if bestAgent == nil {
 		return nil, ErrNoAgentAvailable
 	}
 
-	agentCopy := *bestAgent
-	return &agentCopy, nil
+	return bestAgent, nil
 }
 
 func (s *MemoryAgentStorage) UpdateStatus(id string, status agentModel.AgentStatus, load int, capacity int) error {
@@ -92,10 +208,11 @@ func (s *MemoryAgentStorage) UpdateStatus(id string, status agentModel.AgentStat
 		return ErrAgentNotFound
 	}
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	shard := s.shardFor(id)
+	s.lock(shard, "UpdateStatus")
+	defer shard.mu.Unlock()
 
-	a, exists := s.agents[id]
+	a, exists := shard.agents[id]
 	if !exists {
 		return ErrAgentNotFound
 	}
@@ -109,9 +226,9 @@ func (s *MemoryAgentStorage) UpdateStatus(id string, status agentModel.AgentStat
 
 	if wasOnline != isOnline {
 		if isOnline {
-			s.onlineAgents[id] = a
+			shard.onlineAgents[id] = a
 		} else {
-			delete(s.onlineAgents, id)
+			delete(shard.onlineAgents, id)
 		}
 	}
 
@@ -123,10 +240,11 @@ func (s *MemoryAgentStorage) UpdateStats(id string, completed bool, failed bool)
 		return ErrAgentNotFound
 	}
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	shard := s.shardFor(id)
+	s.lock(shard, "UpdateStats")
+	defer shard.mu.Unlock()
 
-	a, exists := s.agents[id]
+	a, exists := shard.agents[id]
 	if !exists {
 		return ErrAgentNotFound
 	}
@@ -143,15 +261,67 @@ func (s *MemoryAgentStorage) UpdateStats(id string, completed bool, failed bool)
 	return nil
 }
 
-func (s *MemoryAgentStorage) List() []*agentModel.Agent {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+// Heartbeat записывает время последнего сигнала агента. Более ранний сигнал не заменяет
+// уже записанный.
+func (s *MemoryAgentStorage) Heartbeat(id string, at time.Time) error {
+	if id == "" {
+		return ErrAgentNotFound
+	}
+
+	shard := s.shardFor(id)
+	s.lock(shard, "Heartbeat")
+	defer shard.mu.Unlock()
+
+	a, exists := shard.agents[id]
+	if !exists {
+		return ErrAgentNotFound
+	}
 
-	agents := make([]*agentModel.Agent, 0, len(s.agents))
+	if at.After(a.LastHeartbeat) {
+		a.LastHeartbeat = at
+	}
 
-	for _, a := range s.agents {
-		agentCopy := *a
-		agents = append(agents, &agentCopy)
+	return nil
+}
+
+// EvictStale переводит в offline онлайн и занятых агентов, последний сигнал которых старше
+// now-timeout, и возвращает их ID. Агенты с нулевым LastHeartbeat сигналов не отправляют
+// и не вытесняются. Неположительный timeout отключает вытеснение.
+func (s *MemoryAgentStorage) EvictStale(timeout time.Duration, now time.Time) []string {
+	if timeout <= 0 {
+		return nil
+	}
+
+	deadline := now.Add(-timeout)
+
+	var evicted []string
+	for _, shard := range s.shards {
+		s.lock(shard, "EvictStale")
+		for id, a := range shard.agents {
+			if a.Status == agentModel.AgentStatusOffline || a.LastHeartbeat.IsZero() || !a.LastHeartbeat.Before(deadline) {
+				continue
+			}
+
+			a.Status = agentModel.AgentStatusOffline
+			delete(shard.onlineAgents, id)
+			evicted = append(evicted, id)
+		}
+		shard.mu.Unlock()
+	}
+
+	return evicted
+}
+
+func (s *MemoryAgentStorage) List() []*agentModel.Agent {
+	agents := make([]*agentModel.Agent, 0)
+
+	for _, shard := range s.shards {
+		s.rlock(shard, "List")
+		for _, a := range shard.agents {
+			agentCopy := *a
+			agents = append(agents, &agentCopy)
+		}
+		shard.mu.RUnlock()
 	}
 
 	return agents
@@ -162,18 +332,19 @@ func (s *MemoryAgentStorage) Remove(id string) error {
 		return ErrAgentNotFound
 	}
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	shard := s.shardFor(id)
+	s.lock(shard, "Remove")
+	defer shard.mu.Unlock()
 
-	a, exists := s.agents[id]
+	a, exists := shard.agents[id]
 	if !exists {
 		return ErrAgentNotFound
 	}
 
-	delete(s.agents, id)
+	delete(shard.agents, id)
 
 	if a.Status == agentModel.AgentStatusOnline {
-		delete(s.onlineAgents, id)
+		delete(shard.onlineAgents, id)
 	}
 
 	return nil
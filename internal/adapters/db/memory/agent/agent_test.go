@@ -1,6 +1,10 @@
 package agent_test
 
 import (
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -393,7 +397,189 @@ func TestRemove(t *testing.T) {
 	})
 }
 
+func TestShardedStorageConcurrentAccess(t *testing.T) {
+	const (
+		agentsCount = 64
+		goroutines  = 16
+		iterations  = 200
+	)
+
+	for _, shards := range []int{0, 1, agent.DefaultShards} {
+		t.Run(fmt.Sprintf("Shards%d", shards), func(t *testing.T) {
+			storage := agent.NewShardedAgentStorage(shards)
+			for i := range agentsCount {
+				storage.Add(createTestAgent(fmt.Sprintf("agent-%d", i), agentModel.AgentStatusOnline, 0, 5))
+			}
+
+			var wg sync.WaitGroup
+			for g := range goroutines {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := range iterations {
+						id := fmt.Sprintf("agent-%d", (g+i)%agentsCount)
+						if err := storage.UpdateStats(id, true, false); err != nil {
+							t.Errorf("UpdateStats(%s): %v", id, err)
+							return
+						}
+						if _, err := storage.GetByID(id); err != nil {
+							t.Errorf("GetByID(%s): %v", id, err)
+							return
+						}
+						if _, err := storage.GetAvailable(); err != nil {
+							t.Errorf("GetAvailable: %v", err)
+							return
+						}
+					}
+				}()
+			}
+			wg.Wait()
+
+			agents := storage.List()
+			if len(agents) != agentsCount {
+				t.Fatalf("Expected %d agents, got: %d", agentsCount, len(agents))
+			}
+
+			var total int64
+			for _, a := range agents {
+				total += a.OperationsStats.Completed
+			}
+			if total != goroutines*iterations {
+				t.Errorf("Expected %d completed operations, got: %d", goroutines*iterations, total)
+			}
+
+			stats := storage.LockWaitStats()
+			if stats.Acquisitions == 0 {
+				t.Error("Expected lock acquisitions to be counted")
+			}
+			if stats.MaxWait > stats.TotalWait {
+				t.Errorf("Max wait %s exceeds total wait %s", stats.MaxWait, stats.TotalWait)
+			}
+		})
+	}
+}
+
+// BenchmarkStorageContention сравнивает хранилище с общей блокировкой и сегментированное
+// при параллельных обновлениях разных агентов:
+//
+//	go test -bench StorageContention -cpu 8 ./internal/adapters/db/memory/agent/
+func BenchmarkStorageContention(b *testing.B) {
+	const agentsCount = 256
+
+	ids := make([]string, agentsCount)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("agent-%d", i)
+	}
+
+	for _, shards := range []int{1, agent.DefaultShards} {
+		b.Run(fmt.Sprintf("Shards%d", shards), func(b *testing.B) {
+			storage := agent.NewShardedAgentStorage(shards)
+			for _, id := range ids {
+				storage.Add(createTestAgent(id, agentModel.AgentStatusOnline, 0, 5))
+			}
+
+			var offset atomic.Int64
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				// Каждая горутина начинает со своего агента, чтобы не обходить их синхронно.
+				i := int(offset.Add(agentsCount / 8))
+				for pb.Next() {
+					id := ids[i%agentsCount]
+					_ = storage.UpdateStatus(id, agentModel.AgentStatusOnline, i%5, 5)
+					_ = storage.UpdateStats(id, true, false)
+					_, _ = storage.GetByID(id)
+					i++
+				}
+			})
+			b.StopTimer()
+
+			stats := storage.LockWaitStats()
+			b.ReportMetric(float64(stats.TotalWait.Nanoseconds())/float64(b.N), "wait-ns/op")
+		})
+	}
+}
+
 // Helper function to create a test agent
+func TestHeartbeatAndEvictStale(t *testing.T) {
+	now := time.Now()
+	timeout := time.Second
+
+	storage := agent.NewAgentStorage()
+	storage.Add(createTestAgent("fresh", agentModel.AgentStatusOnline, 0, 5))
+	storage.Add(createTestAgent("stale", agentModel.AgentStatusOnline, 0, 5))
+	storage.Add(createTestAgent("stale-busy", agentModel.AgentStatusBusy, 5, 5))
+	storage.Add(createTestAgent("silent", agentModel.AgentStatusOnline, 0, 5))
+
+	for id, at := range map[string]time.Time{
+		"fresh":      now.Add(-timeout / 2),
+		"stale":      now.Add(-2 * timeout),
+		"stale-busy": now.Add(-2 * timeout),
+	} {
+		if err := storage.Heartbeat(id, at); err != nil {
+			t.Fatalf("Heartbeat(%s) failed: %v", id, err)
+		}
+	}
+
+	t.Run("EarlierHeartbeatIgnored", func(t *testing.T) {
+		if err := storage.Heartbeat("fresh", now.Add(-time.Hour)); err != nil {
+			t.Fatalf("Heartbeat failed: %v", err)
+		}
+		a, _ := storage.GetByID("fresh")
+		if !a.LastHeartbeat.Equal(now.Add(-timeout / 2)) {
+			t.Errorf("Earlier heartbeat replaced the latest one: %v", a.LastHeartbeat)
+		}
+	})
+
+	t.Run("UnknownAgent", func(t *testing.T) {
+		if err := storage.Heartbeat("nonexistent", now); err != agent.ErrAgentNotFound {
+			t.Errorf("Expected error %v, got: %v", agent.ErrAgentNotFound, err)
+		}
+	})
+
+	t.Run("DisabledTimeout", func(t *testing.T) {
+		if evicted := storage.EvictStale(0, now); len(evicted) != 0 {
+			t.Errorf("Zero timeout should not evict agents, evicted: %v", evicted)
+		}
+	})
+
+	t.Run("EvictsAgentsWithMissedHeartbeat", func(t *testing.T) {
+		evicted := storage.EvictStale(timeout, now)
+		sort.Strings(evicted)
+		if len(evicted) != 2 || evicted[0] != "stale" || evicted[1] != "stale-busy" {
+			t.Fatalf("Expected stale agents to be evicted, got: %v", evicted)
+		}
+
+		for _, id := range evicted {
+			a, _ := storage.GetByID(id)
+			if a.Status != agentModel.AgentStatusOffline {
+				t.Errorf("Evicted agent %s should be offline, got: %s", id, a.Status)
+			}
+		}
+		for _, id := range []string{"fresh", "silent"} {
+			a, _ := storage.GetByID(id)
+			if a.Status != agentModel.AgentStatusOnline {
+				t.Errorf("Agent %s should stay online, got: %s", id, a.Status)
+			}
+		}
+
+		for range 10 {
+			available, err := storage.GetAvailable()
+			if err != nil {
+				t.Fatalf("Expected an available agent: %v", err)
+			}
+			if available.ID == "stale" {
+				t.Fatal("Evicted agent should not be available for operations")
+			}
+		}
+	})
+
+	t.Run("EvictedAgentsNotReported", func(t *testing.T) {
+		if evicted := storage.EvictStale(timeout, now); len(evicted) != 0 {
+			t.Errorf("Already evicted agents should not be reported again: %v", evicted)
+		}
+	})
+}
+
 func createTestAgent(id string, status agentModel.AgentStatus, currentLoad, maxCapacity int) *agentModel.Agent {
 	return &agentModel.Agent{
 		ID:          id,
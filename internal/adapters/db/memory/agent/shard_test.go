@@ -0,0 +1,98 @@
+package agent
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	agentModel "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/agent"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// idsInDifferentShards возвращает два ID агентов, попадающих в разные сегменты хранилища.
+func idsInDifferentShards(t *testing.T, s *MemoryAgentStorage) (string, string) {
+	t.Helper()
+
+	first := "agent-0"
+	for i := 1; i < 1000; i++ {
+		candidate := "agent-" + strconv.Itoa(i)
+		if s.shardFor(candidate) != s.shardFor(first) {
+			return first, candidate
+		}
+	}
+	t.Fatal("failed to find IDs in different shards")
+	return "", ""
+}
+
+func TestShardLockDoesNotBlockOtherShards(t *testing.T) {
+	storage := NewShardedAgentStorage(DefaultShards)
+	lockedID, freeID := idsInDifferentShards(t, storage)
+	storage.Add(&agentModel.Agent{ID: lockedID, Status: agentModel.AgentStatusOnline, MaxCapacity: 1})
+	storage.Add(&agentModel.Agent{ID: freeID, Status: agentModel.AgentStatusOnline, MaxCapacity: 1})
+
+	locked := storage.shardFor(lockedID)
+	locked.mu.Lock()
+
+	done := make(chan error, 1)
+	go func() { done <- storage.UpdateStats(freeID, true, false) }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("UpdateStats: %v", err)
+		}
+	case <-time.After(time.Second):
+		locked.mu.Unlock()
+		t.Fatal("update of an agent in another shard must not wait for the locked shard")
+	}
+
+	go func() { done <- storage.UpdateStats(lockedID, true, false) }()
+	select {
+	case <-done:
+		t.Fatal("update of an agent in the locked shard must wait for the lock")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	locked.mu.Unlock()
+	if err := <-done; err != nil {
+		t.Fatalf("UpdateStats after unlock: %v", err)
+	}
+}
+
+func TestSlowLockWaitIsLogged(t *testing.T) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	storage := NewShardedAgentStorage(1)
+	storage.SetLockWaitLogging(zap.New(core), 10*time.Millisecond)
+	storage.Add(&agentModel.Agent{ID: "agent-1", Status: agentModel.AgentStatusOnline, MaxCapacity: 1})
+
+	shard := storage.shardFor("agent-1")
+	shard.mu.Lock()
+
+	done := make(chan struct{})
+	go func() {
+		_, _ = storage.GetByID("agent-1")
+		close(done)
+	}()
+
+	time.Sleep(30 * time.Millisecond)
+	shard.mu.Unlock()
+	<-done
+
+	stats := storage.LockWaitStats()
+	if stats.Slow != 1 {
+		t.Errorf("Expected 1 slow lock wait, got: %d", stats.Slow)
+	}
+	if stats.MaxWait < 10*time.Millisecond {
+		t.Errorf("Expected max wait of at least 10ms, got: %s", stats.MaxWait)
+	}
+
+	entries := logs.FilterMessage("Slow agent storage lock acquisition").All()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 slow lock warning, got: %d", len(entries))
+	}
+	if op := entries[0].ContextMap()["op"]; op != "GetByID" {
+		t.Errorf("Expected op GetByID, got: %v", op)
+	}
+}
@@ -0,0 +1,61 @@
+// Package cache содержит in-memory кэш результатов выражений.
+package cache
+
+import (
+	"context"
+	"sync"
+
+	orchrepo "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/repository/orchestrator"
+)
+
+// MemoryResultCache хранит результаты выражений в памяти процесса.
+// При заполнении новое значение вытесняет произвольную запись.
+type MemoryResultCache struct {
+	mu         sync.RWMutex
+	results    map[string]string
+	maxEntries int
+}
+
+var _ orchrepo.ResultCache = (*MemoryResultCache)(nil)
+
+// NewResultCache создает кэш не более чем на maxEntries выражений.
+// Неположительное значение заменяется на одну запись.
+func NewResultCache(maxEntries int) *MemoryResultCache {
+	maxEntries = max(maxEntries, 1)
+	return &MemoryResultCache{
+		results:    make(map[string]string, maxEntries),
+		maxEntries: maxEntries,
+	}
+}
+
+// Get возвращает сохраненный результат выражения.
+func (c *MemoryResultCache) Get(_ context.Context, expression string) (string, bool, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	result, ok := c.results[expression]
+	return result, ok, nil
+}
+
+// Set сохраняет результат выражения.
+func (c *MemoryResultCache) Set(_ context.Context, expression string, result string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.results[expression]; !ok && len(c.results) >= c.maxEntries {
+		for key := range c.results {
+			delete(c.results, key)
+			break
+		}
+	}
+	c.results[expression] = result
+	return nil
+}
+
+// Len возвращает количество сохраненных выражений.
+func (c *MemoryResultCache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.results)
+}
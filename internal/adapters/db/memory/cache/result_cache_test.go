@@ -0,0 +1,53 @@
+package cache_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/db/memory/cache"
+)
+
+func TestMemoryResultCache_GetSet(t *testing.T) {
+	ctx := context.Background()
+	c := cache.NewResultCache(10)
+
+	if _, found, _ := c.Get(ctx, "2+2"); found {
+		t.Fatal("Expected empty cache")
+	}
+
+	if err := c.Set(ctx, "2+2", "4"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	result, found, err := c.Get(ctx, "2+2")
+	if err != nil || !found || result != "4" {
+		t.Errorf("Expected cached result 4, got %q (found=%v, err=%v)", result, found, err)
+	}
+}
+
+func TestMemoryResultCache_MaxEntries(t *testing.T) {
+	ctx := context.Background()
+	c := cache.NewResultCache(2)
+
+	for _, expression := range []string{"1+1", "2+2", "3+3"} {
+		if err := c.Set(ctx, expression, "x"); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+	}
+
+	if c.Len() != 2 {
+		t.Errorf("Expected 2 entries, got %d", c.Len())
+	}
+
+	if _, found, _ := c.Get(ctx, "3+3"); !found {
+		t.Error("Expected the latest expression to be cached")
+	}
+
+	// Перезапись существующего выражения не вытесняет другие
+	if err := c.Set(ctx, "3+3", "6"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if c.Len() != 2 {
+		t.Errorf("Expected 2 entries after overwrite, got %d", c.Len())
+	}
+}
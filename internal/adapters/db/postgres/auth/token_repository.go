@@ -18,24 +18,36 @@ import (
 
 const (
 	queryInsertToken = `
-        INSERT INTO tokens (id, user_id, token, expires_at, created_at, is_revoked)
-        VALUES ($1, $2, $3, $4, $5, $6)`
+        INSERT INTO tokens (id, user_id, token, expires_at, created_at, is_revoked, user_agent, ip, family_issued_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
 
 	queryFindTokenByString = `
-        SELECT id, user_id, token, expires_at, created_at, is_revoked
+        SELECT id, user_id, token, expires_at, created_at, is_revoked, user_agent, ip, family_issued_at
         FROM tokens
         WHERE token = $1`
 
 	queryFindTokenByID = `
-        SELECT id, user_id, token, expires_at, created_at, is_revoked
+        SELECT id, user_id, token, expires_at, created_at, is_revoked, user_agent, ip, family_issued_at
         FROM tokens
         WHERE id = $1`
 
+	queryFindTokensByUserID = `
+        SELECT id, user_id, token, expires_at, created_at, is_revoked, user_agent, ip, family_issued_at
+        FROM tokens
+        WHERE user_id = $1
+        ORDER BY created_at DESC, id
+        LIMIT $2 OFFSET $3`
+
 	queryRevokeToken = `
         UPDATE tokens
         SET is_revoked = true
         WHERE token = $1`
 
+	queryRevokeActiveToken = `
+        UPDATE tokens
+        SET is_revoked = true
+        WHERE token = $1 AND is_revoked = false`
+
 	queryRevokeAllUserTokens = `
         UPDATE tokens
         SET is_revoked = true
@@ -50,6 +62,9 @@ var ErrTokenNotFound = errors.New("token not found")
 
 type PgTokenRepository struct {
 	db *database.Handler
+
+	// statementTimeout - предельное время одного обращения к базе. Ноль - без ограничения.
+	statementTimeout time.Duration
 }
 
 var _ authrepo.TokenRepository = (*PgTokenRepository)(nil)
@@ -58,9 +73,18 @@ func NewTokenRepository(db *database.Handler) *PgTokenRepository {
 	return &PgTokenRepository{db: db}
 }
 
+// SetStatementTimeout ограничивает время каждого обращения репозитория к базе,
+// чтобы медленный запрос не удерживал соединение бесконечно. Ноль снимает ограничение.
+func (r *PgTokenRepository) SetStatementTimeout(timeout time.Duration) {
+	r.statementTimeout = timeout
+}
+
 func (r *PgTokenRepository) Store(ctx context.Context, token *authmodels.Token) error {
 	const op = "PgTokenRepository.Store"
 
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
 	if token.ID == uuid.Nil {
 		token.ID = uuid.New()
 	}
@@ -69,6 +93,10 @@ func (r *PgTokenRepository) Store(ctx context.Context, token *authmodels.Token)
 		token.CreatedAt = time.Now()
 	}
 
+	if token.FamilyIssuedAt.IsZero() {
+		token.FamilyIssuedAt = token.CreatedAt
+	}
+
 	conn, err := r.acquireConn(ctx, op)
 	if err != nil {
 		return err
@@ -82,6 +110,9 @@ func (r *PgTokenRepository) Store(ctx context.Context, token *authmodels.Token)
 		token.ExpiresAt,
 		token.CreatedAt,
 		token.IsRevoked,
+		token.UserAgent,
+		token.IP,
+		token.FamilyIssuedAt,
 	)
 
 	if err != nil {
@@ -94,6 +125,9 @@ func (r *PgTokenRepository) Store(ctx context.Context, token *authmodels.Token)
 func (r *PgTokenRepository) FindByTokenString(ctx context.Context, tokenStr string) (*authmodels.Token, error) {
 	const op = "PgTokenRepository.FindByTokenString"
 
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
 	conn, err := r.acquireConn(ctx, op)
 	if err != nil {
 		return nil, err
@@ -108,6 +142,9 @@ func (r *PgTokenRepository) FindByTokenString(ctx context.Context, tokenStr stri
 		&token.ExpiresAt,
 		&token.CreatedAt,
 		&token.IsRevoked,
+		&token.UserAgent,
+		&token.IP,
+		&token.FamilyIssuedAt,
 	)
 
 	if err != nil {
@@ -123,6 +160,9 @@ func (r *PgTokenRepository) FindByTokenString(ctx context.Context, tokenStr stri
 func (r *PgTokenRepository) FindByID(ctx context.Context, id uuid.UUID) (*authmodels.Token, error) {
 	const op = "PgTokenRepository.FindByID"
 
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
 	conn, err := r.acquireConn(ctx, op)
 	if err != nil {
 		return nil, err
@@ -137,6 +177,9 @@ func (r *PgTokenRepository) FindByID(ctx context.Context, id uuid.UUID) (*authmo
 		&token.ExpiresAt,
 		&token.CreatedAt,
 		&token.IsRevoked,
+		&token.UserAgent,
+		&token.IP,
+		&token.FamilyIssuedAt,
 	)
 
 	if err != nil {
@@ -149,9 +192,62 @@ func (r *PgTokenRepository) FindByID(ctx context.Context, id uuid.UUID) (*authmo
 	return &token, nil
 }
 
+func (r *PgTokenRepository) FindByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*authmodels.Token, error) {
+	const op = "PgTokenRepository.FindByUserID"
+
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
+	// LIMIT NULL в PostgreSQL снимает ограничение
+	var limitArg any
+	if limit > 0 {
+		limitArg = limit
+	}
+
+	conn, err := r.acquireConn(ctx, op)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(ctx, queryFindTokensByUserID, userID, limitArg, max(offset, 0))
+	if err != nil {
+		return nil, r.logError(ctx, op, "query tokens by user ID", err)
+	}
+	defer rows.Close()
+
+	tokens := make([]*authmodels.Token, 0)
+	for rows.Next() {
+		var token authmodels.Token
+		if err := rows.Scan(
+			&token.ID,
+			&token.UserID,
+			&token.TokenStr,
+			&token.ExpiresAt,
+			&token.CreatedAt,
+			&token.IsRevoked,
+			&token.UserAgent,
+			&token.IP,
+			&token.FamilyIssuedAt,
+		); err != nil {
+			return nil, r.logError(ctx, op, "scan token row", err)
+		}
+		tokens = append(tokens, &token)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, r.logError(ctx, op, "iterate token rows", err)
+	}
+
+	return tokens, nil
+}
+
 func (r *PgTokenRepository) RevokeToken(ctx context.Context, tokenStr string) error {
 	const op = "PgTokenRepository.RevokeToken"
 
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
 	conn, err := r.acquireConn(ctx, op)
 	if err != nil {
 		return err
@@ -170,16 +266,29 @@ func (r *PgTokenRepository) RevokeToken(ctx context.Context, tokenStr string) er
 	return nil
 }
 
-func (r *PgTokenRepository) RevokeAllUserTokens(ctx context.Context, userID uuid.UUID) error {
-	const op = "PgTokenRepository.RevokeAllUserTokens"
+// RevokeIfActive отзывает токен одним условным UPDATE, поэтому из параллельных вызовов
+// с одним токеном true получает только один.
+func (r *PgTokenRepository) RevokeIfActive(ctx context.Context, tokenStr string) (bool, error) {
+	const op = "PgTokenRepository.RevokeIfActive"
 
-	conn, err := r.acquireConn(ctx, op)
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, queryRevokeActiveToken, tokenStr)
 	if err != nil {
-		return err
+		return false, r.logError(ctx, op, "revoke active token", err)
 	}
-	defer conn.Release()
 
-	result, err := conn.Exec(ctx, queryRevokeAllUserTokens, userID)
+	return result.RowsAffected() > 0, nil
+}
+
+func (r *PgTokenRepository) RevokeAllUserTokens(ctx context.Context, userID uuid.UUID) error {
+	const op = "PgTokenRepository.RevokeAllUserTokens"
+
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, queryRevokeAllUserTokens, userID)
 	if err != nil {
 		return r.logError(ctx, op, "revoke all user tokens", err)
 	}
@@ -192,26 +301,29 @@ func (r *PgTokenRepository) RevokeAllUserTokens(ctx context.Context, userID uuid
 	return nil
 }
 
-func (r *PgTokenRepository) DeleteExpiredTokens(ctx context.Context, before time.Time) error {
+func (r *PgTokenRepository) DeleteExpiredTokens(ctx context.Context, before time.Time) (int64, error) {
 	const op = "PgTokenRepository.DeleteExpiredTokens"
 
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
 	conn, err := r.acquireConn(ctx, op)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	defer conn.Release()
 
 	result, err := conn.Exec(ctx, queryDeleteExpiredTokens, before)
 	if err != nil {
-		return r.logError(ctx, op, "delete expired tokens", err)
+		return 0, r.logError(ctx, op, "delete expired tokens", err)
 	}
 
-	logger.Info(ctx, nil, "Expired tokens deleted",
+	logger.Debug(ctx, nil, "Expired tokens deleted",
 		zap.String("op", op),
 		zap.Time("before", before),
 		zap.Int64("count", result.RowsAffected()))
 
-	return nil
+	return result.RowsAffected(), nil
 }
 
 func (r *PgTokenRepository) acquireConn(ctx context.Context, op string) (*pgxpool.Conn, error) {
@@ -40,6 +40,8 @@ const (
 	queryDeleteUser = `
         DELETE FROM users
         WHERE id = $1`
+
+	queryCountUsers = `SELECT COUNT(*) FROM users`
 )
 
 var (
@@ -51,6 +53,9 @@ var (
 
 type PgUserRepository struct {
 	db *database.Handler
+
+	// statementTimeout - предельное время одного обращения к базе. Ноль - без ограничения.
+	statementTimeout time.Duration
 }
 
 var _ authrepo.UserRepository = (*PgUserRepository)(nil)
@@ -59,9 +64,18 @@ func NewUserRepository(db *database.Handler) *PgUserRepository {
 	return &PgUserRepository{db: db}
 }
 
+// SetStatementTimeout ограничивает время каждого обращения репозитория к базе,
+// чтобы медленный запрос не удерживал соединение бесконечно. Ноль снимает ограничение.
+func (r *PgUserRepository) SetStatementTimeout(timeout time.Duration) {
+	r.statementTimeout = timeout
+}
+
 func (r *PgUserRepository) Create(ctx context.Context, user *authmodels.User) (*authmodels.User, error) {
 	const op = "PgUserRepository.Create"
 
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
 	if user.ID == uuid.Nil {
 		user.ID = uuid.New()
 	}
@@ -105,6 +119,9 @@ func (r *PgUserRepository) Create(ctx context.Context, user *authmodels.User) (*
 func (r *PgUserRepository) FindByID(ctx context.Context, id uuid.UUID) (*authmodels.User, error) {
 	const op = "PgUserRepository.FindByID"
 
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
 	if id == uuid.Nil {
 		return nil, fmt.Errorf("%s: %w", op, ErrInvalidUserID)
 	}
@@ -115,6 +132,9 @@ func (r *PgUserRepository) FindByID(ctx context.Context, id uuid.UUID) (*authmod
 func (r *PgUserRepository) FindByLogin(ctx context.Context, login string) (*authmodels.User, error) {
 	const op = "PgUserRepository.FindByLogin"
 
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
 	if login == "" {
 		return nil, fmt.Errorf("%s: %w", op, ErrEmptyLogin)
 	}
@@ -125,6 +145,9 @@ func (r *PgUserRepository) FindByLogin(ctx context.Context, login string) (*auth
 func (r *PgUserRepository) Update(ctx context.Context, user *authmodels.User) error {
 	const op = "PgUserRepository.Update"
 
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
 	if user == nil || user.ID == uuid.Nil {
 		return fmt.Errorf("%s: %w", op, ErrInvalidUser)
 	}
@@ -158,17 +181,14 @@ func (r *PgUserRepository) Update(ctx context.Context, user *authmodels.User) er
 func (r *PgUserRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	const op = "PgUserRepository.Delete"
 
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
 	if id == uuid.Nil {
 		return fmt.Errorf("%s: %w", op, ErrInvalidUserID)
 	}
 
-	conn, err := r.acquireConn(ctx, op)
-	if err != nil {
-		return err
-	}
-	defer conn.Release()
-
-	result, err := conn.Exec(ctx, queryDeleteUser, id)
+	result, err := r.db.ExecContext(ctx, queryDeleteUser, id)
 	if err != nil {
 		return r.logError(ctx, op, "delete user", err)
 	}
@@ -180,6 +200,26 @@ func (r *PgUserRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+func (r *PgUserRepository) Count(ctx context.Context) (int, error) {
+	const op = "PgUserRepository.Count"
+
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
+	conn, err := r.acquireConn(ctx, op)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Release()
+
+	var total int
+	if err := conn.QueryRow(ctx, queryCountUsers).Scan(&total); err != nil {
+		return 0, r.logError(ctx, op, "count users", err)
+	}
+
+	return total, nil
+}
+
 func (r *PgUserRepository) acquireConn(ctx context.Context, op string) (*pgxpool.Conn, error) {
 	conn, err := r.db.AcquireConn(ctx)
 	if err != nil {
@@ -2,10 +2,12 @@ package orchestrator
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
 
+	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
 	repo "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/repository/orchestrator"
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/database"
@@ -19,32 +21,87 @@ import (
 const (
 	queryCreateCalculation = `
         INSERT INTO calculations (
-            id, user_id, expression, result, status, error_message, created_at, updated_at
-        ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-        RETURNING id, user_id, expression, result, status, error_message, created_at, updated_at`
+            id, user_id, expression, result, result_display, status, error_message, source, client_version, idempotency_key,
+            created_at, updated_at
+        ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+        ON CONFLICT ON CONSTRAINT calculations_user_idempotency_key_unique DO NOTHING
+        RETURNING id, user_id, expression, result, result_display, status, error_message, source, client_version, created_at, updated_at`
 
 	queryFindCalculationByID = `
-        SELECT id, user_id, expression, result, status, error_message, created_at, updated_at
+        SELECT id, user_id, expression, result, result_display, warnings, status, error_message, source, client_version,
+               created_at, updated_at, deleted_at, compacted_at, operations_archive
         FROM calculations
-        WHERE id = $1`
+        WHERE id = $1 AND ($2 OR deleted_at IS NULL)`
+
+	queryFindCalculationByIdempotencyKey = `
+        SELECT id, user_id, expression, result, result_display, warnings, status, error_message, source, client_version,
+               created_at, updated_at, deleted_at, compacted_at, operations_archive
+        FROM calculations
+        WHERE user_id = $1 AND idempotency_key = $2`
 
 	queryFindCalculationsByUserID = `
-        SELECT id, user_id, expression, result, status, error_message, created_at, updated_at
+        SELECT id, user_id, expression, result, result_display, warnings, status, error_message, source, created_at, updated_at
+        FROM calculations
+        WHERE user_id = $1 AND deleted_at IS NULL AND ($2::text = '' OR status = $2)
+        ORDER BY created_at DESC
+        LIMIT $3 OFFSET $4`
+
+	queryCountCalculationsByUserID = `
+        SELECT COUNT(*)
         FROM calculations
-        WHERE user_id = $1
-        ORDER BY created_at DESC`
+        WHERE user_id = $1 AND deleted_at IS NULL AND ($2::text = '' OR status = $2)`
 
 	queryUpdateCalculation = `
         UPDATE calculations
         SET user_id = $2, expression = $3, result = $4, status = $5, error_message = $6, updated_at = $7
-        WHERE id = $1`
+        WHERE id = $1 AND deleted_at IS NULL`
 
 	queryUpdateCalculationStatus = `
         UPDATE calculations
         SET status = $2, result = $3, error_message = $4, updated_at = $5
-        WHERE id = $1`
+        WHERE id = $1 AND deleted_at IS NULL`
+
+	queryUpdateCalculationDisplayResult = `
+        UPDATE calculations
+        SET result_display = $2
+        WHERE id = $1 AND deleted_at IS NULL`
+
+	queryUpdateCalculationWarnings = `
+        UPDATE calculations
+        SET warnings = $2
+        WHERE id = $1 AND deleted_at IS NULL`
+
+	querySoftDeleteCalculation = `
+        UPDATE calculations
+        SET deleted_at = $2
+        WHERE id = $1 AND deleted_at IS NULL`
+
+	queryCountCalculationsByStatus = `
+        SELECT status, COUNT(*)
+        FROM calculations
+        WHERE deleted_at IS NULL
+        GROUP BY status`
+
+	queryCalculationResultStats = `
+        SELECT COUNT(result_numeric),
+               COALESCE(SUM(result_numeric), 0)::text,
+               COALESCE(trim_scale(AVG(result_numeric))::text, ''),
+               COALESCE(MIN(result_numeric)::text, ''),
+               COALESCE(MAX(result_numeric)::text, '')
+        FROM calculations
+        WHERE user_id = $1 AND deleted_at IS NULL AND result_numeric IS NOT NULL`
+
+	queryFindCompactableCalculations = `
+        SELECT id
+        FROM calculations
+        WHERE status = 'COMPLETED' AND compacted_at IS NULL AND deleted_at IS NULL AND updated_at < $1
+        ORDER BY updated_at
+        LIMIT $2`
 
-	queryDeleteCalculation = `DELETE FROM calculations WHERE id = $1`
+	queryArchiveCalculationOperations = `
+        UPDATE calculations
+        SET operations_archive = $2, compacted_at = $3
+        WHERE id = $1 AND compacted_at IS NULL AND deleted_at IS NULL`
 )
 
 var (
@@ -52,21 +109,37 @@ var (
 	ErrInvalidUserID        = errors.New("invalid user ID")
 	ErrInvalidCalculation   = errors.New("invalid calculation")
 	ErrCalculationNotFound  = errors.New("calculation not found")
+	ErrInvalidPagination    = errors.New("invalid pagination parameters")
 )
 
 type PgCalculationRepository struct {
 	db *database.Handler
+
+	// statementTimeout - предельное время одного обращения к базе. Ноль - без ограничения.
+	statementTimeout time.Duration
 }
 
-var _ repo.CalculationRepository = (*PgCalculationRepository)(nil)
+var (
+	_ repo.CalculationRepository      = (*PgCalculationRepository)(nil)
+	_ repo.OperationArchiveRepository = (*PgCalculationRepository)(nil)
+)
 
 func NewCalculationRepository(db *database.Handler) *PgCalculationRepository {
 	return &PgCalculationRepository{db: db}
 }
 
+// SetStatementTimeout ограничивает время каждого обращения репозитория к базе,
+// чтобы медленный запрос не удерживал соединение бесконечно. Ноль снимает ограничение.
+func (r *PgCalculationRepository) SetStatementTimeout(timeout time.Duration) {
+	r.statementTimeout = timeout
+}
+
 func (r *PgCalculationRepository) Create(ctx context.Context, calculation *orchestrator.Calculation) (*orchestrator.Calculation, error) {
 	const op = "PgCalculationRepository.Create"
 
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
 	if calculation.ID == uuid.Nil {
 		calculation.ID = uuid.New()
 	}
@@ -85,14 +158,24 @@ func (r *PgCalculationRepository) Create(ctx context.Context, calculation *orche
 	}
 	defer conn.Release()
 
+	// Вычисления без ключа идемпотентности хранят NULL и не конфликтуют друг с другом
+	var idempotencyKey *string
+	if calculation.IdempotencyKey != "" {
+		idempotencyKey = &calculation.IdempotencyKey
+	}
+
 	var result orchestrator.Calculation
 	err = conn.QueryRow(ctx, queryCreateCalculation,
 		calculation.ID,
 		calculation.UserID,
 		calculation.Expression,
 		calculation.Result,
+		calculation.DisplayResult,
 		calculation.Status,
 		calculation.ErrorMessage,
+		calculation.Source,
+		calculation.ClientVersion,
+		idempotencyKey,
 		calculation.CreatedAt,
 		calculation.UpdatedAt,
 	).Scan(
@@ -100,43 +183,106 @@ func (r *PgCalculationRepository) Create(ctx context.Context, calculation *orche
 		&result.UserID,
 		&result.Expression,
 		&result.Result,
+		&result.DisplayResult,
 		&result.Status,
 		&result.ErrorMessage,
+		&result.Source,
+		&result.ClientVersion,
 		&result.CreatedAt,
 		&result.UpdatedAt,
 	)
 
 	if err != nil {
+		// Строка не вставлена: у пользователя уже есть вычисление с этим ключом
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, domainerrors.ErrIdempotencyKeyConflict)
+		}
 		return nil, r.logError(ctx, op, "create calculation", err)
 	}
 
+	result.IdempotencyKey = calculation.IdempotencyKey
 	logger.Info(ctx, nil, "Calculation created", zap.String("id", result.ID.String()))
 	return &result, nil
 }
 
-func (r *PgCalculationRepository) FindByID(ctx context.Context, id uuid.UUID) (*orchestrator.Calculation, error) {
+func (r *PgCalculationRepository) FindByID(ctx context.Context, id uuid.UUID, opts ...orchestrator.FindOption) (*orchestrator.Calculation, error) {
 	const op = "PgCalculationRepository.FindByID"
 
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
 	if id == uuid.Nil {
 		return nil, fmt.Errorf("%s: %w", op, ErrInvalidCalculationID)
 	}
 
+	// Вычисление читается сразу после записи (создание, пересчет статуса процессором),
+	// поэтому чтение идет с основного сервера
+	conn, err := r.acquireConn(ctx, op)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	options := orchestrator.NewFindOptions(opts...)
+
+	calculation, err := r.scanCalculation(ctx, op, conn.QueryRow(ctx, queryFindCalculationByID, id, options.IncludeDeleted))
+	if err != nil {
+		return nil, err
+	}
+
+	return calculation, nil
+}
+
+// FindByIdempotencyKey ищет вычисление пользователя, отправленное с ключом идемпотентности key.
+// Запрос идет в основную базу: ключ проверяется сразу после вставки, и реплика может отставать.
+func (r *PgCalculationRepository) FindByIdempotencyKey(ctx context.Context, userID uuid.UUID, key string) (*orchestrator.Calculation, error) {
+	const op = "PgCalculationRepository.FindByIdempotencyKey"
+
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
+	if userID == uuid.Nil {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidUserID)
+	}
+
 	conn, err := r.acquireConn(ctx, op)
 	if err != nil {
 		return nil, err
 	}
 	defer conn.Release()
 
+	calculation, err := r.scanCalculation(ctx, op, conn.QueryRow(ctx, queryFindCalculationByIdempotencyKey, userID, key))
+	if err != nil {
+		return nil, err
+	}
+
+	if calculation != nil {
+		calculation.IdempotencyKey = key
+	}
+	return calculation, nil
+}
+
+// scanCalculation читает вычисление из строки, выбранной запросом поиска вычисления.
+// Если строки нет, возвращает nil без ошибки.
+func (r *PgCalculationRepository) scanCalculation(ctx context.Context, op string, row pgx.Row) (*orchestrator.Calculation, error) {
 	var calculation orchestrator.Calculation
-	err = conn.QueryRow(ctx, queryFindCalculationByID, id).Scan(
+	var archive []byte
+	err := row.Scan(
 		&calculation.ID,
 		&calculation.UserID,
 		&calculation.Expression,
 		&calculation.Result,
+		&calculation.DisplayResult,
+		&calculation.Warnings,
 		&calculation.Status,
 		&calculation.ErrorMessage,
+		&calculation.Source,
+		&calculation.ClientVersion,
 		&calculation.CreatedAt,
 		&calculation.UpdatedAt,
+		&calculation.DeletedAt,
+		&calculation.CompactedAt,
+		&archive,
 	)
 
 	if err != nil {
@@ -146,25 +292,44 @@ func (r *PgCalculationRepository) FindByID(ctx context.Context, id uuid.UUID) (*
 		return nil, r.logError(ctx, op, "find calculation", err)
 	}
 
+	// Строки операций сжатого вычисления удалены, операции читаются из архива
+	if archive != nil {
+		if err := json.Unmarshal(archive, &calculation.Operations); err != nil {
+			return nil, r.logError(ctx, op, "decode operations archive", err)
+		}
+	}
+
 	return &calculation, nil
 }
 
-func (r *PgCalculationRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*orchestrator.Calculation, error) {
+func (r *PgCalculationRepository) FindByUserID(ctx context.Context, userID uuid.UUID, filter orchestrator.CalculationFilter) ([]*orchestrator.Calculation, int, error) {
 	const op = "PgCalculationRepository.FindByUserID"
 
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
 	if userID == uuid.Nil {
-		return nil, fmt.Errorf("%s: %w", op, ErrInvalidUserID)
+		return nil, 0, fmt.Errorf("%s: %w", op, ErrInvalidUserID)
 	}
 
-	conn, err := r.acquireConn(ctx, op)
+	if filter.Limit <= 0 || filter.Offset < 0 {
+		return nil, 0, fmt.Errorf("%s: %w", op, ErrInvalidPagination)
+	}
+
+	conn, err := r.acquireReadConn(ctx, op)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer conn.Release()
 
-	rows, err := conn.Query(ctx, queryFindCalculationsByUserID, userID)
+	var total int
+	if err := conn.QueryRow(ctx, queryCountCalculationsByUserID, userID, string(filter.Status)).Scan(&total); err != nil {
+		return nil, 0, r.logError(ctx, op, "count calculations", err)
+	}
+
+	rows, err := conn.Query(ctx, queryFindCalculationsByUserID, userID, string(filter.Status), filter.Limit, filter.Offset)
 	if err != nil {
-		return nil, r.logError(ctx, op, "query calculations", err)
+		return nil, 0, r.logError(ctx, op, "query calculations", err)
 	}
 	defer rows.Close()
 
@@ -176,27 +341,33 @@ func (r *PgCalculationRepository) FindByUserID(ctx context.Context, userID uuid.
 			&calc.UserID,
 			&calc.Expression,
 			&calc.Result,
+			&calc.DisplayResult,
+			&calc.Warnings,
 			&calc.Status,
 			&calc.ErrorMessage,
+			&calc.Source,
 			&calc.CreatedAt,
 			&calc.UpdatedAt,
 		)
 		if err != nil {
-			return nil, r.logError(ctx, op, "scan calculation row", err)
+			return nil, 0, r.logError(ctx, op, "scan calculation row", err)
 		}
 		calculations = append(calculations, &calc)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, r.logError(ctx, op, "iterate rows", err)
+		return nil, 0, r.logError(ctx, op, "iterate rows", err)
 	}
 
-	return calculations, nil
+	return calculations, total, nil
 }
 
 func (r *PgCalculationRepository) Update(ctx context.Context, calculation *orchestrator.Calculation) error {
 	const op = "PgCalculationRepository.Update"
 
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
 	if calculation == nil || calculation.ID == uuid.Nil {
 		return fmt.Errorf("%s: %w", op, ErrInvalidCalculation)
 	}
@@ -234,6 +405,9 @@ func (r *PgCalculationRepository) Update(ctx context.Context, calculation *orche
 func (r *PgCalculationRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status orchestrator.CalculationStatus, result string, errorMsg string) error {
 	const op = "PgCalculationRepository.UpdateStatus"
 
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
 	if id == uuid.Nil {
 		return fmt.Errorf("%s: %w", op, ErrInvalidCalculationID)
 	}
@@ -263,22 +437,200 @@ func (r *PgCalculationRepository) UpdateStatus(ctx context.Context, id uuid.UUID
 	return nil
 }
 
+func (r *PgCalculationRepository) UpdateDisplayResult(ctx context.Context, id uuid.UUID, displayResult string) error {
+	const op = "PgCalculationRepository.UpdateDisplayResult"
+
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
+	if id == uuid.Nil {
+		return fmt.Errorf("%s: %w", op, ErrInvalidCalculationID)
+	}
+
+	cmdTag, err := r.db.ExecContext(ctx, queryUpdateCalculationDisplayResult, id, displayResult)
+	if err != nil {
+		return r.logError(ctx, op, "update calculation display result", err)
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, ErrCalculationNotFound)
+	}
+
+	return nil
+}
+
+func (r *PgCalculationRepository) UpdateWarnings(ctx context.Context, id uuid.UUID, warnings []string) error {
+	const op = "PgCalculationRepository.UpdateWarnings"
+
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
+	if id == uuid.Nil {
+		return fmt.Errorf("%s: %w", op, ErrInvalidCalculationID)
+	}
+
+	if warnings == nil {
+		warnings = []string{}
+	}
+
+	cmdTag, err := r.db.ExecContext(ctx, queryUpdateCalculationWarnings, id, warnings)
+	if err != nil {
+		return r.logError(ctx, op, "update calculation warnings", err)
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, ErrCalculationNotFound)
+	}
+
+	return nil
+}
+
 func (r *PgCalculationRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	const op = "PgCalculationRepository.Delete"
 
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
 	if id == uuid.Nil {
 		return fmt.Errorf("%s: %w", op, ErrInvalidCalculationID)
 	}
 
+	cmdTag, err := r.db.ExecContext(ctx, querySoftDeleteCalculation, id, time.Now())
+	if err != nil {
+		return r.logError(ctx, op, "delete calculation", err)
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, ErrCalculationNotFound)
+	}
+
+	return nil
+}
+
+func (r *PgCalculationRepository) CountByStatus(ctx context.Context) (map[orchestrator.CalculationStatus]int, error) {
+	const op = "PgCalculationRepository.CountByStatus"
+
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
 	conn, err := r.acquireConn(ctx, op)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer conn.Release()
 
-	cmdTag, err := conn.Exec(ctx, queryDeleteCalculation, id)
+	rows, err := conn.Query(ctx, queryCountCalculationsByStatus)
 	if err != nil {
-		return r.logError(ctx, op, "delete calculation", err)
+		return nil, r.logError(ctx, op, "count calculations", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[orchestrator.CalculationStatus]int)
+	for rows.Next() {
+		var (
+			status orchestrator.CalculationStatus
+			count  int
+		)
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, r.logError(ctx, op, "scan status count", err)
+		}
+		counts[status] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, r.logError(ctx, op, "iterate rows", err)
+	}
+
+	return counts, nil
+}
+
+func (r *PgCalculationRepository) ResultStats(ctx context.Context, userID uuid.UUID) (*orchestrator.ResultStats, error) {
+	const op = "PgCalculationRepository.ResultStats"
+
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
+	if userID == uuid.Nil {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidUserID)
+	}
+
+	conn, err := r.acquireConn(ctx, op)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	var stats orchestrator.ResultStats
+	if err := conn.QueryRow(ctx, queryCalculationResultStats, userID).Scan(
+		&stats.Count, &stats.Sum, &stats.Mean, &stats.Min, &stats.Max,
+	); err != nil {
+		return nil, r.logError(ctx, op, "aggregate results", err)
+	}
+
+	return &stats, nil
+}
+
+func (r *PgCalculationRepository) FindCompactable(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error) {
+	const op = "PgCalculationRepository.FindCompactable"
+
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
+	if limit <= 0 {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidPagination)
+	}
+
+	conn, err := r.acquireConn(ctx, op)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(ctx, queryFindCompactableCalculations, before, limit)
+	if err != nil {
+		return nil, r.logError(ctx, op, "query compactable calculations", err)
+	}
+	defer rows.Close()
+
+	ids := make([]uuid.UUID, 0)
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, r.logError(ctx, op, "scan calculation ID", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, r.logError(ctx, op, "iterate rows", err)
+	}
+
+	return ids, nil
+}
+
+// ArchiveOperations сохраняет операции в JSON в записи вычисления. Возвращает
+// ErrCalculationNotFound, если вычисление не найдено, удалено или уже сжато.
+func (r *PgCalculationRepository) ArchiveOperations(ctx context.Context, calculationID uuid.UUID, operations []*orchestrator.Operation) error {
+	const op = "PgCalculationRepository.ArchiveOperations"
+
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
+	if calculationID == uuid.Nil {
+		return fmt.Errorf("%s: %w", op, ErrInvalidCalculationID)
+	}
+
+	if operations == nil {
+		operations = []*orchestrator.Operation{}
+	}
+	archive, err := json.Marshal(operations)
+	if err != nil {
+		return fmt.Errorf("%s: encode operations archive: %w", op, err)
+	}
+
+	cmdTag, err := r.db.ExecContext(ctx, queryArchiveCalculationOperations, calculationID, archive, time.Now())
+	if err != nil {
+		return r.logError(ctx, op, "archive operations", err)
 	}
 
 	if cmdTag.RowsAffected() == 0 {
@@ -297,6 +649,16 @@ func (r *PgCalculationRepository) acquireConn(ctx context.Context, op string) (*
 	return conn, nil
 }
 
+// acquireReadConn получает соединение для запроса только на чтение, по возможности с реплики.
+func (r *PgCalculationRepository) acquireReadConn(ctx context.Context, op string) (*pgxpool.Conn, error) {
+	conn, err := r.db.AcquireReadConn(ctx)
+	if err != nil {
+		logger.Error(ctx, nil, "Failed to acquire connection", zap.String("op", op), zap.Error(err))
+		return nil, fmt.Errorf("%s: acquire connection: %w", op, err)
+	}
+	return conn, nil
+}
+
 func (r *PgCalculationRepository) logError(ctx context.Context, op, action string, err error) error {
 	logger.Error(ctx, nil, "Failed to "+action, zap.String("op", op), zap.Error(err))
 	return fmt.Errorf("%s: %s: %w", op, action, err)
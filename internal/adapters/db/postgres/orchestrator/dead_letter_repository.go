@@ -0,0 +1,200 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	repo "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/repository/orchestrator"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/database"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+const (
+	queryCreateDeadLetter = `
+        INSERT INTO dead_letter_operations (
+            id, operation_id, calculation_id, expression, operation_type, operand1, operand2, error_history, deterministic, created_at
+        ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	queryFindPendingDeadLetters = `
+        SELECT id, operation_id, calculation_id, expression, operation_type, operand1, operand2, error_history, deterministic, created_at, replayed_at
+        FROM dead_letter_operations
+        WHERE replayed_at IS NULL
+        ORDER BY created_at
+        LIMIT $1`
+
+	queryMarkDeadLetterReplayed = `
+        UPDATE dead_letter_operations
+        SET replayed_at = $2
+        WHERE id = $1 AND replayed_at IS NULL`
+)
+
+var (
+	ErrInvalidDeadLetter  = errors.New("invalid dead letter")
+	ErrDeadLetterNotFound = errors.New("dead letter not found")
+)
+
+type PgDeadLetterRepository struct {
+	db *database.Handler
+
+	// statementTimeout - предельное время одного обращения к базе. Ноль - без ограничения.
+	statementTimeout time.Duration
+}
+
+var _ repo.DeadLetterRepository = (*PgDeadLetterRepository)(nil)
+
+func NewDeadLetterRepository(db *database.Handler) *PgDeadLetterRepository {
+	return &PgDeadLetterRepository{db: db}
+}
+
+// SetStatementTimeout ограничивает время каждого обращения репозитория к базе,
+// чтобы медленный запрос не удерживал соединение бесконечно. Ноль снимает ограничение.
+func (r *PgDeadLetterRepository) SetStatementTimeout(timeout time.Duration) {
+	r.statementTimeout = timeout
+}
+
+func (r *PgDeadLetterRepository) Create(ctx context.Context, deadLetter *orchestrator.DeadLetter) error {
+	const op = "PgDeadLetterRepository.Create"
+
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
+	if deadLetter == nil || deadLetter.OperationID == uuid.Nil {
+		return fmt.Errorf("%s: %w", op, ErrInvalidDeadLetter)
+	}
+
+	if deadLetter.ID == uuid.Nil {
+		deadLetter.ID = uuid.New()
+	}
+	if deadLetter.CreatedAt.IsZero() {
+		deadLetter.CreatedAt = time.Now()
+	}
+	if deadLetter.ErrorHistory == nil {
+		deadLetter.ErrorHistory = []string{}
+	}
+
+	conn, err := r.acquireConn(ctx, op)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	_, err = conn.Exec(ctx, queryCreateDeadLetter,
+		deadLetter.ID,
+		deadLetter.OperationID,
+		deadLetter.CalculationID,
+		deadLetter.Expression,
+		deadLetter.OperationType,
+		deadLetter.Operand1,
+		deadLetter.Operand2,
+		deadLetter.ErrorHistory,
+		deadLetter.Deterministic,
+		deadLetter.CreatedAt,
+	)
+	if err != nil {
+		return r.logError(ctx, op, "create dead letter", err)
+	}
+
+	logger.Info(ctx, nil, "Operation moved to dead letter table",
+		zap.String("id", deadLetter.ID.String()),
+		zap.String("operation_id", deadLetter.OperationID.String()))
+	return nil
+}
+
+func (r *PgDeadLetterRepository) FindPending(ctx context.Context, limit int) ([]*orchestrator.DeadLetter, error) {
+	const op = "PgDeadLetterRepository.FindPending"
+
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
+	if limit <= 0 {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidPagination)
+	}
+
+	conn, err := r.acquireConn(ctx, op)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(ctx, queryFindPendingDeadLetters, limit)
+	if err != nil {
+		return nil, r.logError(ctx, op, "query dead letters", err)
+	}
+	defer rows.Close()
+
+	deadLetters := make([]*orchestrator.DeadLetter, 0)
+	for rows.Next() {
+		var deadLetter orchestrator.DeadLetter
+		err := rows.Scan(
+			&deadLetter.ID,
+			&deadLetter.OperationID,
+			&deadLetter.CalculationID,
+			&deadLetter.Expression,
+			&deadLetter.OperationType,
+			&deadLetter.Operand1,
+			&deadLetter.Operand2,
+			&deadLetter.ErrorHistory,
+			&deadLetter.Deterministic,
+			&deadLetter.CreatedAt,
+			&deadLetter.ReplayedAt,
+		)
+		if err != nil {
+			return nil, r.logError(ctx, op, "scan dead letter row", err)
+		}
+		deadLetters = append(deadLetters, &deadLetter)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, r.logError(ctx, op, "iterate rows", err)
+	}
+
+	return deadLetters, nil
+}
+
+func (r *PgDeadLetterRepository) MarkReplayed(ctx context.Context, id uuid.UUID) error {
+	const op = "PgDeadLetterRepository.MarkReplayed"
+
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
+	if id == uuid.Nil {
+		return fmt.Errorf("%s: %w", op, ErrInvalidDeadLetter)
+	}
+
+	conn, err := r.acquireConn(ctx, op)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	cmdTag, err := conn.Exec(ctx, queryMarkDeadLetterReplayed, id, time.Now())
+	if err != nil {
+		return r.logError(ctx, op, "mark dead letter replayed", err)
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, ErrDeadLetterNotFound)
+	}
+
+	return nil
+}
+
+func (r *PgDeadLetterRepository) acquireConn(ctx context.Context, op string) (*pgxpool.Conn, error) {
+	conn, err := r.db.AcquireConn(ctx)
+	if err != nil {
+		logger.Error(ctx, nil, "Failed to acquire connection", zap.String("op", op), zap.Error(err))
+		return nil, fmt.Errorf("%s: acquire connection: %w", op, err)
+	}
+	return conn, nil
+}
+
+func (r *PgDeadLetterRepository) logError(ctx context.Context, op, action string, err error) error {
+	logger.Error(ctx, nil, "Failed to "+action, zap.String("op", op), zap.Error(err))
+	return fmt.Errorf("%s: %s: %w", op, action, err)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
 	repo "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/repository/orchestrator"
@@ -18,33 +19,33 @@ import (
 const (
 	queryCreateOperation = `
         INSERT INTO operations (
-            id, calculation_id, operation_type, operand1, operand2, result, status, error_message, processing_time_ms, agent_id
+            id, calculation_id, operation_type, operand1, operand2, result, status, error_message, processing_time_ms, agent_id, priority
         ) VALUES (
-            $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
-        ) RETURNING id, calculation_id, operation_type, operand1, operand2, result, status, error_message, processing_time_ms, agent_id`
+            $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+        ) RETURNING id, calculation_id, operation_type, operand1, operand2, result, status, error_message, processing_time_ms, agent_id, priority`
 
 	queryFindOperationByID = `
-        SELECT id, calculation_id, operation_type, operand1, operand2, result, status, error_message, processing_time_ms, agent_id
+        SELECT id, calculation_id, operation_type, operand1, operand2, result, status, error_message, processing_time_ms, agent_id, priority
         FROM operations
         WHERE id = $1`
 
 	queryFindOperationsByCalculationID = `
-        SELECT id, calculation_id, operation_type, operand1, operand2, result, status, error_message, processing_time_ms, agent_id
+        SELECT id, calculation_id, operation_type, operand1, operand2, result, status, error_message, processing_time_ms, agent_id, priority
         FROM operations
         WHERE calculation_id = $1
         ORDER BY id`
 
 	queryGetPendingOperations = `
-        SELECT id, calculation_id, operation_type, operand1, operand2, result, status, error_message, processing_time_ms, agent_id
+        SELECT id, calculation_id, operation_type, operand1, operand2, result, status, error_message, processing_time_ms, agent_id, priority
         FROM operations
         WHERE status = $1
-        ORDER BY id
+        ORDER BY priority DESC, id
         LIMIT $2`
 
 	queryUpdateOperation = `
         UPDATE operations
         SET calculation_id = $2, operation_type = $3, operand1 = $4, operand2 = $5, 
-            result = $6, status = $7, error_message = $8, processing_time_ms = $9, agent_id = $10
+            result = $6, status = $7, error_message = $8, processing_time_ms = $9, agent_id = $10, priority = $11
         WHERE id = $1`
 
 	queryUpdateOperationStatus = `
@@ -52,16 +53,34 @@ const (
         SET status = $2, result = $3, error_message = $4
         WHERE id = $1`
 
+	batchUpdateOperationStatus = `
+        UPDATE operations
+        SET status = $2, result = $3, error_message = $4
+        WHERE id = $1`
+
+	queryCountOperationsByStatus = `
+        SELECT COUNT(*)
+        FROM operations
+        WHERE status = $1`
+
+	queryCountPendingOperationsByUserID = `
+        SELECT COUNT(*)
+        FROM operations o
+        JOIN calculations c ON c.id = o.calculation_id
+        WHERE c.user_id = $1 AND c.deleted_at IS NULL AND o.status IN ($2, $3)`
+
 	queryAssignAgent = `
         UPDATE operations
         SET agent_id = $2, status = $3
         WHERE id = $1 AND status = $4`
 
+	queryDeleteOperationsByCalculationID = `DELETE FROM operations WHERE calculation_id = $1`
+
 	batchInsertOperation = `
         INSERT INTO operations (
-            id, calculation_id, operation_type, operand1, operand2, result, status, error_message, processing_time_ms, agent_id
+            id, calculation_id, operation_type, operand1, operand2, result, status, error_message, processing_time_ms, agent_id, priority
         ) VALUES (
-            $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+            $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
         )`
 )
 
@@ -78,6 +97,9 @@ var (
 
 type PgOperationRepository struct {
 	db *database.Handler
+
+	// statementTimeout - предельное время одного обращения к базе. Ноль - без ограничения.
+	statementTimeout time.Duration
 }
 
 var _ repo.OperationRepository = (*PgOperationRepository)(nil)
@@ -86,9 +108,18 @@ func NewOperationRepository(db *database.Handler) *PgOperationRepository {
 	return &PgOperationRepository{db: db}
 }
 
+// SetStatementTimeout ограничивает время каждого обращения репозитория к базе,
+// чтобы медленный запрос не удерживал соединение бесконечно. Ноль снимает ограничение.
+func (r *PgOperationRepository) SetStatementTimeout(timeout time.Duration) {
+	r.statementTimeout = timeout
+}
+
 func (r *PgOperationRepository) Create(ctx context.Context, operation *orchestrator.Operation) (*orchestrator.Operation, error) {
 	const op = "PgOperationRepository.Create"
 
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
 	if operation == nil {
 		return nil, fmt.Errorf("%s: %w", op, ErrOperationNil)
 	}
@@ -115,6 +146,7 @@ func (r *PgOperationRepository) Create(ctx context.Context, operation *orchestra
 		operation.ErrorMessage,
 		operation.ProcessingTime,
 		operation.AgentID,
+		operation.Priority,
 	).Scan(
 		&result.ID,
 		&result.CalculationID,
@@ -126,6 +158,7 @@ func (r *PgOperationRepository) Create(ctx context.Context, operation *orchestra
 		&result.ErrorMessage,
 		&result.ProcessingTime,
 		&result.AgentID,
+		&result.Priority,
 	)
 
 	if err != nil {
@@ -139,6 +172,9 @@ func (r *PgOperationRepository) Create(ctx context.Context, operation *orchestra
 func (r *PgOperationRepository) CreateBatch(ctx context.Context, operations []*orchestrator.Operation) error {
 	const op = "PgOperationRepository.CreateBatch"
 
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
 	if len(operations) == 0 {
 		return nil
 	}
@@ -192,6 +228,7 @@ func (r *PgOperationRepository) CreateBatch(ctx context.Context, operations []*o
 			operation.ErrorMessage,
 			operation.ProcessingTime,
 			operation.AgentID,
+			operation.Priority,
 		)
 	}
 
@@ -233,13 +270,131 @@ func (r *PgOperationRepository) CreateBatch(ctx context.Context, operations []*o
 	return nil
 }
 
+// CreateBatchPartial сохраняет операции в одной транзакции, оборачивая каждую вставку
+// в точку сохранения: ошибка вставки откатывает только свою операцию.
+func (r *PgOperationRepository) CreateBatchPartial(ctx context.Context, operations []*orchestrator.Operation) ([]orchestrator.OperationInsertResult, error) {
+	const op = "PgOperationRepository.CreateBatchPartial"
+
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
+	if len(operations) == 0 {
+		return nil, nil
+	}
+
+	conn, err := r.acquireConn(ctx, op)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, r.logError(ctx, op, "begin transaction", err)
+	}
+
+	var committed bool
+	defer func() {
+		if !committed {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				logger.Error(ctx, nil, "Failed to rollback transaction",
+					zap.String("op", op),
+					zap.Error(rbErr))
+			}
+		}
+	}()
+
+	results := make([]orchestrator.OperationInsertResult, len(operations))
+	inserted := 0
+	for i, operation := range operations {
+		results[i].Index = i
+
+		if operation == nil {
+			results[i].Err = ErrOperationNil
+			continue
+		}
+		if operation.ID == uuid.Nil {
+			operation.ID = uuid.New()
+		}
+		results[i].OperationID = operation.ID
+
+		if operation.CalculationID == uuid.Nil {
+			results[i].Err = ErrOperationHasNoCalcID
+			continue
+		}
+
+		if err = r.insertWithSavepoint(ctx, tx, operation); err != nil {
+			if ctx.Err() != nil {
+				return nil, r.logError(ctx, op, "insert operation", err)
+			}
+			logger.Warn(ctx, nil, "Failed to insert operation from batch",
+				zap.String("op", op),
+				zap.Int("index", i),
+				zap.String("operation_id", operation.ID.String()),
+				zap.Error(err))
+			results[i].Err = err
+			continue
+		}
+		inserted++
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, r.logError(ctx, op, "commit transaction", err)
+	}
+	committed = true
+
+	logger.Info(ctx, nil, "Created operations batch partially",
+		zap.Int("inserted", inserted),
+		zap.Int("failed", len(operations)-inserted))
+	return results, nil
+}
+
+// insertWithSavepoint вставляет операцию во вложенной транзакции (SAVEPOINT),
+// чтобы ошибка не прерывала внешнюю транзакцию.
+func (r *PgOperationRepository) insertWithSavepoint(ctx context.Context, tx pgx.Tx, operation *orchestrator.Operation) error {
+	savepoint, err := tx.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("create savepoint: %w", err)
+	}
+
+	_, err = savepoint.Exec(ctx, batchInsertOperation,
+		operation.ID,
+		operation.CalculationID,
+		operation.OperationType,
+		operation.Operand1,
+		operation.Operand2,
+		operation.Result,
+		operation.Status,
+		operation.ErrorMessage,
+		operation.ProcessingTime,
+		operation.AgentID,
+		operation.Priority,
+	)
+	if err != nil {
+		if rbErr := savepoint.Rollback(ctx); rbErr != nil {
+			return fmt.Errorf("%w (rollback to savepoint: %v)", err, rbErr)
+		}
+		return err
+	}
+
+	if err = savepoint.Commit(ctx); err != nil {
+		return fmt.Errorf("release savepoint: %w", err)
+	}
+	return nil
+}
+
 func (r *PgOperationRepository) FindByID(ctx context.Context, id uuid.UUID) (*orchestrator.Operation, error) {
 	const op = "PgOperationRepository.FindByID"
 
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
 	if id == uuid.Nil {
 		return nil, fmt.Errorf("%s: %w", op, ErrInvalidOperationID)
 	}
 
+	// Операции читают диспетчер и воркеры сразу после изменения статуса, поэтому чтение
+	// идет с основного сервера: отстающая реплика вернула бы устаревший статус
 	conn, err := r.acquireConn(ctx, op)
 	if err != nil {
 		return nil, err
@@ -258,6 +413,7 @@ func (r *PgOperationRepository) FindByID(ctx context.Context, id uuid.UUID) (*or
 		&operation.ErrorMessage,
 		&operation.ProcessingTime,
 		&operation.AgentID,
+		&operation.Priority,
 	)
 
 	if err != nil {
@@ -273,6 +429,9 @@ func (r *PgOperationRepository) FindByID(ctx context.Context, id uuid.UUID) (*or
 func (r *PgOperationRepository) FindByCalculationID(ctx context.Context, calculationID uuid.UUID) ([]*orchestrator.Operation, error) {
 	const op = "PgOperationRepository.FindByCalculationID"
 
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
 	if calculationID == uuid.Nil {
 		return nil, fmt.Errorf("%s: %w", op, ErrInvalidCalculationID2)
 	}
@@ -303,6 +462,7 @@ func (r *PgOperationRepository) FindByCalculationID(ctx context.Context, calcula
 			&operation.ErrorMessage,
 			&operation.ProcessingTime,
 			&operation.AgentID,
+			&operation.Priority,
 		)
 		if err != nil {
 			return nil, r.logError(ctx, op, "scan row", err)
@@ -320,10 +480,15 @@ func (r *PgOperationRepository) FindByCalculationID(ctx context.Context, calcula
 func (r *PgOperationRepository) GetPendingOperations(ctx context.Context, limit int) ([]*orchestrator.Operation, error) {
 	const op = "PgOperationRepository.GetPendingOperations"
 
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
 	if limit <= 0 {
 		limit = 10
 	}
 
+	// С отстающей реплики уже назначенные операции выглядели бы ожидающими и были бы
+	// назначены повторно, поэтому выборка идет с основного сервера
 	conn, err := r.acquireConn(ctx, op)
 	if err != nil {
 		return nil, err
@@ -351,6 +516,7 @@ func (r *PgOperationRepository) GetPendingOperations(ctx context.Context, limit
 			&operation.ErrorMessage,
 			&operation.ProcessingTime,
 			&operation.AgentID,
+			&operation.Priority,
 		)
 		if err != nil {
 			return nil, r.logError(ctx, op, "scan row", err)
@@ -368,6 +534,9 @@ func (r *PgOperationRepository) GetPendingOperations(ctx context.Context, limit
 func (r *PgOperationRepository) Update(ctx context.Context, operation *orchestrator.Operation) error {
 	const op = "PgOperationRepository.Update"
 
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
 	if operation == nil || operation.ID == uuid.Nil {
 		return fmt.Errorf("%s: %w", op, ErrInvalidOperation)
 	}
@@ -389,6 +558,7 @@ func (r *PgOperationRepository) Update(ctx context.Context, operation *orchestra
 		operation.ErrorMessage,
 		operation.ProcessingTime,
 		operation.AgentID,
+		operation.Priority,
 	)
 
 	if err != nil {
@@ -405,6 +575,9 @@ func (r *PgOperationRepository) Update(ctx context.Context, operation *orchestra
 func (r *PgOperationRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status orchestrator.OperationStatus, result string, errorMsg string) error {
 	const op = "PgOperationRepository.UpdateStatus"
 
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
 	if id == uuid.Nil {
 		return fmt.Errorf("%s: %w", op, ErrInvalidOperationID)
 	}
@@ -433,9 +606,87 @@ func (r *PgOperationRepository) UpdateStatus(ctx context.Context, id uuid.UUID,
 	return nil
 }
 
+// UpdateStatusBatch отправляет обновления статусов одним пакетом в транзакции.
+// Обновление несуществующей операции откатывает весь пакет.
+func (r *PgOperationRepository) UpdateStatusBatch(ctx context.Context, updates []orchestrator.OperationStatusUpdate) error {
+	const op = "PgOperationRepository.UpdateStatusBatch"
+
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
+	if len(updates) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, update := range updates {
+		if update.ID == uuid.Nil {
+			return fmt.Errorf("%s: %w", op, ErrInvalidOperationID)
+		}
+		batch.Queue(batchUpdateOperationStatus, update.ID, update.Status, update.Result, update.ErrorMessage)
+	}
+
+	conn, err := r.acquireConn(ctx, op)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return r.logError(ctx, op, "begin transaction", err)
+	}
+
+	var committed bool
+	defer func() {
+		if !committed {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				logger.Error(ctx, nil, "Failed to rollback transaction",
+					zap.String("op", op),
+					zap.Error(rbErr))
+			}
+		}
+	}()
+
+	batchResults := tx.SendBatch(ctx, batch)
+	err = func() error {
+		defer func() {
+			if closeErr := batchResults.Close(); closeErr != nil {
+				logger.Error(ctx, nil, "Failed to close batch results",
+					zap.String("op", op), zap.Error(closeErr))
+			}
+		}()
+
+		for i, update := range updates {
+			cmdTag, err := batchResults.Exec()
+			if err != nil {
+				return r.logError(ctx, op, fmt.Sprintf("execute batch query at index %d", i), err)
+			}
+			if cmdTag.RowsAffected() == 0 {
+				return fmt.Errorf("%s: %w: %s", op, ErrOperationNotFound, update.ID)
+			}
+		}
+		return nil
+	}()
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return r.logError(ctx, op, "commit transaction", err)
+	}
+	committed = true
+
+	logger.Debug(ctx, nil, "Updated operation statuses batch", zap.Int("count", len(updates)))
+	return nil
+}
+
 func (r *PgOperationRepository) AssignAgent(ctx context.Context, operationID uuid.UUID, agentID string) error {
 	const op = "PgOperationRepository.AssignAgent"
 
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
 	if operationID == uuid.Nil || agentID == "" {
 		return fmt.Errorf("%s: %w", op, ErrInvalidOperationOrAgentID)
 	}
@@ -464,6 +715,66 @@ func (r *PgOperationRepository) AssignAgent(ctx context.Context, operationID uui
 	return nil
 }
 
+func (r *PgOperationRepository) CountByStatus(ctx context.Context, status orchestrator.OperationStatus) (int, error) {
+	const op = "PgOperationRepository.CountByStatus"
+
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
+	conn, err := r.acquireConn(ctx, op)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Release()
+
+	var total int
+	if err := conn.QueryRow(ctx, queryCountOperationsByStatus, status).Scan(&total); err != nil {
+		return 0, r.logError(ctx, op, "count operations", err)
+	}
+
+	return total, nil
+}
+
+func (r *PgOperationRepository) CountPendingByUserID(ctx context.Context, userID uuid.UUID) (int, error) {
+	const op = "PgOperationRepository.CountPendingByUserID"
+
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
+	conn, err := r.acquireConn(ctx, op)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Release()
+
+	var total int
+	err = conn.QueryRow(ctx, queryCountPendingOperationsByUserID,
+		userID, orchestrator.OperationStatusPending, orchestrator.OperationStatusInProgress).Scan(&total)
+	if err != nil {
+		return 0, r.logError(ctx, op, "count pending operations", err)
+	}
+
+	return total, nil
+}
+
+func (r *PgOperationRepository) DeleteByCalculationID(ctx context.Context, calculationID uuid.UUID) (int, error) {
+	const op = "PgOperationRepository.DeleteByCalculationID"
+
+	ctx, cancel := database.WithStatementTimeout(ctx, r.statementTimeout)
+	defer cancel()
+
+	if calculationID == uuid.Nil {
+		return 0, fmt.Errorf("%s: %w", op, ErrInvalidCalculationID2)
+	}
+
+	cmdTag, err := r.db.ExecContext(ctx, queryDeleteOperationsByCalculationID, calculationID)
+	if err != nil {
+		return 0, r.logError(ctx, op, "delete operations", err)
+	}
+
+	return int(cmdTag.RowsAffected()), nil
+}
+
 func (r *PgOperationRepository) acquireConn(ctx context.Context, op string) (*pgxpool.Conn, error) {
 	conn, err := r.db.AcquireConn(ctx)
 	if err != nil {
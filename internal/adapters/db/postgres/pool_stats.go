@@ -0,0 +1,41 @@
+// Package postgres содержит общие для сервисов адаптеры PostgreSQL.
+package postgres
+
+import (
+	"context"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/system"
+	systemrepo "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/repository/system"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/database"
+)
+
+// PoolStatsProvider возвращает состояние пула соединений pgx.
+type PoolStatsProvider struct {
+	db *database.Handler
+}
+
+var _ systemrepo.DBPoolStatsProvider = (*PoolStatsProvider)(nil)
+
+func NewPoolStatsProvider(db *database.Handler) *PoolStatsProvider {
+	return &PoolStatsProvider{db: db}
+}
+
+func (p *PoolStatsProvider) PoolStats() system.DBPoolStats {
+	if p.db == nil {
+		return system.DBPoolStats{}
+	}
+
+	stats, err := p.db.Stats(context.Background())
+	if err != nil {
+		return system.DBPoolStats{}
+	}
+
+	return system.DBPoolStats{
+		TotalConns:        stats.TotalConns,
+		IdleConns:         stats.IdleConns,
+		AcquiredConns:     stats.AcquiredConns,
+		MaxConns:          stats.MaxConns,
+		AcquireCount:      stats.AcquireCount,
+		AcquireDurationMs: stats.AcquireDuration.Milliseconds(),
+	}
+}
@@ -0,0 +1,79 @@
+// Package redis содержит кэш результатов выражений в Redis, общий для нескольких оркестраторов.
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	orchrepo "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/repository/orchestrator"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// cachedEntry - сериализованное значение ключа кэша.
+type cachedEntry struct {
+	Result string                         `json:"result"`
+	Status orchestrator.CalculationStatus `json:"status"`
+}
+
+// ResultCache хранит результаты выражений в Redis. Ошибки Redis возвращаются вызывающему
+// коду, который продолжает вычисление без кэша.
+type ResultCache struct {
+	client    goredis.UniversalClient
+	keyPrefix string
+	ttl       time.Duration
+}
+
+var _ orchrepo.ResultCache = (*ResultCache)(nil)
+
+// NewResultCache создает кэш поверх клиента Redis. Ключи получают префикс keyPrefix,
+// значения хранятся ttl; нулевой ttl хранит их без ограничения.
+func NewResultCache(client goredis.UniversalClient, keyPrefix string, ttl time.Duration) *ResultCache {
+	return &ResultCache{
+		client:    client,
+		keyPrefix: keyPrefix,
+		ttl:       max(ttl, 0),
+	}
+}
+
+// Get возвращает сохраненный результат выражения. Значение, которое не удалось разобрать
+// или которое не описывает завершенное вычисление, считается отсутствующим.
+func (c *ResultCache) Get(ctx context.Context, expression string) (string, bool, error) {
+	const op = "ResultCache.Get"
+
+	data, err := c.client.Get(ctx, c.key(expression)).Bytes()
+	if errors.Is(err, goredis.Nil) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var entry cachedEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Status != orchestrator.CalculationStatusCompleted {
+		return "", false, nil
+	}
+	return entry.Result, true, nil
+}
+
+// Set сохраняет результат выражения.
+func (c *ResultCache) Set(ctx context.Context, expression string, result string) error {
+	const op = "ResultCache.Set"
+
+	data, err := json.Marshal(cachedEntry{Result: result, Status: orchestrator.CalculationStatusCompleted})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := c.client.Set(ctx, c.key(expression), data, c.ttl).Err(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (c *ResultCache) key(expression string) string {
+	return c.keyPrefix + expression
+}
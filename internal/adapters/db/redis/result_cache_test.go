@@ -0,0 +1,83 @@
+package redis_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/db/redis"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCache(t *testing.T, ttl time.Duration) (*redis.ResultCache, *miniredis.Miniredis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: server.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { _ = client.Close() })
+
+	return redis.NewResultCache(client, "calc:result:", ttl), server
+}
+
+func TestResultCache_GetSet(t *testing.T) {
+	ctx := context.Background()
+	c, server := newTestCache(t, time.Hour)
+
+	_, found, err := c.Get(ctx, "2+2")
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	require.NoError(t, c.Set(ctx, "2+2", "4"))
+
+	result, found, err := c.Get(ctx, "2+2")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "4", result)
+
+	stored, err := server.Get("calc:result:2+2")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"result":"4","status":"COMPLETED"}`, stored)
+}
+
+func TestResultCache_Expiry(t *testing.T) {
+	ctx := context.Background()
+	c, server := newTestCache(t, time.Minute)
+
+	require.NoError(t, c.Set(ctx, "1+1", "2"))
+	assert.Equal(t, time.Minute, server.TTL("calc:result:1+1"))
+
+	server.FastForward(time.Minute)
+
+	_, found, err := c.Get(ctx, "1+1")
+	require.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestResultCache_InvalidEntry(t *testing.T) {
+	ctx := context.Background()
+	c, server := newTestCache(t, 0)
+
+	require.NoError(t, server.Set("calc:result:3+3", "not json"))
+	require.NoError(t, server.Set("calc:result:4+4", `{"result":"","status":"ERROR"}`))
+
+	for _, expression := range []string{"3+3", "4+4"} {
+		_, found, err := c.Get(ctx, expression)
+		require.NoError(t, err)
+		assert.False(t, found, expression)
+	}
+}
+
+func TestResultCache_Outage(t *testing.T) {
+	ctx := context.Background()
+	c, server := newTestCache(t, time.Hour)
+
+	server.Close()
+
+	_, found, err := c.Get(ctx, "2+2")
+	require.Error(t, err)
+	assert.False(t, found)
+	require.Error(t, c.Set(ctx, "2+2", "4"))
+}
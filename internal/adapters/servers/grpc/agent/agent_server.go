@@ -0,0 +1,171 @@
+// Package agent реализует gRPC сервис, через который агенты из отдельных процессов
+// регистрируются в оркестраторе, получают операции и сообщают их результаты.
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/grpc/middleware"
+	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
+	agentapi "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/api/agent"
+	agentv1 "github.com/flexer2006/y.lms-final-task-calc-go/pkg/api/proto/v1/agent"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	fieldOp          = "op"
+	fieldAgentID     = "agent_id"
+	fieldOperationID = "operation_id"
+
+	msgAgentRegistered    = "Remote agent registered"
+	msgEmptyAgentID       = "Empty agent ID provided"
+	msgInvalidOperationID = "Invalid operation ID"
+	msgAgentNotFound      = "Agent not found"
+	msgOperationNotFound  = "Operation is not assigned to agent"
+	msgInvalidCapacity    = "Invalid agent capacity"
+	msgAgentAlreadyExists = "Agent ID is taken by a pool worker"
+	msgPoolUnavailable    = "Agent pool is not accepting agents"
+	msgOperationsFetched  = "Operations fetched by remote agent"
+	msgResultReported     = "Operation result reported by remote agent"
+
+	errAgentIDEmpty       = "agent ID cannot be empty"
+	errInvalidOperationID = "invalid operation ID"
+	errAgentNotFound      = "agent not found"
+	errOperationNotFound  = "operation not found"
+	errInvalidCapacity    = "invalid agent capacity"
+	errAgentAlreadyExists = "agent already exists"
+	errPoolUnavailable    = "agent pool is not available"
+	errRegisterFailed     = "failed to register agent"
+	errFetchFailed        = "failed to fetch operations"
+	errReportFailed       = "failed to report operation result"
+
+	opRegister        = "AgentServer.Register"
+	opFetchOperations = "AgentServer.FetchOperations"
+	opReportResult    = "AgentServer.ReportResult"
+)
+
+type Server struct {
+	agentv1.UnimplementedAgentServiceServer
+	registry agentapi.RemoteAgentRegistry
+}
+
+func NewServer(registry agentapi.RemoteAgentRegistry) *Server {
+	return &Server{
+		registry: registry,
+	}
+}
+
+func newGRPCError(code codes.Code, msg string) error {
+	return fmt.Errorf("gRPC error: %w", status.Error(code, msg))
+}
+
+// newDomainError создает ошибку gRPC для доменной ошибки cause и передает ее код в деталях ErrorInfo.
+func newDomainError(code codes.Code, msg string, cause error) error {
+	return fmt.Errorf("gRPC error: %w", middleware.StatusError(code, msg, cause))
+}
+
+func (s *Server) Register(ctx context.Context, req *agentv1.RegisterRequest) (*agentv1.RegisterResponse, error) {
+	log := logger.ContextLogger(ctx, nil).With(
+		zap.String(fieldOp, opRegister),
+		zap.String(fieldAgentID, req.GetAgentId()))
+
+	registered, err := s.registry.RegisterRemote(ctx, req.GetAgentId(), int(req.GetCapacity()))
+	if err != nil {
+		switch {
+		case errors.Is(err, domainerrors.ErrInvalidCapacity):
+			log.Warn(msgInvalidCapacity, zap.Error(err))
+			return nil, newDomainError(codes.InvalidArgument, errInvalidCapacity, err)
+		case errors.Is(err, domainerrors.ErrAgentAlreadyExists):
+			log.Warn(msgAgentAlreadyExists)
+			return nil, newDomainError(codes.AlreadyExists, errAgentAlreadyExists, err)
+		case errors.Is(err, domainerrors.ErrPoolNotRunning), errors.Is(err, domainerrors.ErrPoolDraining):
+			log.Warn(msgPoolUnavailable, zap.Error(err))
+			return nil, newDomainError(codes.Unavailable, errPoolUnavailable, err)
+		}
+		log.Error(errRegisterFailed, zap.Error(err))
+		return nil, newGRPCError(codes.Internal, errRegisterFailed)
+	}
+
+	log.Info(msgAgentRegistered, zap.String(fieldAgentID, registered.ID))
+	return &agentv1.RegisterResponse{
+		AgentId:  registered.ID,
+		Capacity: int32(registered.MaxCapacity), //nolint:gosec
+	}, nil
+}
+
+func (s *Server) FetchOperations(ctx context.Context, req *agentv1.FetchOperationsRequest) (*agentv1.FetchOperationsResponse, error) {
+	log := logger.ContextLogger(ctx, nil).With(
+		zap.String(fieldOp, opFetchOperations),
+		zap.String(fieldAgentID, req.GetAgentId()))
+
+	if req.GetAgentId() == "" {
+		log.Warn(msgEmptyAgentID)
+		return nil, newGRPCError(codes.InvalidArgument, errAgentIDEmpty)
+	}
+
+	tasks, err := s.registry.FetchOperations(ctx, req.GetAgentId(), int(req.GetLimit()))
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrAgentNotFound) {
+			log.Warn(msgAgentNotFound)
+			return nil, newDomainError(codes.NotFound, errAgentNotFound, err)
+		}
+		log.Error(errFetchFailed, zap.Error(err))
+		return nil, newGRPCError(codes.Internal, errFetchFailed)
+	}
+
+	resp := &agentv1.FetchOperationsResponse{Tasks: make([]*agentv1.Task, 0, len(tasks))}
+	for _, task := range tasks {
+		resp.Tasks = append(resp.Tasks, &agentv1.Task{
+			OperationId:     task.Operation.ID.String(),
+			OperationType:   int32(task.Operation.OperationType), //nolint:gosec
+			Operand1:        task.Operation.Operand1,
+			Operand2:        task.Operation.Operand2,
+			OperationTimeMs: task.Duration.Milliseconds(),
+		})
+	}
+
+	if len(resp.Tasks) > 0 {
+		log.Debug(msgOperationsFetched, zap.Int("count", len(resp.Tasks)))
+	}
+	return resp, nil
+}
+
+func (s *Server) ReportResult(ctx context.Context, req *agentv1.ReportResultRequest) (*agentv1.ReportResultResponse, error) {
+	log := logger.ContextLogger(ctx, nil).With(
+		zap.String(fieldOp, opReportResult),
+		zap.String(fieldAgentID, req.GetAgentId()),
+		zap.String(fieldOperationID, req.GetOperationId()))
+
+	if req.GetAgentId() == "" {
+		log.Warn(msgEmptyAgentID)
+		return nil, newGRPCError(codes.InvalidArgument, errAgentIDEmpty)
+	}
+
+	operationID, err := uuid.Parse(req.GetOperationId())
+	if err != nil {
+		log.Warn(msgInvalidOperationID, zap.Error(err))
+		return nil, newGRPCError(codes.InvalidArgument, errInvalidOperationID)
+	}
+
+	if err := s.registry.ReportResult(ctx, req.GetAgentId(), operationID, req.GetResult(), req.GetError()); err != nil {
+		switch {
+		case errors.Is(err, domainerrors.ErrAgentNotFound):
+			log.Warn(msgAgentNotFound)
+			return nil, newDomainError(codes.NotFound, errAgentNotFound, err)
+		case errors.Is(err, domainerrors.ErrOperationNotFound):
+			log.Warn(msgOperationNotFound)
+			return nil, newDomainError(codes.NotFound, errOperationNotFound, err)
+		}
+		log.Error(errReportFailed, zap.Error(err))
+		return nil, newGRPCError(codes.Internal, errReportFailed)
+	}
+
+	log.Debug(msgResultReported, zap.Bool("failed", req.GetError() != ""))
+	return &agentv1.ReportResultResponse{}, nil
+}
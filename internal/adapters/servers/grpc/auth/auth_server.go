@@ -2,11 +2,16 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/grpc/middleware"
+	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
+	authmodels "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/auth"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/api/auth"
 	authv1 "github.com/flexer2006/y.lms-final-task-calc-go/pkg/api/proto/v1/auth"
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -14,29 +19,57 @@ import (
 )
 
 const (
-	fieldOp    = "op"
-	fieldLogin = "login"
+	fieldOp     = "op"
+	fieldLogin  = "login"
+	fieldUserID = "user_id"
 
 	msgEmptyLogin    = "Empty login provided"
 	msgEmptyPassword = "Empty password provided"
 	msgNoToken       = "Empty token provided" //nolint:gosec
 	msgTokenFailed   = "Token validation failed"
+	msgInvalidUserID = "Invalid user ID provided"
+	msgInvalidSessID = "Invalid session ID provided"
 
 	errLoginEmpty     = "login cannot be empty"
 	errPasswordEmpty  = "password cannot be empty"
 	errTokenEmpty     = "token cannot be empty"
 	errRegisterFailed = "failed to register user"
+	errUserExists     = "user already exists"
+	errWeakPassword   = "password does not meet the password policy"
 	errLoginFailed    = "failed to login user"
+	errLoginLimited   = "too many failed login attempts"
+	errInvalidUserID  = "invalid user ID"
+	errUserNotFound   = "user not found"
+	errGetUserFailed  = "failed to get user"
+	errInvalidPass    = "invalid password"
+	errDeleteFailed   = "failed to delete user"
+	errSessionsFailed = "failed to list sessions"
+	errInvalidSessID  = "invalid session ID"
+	errSessNotFound   = "session not found"
+	errRevokeFailed   = "failed to revoke session"
+	errRevokeAllFail  = "failed to revoke sessions"
+	errStatsFailed    = "failed to get stats"
 
 	opRegister        = "AuthServer.Register"
 	opLogin           = "AuthServer.Login"
 	opTokenValidation = "AuthServer.ValidateToken" //nolint:gosec
+	opGetUser         = "AuthServer.GetUser"
+	opDeleteUser      = "AuthServer.DeleteUser"
+	opListSessions    = "AuthServer.ListSessions"
+	opRevokeSession   = "AuthServer.RevokeSession"
+	opRevokeAll       = "AuthServer.RevokeAllSessions"
+	opGetStats        = "AuthServer.GetStats"
 )
 
 func wrapError(code codes.Code, msg string) error {
 	return fmt.Errorf("grpc error: %w", status.Error(code, msg))
 }
 
+// wrapDomainError создает ошибку gRPC для доменной ошибки cause и передает ее код в деталях ErrorInfo.
+func wrapDomainError(code codes.Code, msg string, cause error) error {
+	return fmt.Errorf("grpc error: %w", middleware.StatusError(code, msg, cause))
+}
+
 type Server struct {
 	authv1.UnimplementedAuthServiceServer
 	authUseCase auth.UseCaseUser
@@ -64,8 +97,15 @@ func (s *Server) Register(ctx context.Context, req *authv1.RegisterRequest) (*au
 
 	userID, err := s.authUseCase.Register(ctx, login, password)
 	if err != nil {
-		log.Error(errRegisterFailed, zap.Error(err))
-		return nil, wrapError(codes.Internal, errRegisterFailed)
+		switch {
+		case errors.Is(err, domainerrors.ErrUserAlreadyExists):
+			return nil, wrapDomainError(codes.AlreadyExists, errUserExists, err)
+		case errors.Is(err, domainerrors.ErrWeakPassword):
+			return nil, wrapDomainError(codes.InvalidArgument, errWeakPassword, err)
+		default:
+			log.Error(errRegisterFailed, zap.Error(err))
+			return nil, wrapError(codes.Internal, errRegisterFailed)
+		}
 	}
 
 	return &authv1.RegisterResponse{
@@ -87,10 +127,18 @@ func (s *Server) Login(ctx context.Context, req *authv1.LoginRequest) (*authv1.L
 		return nil, wrapError(codes.InvalidArgument, errPasswordEmpty)
 	}
 
+	ctx = authmodels.WithClientInfo(ctx, authmodels.ClientInfo{
+		UserAgent: req.GetUserAgent(),
+		IP:        req.GetIp(),
+	})
+
 	tokenPair, err := s.authUseCase.Login(ctx, login, password)
 	if err != nil {
 		log.Error(errLoginFailed, zap.Error(err))
-		return nil, wrapError(codes.Unauthenticated, errLoginFailed)
+		if errors.Is(err, domainerrors.ErrTooManyAttempts) {
+			return nil, wrapDomainError(codes.ResourceExhausted, errLoginLimited, err)
+		}
+		return nil, wrapDomainError(codes.Unauthenticated, errLoginFailed, domainerrors.ErrInvalidCredentials)
 	}
 
 	return &authv1.LoginResponse{
@@ -125,3 +173,158 @@ func (s *Server) ValidateToken(ctx context.Context, req *authv1.ValidateTokenReq
 		Valid:  true,
 	}, nil
 }
+
+func (s *Server) GetUser(ctx context.Context, req *authv1.GetUserRequest) (*authv1.GetUserResponse, error) {
+	log := logger.ContextLogger(ctx, nil).With(zap.String(fieldOp, opGetUser), zap.String(fieldUserID, req.GetUserId()))
+
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil || userID == uuid.Nil {
+		log.Warn(msgInvalidUserID)
+		return nil, wrapError(codes.InvalidArgument, errInvalidUserID)
+	}
+
+	user, err := s.authUseCase.GetUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrUserNotFound) {
+			return nil, wrapDomainError(codes.NotFound, errUserNotFound, err)
+		}
+		log.Error(errGetUserFailed, zap.Error(err))
+		return nil, wrapError(codes.Internal, errGetUserFailed)
+	}
+
+	return &authv1.GetUserResponse{
+		UserId:    user.ID.String(),
+		Login:     user.Login,
+		CreatedAt: timestamppb.New(user.CreatedAt),
+		UpdatedAt: timestamppb.New(user.UpdatedAt),
+	}, nil
+}
+
+func (s *Server) DeleteUser(ctx context.Context, req *authv1.DeleteUserRequest) (*authv1.DeleteUserResponse, error) {
+	log := logger.ContextLogger(ctx, nil).With(zap.String(fieldOp, opDeleteUser), zap.String(fieldUserID, req.GetUserId()))
+
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil || userID == uuid.Nil {
+		log.Warn(msgInvalidUserID)
+		return nil, wrapError(codes.InvalidArgument, errInvalidUserID)
+	}
+
+	if req.GetPassword() == "" {
+		log.Warn(msgEmptyPassword)
+		return nil, wrapError(codes.InvalidArgument, errPasswordEmpty)
+	}
+
+	if err := s.authUseCase.DeleteUser(ctx, userID, req.GetPassword()); err != nil {
+		switch {
+		case errors.Is(err, domainerrors.ErrInvalidCredentials):
+			return nil, wrapDomainError(codes.PermissionDenied, errInvalidPass, err)
+		case errors.Is(err, domainerrors.ErrTooManyAttempts):
+			return nil, wrapDomainError(codes.ResourceExhausted, errLoginLimited, err)
+		case errors.Is(err, domainerrors.ErrUserNotFound):
+			return nil, wrapDomainError(codes.NotFound, errUserNotFound, err)
+		default:
+			log.Error(errDeleteFailed, zap.Error(err))
+			return nil, wrapError(codes.Internal, errDeleteFailed)
+		}
+	}
+
+	return &authv1.DeleteUserResponse{}, nil
+}
+
+func (s *Server) ListSessions(ctx context.Context, req *authv1.ListSessionsRequest) (*authv1.ListSessionsResponse, error) {
+	log := logger.ContextLogger(ctx, nil).With(zap.String(fieldOp, opListSessions), zap.String(fieldUserID, req.GetUserId()))
+
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil || userID == uuid.Nil {
+		log.Warn(msgInvalidUserID)
+		return nil, wrapError(codes.InvalidArgument, errInvalidUserID)
+	}
+
+	sessions, err := s.authUseCase.ListSessions(ctx, userID)
+	if err != nil {
+		log.Error(errSessionsFailed, zap.Error(err))
+		return nil, wrapError(codes.Internal, errSessionsFailed)
+	}
+
+	resp := &authv1.ListSessionsResponse{
+		Sessions: make([]*authv1.Session, 0, len(sessions)),
+	}
+	for _, session := range sessions {
+		resp.Sessions = append(resp.Sessions, &authv1.Session{
+			Id:        session.ID.String(),
+			UserAgent: session.UserAgent,
+			Ip:        session.IP,
+			CreatedAt: timestamppb.New(session.CreatedAt),
+			ExpiresAt: timestamppb.New(session.ExpiresAt),
+		})
+	}
+
+	return resp, nil
+}
+
+func (s *Server) RevokeSession(ctx context.Context, req *authv1.RevokeSessionRequest) (*authv1.RevokeSessionResponse, error) {
+	log := logger.ContextLogger(ctx, nil).With(zap.String(fieldOp, opRevokeSession), zap.String(fieldUserID, req.GetUserId()))
+
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil || userID == uuid.Nil {
+		log.Warn(msgInvalidUserID)
+		return nil, wrapError(codes.InvalidArgument, errInvalidUserID)
+	}
+
+	sessionID, err := uuid.Parse(req.GetSessionId())
+	if err != nil || sessionID == uuid.Nil {
+		log.Warn(msgInvalidSessID)
+		return nil, wrapError(codes.InvalidArgument, errInvalidSessID)
+	}
+
+	if err := s.authUseCase.RevokeSession(ctx, userID, sessionID); err != nil {
+		switch {
+		case errors.Is(err, domainerrors.ErrTokenNotFound), errors.Is(err, domainerrors.ErrUnauthorizedAccess):
+			return nil, wrapDomainError(codes.NotFound, errSessNotFound, domainerrors.ErrTokenNotFound)
+		default:
+			log.Error(errRevokeFailed, zap.Error(err))
+			return nil, wrapError(codes.Internal, errRevokeFailed)
+		}
+	}
+
+	return &authv1.RevokeSessionResponse{}, nil
+}
+
+func (s *Server) RevokeAllSessions(ctx context.Context, req *authv1.RevokeAllSessionsRequest) (*authv1.RevokeAllSessionsResponse, error) {
+	log := logger.ContextLogger(ctx, nil).With(zap.String(fieldOp, opRevokeAll), zap.String(fieldUserID, req.GetUserId()))
+
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil || userID == uuid.Nil {
+		log.Warn(msgInvalidUserID)
+		return nil, wrapError(codes.InvalidArgument, errInvalidUserID)
+	}
+
+	if err := s.authUseCase.RevokeAllSessions(ctx, userID); err != nil {
+		log.Error(errRevokeAllFail, zap.Error(err))
+		return nil, wrapError(codes.Internal, errRevokeAllFail)
+	}
+
+	return &authv1.RevokeAllSessionsResponse{}, nil
+}
+
+func (s *Server) GetStats(ctx context.Context, _ *authv1.GetStatsRequest) (*authv1.GetStatsResponse, error) {
+	log := logger.ContextLogger(ctx, nil).With(zap.String(fieldOp, opGetStats))
+
+	stats, err := s.authUseCase.GetStats(ctx)
+	if err != nil {
+		log.Error(errStatsFailed, zap.Error(err))
+		return nil, wrapError(codes.Internal, errStatsFailed)
+	}
+
+	return &authv1.GetStatsResponse{
+		TotalUsers: int64(stats.TotalUsers),
+		DbPool: &authv1.DBPoolStats{
+			TotalConns:        stats.DBPool.TotalConns,
+			IdleConns:         stats.DBPool.IdleConns,
+			AcquiredConns:     stats.DBPool.AcquiredConns,
+			MaxConns:          stats.DBPool.MaxConns,
+			AcquireCount:      stats.DBPool.AcquireCount,
+			AcquireDurationMs: stats.DBPool.AcquireDurationMs,
+		},
+	}, nil
+}
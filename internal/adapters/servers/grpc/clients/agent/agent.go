@@ -0,0 +1,223 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/grpc/middleware"
+	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/agent"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	agentAPI "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/api/agent"
+	agentv1 "github.com/flexer2006/y.lms-final-task-calc-go/pkg/api/proto/v1/agent"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	methodRegister        = "Register"
+	methodFetchOperations = "FetchOperations"
+	methodReportResult    = "ReportResult"
+
+	fieldMethod      = "method"
+	fieldAgentID     = "agent_id"
+	fieldOperationID = "operation_id"
+
+	msgFailedRegister        = "failed to register agent"
+	msgFailedFetchOperations = "failed to fetch operations"
+	msgFailedReportResult    = "failed to report operation result"
+
+	defaultDialTimeout = 5 * time.Second
+)
+
+var (
+	ErrConnectionTimeout   = errors.New("connection timeout: failed to connect to agent service")
+	ErrInvalidResponse     = errors.New("invalid response from agent service")
+	ErrInternalServerError = errors.New("internal server error")
+)
+
+var _ agentAPI.RemoteAgentRegistry = (*Client)(nil)
+
+// Client - клиент сервиса агентов оркестратора, которым пользуется агент из отдельного процесса.
+type Client struct {
+	client agentv1.AgentServiceClient
+	conn   *grpc.ClientConn
+}
+
+// NewClient подключается к сервису агентов оркестратора по адресу address.
+// token - общий токен агентов, который передается с каждым запросом.
+func NewClient(ctx context.Context, address, token string) (*Client, error) {
+	dialCtx, cancel := context.WithTimeout(ctx, defaultDialTimeout)
+	defer cancel()
+
+	conn, err := grpc.Dial(
+		address,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithPerRPCCredentials(agentToken(token)),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to agent service at %s: %w", address, err)
+	}
+
+	if !waitForConnection(dialCtx, conn) {
+		if err := conn.Close(); err != nil {
+			return nil, fmt.Errorf("failed to close connection: %w", err)
+		}
+		return nil, ErrConnectionTimeout
+	}
+
+	return &Client{
+		client: agentv1.NewAgentServiceClient(conn),
+		conn:   conn,
+	}, nil
+}
+
+// agentToken передает общий токен агентов в метаданных authorization.
+type agentToken string
+
+func (t agentToken) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(t)}, nil
+}
+
+// RequireTransportSecurity разрешает передавать токен без TLS: сервис агентов
+// слушает только внутренний интерфейс.
+func (t agentToken) RequireTransportSecurity() bool {
+	return false
+}
+
+func waitForConnection(ctx context.Context, conn *grpc.ClientConn) bool {
+	for {
+		if conn.GetState() == connectivity.Ready {
+			return true
+		}
+		if !conn.WaitForStateChange(ctx, conn.GetState()) {
+			return false
+		}
+	}
+}
+
+func (c *Client) RegisterRemote(ctx context.Context, agentID string, capacity int) (*agent.Agent, error) {
+	log := logger.ContextLogger(ctx, nil).With(
+		zap.String(fieldMethod, methodRegister),
+		zap.String(fieldAgentID, agentID))
+
+	resp, err := c.client.Register(ctx, &agentv1.RegisterRequest{
+		AgentId:  agentID,
+		Capacity: int32(capacity), //nolint:gosec
+	})
+	if err != nil {
+		log.Error("Failed to register agent", zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", msgFailedRegister, mapGRPCError(err))
+	}
+
+	return &agent.Agent{
+		ID:          resp.GetAgentId(),
+		Status:      agent.AgentStatusOnline,
+		MaxCapacity: int(resp.GetCapacity()),
+		StartedAt:   time.Now(),
+		Remote:      true,
+	}, nil
+}
+
+func (c *Client) FetchOperations(ctx context.Context, agentID string, limit int) ([]orchestrator.AgentTask, error) {
+	log := logger.ContextLogger(ctx, nil).With(
+		zap.String(fieldMethod, methodFetchOperations),
+		zap.String(fieldAgentID, agentID))
+
+	resp, err := c.client.FetchOperations(ctx, &agentv1.FetchOperationsRequest{
+		AgentId: agentID,
+		Limit:   int32(limit), //nolint:gosec
+	})
+	if err != nil {
+		log.Error("Failed to fetch operations", zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", msgFailedFetchOperations, mapGRPCError(err))
+	}
+
+	tasks := make([]orchestrator.AgentTask, 0, len(resp.GetTasks()))
+	for _, t := range resp.GetTasks() {
+		operationID, err := uuid.Parse(t.GetOperationId())
+		if err != nil {
+			return nil, fmt.Errorf("%w: operation ID %q", ErrInvalidResponse, t.GetOperationId())
+		}
+
+		tasks = append(tasks, orchestrator.AgentTask{
+			Operation: &orchestrator.Operation{
+				ID:            operationID,
+				OperationType: orchestrator.OperationType(t.GetOperationType()),
+				Operand1:      t.GetOperand1(),
+				Operand2:      t.GetOperand2(),
+				Status:        orchestrator.OperationStatusInProgress,
+				AgentID:       agentID,
+			},
+			Duration: time.Duration(t.GetOperationTimeMs()) * time.Millisecond,
+		})
+	}
+
+	return tasks, nil
+}
+
+func (c *Client) ReportResult(ctx context.Context, agentID string, operationID uuid.UUID, result, errorMessage string) error {
+	log := logger.ContextLogger(ctx, nil).With(
+		zap.String(fieldMethod, methodReportResult),
+		zap.String(fieldAgentID, agentID),
+		zap.String(fieldOperationID, operationID.String()))
+
+	if _, err := c.client.ReportResult(ctx, &agentv1.ReportResultRequest{
+		AgentId:     agentID,
+		OperationId: operationID.String(),
+		Result:      result,
+		Error:       errorMessage,
+	}); err != nil {
+		log.Error("Failed to report operation result", zap.Error(err))
+		return fmt.Errorf("%s: %w", msgFailedReportResult, mapGRPCError(err))
+	}
+
+	return nil
+}
+
+func (c *Client) Close() error {
+	if c.conn != nil {
+		if err := c.conn.Close(); err != nil {
+			return fmt.Errorf("failed to close gRPC connection: %w", err)
+		}
+	}
+	return nil
+}
+
+// mapGRPCError восстанавливает доменную ошибку по коду из деталей ErrorInfo ответа сервера.
+// Для ответов без деталей ошибка выбирается по коду статуса.
+func mapGRPCError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if domainErr := middleware.DomainError(err); domainErr != nil {
+		return domainErr
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	switch st.Code() {
+	case codes.InvalidArgument:
+		return fmt.Errorf("%w: %s", domainerrors.ErrInvalidArgs, st.Message())
+	case codes.Unauthenticated:
+		return fmt.Errorf("%w: %s", domainerrors.ErrInvalidToken, st.Message())
+	case codes.Internal:
+		return ErrInternalServerError
+	default:
+		return err
+	}
+}
@@ -6,11 +6,16 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/grpc/middleware"
+	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/auth"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/system"
 	authAPI "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/api/auth"
 	authv1 "github.com/flexer2006/y.lms-final-task-calc-go/pkg/api/proto/v1/auth"
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/servertiming"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -25,6 +30,12 @@ const (
 	methodValidateToken = "ValidateToken"
 	methodRefreshToken  = "RefreshToken"
 	methodLogout        = "Logout"
+	methodGetUser       = "GetUser"
+	methodDeleteUser    = "DeleteUser"
+	methodListSessions  = "ListSessions"
+	methodRevokeSession = "RevokeSession"
+	methodRevokeAll     = "RevokeAllSessions"
+	methodGetStats      = "GetStats"
 
 	fieldMethod = "method"
 	fieldLogin  = "login"
@@ -33,6 +44,12 @@ const (
 	errMsgRegister      = "failed to register user"
 	errMsgLogin         = "failed to login"
 	errMsgValidateToken = "failed to validate token"
+	errMsgGetUser       = "failed to get user"
+	errMsgDeleteUser    = "failed to delete user"
+	errMsgListSessions  = "failed to list sessions"
+	errMsgRevokeSession = "failed to revoke session"
+	errMsgRevokeAll     = "failed to revoke sessions"
+	errMsgGetStats      = "failed to get auth stats"
 
 	defaultDialTimeout = 5 * time.Second
 	defaultTokenExpiry = 15 * time.Minute
@@ -40,17 +57,12 @@ const (
 
 var (
 	ErrConnectionTimeout = errors.New("connection timeout: failed to connect to auth service")
+	ErrNotReady          = errors.New("auth service connection is not ready")
 	ErrInvalidResponse   = errors.New("invalid response from auth service")
 	ErrInvalidUserID     = errors.New("invalid user ID format")
 	ErrNotImplemented    = errors.New("method not implemented")
 	ErrInvalidToken      = errors.New("invalid token")
 	ErrEmptyUserID       = errors.New("empty user ID") // Added static error instead of dynamic one
-
-	errUserExists       = errors.New("user already exists")
-	errUserNotFound     = errors.New("user not found")
-	errInvalidArgument  = errors.New("invalid argument")
-	errAuthFailed       = errors.New("authentication failed")
-	errPermissionDenied = errors.New("permission denied")
 )
 
 type Client struct {
@@ -58,16 +70,20 @@ type Client struct {
 	conn   *grpc.ClientConn
 }
 
-func NewAuthUseCase(ctx context.Context, address string) (authAPI.UseCaseUser, error) {
+// NewAuthUseCase подключается к сервису авторизации по адресу address. Параметры opts
+// дополняют стандартные, например параметрами из dial.Options.
+func NewAuthUseCase(ctx context.Context, address string, opts ...grpc.DialOption) (authAPI.UseCaseUser, error) {
 	dialCtx, cancel := context.WithTimeout(ctx, defaultDialTimeout)
 	defer cancel()
 
 	// Updated to use recommended approach
-	opts := []grpc.DialOption{
+	dialOpts := append([]grpc.DialOption{
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	}
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		grpc.WithChainUnaryInterceptor(servertiming.UnaryClientInterceptor()),
+	}, opts...)
 
-	conn, err := grpc.Dial(address, opts...)
+	conn, err := grpc.Dial(address, dialOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to auth service at %s: %w", address, err)
 	}
@@ -128,9 +144,12 @@ func (c *Client) Login(ctx context.Context, login, password string) (*auth.Token
 		zap.String(fieldLogin, login),
 	)
 
+	clientInfo := auth.ClientInfoFromContext(ctx)
 	resp, err := c.client.Login(ctx, &authv1.LoginRequest{
-		Login:    login,
-		Password: password,
+		Login:     login,
+		Password:  password,
+		UserAgent: clientInfo.UserAgent,
+		Ip:        clientInfo.IP,
 	})
 	if err != nil {
 		log.Error("Failed to login user", zap.Error(err))
@@ -187,6 +206,143 @@ func (c *Client) ValidateToken(ctx context.Context, token string) (uuid.UUID, er
 	return userID, nil
 }
 
+func (c *Client) GetUser(ctx context.Context, userID uuid.UUID) (*auth.User, error) {
+	log := logger.ContextLogger(ctx, nil).With(
+		zap.String(fieldMethod, methodGetUser),
+		zap.String(fieldUserID, userID.String()),
+	)
+
+	resp, err := c.client.GetUser(ctx, &authv1.GetUserRequest{
+		UserId: userID.String(),
+	})
+	if err != nil {
+		log.Error("Failed to get user", zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", errMsgGetUser, mapGRPCError(err))
+	}
+
+	id, err := uuid.Parse(resp.GetUserId())
+	if err != nil {
+		log.Error("Invalid user ID received", zap.String(fieldUserID, resp.GetUserId()), zap.Error(err))
+		return nil, ErrInvalidResponse
+	}
+
+	return &auth.User{
+		ID:        id,
+		Login:     resp.GetLogin(),
+		CreatedAt: resp.GetCreatedAt().AsTime(),
+		UpdatedAt: resp.GetUpdatedAt().AsTime(),
+	}, nil
+}
+
+func (c *Client) DeleteUser(ctx context.Context, userID uuid.UUID, password string) error {
+	log := logger.ContextLogger(ctx, nil).With(
+		zap.String(fieldMethod, methodDeleteUser),
+		zap.String(fieldUserID, userID.String()),
+	)
+
+	_, err := c.client.DeleteUser(ctx, &authv1.DeleteUserRequest{
+		UserId:   userID.String(),
+		Password: password,
+	})
+	if err != nil {
+		log.Error("Failed to delete user", zap.Error(err))
+		return fmt.Errorf("%s: %w", errMsgDeleteUser, mapGRPCError(err))
+	}
+
+	return nil
+}
+
+func (c *Client) ListSessions(ctx context.Context, userID uuid.UUID) ([]*auth.Session, error) {
+	log := logger.ContextLogger(ctx, nil).With(
+		zap.String(fieldMethod, methodListSessions),
+		zap.String(fieldUserID, userID.String()),
+	)
+
+	resp, err := c.client.ListSessions(ctx, &authv1.ListSessionsRequest{
+		UserId: userID.String(),
+	})
+	if err != nil {
+		log.Error("Failed to list sessions", zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", errMsgListSessions, mapGRPCError(err))
+	}
+
+	sessions := make([]*auth.Session, 0, len(resp.GetSessions()))
+	for _, s := range resp.GetSessions() {
+		id, err := uuid.Parse(s.GetId())
+		if err != nil {
+			log.Error("Invalid session ID received", zap.String("session_id", s.GetId()), zap.Error(err))
+			return nil, ErrInvalidResponse
+		}
+		sessions = append(sessions, &auth.Session{
+			ID:        id,
+			UserAgent: s.GetUserAgent(),
+			IP:        s.GetIp(),
+			CreatedAt: s.GetCreatedAt().AsTime(),
+			ExpiresAt: s.GetExpiresAt().AsTime(),
+		})
+	}
+
+	return sessions, nil
+}
+
+func (c *Client) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	log := logger.ContextLogger(ctx, nil).With(
+		zap.String(fieldMethod, methodRevokeSession),
+		zap.String(fieldUserID, userID.String()),
+		zap.String("session_id", sessionID.String()),
+	)
+
+	_, err := c.client.RevokeSession(ctx, &authv1.RevokeSessionRequest{
+		UserId:    userID.String(),
+		SessionId: sessionID.String(),
+	})
+	if err != nil {
+		log.Error("Failed to revoke session", zap.Error(err))
+		return fmt.Errorf("%s: %w", errMsgRevokeSession, mapGRPCError(err))
+	}
+
+	return nil
+}
+
+func (c *Client) RevokeAllSessions(ctx context.Context, userID uuid.UUID) error {
+	log := logger.ContextLogger(ctx, nil).With(
+		zap.String(fieldMethod, methodRevokeAll),
+		zap.String(fieldUserID, userID.String()),
+	)
+
+	if _, err := c.client.RevokeAllSessions(ctx, &authv1.RevokeAllSessionsRequest{
+		UserId: userID.String(),
+	}); err != nil {
+		log.Error("Failed to revoke all sessions", zap.Error(err))
+		return fmt.Errorf("%s: %w", errMsgRevokeAll, mapGRPCError(err))
+	}
+
+	return nil
+}
+
+func (c *Client) GetStats(ctx context.Context) (*system.AuthStats, error) {
+	log := logger.ContextLogger(ctx, nil).With(zap.String(fieldMethod, methodGetStats))
+
+	resp, err := c.client.GetStats(ctx, &authv1.GetStatsRequest{})
+	if err != nil {
+		log.Error("Failed to get auth stats", zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", errMsgGetStats, mapGRPCError(err))
+	}
+
+	pool := resp.GetDbPool()
+	return &system.AuthStats{
+		TotalUsers: int(resp.GetTotalUsers()),
+		DBPool: system.DBPoolStats{
+			TotalConns:        pool.GetTotalConns(),
+			IdleConns:         pool.GetIdleConns(),
+			AcquiredConns:     pool.GetAcquiredConns(),
+			MaxConns:          pool.GetMaxConns(),
+			AcquireCount:      pool.GetAcquireCount(),
+			AcquireDurationMs: pool.GetAcquireDurationMs(),
+		},
+	}, nil
+}
+
 func parseUserID(id string) (uuid.UUID, error) {
 	if id == "" {
 		return uuid.Nil, ErrEmptyUserID // Using static error instead of dynamic one
@@ -212,6 +368,18 @@ func (c *Client) Logout(ctx context.Context, token string) error {
 	return ErrNotImplemented
 }
 
+// Ping проверяет, что соединение с сервисом авторизации установлено, при необходимости
+// переподключаясь. Ожидание ограничено контекстом.
+func (c *Client) Ping(ctx context.Context) error {
+	if c.conn.GetState() == connectivity.Idle {
+		c.conn.Connect()
+	}
+	if !waitForConnection(ctx, c.conn) {
+		return fmt.Errorf("%w: %s", ErrNotReady, c.conn.GetState())
+	}
+	return nil
+}
+
 func (c *Client) Close() error {
 	if c.conn != nil {
 		// Wrapping the external error
@@ -222,11 +390,17 @@ func (c *Client) Close() error {
 	return nil
 }
 
+// mapGRPCError восстанавливает доменную ошибку по коду из деталей ErrorInfo ответа сервера.
+// Для ответов без деталей ошибка выбирается по коду статуса.
 func mapGRPCError(err error) error {
 	if err == nil {
 		return nil
 	}
 
+	if domainErr := middleware.DomainError(err); domainErr != nil {
+		return domainErr
+	}
+
 	st, ok := status.FromError(err)
 	if !ok {
 		return err
@@ -234,24 +408,19 @@ func mapGRPCError(err error) error {
 
 	switch st.Code() {
 	case codes.AlreadyExists:
-		return errUserExists
+		return domainerrors.ErrUserAlreadyExists
 	case codes.NotFound:
-		return errUserNotFound
+		return domainerrors.ErrUserNotFound
 	case codes.InvalidArgument:
-		if st.Message() != "" {
-			return fmt.Errorf("%w: %s", errInvalidArgument, st.Message())
-		}
-		return errInvalidArgument
+		return fmt.Errorf("%w: %s", domainerrors.ErrInvalidArgs, st.Message())
 	case codes.Unauthenticated:
-		if st.Message() != "" {
-			return fmt.Errorf("%w: %s", errAuthFailed, st.Message())
-		}
-		return errAuthFailed
+		return fmt.Errorf("%w: %s", domainerrors.ErrInvalidToken, st.Message())
 	case codes.PermissionDenied:
-		if st.Message() != "" {
-			return fmt.Errorf("%w: %s", errPermissionDenied, st.Message())
-		}
-		return errPermissionDenied
+		return fmt.Errorf("%w: %s", domainerrors.ErrUnauthorizedAccess, st.Message())
+	case codes.ResourceExhausted:
+		return domainerrors.ErrTooManyAttempts
+	case codes.Internal:
+		return domainerrors.ErrInternalServerError
 	default:
 		return err
 	}
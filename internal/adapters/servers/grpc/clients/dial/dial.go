@@ -0,0 +1,114 @@
+// Package dial содержит общие настройки соединений шлюза с gRPC сервисами:
+// keepalive, задержки переподключения, восстановление соединения перед вызовом
+// и повторы идемпотентных вызовов.
+package dial
+
+import (
+	"context"
+	"time"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/setup/server"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/retry"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
+)
+
+// Рост задержки между повторами вызовов и ее случайный разброс.
+const (
+	retryMultiplier = 2
+	retryJitter     = 0.2
+)
+
+// Options возвращает параметры соединения по конфигурации cfg. Нулевые значения
+// оставляют настройки gRPC по умолчанию. Ошибка возвращается для неизвестных кодов
+// в cfg.RetryCodes.
+func Options(cfg server.GRPCClientConfig) ([]grpc.DialOption, error) {
+	retryable, err := ParseCodes(cfg.RetryCodes)
+	if err != nil {
+		return nil, err
+	}
+	retryPolicy := retry.Policy{
+		MaxAttempts: cfg.RetryMaxAttempts,
+		BaseDelay:   cfg.RetryBaseDelay,
+		Multiplier:  retryMultiplier,
+		MaxDelay:    cfg.RetryMaxDelay,
+		Jitter:      retryJitter,
+	}
+
+	connectParams := grpc.ConnectParams{
+		Backoff:           backoff.DefaultConfig,
+		MinConnectTimeout: cfg.MinConnectTimeout,
+	}
+	if cfg.BackoffBaseDelay > 0 {
+		connectParams.Backoff.BaseDelay = cfg.BackoffBaseDelay
+	}
+	if cfg.BackoffMaxDelay > 0 {
+		connectParams.Backoff.MaxDelay = cfg.BackoffMaxDelay
+	}
+
+	opts := []grpc.DialOption{
+		grpc.WithConnectParams(connectParams),
+		grpc.WithChainUnaryInterceptor(
+			UnaryClientRetry(retryPolicy, retryable, Idempotent),
+			UnaryClientReconnect(cfg.ReconnectWait),
+		),
+		grpc.WithChainStreamInterceptor(StreamClientReconnect(cfg.ReconnectWait)),
+	}
+	if cfg.KeepaliveTime > 0 {
+		opts = append(opts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                cfg.KeepaliveTime,
+			Timeout:             cfg.KeepaliveTimeout,
+			PermitWithoutStream: true,
+		}))
+	}
+	return opts, nil
+}
+
+// UnaryClientReconnect восстанавливает соединение, которое после обрыва ждет следующей
+// попытки подключения: перед вызовом задержка сбрасывается, и вызов ждет готовности
+// соединения не дольше wait. После ответа Unavailable задержка тоже сбрасывается,
+// чтобы следующий вызов не ждал ее окончания.
+func UnaryClientReconnect(wait time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		reconnect(ctx, cc, wait)
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if status.Code(err) == codes.Unavailable {
+			cc.ResetConnectBackoff()
+		}
+		return err
+	}
+}
+
+// StreamClientReconnect - вариант UnaryClientReconnect для потоковых вызовов.
+func StreamClientReconnect(wait time.Duration) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		reconnect(ctx, cc, wait)
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if status.Code(err) == codes.Unavailable {
+			cc.ResetConnectBackoff()
+		}
+		return stream, err
+	}
+}
+
+// reconnect сбрасывает задержку переподключения соединения в состоянии сбоя и ждет,
+// пока оно станет готовым, не дольше wait. Неположительный wait отключает ожидание.
+func reconnect(ctx context.Context, cc *grpc.ClientConn, wait time.Duration) {
+	if wait <= 0 || cc.GetState() != connectivity.TransientFailure {
+		return
+	}
+
+	cc.ResetConnectBackoff()
+
+	waitCtx, cancel := context.WithTimeout(ctx, wait)
+	defer cancel()
+	for state := cc.GetState(); state != connectivity.Ready; state = cc.GetState() {
+		if !cc.WaitForStateChange(waitCtx, state) {
+			return
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package dial_test
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	authclient "github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/grpc/clients/auth"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/grpc/clients/dial"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/setup/server"
+	authv1 "github.com/flexer2006/y.lms-final-task-calc-go/pkg/api/proto/v1/auth"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type authServer struct {
+	authv1.UnimplementedAuthServiceServer
+	userID string
+}
+
+func (s *authServer) ValidateToken(context.Context, *authv1.ValidateTokenRequest) (*authv1.ValidateTokenResponse, error) {
+	return &authv1.ValidateTokenResponse{Valid: true, UserId: s.userID}, nil
+}
+
+// droppingServer - сервер авторизации в памяти, который можно остановить, оборвав
+// соединения, и запустить снова на новом слушателе.
+type droppingServer struct {
+	t      *testing.T
+	userID string
+
+	mu     sync.Mutex
+	lis    *bufconn.Listener
+	server *grpc.Server
+}
+
+func (s *droppingServer) start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lis = bufconn.Listen(1 << 20)
+	s.server = grpc.NewServer()
+	authv1.RegisterAuthServiceServer(s.server, &authServer{userID: s.userID})
+	go func(server *grpc.Server, lis net.Listener) { _ = server.Serve(lis) }(s.server, s.lis)
+	s.t.Cleanup(s.server.Stop)
+}
+
+func (s *droppingServer) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.server.Stop()
+}
+
+func (s *droppingServer) dial(ctx context.Context, _ string) (net.Conn, error) {
+	s.mu.Lock()
+	lis := s.lis
+	s.mu.Unlock()
+	return lis.DialContext(ctx)
+}
+
+func TestClientRecoversAfterDrop(t *testing.T) {
+	ctx := logger.WithLogger(context.Background(), logger.New(zapcore.NewNopCore()))
+	userID := uuid.New()
+
+	srv := &droppingServer{t: t, userID: userID.String()}
+	srv.start()
+
+	cfg := server.GRPCClientConfig{
+		KeepaliveTime:     10 * time.Second,
+		KeepaliveTimeout:  time.Second,
+		BackoffBaseDelay:  time.Minute,
+		BackoffMaxDelay:   time.Minute,
+		MinConnectTimeout: time.Second,
+		ReconnectWait:     5 * time.Second,
+	}
+	opts, err := dial.Options(cfg)
+	require.NoError(t, err)
+	opts = append(opts, grpc.WithContextDialer(srv.dial))
+
+	client, err := authclient.NewAuthUseCase(ctx, "bufnet", opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	got, err := client.ValidateToken(ctx, "token")
+	require.NoError(t, err)
+	assert.Equal(t, userID, got)
+
+	srv.stop()
+	_, err = client.ValidateToken(ctx, "token")
+	require.Error(t, err, "service is down")
+
+	// Задержка переподключения в минуту не должна помешать первому вызову после запуска
+	srv.start()
+	got, err = client.ValidateToken(ctx, "token")
+	require.NoError(t, err)
+	assert.Equal(t, userID, got)
+}
@@ -0,0 +1,88 @@
+package dial
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	authv1 "github.com/flexer2006/y.lms-final-task-calc-go/pkg/api/proto/v1/auth"
+	orchv1 "github.com/flexer2006/y.lms-final-task-calc-go/pkg/api/proto/v1/orchestrator"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/retry"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// readOnlyMethods - методы, которые ничего не изменяют и поэтому безопасно повторяются.
+var readOnlyMethods = map[string]struct{}{
+	authv1.AuthService_ValidateToken_FullMethodName: {},
+	authv1.AuthService_GetUser_FullMethodName:       {},
+	authv1.AuthService_ListSessions_FullMethodName:  {},
+	authv1.AuthService_GetStats_FullMethodName:      {},
+
+	orchv1.OrchestratorService_GetCalculation_FullMethodName:      {},
+	orchv1.OrchestratorService_AdminGetCalculation_FullMethodName: {},
+	orchv1.OrchestratorService_GetOperation_FullMethodName:        {},
+	orchv1.OrchestratorService_ListCalculations_FullMethodName:    {},
+	orchv1.OrchestratorService_GetResultStats_FullMethodName:      {},
+	orchv1.OrchestratorService_CompareExpressions_FullMethodName:  {},
+	orchv1.OrchestratorService_DiffCalculations_FullMethodName:    {},
+	orchv1.OrchestratorService_PreviewExpression_FullMethodName:   {},
+	orchv1.OrchestratorService_ValidateExpression_FullMethodName:  {},
+	orchv1.OrchestratorService_GetPoolStats_FullMethodName:        {},
+	orchv1.OrchestratorService_GetTimingProfile_FullMethodName:    {},
+	orchv1.OrchestratorService_GetSystemStats_FullMethodName:      {},
+	orchv1.OrchestratorService_GetLogLevel_FullMethodName:         {},
+}
+
+// Idempotent сообщает, что вызов method с запросом req можно повторить: метод только читает
+// данные или запрос несет ключ идемпотентности, по которому сервер не выполнит его дважды.
+func Idempotent(method string, req any) bool {
+	if _, ok := readOnlyMethods[method]; ok {
+		return true
+	}
+	keyed, ok := req.(interface{ GetIdempotencyKey() string })
+	return ok && keyed.GetIdempotencyKey() != ""
+}
+
+// ParseCodes разбирает имена кодов gRPC вида UNAVAILABLE.
+func ParseCodes(names []string) ([]codes.Code, error) {
+	parsed := make([]codes.Code, 0, len(names))
+	for _, name := range names {
+		var code codes.Code
+		if err := code.UnmarshalJSON([]byte(strconv.Quote(name))); err != nil {
+			return nil, fmt.Errorf("parse gRPC code %q: %w", name, err)
+		}
+		parsed = append(parsed, code)
+	}
+	return parsed, nil
+}
+
+// UnaryClientRetry повторяет вызовы, для которых idempotent возвращает true, пока они
+// завершаются с одним из кодов retryable, но не больше policy.MaxAttempts попыток всего.
+// Между попытками выдерживается задержка policy; завершение контекста прекращает повторы,
+// и возвращается ошибка последней попытки.
+func UnaryClientRetry(policy retry.Policy, retryable []codes.Code, idempotent func(method string, req any) bool) grpc.UnaryClientInterceptor {
+	policy = policy.Normalize()
+
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if err == nil || policy.MaxAttempts < 2 || !idempotent(method, req) {
+			return err
+		}
+
+		for attempt := 1; attempt < policy.MaxAttempts; attempt++ {
+			if !slices.Contains(retryable, status.Code(err)) {
+				return err
+			}
+			if policy.Wait(ctx, attempt) != nil {
+				return err
+			}
+			if err = invoker(ctx, method, req, reply, cc, opts...); err == nil {
+				return nil
+			}
+		}
+		return err
+	}
+}
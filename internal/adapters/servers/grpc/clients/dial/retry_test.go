@@ -0,0 +1,165 @@
+package dial_test
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/grpc/clients/dial"
+	authv1 "github.com/flexer2006/y.lms-final-task-calc-go/pkg/api/proto/v1/auth"
+	orchv1 "github.com/flexer2006/y.lms-final-task-calc-go/pkg/api/proto/v1/orchestrator"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// flakyServer отвечает ошибкой failCode на первые failures вызовов каждого метода.
+type flakyServer struct {
+	authv1.UnimplementedAuthServiceServer
+	orchv1.UnimplementedOrchestratorServiceServer
+
+	failures int32
+	failCode codes.Code
+
+	validateCalls  atomic.Int32
+	registerCalls  atomic.Int32
+	calculateCalls atomic.Int32
+}
+
+func (s *flakyServer) fail(calls *atomic.Int32) error {
+	if calls.Add(1) <= s.failures {
+		return status.Error(s.failCode, "temporarily unavailable")
+	}
+	return nil
+}
+
+func (s *flakyServer) ValidateToken(context.Context, *authv1.ValidateTokenRequest) (*authv1.ValidateTokenResponse, error) {
+	if err := s.fail(&s.validateCalls); err != nil {
+		return nil, err
+	}
+	return &authv1.ValidateTokenResponse{Valid: true}, nil
+}
+
+func (s *flakyServer) Register(context.Context, *authv1.RegisterRequest) (*authv1.RegisterResponse, error) {
+	if err := s.fail(&s.registerCalls); err != nil {
+		return nil, err
+	}
+	return &authv1.RegisterResponse{}, nil
+}
+
+func (s *flakyServer) Calculate(context.Context, *orchv1.CalculateRequest) (*orchv1.CalculateResponse, error) {
+	if err := s.fail(&s.calculateCalls); err != nil {
+		return nil, err
+	}
+	return &orchv1.CalculateResponse{}, nil
+}
+
+func newRetryConn(t *testing.T, srv *flakyServer, maxAttempts int) *grpc.ClientConn {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	authv1.RegisterAuthServiceServer(server, srv)
+	orchv1.RegisterOrchestratorServiceServer(server, srv)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	policy := retry.Policy{MaxAttempts: maxAttempts, BaseDelay: time.Millisecond, Multiplier: 2}
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithChainUnaryInterceptor(dial.UnaryClientRetry(policy, []codes.Code{codes.Unavailable}, dial.Idempotent)),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+func TestUnaryClientRetry(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Read-only call succeeds after transient failures", func(t *testing.T) {
+		srv := &flakyServer{failures: 2, failCode: codes.Unavailable}
+		client := authv1.NewAuthServiceClient(newRetryConn(t, srv, 3))
+
+		resp, err := client.ValidateToken(ctx, &authv1.ValidateTokenRequest{Token: "token"})
+		require.NoError(t, err)
+		assert.True(t, resp.GetValid())
+		assert.Equal(t, int32(3), srv.validateCalls.Load())
+	})
+
+	t.Run("Attempts are limited", func(t *testing.T) {
+		srv := &flakyServer{failures: 5, failCode: codes.Unavailable}
+		client := authv1.NewAuthServiceClient(newRetryConn(t, srv, 3))
+
+		_, err := client.ValidateToken(ctx, &authv1.ValidateTokenRequest{Token: "token"})
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+		assert.Equal(t, int32(3), srv.validateCalls.Load())
+	})
+
+	t.Run("Other codes are not retried", func(t *testing.T) {
+		srv := &flakyServer{failures: 1, failCode: codes.Internal}
+		client := authv1.NewAuthServiceClient(newRetryConn(t, srv, 3))
+
+		_, err := client.ValidateToken(ctx, &authv1.ValidateTokenRequest{Token: "token"})
+		assert.Equal(t, codes.Internal, status.Code(err))
+		assert.Equal(t, int32(1), srv.validateCalls.Load())
+	})
+
+	t.Run("Mutating call is not retried", func(t *testing.T) {
+		srv := &flakyServer{failures: 1, failCode: codes.Unavailable}
+		client := authv1.NewAuthServiceClient(newRetryConn(t, srv, 3))
+
+		_, err := client.Register(ctx, &authv1.RegisterRequest{Login: "user", Password: "password"})
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+		assert.Equal(t, int32(1), srv.registerCalls.Load())
+	})
+
+	t.Run("Mutating call with idempotency key is retried", func(t *testing.T) {
+		srv := &flakyServer{failures: 2, failCode: codes.Unavailable}
+		client := orchv1.NewOrchestratorServiceClient(newRetryConn(t, srv, 3))
+
+		_, err := client.Calculate(ctx, &orchv1.CalculateRequest{Expression: "2+2"})
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+		assert.Equal(t, int32(1), srv.calculateCalls.Load())
+
+		_, err = client.Calculate(ctx, &orchv1.CalculateRequest{Expression: "2+2", IdempotencyKey: "key"})
+		require.NoError(t, err)
+		assert.Equal(t, int32(3), srv.calculateCalls.Load())
+	})
+
+	t.Run("Canceled context stops retries", func(t *testing.T) {
+		policy := retry.Policy{MaxAttempts: 5, BaseDelay: time.Hour}
+		interceptor := dial.UnaryClientRetry(policy, []codes.Code{codes.Unavailable}, dial.Idempotent)
+
+		cancelCtx, cancel := context.WithCancel(ctx)
+		calls := 0
+		invoker := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+			calls++
+			cancel()
+			return status.Error(codes.Unavailable, "temporarily unavailable")
+		}
+
+		err := interceptor(cancelCtx, authv1.AuthService_ValidateToken_FullMethodName,
+			&authv1.ValidateTokenRequest{}, &authv1.ValidateTokenResponse{}, nil, invoker)
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestParseCodes(t *testing.T) {
+	parsed, err := dial.ParseCodes([]string{"UNAVAILABLE", "DEADLINE_EXCEEDED"})
+	require.NoError(t, err)
+	assert.Equal(t, []codes.Code{codes.Unavailable, codes.DeadlineExceeded}, parsed)
+
+	_, err = dial.ParseCodes([]string{"unavailable"})
+	assert.Error(t, err)
+}
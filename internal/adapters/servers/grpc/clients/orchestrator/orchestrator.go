@@ -4,55 +4,86 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"time"
 
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/grpc/middleware"
+	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/agent"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/system"
 	orchAPI "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/api/orchestrator"
 	orchv1 "github.com/flexer2006/y.lms-final-task-calc-go/pkg/api/proto/v1/orchestrator"
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/servertiming"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials/insecure"
-	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
-	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 const (
-	methodCalculate        = "CalculateExpression"
-	methodGetCalculation   = "GetCalculation"
-	methodListCalculations = "ListCalculations"
+	methodCalculate         = "CalculateExpression"
+	methodGetCalculation    = "GetCalculation"
+	methodAdminGetCalc      = "AdminGetCalculation"
+	methodGetOperation      = "GetOperation"
+	methodListCalculations  = "ListCalculations"
+	methodGetResultStats    = "GetResultStats"
+	methodCancelCalculation = "CancelCalculation"
+	methodStreamCalculation = "StreamCalculation"
+	methodDeleteCalculation = "DeleteCalculation"
+	methodCompare           = "CompareExpressions"
+	methodDiff              = "DiffCalculations"
+	methodPreview           = "PreviewExpression"
+	methodValidate          = "ValidateExpression"
+	methodGetPoolStats      = "GetPoolStats"
+	methodGetSystemStats    = "GetSystemStats"
+	methodGetTimingProfile  = "GetTimingProfile"
+	methodSetAgentCapacity  = "SetAgentCapacity"
 
 	fieldMethod        = "method"
 	fieldUserID        = "user_id"
 	fieldCalculationID = "calculation_id"
+	fieldOperationID   = "operation_id"
 	fieldExpression    = "expression"
 	fieldStatus        = "status"
 	fieldCount         = "count"
-
-	metadataUserID = "user_id"
-
-	msgFailedCalculate        = "failed to calculate expression"
-	msgFailedGetCalculation   = "failed to get calculation"
-	msgFailedListCalculations = "failed to list calculations"
-	msgInvalidCalculationID   = "invalid calculation ID"
-	msgInvalidUserID          = "invalid user ID"
-	msgEmptyExpression        = "expression cannot be empty"
+	fieldSource        = "source"
+
+	msgFailedCalculate         = "failed to calculate expression"
+	msgFailedGetCalculation    = "failed to get calculation"
+	msgFailedGetOperation      = "failed to get operation"
+	msgFailedListCalculations  = "failed to list calculations"
+	msgFailedGetResultStats    = "failed to get calculation result stats"
+	msgFailedCancelCalculation = "failed to cancel calculation"
+	msgFailedStreamCalculation = "failed to stream calculation"
+	msgFailedDeleteCalculation = "failed to delete calculation"
+	msgFailedCompare           = "failed to compare expressions"
+	msgFailedDiff              = "failed to diff calculations"
+	msgFailedPreview           = "failed to preview expression"
+	msgFailedValidate          = "failed to validate expression"
+	msgFailedGetPoolStats      = "failed to get agent pool stats"
+	msgFailedGetSystemStats    = "failed to get system stats"
+	msgFailedGetTimingProfile  = "failed to get agent timing profile"
+	msgFailedSetAgentCapacity  = "failed to set agent capacity"
+	msgInvalidCalculationID    = "invalid calculation ID"
+	msgInvalidUserID           = "invalid user ID"
 
 	defaultDialTimeout = 5 * time.Second
 )
 
 var (
 	ErrConnectionTimeout    = errors.New("connection timeout: failed to connect to orchestrator service")
+	ErrNotReady             = errors.New("orchestrator service connection is not ready")
 	ErrInvalidResponse      = errors.New("invalid response from orchestrator service")
 	ErrInvalidCalculationID = errors.New("invalid calculation ID format")
 	ErrInvalidUserID        = errors.New("invalid user ID format")
 	ErrCalculationNotFound  = errors.New("calculation not found")
 	ErrUnauthorizedAccess   = errors.New("unauthorized access to calculation")
-	ErrInvalidExpression    = errors.New("invalid expression")
 	ErrInternalServerError  = errors.New("internal server error")
 	ErrInvalidArgument      = errors.New("invalid argument") // Add this new error
 )
@@ -62,15 +93,20 @@ type Client struct {
 	conn   *grpc.ClientConn
 }
 
-func NewCalculationUseCase(ctx context.Context, address string) (orchAPI.UseCaseCalculation, error) {
+// NewCalculationUseCase подключается к сервису оркестрации по адресу address. Параметры opts
+// дополняют стандартные, например параметрами из dial.Options.
+func NewCalculationUseCase(ctx context.Context, address string, opts ...grpc.DialOption) (orchAPI.UseCaseCalculation, error) {
 	dialCtx, cancel := context.WithTimeout(ctx, defaultDialTimeout)
 	defer cancel()
 
-	// NewClient takes a target string followed by options (not a context)
-	conn, err := grpc.Dial(
-		address,
+	dialOpts := append([]grpc.DialOption{
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		grpc.WithChainUnaryInterceptor(servertiming.UnaryClientInterceptor()),
+	}, opts...)
+
+	// NewClient takes a target string followed by options (not a context)
+	conn, err := grpc.Dial(address, dialOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to orchestrator service at %s: %w", address, err)
 	}
@@ -99,17 +135,26 @@ func waitForConnection(ctx context.Context, conn *grpc.ClientConn) bool {
 	}
 }
 
-func (c *Client) CalculateExpression(ctx context.Context, userID uuid.UUID, expression string) (*orchestrator.Calculation, error) {
+func (c *Client) CalculateExpression(ctx context.Context, userID uuid.UUID, expression string, source orchestrator.CalculationSource) (*orchestrator.Calculation, error) {
 	log := logger.ContextLogger(ctx, nil).With(
 		zap.String(fieldMethod, methodCalculate),
 		zap.String(fieldUserID, userID.String()),
 		zap.String(fieldExpression, expression),
+		zap.String(fieldSource, string(source)),
 	)
 
-	ctx = metadata.AppendToOutgoingContext(ctx, metadataUserID, userID.String())
+	// Приоритет проверяется до преобразования в int32, чтобы большое значение не превратилось в допустимое
+	priority := orchestrator.PriorityFromContext(ctx)
+	if !orchestrator.ValidPriority(priority) {
+		return nil, fmt.Errorf("%w: %d", domainerrors.ErrInvalidPriority, priority)
+	}
 
 	resp, err := c.client.Calculate(ctx, &orchv1.CalculateRequest{
-		Expression: expression,
+		Expression:     expression,
+		Source:         string(source),
+		ClientVersion:  orchestrator.ClientVersionFromContext(ctx),
+		Priority:       int32(priority), //nolint:gosec
+		IdempotencyKey: orchestrator.IdempotencyKeyFromContext(ctx),
 	})
 	if err != nil {
 		log.Error("Failed to calculate expression", zap.Error(err))
@@ -127,12 +172,17 @@ func (c *Client) CalculateExpression(ctx context.Context, userID uuid.UUID, expr
 	status := mapProtoStatusToDomain(resp.GetStatus())
 
 	calculation := &orchestrator.Calculation{
-		ID:           calculationID,
-		UserID:       userID,
-		Expression:   expression,
-		Result:       resp.GetResult(),
-		Status:       status,
-		ErrorMessage: resp.GetErrorMessage(),
+		ID:                calculationID,
+		UserID:            userID,
+		Expression:        expression,
+		Result:            resp.GetResult(),
+		DisplayResult:     resp.GetDisplayResult(),
+		Warnings:          resp.GetWarnings(),
+		Status:            status,
+		ErrorMessage:      resp.GetErrorMessage(),
+		Source:            orchestrator.CalculationSource(resp.GetSource()),
+		EstimatedDuration: time.Duration(resp.GetEstimatedDurationMs()) * time.Millisecond,
+		LikelyAgents:      resp.GetLikelyAgents(),
 	}
 
 	log.Info("Expression calculation initiated successfully",
@@ -149,8 +199,6 @@ func (c *Client) GetCalculation(ctx context.Context, calculationID uuid.UUID, us
 		zap.String(fieldUserID, userID.String()),
 	)
 
-	ctx = metadata.AppendToOutgoingContext(ctx, metadataUserID, userID.String())
-
 	resp, err := c.client.GetCalculation(ctx, &orchv1.GetCalculationRequest{
 		Id: calculationID.String(),
 	})
@@ -159,6 +207,78 @@ func (c *Client) GetCalculation(ctx context.Context, calculationID uuid.UUID, us
 		return nil, fmt.Errorf("%s: %w", msgFailedGetCalculation, mapGRPCError(err))
 	}
 
+	calculation, err := mapCalculationResponse(log, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debug("Calculation retrieved successfully", zap.String(fieldStatus, string(calculation.Status)))
+	return calculation, nil
+}
+
+// AdminGetCalculation возвращает вычисление любого пользователя вместе с версией клиента.
+func (c *Client) AdminGetCalculation(ctx context.Context, calculationID uuid.UUID) (*orchestrator.Calculation, error) {
+	log := logger.ContextLogger(ctx, nil).With(
+		zap.String(fieldMethod, methodAdminGetCalc),
+		zap.String(fieldCalculationID, calculationID.String()),
+	)
+
+	resp, err := c.client.AdminGetCalculation(ctx, &orchv1.AdminGetCalculationRequest{
+		Id: calculationID.String(),
+	})
+	if err != nil {
+		log.Error("Failed to get calculation", zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", msgFailedGetCalculation, mapGRPCError(err))
+	}
+
+	return mapCalculationResponse(log, resp)
+}
+
+// GetOperation возвращает операцию по ID, если ее вычисление принадлежит пользователю.
+func (c *Client) GetOperation(ctx context.Context, operationID uuid.UUID, userID uuid.UUID) (*orchestrator.Operation, error) {
+	log := logger.ContextLogger(ctx, nil).With(
+		zap.String(fieldMethod, methodGetOperation),
+		zap.String(fieldOperationID, operationID.String()),
+		zap.String(fieldUserID, userID.String()),
+	)
+
+	resp, err := c.client.GetOperation(ctx, &orchv1.GetOperationRequest{
+		Id: operationID.String(),
+	})
+	if err != nil {
+		log.Error("Failed to get operation", zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", msgFailedGetOperation, mapGRPCError(err))
+	}
+
+	respOperationID, err := uuid.Parse(resp.GetId())
+	if err != nil {
+		return nil, fmt.Errorf("%w: operation ID %q", ErrInvalidResponse, resp.GetId())
+	}
+
+	calcID, err := uuid.Parse(resp.GetCalculationId())
+	if err != nil {
+		log.Error("Invalid calculation ID received",
+			zap.String(fieldCalculationID, resp.GetCalculationId()),
+			zap.Error(err))
+		return nil, ErrInvalidCalculationID
+	}
+
+	return &orchestrator.Operation{
+		ID:             respOperationID,
+		CalculationID:  calcID,
+		OperationType:  orchestrator.OperationType(resp.GetOperationType()),
+		Operand1:       resp.GetOperand1(),
+		Operand2:       resp.GetOperand2(),
+		Result:         resp.GetResult(),
+		Status:         orchestrator.OperationStatus(resp.GetStatus()),
+		ErrorMessage:   resp.GetErrorMessage(),
+		ProcessingTime: resp.GetProcessingTimeMs(),
+		AgentID:        resp.GetAgentId(),
+	}, nil
+}
+
+// mapCalculationResponse переводит ответ с деталями вычисления в доменную модель.
+func mapCalculationResponse(log logger.Logger, resp *orchv1.GetCalculationResponse) (*orchestrator.Calculation, error) {
 	calcID, err := uuid.Parse(resp.GetId())
 	if err != nil {
 		log.Error("Invalid calculation ID received",
@@ -175,32 +295,33 @@ func (c *Client) GetCalculation(ctx context.Context, calculationID uuid.UUID, us
 		return nil, ErrInvalidUserID
 	}
 
-	status := mapProtoStatusToDomain(resp.GetStatus())
-
-	calculation := &orchestrator.Calculation{
-		ID:           calcID,
-		UserID:       respUserID,
-		Expression:   resp.GetExpression(),
-		Result:       resp.GetResult(),
-		Status:       status,
-		ErrorMessage: resp.GetErrorMessage(),
-		CreatedAt:    resp.GetCreatedAt().AsTime(),
-		UpdatedAt:    resp.GetUpdatedAt().AsTime(),
-	}
-
-	log.Debug("Calculation retrieved successfully", zap.String(fieldStatus, string(status)))
-	return calculation, nil
+	return &orchestrator.Calculation{
+		ID:            calcID,
+		UserID:        respUserID,
+		Expression:    resp.GetExpression(),
+		Result:        resp.GetResult(),
+		DisplayResult: resp.GetDisplayResult(),
+		Warnings:      resp.GetWarnings(),
+		Status:        mapProtoStatusToDomain(resp.GetStatus()),
+		ErrorMessage:  resp.GetErrorMessage(),
+		Source:        orchestrator.CalculationSource(resp.GetSource()),
+		ClientVersion: resp.GetClientVersion(),
+		CreatedAt:     resp.GetCreatedAt().AsTime(),
+		UpdatedAt:     resp.GetUpdatedAt().AsTime(),
+	}, nil
 }
 
-func (c *Client) ListCalculations(ctx context.Context, userID uuid.UUID) ([]*orchestrator.Calculation, error) {
+func (c *Client) ListCalculations(ctx context.Context, userID uuid.UUID, filter orchestrator.CalculationFilter) (*orchestrator.CalculationPage, error) {
 	log := logger.ContextLogger(ctx, nil).With(
 		zap.String(fieldMethod, methodListCalculations),
 		zap.String(fieldUserID, userID.String()),
 	)
 
-	ctx = metadata.AppendToOutgoingContext(ctx, metadataUserID, userID.String())
-
-	resp, err := c.client.ListCalculations(ctx, &emptypb.Empty{})
+	resp, err := c.client.ListCalculations(ctx, &orchv1.ListCalculationsRequest{
+		Limit:  int32(filter.Limit),  //nolint:gosec
+		Offset: int32(filter.Offset), //nolint:gosec
+		Status: string(filter.Status),
+	})
 	if err != nil {
 		log.Error("Failed to list calculations", zap.Error(err))
 		return nil, fmt.Errorf("%s: %w", msgFailedListCalculations, mapGRPCError(err))
@@ -228,21 +349,309 @@ func (c *Client) ListCalculations(ctx context.Context, userID uuid.UUID) ([]*orc
 		status := mapProtoStatusToDomain(calc.GetStatus())
 
 		calculation := &orchestrator.Calculation{
-			ID:           calcID,
-			UserID:       respUserID,
-			Expression:   calc.GetExpression(),
-			Result:       calc.GetResult(),
-			Status:       status,
-			ErrorMessage: calc.GetErrorMessage(),
-			CreatedAt:    calc.GetCreatedAt().AsTime(),
-			UpdatedAt:    calc.GetUpdatedAt().AsTime(),
+			ID:            calcID,
+			UserID:        respUserID,
+			Expression:    calc.GetExpression(),
+			Result:        calc.GetResult(),
+			DisplayResult: calc.GetDisplayResult(),
+			Warnings:      calc.GetWarnings(),
+			Status:        status,
+			ErrorMessage:  calc.GetErrorMessage(),
+			Source:        orchestrator.CalculationSource(calc.GetSource()),
+			CreatedAt:     calc.GetCreatedAt().AsTime(),
+			UpdatedAt:     calc.GetUpdatedAt().AsTime(),
 		}
 
 		calculations = append(calculations, calculation)
 	}
 
 	log.Info("User calculations retrieved successfully", zap.Int(fieldCount, len(calculations)))
-	return calculations, nil
+	return &orchestrator.CalculationPage{
+		Calculations: calculations,
+		Total:        int(resp.GetTotal()),
+		Limit:        int(resp.GetLimit()),
+		Offset:       int(resp.GetOffset()),
+	}, nil
+}
+
+func (c *Client) GetResultStats(ctx context.Context, userID uuid.UUID) (*orchestrator.ResultStats, error) {
+	log := logger.ContextLogger(ctx, nil).With(
+		zap.String(fieldMethod, methodGetResultStats),
+		zap.String(fieldUserID, userID.String()),
+	)
+
+	resp, err := c.client.GetResultStats(ctx, &orchv1.GetResultStatsRequest{})
+	if err != nil {
+		log.Error("Failed to get calculation result stats", zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", msgFailedGetResultStats, mapGRPCError(err))
+	}
+
+	return &orchestrator.ResultStats{
+		Count: resp.GetCount(),
+		Sum:   resp.GetSum(),
+		Mean:  resp.GetMean(),
+		Min:   resp.GetMin(),
+		Max:   resp.GetMax(),
+	}, nil
+}
+
+func (c *Client) CancelCalculation(ctx context.Context, calculationID uuid.UUID, userID uuid.UUID) error {
+	log := logger.ContextLogger(ctx, nil).With(
+		zap.String(fieldMethod, methodCancelCalculation),
+		zap.String(fieldCalculationID, calculationID.String()),
+		zap.String(fieldUserID, userID.String()),
+	)
+
+	_, err := c.client.CancelCalculation(ctx, &orchv1.CancelCalculationRequest{
+		Id: calculationID.String(),
+	})
+	if err != nil {
+		log.Error("Failed to cancel calculation", zap.Error(err))
+		return fmt.Errorf("%s: %w", msgFailedCancelCalculation, mapGRPCError(err))
+	}
+
+	log.Info("Calculation cancelled successfully")
+	return nil
+}
+
+func (c *Client) DeleteCalculation(ctx context.Context, calculationID uuid.UUID, userID uuid.UUID) error {
+	log := logger.ContextLogger(ctx, nil).With(
+		zap.String(fieldMethod, methodDeleteCalculation),
+		zap.String(fieldCalculationID, calculationID.String()),
+		zap.String(fieldUserID, userID.String()),
+	)
+
+	_, err := c.client.DeleteCalculation(ctx, &orchv1.DeleteCalculationRequest{
+		Id: calculationID.String(),
+	})
+	if err != nil {
+		log.Error("Failed to delete calculation", zap.Error(err))
+		return fmt.Errorf("%s: %w", msgFailedDeleteCalculation, mapGRPCError(err))
+	}
+
+	log.Info("Calculation deleted successfully")
+	return nil
+}
+
+func (c *Client) CompareExpressions(ctx context.Context, expressionA, expressionB string, tolerance float64) (*orchestrator.ExpressionComparison, error) {
+	log := logger.ContextLogger(ctx, nil).With(zap.String(fieldMethod, methodCompare))
+
+	resp, err := c.client.CompareExpressions(ctx, &orchv1.CompareExpressionsRequest{
+		ExpressionA: expressionA,
+		ExpressionB: expressionB,
+		Tolerance:   tolerance,
+	})
+	if err != nil {
+		log.Error("Failed to compare expressions", zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", msgFailedCompare, mapGRPCError(err))
+	}
+
+	return &orchestrator.ExpressionComparison{
+		ExpressionA: expressionA,
+		ExpressionB: expressionB,
+		ResultA:     resp.GetResultA(),
+		ResultB:     resp.GetResultB(),
+		Difference:  resp.GetDifference(),
+		Tolerance:   resp.GetTolerance(),
+		Equal:       resp.GetEqual(),
+	}, nil
+}
+
+func (c *Client) DiffCalculations(ctx context.Context, calculationIDA, calculationIDB uuid.UUID, userID uuid.UUID) (*orchestrator.CalculationDiff, error) {
+	log := logger.ContextLogger(ctx, nil).With(
+		zap.String(fieldMethod, methodDiff),
+		zap.String(fieldCalculationID, calculationIDA.String()),
+		zap.String(fieldUserID, userID.String()),
+	)
+
+	resp, err := c.client.DiffCalculations(ctx, &orchv1.DiffCalculationsRequest{
+		Id:      calculationIDA.String(),
+		OtherId: calculationIDB.String(),
+	})
+	if err != nil {
+		log.Error("Failed to diff calculations", zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", msgFailedDiff, mapGRPCError(err))
+	}
+
+	diff := &orchestrator.CalculationDiff{
+		CalculationIDA: calculationIDA,
+		CalculationIDB: calculationIDB,
+		ExpressionA:    resp.GetExpressionA(),
+		ExpressionB:    resp.GetExpressionB(),
+		ResultA:        resp.GetResultA(),
+		ResultB:        resp.GetResultB(),
+		SameExpression: resp.GetSameExpression(),
+		SameResult:     resp.GetSameResult(),
+	}
+	if resp.GetNumeric() {
+		delta := resp.GetDelta()
+		diff.Delta = &delta
+	}
+	return diff, nil
+}
+
+func (c *Client) PreviewExpression(ctx context.Context, expression string, options orchestrator.PreviewOptions) (*orchestrator.ExpressionPreview, error) {
+	log := logger.ContextLogger(ctx, nil).With(zap.String(fieldMethod, methodPreview))
+
+	resp, err := c.client.PreviewExpression(ctx, &orchv1.PreviewExpressionRequest{
+		Expression: expression,
+		Spaced:     options.Spaced,
+	})
+	if err != nil {
+		log.Error("Failed to preview expression", zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", msgFailedPreview, mapGRPCError(err))
+	}
+
+	return &orchestrator.ExpressionPreview{
+		Expression: resp.GetExpression(),
+		Grouped:    resp.GetGrouped(),
+	}, nil
+}
+
+func (c *Client) ValidateExpression(ctx context.Context, expression string) (*orchestrator.ExpressionValidation, error) {
+	log := logger.ContextLogger(ctx, nil).With(zap.String(fieldMethod, methodValidate))
+
+	resp, err := c.client.ValidateExpression(ctx, &orchv1.ValidateExpressionRequest{Expression: expression})
+	if err != nil {
+		log.Error("Failed to validate expression", zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", msgFailedValidate, mapGRPCError(err))
+	}
+
+	validation := &orchestrator.ExpressionValidation{
+		Expression:   resp.GetExpression(),
+		Valid:        resp.GetValid(),
+		ErrorMessage: resp.GetErrorMessage(),
+	}
+	if !validation.Valid {
+		offset := int(resp.GetErrorOffset())
+		validation.ErrorOffset = &offset
+	}
+	return validation, nil
+}
+
+func (c *Client) GetPoolStats(ctx context.Context) (*agent.PoolStats, error) {
+	log := logger.ContextLogger(ctx, nil).With(zap.String(fieldMethod, methodGetPoolStats))
+
+	resp, err := c.client.GetPoolStats(ctx, &orchv1.GetPoolStatsRequest{})
+	if err != nil {
+		log.Error("Failed to get agent pool stats", zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", msgFailedGetPoolStats, mapGRPCError(err))
+	}
+
+	stats := &agent.PoolStats{
+		Agents:        make([]agent.AgentStats, 0, len(resp.GetAgents())),
+		TotalAgents:   int(resp.GetTotalAgents()),
+		OnlineAgents:  int(resp.GetOnlineAgents()),
+		BusyAgents:    int(resp.GetBusyAgents()),
+		TotalLoad:     int(resp.GetTotalLoad()),
+		TotalCapacity: int(resp.GetTotalCapacity()),
+		Completed:     resp.GetCompleted(),
+		Failed:        resp.GetFailed(),
+		QueueDepth:    int(resp.GetQueueDepth()),
+		Throughput: agent.Throughput{
+			Dispatched: resp.GetThroughput().GetDispatched(),
+			Completed:  resp.GetThroughput().GetCompleted(),
+			Failed:     resp.GetThroughput().GetFailed(),
+		},
+		Workers: int(resp.GetWorkers()),
+	}
+	for _, a := range resp.GetAgents() {
+		stats.Agents = append(stats.Agents, mapAgentStatsFromProto(a))
+	}
+
+	return stats, nil
+}
+
+func (c *Client) GetTimingProfile(ctx context.Context) (*agent.TimingProfile, error) {
+	log := logger.ContextLogger(ctx, nil).With(zap.String(fieldMethod, methodGetTimingProfile))
+
+	resp, err := c.client.GetTimingProfile(ctx, &orchv1.GetTimingProfileRequest{})
+	if err != nil {
+		log.Error("Failed to get agent timing profile", zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", msgFailedGetTimingProfile, mapGRPCError(err))
+	}
+
+	profile := &agent.TimingProfile{
+		ConfiguredMs: resp.GetConfiguredMs(),
+		Agents:       make([]agent.AgentTiming, 0, len(resp.GetAgents())),
+	}
+	if profile.ConfiguredMs == nil {
+		profile.ConfiguredMs = map[string]int64{}
+	}
+	for _, a := range resp.GetAgents() {
+		times := a.GetOperationTimesMs()
+		if times == nil {
+			times = map[string]int64{}
+		}
+		profile.Agents = append(profile.Agents, agent.AgentTiming{
+			ID:               a.GetId(),
+			Deterministic:    a.GetDeterministic(),
+			OperationTimesMs: times,
+		})
+	}
+
+	return profile, nil
+}
+
+func (c *Client) SetAgentCapacity(ctx context.Context, agentID string, capacity int) (*agent.AgentStats, error) {
+	log := logger.ContextLogger(ctx, nil).With(zap.String(fieldMethod, methodSetAgentCapacity))
+
+	resp, err := c.client.SetAgentCapacity(ctx, &orchv1.SetAgentCapacityRequest{
+		AgentId:  agentID,
+		Capacity: int32(capacity), //nolint:gosec
+	})
+	if err != nil {
+		log.Error("Failed to set agent capacity", zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", msgFailedSetAgentCapacity, mapGRPCError(err))
+	}
+
+	stats := mapAgentStatsFromProto(resp.GetAgent())
+	return &stats, nil
+}
+
+func mapAgentStatsFromProto(a *orchv1.AgentStats) agent.AgentStats {
+	return agent.AgentStats{
+		ID:          a.GetId(),
+		Status:      agent.AgentStatus(a.GetStatus()),
+		CurrentLoad: int(a.GetCurrentLoad()),
+		MaxCapacity: int(a.GetMaxCapacity()),
+		Completed:   a.GetCompleted(),
+		Failed:      a.GetFailed(),
+		QueueDepth:  int(a.GetQueueDepth()),
+	}
+}
+
+func (c *Client) GetSystemStats(ctx context.Context) (*system.OrchestratorStats, error) {
+	log := logger.ContextLogger(ctx, nil).With(zap.String(fieldMethod, methodGetSystemStats))
+
+	resp, err := c.client.GetSystemStats(ctx, &orchv1.GetSystemStatsRequest{})
+	if err != nil {
+		log.Error("Failed to get system stats", zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", msgFailedGetSystemStats, mapGRPCError(err))
+	}
+
+	stats := &system.OrchestratorStats{
+		CalculationsByStatus: make(map[string]int, len(resp.GetCalculationsByStatus())),
+		TotalCalculations:    int(resp.GetTotalCalculations()),
+		TotalAgents:          int(resp.GetTotalAgents()),
+		ActiveAgents:         int(resp.GetActiveAgents()),
+		PendingOperations:    int(resp.GetPendingOperations()),
+	}
+	for status, count := range resp.GetCalculationsByStatus() {
+		stats.CalculationsByStatus[status] = int(count)
+	}
+	if pool := resp.GetDbPool(); pool != nil {
+		stats.DBPool = system.DBPoolStats{
+			TotalConns:        pool.GetTotalConns(),
+			IdleConns:         pool.GetIdleConns(),
+			AcquiredConns:     pool.GetAcquiredConns(),
+			MaxConns:          pool.GetMaxConns(),
+			AcquireCount:      pool.GetAcquireCount(),
+			AcquireDurationMs: pool.GetAcquireDurationMs(),
+		}
+	}
+
+	return stats, nil
 }
 
 func (c *Client) ProcessPendingOperations(ctx context.Context) error {
@@ -253,6 +662,71 @@ func (c *Client) UpdateCalculationStatus(ctx context.Context, calculationID uuid
 	return nil
 }
 
+// Ping проверяет, что соединение с сервисом оркестрации установлено, при необходимости
+// переподключаясь. Ожидание ограничено контекстом.
+func (c *Client) Ping(ctx context.Context) error {
+	if c.conn.GetState() == connectivity.Idle {
+		c.conn.Connect()
+	}
+	if !waitForConnection(ctx, c.conn) {
+		return fmt.Errorf("%w: %s", ErrNotReady, c.conn.GetState())
+	}
+	return nil
+}
+
+// SubscribeCalculation открывает поток изменений статуса вычисления. Первое событие
+// читается сразу, чтобы ошибки доступа и отсутствия вычисления вернулись вызывающему,
+// остальные передаются в канал до завершения потока или отмены ctx.
+func (c *Client) SubscribeCalculation(ctx context.Context, calculationID uuid.UUID, userID uuid.UUID) (<-chan orchestrator.CalculationEvent, error) {
+	log := logger.ContextLogger(ctx, nil).With(
+		zap.String(fieldMethod, methodStreamCalculation),
+		zap.String(fieldCalculationID, calculationID.String()),
+		zap.String(fieldUserID, userID.String()),
+	)
+
+	stream, err := c.client.StreamCalculation(ctx, &orchv1.StreamCalculationRequest{
+		Id: calculationID.String(),
+	})
+	if err != nil {
+		log.Error("Failed to open calculation stream", zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", msgFailedStreamCalculation, mapGRPCError(err))
+	}
+
+	first, err := stream.Recv()
+	if err != nil {
+		log.Error("Failed to receive calculation event", zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", msgFailedStreamCalculation, mapGRPCError(err))
+	}
+
+	events := make(chan orchestrator.CalculationEvent, 1)
+	go func() {
+		defer close(events)
+
+		for resp := first; ; {
+			event, err := mapProtoEventToDomain(resp)
+			if err != nil {
+				log.Error("Invalid calculation event received", zap.Error(err))
+				return
+			}
+
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+
+			if resp, err = stream.Recv(); err != nil {
+				if !errors.Is(err, io.EOF) && ctx.Err() == nil {
+					log.Warn("Calculation stream interrupted", zap.Error(err))
+				}
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
+
 func (c *Client) Close() error {
 	if c.conn != nil {
 		if err := c.conn.Close(); err != nil {
@@ -272,16 +746,39 @@ func mapProtoStatusToDomain(status orchv1.CalculationStatus) orchestrator.Calcul
 		return orchestrator.CalculationStatusCompleted
 	case orchv1.CalculationStatus_ERROR:
 		return orchestrator.CalculationStatusError
+	case orchv1.CalculationStatus_CANCELLED:
+		return orchestrator.CalculationStatusCancelled
 	default:
 		return orchestrator.CalculationStatusPending
 	}
 }
 
+func mapProtoEventToDomain(resp *orchv1.CalculationEvent) (orchestrator.CalculationEvent, error) {
+	calcID, err := uuid.Parse(resp.GetId())
+	if err != nil {
+		return orchestrator.CalculationEvent{}, ErrInvalidCalculationID
+	}
+
+	return orchestrator.CalculationEvent{
+		CalculationID: calcID,
+		Status:        mapProtoStatusToDomain(resp.GetStatus()),
+		Result:        resp.GetResult(),
+		ErrorMessage:  resp.GetErrorMessage(),
+		UpdatedAt:     resp.GetUpdatedAt().AsTime(),
+	}, nil
+}
+
+// mapGRPCError восстанавливает доменную ошибку по коду из деталей ErrorInfo ответа сервера.
+// Для ответов без деталей ошибка выбирается по коду статуса.
 func mapGRPCError(err error) error {
 	if err == nil {
 		return nil
 	}
 
+	if domainErr := middleware.DomainError(err); domainErr != nil {
+		return domainErr
+	}
+
 	st, ok := status.FromError(err)
 	if !ok {
 		return err
@@ -289,15 +786,13 @@ func mapGRPCError(err error) error {
 
 	switch st.Code() {
 	case codes.NotFound:
-		return ErrCalculationNotFound
-	case codes.PermissionDenied, codes.Unauthenticated:
-		return ErrUnauthorizedAccess
+		return fmt.Errorf("%w: %w", ErrCalculationNotFound, domainerrors.ErrCalculationNotFound)
+	case codes.PermissionDenied:
+		return fmt.Errorf("%w: %w", ErrUnauthorizedAccess, domainerrors.ErrUnauthorizedAccess)
+	case codes.Unauthenticated:
+		return fmt.Errorf("%w: %s", domainerrors.ErrInvalidToken, st.Message())
 	case codes.InvalidArgument:
-		if st.Message() == msgEmptyExpression {
-			return ErrInvalidExpression
-		}
-		// Use static error instead of dynamic error
-		return fmt.Errorf("%w: %s", ErrInvalidArgument, st.Message())
+		return fmt.Errorf("%w: %w: %s", ErrInvalidArgument, domainerrors.ErrInvalidArgs, st.Message())
 	case codes.Internal:
 		return ErrInternalServerError
 	default:
@@ -1,32 +1,84 @@
 package grpc
 
 import (
+	"time"
+
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/grpc/middleware"
+	jwtPort "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/service/jwt"
+	orchv1 "github.com/flexer2006/y.lms-final-task-calc-go/pkg/api/proto/v1/orchestrator"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
 )
 
+// KeepaliveMinTime - минимальный интервал пингов клиента. Клиенты шлюза пингуют не чаще
+// раза в 10 секунд, поэтому их соединения не закрываются за слишком частые пинги.
+const KeepaliveMinTime = 5 * time.Second
+
+// orchestratorAdminMethods - методы сервиса оркестрации, доступные только администраторам.
+var orchestratorAdminMethods = []string{
+	orchv1.OrchestratorService_GetPoolStats_FullMethodName,
+	orchv1.OrchestratorService_GetTimingProfile_FullMethodName,
+	orchv1.OrchestratorService_SetAgentCapacity_FullMethodName,
+	orchv1.OrchestratorService_GetSystemStats_FullMethodName,
+	orchv1.OrchestratorService_AdminGetCalculation_FullMethodName,
+	orchv1.OrchestratorService_GetLogLevel_FullMethodName,
+	orchv1.OrchestratorService_SetLogLevel_FullMethodName,
+}
+
 func NewServerAuth(opts ...grpc.ServerOption) *grpc.Server {
 	return newServerWithMiddleware(opts...)
 }
 
-func NewServerOrchestrator(opts ...grpc.ServerOption) *grpc.Server {
-	return newServerWithMiddleware(opts...)
+// NewServerOrchestrator создает сервер оркестрации, который принимает только запросы
+// с действительным токеном доступа. Токены проверяет tokens. Административные методы
+// доступны только пользователям из adminIDs.
+func NewServerOrchestrator(tokens jwtPort.Service, adminIDs []string, opts ...grpc.ServerOption) *grpc.Server {
+	authOpts := append([]grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(
+			middleware.UnaryServerAuth(tokens),
+			middleware.UnaryServerAdmin(adminIDs, orchestratorAdminMethods...),
+		),
+		grpc.ChainStreamInterceptor(
+			middleware.StreamServerAuth(tokens),
+			middleware.StreamServerAdmin(adminIDs, orchestratorAdminMethods...),
+		),
+	}, opts...)
+	return newServerWithMiddleware(authOpts...)
+}
+
+// NewServerAgent создает сервер для агентов, работающих в отдельных процессах, который
+// принимает только запросы с общим токеном агентов token.
+func NewServerAgent(token string, opts ...grpc.ServerOption) *grpc.Server {
+	tokenOpts := append([]grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(middleware.UnaryServerAgentToken(token)),
+		grpc.ChainStreamInterceptor(middleware.StreamServerAgentToken(token)),
+	}, opts...)
+	return newServerWithMiddleware(tokenOpts...)
 }
 
 func newServerWithMiddleware(opts ...grpc.ServerOption) *grpc.Server {
 	chainedUnary := grpc.ChainUnaryInterceptor(
 		middleware.UnaryServerRecovery(),
 		middleware.UnaryServerLogging(),
+		middleware.UnaryServerTracing(),
 		middleware.UnaryServerError(),
 	)
 
 	chainedStream := grpc.ChainStreamInterceptor(
 		middleware.StreamServerRecovery(),
 		middleware.StreamServerLogging(),
+		middleware.StreamServerTracing(),
 		middleware.StreamServerError(),
 	)
 
+	// Спаны запросов создает otelgrpc; без настроенного экспорта они не записываются
 	serverOpts := append([]grpc.ServerOption{
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             KeepaliveMinTime,
+			PermitWithoutStream: true,
+		}),
 		chainedUnary,
 		chainedStream,
 	}, opts...)
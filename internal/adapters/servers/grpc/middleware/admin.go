@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const errAdminRequired = "admin role required"
+
+// UnaryServerAdmin пропускает вызовы методов methods (полные имена вида
+// "/package.Service/Method") только от администраторов adminIDs. Остальные методы
+// не проверяются. ID пользователя берется из контекста, поэтому перехватчик ставится
+// после UnaryServerAuth. Некорректные идентификаторы в adminIDs игнорируются.
+func UnaryServerAdmin(adminIDs []string, methods ...string) grpc.UnaryServerInterceptor {
+	admins, guarded := adminSet(adminIDs), methodSet(methods)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if _, ok := guarded[info.FullMethod]; ok {
+			if err := checkAdmin(ctx, admins, info.FullMethod); err != nil {
+				return nil, err
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerAdmin - аналог UnaryServerAdmin для потоковых методов.
+func StreamServerAdmin(adminIDs []string, methods ...string) grpc.StreamServerInterceptor {
+	admins, guarded := adminSet(adminIDs), methodSet(methods)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if _, ok := guarded[info.FullMethod]; ok {
+			if err := checkAdmin(ss.Context(), admins, info.FullMethod); err != nil {
+				return err
+			}
+		}
+		return handler(srv, ss)
+	}
+}
+
+func checkAdmin(ctx context.Context, admins map[uuid.UUID]struct{}, method string) error {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, errMissingToken)
+	}
+	if _, ok := admins[userID]; !ok {
+		logger.ContextLogger(ctx, nil).Warn("Admin method called by non-admin user",
+			zap.String(fieldMethod, method),
+			zap.String("user_id", userID.String()))
+		return status.Error(codes.PermissionDenied, errAdminRequired)
+	}
+	return nil
+}
+
+func adminSet(adminIDs []string) map[uuid.UUID]struct{} {
+	admins := make(map[uuid.UUID]struct{}, len(adminIDs))
+	for _, id := range adminIDs {
+		parsed, err := uuid.Parse(id)
+		if err != nil || parsed == uuid.Nil {
+			continue
+		}
+		admins[parsed] = struct{}{}
+	}
+	return admins
+}
+
+func methodSet(methods []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(methods))
+	for _, method := range methods {
+		set[method] = struct{}{}
+	}
+	return set
+}
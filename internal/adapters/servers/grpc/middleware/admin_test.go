@@ -0,0 +1,85 @@
+package middleware_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/grpc/middleware"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/services/jwt"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestServerAdmin(t *testing.T) {
+	baseCtx := logger.WithLogger(context.Background(), logger.New(zapcore.NewNopCore()))
+	tokens := jwt.NewService(testSecretKey, time.Minute, time.Hour)
+	adminID, userID := uuid.New(), uuid.New()
+
+	const (
+		adminMethod  = "/orchestrator.v1.OrchestratorService/SetAgentCapacity"
+		publicMethod = "/orchestrator.v1.OrchestratorService/Calculate"
+	)
+	admin := middleware.UnaryServerAdmin([]string{adminID.String(), "not-a-uuid"}, adminMethod)
+	adminStream := middleware.StreamServerAdmin([]string{adminID.String()}, adminMethod)
+
+	// authenticated возвращает контекст после UnaryServerAuth, как на сервере оркестрации
+	authenticated := func(t *testing.T, caller uuid.UUID) context.Context {
+		t.Helper()
+
+		pair, err := tokens.GenerateTokens(baseCtx, caller, "user")
+		require.NoError(t, err)
+		ctx := metadata.NewIncomingContext(baseCtx, metadata.Pairs(middleware.MetadataAuthorization, "Bearer "+pair.AccessToken))
+
+		var authCtx context.Context
+		_, err = middleware.UnaryServerAuth(tokens)(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, _ any) (any, error) {
+			authCtx = ctx
+			return nil, nil
+		})
+		require.NoError(t, err)
+		return authCtx
+	}
+
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		method       string
+		expectedCode codes.Code
+	}{
+		{name: "Admin calls admin method", ctx: authenticated(t, adminID), method: adminMethod, expectedCode: codes.OK},
+		{name: "User calls admin method", ctx: authenticated(t, userID), method: adminMethod, expectedCode: codes.PermissionDenied},
+		{name: "User calls public method", ctx: authenticated(t, userID), method: publicMethod, expectedCode: codes.OK},
+		{name: "Unauthenticated admin method", ctx: baseCtx, method: adminMethod, expectedCode: codes.Unauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run("Unary/"+tt.name, func(t *testing.T) {
+			called := false
+			_, err := admin(tt.ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, func(context.Context, any) (any, error) {
+				called = true
+				return nil, nil
+			})
+
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			assert.Equal(t, tt.expectedCode == codes.OK, called)
+		})
+
+		t.Run("Stream/"+tt.name, func(t *testing.T) {
+			called := false
+			err := adminStream(nil, &authStream{ctx: tt.ctx}, &grpc.StreamServerInfo{FullMethod: tt.method}, func(any, grpc.ServerStream) error {
+				called = true
+				return nil
+			})
+
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			assert.Equal(t, tt.expectedCode == codes.OK, called)
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const errInvalidAgentToken = "missing or invalid agent token"
+
+// UnaryServerAgentToken пропускает только запросы агентов, передавших в метаданных
+// authorization общий токен агентов в виде "Bearer <token>". Остальные запросы
+// отклоняются с кодом Unauthenticated.
+func UnaryServerAgentToken(token string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := checkAgentToken(ctx, token, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerAgentToken - аналог UnaryServerAgentToken для потоковых методов.
+func StreamServerAgentToken(token string) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := checkAgentToken(ss.Context(), token, info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func checkAgentToken(ctx context.Context, token, method string) error {
+	got, ok := bearerToken(ctx)
+	if !ok || token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+		logger.ContextLogger(ctx, nil).Warn("Agent request with invalid token", zap.String(fieldMethod, method))
+		return status.Error(codes.Unauthenticated, errInvalidAgentToken)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package middleware_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/grpc/middleware"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestServerAgentToken(t *testing.T) {
+	baseCtx := logger.WithLogger(context.Background(), logger.New(zapcore.NewNopCore()))
+	const token = "agent-secret"
+
+	tests := []struct {
+		name          string
+		token         string
+		authorization string
+		expectedCode  codes.Code
+	}{
+		{name: "Valid token", token: token, authorization: "Bearer " + token, expectedCode: codes.OK},
+		{name: "Missing metadata", token: token, expectedCode: codes.Unauthenticated},
+		{name: "Wrong token", token: token, authorization: "Bearer other", expectedCode: codes.Unauthenticated},
+		{name: "Not a bearer token", token: token, authorization: token, expectedCode: codes.Unauthenticated},
+		{name: "Server without token", token: "", authorization: "Bearer ", expectedCode: codes.Unauthenticated},
+	}
+
+	for _, tt := range tests {
+		ctx := baseCtx
+		if tt.authorization != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(middleware.MetadataAuthorization, tt.authorization))
+		}
+
+		t.Run("Unary/"+tt.name, func(t *testing.T) {
+			called := false
+			handler := func(context.Context, any) (any, error) {
+				called = true
+				return "ok", nil
+			}
+
+			_, err := middleware.UnaryServerAgentToken(tt.token)(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/agent.v1.AgentService/Register"}, handler)
+
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			assert.Equal(t, tt.expectedCode == codes.OK, called)
+		})
+
+		t.Run("Stream/"+tt.name, func(t *testing.T) {
+			called := false
+			handler := func(any, grpc.ServerStream) error {
+				called = true
+				return nil
+			}
+
+			err := middleware.StreamServerAgentToken(tt.token)(nil, &authStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: "/test/Stream"}, handler)
+
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			assert.Equal(t, tt.expectedCode == codes.OK, called)
+		})
+	}
+}
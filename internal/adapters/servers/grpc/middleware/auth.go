@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	jwtPort "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/service/jwt"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// MetadataAuthorization - ключ метаданных, в котором клиент передает токен доступа
+	// в виде "Bearer <token>".
+	MetadataAuthorization = "authorization"
+
+	bearerPrefix = "Bearer "
+
+	errMissingToken = "missing access token"
+	errInvalidToken = "invalid or expired access token"
+)
+
+type userIDContextKey struct{}
+
+// UnaryServerAuth проверяет токен доступа из метаданных запроса и кладет ID пользователя
+// из токена в контекст обработчика. Запросы без токена или с недействительным токеном
+// отклоняются с кодом Unauthenticated. Для проверки достаточно сервиса, который только
+// проверяет токены, например jwt.NewVerifierRSA.
+func UnaryServerAuth(tokens jwtPort.Service) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, tokens, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerAuth - аналог UnaryServerAuth для потоковых методов.
+func StreamServerAuth(tokens jwtPort.Service) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), tokens, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &wrappedServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// UserIDFromContext возвращает ID пользователя, установленный UnaryServerAuth или StreamServerAuth.
+func UserIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	userID, ok := ctx.Value(userIDContextKey{}).(uuid.UUID)
+	return userID, ok
+}
+
+func authenticate(ctx context.Context, tokens jwtPort.Service, method string) (context.Context, error) {
+	log := logger.ContextLogger(ctx, nil).With(zap.String(fieldMethod, method))
+
+	token, ok := bearerToken(ctx)
+	if !ok {
+		log.Warn("Request without access token")
+		return nil, status.Error(codes.Unauthenticated, errMissingToken)
+	}
+
+	userID, err := tokens.ValidateToken(ctx, token)
+	if err != nil {
+		log.Warn("Access token validation failed", zap.Error(err))
+		return nil, status.Error(codes.Unauthenticated, errInvalidToken)
+	}
+
+	return context.WithValue(ctx, userIDContextKey{}, userID), nil
+}
+
+func bearerToken(ctx context.Context) (string, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", false
+	}
+
+	values := md.Get(MetadataAuthorization)
+	if len(values) == 0 || !strings.HasPrefix(values[0], bearerPrefix) {
+		return "", false
+	}
+
+	token := strings.TrimSpace(strings.TrimPrefix(values[0], bearerPrefix))
+	return token, token != ""
+}
@@ -0,0 +1,105 @@
+package middleware_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/grpc/middleware"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/services/jwt"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const testSecretKey = "test-secret-key-for-interceptor"
+
+type authStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestServerAuth(t *testing.T) {
+	baseCtx := logger.WithLogger(context.Background(), logger.New(zapcore.NewNopCore()))
+	tokens := jwt.NewService(testSecretKey, time.Minute, time.Hour)
+	userID := uuid.New()
+
+	pair, err := tokens.GenerateTokens(baseCtx, userID, "alice")
+	require.NoError(t, err)
+
+	otherTokens := jwt.NewService("another-secret-key-for-tests", time.Minute, time.Hour)
+	forged, err := otherTokens.GenerateTokens(baseCtx, userID, "alice")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		authorization string
+		expectedCode  codes.Code
+	}{
+		{name: "Valid token", authorization: "Bearer " + pair.AccessToken, expectedCode: codes.OK},
+		{name: "Missing metadata", authorization: "", expectedCode: codes.Unauthenticated},
+		{name: "Empty bearer token", authorization: "Bearer ", expectedCode: codes.Unauthenticated},
+		{name: "Not a bearer token", authorization: pair.AccessToken, expectedCode: codes.Unauthenticated},
+		{name: "Token signed with another key", authorization: "Bearer " + forged.AccessToken, expectedCode: codes.Unauthenticated},
+		{name: "Refresh token", authorization: "Bearer " + pair.RefreshToken, expectedCode: codes.Unauthenticated},
+	}
+
+	for _, tt := range tests {
+		ctx := baseCtx
+		if tt.authorization != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(middleware.MetadataAuthorization, tt.authorization))
+		}
+
+		t.Run("Unary/"+tt.name, func(t *testing.T) {
+			var got uuid.UUID
+			handler := func(ctx context.Context, _ any) (any, error) {
+				var ok bool
+				got, ok = middleware.UserIDFromContext(ctx)
+				assert.True(t, ok)
+				return "ok", nil
+			}
+
+			resp, err := middleware.UnaryServerAuth(tokens)(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/test/Unary"}, handler)
+
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			if tt.expectedCode == codes.OK {
+				assert.Equal(t, "ok", resp)
+				assert.Equal(t, userID, got)
+			} else {
+				assert.Nil(t, resp)
+				assert.Equal(t, uuid.Nil, got, "handler must not be called")
+			}
+		})
+
+		t.Run("Stream/"+tt.name, func(t *testing.T) {
+			called := false
+			handler := func(_ any, ss grpc.ServerStream) error {
+				called = true
+				got, ok := middleware.UserIDFromContext(ss.Context())
+				assert.True(t, ok)
+				assert.Equal(t, userID, got)
+				return nil
+			}
+
+			err := middleware.StreamServerAuth(tokens)(nil, &authStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: "/test/Stream"}, handler)
+
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			assert.Equal(t, tt.expectedCode == codes.OK, called)
+		})
+	}
+}
+
+func TestUserIDFromContextWithoutAuth(t *testing.T) {
+	_, ok := middleware.UserIDFromContext(context.Background())
+	assert.False(t, ok)
+}
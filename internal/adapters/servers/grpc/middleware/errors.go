@@ -8,11 +8,15 @@ import (
 	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
 	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
+// ErrorDomain - домен, которым помечаются детали ErrorInfo в ошибках gRPC.
+const ErrorDomain = "calc.flexer2006.github.com"
+
 var ErrorMapping = map[error]codes.Code{
 	domainerrors.ErrUserAlreadyExists:   codes.AlreadyExists,
 	domainerrors.ErrInvalidCredentials:  codes.Unauthenticated,
@@ -21,9 +25,72 @@ var ErrorMapping = map[error]codes.Code{
 	domainerrors.ErrTokenExpired:        codes.Unauthenticated,
 	domainerrors.ErrTokenNotFound:       codes.NotFound,
 	domainerrors.ErrTokenRevoked:        codes.Unauthenticated,
+	domainerrors.ErrSessionTooOld:       codes.Unauthenticated,
 	domainerrors.ErrInternalServerError: codes.Internal,
 }
 
+// reasonErrors сопоставляет кодам из ErrorInfo доменные ошибки, которые клиенты
+// восстанавливают из ответа сервера. Если код общий для нескольких ошибок,
+// используется первая в списке.
+var reasonErrors = indexByCode(
+	domainerrors.ErrInternalServerError,
+	domainerrors.ErrInvalidExpression,
+	domainerrors.ErrInvalidUserID,
+	domainerrors.ErrCalculationNotFound,
+	domainerrors.ErrUnauthorizedAccess,
+	domainerrors.ErrTooManyOps,
+	domainerrors.ErrInvalidArgs,
+	domainerrors.ErrInvalidSource,
+	domainerrors.ErrInvalidPriority,
+	domainerrors.ErrInvalidIdempotencyKey,
+	domainerrors.ErrIdempotencyKeyConflict,
+	domainerrors.ErrInvalidPagination,
+	domainerrors.ErrInvalidStatusFilter,
+	domainerrors.ErrCalcNotCancellable,
+	domainerrors.ErrInvalidResultFormat,
+	domainerrors.ErrNonIntegerResult,
+	domainerrors.ErrParseTimeout,
+	domainerrors.ErrInvalidTolerance,
+	domainerrors.ErrReferenceNotCompleted,
+	domainerrors.ErrTooManyReferences,
+	domainerrors.ErrPendingOpsBudget,
+	domainerrors.ErrDatabaseUnavailable,
+	domainerrors.ErrOperationNotFound,
+	domainerrors.ErrInvalidOperationID,
+	domainerrors.ErrInvalidOperand,
+	domainerrors.ErrDivisionByZero,
+	domainerrors.ErrNumericOverflow,
+	domainerrors.ErrFactorialOperand,
+	domainerrors.ErrUnsupportedOp,
+	domainerrors.ErrAgentNotFound,
+	domainerrors.ErrAgentAlreadyExists,
+	domainerrors.ErrInvalidCapacity,
+	domainerrors.ErrPoolNotRunning,
+	domainerrors.ErrPoolDraining,
+	domainerrors.ErrUserAlreadyExists,
+	domainerrors.ErrInvalidCredentials,
+	domainerrors.ErrTooManyAttempts,
+	domainerrors.ErrUserNotFound,
+	domainerrors.ErrInvalidToken,
+	domainerrors.ErrTokenExpired,
+	domainerrors.ErrTokenNotFound,
+	domainerrors.ErrTokenRevoked,
+	domainerrors.ErrSessionTooOld,
+	domainerrors.ErrSamePassword,
+	domainerrors.ErrWeakPassword,
+)
+
+func indexByCode(errs ...error) map[string]error {
+	index := make(map[string]error, len(errs))
+	for _, err := range errs {
+		code := domainerrors.Code(err)
+		if _, ok := index[code]; !ok {
+			index[code] = err
+		}
+	}
+	return index
+}
+
 func UnaryServerError() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
 		resp, err := handler(ctx, req)
@@ -64,5 +131,60 @@ func mapError(ctx context.Context, err error) error {
 		statusCode = codes.Internal
 	}
 
-	return fmt.Errorf("mapped gRPC error: %w", status.Error(statusCode, err.Error()))
+	return fmt.Errorf("mapped gRPC error: %w", StatusError(statusCode, err.Error(), err))
+}
+
+// StatusError создает ошибку gRPC с кодом code и сообщением msg. Если cause несет код доменной
+// ошибки, он передается клиенту в деталях ErrorInfo в поле Reason.
+func StatusError(code codes.Code, msg string, cause error) error {
+	st := status.New(code, msg)
+
+	reason := domainerrors.Code(cause)
+	if reason == "" {
+		return st.Err()
+	}
+
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{Reason: reason, Domain: ErrorDomain})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// ErrorCode возвращает код доменной ошибки из деталей ErrorInfo ошибки gRPC err
+// или пустую строку, если деталей нет.
+func ErrorCode(err error) string {
+	st, ok := status.FromError(err)
+	if !ok {
+		return ""
+	}
+	return errorCode(st)
+}
+
+// DomainError восстанавливает доменную ошибку по коду из деталей ErrorInfo ошибки gRPC err.
+// Если сообщение статуса отличается от текста доменной ошибки, оно добавляется к ней.
+// Возвращает nil, если деталей нет или код неизвестен.
+func DomainError(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return nil
+	}
+
+	domainErr, ok := reasonErrors[errorCode(st)]
+	if !ok {
+		return nil
+	}
+	if st.Message() == "" || st.Message() == domainErr.Error() {
+		return domainErr
+	}
+	return fmt.Errorf("%w: %s", domainErr, st.Message())
+}
+
+func errorCode(st *status.Status) string {
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.GetDomain() == ErrorDomain {
+			return info.GetReason()
+		}
+	}
+	return ""
 }
@@ -0,0 +1,82 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/grpc/middleware"
+	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStatusError(t *testing.T) {
+	err := middleware.StatusError(codes.NotFound, "calculation not found", fmt.Errorf("get: %w", domainerrors.ErrCalculationNotFound))
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, "CALC_NOT_FOUND", middleware.ErrorCode(err))
+	assert.Equal(t, "CALC_NOT_FOUND", middleware.ErrorCode(fmt.Errorf("gRPC error: %w", err)))
+
+	plain := middleware.StatusError(codes.Internal, "failed", errors.New("boom"))
+	assert.Equal(t, codes.Internal, status.Code(plain))
+	assert.Empty(t, middleware.ErrorCode(plain))
+	assert.Empty(t, middleware.ErrorCode(errors.New("not a status")))
+}
+
+func TestDomainError(t *testing.T) {
+	testCases := []struct {
+		name    string
+		err     error
+		want    error
+		message string
+	}{
+		{"Same message", middleware.StatusError(codes.NotFound, "calculation not found", domainerrors.ErrCalculationNotFound), domainerrors.ErrCalculationNotFound, "calculation not found"},
+		{"Server message kept", middleware.StatusError(codes.NotFound, "referenced calculation not found", domainerrors.ErrCalculationNotFound), domainerrors.ErrCalculationNotFound, "calculation not found: referenced calculation not found"},
+		{"Shared code", middleware.StatusError(codes.NotFound, "calculation not found", domainerrors.ErrSpecificCalcNotFound), domainerrors.ErrCalculationNotFound, "calculation not found"},
+		{"Auth error", middleware.StatusError(codes.Unauthenticated, "failed to login user", domainerrors.ErrInvalidCredentials), domainerrors.ErrInvalidCredentials, "invalid login or password: failed to login user"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := middleware.DomainError(tc.err)
+			assert.ErrorIs(t, err, tc.want)
+			assert.EqualError(t, err, tc.message)
+		})
+	}
+
+	assert.NoError(t, middleware.DomainError(status.Error(codes.NotFound, "calculation not found")))
+	assert.NoError(t, middleware.DomainError(middleware.StatusError(codes.Internal, "failed", errors.New("boom"))))
+	assert.NoError(t, middleware.DomainError(errors.New("not a status")))
+}
+
+func TestUnaryServerErrorDetails(t *testing.T) {
+	ctx := logger.WithLogger(context.Background(), logger.New(zapcore.NewNopCore()))
+	interceptor := middleware.UnaryServerError()
+
+	testCases := []struct {
+		name   string
+		err    error
+		code   codes.Code
+		reason string
+	}{
+		{"Domain error", fmt.Errorf("login: %w", domainerrors.ErrInvalidCredentials), codes.Unauthenticated, "AUTH_INVALID_CREDENTIALS"},
+		{"Unmapped domain error", domainerrors.ErrInvalidExpression, codes.Internal, "CALC_INVALID_EXPRESSION"},
+		{"Unknown error", errors.New("boom"), codes.Internal, ""},
+		{"Status error keeps details", middleware.StatusError(codes.NotFound, "user not found", domainerrors.ErrUserNotFound), codes.NotFound, "AUTH_USER_NOT_FOUND"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, func(context.Context, any) (any, error) {
+				return nil, tc.err
+			})
+			assert.Equal(t, tc.code, status.Code(err))
+			assert.Equal(t, tc.reason, middleware.ErrorCode(err))
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/tracing"
+	"google.golang.org/grpc"
+)
+
+// UnaryServerTracing добавляет к спану запроса, созданному otelgrpc, идентификатор запроса
+// журнала. Должен идти в цепочке после UnaryServerLogging, который этот идентификатор создает.
+func UnaryServerTracing() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		tracing.AnnotateRequestID(ctx)
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerTracing - аналог UnaryServerTracing для потоковых методов.
+func StreamServerTracing() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		tracing.AnnotateRequestID(ss.Context())
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,212 @@
+package orchestrator_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	grpcserver "github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/grpc"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/grpc/middleware"
+	grpcorch "github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/grpc/orchestrator"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/services/jwt"
+	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	orchapi "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/api/orchestrator"
+	orchv1 "github.com/flexer2006/y.lms-final-task-calc-go/pkg/api/proto/v1/orchestrator"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// pagedUseCase отдает вычисления пользователя по страницам, как хранилище, и запоминает
+// запрошенные смещения.
+type pagedUseCase struct {
+	orchapi.UseCaseCalculation
+
+	calculations []*orchestrator.Calculation
+
+	mu      sync.Mutex
+	offsets []int
+}
+
+func (u *pagedUseCase) ListCalculations(_ context.Context, _ uuid.UUID, filter orchestrator.CalculationFilter) (*orchestrator.CalculationPage, error) {
+	if filter.Limit < 0 {
+		return nil, fmt.Errorf("%w: negative limit", domainerrors.ErrInvalidPagination)
+	}
+	if filter.Limit == 0 {
+		filter.Limit = 10
+	}
+
+	u.mu.Lock()
+	u.offsets = append(u.offsets, filter.Offset)
+	u.mu.Unlock()
+
+	start := min(filter.Offset, len(u.calculations))
+	end := min(start+filter.Limit, len(u.calculations))
+	return &orchestrator.CalculationPage{
+		Calculations: u.calculations[start:end],
+		Total:        len(u.calculations),
+		Limit:        filter.Limit,
+		Offset:       filter.Offset,
+	}, nil
+}
+
+func (u *pagedUseCase) requestedOffsets() []int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return append([]int(nil), u.offsets...)
+}
+
+func newCalculations(userID uuid.UUID, count int) []*orchestrator.Calculation {
+	calculations := make([]*orchestrator.Calculation, count)
+	for i := range calculations {
+		calculations[i] = &orchestrator.Calculation{
+			ID:         uuid.New(),
+			UserID:     userID,
+			Expression: fmt.Sprintf("%d+1", i),
+			Status:     orchestrator.CalculationStatusCompleted,
+			CreatedAt:  time.Now(),
+			UpdatedAt:  time.Now(),
+		}
+	}
+	return calculations
+}
+
+// newStreamClient запускает сервер оркестрации в памяти и возвращает клиента и контекст
+// с токеном пользователя userID.
+func newStreamClient(t *testing.T, useCase orchapi.UseCaseCalculation, userID uuid.UUID) (orchv1.OrchestratorServiceClient, context.Context) {
+	t.Helper()
+
+	tokens := jwt.NewService("test-secret-key-for-list-stream", time.Minute, time.Hour)
+	baseCtx := logger.WithLogger(context.Background(), logger.New(zapcore.NewNopCore()))
+	pair, err := tokens.GenerateTokens(baseCtx, userID, "user")
+	require.NoError(t, err)
+
+	lis := bufconn.Listen(1 << 20)
+	server := grpcserver.NewServerOrchestrator(tokens, nil)
+	orchv1.RegisterOrchestratorServiceServer(server, grpcorch.NewServer(useCase))
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	ctx := metadata.AppendToOutgoingContext(baseCtx, middleware.MetadataAuthorization, "Bearer "+pair.AccessToken)
+	return orchv1.NewOrchestratorServiceClient(conn), ctx
+}
+
+// drain читает поток до конца и возвращает полученные страницы.
+func drain(t *testing.T, stream grpc.ServerStreamingClient[orchv1.ListCalculationsResponse]) ([]*orchv1.ListCalculationsResponse, error) {
+	t.Helper()
+
+	var pages []*orchv1.ListCalculationsResponse
+	for {
+		page, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return pages, nil
+		}
+		if err != nil {
+			return pages, err
+		}
+		pages = append(pages, page)
+	}
+}
+
+func TestListCalculationsStream(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("All pages arrive in order", func(t *testing.T) {
+		useCase := &pagedUseCase{calculations: newCalculations(userID, 7)}
+		client, ctx := newStreamClient(t, useCase, userID)
+
+		stream, err := client.ListCalculationsStream(ctx, &orchv1.ListCalculationsRequest{Limit: 3})
+		require.NoError(t, err)
+		pages, err := drain(t, stream)
+		require.NoError(t, err)
+
+		require.Len(t, pages, 3)
+		var ids []string
+		for i, page := range pages {
+			assert.Equal(t, int32(7), page.GetTotal())
+			assert.Equal(t, int32(3), page.GetLimit())
+			assert.Equal(t, int32(i*3), page.GetOffset())
+			for _, calc := range page.GetCalculations() {
+				ids = append(ids, calc.GetId())
+			}
+		}
+		assert.Len(t, pages[2].GetCalculations(), 1)
+
+		expected := make([]string, len(useCase.calculations))
+		for i, calc := range useCase.calculations {
+			expected[i] = calc.ID.String()
+		}
+		assert.Equal(t, expected, ids)
+		assert.Equal(t, []int{0, 3, 6}, useCase.requestedOffsets())
+	})
+
+	t.Run("Full last page ends the stream", func(t *testing.T) {
+		useCase := &pagedUseCase{calculations: newCalculations(userID, 6)}
+		client, ctx := newStreamClient(t, useCase, userID)
+
+		stream, err := client.ListCalculationsStream(ctx, &orchv1.ListCalculationsRequest{Limit: 3, Offset: 0})
+		require.NoError(t, err)
+		pages, err := drain(t, stream)
+		require.NoError(t, err)
+
+		require.Len(t, pages, 2)
+		assert.Equal(t, []int{0, 3}, useCase.requestedOffsets(), "no request past the total")
+	})
+
+	t.Run("Offset skips first calculations", func(t *testing.T) {
+		useCase := &pagedUseCase{calculations: newCalculations(userID, 5)}
+		client, ctx := newStreamClient(t, useCase, userID)
+
+		stream, err := client.ListCalculationsStream(ctx, &orchv1.ListCalculationsRequest{Limit: 2, Offset: 1})
+		require.NoError(t, err)
+		pages, err := drain(t, stream)
+		require.NoError(t, err)
+
+		require.Len(t, pages, 2)
+		assert.Equal(t, useCase.calculations[1].ID.String(), pages[0].GetCalculations()[0].GetId())
+		assert.Equal(t, useCase.calculations[4].ID.String(), pages[1].GetCalculations()[1].GetId())
+	})
+
+	t.Run("Empty list sends one empty page", func(t *testing.T) {
+		client, ctx := newStreamClient(t, &pagedUseCase{}, userID)
+
+		stream, err := client.ListCalculationsStream(ctx, &orchv1.ListCalculationsRequest{})
+		require.NoError(t, err)
+		pages, err := drain(t, stream)
+		require.NoError(t, err)
+
+		require.Len(t, pages, 1)
+		assert.Empty(t, pages[0].GetCalculations())
+		assert.Zero(t, pages[0].GetTotal())
+	})
+
+	t.Run("Invalid pagination", func(t *testing.T) {
+		client, ctx := newStreamClient(t, &pagedUseCase{}, userID)
+
+		stream, err := client.ListCalculationsStream(ctx, &orchv1.ListCalculationsRequest{Limit: -1})
+		require.NoError(t, err)
+		_, err = drain(t, stream)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
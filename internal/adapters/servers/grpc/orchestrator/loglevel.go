@@ -0,0 +1,67 @@
+package orchestrator
+
+import (
+	"context"
+
+	orchv1 "github.com/flexer2006/y.lms-final-task-calc-go/pkg/api/proto/v1/orchestrator"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	opSetLogLevel = "OrchestratorServer.SetLogLevel"
+
+	msgInvalidLogLevel = "Invalid log level"
+	msgLogLevelChanged = "Log level changed by administrator"
+
+	errInvalidLogLevel    = "log level must be one of debug, info, warn, error, fatal"
+	errLogLevelNotEnabled = "log level control is not configured"
+)
+
+// SetServiceLogger задает журнал сервиса, уровень которого читают и меняют GetLogLevel
+// и SetLogLevel. Уровень общий для всех журналов, созданных из него через With.
+func (s *Server) SetServiceLogger(log logger.Logger) {
+	s.serviceLogger = log
+}
+
+// requireServiceLogger проверяет, что журнал сервиса задан. Роль администратора
+// проверяет перехватчик сервера, см. middleware.UnaryServerAdmin.
+func (s *Server) requireServiceLogger() error {
+	if s.serviceLogger == nil {
+		return newGRPCError(codes.Unavailable, errLogLevelNotEnabled)
+	}
+	return nil
+}
+
+// GetLogLevel возвращает текущий уровень журнала сервиса оркестрации.
+func (s *Server) GetLogLevel(_ context.Context, _ *orchv1.GetLogLevelRequest) (*orchv1.LogLevelResponse, error) {
+	if err := s.requireServiceLogger(); err != nil {
+		return nil, err
+	}
+
+	return &orchv1.LogLevelResponse{Level: logger.LevelName(s.serviceLogger.GetLevel())}, nil
+}
+
+// SetLogLevel меняет уровень журнала сервиса оркестрации без перезапуска.
+func (s *Server) SetLogLevel(ctx context.Context, req *orchv1.SetLogLevelRequest) (*orchv1.LogLevelResponse, error) {
+	if err := s.requireServiceLogger(); err != nil {
+		return nil, err
+	}
+
+	log := logger.ContextLogger(ctx, nil).With(zap.String(fieldOp, opSetLogLevel))
+
+	lvl, err := logger.ParseLevel(req.GetLevel())
+	if err != nil {
+		log.Warn(msgInvalidLogLevel, zap.Error(err))
+		return nil, newGRPCError(codes.InvalidArgument, errInvalidLogLevel)
+	}
+
+	previous := s.serviceLogger.GetLevel()
+	s.serviceLogger.SetLevel(lvl)
+	s.serviceLogger.Warn(msgLogLevelChanged,
+		zap.String("from", logger.LevelName(previous)),
+		zap.String("to", logger.LevelName(lvl)))
+
+	return &orchv1.LogLevelResponse{Level: logger.LevelName(s.serviceLogger.GetLevel())}, nil
+}
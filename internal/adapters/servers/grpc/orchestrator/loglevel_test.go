@@ -0,0 +1,122 @@
+package orchestrator_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/grpc/middleware"
+	grpcorch "github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/grpc/orchestrator"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/services/jwt"
+	orchv1 "github.com/flexer2006/y.lms-final-task-calc-go/pkg/api/proto/v1/orchestrator"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestLogLevel(t *testing.T) {
+	observed, logs := observer.New(zapcore.DebugLevel)
+	serviceLogger := logger.New(observed)
+	baseCtx := logger.WithLogger(context.Background(), logger.New(zapcore.NewNopCore()))
+
+	tokens := jwt.NewService("test-secret-key-for-log-level", time.Minute, time.Hour)
+	adminID, userID := uuid.New(), uuid.New()
+
+	server := grpcorch.NewServer(nil)
+	server.SetServiceLogger(serviceLogger)
+
+	const fullMethod = orchv1.OrchestratorService_SetLogLevel_FullMethodName
+	admin := middleware.UnaryServerAdmin([]string{adminID.String(), "not-a-uuid"}, fullMethod)
+
+	// call выполняет метод сервера за проверкой токена и роли, как это делает gRPC сервер оркестрации
+	call := func(t *testing.T, caller uuid.UUID, method func(context.Context) (*orchv1.LogLevelResponse, error)) (*orchv1.LogLevelResponse, error) {
+		t.Helper()
+
+		pair, err := tokens.GenerateTokens(baseCtx, caller, "user")
+		require.NoError(t, err)
+		ctx := metadata.NewIncomingContext(baseCtx, metadata.Pairs(middleware.MetadataAuthorization, "Bearer "+pair.AccessToken))
+		info := &grpc.UnaryServerInfo{FullMethod: fullMethod}
+
+		resp, err := middleware.UnaryServerAuth(tokens)(ctx, nil, info,
+			func(ctx context.Context, req any) (any, error) {
+				return admin(ctx, req, info, func(ctx context.Context, _ any) (any, error) {
+					return method(ctx)
+				})
+			})
+		if err != nil {
+			return nil, err
+		}
+		return resp.(*orchv1.LogLevelResponse), nil
+	}
+	setLevel := func(level string) func(context.Context) (*orchv1.LogLevelResponse, error) {
+		return func(ctx context.Context) (*orchv1.LogLevelResponse, error) {
+			return server.SetLogLevel(ctx, &orchv1.SetLogLevelRequest{Level: level})
+		}
+	}
+	getLevel := func(ctx context.Context) (*orchv1.LogLevelResponse, error) {
+		return server.GetLogLevel(ctx, &orchv1.GetLogLevelRequest{})
+	}
+
+	resp, err := call(t, adminID, getLevel)
+	require.NoError(t, err)
+	assert.Equal(t, "debug", resp.GetLevel())
+
+	t.Run("Admin raises the level", func(t *testing.T) {
+		resp, err := call(t, adminID, setLevel("error"))
+		require.NoError(t, err)
+		assert.Equal(t, "error", resp.GetLevel())
+
+		logs.TakeAll()
+		serviceLogger.Debug("debug entry")
+		serviceLogger.Warn("warn entry")
+		serviceLogger.Error("error entry")
+
+		entries := logs.TakeAll()
+		require.Len(t, entries, 1)
+		assert.Equal(t, "error entry", entries[0].Message)
+	})
+
+	t.Run("Admin lowers the level", func(t *testing.T) {
+		resp, err := call(t, adminID, setLevel("info"))
+		require.NoError(t, err)
+		assert.Equal(t, "info", resp.GetLevel())
+
+		logs.TakeAll()
+		serviceLogger.Debug("debug entry")
+		serviceLogger.Info("info entry")
+
+		entries := logs.TakeAll()
+		require.Len(t, entries, 1)
+		assert.Equal(t, "info entry", entries[0].Message)
+	})
+
+	t.Run("Non-admin is rejected", func(t *testing.T) {
+		_, err := call(t, userID, setLevel("debug"))
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+		_, err = call(t, userID, getLevel)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		assert.Equal(t, zapcore.InfoLevel, serviceLogger.RawLogger().Level())
+	})
+
+	t.Run("Unknown level", func(t *testing.T) {
+		_, err := call(t, adminID, setLevel("verbose"))
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("Without service logger", func(t *testing.T) {
+		unconfigured := grpcorch.NewServer(nil)
+
+		_, err := call(t, adminID, func(ctx context.Context) (*orchv1.LogLevelResponse, error) {
+			return unconfigured.GetLogLevel(ctx, &orchv1.GetLogLevelRequest{})
+		})
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	})
+}
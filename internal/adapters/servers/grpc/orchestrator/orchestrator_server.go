@@ -2,25 +2,33 @@ package orchestrator
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/grpc/middleware"
+	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/agent"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/system"
 	orchapi "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/api/orchestrator"
 	orchv1 "github.com/flexer2006/y.lms-final-task-calc-go/pkg/api/proto/v1/orchestrator"
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
-	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 const (
 	fieldOp            = "op"
 	fieldCalculationID = "calculation_id"
+	fieldOperationID   = "operation_id"
 	fieldCount         = "count"
+	fieldSource        = "source"
+	fieldPriority      = "priority"
+	fieldTotal         = "total"
 
 	msgEmptyExpression      = "Empty expression provided"
 	msgEmptyCalculationID   = "Empty calculation ID provided"
@@ -28,26 +36,101 @@ const (
 	msgFailedGetUserID      = "Failed to get user ID"
 	msgCalcNotFound         = "Calculation not found"
 	msgCalcListSuccess      = "Calculations list retrieved successfully"
-
-	errExpressionEmpty = "expression cannot be empty"
-	errCalcIDEmpty     = "calculation ID cannot be empty"
-	errInvalidCalcID   = "invalid calculation ID"
-	errCalcNotFound    = "calculation not found"
-	errCalcFailed      = "failed to calculate expression"
-	errGetCalcFailed   = "failed to get calculation"
-	errListCalcFailed  = "failed to list calculations"
-	errMissingMetadata = "missing metadata"
-	errMissingUserID   = "missing user ID"
-	errInvalidUserID   = "invalid user ID"
-
-	opCalculate        = "OrchestratorServer.Calculate"
-	opGetCalculation   = "OrchestratorServer.GetCalculation"
-	opListCalculations = "OrchestratorServer.ListCalculations"
+	msgCalcListStreamed     = "Calculations list streamed successfully"
+	msgInvalidSource        = "Invalid calculation source"
+	msgInvalidPriority      = "Invalid calculation priority"
+	msgInvalidIdempotency   = "Invalid idempotency key"
+	msgUnsupportedOperation = "Expression contains unsupported operation"
+	msgInvalidListFilter    = "Invalid calculations list filter"
+	msgCalcAccessDenied     = "Access to calculation denied"
+	msgCalcNotCancellable   = "Calculation cannot be cancelled"
+	msgCalcCancelled        = "Calculation cancelled successfully"
+	msgCalcDeleted          = "Calculation deleted successfully"
+	msgParseTimeout         = "Expression parsing timed out"
+	msgInvalidComparison    = "Invalid comparison request"
+	msgInvalidPreview       = "Invalid expression preview request"
+	msgPoolUnavailable      = "Agent pool is not available"
+	msgInvalidReference     = "Invalid calculation reference"
+	msgStreamFinished       = "Calculation stream finished"
+	msgStreamClosed         = "Calculation stream closed by client"
+	msgResultStatsSuccess   = "Calculation result stats retrieved successfully"
+	msgAgentNotFound        = "Agent not found"
+	msgInvalidCapacity      = "Invalid agent capacity"
+	msgPendingOpsBudget     = "Pending operations budget exceeded"
+	msgDatabaseUnavailable  = "Rejecting calculation while database is unavailable"
+	msgEmptyOperationID     = "Empty operation ID provided"
+	msgInvalidOperationID   = "Invalid operation ID"
+	msgOperationNotFound    = "Operation not found"
+	msgOperationDenied      = "Access to operation denied"
+
+	errExpressionEmpty     = "expression cannot be empty"
+	errCalcIDEmpty         = "calculation ID cannot be empty"
+	errInvalidCalcID       = "invalid calculation ID"
+	errCalcNotFound        = "calculation not found"
+	errCalcFailed          = "failed to calculate expression"
+	errGetCalcFailed       = "failed to get calculation"
+	errListCalcFailed      = "failed to list calculations"
+	errSendPageFailed      = "failed to send calculations page"
+	errResultStatsFailed   = "failed to get calculation result stats"
+	errMissingUserID       = "missing user ID"
+	errInvalidSource       = "invalid calculation source"
+	errInvalidPriority     = "invalid calculation priority"
+	errInvalidIdempotency  = "invalid idempotency key"
+	errUnsupportedOp       = "unsupported operation type"
+	errCalcAccessDenied    = "access to calculation denied"
+	errCalcNotCancellable  = "calculation cannot be cancelled in its current status"
+	errCancelCalcFailed    = "failed to cancel calculation"
+	errDeleteCalcFailed    = "failed to delete calculation"
+	errParseTimeout        = "expression parsing timed out"
+	errCompareFailed       = "failed to compare expressions"
+	errDiffFailed          = "failed to diff calculations"
+	errPreviewFailed       = "failed to preview expression"
+	errValidateFailed      = "failed to validate expression"
+	errPoolStatsFailed     = "failed to get agent pool stats"
+	errSystemStatsFailed   = "failed to get system stats"
+	errTimingProfileFailed = "failed to get agent timing profile"
+	errPoolUnavailable     = "agent pool is not available"
+	errStreamCalcFailed    = "failed to stream calculation"
+	errReferenceNotFound   = "referenced calculation not found"
+	errReferenceDenied     = "access to referenced calculation denied"
+	errReferenceIncomplete = "referenced calculation is not completed"
+	errTooManyReferences   = "too many calculation references in expression"
+	errAgentIDEmpty        = "agent ID cannot be empty"
+	errAgentNotFound       = "agent not found"
+	errInvalidCapacity     = "invalid agent capacity"
+	errSetCapacityFailed   = "failed to set agent capacity"
+	errPendingOpsBudget    = "pending operations budget exceeded"
+	errDatabaseUnavailable = "database is unavailable"
+	errOperationIDEmpty    = "operation ID cannot be empty"
+	errInvalidOperationID  = "invalid operation ID"
+	errOperationNotFound   = "operation not found"
+	errOperationDenied     = "access to operation denied"
+	errGetOperationFailed  = "failed to get operation"
+
+	opCalculate         = "OrchestratorServer.Calculate"
+	opGetCalculation    = "OrchestratorServer.GetCalculation"
+	opListCalculations  = "OrchestratorServer.ListCalculations"
+	opListCalcStream    = "OrchestratorServer.ListCalculationsStream"
+	opGetResultStats    = "OrchestratorServer.GetResultStats"
+	opStreamCalculation = "OrchestratorServer.StreamCalculation"
+	opCancelCalculation = "OrchestratorServer.CancelCalculation"
+	opDeleteCalculation = "OrchestratorServer.DeleteCalculation"
+	opCompare           = "OrchestratorServer.CompareExpressions"
+	opDiff              = "OrchestratorServer.DiffCalculations"
+	opPreview           = "OrchestratorServer.PreviewExpression"
+	opValidate          = "OrchestratorServer.ValidateExpression"
+	opGetPoolStats      = "OrchestratorServer.GetPoolStats"
+	opGetSystemStats    = "OrchestratorServer.GetSystemStats"
+	opGetTimingProfile  = "OrchestratorServer.GetTimingProfile"
+	opSetAgentCapacity  = "OrchestratorServer.SetAgentCapacity"
+	opAdminGetCalc      = "OrchestratorServer.AdminGetCalculation"
+	opGetOperation      = "OrchestratorServer.GetOperation"
 )
 
 type Server struct {
 	orchv1.UnimplementedOrchestratorServiceServer
 	calculationUseCase orchapi.UseCaseCalculation
+	serviceLogger      logger.Logger
 }
 
 func NewServer(calculationUseCase orchapi.UseCaseCalculation) *Server {
@@ -60,22 +143,17 @@ func newGRPCError(code codes.Code, msg string) error {
 	return fmt.Errorf("gRPC error: %w", status.Error(code, msg))
 }
 
+// newDomainError создает ошибку gRPC для доменной ошибки cause и передает ее код в деталях ErrorInfo.
+func newDomainError(code codes.Code, msg string, cause error) error {
+	return fmt.Errorf("gRPC error: %w", middleware.StatusError(code, msg, cause))
+}
+
+// getUserID возвращает ID пользователя из проверенного токена доступа.
 func getUserID(ctx context.Context) (uuid.UUID, error) {
-	md, ok := metadata.FromIncomingContext(ctx)
+	userID, ok := middleware.UserIDFromContext(ctx)
 	if !ok {
-		return uuid.Nil, newGRPCError(codes.Unauthenticated, errMissingMetadata)
-	}
-
-	values := md.Get("user_id")
-	if len(values) == 0 {
 		return uuid.Nil, newGRPCError(codes.Unauthenticated, errMissingUserID)
 	}
-
-	userID, err := uuid.Parse(values[0])
-	if err != nil {
-		return uuid.Nil, newGRPCError(codes.Unauthenticated, errInvalidUserID)
-	}
-
 	return userID, nil
 }
 
@@ -84,7 +162,7 @@ func (s *Server) Calculate(ctx context.Context, req *orchv1.CalculateRequest) (*
 
 	if req.GetExpression() == "" {
 		log.Warn(msgEmptyExpression)
-		return nil, newGRPCError(codes.InvalidArgument, errExpressionEmpty)
+		return nil, newDomainError(codes.InvalidArgument, errExpressionEmpty, domainerrors.ErrInvalidExpression)
 	}
 
 	userID, err := getUserID(ctx)
@@ -93,17 +171,61 @@ func (s *Server) Calculate(ctx context.Context, req *orchv1.CalculateRequest) (*
 		return nil, err
 	}
 
-	calculation, err := s.calculationUseCase.CalculateExpression(ctx, userID, req.GetExpression())
+	source := orchestrator.CalculationSource(req.GetSource())
+	ctx = orchestrator.WithClientVersion(ctx, req.GetClientVersion())
+	ctx = orchestrator.WithPriority(ctx, int(req.GetPriority()))
+	if key := req.GetIdempotencyKey(); key != "" {
+		ctx = orchestrator.WithIdempotencyKey(ctx, key)
+	}
+
+	calculation, err := s.calculationUseCase.CalculateExpression(ctx, userID, req.GetExpression(), source)
 	if err != nil {
+		if errors.Is(err, domainerrors.ErrInvalidSource) {
+			log.Warn(msgInvalidSource, zap.String(fieldSource, req.GetSource()))
+			return nil, newDomainError(codes.InvalidArgument, errInvalidSource, err)
+		}
+		if errors.Is(err, domainerrors.ErrInvalidPriority) {
+			log.Warn(msgInvalidPriority, zap.Int32(fieldPriority, req.GetPriority()))
+			return nil, newDomainError(codes.InvalidArgument, errInvalidPriority, err)
+		}
+		if errors.Is(err, domainerrors.ErrInvalidIdempotencyKey) {
+			log.Warn(msgInvalidIdempotency, zap.Error(err))
+			return nil, newDomainError(codes.InvalidArgument, errInvalidIdempotency, err)
+		}
+		if errors.Is(err, domainerrors.ErrUnsupportedOp) {
+			log.Warn(msgUnsupportedOperation, zap.Error(err))
+			return nil, newDomainError(codes.InvalidArgument, errUnsupportedOp, err)
+		}
+		if errors.Is(err, domainerrors.ErrParseTimeout) {
+			log.Warn(msgParseTimeout)
+			return nil, newDomainError(codes.DeadlineExceeded, errParseTimeout, err)
+		}
+		if errors.Is(err, domainerrors.ErrPendingOpsBudget) {
+			log.Warn(msgPendingOpsBudget, zap.Error(err))
+			return nil, newDomainError(codes.ResourceExhausted, errPendingOpsBudget, err)
+		}
+		if errors.Is(err, domainerrors.ErrDatabaseUnavailable) {
+			log.Warn(msgDatabaseUnavailable, zap.Error(err))
+			return nil, newDomainError(codes.Unavailable, errDatabaseUnavailable, err)
+		}
+		if refErr := mapReferenceError(err); refErr != nil {
+			log.Warn(msgInvalidReference, zap.Error(err))
+			return nil, refErr
+		}
 		log.Error(errCalcFailed, zap.Error(err))
 		return nil, newGRPCError(codes.Internal, errCalcFailed)
 	}
 
 	return &orchv1.CalculateResponse{
-		Id:           calculation.ID.String(),
-		Status:       mapCalculationStatusToProto(calculation.Status),
-		Result:       calculation.Result,
-		ErrorMessage: calculation.ErrorMessage,
+		Id:                  calculation.ID.String(),
+		Status:              mapCalculationStatusToProto(calculation.Status),
+		Result:              calculation.Result,
+		DisplayResult:       calculation.DisplayResult,
+		Warnings:            calculation.Warnings,
+		ErrorMessage:        calculation.ErrorMessage,
+		Source:              string(calculation.Source),
+		EstimatedDurationMs: calculation.EstimatedDuration.Milliseconds(),
+		LikelyAgents:        calculation.LikelyAgents,
 	}, nil
 }
 
@@ -132,19 +254,82 @@ func (s *Server) GetCalculation(ctx context.Context, req *orchv1.GetCalculationR
 
 	calculation, err := s.calculationUseCase.GetCalculation(ctx, calculationID, userID)
 	if err != nil {
-		log.Error(errGetCalcFailed, zap.Error(err))
-		return nil, newGRPCError(codes.Internal, errGetCalcFailed)
+		switch {
+		case errors.Is(err, domainerrors.ErrCalculationNotFound):
+			return nil, newDomainError(codes.NotFound, errCalcNotFound, err)
+		case errors.Is(err, domainerrors.ErrUnauthorizedAccess):
+			return nil, newDomainError(codes.PermissionDenied, errCalcAccessDenied, err)
+		default:
+			log.Error(errGetCalcFailed, zap.Error(err))
+			return nil, newGRPCError(codes.Internal, errGetCalcFailed)
+		}
 	}
 
 	if calculation == nil {
 		log.Warn(msgCalcNotFound)
-		return nil, newGRPCError(codes.NotFound, errCalcNotFound)
+		return nil, newDomainError(codes.NotFound, errCalcNotFound, domainerrors.ErrCalculationNotFound)
 	}
 
 	return mapCalculationToProtoResponse(calculation), nil
 }
 
-func (s *Server) ListCalculations(ctx context.Context, _ *emptypb.Empty) (*orchv1.ListCalculationsResponse, error) {
+// StreamCalculation отправляет клиенту изменения статуса вычисления, пока оно
+// не завершится или клиент не отключится.
+func (s *Server) StreamCalculation(req *orchv1.StreamCalculationRequest, stream grpc.ServerStreamingServer[orchv1.CalculationEvent]) error {
+	ctx := stream.Context()
+	log := logger.ContextLogger(ctx, nil).With(
+		zap.String(fieldOp, opStreamCalculation),
+		zap.String(fieldCalculationID, req.GetId()),
+	)
+
+	if req.GetId() == "" {
+		log.Warn(msgEmptyCalculationID)
+		return newGRPCError(codes.InvalidArgument, errCalcIDEmpty)
+	}
+
+	userID, err := getUserID(ctx)
+	if err != nil {
+		log.Warn(msgFailedGetUserID, zap.Error(err))
+		return err
+	}
+
+	calculationID, err := uuid.Parse(req.GetId())
+	if err != nil {
+		log.Warn(msgInvalidCalculationID, zap.Error(err))
+		return newGRPCError(codes.InvalidArgument, errInvalidCalcID)
+	}
+
+	events, err := s.calculationUseCase.SubscribeCalculation(ctx, calculationID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domainerrors.ErrCalculationNotFound):
+			log.Warn(msgCalcNotFound)
+			return newDomainError(codes.NotFound, errCalcNotFound, err)
+		case errors.Is(err, domainerrors.ErrUnauthorizedAccess):
+			log.Warn(msgCalcAccessDenied)
+			return newDomainError(codes.PermissionDenied, errCalcAccessDenied, err)
+		default:
+			log.Error(errStreamCalcFailed, zap.Error(err))
+			return newGRPCError(codes.Internal, errStreamCalcFailed)
+		}
+	}
+
+	for event := range events {
+		if err := stream.Send(mapCalculationEventToProto(event)); err != nil {
+			return fmt.Errorf("sending calculation event: %w", err)
+		}
+	}
+
+	if ctx.Err() != nil {
+		log.Debug(msgStreamClosed)
+		return nil
+	}
+
+	log.Debug(msgStreamFinished)
+	return nil
+}
+
+func (s *Server) ListCalculations(ctx context.Context, req *orchv1.ListCalculationsRequest) (*orchv1.ListCalculationsResponse, error) {
 	log := logger.ContextLogger(ctx, nil).With(zap.String(fieldOp, opListCalculations))
 
 	userID, err := getUserID(ctx)
@@ -153,24 +338,505 @@ func (s *Server) ListCalculations(ctx context.Context, _ *emptypb.Empty) (*orchv
 		return nil, err
 	}
 
-	calculations, err := s.calculationUseCase.ListCalculations(ctx, userID)
+	filter := orchestrator.CalculationFilter{
+		Limit:  int(req.GetLimit()),
+		Offset: int(req.GetOffset()),
+		Status: orchestrator.CalculationStatus(req.GetStatus()),
+	}
+
+	page, err := s.calculationUseCase.ListCalculations(ctx, userID, filter)
+	if err != nil {
+		return nil, listCalculationsError(log, err)
+	}
+
+	log.Info(msgCalcListSuccess, zap.Int(fieldCount, len(page.Calculations)), zap.Int(fieldTotal, page.Total))
+	return mapCalculationPageToProto(page), nil
+}
+
+// ListCalculationsStream отправляет вычисления пользователя по страницам размером req.Limit,
+// начиная с req.Offset. Каждая страница запрашивается у хранилища отдельно, когда предыдущая
+// уже отправлена; поток завершается после неполной страницы или страницы, на которой
+// достигнуто общее количество. Первая страница отправляется всегда, даже пустая, чтобы клиент
+// получил общее количество.
+func (s *Server) ListCalculationsStream(req *orchv1.ListCalculationsRequest, stream grpc.ServerStreamingServer[orchv1.ListCalculationsResponse]) error {
+	ctx := stream.Context()
+	log := logger.ContextLogger(ctx, nil).With(zap.String(fieldOp, opListCalcStream))
+
+	userID, err := getUserID(ctx)
 	if err != nil {
-		log.Error(errListCalcFailed, zap.Error(err))
-		return nil, newGRPCError(codes.Internal, errListCalcFailed)
+		log.Warn(msgFailedGetUserID, zap.Error(err))
+		return err
+	}
+
+	filter := orchestrator.CalculationFilter{
+		Limit:  int(req.GetLimit()),
+		Offset: int(req.GetOffset()),
+		Status: orchestrator.CalculationStatus(req.GetStatus()),
 	}
 
+	sent := 0
+	for {
+		page, err := s.calculationUseCase.ListCalculations(ctx, userID, filter)
+		if err != nil {
+			return listCalculationsError(log, err)
+		}
+
+		if err := stream.Send(mapCalculationPageToProto(page)); err != nil {
+			log.Debug(errSendPageFailed, zap.Error(err))
+			return fmt.Errorf("%s: %w", errSendPageFailed, err)
+		}
+		sent += len(page.Calculations)
+
+		next := page.Offset + len(page.Calculations)
+		if len(page.Calculations) < page.Limit || next >= page.Total {
+			break
+		}
+		filter.Limit = page.Limit
+		filter.Offset = next
+	}
+
+	log.Info(msgCalcListStreamed, zap.Int(fieldCount, sent))
+	return nil
+}
+
+// listCalculationsError преобразует ошибку получения страницы вычислений в статус gRPC.
+func listCalculationsError(log logger.Logger, err error) error {
+	if errors.Is(err, domainerrors.ErrInvalidPagination) || errors.Is(err, domainerrors.ErrInvalidStatusFilter) {
+		log.Warn(msgInvalidListFilter, zap.Error(err))
+		return newDomainError(codes.InvalidArgument, err.Error(), err)
+	}
+	log.Error(errListCalcFailed, zap.Error(err))
+	return newGRPCError(codes.Internal, errListCalcFailed)
+}
+
+func mapCalculationPageToProto(page *orchestrator.CalculationPage) *orchv1.ListCalculationsResponse {
 	response := &orchv1.ListCalculationsResponse{
-		Calculations: make([]*orchv1.GetCalculationResponse, len(calculations)),
+		Calculations: make([]*orchv1.GetCalculationResponse, len(page.Calculations)),
+		Total:        int32(page.Total),  //nolint:gosec
+		Limit:        int32(page.Limit),  //nolint:gosec
+		Offset:       int32(page.Offset), //nolint:gosec
 	}
 
-	for i, calc := range calculations {
+	for i, calc := range page.Calculations {
 		response.Calculations[i] = mapCalculationToProtoResponse(calc)
 	}
+	return response
+}
+
+func (s *Server) GetResultStats(ctx context.Context, _ *orchv1.GetResultStatsRequest) (*orchv1.GetResultStatsResponse, error) {
+	log := logger.ContextLogger(ctx, nil).With(zap.String(fieldOp, opGetResultStats))
+
+	userID, err := getUserID(ctx)
+	if err != nil {
+		log.Warn(msgFailedGetUserID, zap.Error(err))
+		return nil, err
+	}
+
+	stats, err := s.calculationUseCase.GetResultStats(ctx, userID)
+	if err != nil {
+		log.Error(errResultStatsFailed, zap.Error(err))
+		return nil, newGRPCError(codes.Internal, errResultStatsFailed)
+	}
+
+	log.Info(msgResultStatsSuccess, zap.Int64(fieldCount, stats.Count))
+	return &orchv1.GetResultStatsResponse{
+		Count: stats.Count,
+		Sum:   stats.Sum,
+		Mean:  stats.Mean,
+		Min:   stats.Min,
+		Max:   stats.Max,
+	}, nil
+}
+
+func (s *Server) CancelCalculation(ctx context.Context, req *orchv1.CancelCalculationRequest) (*orchv1.CancelCalculationResponse, error) {
+	log := logger.ContextLogger(ctx, nil).With(
+		zap.String(fieldOp, opCancelCalculation),
+		zap.String(fieldCalculationID, req.GetId()),
+	)
+
+	if req.GetId() == "" {
+		log.Warn(msgEmptyCalculationID)
+		return nil, newGRPCError(codes.InvalidArgument, errCalcIDEmpty)
+	}
+
+	userID, err := getUserID(ctx)
+	if err != nil {
+		log.Warn(msgFailedGetUserID, zap.Error(err))
+		return nil, err
+	}
+
+	calculationID, err := uuid.Parse(req.GetId())
+	if err != nil {
+		log.Warn(msgInvalidCalculationID, zap.Error(err))
+		return nil, newGRPCError(codes.InvalidArgument, errInvalidCalcID)
+	}
+
+	if err := s.calculationUseCase.CancelCalculation(ctx, calculationID, userID); err != nil {
+		switch {
+		case errors.Is(err, domainerrors.ErrCalculationNotFound):
+			log.Warn(msgCalcNotFound)
+			return nil, newDomainError(codes.NotFound, errCalcNotFound, err)
+		case errors.Is(err, domainerrors.ErrUnauthorizedAccess):
+			log.Warn(msgCalcAccessDenied)
+			return nil, newDomainError(codes.PermissionDenied, errCalcAccessDenied, err)
+		case errors.Is(err, domainerrors.ErrCalcNotCancellable):
+			log.Warn(msgCalcNotCancellable, zap.Error(err))
+			return nil, newDomainError(codes.FailedPrecondition, errCalcNotCancellable, err)
+		default:
+			log.Error(errCancelCalcFailed, zap.Error(err))
+			return nil, newGRPCError(codes.Internal, errCancelCalcFailed)
+		}
+	}
+
+	log.Info(msgCalcCancelled)
+	return &orchv1.CancelCalculationResponse{
+		Id:     calculationID.String(),
+		Status: orchv1.CalculationStatus_CANCELLED,
+	}, nil
+}
+
+func (s *Server) DeleteCalculation(ctx context.Context, req *orchv1.DeleteCalculationRequest) (*orchv1.DeleteCalculationResponse, error) {
+	log := logger.ContextLogger(ctx, nil).With(
+		zap.String(fieldOp, opDeleteCalculation),
+		zap.String(fieldCalculationID, req.GetId()),
+	)
+
+	if req.GetId() == "" {
+		log.Warn(msgEmptyCalculationID)
+		return nil, newGRPCError(codes.InvalidArgument, errCalcIDEmpty)
+	}
+
+	userID, err := getUserID(ctx)
+	if err != nil {
+		log.Warn(msgFailedGetUserID, zap.Error(err))
+		return nil, err
+	}
+
+	calculationID, err := uuid.Parse(req.GetId())
+	if err != nil {
+		log.Warn(msgInvalidCalculationID, zap.Error(err))
+		return nil, newGRPCError(codes.InvalidArgument, errInvalidCalcID)
+	}
+
+	if err := s.calculationUseCase.DeleteCalculation(ctx, calculationID, userID); err != nil {
+		switch {
+		case errors.Is(err, domainerrors.ErrCalculationNotFound):
+			log.Warn(msgCalcNotFound)
+			return nil, newDomainError(codes.NotFound, errCalcNotFound, err)
+		case errors.Is(err, domainerrors.ErrUnauthorizedAccess):
+			log.Warn(msgCalcAccessDenied)
+			return nil, newDomainError(codes.PermissionDenied, errCalcAccessDenied, err)
+		default:
+			log.Error(errDeleteCalcFailed, zap.Error(err))
+			return nil, newGRPCError(codes.Internal, errDeleteCalcFailed)
+		}
+	}
+
+	log.Info(msgCalcDeleted)
+	return &orchv1.DeleteCalculationResponse{
+		Id: calculationID.String(),
+	}, nil
+}
+
+func (s *Server) CompareExpressions(ctx context.Context, req *orchv1.CompareExpressionsRequest) (*orchv1.CompareExpressionsResponse, error) {
+	log := logger.ContextLogger(ctx, nil).With(zap.String(fieldOp, opCompare))
+
+	comparison, err := s.calculationUseCase.CompareExpressions(ctx, req.GetExpressionA(), req.GetExpressionB(), req.GetTolerance())
+	if err != nil {
+		switch {
+		case errors.Is(err, domainerrors.ErrInvalidExpression), errors.Is(err, domainerrors.ErrInvalidTolerance):
+			log.Warn(msgInvalidComparison, zap.Error(err))
+			return nil, newDomainError(codes.InvalidArgument, err.Error(), err)
+		case errors.Is(err, domainerrors.ErrParseTimeout):
+			log.Warn(msgParseTimeout)
+			return nil, newDomainError(codes.DeadlineExceeded, errParseTimeout, err)
+		default:
+			log.Error(errCompareFailed, zap.Error(err))
+			return nil, newGRPCError(codes.Internal, errCompareFailed)
+		}
+	}
+
+	return &orchv1.CompareExpressionsResponse{
+		ResultA:    comparison.ResultA,
+		ResultB:    comparison.ResultB,
+		Difference: comparison.Difference,
+		Tolerance:  comparison.Tolerance,
+		Equal:      comparison.Equal,
+	}, nil
+}
+
+func (s *Server) DiffCalculations(ctx context.Context, req *orchv1.DiffCalculationsRequest) (*orchv1.DiffCalculationsResponse, error) {
+	log := logger.ContextLogger(ctx, nil).With(
+		zap.String(fieldOp, opDiff),
+		zap.String(fieldCalculationID, req.GetId()),
+	)
+
+	if req.GetId() == "" || req.GetOtherId() == "" {
+		log.Warn(msgEmptyCalculationID)
+		return nil, newGRPCError(codes.InvalidArgument, errCalcIDEmpty)
+	}
+
+	userID, err := getUserID(ctx)
+	if err != nil {
+		log.Warn(msgFailedGetUserID, zap.Error(err))
+		return nil, err
+	}
+
+	calculationIDA, err := uuid.Parse(req.GetId())
+	if err != nil {
+		log.Warn(msgInvalidCalculationID, zap.Error(err))
+		return nil, newGRPCError(codes.InvalidArgument, errInvalidCalcID)
+	}
+
+	calculationIDB, err := uuid.Parse(req.GetOtherId())
+	if err != nil {
+		log.Warn(msgInvalidCalculationID, zap.Error(err))
+		return nil, newGRPCError(codes.InvalidArgument, errInvalidCalcID)
+	}
+
+	diff, err := s.calculationUseCase.DiffCalculations(ctx, calculationIDA, calculationIDB, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domainerrors.ErrCalculationNotFound):
+			log.Warn(msgCalcNotFound)
+			return nil, newDomainError(codes.NotFound, errCalcNotFound, err)
+		case errors.Is(err, domainerrors.ErrUnauthorizedAccess):
+			log.Warn(msgCalcAccessDenied)
+			return nil, newDomainError(codes.PermissionDenied, errCalcAccessDenied, err)
+		default:
+			log.Error(errDiffFailed, zap.Error(err))
+			return nil, newGRPCError(codes.Internal, errDiffFailed)
+		}
+	}
+
+	resp := &orchv1.DiffCalculationsResponse{
+		Id:             diff.CalculationIDA.String(),
+		OtherId:        diff.CalculationIDB.String(),
+		ExpressionA:    diff.ExpressionA,
+		ExpressionB:    diff.ExpressionB,
+		ResultA:        diff.ResultA,
+		ResultB:        diff.ResultB,
+		SameExpression: diff.SameExpression,
+		SameResult:     diff.SameResult,
+	}
+	if diff.Delta != nil {
+		resp.Numeric = true
+		resp.Delta = *diff.Delta
+	}
+	return resp, nil
+}
+
+func (s *Server) PreviewExpression(ctx context.Context, req *orchv1.PreviewExpressionRequest) (*orchv1.PreviewExpressionResponse, error) {
+	log := logger.ContextLogger(ctx, nil).With(zap.String(fieldOp, opPreview))
+
+	options := orchestrator.PreviewOptions{Spaced: req.GetSpaced()}
+
+	preview, err := s.calculationUseCase.PreviewExpression(ctx, req.GetExpression(), options)
+	if err != nil {
+		switch {
+		case errors.Is(err, domainerrors.ErrInvalidExpression):
+			log.Warn(msgInvalidPreview, zap.Error(err))
+			return nil, newDomainError(codes.InvalidArgument, err.Error(), err)
+		case errors.Is(err, domainerrors.ErrParseTimeout):
+			log.Warn(msgParseTimeout)
+			return nil, newDomainError(codes.DeadlineExceeded, errParseTimeout, err)
+		default:
+			log.Error(errPreviewFailed, zap.Error(err))
+			return nil, newGRPCError(codes.Internal, errPreviewFailed)
+		}
+	}
+
+	return &orchv1.PreviewExpressionResponse{
+		Expression: preview.Expression,
+		Grouped:    preview.Grouped,
+	}, nil
+}
+
+func (s *Server) ValidateExpression(ctx context.Context, req *orchv1.ValidateExpressionRequest) (*orchv1.ValidateExpressionResponse, error) {
+	log := logger.ContextLogger(ctx, nil).With(zap.String(fieldOp, opValidate))
+
+	validation, err := s.calculationUseCase.ValidateExpression(ctx, req.GetExpression())
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrParseTimeout) {
+			log.Warn(msgParseTimeout)
+			return nil, newDomainError(codes.DeadlineExceeded, errParseTimeout, err)
+		}
+		log.Error(errValidateFailed, zap.Error(err))
+		return nil, newGRPCError(codes.Internal, errValidateFailed)
+	}
+
+	resp := &orchv1.ValidateExpressionResponse{
+		Expression:   validation.Expression,
+		Valid:        validation.Valid,
+		ErrorMessage: validation.ErrorMessage,
+	}
+	if validation.ErrorOffset != nil {
+		resp.ErrorOffset = int32(*validation.ErrorOffset) //nolint:gosec
+	}
+	return resp, nil
+}
+
+func (s *Server) GetPoolStats(ctx context.Context, _ *orchv1.GetPoolStatsRequest) (*orchv1.GetPoolStatsResponse, error) {
+	log := logger.ContextLogger(ctx, nil).With(zap.String(fieldOp, opGetPoolStats))
+
+	stats, err := s.calculationUseCase.GetPoolStats(ctx)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrNilPool) {
+			log.Warn(msgPoolUnavailable)
+			return nil, newDomainError(codes.Unavailable, errPoolUnavailable, domainerrors.ErrPoolNotRunning)
+		}
+		log.Error(errPoolStatsFailed, zap.Error(err))
+		return nil, newGRPCError(codes.Internal, errPoolStatsFailed)
+	}
+
+	return mapPoolStatsToProto(stats), nil
+}
+
+func (s *Server) GetTimingProfile(ctx context.Context, _ *orchv1.GetTimingProfileRequest) (*orchv1.GetTimingProfileResponse, error) {
+	log := logger.ContextLogger(ctx, nil).With(zap.String(fieldOp, opGetTimingProfile))
+
+	profile, err := s.calculationUseCase.GetTimingProfile(ctx)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrNilPool) {
+			log.Warn(msgPoolUnavailable)
+			return nil, newDomainError(codes.Unavailable, errPoolUnavailable, domainerrors.ErrPoolNotRunning)
+		}
+		log.Error(errTimingProfileFailed, zap.Error(err))
+		return nil, newGRPCError(codes.Internal, errTimingProfileFailed)
+	}
+
+	return mapTimingProfileToProto(profile), nil
+}
+
+func (s *Server) SetAgentCapacity(ctx context.Context, req *orchv1.SetAgentCapacityRequest) (*orchv1.SetAgentCapacityResponse, error) {
+	log := logger.ContextLogger(ctx, nil).With(
+		zap.String(fieldOp, opSetAgentCapacity),
+		zap.String("agent_id", req.GetAgentId()))
+
+	if req.GetAgentId() == "" {
+		log.Warn(msgAgentNotFound)
+		return nil, newGRPCError(codes.InvalidArgument, errAgentIDEmpty)
+	}
+
+	stats, err := s.calculationUseCase.SetAgentCapacity(ctx, req.GetAgentId(), int(req.GetCapacity()))
+	if err != nil {
+		switch {
+		case errors.Is(err, domainerrors.ErrNilPool):
+			log.Warn(msgPoolUnavailable)
+			return nil, newDomainError(codes.Unavailable, errPoolUnavailable, domainerrors.ErrPoolNotRunning)
+		case errors.Is(err, domainerrors.ErrAgentNotFound):
+			log.Warn(msgAgentNotFound)
+			return nil, newDomainError(codes.NotFound, errAgentNotFound, err)
+		case errors.Is(err, domainerrors.ErrInvalidCapacity):
+			log.Warn(msgInvalidCapacity, zap.Error(err))
+			return nil, newDomainError(codes.InvalidArgument, errInvalidCapacity, err)
+		}
+		log.Error(errSetCapacityFailed, zap.Error(err))
+		return nil, newGRPCError(codes.Internal, errSetCapacityFailed)
+	}
+
+	return &orchv1.SetAgentCapacityResponse{Agent: mapAgentStatsToProto(*stats)}, nil
+}
+
+func (s *Server) GetSystemStats(ctx context.Context, _ *orchv1.GetSystemStatsRequest) (*orchv1.GetSystemStatsResponse, error) {
+	log := logger.ContextLogger(ctx, nil).With(zap.String(fieldOp, opGetSystemStats))
+
+	stats, err := s.calculationUseCase.GetSystemStats(ctx)
+	if err != nil {
+		log.Error(errSystemStatsFailed, zap.Error(err))
+		return nil, newGRPCError(codes.Internal, errSystemStatsFailed)
+	}
+
+	return mapSystemStatsToProto(stats), nil
+}
+
+// AdminGetCalculation возвращает вычисление любого пользователя вместе с версией клиента.
+// Доступ проверяет шлюз: метод вызывается только для администраторов.
+func (s *Server) AdminGetCalculation(ctx context.Context, req *orchv1.AdminGetCalculationRequest) (*orchv1.GetCalculationResponse, error) {
+	log := logger.ContextLogger(ctx, nil).With(
+		zap.String(fieldOp, opAdminGetCalc),
+		zap.String(fieldCalculationID, req.GetId()),
+	)
+
+	if req.GetId() == "" {
+		log.Warn(msgEmptyCalculationID)
+		return nil, newGRPCError(codes.InvalidArgument, errCalcIDEmpty)
+	}
+
+	calculationID, err := uuid.Parse(req.GetId())
+	if err != nil {
+		log.Warn(msgInvalidCalculationID, zap.Error(err))
+		return nil, newGRPCError(codes.InvalidArgument, errInvalidCalcID)
+	}
 
-	log.Info(msgCalcListSuccess, zap.Int(fieldCount, len(calculations)))
+	calculation, err := s.calculationUseCase.AdminGetCalculation(ctx, calculationID)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrCalculationNotFound) {
+			log.Warn(msgCalcNotFound)
+			return nil, newDomainError(codes.NotFound, errCalcNotFound, err)
+		}
+		log.Error(errGetCalcFailed, zap.Error(err))
+		return nil, newGRPCError(codes.Internal, errGetCalcFailed)
+	}
+
+	response := mapCalculationToProtoResponse(calculation)
+	response.ClientVersion = calculation.ClientVersion
 	return response, nil
 }
 
+// GetOperation возвращает отдельную операцию вычисления пользователя для отладки.
+func (s *Server) GetOperation(ctx context.Context, req *orchv1.GetOperationRequest) (*orchv1.GetOperationResponse, error) {
+	log := logger.ContextLogger(ctx, nil).With(
+		zap.String(fieldOp, opGetOperation),
+		zap.String(fieldOperationID, req.GetId()),
+	)
+
+	if req.GetId() == "" {
+		log.Warn(msgEmptyOperationID)
+		return nil, newGRPCError(codes.InvalidArgument, errOperationIDEmpty)
+	}
+
+	userID, err := getUserID(ctx)
+	if err != nil {
+		log.Warn(msgFailedGetUserID, zap.Error(err))
+		return nil, err
+	}
+
+	operationID, err := uuid.Parse(req.GetId())
+	if err != nil {
+		log.Warn(msgInvalidOperationID, zap.Error(err))
+		return nil, newGRPCError(codes.InvalidArgument, errInvalidOperationID)
+	}
+
+	op, err := s.calculationUseCase.GetOperation(ctx, operationID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domainerrors.ErrOperationNotFound):
+			log.Warn(msgOperationNotFound)
+			return nil, newDomainError(codes.NotFound, errOperationNotFound, err)
+		case errors.Is(err, domainerrors.ErrUnauthorizedAccess):
+			log.Warn(msgOperationDenied)
+			return nil, newDomainError(codes.PermissionDenied, errOperationDenied, err)
+		default:
+			log.Error(errGetOperationFailed, zap.Error(err))
+			return nil, newGRPCError(codes.Internal, errGetOperationFailed)
+		}
+	}
+
+	return &orchv1.GetOperationResponse{
+		Id:               op.ID.String(),
+		CalculationId:    op.CalculationID.String(),
+		OperationType:    int32(op.OperationType), //nolint:gosec
+		Operand1:         op.Operand1,
+		Operand2:         op.Operand2,
+		Result:           op.Result,
+		Status:           string(op.Status),
+		ErrorMessage:     op.ErrorMessage,
+		AgentId:          op.AgentID,
+		ProcessingTimeMs: op.ProcessingTime,
+	}, nil
+}
+
 func mapCalculationStatusToProto(status orchestrator.CalculationStatus) orchv1.CalculationStatus {
 	switch status {
 	case orchestrator.CalculationStatusPending:
@@ -181,6 +847,8 @@ func mapCalculationStatusToProto(status orchestrator.CalculationStatus) orchv1.C
 		return orchv1.CalculationStatus_COMPLETED
 	case orchestrator.CalculationStatusError:
 		return orchv1.CalculationStatus_ERROR
+	case orchestrator.CalculationStatusCancelled:
+		return orchv1.CalculationStatus_CANCELLED
 	default:
 		return orchv1.CalculationStatus_PENDING
 	}
@@ -192,13 +860,131 @@ func mapCalculationToProtoResponse(calculation *orchestrator.Calculation) *orchv
 	}
 
 	return &orchv1.GetCalculationResponse{
-		Id:           calculation.ID.String(),
-		UserId:       calculation.UserID.String(),
-		Expression:   calculation.Expression,
-		Result:       calculation.Result,
-		Status:       mapCalculationStatusToProto(calculation.Status),
-		ErrorMessage: calculation.ErrorMessage,
-		CreatedAt:    timestamppb.New(calculation.CreatedAt),
-		UpdatedAt:    timestamppb.New(calculation.UpdatedAt),
+		Id:            calculation.ID.String(),
+		UserId:        calculation.UserID.String(),
+		Expression:    calculation.Expression,
+		Result:        calculation.Result,
+		DisplayResult: calculation.DisplayResult,
+		Warnings:      calculation.Warnings,
+		Status:        mapCalculationStatusToProto(calculation.Status),
+		ErrorMessage:  calculation.ErrorMessage,
+		CreatedAt:     timestamppb.New(calculation.CreatedAt),
+		UpdatedAt:     timestamppb.New(calculation.UpdatedAt),
+		Source:        string(calculation.Source),
+	}
+}
+
+// mapReferenceError переводит ошибку подстановки calc:{uuid} в ошибку gRPC.
+// Возвращает nil, если ошибка не связана со ссылками на вычисления.
+func mapReferenceError(err error) error {
+	switch {
+	case errors.Is(err, domainerrors.ErrCalculationNotFound):
+		return newDomainError(codes.NotFound, errReferenceNotFound, err)
+	case errors.Is(err, domainerrors.ErrUnauthorizedAccess):
+		return newDomainError(codes.PermissionDenied, errReferenceDenied, err)
+	case errors.Is(err, domainerrors.ErrReferenceNotCompleted):
+		return newDomainError(codes.FailedPrecondition, errReferenceIncomplete, err)
+	case errors.Is(err, domainerrors.ErrTooManyReferences):
+		return newDomainError(codes.InvalidArgument, errTooManyReferences, err)
+	default:
+		return nil
+	}
+}
+
+func mapCalculationEventToProto(event orchestrator.CalculationEvent) *orchv1.CalculationEvent {
+	return &orchv1.CalculationEvent{
+		Id:           event.CalculationID.String(),
+		Status:       mapCalculationStatusToProto(event.Status),
+		Result:       event.Result,
+		ErrorMessage: event.ErrorMessage,
+		UpdatedAt:    timestamppb.New(event.UpdatedAt),
+	}
+}
+
+func mapSystemStatsToProto(stats *system.OrchestratorStats) *orchv1.GetSystemStatsResponse {
+	if stats == nil {
+		return &orchv1.GetSystemStatsResponse{}
+	}
+
+	byStatus := make(map[string]int64, len(stats.CalculationsByStatus))
+	for status, count := range stats.CalculationsByStatus {
+		byStatus[status] = int64(count)
+	}
+
+	return &orchv1.GetSystemStatsResponse{
+		CalculationsByStatus: byStatus,
+		TotalCalculations:    int64(stats.TotalCalculations),
+		TotalAgents:          int32(stats.TotalAgents),
+		ActiveAgents:         int32(stats.ActiveAgents),
+		PendingOperations:    int64(stats.PendingOperations),
+		DbPool: &orchv1.DBPoolStats{
+			TotalConns:        stats.DBPool.TotalConns,
+			IdleConns:         stats.DBPool.IdleConns,
+			AcquiredConns:     stats.DBPool.AcquiredConns,
+			MaxConns:          stats.DBPool.MaxConns,
+			AcquireCount:      stats.DBPool.AcquireCount,
+			AcquireDurationMs: stats.DBPool.AcquireDurationMs,
+		},
+	}
+}
+
+func mapAgentStatsToProto(a agent.AgentStats) *orchv1.AgentStats {
+	return &orchv1.AgentStats{
+		Id:          a.ID,
+		Status:      string(a.Status),
+		CurrentLoad: int32(a.CurrentLoad), //nolint:gosec
+		MaxCapacity: int32(a.MaxCapacity), //nolint:gosec
+		Completed:   a.Completed,
+		Failed:      a.Failed,
+		QueueDepth:  int32(a.QueueDepth), //nolint:gosec
+	}
+}
+
+func mapTimingProfileToProto(profile *agent.TimingProfile) *orchv1.GetTimingProfileResponse {
+	if profile == nil {
+		return &orchv1.GetTimingProfileResponse{}
+	}
+
+	agents := make([]*orchv1.AgentTiming, 0, len(profile.Agents))
+	for _, a := range profile.Agents {
+		agents = append(agents, &orchv1.AgentTiming{
+			Id:               a.ID,
+			Deterministic:    a.Deterministic,
+			OperationTimesMs: a.OperationTimesMs,
+		})
+	}
+
+	return &orchv1.GetTimingProfileResponse{
+		ConfiguredMs: profile.ConfiguredMs,
+		Agents:       agents,
+	}
+}
+
+func mapPoolStatsToProto(stats *agent.PoolStats) *orchv1.GetPoolStatsResponse {
+	if stats == nil {
+		return &orchv1.GetPoolStatsResponse{}
+	}
+
+	agents := make([]*orchv1.AgentStats, 0, len(stats.Agents))
+	for _, a := range stats.Agents {
+		agents = append(agents, mapAgentStatsToProto(a))
+	}
+
+	return &orchv1.GetPoolStatsResponse{
+		Agents:        agents,
+		TotalAgents:   int32(stats.TotalAgents),   //nolint:gosec
+		OnlineAgents:  int32(stats.OnlineAgents),  //nolint:gosec
+		BusyAgents:    int32(stats.BusyAgents),    //nolint:gosec
+		TotalLoad:     int32(stats.TotalLoad),     //nolint:gosec
+		TotalCapacity: int32(stats.TotalCapacity), //nolint:gosec
+		Completed:     stats.Completed,
+		Failed:        stats.Failed,
+		QueueDepth:    int32(stats.QueueDepth), //nolint:gosec
+		Throughput: &orchv1.PoolThroughput{
+			Dispatched: stats.Throughput.Dispatched,
+			Completed:  stats.Throughput.Completed,
+			Failed:     stats.Throughput.Failed,
+		},
+		Workers: int32(stats.Workers), //nolint:gosec
 	}
 }
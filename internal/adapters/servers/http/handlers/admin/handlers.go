@@ -0,0 +1,212 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/http/midleware"
+	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/system"
+	authAPI "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/api/auth"
+	orchAPI "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/api/orchestrator"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/servertiming"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	contentTypeJSON = "application/json"
+
+	paramAgentID       = "id"
+	paramCalculationID = "id"
+)
+
+type Handler struct {
+	authUseCase authAPI.UseCaseUser
+	calcUseCase orchAPI.UseCaseCalculation
+}
+
+func NewHandler(authUseCase authAPI.UseCaseUser, calcUseCase orchAPI.UseCaseCalculation) *Handler {
+	return &Handler{
+		authUseCase: authUseCase,
+		calcUseCase: calcUseCase,
+	}
+}
+
+type UsersStats struct {
+	Total int `json:"total"`
+}
+
+type CalculationsStats struct {
+	Total    int            `json:"total"`
+	ByStatus map[string]int `json:"by_status"`
+}
+
+type AgentsStats struct {
+	Total  int `json:"total"`
+	Active int `json:"active"`
+}
+
+type DBPoolsStats struct {
+	Auth         system.DBPoolStats `json:"auth"`
+	Orchestrator system.DBPoolStats `json:"orchestrator"`
+}
+
+// StatsResponse - сводная статистика системы, собранная из сервисов аутентификации и оркестрации.
+type StatsResponse struct {
+	Users             UsersStats        `json:"users"`
+	Calculations      CalculationsStats `json:"calculations"`
+	Agents            AgentsStats       `json:"agents"`
+	PendingOperations int               `json:"pending_operations"`
+	DBPools           DBPoolsStats      `json:"db_pools"`
+}
+
+func (h *Handler) GetStats(w http.ResponseWriter, r *http.Request) {
+	authStats, err := h.authUseCase.GetStats(r.Context())
+	if err != nil {
+		midleware.HandleError(r.Context(), w, err, http.StatusInternalServerError)
+		return
+	}
+
+	orchStats, err := h.calcUseCase.GetSystemStats(r.Context())
+	if err != nil {
+		midleware.HandleError(r.Context(), w, err, http.StatusInternalServerError)
+		return
+	}
+
+	byStatus := orchStats.CalculationsByStatus
+	if byStatus == nil {
+		byStatus = map[string]int{}
+	}
+
+	respondJSON(r.Context(), w, StatsResponse{
+		Users: UsersStats{Total: authStats.TotalUsers},
+		Calculations: CalculationsStats{
+			Total:    orchStats.TotalCalculations,
+			ByStatus: byStatus,
+		},
+		Agents: AgentsStats{
+			Total:  orchStats.TotalAgents,
+			Active: orchStats.ActiveAgents,
+		},
+		PendingOperations: orchStats.PendingOperations,
+		DBPools: DBPoolsStats{
+			Auth:         authStats.DBPool,
+			Orchestrator: orchStats.DBPool,
+		},
+	}, http.StatusOK, logger.ContextLogger(r.Context(), nil))
+}
+
+// SetAgentCapacityRequest - новая емкость агента в единицах стоимости операций.
+type SetAgentCapacityRequest struct {
+	Capacity int `json:"capacity"`
+}
+
+// SetAgentCapacity меняет емкость работающего агента без перезапуска оркестратора.
+func (h *Handler) SetAgentCapacity(w http.ResponseWriter, r *http.Request) {
+	var req SetAgentCapacityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		midleware.HandleError(r.Context(), w, err, http.StatusBadRequest)
+		return
+	}
+
+	agentID := chi.URLParam(r, paramAgentID)
+
+	stats, err := h.calcUseCase.SetAgentCapacity(r.Context(), agentID, req.Capacity)
+	if err != nil {
+		midleware.HandleError(r.Context(), w, err, setCapacityErrorStatus(err))
+		return
+	}
+
+	logger.ContextLogger(r.Context(), nil).Info("Agent capacity changed by administrator",
+		zap.String("agent_id", agentID),
+		zap.Int("capacity", stats.MaxCapacity))
+
+	respondJSON(r.Context(), w, stats, http.StatusOK, logger.ContextLogger(r.Context(), nil))
+}
+
+// GetPoolStats возвращает состояние агентов пула и счетчики выполненных операций.
+func (h *Handler) GetPoolStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.calcUseCase.GetPoolStats(r.Context())
+	if err != nil {
+		midleware.HandleError(r.Context(), w, err, midleware.ErrorStatus(err, http.StatusInternalServerError))
+		return
+	}
+
+	respondJSON(r.Context(), w, stats, http.StatusOK, logger.ContextLogger(r.Context(), nil))
+}
+
+// GetTimingProfile возвращает настроенное время выполнения операций и фактические значения,
+// применяемые каждым агентом пула с учетом детерминированного режима.
+func (h *Handler) GetTimingProfile(w http.ResponseWriter, r *http.Request) {
+	profile, err := h.calcUseCase.GetTimingProfile(r.Context())
+	if err != nil {
+		midleware.HandleError(r.Context(), w, err, midleware.ErrorStatus(err, http.StatusInternalServerError))
+		return
+	}
+
+	respondJSON(r.Context(), w, profile, http.StatusOK, logger.ContextLogger(r.Context(), nil))
+}
+
+// GetCalculation возвращает вычисление любого пользователя, включая удаленные,
+// вместе с версией клиента, которая его отправила.
+func (h *Handler) GetCalculation(w http.ResponseWriter, r *http.Request) {
+	calculationID, err := uuid.Parse(chi.URLParam(r, paramCalculationID))
+	if err != nil {
+		midleware.HandleError(r.Context(), w, err, http.StatusBadRequest)
+		return
+	}
+
+	calculation, err := h.calcUseCase.AdminGetCalculation(r.Context(), calculationID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, domainerrors.ErrCalculationNotFound) {
+			status = http.StatusNotFound
+		}
+		midleware.HandleError(r.Context(), w, err, status)
+		return
+	}
+
+	respondJSON(r.Context(), w, calculation, http.StatusOK, logger.ContextLogger(r.Context(), nil))
+}
+
+func setCapacityErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domainerrors.ErrInvalidCapacity):
+		return http.StatusBadRequest
+	case errors.Is(err, domainerrors.ErrAgentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domainerrors.ErrNilPool):
+		return http.StatusServiceUnavailable
+	default:
+		return midleware.ErrorStatus(err, http.StatusInternalServerError)
+	}
+}
+
+// respondJSON сериализует data до отправки заголовков, чтобы длительность сериализации
+// попала в заголовок Server-Timing.
+func respondJSON(ctx context.Context, w http.ResponseWriter, data any, statusCode int, log logger.Logger) {
+	start := time.Now()
+	body, err := json.Marshal(data)
+	servertiming.Since(ctx, servertiming.MetricSerialize, start)
+	if err != nil {
+		log.Error("failed to encode response to JSON",
+			zap.Error(err),
+			zap.Int("status_code", statusCode))
+		midleware.HandleError(ctx, w, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentTypeJSON)
+	w.WriteHeader(statusCode)
+	if _, err := w.Write(append(body, '\n')); err != nil {
+		log.Error("failed to write response",
+			zap.Error(err),
+			zap.Int("status_code", statusCode))
+	}
+}
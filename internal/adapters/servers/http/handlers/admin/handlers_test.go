@@ -0,0 +1,271 @@
+package admin_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	handlers "github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/http/handlers/admin"
+	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/agent"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/system"
+	authAPI "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/api/auth"
+	orchAPI "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/api/orchestrator"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+type stubAuthUseCase struct {
+	authAPI.UseCaseUser
+	stats *system.AuthStats
+	err   error
+}
+
+func (s *stubAuthUseCase) GetStats(context.Context) (*system.AuthStats, error) {
+	return s.stats, s.err
+}
+
+type stubCalcUseCase struct {
+	orchAPI.UseCaseCalculation
+	stats *system.OrchestratorStats
+	err   error
+
+	capacityErr   error
+	agentID       string
+	capacity      int
+	capacityCalls int
+
+	calculation *orchestrator.Calculation
+	calcErr     error
+
+	timing    *agent.TimingProfile
+	timingErr error
+}
+
+func (s *stubCalcUseCase) GetTimingProfile(context.Context) (*agent.TimingProfile, error) {
+	return s.timing, s.timingErr
+}
+
+func (s *stubCalcUseCase) AdminGetCalculation(_ context.Context, calculationID uuid.UUID) (*orchestrator.Calculation, error) {
+	if s.calcErr != nil {
+		return nil, s.calcErr
+	}
+	if s.calculation == nil || s.calculation.ID != calculationID {
+		return nil, domainerrors.ErrCalculationNotFound
+	}
+	return s.calculation, nil
+}
+
+func (s *stubCalcUseCase) SetAgentCapacity(_ context.Context, agentID string, capacity int) (*agent.AgentStats, error) {
+	s.capacityCalls++
+	s.agentID, s.capacity = agentID, capacity
+	if s.capacityErr != nil {
+		return nil, s.capacityErr
+	}
+	return &agent.AgentStats{ID: agentID, Status: agent.AgentStatusOnline, MaxCapacity: capacity}, nil
+}
+
+func (s *stubCalcUseCase) GetSystemStats(context.Context) (*system.OrchestratorStats, error) {
+	return s.stats, s.err
+}
+
+func getStats(t *testing.T, authUseCase authAPI.UseCaseUser, calcUseCase orchAPI.UseCaseCalculation) *httptest.ResponseRecorder {
+	t.Helper()
+
+	ctx := logger.WithLogger(context.Background(), logger.New(zapcore.NewNopCore()))
+	req := httptest.NewRequestWithContext(ctx, http.MethodGet, "/api/v1/admin/stats", nil)
+	rec := httptest.NewRecorder()
+	handlers.NewHandler(authUseCase, calcUseCase).GetStats(rec, req)
+	return rec
+}
+
+func TestGetStats(t *testing.T) {
+	authStats := &system.AuthStats{
+		TotalUsers: 42,
+		DBPool:     system.DBPoolStats{TotalConns: 3, IdleConns: 2, AcquiredConns: 1, MaxConns: 10},
+	}
+	orchStats := &system.OrchestratorStats{
+		CalculationsByStatus: map[string]int{"COMPLETED": 7, "PENDING": 2, "ERROR": 1},
+		TotalCalculations:    10,
+		TotalAgents:          4,
+		ActiveAgents:         3,
+		PendingOperations:    5,
+		DBPool:               system.DBPoolStats{TotalConns: 5, IdleConns: 1, AcquiredConns: 4, MaxConns: 20},
+	}
+
+	t.Run("Aggregates stats from both services", func(t *testing.T) {
+		rec := getStats(t, &stubAuthUseCase{stats: authStats}, &stubCalcUseCase{stats: orchStats})
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var resp handlers.StatsResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+
+		assert.Equal(t, 42, resp.Users.Total)
+		assert.Equal(t, 10, resp.Calculations.Total)
+		assert.Equal(t, orchStats.CalculationsByStatus, resp.Calculations.ByStatus)
+		assert.Equal(t, 4, resp.Agents.Total)
+		assert.Equal(t, 3, resp.Agents.Active)
+		assert.Equal(t, 5, resp.PendingOperations)
+		assert.Equal(t, authStats.DBPool, resp.DBPools.Auth)
+		assert.Equal(t, orchStats.DBPool, resp.DBPools.Orchestrator)
+	})
+
+	t.Run("Auth service error", func(t *testing.T) {
+		rec := getStats(t, &stubAuthUseCase{err: errors.New("unavailable")}, &stubCalcUseCase{stats: orchStats})
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("Orchestrator service error", func(t *testing.T) {
+		rec := getStats(t, &stubAuthUseCase{stats: authStats}, &stubCalcUseCase{err: errors.New("unavailable")})
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+}
+
+func setAgentCapacity(t *testing.T, calcUseCase *stubCalcUseCase, agentID, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	router := chi.NewRouter()
+	router.Put("/api/v1/admin/agents/{id}/capacity", handlers.NewHandler(&stubAuthUseCase{}, calcUseCase).SetAgentCapacity)
+
+	ctx := logger.WithLogger(context.Background(), logger.New(zapcore.NewNopCore()))
+	req := httptest.NewRequestWithContext(ctx, http.MethodPut, "/api/v1/admin/agents/"+agentID+"/capacity", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestSetAgentCapacity(t *testing.T) {
+	t.Run("Changes capacity", func(t *testing.T) {
+		calcUseCase := &stubCalcUseCase{}
+		rec := setAgentCapacity(t, calcUseCase, "agent-1", `{"capacity": 2}`)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var resp agent.AgentStats
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, "agent-1", resp.ID)
+		assert.Equal(t, 2, resp.MaxCapacity)
+		assert.Equal(t, "agent-1", calcUseCase.agentID)
+		assert.Equal(t, 2, calcUseCase.capacity)
+	})
+
+	t.Run("Malformed body", func(t *testing.T) {
+		calcUseCase := &stubCalcUseCase{}
+		rec := setAgentCapacity(t, calcUseCase, "agent-1", `{"capacity": "many"}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Zero(t, calcUseCase.capacityCalls)
+	})
+
+	for _, tc := range []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{name: "Invalid capacity", err: domainerrors.ErrInvalidCapacity, expected: http.StatusBadRequest},
+		{name: "Unknown agent", err: domainerrors.ErrAgentNotFound, expected: http.StatusNotFound},
+		{name: "Pool unavailable", err: domainerrors.ErrNilPool, expected: http.StatusServiceUnavailable},
+		{name: "Unexpected error", err: errors.New("boom"), expected: http.StatusInternalServerError},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := setAgentCapacity(t, &stubCalcUseCase{capacityErr: tc.err}, "agent-1", `{"capacity": 0}`)
+			assert.Equal(t, tc.expected, rec.Code)
+		})
+	}
+}
+
+func getCalculation(t *testing.T, calcUseCase *stubCalcUseCase, calculationID string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	router := chi.NewRouter()
+	router.Get("/api/v1/admin/calculations/{id}", handlers.NewHandler(&stubAuthUseCase{}, calcUseCase).GetCalculation)
+
+	ctx := logger.WithLogger(context.Background(), logger.New(zapcore.NewNopCore()))
+	req := httptest.NewRequestWithContext(ctx, http.MethodGet, "/api/v1/admin/calculations/"+calculationID, nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestGetCalculation(t *testing.T) {
+	calc := &orchestrator.Calculation{
+		ID:            uuid.New(),
+		UserID:        uuid.New(),
+		Expression:    "2+2",
+		Result:        "4",
+		Status:        orchestrator.CalculationStatusCompleted,
+		Source:        orchestrator.CalculationSourceCLI,
+		ClientVersion: "calc-cli/2.1.0",
+	}
+
+	t.Run("Client version returned", func(t *testing.T) {
+		rec := getCalculation(t, &stubCalcUseCase{calculation: calc}, calc.ID.String())
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var resp map[string]any
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, "calc-cli/2.1.0", resp["client_version"])
+		assert.Equal(t, calc.UserID.String(), resp["user_id"])
+	})
+
+	t.Run("Calculation not found", func(t *testing.T) {
+		rec := getCalculation(t, &stubCalcUseCase{calculation: calc}, uuid.NewString())
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("Invalid calculation ID", func(t *testing.T) {
+		rec := getCalculation(t, &stubCalcUseCase{calculation: calc}, "not-a-uuid")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("Orchestrator service error", func(t *testing.T) {
+		rec := getCalculation(t, &stubCalcUseCase{calcErr: errors.New("unavailable")}, calc.ID.String())
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+}
+
+func getTimingProfile(t *testing.T, calcUseCase *stubCalcUseCase) *httptest.ResponseRecorder {
+	t.Helper()
+
+	ctx := logger.WithLogger(context.Background(), logger.New(zapcore.NewNopCore()))
+	req := httptest.NewRequestWithContext(ctx, http.MethodGet, "/api/v1/admin/agents/timings", nil)
+	rec := httptest.NewRecorder()
+	handlers.NewHandler(&stubAuthUseCase{}, calcUseCase).GetTimingProfile(rec, req)
+	return rec
+}
+
+func TestGetTimingProfile(t *testing.T) {
+	t.Run("Returns configured and effective timings", func(t *testing.T) {
+		profile := &agent.TimingProfile{
+			ConfiguredMs: map[string]int64{"addition": 1000, "division": 3000},
+			Agents: []agent.AgentTiming{
+				{ID: "agent-1", OperationTimesMs: map[string]int64{"addition": 1000, "division": 3000}},
+				{ID: "agent-2", Deterministic: true, OperationTimesMs: map[string]int64{"addition": 0, "division": 0}},
+			},
+		}
+
+		rec := getTimingProfile(t, &stubCalcUseCase{timing: profile})
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var resp agent.TimingProfile
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, *profile, resp)
+	})
+
+	t.Run("Pool unavailable", func(t *testing.T) {
+		rec := getTimingProfile(t, &stubCalcUseCase{timingErr: domainerrors.ErrNilPool})
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	})
+
+	t.Run("Unexpected error", func(t *testing.T) {
+		rec := getTimingProfile(t, &stubCalcUseCase{timingErr: errors.New("boom")})
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+}
@@ -0,0 +1,55 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/http/midleware"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"go.uber.org/zap"
+)
+
+var ErrInvalidLogLevel = midleware.NewAPIError("log level must be one of debug, info, warn, error, fatal", "INVALID_LOG_LEVEL")
+
+// LogLevelResponse - текущий уровень журнала шлюза.
+type LogLevelResponse struct {
+	Level string `json:"level"`
+}
+
+// SetLogLevelRequest - новый уровень журнала шлюза.
+type SetLogLevelRequest struct {
+	Level string `json:"level"`
+}
+
+// GetLogLevel возвращает текущий уровень журнала шлюза.
+func (h *Handler) GetLogLevel(w http.ResponseWriter, r *http.Request) {
+	log := logger.ContextLogger(r.Context(), nil)
+	respondJSON(r.Context(), w, LogLevelResponse{Level: logger.LevelName(log.GetLevel())}, http.StatusOK, log)
+}
+
+// SetLogLevel меняет уровень журнала шлюза без перезапуска. Уровень общий для всех
+// журналов сервиса, поэтому новые записи сразу фильтруются по нему.
+func (h *Handler) SetLogLevel(w http.ResponseWriter, r *http.Request) {
+	var req SetLogLevelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		midleware.HandleError(r.Context(), w, err, http.StatusBadRequest)
+		return
+	}
+
+	lvl, err := logger.ParseLevel(req.Level)
+	if err != nil {
+		midleware.HandleError(r.Context(), w, ErrInvalidLogLevel, http.StatusBadRequest)
+		return
+	}
+
+	log := logger.ContextLogger(r.Context(), nil)
+	previous := log.GetLevel()
+	log.SetLevel(lvl)
+
+	// Запись уровня Warn видна и при повышении уровня до warn
+	log.Warn("Log level changed by administrator",
+		zap.String("from", logger.LevelName(previous)),
+		zap.String("to", logger.LevelName(lvl)))
+
+	respondJSON(r.Context(), w, LogLevelResponse{Level: logger.LevelName(log.GetLevel())}, http.StatusOK, log)
+}
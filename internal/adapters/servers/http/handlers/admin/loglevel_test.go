@@ -0,0 +1,89 @@
+package admin_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	handlers "github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/http/handlers/admin"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLogLevel(t *testing.T) {
+	observed, logs := observer.New(zapcore.DebugLevel)
+	serviceLogger := logger.New(observed)
+	ctx := logger.WithLogger(context.Background(), serviceLogger)
+	handler := handlers.NewHandler(&stubAuthUseCase{}, &stubCalcUseCase{})
+
+	getLevel := func(t *testing.T) string {
+		t.Helper()
+		req := httptest.NewRequestWithContext(ctx, http.MethodGet, "/debug/loglevel", nil)
+		rec := httptest.NewRecorder()
+		handler.GetLogLevel(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var resp handlers.LogLevelResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		return resp.Level
+	}
+	setLevel := func(t *testing.T, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequestWithContext(ctx, http.MethodPut, "/debug/loglevel", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		handler.SetLogLevel(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, "debug", getLevel(t))
+
+	t.Run("Raising the level suppresses lower entries", func(t *testing.T) {
+		rec := setLevel(t, `{"level":"WARN"}`)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"level":"warn"}`, rec.Body.String())
+		assert.Equal(t, "warn", getLevel(t))
+
+		logs.TakeAll()
+		serviceLogger.Debug("debug entry")
+		serviceLogger.Info("info entry")
+		serviceLogger.Warn("warn entry")
+		serviceLogger.Error("error entry")
+
+		entries := logs.TakeAll()
+		require.Len(t, entries, 2)
+		assert.Equal(t, "warn entry", entries[0].Message)
+		assert.Equal(t, "error entry", entries[1].Message)
+	})
+
+	t.Run("Lowering the level enables debug entries", func(t *testing.T) {
+		rec := setLevel(t, `{"level":"debug"}`)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		changed := logs.FilterMessage("Log level changed by administrator").TakeAll()
+		require.Len(t, changed, 1)
+		assert.Equal(t, "warn", changed[0].ContextMap()["from"])
+		assert.Equal(t, "debug", changed[0].ContextMap()["to"])
+
+		logs.TakeAll()
+		serviceLogger.Debug("debug entry")
+		assert.Equal(t, 1, logs.FilterMessage("debug entry").Len())
+	})
+
+	t.Run("Unknown level", func(t *testing.T) {
+		rec := setLevel(t, `{"level":"verbose"}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "INVALID_LOG_LEVEL")
+		assert.Equal(t, "debug", getLevel(t))
+	})
+
+	t.Run("Malformed body", func(t *testing.T) {
+		rec := setLevel(t, `{"level":`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/http/handlers/admin"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/http/handlers/auth"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/http/handlers/orchestrator"
 	authAPI "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/api/auth"
@@ -10,6 +11,7 @@ import (
 type Handlers struct {
 	Auth         *auth.Handler
 	Orchestrator *orchestrator.Handler
+	Admin        *admin.Handler
 }
 
 func NewHandlers(
@@ -19,5 +21,6 @@ func NewHandlers(
 	return &Handlers{
 		Auth:         auth.NewHandler(authUseCase),
 		Orchestrator: orchestrator.NewHandler(calcUseCase),
+		Admin:        admin.NewHandler(authUseCase, calcUseCase),
 	}
 }
@@ -1,15 +1,22 @@
 package auth
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"net"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/http/midleware"
+	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
+	authmodels "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/auth"
 	authAPI "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/api/auth"
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/servertiming"
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
@@ -20,6 +27,8 @@ const (
 	tokenExpiryMinutes = 15
 )
 
+var errPasswordRequired = errors.New("password confirmation is required")
+
 type Handler struct {
 	authUseCase authAPI.UseCaseUser
 	router      *chi.Mux
@@ -35,6 +44,11 @@ func NewHandler(authUseCase authAPI.UseCaseUser) *Handler {
 	h.router.Post("/login", h.Login)
 	h.router.Post("/refresh", h.RefreshToken)
 	h.router.Post("/logout", h.Logout)
+	h.router.Get("/me", h.GetCurrentUser)
+	h.router.Delete("/me", h.DeleteCurrentUser)
+	h.router.Get("/sessions", h.ListSessions)
+	h.router.Delete("/sessions", h.RevokeAllSessions)
+	h.router.Delete("/sessions/{id}", h.RevokeSession)
 
 	return h
 }
@@ -50,6 +64,11 @@ type LoginRequest struct {
 	Password string `json:"password"`
 }
 
+// DeleteAccountRequest - подтверждение удаления учетной записи текущим паролем.
+type DeleteAccountRequest struct {
+	Password string `json:"password"`
+}
+
 type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token"`
 }
@@ -60,6 +79,18 @@ type TokenResponse struct {
 	ExpiresIn    int64  `json:"expires_in"`
 }
 
+// UserResponse - профиль пользователя. Хеш пароля в ответ не входит.
+type UserResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Login     string    `json:"login"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type SessionsResponse struct {
+	Sessions []*authmodels.Session `json:"sessions"`
+}
+
 func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 	log := logger.ContextLogger(r.Context(), nil)
 
@@ -73,11 +104,11 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 	userID, err := h.authUseCase.Register(r.Context(), req.Email, req.Password)
 	if err != nil {
 		log.Error("failed to register user", zap.Error(err))
-		midleware.HandleError(r.Context(), w, err, http.StatusInternalServerError)
+		midleware.HandleError(r.Context(), w, err, midleware.ErrorStatus(err, http.StatusInternalServerError))
 		return
 	}
 
-	tokens, err := h.authUseCase.Login(r.Context(), req.Email, req.Password)
+	tokens, err := h.authUseCase.Login(withClientInfo(r), req.Email, req.Password)
 	if err != nil {
 		log.Error("failed to login after registration",
 			zap.String("user_id", userID.String()),
@@ -86,7 +117,7 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	respondJSON(w, TokenResponse{
+	respondJSON(r.Context(), w, TokenResponse{
 		AccessToken:  tokens.AccessToken,
 		RefreshToken: tokens.RefreshToken,
 		ExpiresIn:    time.Now().Add(tokenExpiryMinutes * time.Minute).Unix(),
@@ -103,14 +134,14 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tokens, err := h.authUseCase.Login(r.Context(), req.Email, req.Password)
+	tokens, err := h.authUseCase.Login(withClientInfo(r), req.Email, req.Password)
 	if err != nil {
 		log.Error("failed to login", zap.Error(err))
-		midleware.HandleError(r.Context(), w, err, http.StatusUnauthorized)
+		midleware.HandleError(r.Context(), w, err, loginErrorStatus(err))
 		return
 	}
 
-	respondJSON(w, TokenResponse{
+	respondJSON(r.Context(), w, TokenResponse{
 		AccessToken:  tokens.AccessToken,
 		RefreshToken: tokens.RefreshToken,
 		ExpiresIn:    time.Now().Add(tokenExpiryMinutes * time.Minute).Unix(),
@@ -134,7 +165,7 @@ func (h *Handler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	respondJSON(w, TokenResponse{
+	respondJSON(r.Context(), w, TokenResponse{
 		AccessToken:  tokens.AccessToken,
 		RefreshToken: tokens.RefreshToken,
 		ExpiresIn:    time.Now().Add(tokenExpiryMinutes * time.Minute).Unix(),
@@ -165,16 +196,197 @@ func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// GetCurrentUser возвращает профиль пользователя, которому принадлежит токен доступа.
+func (h *Handler) GetCurrentUser(w http.ResponseWriter, r *http.Request) {
+	log := logger.ContextLogger(r.Context(), nil)
+
+	userID, err := midleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		midleware.HandleError(r.Context(), w, err, http.StatusUnauthorized)
+		return
+	}
+
+	user, err := h.authUseCase.GetUser(r.Context(), userID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, domainerrors.ErrUserNotFound) {
+			status = http.StatusNotFound
+		} else {
+			log.Error("failed to get user", zap.Error(err))
+		}
+		midleware.HandleError(r.Context(), w, err, status)
+		return
+	}
+
+	respondJSON(r.Context(), w, UserResponse{
+		ID:        user.ID,
+		Login:     user.Login,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}, http.StatusOK, log)
+}
+
+// DeleteCurrentUser удаляет учетную запись пользователя, которому принадлежит токен доступа.
+// Удаление требует текущего пароля в теле запроса.
+func (h *Handler) DeleteCurrentUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := midleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		midleware.HandleError(r.Context(), w, err, http.StatusUnauthorized)
+		return
+	}
+
+	var req DeleteAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		midleware.HandleError(r.Context(), w, err, http.StatusBadRequest)
+		return
+	}
+
+	if req.Password == "" {
+		midleware.HandleError(r.Context(), w, errPasswordRequired, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.authUseCase.DeleteUser(withClientInfo(r), userID, req.Password); err != nil {
+		status := deleteUserErrorStatus(err)
+		if status == http.StatusInternalServerError {
+			logger.ContextLogger(r.Context(), nil).Error("failed to delete user", zap.Error(err))
+		}
+		midleware.HandleError(r.Context(), w, err, status)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	log := logger.ContextLogger(r.Context(), nil)
+
+	userID, err := midleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		midleware.HandleError(r.Context(), w, err, http.StatusUnauthorized)
+		return
+	}
+
+	sessions, err := h.authUseCase.ListSessions(r.Context(), userID)
+	if err != nil {
+		log.Error("failed to list sessions", zap.Error(err))
+		midleware.HandleError(r.Context(), w, err, http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(r.Context(), w, SessionsResponse{Sessions: sessions}, http.StatusOK, log)
+}
+
+func (h *Handler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	userID, err := midleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		midleware.HandleError(r.Context(), w, err, http.StatusUnauthorized)
+		return
+	}
+
+	sessionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		midleware.HandleError(r.Context(), w, err, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.authUseCase.RevokeSession(r.Context(), userID, sessionID); err != nil {
+		logger.ContextLogger(r.Context(), nil).Error("failed to revoke session",
+			zap.String("session_id", sessionID.String()),
+			zap.Error(err))
+		// Чужая сессия не отличается от несуществующей, чтобы не раскрывать ее наличие
+		if errors.Is(err, domainerrors.ErrUnauthorizedAccess) {
+			err = domainerrors.ErrTokenNotFound
+		}
+		midleware.HandleError(r.Context(), w, err, revokeErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) RevokeAllSessions(w http.ResponseWriter, r *http.Request) {
+	userID, err := midleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		midleware.HandleError(r.Context(), w, err, http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.authUseCase.RevokeAllSessions(r.Context(), userID); err != nil {
+		logger.ContextLogger(r.Context(), nil).Error("failed to revoke all sessions", zap.Error(err))
+		midleware.HandleError(r.Context(), w, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func loginErrorStatus(err error) int {
+	if errors.Is(err, domainerrors.ErrTooManyAttempts) {
+		return http.StatusTooManyRequests
+	}
+	return http.StatusUnauthorized
+}
+
+func deleteUserErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domainerrors.ErrInvalidCredentials):
+		return http.StatusForbidden
+	case errors.Is(err, domainerrors.ErrUserNotFound):
+		return http.StatusNotFound
+	default:
+		return midleware.ErrorStatus(err, http.StatusInternalServerError)
+	}
+}
+
+func revokeErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domainerrors.ErrTokenNotFound):
+		return http.StatusNotFound
+	default:
+		return midleware.ErrorStatus(err, http.StatusInternalServerError)
+	}
+}
+
+// withClientInfo добавляет в контекст User-Agent и IP клиента для сохранения вместе с сессией.
+// IP берется из midleware.ClientIP с учетом доверенных прокси; без него - адрес соединения.
+func withClientInfo(r *http.Request) context.Context {
+	ip := midleware.GetClientIPFromContext(r.Context())
+	if ip == "" {
+		ip = r.RemoteAddr
+		if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+			ip = host
+		}
+	}
+
+	return authmodels.WithClientInfo(r.Context(), authmodels.ClientInfo{
+		UserAgent: r.UserAgent(),
+		IP:        ip,
+	})
+}
+
 func (h *Handler) Routes() *chi.Mux {
 	return h.router
 }
 
-func respondJSON(w http.ResponseWriter, data interface{}, statusCode int, log logger.Logger) {
+// respondJSON сериализует data до отправки заголовков, чтобы длительность сериализации
+// попала в заголовок Server-Timing.
+func respondJSON(ctx context.Context, w http.ResponseWriter, data interface{}, statusCode int, log logger.Logger) {
+	start := time.Now()
+	body, err := json.Marshal(data)
+	servertiming.Since(ctx, servertiming.MetricSerialize, start)
+	if err != nil {
+		log.Error("failed to encode response to JSON",
+			zap.Error(err),
+			zap.Int("status_code", statusCode))
+		midleware.HandleError(ctx, w, err, http.StatusInternalServerError)
+		return
+	}
+
 	w.Header().Set("Content-Type", contentTypeJSON)
 	w.WriteHeader(statusCode)
-	if err := json.NewEncoder(w).Encode(data); err != nil {
-		// We've already written headers, so we can't change the status code
-		log.Error("failed to encode response to JSON",
+	if _, err := w.Write(append(body, '\n')); err != nil {
+		log.Error("failed to write response",
 			zap.Error(err),
 			zap.Int("status_code", statusCode))
 	}
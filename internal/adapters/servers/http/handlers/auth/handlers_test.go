@@ -0,0 +1,254 @@
+package auth_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	handlers "github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/http/handlers/auth"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/http/midleware"
+	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
+	authmodels "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/auth"
+	authAPI "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/api/auth"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+const passwordHash = "$argon2id$v=19$m=65536,t=1,p=4$c2FsdA$aGFzaA"
+
+type stubAuthUseCase struct {
+	authAPI.UseCaseUser
+	userID  uuid.UUID
+	user    *authmodels.User
+	userErr error
+
+	deleteErr       error
+	deletedPassword string
+
+	sessions    []*authmodels.Session
+	sessionsErr error
+
+	revokeErr      error
+	revokedUserID  uuid.UUID
+	revokedSession uuid.UUID
+}
+
+func (s *stubAuthUseCase) ValidateToken(context.Context, string) (uuid.UUID, error) {
+	return s.userID, nil
+}
+
+func (s *stubAuthUseCase) GetUser(_ context.Context, userID uuid.UUID) (*authmodels.User, error) {
+	if s.userErr != nil {
+		return nil, s.userErr
+	}
+	if s.user == nil || s.user.ID != userID {
+		return nil, domainerrors.ErrUserNotFound
+	}
+	return s.user, nil
+}
+
+func (s *stubAuthUseCase) DeleteUser(_ context.Context, _ uuid.UUID, password string) error {
+	s.deletedPassword = password
+	return s.deleteErr
+}
+
+func (s *stubAuthUseCase) ListSessions(context.Context, uuid.UUID) ([]*authmodels.Session, error) {
+	return s.sessions, s.sessionsErr
+}
+
+func (s *stubAuthUseCase) RevokeSession(_ context.Context, userID, sessionID uuid.UUID) error {
+	s.revokedUserID, s.revokedSession = userID, sessionID
+	return s.revokeErr
+}
+
+// serveAuth выполняет запрос к маршрутам обработчика с авторизацией, как в API Gateway.
+func serveAuth(t *testing.T, authUseCase *stubAuthUseCase, method, target string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	handler := handlers.NewHandler(authUseCase)
+
+	ctx := logger.WithLogger(context.Background(), logger.New(zapcore.NewNopCore()))
+	req := httptest.NewRequestWithContext(ctx, method, target, nil)
+	req.Header.Set("Authorization", "Bearer token")
+
+	rec := httptest.NewRecorder()
+	midleware.AuthMiddleware(authUseCase)(handler.Routes()).ServeHTTP(rec, req)
+	return rec
+}
+
+func getCurrentUser(t *testing.T, authUseCase *stubAuthUseCase) *httptest.ResponseRecorder {
+	t.Helper()
+
+	handler := handlers.NewHandler(authUseCase)
+	authMiddleware := midleware.AuthMiddleware(authUseCase)
+
+	ctx := logger.WithLogger(context.Background(), logger.New(zapcore.NewNopCore()))
+	req := httptest.NewRequestWithContext(ctx, http.MethodGet, "/api/v1/auth/me", nil)
+	req.Header.Set("Authorization", "Bearer token")
+
+	rec := httptest.NewRecorder()
+	authMiddleware(http.HandlerFunc(handler.GetCurrentUser)).ServeHTTP(rec, req)
+	return rec
+}
+
+func TestGetCurrentUser(t *testing.T) {
+	userID := uuid.New()
+	createdAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	updatedAt := createdAt.Add(time.Hour)
+
+	t.Run("Found", func(t *testing.T) {
+		rec := getCurrentUser(t, &stubAuthUseCase{
+			userID: userID,
+			user: &authmodels.User{
+				ID:           userID,
+				Login:        "alice",
+				PasswordHash: passwordHash,
+				CreatedAt:    createdAt,
+				UpdatedAt:    updatedAt,
+			},
+		})
+
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, userID.String(), body["id"])
+		assert.Equal(t, "alice", body["login"])
+		assert.Equal(t, createdAt.Format(time.RFC3339), body["created_at"])
+		assert.Equal(t, updatedAt.Format(time.RFC3339), body["updated_at"])
+		assert.Len(t, body, 4)
+		assert.NotContains(t, rec.Body.String(), passwordHash)
+		assert.NotContains(t, strings.ToLower(rec.Body.String()), "password")
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		rec := getCurrentUser(t, &stubAuthUseCase{userID: userID})
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("Internal error", func(t *testing.T) {
+		rec := getCurrentUser(t, &stubAuthUseCase{userID: userID, userErr: domainerrors.ErrInternalServerError})
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+}
+
+func TestDeleteCurrentUser(t *testing.T) {
+	testCases := []struct {
+		name       string
+		body       string
+		deleteErr  error
+		statusCode int
+	}{
+		{name: "Deleted", body: `{"password":"password123"}`, statusCode: http.StatusNoContent},
+		{name: "Missing password", body: `{}`, statusCode: http.StatusBadRequest},
+		{name: "Invalid body", body: `not json`, statusCode: http.StatusBadRequest},
+		{name: "Wrong password", body: `{"password":"wrong"}`, deleteErr: domainerrors.ErrInvalidCredentials, statusCode: http.StatusForbidden},
+		{name: "Too many attempts", body: `{"password":"wrong"}`, deleteErr: domainerrors.ErrTooManyAttempts, statusCode: http.StatusTooManyRequests},
+		{name: "Not found", body: `{"password":"password123"}`, deleteErr: domainerrors.ErrUserNotFound, statusCode: http.StatusNotFound},
+		{name: "Internal error", body: `{"password":"password123"}`, deleteErr: domainerrors.ErrInternalServerError, statusCode: http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			authUseCase := &stubAuthUseCase{userID: uuid.New(), deleteErr: tc.deleteErr}
+			handler := handlers.NewHandler(authUseCase)
+
+			ctx := logger.WithLogger(context.Background(), logger.New(zapcore.NewNopCore()))
+			req := httptest.NewRequestWithContext(ctx, http.MethodDelete, "/api/v1/auth/me", strings.NewReader(tc.body))
+			req.Header.Set("Authorization", "Bearer token")
+
+			rec := httptest.NewRecorder()
+			midleware.AuthMiddleware(authUseCase)(http.HandlerFunc(handler.DeleteCurrentUser)).ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.statusCode, rec.Code)
+			if tc.statusCode == http.StatusNoContent {
+				assert.Equal(t, "password123", authUseCase.deletedPassword)
+			}
+		})
+	}
+}
+
+func TestListSessions(t *testing.T) {
+	userID := uuid.New()
+	createdAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Listed", func(t *testing.T) {
+		session := &authmodels.Session{
+			ID:        uuid.New(),
+			UserAgent: "Mozilla/5.0",
+			IP:        "203.0.113.7",
+			CreatedAt: createdAt,
+			ExpiresAt: createdAt.Add(24 * time.Hour),
+		}
+		rec := serveAuth(t, &stubAuthUseCase{userID: userID, sessions: []*authmodels.Session{session}},
+			http.MethodGet, "/sessions")
+
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var body struct {
+			Sessions []authmodels.Session `json:"sessions"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		require.Len(t, body.Sessions, 1)
+		assert.Equal(t, *session, body.Sessions[0])
+		assert.NotContains(t, rec.Body.String(), "token")
+	})
+
+	t.Run("Internal error", func(t *testing.T) {
+		rec := serveAuth(t, &stubAuthUseCase{userID: userID, sessionsErr: domainerrors.ErrInternalServerError},
+			http.MethodGet, "/sessions")
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+}
+
+func TestRevokeSession(t *testing.T) {
+	sessionID := uuid.New()
+
+	testCases := []struct {
+		name       string
+		target     string
+		revokeErr  error
+		statusCode int
+	}{
+		{name: "Revoked", target: "/sessions/" + sessionID.String(), statusCode: http.StatusNoContent},
+		{name: "Invalid id", target: "/sessions/not-a-uuid", statusCode: http.StatusBadRequest},
+		{name: "Not found", target: "/sessions/" + sessionID.String(), revokeErr: domainerrors.ErrTokenNotFound, statusCode: http.StatusNotFound},
+		{name: "Another user's session", target: "/sessions/" + sessionID.String(), revokeErr: domainerrors.ErrUnauthorizedAccess, statusCode: http.StatusNotFound},
+		{name: "Internal error", target: "/sessions/" + sessionID.String(), revokeErr: domainerrors.ErrInternalServerError, statusCode: http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			authUseCase := &stubAuthUseCase{userID: uuid.New(), revokeErr: tc.revokeErr}
+
+			rec := serveAuth(t, authUseCase, http.MethodDelete, tc.target)
+
+			assert.Equal(t, tc.statusCode, rec.Code)
+			if tc.statusCode != http.StatusBadRequest {
+				assert.Equal(t, authUseCase.userID, authUseCase.revokedUserID)
+				assert.Equal(t, sessionID, authUseCase.revokedSession)
+			}
+		})
+	}
+}
+
+func TestRevokeSessionHidesOwnership(t *testing.T) {
+	target := "/sessions/" + uuid.New().String()
+
+	foreign := serveAuth(t, &stubAuthUseCase{userID: uuid.New(), revokeErr: domainerrors.ErrUnauthorizedAccess}, http.MethodDelete, target)
+	missing := serveAuth(t, &stubAuthUseCase{userID: uuid.New(), revokeErr: domainerrors.ErrTokenNotFound}, http.MethodDelete, target)
+
+	assert.Equal(t, http.StatusNotFound, foreign.Code)
+	assert.Equal(t, missing.Code, foreign.Code)
+	assert.JSONEq(t, missing.Body.String(), foreign.Body.String())
+}
@@ -1,18 +1,49 @@
 package orchestrator
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/http/midleware"
+	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
 	orchAPI "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/api/orchestrator"
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/servertiming"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
-const contentTypeJSON = "application/json"
+const (
+	contentTypeJSON        = "application/json"
+	contentTypeEventStream = "text/event-stream"
+
+	// sseEventStatus - имя события SSE со сменой статуса вычисления.
+	sseEventStatus = "status"
+	// sseKeepAliveInterval - период комментариев, не дающих прокси закрыть
+	// простаивающее соединение.
+	sseKeepAliveInterval = 15 * time.Second
+
+	queryLimit  = "limit"
+	queryOffset = "offset"
+	queryStatus = "status"
+	queryFormat = "format"
+
+	headerRetryAfter = "Retry-After"
+)
+
+var (
+	ErrInvalidLimit  = midleware.NewAPIError("limit must be an integer", "INVALID_LIMIT")
+	ErrInvalidOffset = midleware.NewAPIError("offset must be an integer", "INVALID_OFFSET")
+)
 
 type Handler struct {
 	calcUseCase orchAPI.UseCaseCalculation
@@ -24,6 +55,23 @@ func NewHandler(calcUseCase orchAPI.UseCaseCalculation) *Handler {
 
 type CalculateRequest struct {
 	Expression string `json:"expression"`
+	// Priority - приоритет выполнения от 0 до orchestrator.MaxPriority, по умолчанию 0.
+	Priority int `json:"priority"`
+}
+
+type CompareRequest struct {
+	ExpressionA string  `json:"expression_a"`
+	ExpressionB string  `json:"expression_b"`
+	Tolerance   float64 `json:"tolerance"`
+}
+
+type PreviewRequest struct {
+	Expression string `json:"expression"`
+	Spaced     bool   `json:"spaced"`
+}
+
+type ValidateRequest struct {
+	Expression string `json:"expression"`
 }
 
 func (h *Handler) CalculateExpression(w http.ResponseWriter, r *http.Request) {
@@ -39,14 +87,121 @@ func (h *Handler) CalculateExpression(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	calculation, err := h.calcUseCase.CalculateExpression(r.Context(), userID, req.Expression)
+	source := midleware.GetSourceFromContext(r.Context())
+	ctx := orchestrator.WithPriority(r.Context(), req.Priority)
+
+	calculation, err := h.calcUseCase.CalculateExpression(ctx, userID, req.Expression, source)
 	if err != nil {
-		logger.ContextLogger(r.Context(), nil).Error("failed to create calculation", zap.Error(err))
-		midleware.HandleError(r.Context(), w, err, http.StatusInternalServerError)
+		status := calculateErrorStatus(err)
+		if status == http.StatusInternalServerError {
+			logger.ContextLogger(r.Context(), nil).Error("failed to create calculation", zap.Error(err))
+		}
+		midleware.HandleError(r.Context(), w, err, status)
+		return
+	}
+
+	if calculation.Status == orchestrator.CalculationStatusPending || calculation.Status == orchestrator.CalculationStatusInProgress {
+		w.Header().Set(headerRetryAfter, strconv.Itoa(retryAfterSeconds(calculation.EstimatedDuration)))
+	}
+
+	respondJSON(r.Context(), w, calculation, http.StatusAccepted, logger.ContextLogger(r.Context(), nil))
+}
+
+// calculateErrorStatus выбирает код ответа для ошибки создания вычисления,
+// в том числе для ошибок подстановки ссылок calc:{uuid}.
+func calculateErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domainerrors.ErrParseTimeout),
+		errors.Is(err, domainerrors.ErrReferenceNotCompleted),
+		errors.Is(err, domainerrors.ErrTooManyReferences):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, domainerrors.ErrUnsupportedOp),
+		errors.Is(err, domainerrors.ErrInvalidPriority),
+		errors.Is(err, domainerrors.ErrInvalidIdempotencyKey):
+		return http.StatusBadRequest
+	case errors.Is(err, domainerrors.ErrPendingOpsBudget):
+		return http.StatusTooManyRequests
+	case errors.Is(err, domainerrors.ErrDatabaseUnavailable):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, domainerrors.ErrCalculationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domainerrors.ErrUnauthorizedAccess):
+		return http.StatusForbidden
+	default:
+		return midleware.ErrorStatus(err, http.StatusInternalServerError)
+	}
+}
+
+// retryAfterSeconds переводит оценку оставшегося времени в значение заголовка Retry-After:
+// целое число секунд с округлением вверх, но не меньше одной секунды.
+func retryAfterSeconds(estimate time.Duration) int {
+	seconds := int(math.Ceil(estimate.Seconds()))
+	return max(seconds, 1)
+}
+
+func (h *Handler) CompareExpressions(w http.ResponseWriter, r *http.Request) {
+	var req CompareRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		midleware.HandleError(r.Context(), w, err, http.StatusBadRequest)
+		return
+	}
+
+	comparison, err := h.calcUseCase.CompareExpressions(r.Context(), req.ExpressionA, req.ExpressionB, req.Tolerance)
+	if err != nil {
+		midleware.HandleError(r.Context(), w, err, compareErrorStatus(err))
+		return
+	}
+
+	respondJSON(r.Context(), w, comparison, http.StatusOK, logger.ContextLogger(r.Context(), nil))
+}
+
+func compareErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domainerrors.ErrInvalidExpression),
+		errors.Is(err, domainerrors.ErrInvalidTolerance),
+		errors.Is(err, domainerrors.ErrInvalidArgs):
+		return http.StatusBadRequest
+	case errors.Is(err, domainerrors.ErrParseTimeout):
+		return http.StatusUnprocessableEntity
+	default:
+		return midleware.ErrorStatus(err, http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) PreviewExpression(w http.ResponseWriter, r *http.Request) {
+	var req PreviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		midleware.HandleError(r.Context(), w, err, http.StatusBadRequest)
+		return
+	}
+
+	options := orchestrator.PreviewOptions{Spaced: req.Spaced}
+
+	preview, err := h.calcUseCase.PreviewExpression(r.Context(), req.Expression, options)
+	if err != nil {
+		midleware.HandleError(r.Context(), w, err, compareErrorStatus(err))
+		return
+	}
+
+	respondJSON(r.Context(), w, preview, http.StatusOK, logger.ContextLogger(r.Context(), nil))
+}
+
+// ValidateExpression проверяет синтаксис выражения без создания вычисления. Некорректное
+// выражение дает ответ 200 с valid = false и позицией первой ошибки.
+func (h *Handler) ValidateExpression(w http.ResponseWriter, r *http.Request) {
+	var req ValidateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		midleware.HandleError(r.Context(), w, err, http.StatusBadRequest)
+		return
+	}
+
+	validation, err := h.calcUseCase.ValidateExpression(r.Context(), req.Expression)
+	if err != nil {
+		midleware.HandleError(r.Context(), w, err, compareErrorStatus(err))
 		return
 	}
 
-	respondJSON(w, calculation, http.StatusAccepted, logger.ContextLogger(r.Context(), nil))
+	respondJSON(r.Context(), w, validation, http.StatusOK, logger.ContextLogger(r.Context(), nil))
 }
 
 func (h *Handler) GetCalculation(w http.ResponseWriter, r *http.Request) {
@@ -56,6 +211,12 @@ func (h *Handler) GetCalculation(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	format := orchestrator.ResultFormat(strings.ToLower(r.URL.Query().Get(queryFormat)))
+	if format != "" && !format.IsValid() {
+		midleware.HandleError(r.Context(), w, domainerrors.ErrInvalidResultFormat, http.StatusBadRequest)
+		return
+	}
+
 	userID, err := midleware.GetUserIDFromContext(r.Context())
 	if err != nil {
 		midleware.HandleError(r.Context(), w, err, http.StatusUnauthorized)
@@ -71,7 +232,266 @@ func (h *Handler) GetCalculation(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	respondJSON(w, calculation, http.StatusOK, logger.ContextLogger(r.Context(), nil))
+	if err := applyResultFormat(calculation, format); err != nil {
+		midleware.HandleError(r.Context(), w, err, http.StatusUnprocessableEntity)
+		return
+	}
+
+	respondJSON(r.Context(), w, calculation, http.StatusOK, logger.ContextLogger(r.Context(), nil))
+}
+
+// applyResultFormat заполняет FormattedResult для завершенного вычисления.
+// Пока результата нет, формат игнорируется.
+func applyResultFormat(calculation *orchestrator.Calculation, format orchestrator.ResultFormat) error {
+	if format == "" || format == orchestrator.ResultFormatDecimal || calculation.Result == "" {
+		return nil
+	}
+
+	formatted, err := orchestrator.FormatResult(calculation.Result, format)
+	if err != nil {
+		return err
+	}
+	calculation.FormattedResult = formatted
+	return nil
+}
+
+// StreamCalculation передает изменения статуса вычисления как Server-Sent Events.
+// Первое событие содержит текущее состояние, последнее - конечный статус и результат,
+// после чего поток закрывается. Отключение клиента отменяет подписку.
+func (h *Handler) StreamCalculation(w http.ResponseWriter, r *http.Request) {
+	calculationID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		midleware.HandleError(r.Context(), w, err, http.StatusBadRequest)
+		return
+	}
+
+	userID, err := midleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		midleware.HandleError(r.Context(), w, err, http.StatusUnauthorized)
+		return
+	}
+
+	log := logger.ContextLogger(r.Context(), nil)
+
+	events, err := h.calcUseCase.SubscribeCalculation(r.Context(), calculationID, userID)
+	if err != nil {
+		log.Error("failed to subscribe to calculation",
+			zap.String("calculation_id", calculationID.String()),
+			zap.Error(err))
+		midleware.HandleError(r.Context(), w, err, streamErrorStatus(err))
+		return
+	}
+
+	// Поток живет дольше HTTP_WRITE_TIMEOUT, поэтому срок записи снимается
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		log.Warn("failed to clear write deadline for stream", zap.Error(err))
+	}
+
+	w.Header().Set("Content-Type", contentTypeEventStream)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		log.Error("streaming is not supported by response writer", zap.Error(err))
+		return
+	}
+
+	keepAlive := time.NewTicker(sseKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := writeSSEEvent(w, sseEventStatus, event); err != nil {
+				log.Debug("failed to write calculation event", zap.Error(err))
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := w.Write([]byte(": keep-alive\n\n")); err != nil {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		}
+
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// writeSSEEvent записывает событие SSE с данными в формате JSON.
+func writeSSEEvent(w http.ResponseWriter, name string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("encoding event: %w", err)
+	}
+
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, payload); err != nil {
+		return fmt.Errorf("writing event: %w", err)
+	}
+	return nil
+}
+
+func streamErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domainerrors.ErrCalculationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domainerrors.ErrUnauthorizedAccess):
+		return http.StatusForbidden
+	default:
+		return midleware.ErrorStatus(err, http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) CancelCalculation(w http.ResponseWriter, r *http.Request) {
+	calculationID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		midleware.HandleError(r.Context(), w, err, http.StatusBadRequest)
+		return
+	}
+
+	userID, err := midleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		midleware.HandleError(r.Context(), w, err, http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.calcUseCase.CancelCalculation(r.Context(), calculationID, userID); err != nil {
+		logger.ContextLogger(r.Context(), nil).Error("failed to cancel calculation",
+			zap.String("calculation_id", calculationID.String()),
+			zap.Error(err))
+		midleware.HandleError(r.Context(), w, err, cancelErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func cancelErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domainerrors.ErrCalculationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domainerrors.ErrUnauthorizedAccess):
+		return http.StatusForbidden
+	case errors.Is(err, domainerrors.ErrCalcNotCancellable):
+		return http.StatusConflict
+	default:
+		return midleware.ErrorStatus(err, http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) DeleteCalculation(w http.ResponseWriter, r *http.Request) {
+	calculationID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		midleware.HandleError(r.Context(), w, err, http.StatusBadRequest)
+		return
+	}
+
+	userID, err := midleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		midleware.HandleError(r.Context(), w, err, http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.calcUseCase.DeleteCalculation(r.Context(), calculationID, userID); err != nil {
+		logger.ContextLogger(r.Context(), nil).Error("failed to delete calculation",
+			zap.String("calculation_id", calculationID.String()),
+			zap.Error(err))
+		midleware.HandleError(r.Context(), w, err, deleteErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func deleteErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domainerrors.ErrCalculationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domainerrors.ErrUnauthorizedAccess):
+		return http.StatusForbidden
+	default:
+		return midleware.ErrorStatus(err, http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) DiffCalculations(w http.ResponseWriter, r *http.Request) {
+	calculationIDA, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		midleware.HandleError(r.Context(), w, err, http.StatusBadRequest)
+		return
+	}
+
+	calculationIDB, err := uuid.Parse(chi.URLParam(r, "otherID"))
+	if err != nil {
+		midleware.HandleError(r.Context(), w, err, http.StatusBadRequest)
+		return
+	}
+
+	userID, err := midleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		midleware.HandleError(r.Context(), w, err, http.StatusUnauthorized)
+		return
+	}
+
+	diff, err := h.calcUseCase.DiffCalculations(r.Context(), calculationIDA, calculationIDB, userID)
+	if err != nil {
+		midleware.HandleError(r.Context(), w, err, diffErrorStatus(err))
+		return
+	}
+
+	respondJSON(r.Context(), w, diff, http.StatusOK, logger.ContextLogger(r.Context(), nil))
+}
+
+func diffErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domainerrors.ErrCalculationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domainerrors.ErrUnauthorizedAccess):
+		return http.StatusForbidden
+	default:
+		return midleware.ErrorStatus(err, http.StatusInternalServerError)
+	}
+}
+
+// GetOperation возвращает отдельную операцию вычисления пользователя для отладки.
+func (h *Handler) GetOperation(w http.ResponseWriter, r *http.Request) {
+	operationID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		midleware.HandleError(r.Context(), w, err, http.StatusBadRequest)
+		return
+	}
+
+	userID, err := midleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		midleware.HandleError(r.Context(), w, err, http.StatusUnauthorized)
+		return
+	}
+
+	op, err := h.calcUseCase.GetOperation(r.Context(), operationID, userID)
+	if err != nil {
+		midleware.HandleError(r.Context(), w, err, getOperationErrorStatus(err))
+		return
+	}
+
+	respondJSON(r.Context(), w, op, http.StatusOK, logger.ContextLogger(r.Context(), nil))
+}
+
+func getOperationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domainerrors.ErrOperationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domainerrors.ErrUnauthorizedAccess):
+		return http.StatusForbidden
+	default:
+		return midleware.ErrorStatus(err, http.StatusInternalServerError)
+	}
 }
 
 func (h *Handler) ListCalculations(w http.ResponseWriter, r *http.Request) {
@@ -81,22 +501,95 @@ func (h *Handler) ListCalculations(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	calculations, err := h.calcUseCase.ListCalculations(r.Context(), userID)
+	filter, err := parseCalculationFilter(r)
 	if err != nil {
+		midleware.HandleError(r.Context(), w, err, http.StatusBadRequest)
+		return
+	}
+
+	page, err := h.calcUseCase.ListCalculations(r.Context(), userID, filter)
+	if err != nil {
+		if isInvalidListFilter(err) {
+			midleware.HandleError(r.Context(), w, err, http.StatusBadRequest)
+			return
+		}
 		logger.ContextLogger(r.Context(), nil).Error("failed to list calculations", zap.Error(err))
 		midleware.HandleError(r.Context(), w, err, http.StatusInternalServerError)
 		return
 	}
 
-	respondJSON(w, calculations, http.StatusOK, logger.ContextLogger(r.Context(), nil))
+	respondJSON(r.Context(), w, page, http.StatusOK, logger.ContextLogger(r.Context(), nil))
+}
+
+// GetResultStats возвращает количество, сумму, среднее, минимум и максимум числовых
+// результатов завершенных вычислений пользователя.
+func (h *Handler) GetResultStats(w http.ResponseWriter, r *http.Request) {
+	userID, err := midleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		midleware.HandleError(r.Context(), w, err, http.StatusUnauthorized)
+		return
+	}
+
+	stats, err := h.calcUseCase.GetResultStats(r.Context(), userID)
+	if err != nil {
+		logger.ContextLogger(r.Context(), nil).Error("failed to get calculation result stats", zap.Error(err))
+		midleware.HandleError(r.Context(), w, err, http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(r.Context(), w, stats, http.StatusOK, logger.ContextLogger(r.Context(), nil))
 }
 
-func respondJSON(w http.ResponseWriter, data any, statusCode int, log logger.Logger) {
+// parseCalculationFilter читает параметры ?limit=&offset=&status= из строки запроса.
+func parseCalculationFilter(r *http.Request) (orchestrator.CalculationFilter, error) {
+	query := r.URL.Query()
+	filter := orchestrator.CalculationFilter{
+		Status: orchestrator.CalculationStatus(strings.ToUpper(query.Get(queryStatus))),
+	}
+
+	if raw := query.Get(queryLimit); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil {
+			return filter, ErrInvalidLimit
+		}
+		filter.Limit = limit
+	}
+
+	if raw := query.Get(queryOffset); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil {
+			return filter, ErrInvalidOffset
+		}
+		filter.Offset = offset
+	}
+
+	return filter, nil
+}
+
+func isInvalidListFilter(err error) bool {
+	return errors.Is(err, domainerrors.ErrInvalidPagination) ||
+		errors.Is(err, domainerrors.ErrInvalidStatusFilter) ||
+		errors.Is(err, domainerrors.ErrInvalidArgs)
+}
+
+// respondJSON сериализует data до отправки заголовков, чтобы длительность сериализации
+// попала в заголовок Server-Timing.
+func respondJSON(ctx context.Context, w http.ResponseWriter, data any, statusCode int, log logger.Logger) {
+	start := time.Now()
+	body, err := json.Marshal(data)
+	servertiming.Since(ctx, servertiming.MetricSerialize, start)
+	if err != nil {
+		log.Error("failed to encode response to JSON",
+			zap.Error(err),
+			zap.Int("status_code", statusCode))
+		midleware.HandleError(ctx, w, err, http.StatusInternalServerError)
+		return
+	}
+
 	w.Header().Set("Content-Type", contentTypeJSON)
 	w.WriteHeader(statusCode)
-	if err := json.NewEncoder(w).Encode(data); err != nil {
-		// We've already written headers, so we can't change the status code
-		log.Error("failed to encode response to JSON",
+	if _, err := w.Write(append(body, '\n')); err != nil {
+		log.Error("failed to write response",
 			zap.Error(err),
 			zap.Int("status_code", statusCode))
 	}
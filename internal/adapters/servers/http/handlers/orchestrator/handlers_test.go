@@ -0,0 +1,374 @@
+package orchestrator_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	handlers "github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/http/handlers/orchestrator"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/http/midleware"
+	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	authAPI "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/api/auth"
+	orchAPI "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/api/orchestrator"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+type stubAuthUseCase struct {
+	authAPI.UseCaseUser
+	userID uuid.UUID
+}
+
+func (s *stubAuthUseCase) ValidateToken(context.Context, string) (uuid.UUID, error) {
+	return s.userID, nil
+}
+
+type stubCalcUseCase struct {
+	orchAPI.UseCaseCalculation
+	calculation  *orchestrator.Calculation
+	calculateErr error
+	deleteErr    error
+	events       chan orchestrator.CalculationEvent
+	subscribeErr error
+	validation   *orchestrator.ExpressionValidation
+	validateErr  error
+	operation    *orchestrator.Operation
+	operationErr error
+}
+
+func (s *stubCalcUseCase) ValidateExpression(context.Context, string) (*orchestrator.ExpressionValidation, error) {
+	return s.validation, s.validateErr
+}
+
+func (s *stubCalcUseCase) CalculateExpression(context.Context, uuid.UUID, string, orchestrator.CalculationSource) (*orchestrator.Calculation, error) {
+	if s.calculateErr != nil {
+		return nil, s.calculateErr
+	}
+	return s.calculation, nil
+}
+
+func (s *stubCalcUseCase) GetOperation(context.Context, uuid.UUID, uuid.UUID) (*orchestrator.Operation, error) {
+	return s.operation, s.operationErr
+}
+
+func (s *stubCalcUseCase) DeleteCalculation(context.Context, uuid.UUID, uuid.UUID) error {
+	return s.deleteErr
+}
+
+func (s *stubCalcUseCase) SubscribeCalculation(context.Context, uuid.UUID, uuid.UUID) (<-chan orchestrator.CalculationEvent, error) {
+	if s.subscribeErr != nil {
+		return nil, s.subscribeErr
+	}
+	return s.events, nil
+}
+
+func calculate(t *testing.T, calculation *orchestrator.Calculation) *httptest.ResponseRecorder {
+	t.Helper()
+
+	return calculateWith(t, &stubCalcUseCase{calculation: calculation})
+}
+
+func calculateWith(t *testing.T, calcUseCase *stubCalcUseCase) *httptest.ResponseRecorder {
+	t.Helper()
+
+	handler := handlers.NewHandler(calcUseCase)
+	authMiddleware := midleware.AuthMiddleware(&stubAuthUseCase{userID: uuid.New()})
+
+	ctx := logger.WithLogger(context.Background(), logger.New(zapcore.NewNopCore()))
+	req := httptest.NewRequestWithContext(ctx, http.MethodPost, "/api/v1/calculations", strings.NewReader(`{"expression":"2*3+4"}`))
+	req.Header.Set("Authorization", "Bearer token")
+
+	rec := httptest.NewRecorder()
+	authMiddleware(http.HandlerFunc(handler.CalculateExpression)).ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCalculateExpressionRetryAfter(t *testing.T) {
+	testCases := []struct {
+		name       string
+		status     orchestrator.CalculationStatus
+		estimate   time.Duration
+		retryAfter string
+	}{
+		{name: "Rounds estimate up to whole seconds", status: orchestrator.CalculationStatusInProgress, estimate: 2500 * time.Millisecond, retryAfter: "3"},
+		{name: "Exact seconds", status: orchestrator.CalculationStatusPending, estimate: 4 * time.Second, retryAfter: "4"},
+		{name: "Unknown estimate polls after one second", status: orchestrator.CalculationStatusInProgress, estimate: 0, retryAfter: "1"},
+		{name: "Failed calculation has nothing to poll", status: orchestrator.CalculationStatusError, estimate: time.Second, retryAfter: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := calculate(t, &orchestrator.Calculation{
+				ID:                uuid.New(),
+				Status:            tc.status,
+				EstimatedDuration: tc.estimate,
+			})
+
+			assert.Equal(t, http.StatusAccepted, rec.Code)
+			assert.Equal(t, tc.retryAfter, rec.Header().Get("Retry-After"))
+		})
+	}
+}
+
+func TestCalculateExpressionDatabaseUnavailable(t *testing.T) {
+	rec := calculateWith(t, &stubCalcUseCase{
+		calculateErr: fmt.Errorf("%w: connection refused", domainerrors.ErrDatabaseUnavailable),
+	})
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, rec.Body.String(), "database is unavailable")
+}
+
+func TestDeleteCalculation(t *testing.T) {
+	testCases := []struct {
+		name       string
+		id         string
+		deleteErr  error
+		statusCode int
+	}{
+		{name: "Deleted", id: uuid.NewString(), statusCode: http.StatusNoContent},
+		{name: "Invalid ID", id: "not-a-uuid", statusCode: http.StatusBadRequest},
+		{name: "Not found", id: uuid.NewString(), deleteErr: domainerrors.ErrCalculationNotFound, statusCode: http.StatusNotFound},
+		{name: "Another user's calculation", id: uuid.NewString(), deleteErr: domainerrors.ErrUnauthorizedAccess, statusCode: http.StatusForbidden},
+		{name: "Internal error", id: uuid.NewString(), deleteErr: domainerrors.ErrInternalError, statusCode: http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := handlers.NewHandler(&stubCalcUseCase{deleteErr: tc.deleteErr})
+
+			router := chi.NewRouter()
+			router.Use(midleware.AuthMiddleware(&stubAuthUseCase{userID: uuid.New()}))
+			router.Delete("/api/v1/calculations/{id}", handler.DeleteCalculation)
+
+			ctx := logger.WithLogger(context.Background(), logger.New(zapcore.NewNopCore()))
+			req := httptest.NewRequestWithContext(ctx, http.MethodDelete, "/api/v1/calculations/"+tc.id, nil)
+			req.Header.Set("Authorization", "Bearer token")
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.statusCode, rec.Code)
+		})
+	}
+}
+
+func TestGetOperation(t *testing.T) {
+	operation := &orchestrator.Operation{
+		ID:            uuid.New(),
+		CalculationID: uuid.New(),
+		OperationType: orchestrator.OperationTypeDivision,
+		Operand1:      "1",
+		Operand2:      "0",
+		Status:        orchestrator.OperationStatusError,
+		ErrorMessage:  "division by zero",
+		AgentID:       "agent-1",
+	}
+
+	testCases := []struct {
+		name         string
+		id           string
+		operationErr error
+		statusCode   int
+	}{
+		{name: "Found", id: operation.ID.String(), statusCode: http.StatusOK},
+		{name: "Invalid ID", id: "not-a-uuid", statusCode: http.StatusBadRequest},
+		{name: "Not found", id: uuid.NewString(), operationErr: domainerrors.ErrOperationNotFound, statusCode: http.StatusNotFound},
+		{name: "Another user's operation", id: uuid.NewString(), operationErr: domainerrors.ErrUnauthorizedAccess, statusCode: http.StatusForbidden},
+		{name: "Internal error", id: uuid.NewString(), operationErr: domainerrors.ErrInternalError, statusCode: http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			stub := &stubCalcUseCase{operationErr: tc.operationErr}
+			if tc.operationErr == nil {
+				stub.operation = operation
+			}
+			handler := handlers.NewHandler(stub)
+
+			router := chi.NewRouter()
+			router.Use(midleware.AuthMiddleware(&stubAuthUseCase{userID: uuid.New()}))
+			router.Get("/api/v1/operations/{id}", handler.GetOperation)
+
+			ctx := logger.WithLogger(context.Background(), logger.New(zapcore.NewNopCore()))
+			req := httptest.NewRequestWithContext(ctx, http.MethodGet, "/api/v1/operations/"+tc.id, nil)
+			req.Header.Set("Authorization", "Bearer token")
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.statusCode, rec.Code)
+			if tc.statusCode != http.StatusOK {
+				return
+			}
+
+			var got orchestrator.Operation
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			assert.Equal(t, *operation, got)
+		})
+	}
+}
+
+func TestValidateExpression(t *testing.T) {
+	offset := 2
+	testCases := []struct {
+		name        string
+		body        string
+		validation  *orchestrator.ExpressionValidation
+		validateErr error
+		statusCode  int
+		expected    string
+	}{
+		{
+			name:       "Valid expression",
+			body:       `{"expression":"2+2"}`,
+			validation: &orchestrator.ExpressionValidation{Expression: "2+2", Valid: true},
+			statusCode: http.StatusOK,
+			expected:   `{"expression":"2+2","valid":true}`,
+		},
+		{
+			name: "Invalid expression is reported in the body",
+			body: `{"expression":"2+"}`,
+			validation: &orchestrator.ExpressionValidation{
+				Expression: "2+", ErrorOffset: &offset, ErrorMessage: "expected operand",
+			},
+			statusCode: http.StatusOK,
+			expected:   `{"expression":"2+","valid":false,"error_offset":2,"error_message":"expected operand"}`,
+		},
+		{name: "Malformed body", body: `{`, statusCode: http.StatusBadRequest},
+		{name: "Timeout", body: `{"expression":"1"}`, validateErr: domainerrors.ErrParseTimeout, statusCode: http.StatusUnprocessableEntity},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := handlers.NewHandler(&stubCalcUseCase{validation: tc.validation, validateErr: tc.validateErr})
+
+			ctx := logger.WithLogger(context.Background(), logger.New(zapcore.NewNopCore()))
+			req := httptest.NewRequestWithContext(ctx, http.MethodPost, "/api/v1/calculations/validate", strings.NewReader(tc.body))
+
+			rec := httptest.NewRecorder()
+			handler.ValidateExpression(rec, req)
+
+			assert.Equal(t, tc.statusCode, rec.Code)
+			if tc.expected != "" {
+				assert.JSONEq(t, tc.expected, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestStreamCalculation(t *testing.T) {
+	calculationID := uuid.New()
+
+	t.Run("Streams status changes until final status", func(t *testing.T) {
+		events := make(chan orchestrator.CalculationEvent)
+		server := newStreamServer(&stubCalcUseCase{events: events})
+		defer server.Close()
+
+		resp := openStream(t, server.URL+"/api/v1/calculations/"+calculationID.String()+"/stream")
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+		reader := bufio.NewReader(resp.Body)
+
+		events <- orchestrator.CalculationEvent{CalculationID: calculationID, Status: orchestrator.CalculationStatusInProgress}
+		assert.Equal(t, orchestrator.CalculationStatusInProgress, readSSEEvent(t, reader).Status)
+
+		events <- orchestrator.CalculationEvent{CalculationID: calculationID, Status: orchestrator.CalculationStatusCompleted, Result: "10"}
+		final := readSSEEvent(t, reader)
+		assert.Equal(t, orchestrator.CalculationStatusCompleted, final.Status)
+		assert.Equal(t, "10", final.Result)
+
+		close(events)
+		_, err := reader.ReadString('\n')
+		assert.ErrorIs(t, err, io.EOF)
+	})
+
+	errorCases := []struct {
+		name       string
+		err        error
+		statusCode int
+	}{
+		{name: "Not found", err: domainerrors.ErrCalculationNotFound, statusCode: http.StatusNotFound},
+		{name: "Another user's calculation", err: domainerrors.ErrUnauthorizedAccess, statusCode: http.StatusForbidden},
+	}
+
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newStreamServer(&stubCalcUseCase{subscribeErr: tc.err})
+			defer server.Close()
+
+			resp := openStream(t, server.URL+"/api/v1/calculations/"+calculationID.String()+"/stream")
+			defer resp.Body.Close()
+
+			assert.Equal(t, tc.statusCode, resp.StatusCode)
+		})
+	}
+}
+
+func newStreamServer(calcUseCase *stubCalcUseCase) *httptest.Server {
+	handler := handlers.NewHandler(calcUseCase)
+
+	router := chi.NewRouter()
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := logger.WithLogger(r.Context(), logger.New(zapcore.NewNopCore()))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+	router.Use(midleware.Logger)
+	router.Use(midleware.AuthMiddleware(&stubAuthUseCase{userID: uuid.New()}))
+	router.Get("/api/v1/calculations/{id}/stream", handler.StreamCalculation)
+
+	return httptest.NewServer(router)
+}
+
+func openStream(t *testing.T, url string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer token")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	return resp
+}
+
+func readSSEEvent(t *testing.T, reader *bufio.Reader) orchestrator.CalculationEvent {
+	t.Helper()
+
+	var name, data string
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "" && data != "":
+			assert.Equal(t, "status", name)
+
+			var event orchestrator.CalculationEvent
+			require.NoError(t, json.Unmarshal([]byte(data), &event))
+			return event
+		case strings.HasPrefix(line, "event: "):
+			name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
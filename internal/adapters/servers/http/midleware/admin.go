@@ -0,0 +1,39 @@
+package midleware
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+var ErrAdminRequired = NewAPIError("admin role required", "AUTH_ADMIN_REQUIRED")
+
+// RequireAdmin пропускает запрос, только если пользователь из контекста входит в adminIDs.
+// Должен подключаться после AuthMiddleware. Некорректные идентификаторы в adminIDs игнорируются.
+func RequireAdmin(adminIDs []string) func(http.Handler) http.Handler {
+	admins := make(map[uuid.UUID]struct{}, len(adminIDs))
+	for _, id := range adminIDs {
+		parsed, err := uuid.Parse(id)
+		if err != nil || parsed == uuid.Nil {
+			continue
+		}
+		admins[parsed] = struct{}{}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, err := GetUserIDFromContext(r.Context())
+			if err != nil {
+				HandleError(r.Context(), w, err, http.StatusUnauthorized)
+				return
+			}
+
+			if _, ok := admins[userID]; !ok {
+				HandleError(r.Context(), w, ErrAdminRequired, http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
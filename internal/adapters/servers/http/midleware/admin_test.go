@@ -0,0 +1,42 @@
+package midleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestRequireAdmin(t *testing.T) {
+	adminID := uuid.New()
+	handler := RequireAdmin([]string{"not-a-uuid", adminID.String()})(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	serve := func(req *http.Request) int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	t.Run("Admin passes", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/admin", nil).WithContext(userContext(adminID))
+		assert.Equal(t, http.StatusNoContent, serve(req))
+	})
+
+	t.Run("Regular user is forbidden", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/admin", nil).WithContext(userContext(uuid.New()))
+		assert.Equal(t, http.StatusForbidden, serve(req))
+	})
+
+	t.Run("Missing user is unauthorized", func(t *testing.T) {
+		ctx := logger.WithLogger(context.Background(), logger.New(zapcore.NewNopCore()))
+		req := httptest.NewRequest(http.MethodGet, "/admin", nil).WithContext(ctx)
+		assert.Equal(t, http.StatusUnauthorized, serve(req))
+	})
+}
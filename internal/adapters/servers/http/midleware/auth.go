@@ -4,17 +4,23 @@ import (
 	"context"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/api/auth"
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/servertiming"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/metadata"
 )
 
 const (
 	authHeaderName   = "Authorization"
 	bearerScheme     = "Bearer"
 	bearerPartsCount = 2
+
+	// metadataAuthorization - ключ метаданных gRPC, в котором сервис оркестрации ожидает токен доступа.
+	metadataAuthorization = "authorization"
 )
 
 type userIDContextKey struct{}
@@ -57,14 +63,22 @@ func AuthMiddleware(authUseCase auth.UseCaseUser) func(http.Handler) http.Handle
 				return
 			}
 
-			userID, err := authUseCase.ValidateToken(r.Context(), parts[1])
+			// Проверка токена по gRPC записывается целиком как этап auth, а не grpc
+			start := time.Now()
+			userID, err := authUseCase.ValidateToken(servertiming.Without(r.Context()), parts[1])
+			servertiming.Since(r.Context(), servertiming.MetricAuth, start)
 			if err != nil {
 				logger.ContextLogger(r.Context(), nil).Error("token validation failed", zap.Error(err))
 				HandleError(r.Context(), w, ErrInvalidToken, http.StatusUnauthorized)
 				return
 			}
 
+			recordAccessLogUser(r.Context(), userID)
+
 			ctx := context.WithValue(r.Context(), userIDContextKey{}, userID)
+			// Токен передается дальше в исходящих метаданных: сервис оркестрации проверяет его
+			// сам и не доверяет ID пользователя, присланному шлюзом.
+			ctx = metadata.AppendToOutgoingContext(ctx, metadataAuthorization, authHeader)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
@@ -0,0 +1,43 @@
+package midleware
+
+import (
+	"net/http"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"go.uber.org/zap"
+)
+
+var ErrRequestTooLarge = NewAPIError("request body is too large", "REQUEST_TOO_LARGE")
+
+// MaxBodySize ограничивает тело запросов POST, PUT, PATCH и DELETE размером limit байт.
+// Запрос с заявленным Content-Length больше лимита отклоняется с кодом 413 до чтения тела;
+// иначе тело читается через http.MaxBytesReader, и обработчик, получивший ошибку
+// превышения при декодировании, отвечает 413 через HandleError. Запросы GET, в том числе
+// потоковые (SSE), не ограничиваются. Неположительный limit отключает ограничение.
+func MaxBodySize(limit int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if limit <= 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+			default:
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if r.ContentLength > limit {
+				logger.ContextLogger(r.Context(), nil).Warn("request body rejected",
+					zap.Int64("content_length", r.ContentLength),
+					zap.Int64("limit", limit))
+				HandleError(r.Context(), w, ErrRequestTooLarge, http.StatusRequestEntityTooLarge)
+				return
+			}
+
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
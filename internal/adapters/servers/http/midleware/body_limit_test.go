@@ -0,0 +1,113 @@
+package midleware
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// jsonBody возвращает тело {"expression":"1+1+...+1"} размером ровно size байт.
+func jsonBody(t *testing.T, size int) string {
+	t.Helper()
+
+	const prefix, suffix = `{"expression":"1`, `"}`
+	padding := size - len(prefix) - len(suffix)
+	require.GreaterOrEqual(t, padding, 0)
+	require.Zero(t, padding%2)
+
+	return prefix + strings.Repeat("+1", padding/2) + suffix
+}
+
+func TestMaxBodySize(t *testing.T) {
+	const limit = 64
+
+	// Обработчик декодирует тело так же, как обработчики шлюза
+	decoded := 0
+	decode := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Expression string `json:"expression"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			HandleError(r.Context(), w, err, http.StatusBadRequest)
+			return
+		}
+		decoded++
+		w.WriteHeader(http.StatusCreated)
+	})
+	handler := MaxBodySize(limit)(decode)
+
+	ctx := logger.WithLogger(context.Background(), logger.New(zapcore.NewNopCore()))
+	serve := func(method string, body io.Reader, contentLength int64) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/v1/calculations/", body).WithContext(ctx)
+		req.ContentLength = contentLength
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+	assertTooLarge := func(t *testing.T, rec *httptest.ResponseRecorder) {
+		t.Helper()
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+		var resp ErrorResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, "REQUEST_TOO_LARGE", resp.Error.Code)
+	}
+
+	t.Run("Body just under the limit", func(t *testing.T) {
+		body := jsonBody(t, limit-2)
+		rec := serve(http.MethodPost, strings.NewReader(body), int64(len(body)))
+		assert.Equal(t, http.StatusCreated, rec.Code)
+	})
+
+	t.Run("Body at the limit", func(t *testing.T) {
+		body := jsonBody(t, limit)
+		rec := serve(http.MethodPost, strings.NewReader(body), int64(len(body)))
+		assert.Equal(t, http.StatusCreated, rec.Code)
+	})
+
+	t.Run("Body just over the limit", func(t *testing.T) {
+		before := decoded
+		body := jsonBody(t, limit+2)
+		assertTooLarge(t, serve(http.MethodPost, strings.NewReader(body), int64(len(body))))
+		assert.Equal(t, before, decoded)
+	})
+
+	t.Run("Body of unknown length over the limit", func(t *testing.T) {
+		before := decoded
+		body := jsonBody(t, limit+2)
+		assertTooLarge(t, serve(http.MethodPost, io.NopCloser(strings.NewReader(body)), -1))
+		assert.Equal(t, before, decoded)
+	})
+
+	t.Run("Streams are not limited", func(t *testing.T) {
+		streamed := false
+		stream := MaxBodySize(limit)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n, err := io.Copy(io.Discard, r.Body)
+			require.NoError(t, err)
+			assert.Equal(t, int64(limit*2), n)
+			streamed = true
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/calculations/1/stream", strings.NewReader(strings.Repeat("x", limit*2))).WithContext(ctx)
+		stream.ServeHTTP(httptest.NewRecorder(), req)
+		assert.True(t, streamed)
+	})
+
+	t.Run("Zero limit disables the check", func(t *testing.T) {
+		unlimited := MaxBodySize(0)(decode)
+		body := jsonBody(t, limit*4)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/calculations/", strings.NewReader(body)).WithContext(ctx)
+		rec := httptest.NewRecorder()
+		unlimited.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+	})
+}
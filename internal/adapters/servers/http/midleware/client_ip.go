@@ -0,0 +1,119 @@
+package midleware
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+const (
+	headerRealIP       = "X-Real-IP"
+	headerForwardedFor = "X-Forwarded-For"
+)
+
+type clientIPContextKey struct{}
+
+// ParseTrustedProxies разбирает список доверенных прокси: адреса или подсети в нотации CIDR.
+// Вызывается при запуске, чтобы ошибка в конфигурации не приводила к молчаливому
+// использованию адреса прокси вместо адреса клиента.
+func ParseTrustedProxies(proxies []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(proxies))
+	for _, proxy := range proxies {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+
+		if strings.Contains(proxy, "/") {
+			prefix, err := netip.ParsePrefix(proxy)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+
+		addr, err := netip.ParseAddr(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
+
+// ClientIP определяет IP-адрес клиента и сохраняет его в контексте запроса.
+// Заголовкам X-Forwarded-For и X-Real-IP верит только если запрос пришел
+// от доверенного прокси: из X-Forwarded-For берется ближайший к серверу адрес,
+// не принадлежащий доверенным прокси. Иначе используется адрес соединения.
+// Некорректные записи trustedProxies пропускаются - они проверяются при запуске
+// через ParseTrustedProxies.
+func ClientIP(trustedProxies []string) func(http.Handler) http.Handler {
+	var trusted []netip.Prefix
+	for _, proxy := range trustedProxies {
+		if prefixes, err := ParseTrustedProxies([]string{proxy}); err == nil {
+			trusted = append(trusted, prefixes...)
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := resolveClientIP(r, trusted)
+			ctx := context.WithValue(r.Context(), clientIPContextKey{}, ip)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// GetClientIPFromContext возвращает IP-адрес клиента, определенный ClientIP,
+// или пустую строку, если middleware не применялся.
+func GetClientIPFromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPContextKey{}).(string)
+	return ip
+}
+
+// resolveClientIP возвращает IP-адрес клиента с учетом доверенных прокси.
+func resolveClientIP(r *http.Request, trusted []netip.Prefix) string {
+	remote := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		remote = host
+	}
+
+	remoteAddr, err := netip.ParseAddr(remote)
+	if err != nil || !isTrustedProxy(remoteAddr, trusted) {
+		return remote
+	}
+
+	if forwarded := r.Header.Values(headerForwardedFor); len(forwarded) > 0 {
+		hops := strings.Split(strings.Join(forwarded, ","), ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+			if err != nil {
+				break
+			}
+			if !isTrustedProxy(hop, trusted) {
+				return hop.Unmap().String()
+			}
+		}
+	}
+
+	if realIP, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get(headerRealIP))); err == nil {
+		return realIP.Unmap().String()
+	}
+
+	return remote
+}
+
+func isTrustedProxy(addr netip.Addr, trusted []netip.Prefix) bool {
+	addr = addr.Unmap()
+	for _, prefix := range trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
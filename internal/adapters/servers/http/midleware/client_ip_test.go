@@ -0,0 +1,91 @@
+package midleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientIP(t *testing.T) {
+	handler := func(trusted []string) http.Handler {
+		return ClientIP(trusted)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(GetClientIPFromContext(r.Context())))
+		}))
+	}
+
+	tests := []struct {
+		name       string
+		trusted    []string
+		remoteAddr string
+		headers    map[string]string
+		want       string
+	}{
+		{
+			name:       "no proxy configured ignores headers",
+			remoteAddr: "203.0.113.7:5000",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1", "X-Real-IP": "198.51.100.1"},
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "untrusted peer cannot spoof",
+			trusted:    []string{"10.0.0.0/8"},
+			remoteAddr: "203.0.113.7:5000",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1"},
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "trusted proxy forwarded for",
+			trusted:    []string{"10.0.0.0/8"},
+			remoteAddr: "10.0.0.2:5000",
+			headers:    map[string]string{"X-Forwarded-For": "192.0.2.99, 198.51.100.1"},
+			want:       "198.51.100.1",
+		},
+		{
+			name:       "trusted hops skipped",
+			trusted:    []string{"10.0.0.0/8", "172.16.0.5"},
+			remoteAddr: "10.0.0.2:5000",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1, 172.16.0.5"},
+			want:       "198.51.100.1",
+		},
+		{
+			name:       "trusted proxy real ip",
+			trusted:    []string{"10.0.0.2"},
+			remoteAddr: "10.0.0.2:5000",
+			headers:    map[string]string{"X-Real-IP": "198.51.100.1"},
+			want:       "198.51.100.1",
+		},
+		{
+			name:       "trusted proxy without headers",
+			trusted:    []string{"10.0.0.0/8"},
+			remoteAddr: "10.0.0.2:5000",
+			want:       "10.0.0.2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			handler(tt.trusted).ServeHTTP(rec, req)
+			assert.Equal(t, tt.want, rec.Body.String())
+		})
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	prefixes, err := ParseTrustedProxies([]string{"10.0.0.0/8", " 192.168.1.1 ", "", "::1"})
+	require.NoError(t, err)
+	assert.Len(t, prefixes, 3)
+
+	_, err = ParseTrustedProxies([]string{"nginx"})
+	assert.Error(t, err)
+	_, err = ParseTrustedProxies([]string{"10.0.0.0/33"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,24 @@
+package midleware
+
+import (
+	"net/http"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+)
+
+// ClientVersion сохраняет в контексте версию клиента из заголовка header, чтобы она
+// записалась вместе с вычислением. Пустое имя заголовка отключает сохранение.
+func ClientVersion(header string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if header == "" {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if version := r.Header.Get(header); version != "" {
+				r = r.WithContext(orchestrator.WithClientVersion(r.Context(), version))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
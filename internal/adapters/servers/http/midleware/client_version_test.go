@@ -0,0 +1,30 @@
+package midleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientVersion(t *testing.T) {
+	serve := func(header, value string) string {
+		var version string
+		handler := ClientVersion(header)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			version = orchestrator.ClientVersionFromContext(r.Context())
+		}))
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/calculations/", nil)
+		if value != "" {
+			req.Header.Set("X-Client-Version", value)
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		return version
+	}
+
+	assert.Equal(t, "calc-cli/2.1.0", serve("X-Client-Version", " calc-cli/2.1.0 "))
+	assert.Empty(t, serve("X-Client-Version", ""), "header not sent")
+	assert.Empty(t, serve("", "calc-cli/2.1.0"), "storing client version disabled")
+}
@@ -0,0 +1,29 @@
+package midleware
+
+import (
+	"net/http"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/setup/server"
+	"github.com/go-chi/cors"
+)
+
+// corsExposedHeaders - заголовки ответа, доступные скриптам страницы.
+var corsExposedHeaders = []string{"Link", headerRequestID, headerRetryAfter}
+
+// CORS разрешает запросы к API со страниц источников из cfg.AllowedOrigins и отвечает
+// на предварительные запросы OPTIONS. Без настроенных источников middleware ничего
+// не делает: ответы не содержат заголовков CORS, и браузер запрещает такие запросы.
+func CORS(cfg server.CORSConfig) func(http.Handler) http.Handler {
+	if len(cfg.AllowedOrigins) == 0 {
+		return func(next http.Handler) http.Handler { return next }
+	}
+
+	return cors.Handler(cors.Options{
+		AllowedOrigins:   cfg.AllowedOrigins,
+		AllowedMethods:   cfg.AllowedMethods,
+		AllowedHeaders:   cfg.AllowedHeaders,
+		ExposedHeaders:   corsExposedHeaders,
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           int(cfg.MaxAge.Seconds()),
+	})
+}
@@ -0,0 +1,88 @@
+package midleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/setup/server"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	headerOrigin           = "Origin"
+	headerAllowOrigin      = "Access-Control-Allow-Origin"
+	headerAllowMethods     = "Access-Control-Allow-Methods"
+	headerAllowHeaders     = "Access-Control-Allow-Headers"
+	headerAllowCredentials = "Access-Control-Allow-Credentials"
+	headerMaxAge           = "Access-Control-Max-Age"
+	headerRequestMethod    = "Access-Control-Request-Method"
+	headerRequestHeaders   = "Access-Control-Request-Headers"
+	allowedOrigin          = "https://calc.example.com"
+	disallowedOrigin       = "https://evil.example.com"
+)
+
+func TestCORS(t *testing.T) {
+	cfg := server.CORSConfig{
+		AllowedOrigins:   []string{allowedOrigin},
+		AllowedMethods:   []string{http.MethodGet, http.MethodPost},
+		AllowedHeaders:   []string{"Authorization", "Content-Type"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}
+
+	serve := func(cfg server.CORSConfig, req *http.Request) *httptest.ResponseRecorder {
+		handler := CORS(cfg)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("Allowed origin", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/calculations", nil)
+		req.Header.Set(headerOrigin, allowedOrigin)
+
+		rec := serve(cfg, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, allowedOrigin, rec.Header().Get(headerAllowOrigin))
+		assert.Equal(t, "true", rec.Header().Get(headerAllowCredentials))
+	})
+
+	t.Run("Disallowed origin", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/calculations", nil)
+		req.Header.Set(headerOrigin, disallowedOrigin)
+
+		rec := serve(cfg, req)
+
+		assert.Empty(t, rec.Header().Get(headerAllowOrigin))
+	})
+
+	t.Run("Preflight request", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodOptions, "/api/v1/calculations", nil)
+		req.Header.Set(headerOrigin, allowedOrigin)
+		req.Header.Set(headerRequestMethod, http.MethodPost)
+		req.Header.Set(headerRequestHeaders, "Authorization")
+
+		rec := serve(cfg, req)
+
+		assert.Equal(t, allowedOrigin, rec.Header().Get(headerAllowOrigin))
+		assert.Equal(t, http.MethodPost, rec.Header().Get(headerAllowMethods))
+		assert.Equal(t, "Authorization", rec.Header().Get(headerAllowHeaders))
+		assert.Equal(t, "600", rec.Header().Get(headerMaxAge))
+	})
+
+	t.Run("No origins configured", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodOptions, "/api/v1/calculations", nil)
+		req.Header.Set(headerOrigin, allowedOrigin)
+		req.Header.Set(headerRequestMethod, http.MethodPost)
+
+		rec := serve(server.CORSConfig{AllowedMethods: cfg.AllowedMethods}, req)
+
+		assert.Empty(t, rec.Header().Get(headerAllowOrigin))
+		assert.Empty(t, rec.Header().Get(headerAllowMethods))
+	})
+}
@@ -0,0 +1,70 @@
+package midleware
+
+import (
+	"errors"
+	"net/http"
+
+	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
+)
+
+// domainErrorStatuses сопоставляет доменным ошибкам HTTP статусы по умолчанию. Обработчик
+// может выбрать для своего эндпоинта другой статус: неверный пароль при входе дает 401,
+// а при удалении учетной записи - 403.
+var domainErrorStatuses = []struct {
+	err    error
+	status int
+}{
+	{domainerrors.ErrInvalidExpression, http.StatusBadRequest},
+	{domainerrors.ErrInvalidOperand, http.StatusBadRequest},
+	{domainerrors.ErrUnsupportedOp, http.StatusBadRequest},
+	{domainerrors.ErrInvalidUserID, http.StatusBadRequest},
+	{domainerrors.ErrInvalidArgs, http.StatusBadRequest},
+	{domainerrors.ErrInvalidSource, http.StatusBadRequest},
+	{domainerrors.ErrInvalidPriority, http.StatusBadRequest},
+	{domainerrors.ErrInvalidIdempotencyKey, http.StatusBadRequest},
+	{domainerrors.ErrInvalidPagination, http.StatusBadRequest},
+	{domainerrors.ErrInvalidStatusFilter, http.StatusBadRequest},
+	{domainerrors.ErrInvalidResultFormat, http.StatusBadRequest},
+	{domainerrors.ErrInvalidTolerance, http.StatusBadRequest},
+	{domainerrors.ErrInvalidCapacity, http.StatusBadRequest},
+	{domainerrors.ErrWeakPassword, http.StatusBadRequest},
+	{domainerrors.ErrSamePassword, http.StatusBadRequest},
+	{domainerrors.ErrInvalidCredentials, http.StatusUnauthorized},
+	{domainerrors.ErrInvalidToken, http.StatusUnauthorized},
+	{domainerrors.ErrTokenExpired, http.StatusUnauthorized},
+	{domainerrors.ErrTokenRevoked, http.StatusUnauthorized},
+	{domainerrors.ErrSessionTooOld, http.StatusUnauthorized},
+	{domainerrors.ErrUnauthorizedAccess, http.StatusForbidden},
+	{domainerrors.ErrCalculationNotFound, http.StatusNotFound},
+	{domainerrors.ErrSpecificCalcNotFound, http.StatusNotFound},
+	{domainerrors.ErrOperationNotFound, http.StatusNotFound},
+	{domainerrors.ErrUserNotFound, http.StatusNotFound},
+	{domainerrors.ErrTokenNotFound, http.StatusNotFound},
+	{domainerrors.ErrAgentNotFound, http.StatusNotFound},
+	{domainerrors.ErrUserAlreadyExists, http.StatusConflict},
+	{domainerrors.ErrAgentAlreadyExists, http.StatusConflict},
+	{domainerrors.ErrIdempotencyKeyConflict, http.StatusConflict},
+	{domainerrors.ErrCalcNotCancellable, http.StatusConflict},
+	{domainerrors.ErrTooManyOps, http.StatusUnprocessableEntity},
+	{domainerrors.ErrParseTimeout, http.StatusUnprocessableEntity},
+	{domainerrors.ErrReferenceNotCompleted, http.StatusUnprocessableEntity},
+	{domainerrors.ErrTooManyReferences, http.StatusUnprocessableEntity},
+	{domainerrors.ErrNonIntegerResult, http.StatusUnprocessableEntity},
+	{domainerrors.ErrTooManyAttempts, http.StatusTooManyRequests},
+	{domainerrors.ErrPendingOpsBudget, http.StatusTooManyRequests},
+	{domainerrors.ErrNilPool, http.StatusServiceUnavailable},
+	{domainerrors.ErrPoolNotRunning, http.StatusServiceUnavailable},
+	{domainerrors.ErrPoolDraining, http.StatusServiceUnavailable},
+	{domainerrors.ErrDatabaseUnavailable, http.StatusServiceUnavailable},
+}
+
+// ErrorStatus возвращает HTTP статус по умолчанию для доменной ошибки в цепочке err
+// или fallback, если ошибка не сопоставлена статусу.
+func ErrorStatus(err error, fallback int) int {
+	for _, mapping := range domainErrorStatuses {
+		if errors.Is(err, mapping.err) {
+			return mapping.status
+		}
+	}
+	return fallback
+}
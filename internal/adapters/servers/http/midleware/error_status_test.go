@@ -0,0 +1,102 @@
+package midleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestDomainErrorResponses(t *testing.T) {
+	testCases := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{domainerrors.ErrInvalidExpression, http.StatusBadRequest, "CALC_INVALID_EXPRESSION"},
+		{domainerrors.ErrInvalidOperand, http.StatusBadRequest, "CALC_INVALID_OPERAND"},
+		{domainerrors.ErrUnsupportedOp, http.StatusBadRequest, "CALC_UNSUPPORTED_OPERATION"},
+		{domainerrors.ErrInvalidUserID, http.StatusBadRequest, "CALC_INVALID_USER_ID"},
+		{domainerrors.ErrInvalidArgs, http.StatusBadRequest, "INVALID_ARGUMENT"},
+		{domainerrors.ErrInvalidSource, http.StatusBadRequest, "CALC_INVALID_SOURCE"},
+		{domainerrors.ErrInvalidPriority, http.StatusBadRequest, "CALC_INVALID_PRIORITY"},
+		{domainerrors.ErrInvalidIdempotencyKey, http.StatusBadRequest, "CALC_INVALID_IDEMPOTENCY_KEY"},
+		{domainerrors.ErrInvalidPagination, http.StatusBadRequest, "INVALID_PAGINATION"},
+		{domainerrors.ErrInvalidStatusFilter, http.StatusBadRequest, "CALC_INVALID_STATUS_FILTER"},
+		{domainerrors.ErrInvalidResultFormat, http.StatusBadRequest, "CALC_INVALID_RESULT_FORMAT"},
+		{domainerrors.ErrInvalidTolerance, http.StatusBadRequest, "CALC_INVALID_TOLERANCE"},
+		{domainerrors.ErrInvalidCapacity, http.StatusBadRequest, "AGENT_INVALID_CAPACITY"},
+		{domainerrors.ErrWeakPassword, http.StatusBadRequest, "AUTH_WEAK_PASSWORD"},
+		{domainerrors.ErrSamePassword, http.StatusBadRequest, "AUTH_SAME_PASSWORD"},
+		{domainerrors.ErrInvalidCredentials, http.StatusUnauthorized, "AUTH_INVALID_CREDENTIALS"},
+		{domainerrors.ErrInvalidToken, http.StatusUnauthorized, "AUTH_INVALID_TOKEN"},
+		{domainerrors.ErrTokenExpired, http.StatusUnauthorized, "AUTH_TOKEN_EXPIRED"},
+		{domainerrors.ErrTokenRevoked, http.StatusUnauthorized, "AUTH_TOKEN_REVOKED"},
+		{domainerrors.ErrSessionTooOld, http.StatusUnauthorized, "AUTH_SESSION_TOO_OLD"},
+		{domainerrors.ErrUnauthorizedAccess, http.StatusForbidden, "CALC_FORBIDDEN"},
+		{domainerrors.ErrCalculationNotFound, http.StatusNotFound, "CALC_NOT_FOUND"},
+		{domainerrors.ErrSpecificCalcNotFound, http.StatusNotFound, "CALC_NOT_FOUND"},
+		{domainerrors.ErrOperationNotFound, http.StatusNotFound, "OPERATION_NOT_FOUND"},
+		{domainerrors.ErrUserNotFound, http.StatusNotFound, "AUTH_USER_NOT_FOUND"},
+		{domainerrors.ErrTokenNotFound, http.StatusNotFound, "AUTH_TOKEN_NOT_FOUND"},
+		{domainerrors.ErrAgentNotFound, http.StatusNotFound, "AGENT_NOT_FOUND"},
+		{domainerrors.ErrUserAlreadyExists, http.StatusConflict, "AUTH_USER_ALREADY_EXISTS"},
+		{domainerrors.ErrAgentAlreadyExists, http.StatusConflict, "AGENT_ALREADY_EXISTS"},
+		{domainerrors.ErrIdempotencyKeyConflict, http.StatusConflict, "CALC_IDEMPOTENCY_KEY_CONFLICT"},
+		{domainerrors.ErrCalcNotCancellable, http.StatusConflict, "CALC_NOT_CANCELLABLE"},
+		{domainerrors.ErrTooManyOps, http.StatusUnprocessableEntity, "CALC_TOO_MANY_OPERATIONS"},
+		{domainerrors.ErrParseTimeout, http.StatusUnprocessableEntity, "CALC_PARSE_TIMEOUT"},
+		{domainerrors.ErrReferenceNotCompleted, http.StatusUnprocessableEntity, "CALC_REFERENCE_NOT_COMPLETED"},
+		{domainerrors.ErrTooManyReferences, http.StatusUnprocessableEntity, "CALC_TOO_MANY_REFERENCES"},
+		{domainerrors.ErrNonIntegerResult, http.StatusUnprocessableEntity, "CALC_NON_INTEGER_RESULT"},
+		{domainerrors.ErrTooManyAttempts, http.StatusTooManyRequests, "AUTH_TOO_MANY_ATTEMPTS"},
+		{domainerrors.ErrPendingOpsBudget, http.StatusTooManyRequests, "CALC_PENDING_OPERATIONS_LIMIT"},
+		{domainerrors.ErrNilPool, http.StatusServiceUnavailable, "INTERNAL_ERROR"},
+		{domainerrors.ErrPoolNotRunning, http.StatusServiceUnavailable, "AGENT_POOL_NOT_RUNNING"},
+		{domainerrors.ErrPoolDraining, http.StatusServiceUnavailable, "AGENT_POOL_DRAINING"},
+		{domainerrors.ErrDatabaseUnavailable, http.StatusServiceUnavailable, "DATABASE_UNAVAILABLE"},
+		{domainerrors.ErrDivisionByZero, http.StatusInternalServerError, "CALC_DIVISION_BY_ZERO"},
+		{domainerrors.ErrInternalServerError, http.StatusInternalServerError, "INTERNAL_ERROR"},
+		{errors.New("connection reset"), http.StatusInternalServerError, "INTERNAL_ERROR"},
+	}
+
+	require.Len(t, domainErrorStatuses, len(testCases)-3, "every mapped error is covered")
+
+	ctx := logger.WithLogger(context.Background(), logger.New(zapcore.NewNopCore()))
+	for _, tc := range testCases {
+		t.Run(tc.err.Error(), func(t *testing.T) {
+			// Обработчики получают ошибки, обернутые сервисами и клиентами gRPC
+			err := fmt.Errorf("CalculationUseCase.Get: %w", tc.err)
+
+			status := ErrorStatus(err, http.StatusInternalServerError)
+			assert.Equal(t, tc.status, status)
+
+			rec := httptest.NewRecorder()
+			HandleError(ctx, rec, err, status)
+			assert.Equal(t, tc.status, rec.Code)
+
+			var body map[string]map[string]string
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.Equal(t, tc.code, body["error"]["code"])
+			assert.Equal(t, err.Error(), body["error"]["message"])
+		})
+	}
+
+	t.Run("API error keeps its code", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		HandleError(ctx, rec, ErrMissingToken, http.StatusUnauthorized)
+
+		var body ErrorResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, "AUTH_MISSING_TOKEN", body.Error.Code)
+	})
+}
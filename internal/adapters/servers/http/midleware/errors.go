@@ -6,11 +6,16 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
+	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/servertiming"
 	"go.uber.org/zap"
 )
 
+// ErrorResponse - тело ответа с ошибкой. Code - стабильный машиночитаемый код: код APIError
+// или доменной ошибки, INTERNAL_ERROR для прочих ошибок.
 type ErrorResponse struct {
 	Error struct {
 		Message string `json:"message"`
@@ -53,6 +58,12 @@ func ErrorHandler(next http.Handler) http.Handler {
 func HandleError(ctx context.Context, w http.ResponseWriter, err error, statusCode int) {
 	response := ErrorResponse{}
 
+	// Тело, превысившее лимит MaxBodySize, обработчики получают как ошибку декодирования
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		err, statusCode = ErrRequestTooLarge, http.StatusRequestEntityTooLarge
+	}
+
 	// Check if the error is our custom APIError type
 	var apiErr APIError
 	if errors.As(err, &apiErr) {
@@ -61,18 +72,30 @@ func HandleError(ctx context.Context, w http.ResponseWriter, err error, statusCo
 	} else {
 		// For standard errors
 		response.Error.Message = err.Error()
-		response.Error.Code = "INTERNAL_ERROR"
+		response.Error.Code = domainerrors.Code(err)
+		if response.Error.Code == "" {
+			response.Error.Code = domainerrors.CodeInternal
+		}
 	}
 
+	// Ответ сериализуется до отправки заголовков, чтобы длительность попала в Server-Timing
+	start := time.Now()
+	body, encodeErr := json.Marshal(response)
+	servertiming.Since(ctx, servertiming.MetricSerialize, start)
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
 
-	if encodeErr := json.NewEncoder(w).Encode(response); encodeErr != nil {
+	if encodeErr != nil {
 		// If we failed to encode the error response, log it
 		log := logger.ContextLogger(ctx, nil)
 		log.Error("failed to encode error response",
 			zap.Error(encodeErr),
 			zap.Error(err),
 			zap.Int("status_code", statusCode))
+		return
+	}
+	if _, writeErr := w.Write(append(body, '\n')); writeErr != nil {
+		logger.ContextLogger(ctx, nil).Error("failed to write error response", zap.Error(writeErr))
 	}
 }
@@ -0,0 +1,21 @@
+package midleware
+
+import (
+	"net/http"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+)
+
+const headerIdempotencyKey = "Idempotency-Key"
+
+// IdempotencyKey сохраняет в контексте ключ идемпотентности из заголовка Idempotency-Key.
+// Клиент, повторяющий отправку после сбоя сети, получает уже созданное вычисление
+// вместо дубликата.
+func IdempotencyKey(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if key := r.Header.Get(headerIdempotencyKey); key != "" {
+			r = r.WithContext(orchestrator.WithIdempotencyKey(r.Context(), key))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
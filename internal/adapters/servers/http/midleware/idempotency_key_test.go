@@ -0,0 +1,29 @@
+package midleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	serve := func(value string) string {
+		var key string
+		handler := IdempotencyKey(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			key = orchestrator.IdempotencyKeyFromContext(r.Context())
+		}))
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/calculations/", nil)
+		if value != "" {
+			req.Header.Set("Idempotency-Key", value)
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		return key
+	}
+
+	assert.Equal(t, "retry-42", serve(" retry-42 "))
+	assert.Empty(t, serve(""), "header not sent")
+}
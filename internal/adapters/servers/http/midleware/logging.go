@@ -1,7 +1,9 @@
 package midleware
 
 import (
+	"context"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
@@ -11,50 +13,101 @@ import (
 
 const (
 	headerRequestID = "X-Request-ID"
+
+	// maxRequestIDLength - наибольшая длина идентификатора запроса, принятого от клиента.
+	maxRequestIDLength = 64
+
+	msgRequestCompleted = "Request completed"
 )
 
+// accessLogEntry собирает сведения о запросе, которые становятся известны только
+// внутренним обработчикам, например ID пользователя после аутентификации.
+type accessLogEntry struct {
+	userID uuid.UUID
+}
+
+type accessLogContextKey struct{}
+
+// fallbackLogger - журнал запросов, в контексте которых нет журнала сервиса.
+var fallbackLogger = sync.OnceValue(func() logger.ZapLogger {
+	if devLogger, err := logger.Development(); err == nil {
+		return devLogger
+	}
+	return logger.Console(logger.InfoLevel, false)
+})
+
+// Logger пишет одну запись журнала на каждый запрос: метод, путь, адрес и User-Agent
+// клиента, статус ответа, длительность, идентификатор запроса и ID пользователя, если запрос
+// прошел AuthMiddleware. Журнал с полями запроса передается внутренним обработчикам.
+// Идентификатор запроса берется из заголовка X-Request-ID, если он не длиннее
+// maxRequestIDLength и состоит из латинских букв, цифр и символов "-", "_", ".", иначе
+// создается новый. Он передается внутренним обработчикам и возвращается клиенту в том же
+// заголовке. Если в контексте запроса нет журнала сервиса, используется журнал разработки.
 func Logger(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		startTime := time.Now()
 
 		requestID := r.Header.Get(headerRequestID)
-		if requestID == "" {
-			requestID = uuid.New().String()
-			r.Header.Set(headerRequestID, requestID)
+		if !validRequestID(requestID) {
+			requestID = logger.GenerateRequestID()
 		}
-
+		r.Header.Set(headerRequestID, requestID)
 		w.Header().Set(headerRequestID, requestID)
 
 		ctx := logger.WithRequestID(r.Context(), requestID)
+		entry := &accessLogEntry{}
+		ctx = context.WithValue(ctx, accessLogContextKey{}, entry)
 
-		defaultLogger, err := logger.Development()
-		if err != nil {
-			defaultLogger = logger.Console(logger.InfoLevel, false)
+		baseLogger, ok := logger.FromContext(ctx)
+		if !ok {
+			baseLogger = fallbackLogger()
 		}
-
-		log := logger.ContextLogger(ctx, defaultLogger)
-
-		log = log.With(
-			zap.String("request_id", requestID),
+		ctx = logger.WithLogger(ctx, baseLogger.With(
 			zap.String("method", r.Method),
 			zap.String("path", r.URL.Path),
 			zap.String("remote_addr", r.RemoteAddr),
 			zap.String("user_agent", r.UserAgent()),
-		).(logger.ZapLogger)
+		))
 
-		ctx = logger.WithLogger(ctx, log)
-		r = r.WithContext(ctx)
+		ww := &responseWriterWrapper{ResponseWriter: w}
+		next.ServeHTTP(ww, r.WithContext(ctx))
 
-		ww := &responseWriterWrapper{ResponseWriter: w, statusCode: http.StatusOK}
-
-		log.Info("Request started")
+		status := ww.statusCode
+		if status == 0 {
+			status = http.StatusOK
+		}
 
-		next.ServeHTTP(ww, r)
+		fields := []logger.Field{
+			zap.Int("status", status),
+			zap.Duration("duration", time.Since(startTime)),
+		}
+		if entry.userID != uuid.Nil {
+			fields = append(fields, zap.String("user_id", entry.userID.String()))
+		}
 
-		duration := time.Since(startTime)
-		log.Info("Request completed",
-			zap.Int("status", ww.statusCode),
-			zap.Duration("duration", duration),
-		)
+		logger.ContextLogger(ctx, nil).Info(msgRequestCompleted, fields...)
 	})
 }
+
+// recordAccessLogUser сообщает Logger ID аутентифицированного пользователя.
+func recordAccessLogUser(ctx context.Context, userID uuid.UUID) {
+	if entry, ok := ctx.Value(accessLogContextKey{}).(*accessLogEntry); ok {
+		entry.userID = userID
+	}
+}
+
+// validRequestID проверяет идентификатор запроса, присланный клиентом: он попадает
+// в журналы и заголовки ответа, поэтому длина и набор символов ограничены.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_', c == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,122 @@
+package midleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/api/auth"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type stubTokenValidator struct {
+	auth.UseCaseUser
+	userID uuid.UUID
+}
+
+func (s stubTokenValidator) ValidateToken(_ context.Context, token string) (uuid.UUID, error) {
+	if token != "valid" {
+		return uuid.Nil, errors.New("invalid token")
+	}
+	return s.userID, nil
+}
+
+func TestLogger(t *testing.T) {
+	userID := uuid.New()
+	handler := Logger(AuthMiddleware(stubTokenValidator{userID: userID})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, ok := logger.RequestID(r.Context())
+		assert.True(t, ok)
+		assert.Equal(t, id, r.Header.Get(headerRequestID), "inner handlers see the same request ID")
+		w.WriteHeader(http.StatusCreated)
+	})))
+
+	serve := func(authorization, requestID string) (*httptest.ResponseRecorder, observer.LoggedEntry) {
+		core, logs := observer.New(zapcore.InfoLevel)
+		ctx := logger.WithLogger(context.Background(), logger.New(core))
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/calculations/", nil).WithContext(ctx)
+		if authorization != "" {
+			req.Header.Set(authHeaderName, authorization)
+		}
+		if requestID != "" {
+			req.Header.Set(headerRequestID, requestID)
+		}
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		entries := logs.FilterMessage(msgRequestCompleted).All()
+		require.Len(t, entries, 1)
+		return rec, entries[0]
+	}
+
+	t.Run("Authenticated request", func(t *testing.T) {
+		rec, entry := serve("Bearer valid", "req-42")
+		fields := entry.ContextMap()
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, "req-42", rec.Header().Get(headerRequestID))
+		assert.Equal(t, zapcore.InfoLevel, entry.Level)
+		assert.Equal(t, http.MethodPost, fields["method"])
+		assert.Equal(t, "/api/v1/calculations/", fields["path"])
+		assert.Contains(t, fields, "remote_addr")
+		assert.EqualValues(t, http.StatusCreated, fields["status"])
+		assert.Contains(t, fields, "duration")
+		assert.Equal(t, "req-42", fields[logger.RequestIDField])
+		assert.Equal(t, userID.String(), fields["user_id"])
+	})
+
+	t.Run("Rejected request without user", func(t *testing.T) {
+		rec, entry := serve("Bearer forged", "")
+		fields := entry.ContextMap()
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.EqualValues(t, http.StatusUnauthorized, fields["status"])
+		assert.NotEmpty(t, fields[logger.RequestIDField])
+		assert.Equal(t, rec.Header().Get(headerRequestID), fields[logger.RequestIDField])
+		assert.NotContains(t, fields, "user_id")
+	})
+
+	t.Run("Invalid request ID is replaced", func(t *testing.T) {
+		for _, requestID := range []string{
+			strings.Repeat("a", maxRequestIDLength+1),
+			"req 42",
+			"req-42\r\nX-Injected: 1",
+			"запрос",
+		} {
+			rec, entry := serve("Bearer valid", requestID)
+
+			generated := rec.Header().Get(headerRequestID)
+			assert.NotEqual(t, requestID, generated)
+			assert.True(t, validRequestID(generated), generated)
+			assert.Equal(t, generated, entry.ContextMap()[logger.RequestIDField])
+		}
+	})
+
+	t.Run("Longest valid request ID is kept", func(t *testing.T) {
+		requestID := strings.Repeat("a", maxRequestIDLength-4) + "-_.9"
+		rec, _ := serve("Bearer valid", requestID)
+
+		assert.Equal(t, requestID, rec.Header().Get(headerRequestID))
+	})
+}
+
+func TestLoggerWithoutLogger(t *testing.T) {
+	handler := Logger(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get(headerRequestID))
+}
@@ -0,0 +1,136 @@
+package midleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	chiMiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	metricsNamespace = "calc_gateway"
+
+	routeUnmatched = "unmatched"
+
+	resultSuccess = "success"
+	resultFailure = "failure"
+)
+
+// authOperations сопоставляет маршруты авторизации с именем операции в метриках.
+var authOperations = map[string]string{
+	"/api/v1/auth/register": "register",
+	"/api/v1/auth/login":    "login",
+	"/api/v1/auth/refresh":  "refresh",
+}
+
+// calculationSubmitRoute - маршрут отправки выражения на вычисление.
+const calculationSubmitRoute = "/api/v1/calculations"
+
+// Metrics собирает метрики HTTP шлюза в собственном реестре Prometheus.
+type Metrics struct {
+	registry          *prometheus.Registry
+	requestsTotal     *prometheus.CounterVec
+	requestDuration   *prometheus.HistogramVec
+	authAttemptsTotal *prometheus.CounterVec
+	calculationsTotal *prometheus.CounterVec
+}
+
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "http_requests_total",
+			Help:      "Total number of HTTP requests by route, method and status code.",
+		}, []string{"method", "route", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by route and method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		authAttemptsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "auth_attempts_total",
+			Help:      "Total number of authentication attempts by operation and result.",
+		}, []string{"operation", "result"}),
+		calculationsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "calculations_submitted_total",
+			Help:      "Total number of submitted calculations by result.",
+		}, []string{"result"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requestsTotal,
+		m.requestDuration,
+		m.authAttemptsTotal,
+		m.calculationsTotal,
+	)
+
+	return m
+}
+
+// Handler возвращает обработчик, отдающий метрики в формате Prometheus.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Middleware учитывает каждый запрос. Маршрут берется из шаблона chi, а не из пути,
+// чтобы идентификаторы в URL не порождали новые ряды метрик.
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		startTime := time.Now()
+
+		rctx := chi.RouteContext(r.Context())
+		if rctx == nil {
+			rctx = chi.NewRouteContext()
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+		}
+
+		ww := chiMiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		route := routePattern(rctx)
+		m.requestsTotal.WithLabelValues(r.Method, route, strconv.Itoa(status)).Inc()
+		m.requestDuration.WithLabelValues(r.Method, route).Observe(time.Since(startTime).Seconds())
+
+		if r.Method != http.MethodPost {
+			return
+		}
+
+		result := resultSuccess
+		if status >= http.StatusBadRequest {
+			result = resultFailure
+		}
+
+		if operation, ok := authOperations[route]; ok {
+			m.authAttemptsTotal.WithLabelValues(operation, result).Inc()
+		}
+		if route == calculationSubmitRoute {
+			m.calculationsTotal.WithLabelValues(result).Inc()
+		}
+	})
+}
+
+func routePattern(rctx *chi.Context) string {
+	pattern := strings.ReplaceAll(rctx.RoutePattern(), "/*/", "/")
+	if pattern == "" {
+		return routeUnmatched
+	}
+	return pattern
+}
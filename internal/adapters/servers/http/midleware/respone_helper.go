@@ -7,3 +7,9 @@ type responseWriterWrapper struct {
 	http.ResponseWriter
 	statusCode int
 }
+
+// Unwrap returns the underlying http.ResponseWriter so that http.ResponseController
+// can reach Flush and SetWriteDeadline, which streaming handlers rely on.
+func (rw *responseWriterWrapper) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
@@ -0,0 +1,70 @@
+package midleware
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/servertiming"
+)
+
+// ServerTiming добавляет к ответу заголовок Server-Timing с длительностями проверки токена,
+// вызовов gRPC, сериализации ответа и общей длительностью до отправки заголовков.
+// Заголовок добавляется ко всем ответам, если enabled, или к ответам на запросы
+// с заголовком header, равным true или 1. Пустой header отключает включение по запросу.
+func ServerTiming(enabled bool, header string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !enabled && !serverTimingRequested(r, header) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, timings := servertiming.NewContext(r.Context())
+			tw := &serverTimingWriter{ResponseWriter: w, timings: timings, start: time.Now()}
+			next.ServeHTTP(tw, r.WithContext(ctx))
+		})
+	}
+}
+
+func serverTimingRequested(r *http.Request, header string) bool {
+	if header == "" {
+		return false
+	}
+	requested, err := strconv.ParseBool(r.Header.Get(header))
+	return err == nil && requested
+}
+
+// serverTimingWriter выставляет заголовок Server-Timing перед отправкой заголовков ответа.
+type serverTimingWriter struct {
+	http.ResponseWriter
+	timings     *servertiming.Timings
+	start       time.Time
+	wroteHeader bool
+}
+
+func (w *serverTimingWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.timings.Add(servertiming.MetricTotal, time.Since(w.start))
+		w.Header().Set(servertiming.HeaderName, w.timings.Header())
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *serverTimingWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	n, err := w.ResponseWriter.Write(b)
+	if err != nil {
+		return n, fmt.Errorf("response writer error: %w", err)
+	}
+	return n, nil
+}
+
+// Unwrap открывает http.ResponseController доступ к Flush потоковых обработчиков.
+func (w *serverTimingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,116 @@
+package midleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/api/auth"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/servertiming"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+const debugTimingHeader = "X-Debug-Timing"
+
+var serverTimingMetric = regexp.MustCompile(`^([a-z]+);dur=\d+\.\d{2}$`)
+
+// tokenValidator проверяет любой токен, записывая вызов gRPC, как настоящий клиент сервиса авторизации.
+type tokenValidator struct {
+	auth.UseCaseUser
+	userID uuid.UUID
+}
+
+func (v tokenValidator) ValidateToken(ctx context.Context, _ string) (uuid.UUID, error) {
+	servertiming.Add(ctx, servertiming.MetricGRPC, time.Millisecond)
+	return v.userID, nil
+}
+
+// serverTimingMetrics разбирает заголовок Server-Timing и возвращает имена этапов по порядку.
+func serverTimingMetrics(t *testing.T, header string) []string {
+	t.Helper()
+
+	var names []string
+	for _, metric := range strings.Split(header, ", ") {
+		match := serverTimingMetric.FindStringSubmatch(metric)
+		require.NotNil(t, match, "malformed metric %q in %q", metric, header)
+		names = append(names, match[1])
+	}
+	return names
+}
+
+func TestServerTiming(t *testing.T) {
+	ctx := logger.WithLogger(context.Background(), logger.New(zapcore.NewNopCore()))
+
+	// Обработчик проверяет токен, вызывает сервис по gRPC и сериализует ответ.
+	handler := func(enabled bool, header string) http.Handler {
+		inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			servertiming.Add(r.Context(), servertiming.MetricGRPC, 2*time.Millisecond)
+			HandleError(r.Context(), w, ErrUserNotInContext, http.StatusNotFound)
+		})
+		return ServerTiming(enabled, header)(AuthMiddleware(tokenValidator{userID: uuid.New()})(inner))
+	}
+
+	serve := func(h http.Handler, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/calculations", nil).WithContext(ctx)
+		req.Header.Set(authHeaderName, bearerScheme+" token")
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("Enabled globally", func(t *testing.T) {
+		rec := serve(handler(true, ""), nil)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		header := rec.Header().Get(servertiming.HeaderName)
+		assert.Equal(t, []string{
+			servertiming.MetricAuth,
+			servertiming.MetricGRPC,
+			servertiming.MetricSerialize,
+			servertiming.MetricTotal,
+		}, serverTimingMetrics(t, header))
+		assert.Contains(t, header, "grpc;dur=2.00", "token validation is recorded as auth, not grpc")
+	})
+
+	t.Run("Enabled by request header", func(t *testing.T) {
+		h := handler(false, debugTimingHeader)
+
+		rec := serve(h, map[string]string{debugTimingHeader: "true"})
+		assert.Len(t, serverTimingMetrics(t, rec.Header().Get(servertiming.HeaderName)), 4)
+
+		rec = serve(h, map[string]string{debugTimingHeader: "1"})
+		assert.NotEmpty(t, rec.Header().Get(servertiming.HeaderName))
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		rec := serve(handler(false, debugTimingHeader), nil)
+		assert.Empty(t, rec.Header().Get(servertiming.HeaderName))
+
+		rec = serve(handler(false, debugTimingHeader), map[string]string{debugTimingHeader: "no"})
+		assert.Empty(t, rec.Header().Get(servertiming.HeaderName))
+
+		rec = serve(handler(false, ""), map[string]string{debugTimingHeader: "true"})
+		assert.Empty(t, rec.Header().Get(servertiming.HeaderName))
+	})
+
+	t.Run("Implicit status", func(t *testing.T) {
+		h := ServerTiming(true, "")(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte("ok"))
+		}))
+		rec := serve(h, nil)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, []string{servertiming.MetricTotal}, serverTimingMetrics(t, rec.Header().Get(servertiming.HeaderName)))
+	})
+}
@@ -0,0 +1,55 @@
+package midleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+)
+
+const headerClientSource = "X-Client-Source"
+
+type sourceContextKey struct{}
+
+var ErrInvalidSource = NewAPIError("unknown client source", "INVALID_CLIENT_SOURCE")
+
+// ParseSource приводит значение канала к нижнему регистру без пробелов по краям и проверяет,
+// что это один из известных каналов. Используется для заголовка X-Client-Source и для
+// канала по умолчанию из конфигурации, который проверяется при запуске.
+func ParseSource(value string) (orchestrator.CalculationSource, error) {
+	source := orchestrator.CalculationSource(strings.ToLower(strings.TrimSpace(value)))
+	if !source.IsValid() {
+		return "", ErrInvalidSource
+	}
+	return source, nil
+}
+
+// Source определяет канал, через который пришел запрос, по заголовку X-Client-Source.
+// Если заголовок не передан, используется defaultSource. Запрос с неизвестным каналом
+// отклоняется с кодом 400.
+func Source(defaultSource orchestrator.CalculationSource) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			source := defaultSource
+
+			if header := r.Header.Get(headerClientSource); strings.TrimSpace(header) != "" {
+				parsed, err := ParseSource(header)
+				if err != nil {
+					HandleError(r.Context(), w, err, http.StatusBadRequest)
+					return
+				}
+				source = parsed
+			}
+
+			ctx := context.WithValue(r.Context(), sourceContextKey{}, source)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// GetSourceFromContext возвращает канал запроса или пустую строку, если он не определен.
+func GetSourceFromContext(ctx context.Context) orchestrator.CalculationSource {
+	source, _ := ctx.Value(sourceContextKey{}).(orchestrator.CalculationSource)
+	return source
+}
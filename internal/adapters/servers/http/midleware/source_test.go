@@ -0,0 +1,51 @@
+package midleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSource(t *testing.T) {
+	source, err := ParseSource(" CLI ")
+	require.NoError(t, err)
+	assert.Equal(t, orchestrator.CalculationSourceCLI, source)
+
+	_, err = ParseSource("mobile")
+	require.ErrorIs(t, err, ErrInvalidSource)
+	_, err = ParseSource("")
+	require.ErrorIs(t, err, ErrInvalidSource)
+}
+
+func TestSource(t *testing.T) {
+	serve := func(value string) (int, orchestrator.CalculationSource) {
+		var source orchestrator.CalculationSource
+		handler := Source(orchestrator.CalculationSourceWeb)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			source = GetSourceFromContext(r.Context())
+		}))
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/calculations/", nil)
+		if value != "" {
+			req.Header.Set("X-Client-Source", value)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code, source
+	}
+
+	code, source := serve("")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, orchestrator.CalculationSourceWeb, source, "header not sent")
+
+	code, source = serve("Batch")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, orchestrator.CalculationSourceBatch, source)
+
+	code, source = serve("mobile")
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Empty(t, source)
+}
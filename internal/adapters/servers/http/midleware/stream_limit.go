@@ -0,0 +1,103 @@
+package midleware
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrTooManyUserStreams = NewAPIError("too many concurrent streaming connections for user", "STREAM_USER_LIMIT")
+	ErrTooManyStreams     = NewAPIError("too many concurrent streaming connections", "STREAM_GLOBAL_LIMIT")
+)
+
+// StreamLimiter ограничивает число одновременных потоковых (SSE) соединений
+// для каждого пользователя и для сервера в целом. Нулевой лимит отключает соответствующую проверку.
+type StreamLimiter struct {
+	mu         sync.Mutex
+	maxPerUser int
+	maxTotal   int
+	total      int
+	perUser    map[uuid.UUID]int
+}
+
+// NewStreamLimiter создает ограничитель потоковых соединений.
+func NewStreamLimiter(maxPerUser, maxTotal int) *StreamLimiter {
+	return &StreamLimiter{
+		maxPerUser: max(maxPerUser, 0),
+		maxTotal:   max(maxTotal, 0),
+		perUser:    make(map[uuid.UUID]int),
+	}
+}
+
+// Acquire занимает слот для соединения пользователя.
+// Возвращает ErrTooManyUserStreams или ErrTooManyStreams, если лимит исчерпан.
+func (l *StreamLimiter) Acquire(userID uuid.UUID) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.maxTotal > 0 && l.total >= l.maxTotal {
+		return ErrTooManyStreams
+	}
+
+	if l.maxPerUser > 0 && l.perUser[userID] >= l.maxPerUser {
+		return ErrTooManyUserStreams
+	}
+
+	l.total++
+	l.perUser[userID]++
+	return nil
+}
+
+// Release освобождает слот, занятый Acquire.
+func (l *StreamLimiter) Release(userID uuid.UUID) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.perUser[userID] <= 1 {
+		delete(l.perUser, userID)
+	} else {
+		l.perUser[userID]--
+	}
+
+	if l.total > 0 {
+		l.total--
+	}
+}
+
+// Active возвращает число открытых соединений пользователя и общее число соединений.
+func (l *StreamLimiter) Active(userID uuid.UUID) (int, int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.perUser[userID], l.total
+}
+
+// StreamLimit ограничивает одновременные потоковые соединения с помощью limiter.
+// Должен подключаться после AuthMiddleware. Слот освобождается, когда обработчик
+// завершается, в том числе при отключении клиента. При превышении лимита возвращается 429.
+func StreamLimit(limiter *StreamLimiter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, err := GetUserIDFromContext(r.Context())
+			if err != nil {
+				HandleError(r.Context(), w, err, http.StatusUnauthorized)
+				return
+			}
+
+			if err := limiter.Acquire(userID); err != nil {
+				logger.ContextLogger(r.Context(), nil).Warn("streaming connection rejected",
+					zap.String("user_id", userID.String()),
+					zap.Error(err))
+				HandleError(r.Context(), w, err, http.StatusTooManyRequests)
+				return
+			}
+			defer limiter.Release(userID)
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
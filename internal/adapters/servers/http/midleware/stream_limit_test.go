@@ -0,0 +1,134 @@
+package midleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func userContext(userID uuid.UUID) context.Context {
+	ctx := logger.WithLogger(context.Background(), logger.New(zapcore.NewNopCore()))
+	return context.WithValue(ctx, userIDContextKey{}, userID)
+}
+
+// openStream имитирует потоковое соединение: обработчик держит его открытым
+// до отмены контекста запроса. Возвращает функцию отключения клиента.
+func openStream(t *testing.T, handler http.Handler, userID uuid.UUID, started chan struct{}) (*httptest.ResponseRecorder, func()) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(userContext(userID))
+	req := httptest.NewRequest(http.MethodGet, "/stream", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handler.ServeHTTP(rec, req)
+	}()
+
+	select {
+	case <-started:
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("stream handler did not start")
+	}
+
+	return rec, func() {
+		cancel()
+		<-done
+	}
+}
+
+func TestStreamLimit(t *testing.T) {
+	limiter := NewStreamLimiter(2, 3)
+	started := make(chan struct{})
+	handler := StreamLimit(limiter)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		started <- struct{}{}
+		<-r.Context().Done()
+	}))
+
+	alice, bob, carol := uuid.New(), uuid.New(), uuid.New()
+
+	_, closeFirst := openStream(t, handler, alice, started)
+	_, closeSecond := openStream(t, handler, alice, started)
+	defer closeSecond()
+
+	rec, closeRejected := openStream(t, handler, alice, started)
+	closeRejected()
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code, "per-user cap must be enforced")
+	assert.Contains(t, rec.Body.String(), ErrTooManyUserStreams.Code)
+
+	rec, closeBob := openStream(t, handler, bob, started)
+	defer closeBob()
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec, closeCarol := openStream(t, handler, carol, started)
+	closeCarol()
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code, "global cap must be enforced")
+	assert.Contains(t, rec.Body.String(), ErrTooManyStreams.Code)
+
+	perUser, total := limiter.Active(alice)
+	assert.Equal(t, 2, perUser)
+	assert.Equal(t, 3, total)
+
+	// Отключение клиента освобождает слот
+	closeFirst()
+	perUser, total = limiter.Active(alice)
+	assert.Equal(t, 1, perUser)
+	assert.Equal(t, 2, total)
+
+	rec, closeReopened := openStream(t, handler, alice, started)
+	defer closeReopened()
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestStreamLimitReleasesOnPanic(t *testing.T) {
+	limiter := NewStreamLimiter(1, 0)
+	userID := uuid.New()
+	handler := StreamLimit(limiter)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("stream failed")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/stream", nil).
+		WithContext(userContext(userID))
+
+	require.Panics(t, func() { handler.ServeHTTP(httptest.NewRecorder(), req) })
+
+	perUser, total := limiter.Active(userID)
+	assert.Zero(t, perUser)
+	assert.Zero(t, total)
+}
+
+func TestStreamLimitDisabled(t *testing.T) {
+	limiter := NewStreamLimiter(0, 0)
+	userID := uuid.New()
+
+	for range 10 {
+		require.NoError(t, limiter.Acquire(userID))
+	}
+
+	perUser, total := limiter.Active(userID)
+	assert.Equal(t, 10, perUser)
+	assert.Equal(t, 10, total)
+}
+
+func TestStreamLimitRequiresUser(t *testing.T) {
+	handler := StreamLimit(NewStreamLimiter(1, 1))(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/stream", nil)
+	handler.ServeHTTP(rec, req.WithContext(logger.WithLogger(req.Context(), logger.New(zapcore.NewNopCore()))))
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
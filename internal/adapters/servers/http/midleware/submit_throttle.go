@@ -0,0 +1,102 @@
+package midleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const headerRetryAfter = "Retry-After"
+
+var ErrSubmitTooFrequent = NewAPIError("calculations are submitted too frequently", "SUBMIT_TOO_FREQUENT")
+
+// SubmitThrottle выдерживает минимальный интервал между отправками вычислений одного
+// пользователя. В отличие от ограничения по числу запросов, он не копит запас: каждая
+// следующая отправка возможна не раньше, чем через interval после предыдущей.
+// Нулевой интервал отключает проверку.
+type SubmitThrottle struct {
+	mu        sync.Mutex
+	interval  time.Duration
+	last      map[uuid.UUID]time.Time
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewSubmitThrottle создает ограничитель частоты отправки вычислений.
+func NewSubmitThrottle(interval time.Duration) *SubmitThrottle {
+	return &SubmitThrottle{
+		interval: max(interval, 0),
+		last:     make(map[uuid.UUID]time.Time),
+		now:      time.Now,
+	}
+}
+
+// Allow засчитывает отправку пользователя и возвращает ноль, если с предыдущей прошло
+// не меньше интервала. Иначе отправка не засчитывается, а возвращается время до следующей
+// разрешенной отправки.
+func (t *SubmitThrottle) Allow(userID uuid.UUID) time.Duration {
+	if t.interval == 0 {
+		return 0
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	if last, ok := t.last[userID]; ok {
+		if wait := t.interval - now.Sub(last); wait > 0 {
+			return wait
+		}
+	}
+
+	t.last[userID] = now
+	t.sweep(now)
+	return 0
+}
+
+// sweep раз в интервал удаляет пользователей, которым ограничение уже не мешает,
+// чтобы таблица не росла вместе с числом когда-либо отправлявших пользователей.
+func (t *SubmitThrottle) sweep(now time.Time) {
+	if now.Sub(t.lastSweep) < t.interval {
+		return
+	}
+	t.lastSweep = now
+
+	for userID, last := range t.last {
+		if now.Sub(last) >= t.interval {
+			delete(t.last, userID)
+		}
+	}
+}
+
+// SubmitInterval отклоняет отправку вычисления с кодом 429 и заголовком Retry-After, если
+// пользователь отправляет вычисления чаще, чем разрешает throttle. Должен подключаться
+// после AuthMiddleware.
+func SubmitInterval(throttle *SubmitThrottle) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, err := GetUserIDFromContext(r.Context())
+			if err != nil {
+				HandleError(r.Context(), w, err, http.StatusUnauthorized)
+				return
+			}
+
+			if wait := throttle.Allow(userID); wait > 0 {
+				logger.ContextLogger(r.Context(), nil).Warn("calculation submission throttled",
+					zap.String("user_id", userID.String()),
+					zap.Duration("retry_after", wait))
+				w.Header().Set(headerRetryAfter, strconv.Itoa(max(int(math.Ceil(wait.Seconds())), 1)))
+				HandleError(r.Context(), w, ErrSubmitTooFrequent, http.StatusTooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
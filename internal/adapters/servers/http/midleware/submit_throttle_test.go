@@ -0,0 +1,71 @@
+package midleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubmitInterval(t *testing.T) {
+	throttle := NewSubmitThrottle(5 * time.Second)
+	now := time.Now()
+	throttle.now = func() time.Time { return now }
+
+	handler := SubmitInterval(throttle)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+
+	submit := func(userID uuid.UUID) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/calculations/", nil).WithContext(userContext(userID))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	alice, bob := uuid.New(), uuid.New()
+
+	assert.Equal(t, http.StatusAccepted, submit(alice).Code)
+
+	now = now.Add(1500 * time.Millisecond)
+	throttled := submit(alice)
+	assert.Equal(t, http.StatusTooManyRequests, throttled.Code, "second submission within the interval")
+	assert.Equal(t, "4", throttled.Header().Get(headerRetryAfter), "3.5s left, rounded up")
+	assert.Contains(t, throttled.Body.String(), ErrSubmitTooFrequent.Code)
+
+	assert.Equal(t, http.StatusAccepted, submit(bob).Code, "interval is per user")
+
+	now = now.Add(3500 * time.Millisecond)
+	assert.Equal(t, http.StatusAccepted, submit(alice).Code, "rejected submission does not extend the interval")
+
+	now = now.Add(time.Second)
+	assert.Equal(t, http.StatusTooManyRequests, submit(alice).Code)
+}
+
+func TestSubmitThrottleDisabled(t *testing.T) {
+	throttle := NewSubmitThrottle(0)
+	userID := uuid.New()
+
+	assert.Zero(t, throttle.Allow(userID))
+	assert.Zero(t, throttle.Allow(userID))
+}
+
+func TestSubmitThrottleSweep(t *testing.T) {
+	throttle := NewSubmitThrottle(time.Second)
+	now := time.Now()
+	throttle.now = func() time.Time { return now }
+
+	stale := uuid.New()
+	assert.Zero(t, throttle.Allow(stale))
+
+	now = now.Add(2 * time.Second)
+	assert.Zero(t, throttle.Allow(uuid.New()))
+
+	throttle.mu.Lock()
+	defer throttle.mu.Unlock()
+	assert.NotContains(t, throttle.last, stale)
+	assert.Len(t, throttle.last, 1)
+}
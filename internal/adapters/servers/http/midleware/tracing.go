@@ -0,0 +1,28 @@
+package midleware
+
+import (
+	"net/http"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/tracing"
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracingOperation = "http.server"
+
+// Tracing создает спан для каждого запроса и продолжает трассу из заголовков клиента.
+// Спан называется по шаблону маршрута chi, как и ряды метрик, и получает атрибут
+// request_id, поэтому должен подключаться после Logger.
+func Tracing(next http.Handler) http.Handler {
+	traced := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tracing.AnnotateRequestID(r.Context())
+
+		next.ServeHTTP(w, r)
+
+		if rctx := chi.RouteContext(r.Context()); rctx != nil {
+			trace.SpanFromContext(r.Context()).SetName(r.Method + " " + routePattern(rctx))
+		}
+	})
+	return otelhttp.NewHandler(traced, tracingOperation)
+}
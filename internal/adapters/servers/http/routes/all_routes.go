@@ -3,15 +3,17 @@ package routes
 import (
 	"net/http"
 
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/http/handlers/admin"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/http/handlers/auth"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/http/handlers/orchestrator"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/http/midleware"
+	orchModels "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
 	authAPI "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/api/auth"
 	orchAPI "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/api/orchestrator"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/setup/server"
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
 	"github.com/go-chi/chi/v5"
 	chiMiddleware "github.com/go-chi/chi/v5/middleware"
-	"github.com/go-chi/cors"
 	"go.uber.org/zap"
 )
 
@@ -23,10 +25,32 @@ const (
 	pathLogin    = "/login"
 	pathRefresh  = "/refresh"
 	pathLogout   = "/logout"
-
-	calcPrefix = apiVersion + "/calculations"
-	pathRoot   = "/"
-	pathByID   = "/{id}"
+	pathMe       = "/me"
+	pathSessions = "/sessions"
+	pathSession  = "/sessions/{id}"
+
+	calcPrefix      = apiVersion + "/calculations"
+	pathRoot        = "/"
+	pathByID        = "/{id}"
+	pathCancel      = "/{id}/cancel"
+	pathStream      = "/{id}/stream"
+	pathDiff        = "/{id}/diff/{otherID}"
+	pathCompare     = "/compare"
+	pathPreview     = "/preview"
+	pathValidate    = "/validate"
+	pathStats       = "/stats"
+	pathResultStats = "/results/stats"
+
+	operationPrefix = apiVersion + "/operations"
+
+	adminPrefix       = apiVersion + "/admin"
+	pathAgentCapacity = "/agents/{id}/capacity"
+	pathAgentTimings  = "/agents/timings"
+	pathAgentStats    = "/agents/stats"
+	pathAdminCalcByID = "/calculations/{id}"
+
+	debugPrefix  = "/debug"
+	pathLogLevel = "/loglevel"
 
 	pathHealth    = "/health"
 	apiHealthMsg  = "API Gateway is healthy"
@@ -34,18 +58,14 @@ const (
 	calcHealthMsg = "Orchestrator service is healthy"
 )
 
-func NewRouter(authUseCase authAPI.UseCaseUser, calcUseCase orchAPI.UseCaseCalculation) http.Handler {
+func NewRouter(cfg server.Config, authUseCase authAPI.UseCaseUser, calcUseCase orchAPI.UseCaseCalculation) http.Handler {
 	r := chi.NewRouter()
 
 	// Global middleware
-	r.Use(cors.Handler(cors.Options{
-		AllowedOrigins:   []string{"*"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-Request-ID"},
-		ExposedHeaders:   []string{"Link"},
-		AllowCredentials: true,
-		MaxAge:           300,
-	}))
+	r.Use(midleware.ClientIP(cfg.TrustedProxies))
+	r.Use(midleware.CORS(cfg.CORS))
+	r.Use(midleware.ServerTiming(cfg.ServerTiming, cfg.ServerTimingHeader))
+	r.Use(midleware.MaxBodySize(cfg.MaxRequestBodyBytes))
 
 	// Root health check
 	r.Get(pathHealth, func(w http.ResponseWriter, r *http.Request) {
@@ -60,7 +80,20 @@ func NewRouter(authUseCase authAPI.UseCaseUser, calcUseCase orchAPI.UseCaseCalcu
 	registerAuthRoutes(r, authUseCase)
 
 	// Calculation routes
-	registerCalculationRoutes(r, calcUseCase, authUseCase)
+	streamLimiter := midleware.NewStreamLimiter(cfg.MaxStreamsPerUser, cfg.MaxStreams)
+	submitThrottle := midleware.NewSubmitThrottle(cfg.MinSubmitInterval)
+	// Канал по умолчанию проверяется при запуске, неизвестное значение заменяется на web
+	defaultSource, err := midleware.ParseSource(cfg.DefaultSource)
+	if err != nil {
+		defaultSource = orchModels.CalculationSourceWeb
+	}
+	registerCalculationRoutes(r, calcUseCase, authUseCase, defaultSource, cfg.ClientVersionHeader, streamLimiter, submitThrottle)
+
+	// Operation routes
+	registerOperationRoutes(r, calcUseCase, authUseCase)
+
+	// Admin routes
+	registerAdminRoutes(r, authUseCase, calcUseCase, cfg.AdminUserIDs)
 
 	return r
 }
@@ -70,7 +103,7 @@ func registerAuthRoutes(r chi.Router, authUseCase authAPI.UseCaseUser) {
 
 	r.Route(authPrefix, func(r chi.Router) {
 		r.Use(chiMiddleware.RequestID)
-		r.Use(midleware.Logger)
+		r.Use(midleware.Tracing)
 		r.Use(midleware.Recovery)
 		r.Use(midleware.ErrorHandler)
 
@@ -87,23 +120,38 @@ func registerAuthRoutes(r chi.Router, authUseCase authAPI.UseCaseUser) {
 		r.Group(func(r chi.Router) {
 			r.Use(midleware.AuthMiddleware(authUseCase))
 			r.Post(pathLogout, authHandler.Logout)
+			r.Get(pathMe, authHandler.GetCurrentUser)
+			r.Delete(pathMe, authHandler.DeleteCurrentUser)
+			r.Get(pathSessions, authHandler.ListSessions)
+			r.Delete(pathSessions, authHandler.RevokeAllSessions)
+			r.Delete(pathSession, authHandler.RevokeSession)
 		})
 	})
 }
 
-func registerCalculationRoutes(r chi.Router, calcUseCase orchAPI.UseCaseCalculation, authUseCase authAPI.UseCaseUser) {
+func registerCalculationRoutes(r chi.Router, calcUseCase orchAPI.UseCaseCalculation, authUseCase authAPI.UseCaseUser, defaultSource orchModels.CalculationSource, clientVersionHeader string, streamLimiter *midleware.StreamLimiter, submitThrottle *midleware.SubmitThrottle) {
 	calcHandler := orchestrator.NewHandler(calcUseCase)
 
 	r.Route(calcPrefix, func(r chi.Router) {
 		r.Use(chiMiddleware.RequestID)
-		r.Use(midleware.Logger)
+		r.Use(midleware.Tracing)
 		r.Use(midleware.Recovery)
 		r.Use(midleware.ErrorHandler)
 		r.Use(midleware.AuthMiddleware(authUseCase))
+		r.Use(midleware.Source(defaultSource))
+		r.Use(midleware.ClientVersion(clientVersionHeader))
 
-		r.Post(pathRoot, calcHandler.CalculateExpression)
+		r.With(midleware.IdempotencyKey, midleware.SubmitInterval(submitThrottle)).Post(pathRoot, calcHandler.CalculateExpression)
 		r.Get(pathRoot, calcHandler.ListCalculations)
 		r.Get(pathByID, calcHandler.GetCalculation)
+		r.Delete(pathByID, calcHandler.DeleteCalculation)
+		r.Post(pathCancel, calcHandler.CancelCalculation)
+		r.With(midleware.StreamLimit(streamLimiter)).Get(pathStream, calcHandler.StreamCalculation)
+		r.Get(pathDiff, calcHandler.DiffCalculations)
+		r.Post(pathCompare, calcHandler.CompareExpressions)
+		r.Post(pathPreview, calcHandler.PreviewExpression)
+		r.Post(pathValidate, calcHandler.ValidateExpression)
+		r.Get(pathResultStats, calcHandler.GetResultStats)
 		r.Get(pathHealth, func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusOK)
 			if _, err := w.Write([]byte(calcHealthMsg)); err != nil {
@@ -112,3 +160,48 @@ func registerCalculationRoutes(r chi.Router, calcUseCase orchAPI.UseCaseCalculat
 		})
 	})
 }
+
+func registerOperationRoutes(r chi.Router, calcUseCase orchAPI.UseCaseCalculation, authUseCase authAPI.UseCaseUser) {
+	calcHandler := orchestrator.NewHandler(calcUseCase)
+
+	r.Route(operationPrefix, func(r chi.Router) {
+		r.Use(chiMiddleware.RequestID)
+		r.Use(midleware.Tracing)
+		r.Use(midleware.Recovery)
+		r.Use(midleware.ErrorHandler)
+		r.Use(midleware.AuthMiddleware(authUseCase))
+
+		r.Get(pathByID, calcHandler.GetOperation)
+	})
+}
+
+func registerAdminRoutes(r chi.Router, authUseCase authAPI.UseCaseUser, calcUseCase orchAPI.UseCaseCalculation, adminIDs []string) {
+	adminHandler := admin.NewHandler(authUseCase, calcUseCase)
+
+	r.Route(adminPrefix, func(r chi.Router) {
+		r.Use(chiMiddleware.RequestID)
+		r.Use(midleware.Tracing)
+		r.Use(midleware.Recovery)
+		r.Use(midleware.ErrorHandler)
+		r.Use(midleware.AuthMiddleware(authUseCase))
+		r.Use(midleware.RequireAdmin(adminIDs))
+
+		r.Get(pathStats, adminHandler.GetStats)
+		r.Put(pathAgentCapacity, adminHandler.SetAgentCapacity)
+		r.Get(pathAgentTimings, adminHandler.GetTimingProfile)
+		r.Get(pathAgentStats, adminHandler.GetPoolStats)
+		r.Get(pathAdminCalcByID, adminHandler.GetCalculation)
+	})
+	// Уровень журнала шлюза меняется во время работы, поэтому доступен только администраторам
+	r.Route(debugPrefix, func(r chi.Router) {
+		r.Use(chiMiddleware.RequestID)
+		r.Use(midleware.Tracing)
+		r.Use(midleware.Recovery)
+		r.Use(midleware.ErrorHandler)
+		r.Use(midleware.AuthMiddleware(authUseCase))
+		r.Use(midleware.RequireAdmin(adminIDs))
+
+		r.Get(pathLogLevel, adminHandler.GetLogLevel)
+		r.Put(pathLogLevel, adminHandler.SetLogLevel)
+	})
+}
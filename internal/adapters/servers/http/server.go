@@ -4,21 +4,27 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"time"
 
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/http/handlers"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/http/midleware"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/servers/http/routes"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/api/auth"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/api/orchestrator"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/setup/server"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/health"
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/go-chi/chi/v5"
 	"go.uber.org/zap"
 )
 
 const (
 	defaultIdleTimeout     = 2 * time.Minute
 	defaultShutdownTimeout = 10 * time.Second
+
+	pathMetrics = "/metrics"
 )
 
 type Server struct {
@@ -27,6 +33,9 @@ type Server struct {
 	authAPI    auth.UseCaseUser
 	orchAPI    orchestrator.UseCaseCalculation
 	handlers   *handlers.Handlers
+	metrics    *midleware.Metrics
+	health     *health.Checker
+	internal   *health.Server
 	shutdownCh chan struct{}
 }
 
@@ -36,10 +45,19 @@ func NewServer(config server.Config, authAPI auth.UseCaseUser, orchAPI orchestra
 		authAPI:    authAPI,
 		orchAPI:    orchAPI,
 		handlers:   handlers.NewHandlers(authAPI, orchAPI),
+		metrics:    midleware.NewMetrics(),
+		health:     health.NewChecker(),
 		shutdownCh: make(chan struct{}),
 	}
 }
 
+// SetHealthChecker задает проверки зависимостей, которые выполняет /readyz.
+func (s *Server) SetHealthChecker(checker *health.Checker) {
+	if checker != nil {
+		s.health = checker
+	}
+}
+
 func (s *Server) Start(ctx context.Context) error {
 	log := logger.ContextLogger(ctx, nil)
 	addr := fmt.Sprintf("%s:%d", s.config.Host, s.config.Port)
@@ -49,11 +67,9 @@ func (s *Server) Start(ctx context.Context) error {
 		zap.Duration("read_timeout", s.config.ReadTimeout),
 		zap.Duration("write_timeout", s.config.WriteTimeout))
 
-	router := routes.NewRouter(s.authAPI, s.orchAPI)
-
 	s.server = &http.Server{
 		Addr:              addr,
-		Handler:           router,
+		Handler:           s.handler(),
 		ReadTimeout:       s.config.ReadTimeout,
 		WriteTimeout:      s.config.WriteTimeout,
 		IdleTimeout:       defaultIdleTimeout,
@@ -61,6 +77,23 @@ func (s *Server) Start(ctx context.Context) error {
 		MaxHeaderBytes:    1 << 20,
 	}
 
+	// Запросы получают журнал сервиса, но не остальные значения ctx запуска,
+	// в том числе его идентификатор запроса.
+	if serviceLogger, ok := logger.FromContext(ctx); ok {
+		baseCtx := logger.WithLogger(context.Background(), serviceLogger)
+		s.server.BaseContext = func(net.Listener) context.Context { return baseCtx }
+	}
+
+	if s.config.MetricsPort > 0 {
+		internalAddr := fmt.Sprintf("%s:%d", s.config.Host, s.config.MetricsPort)
+		s.internal = health.NewServer(internalAddr, s.health)
+		s.internal.Handle(pathMetrics, s.metrics.Handler())
+		if err := s.internal.Start(ctx); err != nil {
+			return fmt.Errorf("failed to start metrics server: %w", err)
+		}
+		log.Info("Metrics server listening", zap.String("address", internalAddr))
+	}
+
 	go func() {
 		if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			log.Error("HTTP server error", zap.Error(err))
@@ -72,6 +105,18 @@ func (s *Server) Start(ctx context.Context) error {
 	return nil
 }
 
+// handler собирает маршрутизатор шлюза, оборачивая все маршруты сбором метрик и журналом доступа.
+// Проверки /healthz и /readyz регистрируются вне API; /metrics отдается только внутренним
+// сервером на порту MetricsPort.
+func (s *Server) handler() http.Handler {
+	router := chi.NewRouter()
+	router.Use(s.metrics.Middleware)
+	router.Use(midleware.Logger)
+	s.health.Routes(router)
+	router.Mount("/", routes.NewRouter(s.config, s.authAPI, s.orchAPI))
+	return router
+}
+
 func (s *Server) Stop(ctx context.Context) error {
 	log := logger.ContextLogger(ctx, nil)
 	log.Info("Stopping HTTP server")
@@ -79,6 +124,12 @@ func (s *Server) Stop(ctx context.Context) error {
 	shutdownCtx, cancel := context.WithTimeout(ctx, defaultShutdownTimeout)
 	defer cancel()
 
+	if s.internal != nil {
+		if err := s.internal.Stop(shutdownCtx); err != nil {
+			log.Error("Metrics server shutdown error", zap.Error(err))
+		}
+	}
+
 	err := s.server.Shutdown(shutdownCtx)
 	if err != nil {
 		log.Error("HTTP server shutdown error", zap.Error(err))
@@ -0,0 +1,97 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/setup/server"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/health"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsEndpoint(t *testing.T) {
+	s := NewServer(server.Config{DefaultSource: "web"}, nil, nil)
+	ts := httptest.NewServer(s.handler())
+	defer ts.Close()
+
+	send := func(method, path, body string) int {
+		req, err := http.NewRequestWithContext(context.Background(), method, ts.URL+path, strings.NewReader(body))
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, http.StatusOK, send(http.MethodGet, "/health", ""))
+	assert.Equal(t, http.StatusBadRequest, send(http.MethodPost, "/api/v1/auth/login", "{"))
+	assert.Equal(t, http.StatusUnauthorized, send(http.MethodPost, "/api/v1/calculations/", `{"expression":"2+2"}`))
+
+	assert.NotEqual(t, http.StatusOK, send(http.MethodGet, pathMetrics, ""), "metrics must not be served on the public port")
+
+	internal := httptest.NewServer(s.metrics.Handler())
+	defer internal.Close()
+
+	resp, err := http.Get(internal.URL + pathMetrics)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	metrics := string(body)
+
+	for _, expected := range []string{
+		`calc_gateway_http_requests_total{method="GET",route="/health",status="200"} 1`,
+		`calc_gateway_http_requests_total{method="POST",route="/api/v1/auth/login",status="400"} 1`,
+		`calc_gateway_http_request_duration_seconds_bucket{method="GET",route="/health"`,
+		`calc_gateway_auth_attempts_total{operation="login",result="failure"} 1`,
+		`calc_gateway_calculations_submitted_total{result="failure"} 1`,
+	} {
+		assert.Contains(t, metrics, expected)
+	}
+}
+
+func TestHealthEndpoints(t *testing.T) {
+	healthy := true
+	checker := health.NewChecker()
+	checker.Add("auth", func(context.Context) error { return nil })
+	checker.Add("orchestrator", func(context.Context) error {
+		if healthy {
+			return nil
+		}
+		return errors.New("connection is not ready")
+	})
+
+	s := NewServer(server.Config{DefaultSource: "web"}, nil, nil)
+	s.SetHealthChecker(checker)
+	ts := httptest.NewServer(s.handler())
+	defer ts.Close()
+
+	get := func(path string) (int, string) {
+		resp, err := http.Get(ts.URL + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(body)
+	}
+
+	code, _ := get(health.PathReadiness)
+	assert.Equal(t, http.StatusOK, code)
+
+	healthy = false
+	code, body := get(health.PathReadiness)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Contains(t, body, `"orchestrator":{"status":"unavailable","error":"connection is not ready"}`)
+	assert.Contains(t, body, `"auth":{"status":"ok"}`)
+
+	code, _ = get(health.PathLiveness)
+	assert.Equal(t, http.StatusOK, code, "liveness must not depend on dependencies")
+}
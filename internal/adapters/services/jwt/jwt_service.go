@@ -2,6 +2,7 @@ package jwt
 
 import (
 	"context"
+	"crypto/rsa"
 	"errors"
 	"fmt"
 	"strings"
@@ -34,6 +35,9 @@ var (
 	ErrInvalidClaims        = errors.New("invalid token claims")
 	ErrGeneratingToken      = errors.New("failed to generate token")
 	ErrInsecureSecretKey    = errors.New("provided secret key is too short")
+	ErrInvalidRSAKey        = errors.New("invalid RSA key")
+	ErrKeyMismatch          = errors.New("RSA public key does not match the private key")
+	ErrVerifyOnly           = errors.New("service is verify-only and cannot sign tokens")
 )
 
 type Claims struct {
@@ -45,25 +49,82 @@ type Claims struct {
 
 type Service struct {
 	secretKey       []byte
+	privateKey      *rsa.PrivateKey
+	publicKey       *rsa.PublicKey
+	signingMethod   jwt.SigningMethod
 	accessTokenTTL  time.Duration
 	refreshTokenTTL time.Duration
 }
 
 var _ jwtPort.Service = (*Service)(nil)
 
+// NewService создает сервис, подписывающий токены симметричным ключом (HS256).
 func NewService(secretKey string, accessTokenTTL, refreshTokenTTL time.Duration) *Service {
-	if accessTokenTTL <= 0 {
-		accessTokenTTL = 15 * time.Minute
+	accessTokenTTL, refreshTokenTTL = normalizeTTLs(accessTokenTTL, refreshTokenTTL)
+
+	return &Service{
+		secretKey:       []byte(secretKey),
+		signingMethod:   jwt.SigningMethodHS256,
+		accessTokenTTL:  accessTokenTTL,
+		refreshTokenTTL: refreshTokenTTL,
 	}
-	if refreshTokenTTL <= 0 {
-		refreshTokenTTL = 24 * time.Hour
+}
+
+// NewServiceRSA создает сервис, подписывающий токены закрытым ключом RSA (RS256)
+// и проверяющий их открытым ключом. Ключи передаются в формате PEM.
+func NewServiceRSA(privateKeyPEM, publicKeyPEM []byte, accessTokenTTL, refreshTokenTTL time.Duration) (*Service, error) {
+	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(privateKeyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("%w: private key: %w", ErrInvalidRSAKey, err)
 	}
 
+	publicKey, err := jwt.ParseRSAPublicKeyFromPEM(publicKeyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("%w: public key: %w", ErrInvalidRSAKey, err)
+	}
+
+	if !privateKey.PublicKey.Equal(publicKey) {
+		return nil, ErrKeyMismatch
+	}
+
+	accessTokenTTL, refreshTokenTTL = normalizeTTLs(accessTokenTTL, refreshTokenTTL)
+
 	return &Service{
-		secretKey:       []byte(secretKey),
+		privateKey:      privateKey,
+		publicKey:       publicKey,
+		signingMethod:   jwt.SigningMethodRS256,
 		accessTokenTTL:  accessTokenTTL,
 		refreshTokenTTL: refreshTokenTTL,
+	}, nil
+}
+
+// NewVerifierRSA создает сервис, который только проверяет токены RS256 открытым ключом.
+// Такой сервис подходит для компонентов, которые не должны выпускать токены:
+// GenerateTokens возвращает ErrVerifyOnly.
+func NewVerifierRSA(publicKeyPEM []byte, accessTokenTTL, refreshTokenTTL time.Duration) (*Service, error) {
+	publicKey, err := jwt.ParseRSAPublicKeyFromPEM(publicKeyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("%w: public key: %w", ErrInvalidRSAKey, err)
 	}
+
+	accessTokenTTL, refreshTokenTTL = normalizeTTLs(accessTokenTTL, refreshTokenTTL)
+
+	return &Service{
+		publicKey:       publicKey,
+		signingMethod:   jwt.SigningMethodRS256,
+		accessTokenTTL:  accessTokenTTL,
+		refreshTokenTTL: refreshTokenTTL,
+	}, nil
+}
+
+func normalizeTTLs(accessTokenTTL, refreshTokenTTL time.Duration) (time.Duration, time.Duration) {
+	if accessTokenTTL <= 0 {
+		accessTokenTTL = 15 * time.Minute
+	}
+	if refreshTokenTTL <= 0 {
+		refreshTokenTTL = 24 * time.Hour
+	}
+	return accessTokenTTL, refreshTokenTTL
 }
 
 func (s *Service) GetTokenTTL() time.Duration {
@@ -83,13 +144,20 @@ func (s *Service) GenerateTokens(ctx context.Context, userID uuid.UUID, login st
 		return nil, fmt.Errorf("%w: user ID cannot be nil", ErrGeneratingToken)
 	}
 
-	if len(s.secretKey) == 0 {
-		log.Error("Empty secret key")
-		return nil, fmt.Errorf("%w: empty secret key", ErrGeneratingToken)
-	}
+	if s.signingMethod == jwt.SigningMethodRS256 {
+		if s.privateKey == nil {
+			log.Error("Private key is not configured")
+			return nil, fmt.Errorf("%w: %w", ErrGeneratingToken, ErrVerifyOnly)
+		}
+	} else {
+		if len(s.secretKey) == 0 {
+			log.Error("Empty secret key")
+			return nil, fmt.Errorf("%w: empty secret key", ErrGeneratingToken)
+		}
 
-	if len(s.secretKey) < minSecretKeyLength {
-		log.Warn("Secret key is too short", zap.Int("minLength", minSecretKeyLength))
+		if len(s.secretKey) < minSecretKeyLength {
+			log.Warn("Secret key is too short", zap.Int("minLength", minSecretKeyLength))
+		}
 	}
 
 	now := time.Now()
@@ -138,8 +206,8 @@ func (s *Service) generateToken(
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(s.secretKey)
+	token := jwt.NewWithClaims(s.signingMethod, claims)
+	tokenString, err := token.SignedString(s.signingKey())
 	if err != nil {
 		logger.Error(ctx, nil, "Failed to sign token",
 			zap.String("type", string(tokenType)),
@@ -150,6 +218,22 @@ func (s *Service) generateToken(
 	return tokenString, nil
 }
 
+// signingKey возвращает ключ подписи для выбранного алгоритма.
+func (s *Service) signingKey() any {
+	if s.signingMethod == jwt.SigningMethodRS256 {
+		return s.privateKey
+	}
+	return s.secretKey
+}
+
+// verificationKey возвращает ключ проверки подписи для выбранного алгоритма.
+func (s *Service) verificationKey() any {
+	if s.signingMethod == jwt.SigningMethodRS256 {
+		return s.publicKey
+	}
+	return s.secretKey
+}
+
 func (s *Service) ValidateToken(ctx context.Context, tokenString string) (uuid.UUID, error) {
 	const op = "JWTService.ValidateToken"
 	log := logger.ContextLogger(ctx, nil).With(zap.String("op", op))
@@ -160,10 +244,10 @@ func (s *Service) ValidateToken(ctx context.Context, tokenString string) (uuid.U
 
 	var claims Claims
 	token, err := jwt.ParseWithClaims(tokenString, &claims, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("%w: expected HS256, got %v", ErrInvalidSigningMethod, token.Header["alg"])
+		if token.Method.Alg() != s.signingMethod.Alg() {
+			return nil, fmt.Errorf("%w: expected %s, got %v", ErrInvalidSigningMethod, s.signingMethod.Alg(), token.Header["alg"])
 		}
-		return s.secretKey, nil
+		return s.verificationKey(), nil
 	})
 
 	if err != nil {
@@ -175,7 +259,7 @@ func (s *Service) ValidateToken(ctx context.Context, tokenString string) (uuid.U
 			zap.Error(err),
 			zap.String("token_prefix", tokenString[:min(10, len(tokenString))]))
 
-		return uuid.Nil, fmt.Errorf("%w: %s", ErrInvalidToken, err.Error())
+		return uuid.Nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
 	}
 
 	if !token.Valid {
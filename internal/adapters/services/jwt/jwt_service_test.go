@@ -0,0 +1,147 @@
+package jwt_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	jwtsvc "github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/services/jwt"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func testContext() context.Context {
+	return logger.WithLogger(context.Background(), logger.New(zapcore.NewNopCore()))
+}
+
+// generateKeyPair возвращает закрытый и открытый ключи RSA в формате PEM.
+func generateKeyPair(t *testing.T) ([]byte, []byte) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	publicDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}),
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})
+}
+
+func TestRSASignAndVerifyWithPublicKeyOnly(t *testing.T) {
+	ctx := testContext()
+	privatePEM, publicPEM := generateKeyPair(t)
+
+	signer, err := jwtsvc.NewServiceRSA(privatePEM, publicPEM, time.Minute, time.Hour)
+	require.NoError(t, err)
+
+	verifier, err := jwtsvc.NewVerifierRSA(publicPEM, time.Minute, time.Hour)
+	require.NoError(t, err)
+
+	userID := uuid.New()
+	tokens, err := signer.GenerateTokens(ctx, userID, "user")
+	require.NoError(t, err)
+
+	validatedID, err := verifier.ValidateToken(ctx, tokens.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, userID, validatedID)
+
+	validatedID, err = signer.ValidateToken(ctx, tokens.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, userID, validatedID)
+
+	claims, err := verifier.ParseToken(ctx, tokens.RefreshToken)
+	require.NoError(t, err)
+	assert.Equal(t, userID.String(), claims["user_id"])
+
+	// Refresh токен не принимается как токен доступа
+	_, err = verifier.ValidateToken(ctx, tokens.RefreshToken)
+	assert.ErrorIs(t, err, jwtsvc.ErrInvalidToken)
+}
+
+func TestRSAVerifierCannotSign(t *testing.T) {
+	_, publicPEM := generateKeyPair(t)
+
+	verifier, err := jwtsvc.NewVerifierRSA(publicPEM, time.Minute, time.Hour)
+	require.NoError(t, err)
+
+	_, err = verifier.GenerateTokens(testContext(), uuid.New(), "user")
+	assert.ErrorIs(t, err, jwtsvc.ErrVerifyOnly)
+}
+
+func TestRSARejectsTokenSignedByAnotherKey(t *testing.T) {
+	ctx := testContext()
+	foreignPrivatePEM, foreignPublicPEM := generateKeyPair(t)
+	_, publicPEM := generateKeyPair(t)
+
+	foreignSigner, err := jwtsvc.NewServiceRSA(foreignPrivatePEM, foreignPublicPEM, time.Minute, time.Hour)
+	require.NoError(t, err)
+
+	verifier, err := jwtsvc.NewVerifierRSA(publicPEM, time.Minute, time.Hour)
+	require.NoError(t, err)
+
+	tokens, err := foreignSigner.GenerateTokens(ctx, uuid.New(), "user")
+	require.NoError(t, err)
+
+	_, err = verifier.ValidateToken(ctx, tokens.AccessToken)
+	assert.ErrorIs(t, err, jwtsvc.ErrInvalidToken)
+}
+
+func TestRSARejectsHMACToken(t *testing.T) {
+	ctx := testContext()
+	_, publicPEM := generateKeyPair(t)
+
+	hmacSigner := jwtsvc.NewService("0123456789abcdef0123456789abcdef", time.Minute, time.Hour)
+	tokens, err := hmacSigner.GenerateTokens(ctx, uuid.New(), "user")
+	require.NoError(t, err)
+
+	verifier, err := jwtsvc.NewVerifierRSA(publicPEM, time.Minute, time.Hour)
+	require.NoError(t, err)
+
+	_, err = verifier.ValidateToken(ctx, tokens.AccessToken)
+	assert.ErrorIs(t, err, jwtsvc.ErrInvalidToken)
+	assert.ErrorIs(t, err, jwtsvc.ErrInvalidSigningMethod)
+}
+
+func TestNewServiceRSAInvalidKeys(t *testing.T) {
+	privatePEM, publicPEM := generateKeyPair(t)
+	_, otherPublicPEM := generateKeyPair(t)
+
+	_, err := jwtsvc.NewServiceRSA([]byte("not a key"), publicPEM, time.Minute, time.Hour)
+	assert.ErrorIs(t, err, jwtsvc.ErrInvalidRSAKey)
+
+	_, err = jwtsvc.NewServiceRSA(privatePEM, []byte("not a key"), time.Minute, time.Hour)
+	assert.ErrorIs(t, err, jwtsvc.ErrInvalidRSAKey)
+
+	_, err = jwtsvc.NewServiceRSA(privatePEM, otherPublicPEM, time.Minute, time.Hour)
+	assert.ErrorIs(t, err, jwtsvc.ErrKeyMismatch)
+
+	_, err = jwtsvc.NewVerifierRSA([]byte("not a key"), time.Minute, time.Hour)
+	assert.ErrorIs(t, err, jwtsvc.ErrInvalidRSAKey)
+}
+
+func TestHMACRoundTrip(t *testing.T) {
+	ctx := testContext()
+	svc := jwtsvc.NewService("0123456789abcdef0123456789abcdef", time.Minute, time.Hour)
+
+	userID := uuid.New()
+	tokens, err := svc.GenerateTokens(ctx, userID, "user")
+	require.NoError(t, err)
+
+	validatedID, err := svc.ValidateToken(ctx, tokens.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, userID, validatedID)
+
+	other := jwtsvc.NewService("fedcba9876543210fedcba9876543210", time.Minute, time.Hour)
+	_, err = other.ValidateToken(ctx, tokens.AccessToken)
+	assert.ErrorIs(t, err, jwtsvc.ErrInvalidToken)
+}
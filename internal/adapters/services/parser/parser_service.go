@@ -6,10 +6,15 @@ import (
 	"fmt"
 	"go/ast"
 	"go/parser"
+	"go/scanner"
 	"go/token"
+	"math"
 	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
+	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
 	parserPort "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/service/parser"
 	"github.com/google/uuid"
@@ -24,10 +29,22 @@ var (
 	ErrInvalidParenExpression = errors.New("invalid parenthesized expression")
 	ErrDivisionByZero         = errors.New("division by zero")
 	ErrExpressionTooComplex   = errors.New("expression too complex")
+	ErrParseTimeout           = errors.New("expression parsing timed out")
+	ErrExpressionTooLong      = errors.New("expression too long")
+	ErrFactorialOperand       = errors.New("factorial operand must be a non-negative integer")
 )
 
 type Service struct {
 	maxOperations int
+
+	// implicitZeroOperand - дополнять выражение, оканчивающееся оператором, нулевым операндом.
+	implicitZeroOperand bool
+
+	// maxExpressionLength - наибольшая длина выражения в байтах, ноль - без ограничения.
+	maxExpressionLength int
+
+	// divisionByZero - результат деления на ноль, по умолчанию ошибка.
+	divisionByZero orchestrator.DivisionByZeroPolicy
 }
 
 var _ parserPort.ExpressionParser = (*Service)(nil)
@@ -39,25 +56,275 @@ func NewService(maxOperations int) *Service {
 	return &Service{maxOperations: maxOperations}
 }
 
+// SetImplicitZeroOperand задает реакцию на выражение, оканчивающееся оператором (5+).
+// В нестрогом режиме недостающий операнд считается нулем: 5+ вычисляется как 5+0.
+// По умолчанию такое выражение считается ошибочным.
+func (s *Service) SetImplicitZeroOperand(enabled bool) {
+	s.implicitZeroOperand = enabled
+}
+
+// SetMaxExpressionLength ограничивает длину выражения в байтах: более длинное выражение
+// отклоняется в Validate до разбора, чтобы огромная строка не расходовала память на AST.
+// Неположительное значение отключает проверку.
+func (s *Service) SetMaxExpressionLength(length int) {
+	s.maxExpressionLength = max(length, 0)
+}
+
+// SetDivisionByZeroPolicy задает результат деления на ноль. В нестрогих режимах деление
+// и остаток от деления на ноль не отклоняются при разборе, а дают бесконечность или NaN,
+// как у агентов с той же политикой.
+func (s *Service) SetDivisionByZeroPolicy(policy orchestrator.DivisionByZeroPolicy) {
+	s.divisionByZero = policy
+}
+
+// normalize дополняет выражение нулевым операндом, если оно оканчивается оператором
+// и включен нестрогий режим.
+func (s *Service) normalize(expression string) string {
+	if !s.implicitZeroOperand {
+		return expression
+	}
+
+	trimmed := strings.TrimRightFunc(expression, unicode.IsSpace)
+	// Одиночный оператор без операндов не дополняется: с унарными знаками +0 стал бы допустимым.
+	if len(strings.TrimSpace(trimmed)) <= 1 {
+		return expression
+	}
+
+	switch trimmed[len(trimmed)-1] {
+	case '+', '-', '*', '/', '%':
+		return trimmed + "0"
+	default:
+		return expression
+	}
+}
+
+// separateSigns разделяет пробелом подряд идущие знаки + и -: 2--3 -> 2- -3.
+// Иначе сканер Go прочитает их как операторы -- и ++, а так первый знак остается
+// бинарным или унарным по положению в выражении, а следующие становятся унарными.
+func separateSigns(expression string) string {
+	if !strings.Contains(expression, "--") && !strings.Contains(expression, "++") &&
+		!strings.Contains(expression, "+-") && !strings.Contains(expression, "-+") {
+		return expression
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(expression) + 8)
+	for i := 0; i < len(expression); i++ {
+		c := expression[i]
+		sb.WriteByte(c)
+		if isSign(c) && i+1 < len(expression) && isSign(expression[i+1]) {
+			sb.WriteByte(' ')
+		}
+	}
+	return sb.String()
+}
+
+// rewriteFactorials заменяет постфиксный ! на пустые скобки вызова: 5! -> 5(), (2+3)! -> (2+3)().
+// В Go нет постфиксных операторов, а вызов без аргументов go/parser связывает с операндом
+// так же плотно, как факториал, поэтому -3! разбирается как -(3!). ! в остальных позициях
+// (!5) не заменяется и отклоняется как неподдерживаемый оператор.
+func rewriteFactorials(expression string) string {
+	if !strings.Contains(expression, "!") {
+		return expression
+	}
+
+	factorials := postfixFactorials(expression)
+	var sb strings.Builder
+	sb.Grow(len(expression) + 4)
+	for i := 0; i < len(expression); i++ {
+		if factorials[i] {
+			sb.WriteString("()")
+			continue
+		}
+		sb.WriteByte(expression[i])
+	}
+	return sb.String()
+}
+
+// postfixFactorials отмечает символы !, которые стоят после операнда (числа, скобки или другого
+// факториала) и поэтому читаются как факториал.
+func postfixFactorials(expression string) []bool {
+	factorials := make([]bool, len(expression))
+	afterOperand := false
+	for i := 0; i < len(expression); i++ {
+		c := expression[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+		case c == '!':
+			factorials[i] = afterOperand
+		default:
+			afterOperand = endsOperand(c)
+		}
+	}
+	return factorials
+}
+
+// endsOperand сообщает, может ли символ завершать операнд.
+func endsOperand(c byte) bool {
+	return c == ')' || c == '.' || c == '_' ||
+		c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
 func (s *Service) Validate(ctx context.Context, expression string) error {
+	if s.maxExpressionLength > 0 && len(expression) > s.maxExpressionLength {
+		return fmt.Errorf("%w: %w: %d bytes, limit is %d",
+			domainerrors.ErrInvalidExpression, ErrExpressionTooLong, len(expression), s.maxExpressionLength)
+	}
 	if strings.TrimSpace(expression) == "" {
 		return ErrEmptyExpression
 	}
+	normalized := s.normalize(expression)
 
-	if _, err := parser.ParseExpr(expression); err != nil {
-		return fmt.Errorf("%w: %s", ErrInvalidExpression, err.Error())
+	fset := token.NewFileSet()
+	expr, err := parseExprFrom(ctx, fset, normalized)
+	if err != nil {
+		if errors.Is(err, ErrParseTimeout) {
+			return err
+		}
+		return fmt.Errorf("%w: %w", ErrInvalidExpression, newSyntaxError(expression, normalized, err))
+	}
+
+	// go/parser принимает любое выражение Go (*3, x, 2^3), поэтому узлы, которые калькулятор
+	// не вычисляет, тоже считаются синтаксической ошибкой.
+	// Вызов без аргументов допустим, только если его скобки получены из факториала, а не набраны в выражении.
+	isFactorial := func(call *ast.CallExpr) bool {
+		offset := sourceOffset(normalized, fset.Position(call.Lparen).Offset)
+		return len(call.Args) == 0 && offset < len(normalized) && normalized[offset] == '!'
+	}
+	if err := checkNodes(ctx, expr, isFactorial); err != nil {
+		var nodeErr *nodeError
+		if !errors.As(err, &nodeErr) {
+			return err
+		}
+		offset := min(sourceOffset(normalized, fset.Position(nodeErr.pos).Offset), len(expression))
+		return fmt.Errorf("%w: %w", ErrInvalidExpression, &orchestrator.SyntaxError{
+			Offset:  utf8.RuneCountInString(expression[:offset]),
+			Message: nodeErr.message,
+		})
 	}
 
 	return nil
 }
 
+// nodeError - узел AST, который калькулятор не поддерживает, и его позиция.
+type nodeError struct {
+	pos     token.Pos
+	message string
+}
+
+func (e *nodeError) Error() string {
+	return e.message
+}
+
+// checkNodes проверяет, что выражение состоит только из чисел, скобок, унарных + и -,
+// бинарных + - * / % и постфиксного !.
+func checkNodes(ctx context.Context, expr ast.Expr, isFactorial func(*ast.CallExpr) bool) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%w: %w", ErrParseTimeout, err)
+	}
+
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if _, err := strconv.ParseFloat(e.Value, 64); err != nil {
+			return &nodeError{pos: e.ValuePos, message: "invalid number " + e.Value}
+		}
+		return nil
+
+	case *ast.ParenExpr:
+		return checkNodes(ctx, e.X, isFactorial)
+
+	case *ast.UnaryExpr:
+		if e.Op != token.ADD && e.Op != token.SUB {
+			return &nodeError{pos: e.OpPos, message: "unsupported operator " + e.Op.String()}
+		}
+		return checkNodes(ctx, e.X, isFactorial)
+
+	case *ast.CallExpr:
+		if !isFactorial(e) {
+			return &nodeError{pos: e.Lparen, message: "unexpected ("}
+		}
+		return checkNodes(ctx, e.Fun, isFactorial)
+
+	case *ast.BinaryExpr:
+		switch e.Op {
+		case token.ADD, token.SUB, token.MUL, token.QUO, token.REM:
+		default:
+			return &nodeError{pos: e.OpPos, message: "unsupported operator " + e.Op.String()}
+		}
+		if err := checkNodes(ctx, e.X, isFactorial); err != nil {
+			return err
+		}
+		return checkNodes(ctx, e.Y, isFactorial)
+
+	case *ast.StarExpr:
+		return &nodeError{pos: e.Star, message: "unexpected *"}
+
+	case *ast.Ident:
+		return &nodeError{pos: e.NamePos, message: "unexpected identifier " + e.Name}
+
+	default:
+		return &nodeError{pos: expr.Pos(), message: "unsupported expression"}
+	}
+}
+
+// newSyntaxError переводит первую ошибку go/parser в orchestrator.SyntaxError. Позиция ошибки в строке,
+// которую разбирал go/parser, пересчитывается в номер символа исходного выражения.
+func newSyntaxError(expression, normalized string, err error) *orchestrator.SyntaxError {
+	var list scanner.ErrorList
+	if !errors.As(err, &list) || len(list) == 0 {
+		return &orchestrator.SyntaxError{Message: err.Error()}
+	}
+
+	first := list[0]
+	offset := min(sourceOffset(normalized, first.Pos.Offset), len(expression))
+	return &orchestrator.SyntaxError{
+		Offset:  utf8.RuneCountInString(expression[:offset]),
+		Message: first.Msg,
+	}
+}
+
+// sourceOffset переводит байтовую позицию в строке после separateSigns и rewriteFactorials
+// в позицию в expression. Позиция вставленного пробела соответствует следующему за ним знаку,
+// обе скобки, которыми заменен факториал, - самому символу !.
+func sourceOffset(expression string, offset int) int {
+	factorials := postfixFactorials(expression)
+	position := 0
+	for i := 0; i < len(expression); i++ {
+		if position >= offset {
+			return i
+		}
+		position++
+		if isSign(expression[i]) && i+1 < len(expression) && isSign(expression[i+1]) {
+			if position >= offset {
+				return i + 1
+			}
+			position++
+		}
+		if factorials[i] {
+			if position >= offset {
+				return i
+			}
+			position++
+		}
+	}
+	return len(expression)
+}
+
+func isSign(c byte) bool {
+	return c == '+' || c == '-'
+}
+
 func (s *Service) Parse(ctx context.Context, expression string) ([]*orchestrator.Operation, error) {
 	if err := s.Validate(ctx, expression); err != nil {
 		return nil, err
 	}
+	expression = s.normalize(expression)
 
-	expr, err := parser.ParseExpr(expression)
+	expr, err := parseExpr(ctx, expression)
 	if err != nil {
+		if errors.Is(err, ErrParseTimeout) {
+			return nil, err
+		}
 		return nil, fmt.Errorf("%w: %s", ErrParsingExpression, err.Error())
 	}
 
@@ -73,12 +340,265 @@ func (s *Service) Parse(ctx context.Context, expression string) ([]*orchestrator
 	return operations, nil
 }
 
+// ctxCheckTokens - через сколько лексем сканирование проверяет отмену контекста.
+const ctxCheckTokens = 1024
+
+// parseExpr строит AST выражения, прерываясь по отмене контекста.
+func parseExpr(ctx context.Context, expression string) (ast.Expr, error) {
+	return parseExprFrom(ctx, token.NewFileSet(), expression)
+}
+
+// parseExprFrom строит AST выражения, сохраняя позиции узлов в fset.
+// go/parser не принимает контекст, поэтому выражение сначала сканируется с проверкой
+// отмены каждые ctxCheckTokens лексем, и только полностью прочитанное выражение
+// передается go/parser. Построение дерева линейно по числу уже прочитанных лексем,
+// а обход AST снова проверяет контекст, поэтому разбор не продолжается в фоне
+// после истечения срока.
+func parseExprFrom(ctx context.Context, fset *token.FileSet, expression string) (ast.Expr, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrParseTimeout, err)
+	}
+
+	source := rewriteFactorials(separateSigns(expression))
+	if err := scanTokens(ctx, source); err != nil {
+		return nil, err
+	}
+
+	expr, err := parser.ParseExprFrom(fset, "", source, 0)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, fmt.Errorf("%w: %w", ErrParseTimeout, ctxErr)
+	}
+	return expr, err
+}
+
+// scanTokens читает лексемы выражения до конца, проверяя отмену контекста.
+// Ошибки сканирования не возвращаются - их с позицией сообщит go/parser.
+func scanTokens(ctx context.Context, source string) error {
+	var s scanner.Scanner
+	file := token.NewFileSet().AddFile("", -1, len(source))
+	s.Init(file, []byte(source), nil, 0)
+
+	for i := 1; ; i++ {
+		if i%ctxCheckTokens == 0 {
+			if err := ctx.Err(); err != nil {
+				return fmt.Errorf("%w: %w", ErrParseTimeout, err)
+			}
+		}
+		if _, tok, _ := s.Scan(); tok == token.EOF {
+			return nil
+		}
+	}
+}
+
+// Evaluate вычисляет выражение непосредственно по AST с той же семантикой,
+// что и агенты: вещественная арифметика и ошибка при делении на ноль, если не включен
+// нестрогий режим деления.
+func (s *Service) Evaluate(ctx context.Context, expression string) (float64, error) {
+	if err := s.Validate(ctx, expression); err != nil {
+		return 0, err
+	}
+	expression = s.normalize(expression)
+
+	expr, err := parseExpr(ctx, expression)
+	if err != nil {
+		if errors.Is(err, ErrParseTimeout) {
+			return 0, err
+		}
+		return 0, fmt.Errorf("%w: %s", ErrParsingExpression, err.Error())
+	}
+
+	return s.evaluateExpression(ctx, expr)
+}
+
+func (s *Service) evaluateExpression(ctx context.Context, expr ast.Expr) (float64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrParseTimeout, err)
+	}
+
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		value, err := strconv.ParseFloat(e.Value, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %s", ErrInvalidExpression, e.Value)
+		}
+		return value, nil
+
+	case *ast.ParenExpr:
+		return s.evaluateExpression(ctx, e.X)
+
+	case *ast.UnaryExpr:
+		if e.Op != token.SUB && e.Op != token.ADD {
+			return 0, ErrUnsupportedOperator
+		}
+		value, err := s.evaluateExpression(ctx, e.X)
+		if err != nil {
+			return 0, err
+		}
+		if e.Op == token.ADD {
+			return value, nil
+		}
+		return -value, nil
+
+	case *ast.CallExpr:
+		if len(e.Args) != 0 {
+			return 0, ErrUnsupportedOperator
+		}
+		value, err := s.evaluateExpression(ctx, e.Fun)
+		if err != nil {
+			return 0, err
+		}
+		return orchestrator.Factorial(value)
+
+	case *ast.BinaryExpr:
+		left, err := s.evaluateExpression(ctx, e.X)
+		if err != nil {
+			return 0, err
+		}
+		right, err := s.evaluateExpression(ctx, e.Y)
+		if err != nil {
+			return 0, err
+		}
+
+		switch e.Op {
+		case token.ADD:
+			return left + right, nil
+		case token.SUB:
+			return left - right, nil
+		case token.MUL:
+			return left * right, nil
+		case token.QUO:
+			if right == 0 && !s.divisionByZero.AllowsDivisionByZero() {
+				return 0, ErrDivisionByZero
+			}
+			return s.divisionByZero.Divide(left, right)
+		case token.REM:
+			if right == 0 && !s.divisionByZero.AllowsDivisionByZero() {
+				return 0, ErrDivisionByZero
+			}
+			return s.divisionByZero.Mod(left, right)
+		default:
+			return 0, ErrUnsupportedOperator
+		}
+
+	default:
+		return 0, ErrInvalidExpression
+	}
+}
+
+// Parenthesize восстанавливает выражение по AST, заключая в скобки каждый операнд,
+// который сам является операцией: 2+3*4 -> 2+(3*4), 1-2-3 -> (1-2)-3.
+// Скобки исходного выражения не сохраняются, так как порядок уже задан деревом.
+func (s *Service) Parenthesize(ctx context.Context, expression string, options orchestrator.PreviewOptions) (string, error) {
+	if err := s.Validate(ctx, expression); err != nil {
+		return "", err
+	}
+	expression = s.normalize(expression)
+
+	expr, err := parseExpr(ctx, expression)
+	if err != nil {
+		if errors.Is(err, ErrParseTimeout) {
+			return "", err
+		}
+		return "", fmt.Errorf("%w: %s", ErrParsingExpression, err.Error())
+	}
+
+	var sb strings.Builder
+	if err := s.writeGrouped(ctx, &sb, unparen(expr), options); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+func (s *Service) writeGrouped(ctx context.Context, sb *strings.Builder, expr ast.Expr, options orchestrator.PreviewOptions) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%w: %w", ErrParseTimeout, err)
+	}
+
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		sb.WriteString(e.Value)
+		return nil
+
+	case *ast.UnaryExpr:
+		switch e.Op {
+		case token.ADD:
+			// Унарный плюс не меняет значения, поэтому в превью он опускается.
+			return s.writeGrouped(ctx, sb, unparen(e.X), options)
+		case token.SUB:
+			sb.WriteString("-")
+			return s.writeOperand(ctx, sb, e.X, options)
+		default:
+			return ErrUnsupportedOperator
+		}
+
+	case *ast.CallExpr:
+		if len(e.Args) != 0 {
+			return ErrUnsupportedOperator
+		}
+		if err := s.writeOperand(ctx, sb, e.Fun, options); err != nil {
+			return err
+		}
+		sb.WriteString("!")
+		return nil
+
+	case *ast.BinaryExpr:
+		switch e.Op {
+		case token.ADD, token.SUB, token.MUL, token.QUO, token.REM:
+		default:
+			return ErrUnsupportedOperator
+		}
+
+		if err := s.writeOperand(ctx, sb, e.X, options); err != nil {
+			return err
+		}
+		if options.Spaced {
+			sb.WriteString(" " + e.Op.String() + " ")
+		} else {
+			sb.WriteString(e.Op.String())
+		}
+		return s.writeOperand(ctx, sb, e.Y, options)
+
+	default:
+		return ErrInvalidExpression
+	}
+}
+
+// writeOperand записывает операнд, заключая его в скобки, если он не является числом.
+func (s *Service) writeOperand(ctx context.Context, sb *strings.Builder, expr ast.Expr, options orchestrator.PreviewOptions) error {
+	expr = unparen(expr)
+	if _, ok := expr.(*ast.BasicLit); ok {
+		return s.writeGrouped(ctx, sb, expr, options)
+	}
+
+	sb.WriteString("(")
+	if err := s.writeGrouped(ctx, sb, expr, options); err != nil {
+		return err
+	}
+	sb.WriteString(")")
+	return nil
+}
+
+// unparen снимает скобки исходного выражения.
+func unparen(expr ast.Expr) ast.Expr {
+	for {
+		paren, ok := expr.(*ast.ParenExpr)
+		if !ok {
+			return expr
+		}
+		expr = paren.X
+	}
+}
+
 func (s *Service) processExpression(
 	ctx context.Context,
 	expr ast.Expr,
 	operations *[]*orchestrator.Operation,
 	calculationID *uuid.UUID,
 ) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrParseTimeout, err)
+	}
+
 	var calcID uuid.UUID
 	if calculationID != nil {
 		calcID = *calculationID
@@ -88,36 +608,49 @@ func (s *Service) processExpression(
 	case *ast.BinaryExpr:
 		return s.processBinaryExpr(ctx, e, operations, calculationID)
 
+	case *ast.CallExpr:
+		return s.processFactorial(ctx, e, operations, calculationID)
+
 	case *ast.BasicLit:
+		// Сканер Go читает число в экспоненциальной записи (1.5e-3) одним литералом, и агенты
+		// разбирают его так же. Литералы, которые агент не разберет (2i, 'a'), отклоняются здесь.
+		if _, err := strconv.ParseFloat(e.Value, 64); err != nil {
+			return "", fmt.Errorf("%w: %s", ErrInvalidExpression, e.Value)
+		}
 		return e.Value, nil
 
 	case *ast.ParenExpr:
 		return s.processExpression(ctx, e.X, operations, calculationID)
 
 	case *ast.UnaryExpr:
-		if e.Op == token.SUB {
-			val, err := s.processExpression(ctx, e.X, operations, calculationID)
-			if err != nil {
-				return "", err
-			}
+		if e.Op != token.SUB && e.Op != token.ADD {
+			return "", ErrUnsupportedOperator
+		}
 
-			if _, err := strconv.ParseFloat(val, 64); err == nil {
-				return "-" + val, nil
-			}
+		val, err := s.processExpression(ctx, e.X, operations, calculationID)
+		if err != nil {
+			return "", err
+		}
+		if e.Op == token.ADD {
+			return val, nil
+		}
 
-			op := &orchestrator.Operation{
-				ID:            uuid.New(),
-				CalculationID: calcID,
-				OperationType: orchestrator.OperationTypeSubtraction,
-				Operand1:      "0",
-				Operand2:      val,
-				Status:        orchestrator.OperationStatusPending,
-			}
+		// Знак числа переносится в сам операнд, отдельная операция не нужна.
+		if _, err := strconv.ParseFloat(val, 64); err == nil {
+			return negate(val), nil
+		}
 
-			*operations = append(*operations, op)
-			return op.ID.String(), nil
+		op := &orchestrator.Operation{
+			ID:            uuid.New(),
+			CalculationID: calcID,
+			OperationType: orchestrator.OperationTypeSubtraction,
+			Operand1:      "0",
+			Operand2:      "ref:" + val,
+			Status:        orchestrator.OperationStatusPending,
 		}
-		return "", ErrUnsupportedOperator
+
+		*operations = append(*operations, op)
+		return op.ID.String(), nil
 
 	default:
 		return "", ErrInvalidExpression
@@ -145,8 +678,8 @@ func (s *Service) processBinaryExpr(
 	rightIsUUID := isUUIDReference(rightVal)
 
 	// If division by zero check is needed, make sure to parse non-UUID values
-	if expr.Op == token.QUO && !rightIsUUID {
-		if rightVal == "0" {
+	if (expr.Op == token.QUO || expr.Op == token.REM) && !rightIsUUID && !s.divisionByZero.AllowsDivisionByZero() {
+		if value, err := strconv.ParseFloat(rightVal, 64); err == nil && value == 0 {
 			return "", ErrDivisionByZero
 		}
 	}
@@ -161,6 +694,8 @@ func (s *Service) processBinaryExpr(
 		operType = orchestrator.OperationTypeMultiplication
 	case token.QUO:
 		operType = orchestrator.OperationTypeDivision
+	case token.REM:
+		operType = orchestrator.OperationTypeModulo
 	default:
 		return "", ErrUnsupportedOperator
 	}
@@ -192,6 +727,56 @@ func (s *Service) processBinaryExpr(
 	return op.ID.String(), nil
 }
 
+// processFactorial создает операцию факториала над операндом, записанным перед !.
+// Отрицательный или дробный числовой операнд отклоняется при разборе, вычисленный - агентом.
+func (s *Service) processFactorial(
+	ctx context.Context,
+	expr *ast.CallExpr,
+	operations *[]*orchestrator.Operation,
+	calculationID *uuid.UUID,
+) (string, error) {
+	if len(expr.Args) != 0 {
+		return "", ErrUnsupportedOperator
+	}
+
+	val, err := s.processExpression(ctx, expr.Fun, operations, calculationID)
+	if err != nil {
+		return "", err
+	}
+
+	operand := val
+	if isUUIDReference(val) {
+		operand = "ref:" + val
+	} else if value, err := strconv.ParseFloat(val, 64); err == nil && (value < 0 || value != math.Trunc(value)) {
+		return "", fmt.Errorf("%w: %s", ErrFactorialOperand, val)
+	}
+
+	var calcID uuid.UUID
+	if calculationID != nil {
+		calcID = *calculationID
+	}
+
+	op := &orchestrator.Operation{
+		ID:            uuid.New(),
+		CalculationID: calcID,
+		OperationType: orchestrator.OperationTypeFactorial,
+		Operand1:      operand,
+		Operand2:      "0",
+		Status:        orchestrator.OperationStatusPending,
+	}
+
+	*operations = append(*operations, op)
+	return op.ID.String(), nil
+}
+
+// negate меняет знак числового операнда: 5 -> -5, -5 -> 5.
+func negate(val string) string {
+	if trimmed, ok := strings.CutPrefix(val, "-"); ok {
+		return trimmed
+	}
+	return "-" + val
+}
+
 func isUUIDReference(val string) bool {
 	_, err := uuid.Parse(val)
 	return err == nil && len(val) == 36 // Standard UUID length
@@ -0,0 +1,492 @@
+package parser_test
+
+import (
+	"context"
+	"math"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/services/parser"
+	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hugeExpression строит выражение вида 1+1+...+1 из terms слагаемых.
+func hugeExpression(terms int) string {
+	return strings.TrimSuffix(strings.Repeat("1+", terms), "+")
+}
+
+func TestParse(t *testing.T) {
+	svc := parser.NewService(100)
+
+	operations, err := svc.Parse(context.Background(), "2+2*2")
+	require.NoError(t, err)
+	assert.Len(t, operations, 2)
+
+	_, err = svc.Parse(context.Background(), "")
+	assert.ErrorIs(t, err, parser.ErrEmptyExpression)
+}
+
+func TestParseModulo(t *testing.T) {
+	svc := parser.NewService(100)
+
+	operations, err := svc.Parse(context.Background(), "2+7%3")
+	require.NoError(t, err)
+	require.Len(t, operations, 2)
+	assert.Equal(t, orchestrator.OperationTypeModulo, operations[0].OperationType)
+	assert.Equal(t, "7", operations[0].Operand1)
+	assert.Equal(t, "3", operations[0].Operand2)
+	assert.Equal(t, orchestrator.OperationTypeAddition, operations[1].OperationType)
+
+	_, err = svc.Parse(context.Background(), "7%0")
+	assert.ErrorIs(t, err, parser.ErrDivisionByZero)
+}
+
+func TestParseFactorial(t *testing.T) {
+	svc := parser.NewService(100)
+
+	operations, err := svc.Parse(context.Background(), "2*5!")
+	require.NoError(t, err)
+	require.Len(t, operations, 2)
+	assert.Equal(t, orchestrator.OperationTypeFactorial, operations[0].OperationType)
+	assert.Equal(t, "5", operations[0].Operand1)
+	assert.Equal(t, orchestrator.OperationTypeMultiplication, operations[1].OperationType)
+	assert.Equal(t, "ref:"+operations[0].ID.String(), operations[1].Operand2)
+
+	operations, err = svc.Parse(context.Background(), "(2+1)!")
+	require.NoError(t, err)
+	require.Len(t, operations, 2)
+	assert.Equal(t, orchestrator.OperationTypeFactorial, operations[1].OperationType)
+	assert.Equal(t, "ref:"+operations[0].ID.String(), operations[1].Operand1)
+
+	_, err = svc.Parse(context.Background(), "(-3)!")
+	require.ErrorIs(t, err, parser.ErrFactorialOperand)
+
+	_, err = svc.Parse(context.Background(), "2.5!")
+	require.ErrorIs(t, err, parser.ErrFactorialOperand)
+}
+
+func TestParseHonorsCancelledContext(t *testing.T) {
+	svc := parser.NewService(100)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := svc.Parse(ctx, "2+2")
+	assert.ErrorIs(t, err, parser.ErrParseTimeout)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	err = svc.Validate(ctx, "2+2")
+	assert.ErrorIs(t, err, parser.ErrParseTimeout)
+}
+
+func TestParseTimeout(t *testing.T) {
+	const (
+		timeout = 5 * time.Millisecond
+		cutoff  = 500 * time.Millisecond
+	)
+
+	svc := parser.NewService(5_000_000)
+	expression := hugeExpression(2_000_000)
+
+	testCases := []struct {
+		name string
+		run  func(ctx context.Context) error
+	}{
+		{
+			name: "Parse",
+			run: func(ctx context.Context) error {
+				_, err := svc.Parse(ctx, expression)
+				return err
+			},
+		},
+		{
+			name: "Validate",
+			run: func(ctx context.Context) error {
+				return svc.Validate(ctx, expression)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			goroutines := runtime.NumGoroutine()
+			start := time.Now()
+			err := tc.run(ctx)
+			elapsed := time.Since(start)
+
+			require.ErrorIs(t, err, parser.ErrParseTimeout)
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+			assert.Less(t, elapsed, cutoff, "parsing must stop shortly after the deadline")
+			assert.LessOrEqual(t, runtime.NumGoroutine(), goroutines, "parsing must not continue in the background")
+		})
+	}
+}
+
+func TestMaxExpressionLength(t *testing.T) {
+	const limit = 9
+
+	svc := parser.NewService(100)
+	svc.SetMaxExpressionLength(limit)
+
+	atLimit := hugeExpression(5) // 1+1+1+1+1
+	require.Len(t, atLimit, limit)
+	overLimit := atLimit + "1"
+
+	require.NoError(t, svc.Validate(context.Background(), atLimit))
+	operations, err := svc.Parse(context.Background(), atLimit)
+	require.NoError(t, err)
+	assert.Len(t, operations, 4)
+
+	err = svc.Validate(context.Background(), overLimit)
+	require.ErrorIs(t, err, domainerrors.ErrInvalidExpression)
+	assert.ErrorIs(t, err, parser.ErrExpressionTooLong)
+
+	_, err = svc.Parse(context.Background(), overLimit)
+	require.ErrorIs(t, err, domainerrors.ErrInvalidExpression)
+	_, err = svc.Evaluate(context.Background(), overLimit)
+	require.ErrorIs(t, err, parser.ErrExpressionTooLong)
+
+	t.Run("Implicit zero operand does not count", func(t *testing.T) {
+		lenient := parser.NewService(100)
+		lenient.SetMaxExpressionLength(limit)
+		lenient.SetImplicitZeroOperand(true)
+
+		result, err := lenient.Evaluate(context.Background(), "1+1+1+10+")
+		require.NoError(t, err)
+		assert.InDelta(t, 13, result, 1e-12)
+	})
+
+	t.Run("Disabled by default", func(t *testing.T) {
+		require.NoError(t, parser.NewService(100).Validate(context.Background(), hugeExpression(10_000)))
+	})
+}
+
+func TestLenientDivision(t *testing.T) {
+	strict := parser.NewService(100)
+	_, err := strict.Parse(context.Background(), "1/0")
+	require.Error(t, err)
+	_, err = strict.Evaluate(context.Background(), "1/0")
+	require.ErrorIs(t, err, parser.ErrDivisionByZero)
+
+	lenient := parser.NewService(100)
+	lenient.SetDivisionByZeroPolicy(orchestrator.DivisionByZeroInfinity)
+
+	ops, err := lenient.Parse(context.Background(), "1/0+2")
+	require.NoError(t, err)
+	assert.Len(t, ops, 2)
+
+	result, err := lenient.Evaluate(context.Background(), "1/0+2")
+	require.NoError(t, err)
+	assert.True(t, math.IsInf(result, 1))
+
+	result, err = lenient.Evaluate(context.Background(), "5%0")
+	require.NoError(t, err)
+	assert.True(t, math.IsNaN(result))
+
+	nan := parser.NewService(100)
+	nan.SetDivisionByZeroPolicy(orchestrator.DivisionByZeroNaN)
+
+	result, err = nan.Evaluate(context.Background(), "1/0+2")
+	require.NoError(t, err)
+	assert.True(t, math.IsNaN(result))
+}
+
+func TestValidateErrorOffset(t *testing.T) {
+	svc := parser.NewService(100)
+
+	testCases := []struct {
+		name       string
+		expression string
+		offset     int
+	}{
+		{name: "Missing last operand", expression: "2+", offset: 2},
+		{name: "Operator instead of operand", expression: "2*/3", offset: 2},
+		{name: "Unclosed parenthesis", expression: "(2+3", offset: 4},
+		{name: "Extra closing parenthesis", expression: "1+2)", offset: 3},
+		{name: "Missing operator", expression: "2 3", offset: 2},
+		{name: "Sign sequence without operand", expression: "2--", offset: 3},
+		{name: "Error after separated signs", expression: "1--2*/3", offset: 5},
+		{name: "Offset counts characters, not bytes", expression: "2×3", offset: 1},
+		{name: "Dereference is not an operand", expression: "2+*3", offset: 2},
+		{name: "Unsupported binary operator", expression: "2^3", offset: 1},
+		{name: "Identifier", expression: "1+x", offset: 2},
+		{name: "Imaginary literal", expression: "(1+2i)", offset: 3},
+		{name: "Unsupported operator after signs", expression: "1--2&3", offset: 4},
+		{name: "Prefix exclamation mark", expression: "!5", offset: 0},
+		{name: "Call parentheses", expression: "5()", offset: 1},
+		{name: "Error after factorial", expression: "3!!*/2", offset: 4},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := svc.Validate(context.Background(), tc.expression)
+			require.ErrorIs(t, err, parser.ErrInvalidExpression)
+
+			var syntaxErr *orchestrator.SyntaxError
+			require.ErrorAs(t, err, &syntaxErr)
+			assert.Equal(t, tc.offset, syntaxErr.Offset)
+			assert.NotEmpty(t, syntaxErr.Message)
+		})
+	}
+
+	t.Run("Valid expression", func(t *testing.T) {
+		require.NoError(t, svc.Validate(context.Background(), "-(2+3)*4"))
+		require.NoError(t, svc.Validate(context.Background(), "(2+1)! * 3 !"))
+	})
+}
+
+func TestEvaluate(t *testing.T) {
+	svc := parser.NewService(100)
+
+	testCases := []struct {
+		name        string
+		expression  string
+		expected    float64
+		expectedErr error
+	}{
+		{name: "Division", expression: "1/2", expected: 0.5},
+		{name: "Precedence", expression: "2+2*2", expected: 6},
+		{name: "Parentheses and unary minus", expression: "-(2+3)*2", expected: -10},
+		{name: "Integer modulo", expression: "7%3", expected: 1},
+		{name: "Floating modulo", expression: "7.5%2", expected: 1.5},
+		{name: "Modulo precedence", expression: "1+7%3*2", expected: 3},
+		{name: "Division by zero", expression: "1/0", expectedErr: parser.ErrDivisionByZero},
+		{name: "Modulo by zero", expression: "7%0", expectedErr: parser.ErrDivisionByZero},
+		{name: "Factorial", expression: "5!", expected: 120},
+		{name: "Factorial of zero", expression: "0!", expected: 1},
+		{name: "Factorial binds tighter than unary minus", expression: "-3!+1", expected: -5},
+		{name: "Repeated factorial", expression: "3!!", expected: 720},
+		{name: "Factorial overflow", expression: "171!", expectedErr: domainerrors.ErrNumericOverflow},
+		{name: "Fractional factorial", expression: "2.5!", expectedErr: domainerrors.ErrFactorialOperand},
+		{name: "Empty expression", expression: "", expectedErr: parser.ErrEmptyExpression},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := svc.Evaluate(context.Background(), tc.expression)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.InDelta(t, tc.expected, result, 1e-12)
+		})
+	}
+}
+
+func TestImplicitZeroOperand(t *testing.T) {
+	t.Run("Strict mode rejects trailing operator", func(t *testing.T) {
+		svc := parser.NewService(100)
+
+		err := svc.Validate(context.Background(), "5+")
+		require.ErrorIs(t, err, parser.ErrInvalidExpression)
+
+		_, err = svc.Evaluate(context.Background(), "5+")
+		require.ErrorIs(t, err, parser.ErrInvalidExpression)
+	})
+
+	t.Run("Lenient mode defaults missing operand to zero", func(t *testing.T) {
+		svc := parser.NewService(100)
+		svc.SetImplicitZeroOperand(true)
+
+		require.NoError(t, svc.Validate(context.Background(), "5+"))
+
+		result, err := svc.Evaluate(context.Background(), "5+ ")
+		require.NoError(t, err)
+		assert.InDelta(t, 5, result, 1e-12)
+
+		operations, err := svc.Parse(context.Background(), "2*3-")
+		require.NoError(t, err)
+		require.Len(t, operations, 2)
+		assert.Equal(t, orchestrator.OperationTypeSubtraction, operations[1].OperationType)
+		assert.Equal(t, "0", operations[1].Operand2)
+
+		// Пропущенный делитель становится нулем, и деление остается ошибкой
+		_, err = svc.Evaluate(context.Background(), "5/")
+		require.ErrorIs(t, err, parser.ErrDivisionByZero)
+
+		_, err = svc.Evaluate(context.Background(), "+")
+		require.Error(t, err)
+	})
+}
+
+func TestParenthesize(t *testing.T) {
+	svc := parser.NewService(100)
+
+	testCases := []struct {
+		expression string
+		expected   string
+	}{
+		{"2+3*4", "2+(3*4)"},
+		{"2*3+4", "(2*3)+4"},
+		{"1-2-3", "(1-2)-3"},
+		{"8/4/2", "(8/4)/2"},
+		{"2+3*4-5/6", "(2+(3*4))-(5/6)"},
+		{"(2+3)*4", "(2+3)*4"},
+		{"((7))", "7"},
+		{"10%3*2+1", "((10%3)*2)+1"},
+		{"-2*-(3+4)", "(-2)*(-(3+4))"},
+		{"2+3!*(1+1)!", "2+((3!)*((1+1)!))"},
+		{"-3!", "-(3!)"},
+		{"2 + 3 * (4 - 1) / 5", "2+((3*(4-1))/5)"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expression, func(t *testing.T) {
+			grouped, err := svc.Parenthesize(context.Background(), tc.expression, orchestrator.PreviewOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, grouped)
+
+			// Сгруппированное выражение вычисляется так же, как исходное
+			original, err := svc.Evaluate(context.Background(), tc.expression)
+			require.NoError(t, err)
+			regrouped, err := svc.Evaluate(context.Background(), grouped)
+			require.NoError(t, err)
+			assert.InDelta(t, original, regrouped, 0)
+		})
+	}
+
+	t.Run("Spaced", func(t *testing.T) {
+		grouped, err := svc.Parenthesize(context.Background(), "2+3*4", orchestrator.PreviewOptions{Spaced: true})
+		require.NoError(t, err)
+		assert.Equal(t, "2 + (3 * 4)", grouped)
+	})
+
+	t.Run("Invalid expression", func(t *testing.T) {
+		_, err := svc.Parenthesize(context.Background(), "2+*3", orchestrator.PreviewOptions{})
+		require.ErrorIs(t, err, parser.ErrInvalidExpression)
+
+		_, err = svc.Parenthesize(context.Background(), "", orchestrator.PreviewOptions{})
+		require.ErrorIs(t, err, parser.ErrEmptyExpression)
+	})
+}
+
+// executeOperations выполняет операции в порядке выдачи парсером так же, как агент:
+// операнды ref:{id} заменяются результатами ранее выполненных операций.
+func executeOperations(t *testing.T, operations []*orchestrator.Operation) float64 {
+	t.Helper()
+
+	results := make(map[string]float64, len(operations))
+	operand := func(value string) float64 {
+		if ref, ok := strings.CutPrefix(value, "ref:"); ok {
+			result, found := results[ref]
+			require.True(t, found, "reference to an operation that was not executed yet: %s", ref)
+			return result
+		}
+		number, err := strconv.ParseFloat(value, 64)
+		require.NoError(t, err, "operand must be a number: %q", value)
+		return number
+	}
+
+	var last float64
+	for _, op := range operations {
+		left, right := operand(op.Operand1), operand(op.Operand2)
+		switch op.OperationType {
+		case orchestrator.OperationTypeAddition:
+			last = left + right
+		case orchestrator.OperationTypeSubtraction:
+			last = left - right
+		case orchestrator.OperationTypeMultiplication:
+			last = left * right
+		case orchestrator.OperationTypeDivision:
+			last = left / right
+		case orchestrator.OperationTypeModulo:
+			last = math.Mod(left, right)
+		case orchestrator.OperationTypeFactorial:
+			factorial, err := orchestrator.Factorial(left)
+			require.NoError(t, err)
+			last = factorial
+		}
+		results[op.ID.String()] = last
+	}
+	return last
+}
+
+func TestScientificNotation(t *testing.T) {
+	svc := parser.NewService(100)
+
+	operations, err := svc.Parse(context.Background(), "1.5e-3*2+1E3")
+	require.NoError(t, err)
+	require.Len(t, operations, 2)
+	assert.Equal(t, "1.5e-3", operations[0].Operand1)
+	assert.Equal(t, "2", operations[0].Operand2)
+	assert.Equal(t, "1E3", operations[1].Operand2)
+	assert.InDelta(t, 1000.003, executeOperations(t, operations), 1e-9)
+
+	operations, err = svc.Parse(context.Background(), "-2.5e+2/5")
+	require.NoError(t, err)
+	require.Len(t, operations, 1)
+	assert.Equal(t, "-2.5e+2", operations[0].Operand1)
+
+	result, err := svc.Evaluate(context.Background(), "1e-3*1e3")
+	require.NoError(t, err)
+	assert.InDelta(t, 1, result, 1e-12)
+
+	_, err = svc.Parse(context.Background(), "1/0e5")
+	require.ErrorIs(t, err, parser.ErrDivisionByZero)
+
+	_, err = svc.Parse(context.Background(), "2i+1")
+	require.ErrorIs(t, err, parser.ErrInvalidExpression)
+}
+
+func TestUnarySigns(t *testing.T) {
+	svc := parser.NewService(100)
+
+	testCases := []struct {
+		name       string
+		expression string
+		expected   float64
+		operations int
+	}{
+		{name: "Leading minus", expression: "-5+3", expected: -2, operations: 1},
+		{name: "Minus after operator", expression: "2*-4", expected: -8, operations: 1},
+		{name: "Minus after subtraction", expression: "2--3", expected: 5, operations: 1},
+		{name: "Double negation", expression: "--5", expected: 5, operations: 0},
+		{name: "Double negation in parentheses", expression: "-(-5)*2", expected: 10, operations: 1},
+		{name: "Leading plus", expression: "+5-3", expected: 2, operations: 1},
+		{name: "Plus after operator", expression: "2*+4", expected: 8, operations: 1},
+		{name: "Mixed signs", expression: "2-+-3", expected: 5, operations: 1},
+		{name: "Negated subexpression", expression: "-(2+3)*2", expected: -10, operations: 3},
+		{name: "Negated subexpression operand", expression: "10/-(1+1)", expected: -5, operations: 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.NoError(t, svc.Validate(context.Background(), tc.expression))
+
+			evaluated, err := svc.Evaluate(context.Background(), tc.expression)
+			require.NoError(t, err)
+			assert.InDelta(t, tc.expected, evaluated, 1e-12)
+
+			operations, err := svc.Parse(context.Background(), tc.expression)
+			require.NoError(t, err)
+			require.Len(t, operations, tc.operations)
+			if tc.operations > 0 {
+				assert.InDelta(t, tc.expected, executeOperations(t, operations), 1e-12)
+			}
+		})
+	}
+
+	t.Run("Signed operands are emitted as numbers", func(t *testing.T) {
+		operations, err := svc.Parse(context.Background(), "2*-4")
+		require.NoError(t, err)
+		require.Len(t, operations, 1)
+		assert.Equal(t, "2", operations[0].Operand1)
+		assert.Equal(t, "-4", operations[0].Operand2)
+	})
+
+	t.Run("Division by negative zero", func(t *testing.T) {
+		_, err := svc.Parse(context.Background(), "5/-0")
+		require.ErrorIs(t, err, parser.ErrDivisionByZero)
+	})
+}
@@ -0,0 +1,38 @@
+package password
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type bcryptHasher struct {
+	cost int
+}
+
+func newBcryptHasher(cost int) *bcryptHasher {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		cost = DefaultCost
+	}
+	return &bcryptHasher{cost: cost}
+}
+
+func (h *bcryptHasher) hash(password []byte) (string, error) {
+	hashedBytes, err := bcrypt.GenerateFromPassword(password, h.cost)
+	if err != nil {
+		return "", fmt.Errorf("bcrypt: %w", err)
+	}
+	return string(hashedBytes), nil
+}
+
+func (h *bcryptHasher) verify(password []byte, encoded string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), password)
+	if err != nil {
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+		return false, fmt.Errorf("bcrypt: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,52 @@
+package password
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Algorithm определяет алгоритм хеширования паролей.
+type Algorithm string
+
+const (
+	AlgorithmBcrypt   Algorithm = "bcrypt"
+	AlgorithmArgon2id Algorithm = "argon2id"
+	AlgorithmScrypt   Algorithm = "scrypt"
+)
+
+var ErrUnsupportedAlgorithm = errors.New("unsupported password hash algorithm")
+
+// hasher реализует отдельный алгоритм хеширования.
+// Закодированный хеш начинается с префикса алгоритма, по которому Verify выбирает нужную реализацию.
+type hasher interface {
+	hash(password []byte) (string, error)
+	verify(password []byte, encoded string) (bool, error)
+}
+
+// ParseAlgorithm проверяет название алгоритма из конфигурации. Пустое значение означает bcrypt.
+func ParseAlgorithm(name string) (Algorithm, error) {
+	switch algorithm := Algorithm(strings.ToLower(strings.TrimSpace(name))); algorithm {
+	case AlgorithmBcrypt, AlgorithmArgon2id, AlgorithmScrypt:
+		return algorithm, nil
+	case "":
+		return AlgorithmBcrypt, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, name)
+	}
+}
+
+// detectAlgorithm определяет алгоритм по префиксу закодированного хеша.
+// Хеши bcrypt хранятся в собственном формате ($2a$, $2b$, $2y$) для совместимости с уже сохраненными.
+func detectAlgorithm(encoded string) (Algorithm, error) {
+	switch {
+	case strings.HasPrefix(encoded, "$2a$"), strings.HasPrefix(encoded, "$2b$"), strings.HasPrefix(encoded, "$2y$"):
+		return AlgorithmBcrypt, nil
+	case strings.HasPrefix(encoded, argon2idPrefix):
+		return AlgorithmArgon2id, nil
+	case strings.HasPrefix(encoded, scryptPrefix):
+		return AlgorithmScrypt, nil
+	default:
+		return "", ErrInvalidHashFormat
+	}
+}
@@ -14,7 +14,7 @@ import (
 )
 
 const (
-	MinPasswordLength = 8
+	MinPasswordLength = password.MinPasswordLength
 	DefaultCost       = bcrypt.DefaultCost
 	lowerChars        = "abcdefghijklmnopqrstuvwxyz"
 	upperChars        = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
@@ -30,17 +30,45 @@ var (
 	ErrGeneratingRandom  = errors.New("error generating random password")
 )
 
+// Service хеширует пароли выбранным алгоритмом и проверяет хеши любого поддерживаемого алгоритма,
+// что позволяет переходить с одного алгоритма на другой без сброса паролей.
 type Service struct {
-	cost int
+	algorithm Algorithm
+	hashers   map[Algorithm]hasher
 }
 
 var _ password.Service = (*Service)(nil)
 
-func NewService(cost int) *Service {
-	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
-		cost = DefaultCost
+// NewService создает сервис паролей. algorithm задает алгоритм для новых хешей,
+// cost - стоимость bcrypt (вне допустимого диапазона используется DefaultCost).
+func NewService(algorithm Algorithm, cost int) (*Service, error) {
+	algorithm, err := ParseAlgorithm(string(algorithm))
+	if err != nil {
+		return nil, err
+	}
+
+	hashers := map[Algorithm]hasher{
+		AlgorithmBcrypt:   newBcryptHasher(cost),
+		AlgorithmArgon2id: &argon2idHasher{params: DefaultArgon2Params},
+		AlgorithmScrypt:   &scryptHasher{},
+	}
+
+	return &Service{algorithm: algorithm, hashers: hashers}, nil
+}
+
+// SetArgon2Params задает параметры Argon2id для новых хешей. Хеши с другими параметрами
+// по-прежнему проверяются: параметры записаны в самом хеше.
+func (s *Service) SetArgon2Params(params Argon2Params) error {
+	if err := params.Validate(); err != nil {
+		return err
 	}
-	return &Service{cost: cost}
+	s.hashers[AlgorithmArgon2id] = &argon2idHasher{params: params}
+	return nil
+}
+
+// Algorithm возвращает алгоритм, которым хешируются новые пароли.
+func (s *Service) Algorithm() Algorithm {
+	return s.algorithm
 }
 
 func (s *Service) Hash(ctx context.Context, password string) (string, error) {
@@ -54,13 +82,16 @@ func (s *Service) Hash(ctx context.Context, password string) (string, error) {
 		return "", ErrPasswordTooShort
 	}
 
-	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(password), s.cost)
+	hashed, err := s.hashers[s.algorithm].hash([]byte(password))
 	if err != nil {
-		logger.Error(ctx, nil, "failed to hash password", zap.String("op", op), zap.Error(err))
+		logger.Error(ctx, nil, "failed to hash password",
+			zap.String("op", op),
+			zap.String("algorithm", string(s.algorithm)),
+			zap.Error(err))
 		return "", fmt.Errorf("failed to hash password: %w", err)
 	}
 
-	return string(hashedBytes), nil
+	return hashed, nil
 }
 
 func (s *Service) Verify(ctx context.Context, password, hashedPassword string) (bool, error) {
@@ -72,15 +103,17 @@ func (s *Service) Verify(ctx context.Context, password, hashedPassword string) (
 		return false, ErrInvalidHashFormat
 	}
 
-	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+	algorithm, err := detectAlgorithm(hashedPassword)
+	if err != nil {
+		return false, err
+	}
+
+	valid, err := s.hashers[algorithm].verify([]byte(password), hashedPassword)
 	if err != nil {
-		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
-			return false, nil
-		}
 		return false, fmt.Errorf("error verifying password: %w", err)
 	}
 
-	return true, nil
+	return valid, nil
 }
 
 func (s *Service) GenerateRandom(ctx context.Context, length int) (string, error) {
@@ -0,0 +1,138 @@
+package password_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/services/password"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPassword = "correct-horse-battery"
+
+func TestNewService(t *testing.T) {
+	svc, err := password.NewService("", 0)
+	require.NoError(t, err)
+	assert.Equal(t, password.AlgorithmBcrypt, svc.Algorithm())
+
+	svc, err = password.NewService("Argon2ID", 0)
+	require.NoError(t, err)
+	assert.Equal(t, password.AlgorithmArgon2id, svc.Algorithm())
+
+	_, err = password.NewService("md5", 0)
+	assert.ErrorIs(t, err, password.ErrUnsupportedAlgorithm)
+}
+
+func TestHashAndVerify(t *testing.T) {
+	testCases := []struct {
+		algorithm password.Algorithm
+		prefix    string
+	}{
+		{algorithm: password.AlgorithmBcrypt, prefix: "$2a$"},
+		{algorithm: password.AlgorithmArgon2id, prefix: "$argon2id$v=19$"},
+		{algorithm: password.AlgorithmScrypt, prefix: "$scrypt$ln="},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.algorithm), func(t *testing.T) {
+			ctx := context.Background()
+			svc, err := password.NewService(tc.algorithm, 4)
+			require.NoError(t, err)
+
+			hash, err := svc.Hash(ctx, testPassword)
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(hash, tc.prefix), "unexpected hash format: %s", hash)
+
+			valid, err := svc.Verify(ctx, testPassword, hash)
+			require.NoError(t, err)
+			assert.True(t, valid)
+
+			valid, err = svc.Verify(ctx, "wrong-password", hash)
+			require.NoError(t, err)
+			assert.False(t, valid)
+
+			other, err := svc.Hash(ctx, testPassword)
+			require.NoError(t, err)
+			assert.NotEqual(t, hash, other, "hashes must be salted")
+		})
+	}
+}
+
+func TestVerifyAcrossAlgorithms(t *testing.T) {
+	ctx := context.Background()
+	algorithms := []password.Algorithm{password.AlgorithmBcrypt, password.AlgorithmArgon2id, password.AlgorithmScrypt}
+
+	hashes := make(map[password.Algorithm]string, len(algorithms))
+	for _, algorithm := range algorithms {
+		svc, err := password.NewService(algorithm, 4)
+		require.NoError(t, err)
+		hashes[algorithm], err = svc.Hash(ctx, testPassword)
+		require.NoError(t, err)
+	}
+
+	for _, verifier := range algorithms {
+		svc, err := password.NewService(verifier, 4)
+		require.NoError(t, err)
+
+		for hashedWith, hash := range hashes {
+			valid, err := svc.Verify(ctx, testPassword, hash)
+			require.NoError(t, err, "%s service verifying %s hash", verifier, hashedWith)
+			assert.True(t, valid, "%s service verifying %s hash", verifier, hashedWith)
+		}
+	}
+}
+
+func TestVerifyInvalidHash(t *testing.T) {
+	ctx := context.Background()
+	svc, err := password.NewService(password.AlgorithmBcrypt, 4)
+	require.NoError(t, err)
+
+	_, err = svc.Verify(ctx, testPassword, "plain-text")
+	assert.ErrorIs(t, err, password.ErrInvalidHashFormat)
+
+	_, err = svc.Verify(ctx, testPassword, "$argon2id$v=19$broken")
+	assert.ErrorIs(t, err, password.ErrInvalidHashFormat)
+
+	_, err = svc.Verify(ctx, testPassword, "")
+	assert.ErrorIs(t, err, password.ErrInvalidHashFormat)
+}
+
+func TestArgon2Params(t *testing.T) {
+	ctx := context.Background()
+	svc, err := password.NewService(password.AlgorithmArgon2id, 0)
+	require.NoError(t, err)
+	require.NoError(t, svc.SetArgon2Params(password.Argon2Params{Memory: 16 * 1024, Time: 2, Parallelism: 1}))
+
+	hash, err := svc.Hash(ctx, testPassword)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=16384,t=2,p=1$"), "unexpected hash format: %s", hash)
+
+	// Параметры читаются из хеша, а не из настроек проверяющего сервиса
+	defaults, err := password.NewService(password.AlgorithmArgon2id, 0)
+	require.NoError(t, err)
+
+	valid, err := defaults.Verify(ctx, testPassword, hash)
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	valid, err = defaults.Verify(ctx, "wrong-password", hash)
+	require.NoError(t, err)
+	assert.False(t, valid)
+
+	for _, params := range []password.Argon2Params{
+		{Memory: 64 * 1024, Time: 0, Parallelism: 4},
+		{Memory: 64 * 1024, Time: 1, Parallelism: 0},
+		{Memory: 16, Time: 1, Parallelism: 4},
+	} {
+		assert.ErrorIs(t, svc.SetArgon2Params(params), password.ErrInvalidArgon2Params, "%+v", params)
+	}
+
+	parts := strings.Split(hash, "$")
+	for _, encoded := range []string{"m=16384,t=0,p=1", "m=16384,t=2", "m=x,t=2,p=1"} {
+		parts[3] = encoded
+		_, err = defaults.Verify(ctx, testPassword, strings.Join(parts, "$"))
+		assert.ErrorIs(t, err, password.ErrInvalidHashFormat, encoded)
+	}
+}
@@ -0,0 +1,65 @@
+package password
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	scryptPrefix = "$scrypt$"
+
+	scryptLogN   = 15
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+	scryptMaxLog = 30
+)
+
+// scryptHasher кодирует хеш в формате $scrypt$ln=15,r=8,p=1$<salt>$<hash>.
+type scryptHasher struct{}
+
+func (h *scryptHasher) hash(password []byte) (string, error) {
+	salt, err := randomSalt()
+	if err != nil {
+		return "", err
+	}
+
+	key, err := scrypt.Key(password, salt, 1<<scryptLogN, scryptR, scryptP, scryptKeyLen)
+	if err != nil {
+		return "", fmt.Errorf("scrypt: %w", err)
+	}
+
+	return fmt.Sprintf("%sln=%d,r=%d,p=%d$%s$%s",
+		scryptPrefix, scryptLogN, scryptR, scryptP,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (h *scryptHasher) verify(password []byte, encoded string) (bool, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 5 {
+		return false, ErrInvalidHashFormat
+	}
+
+	var logN, r, p int
+	if _, err := fmt.Sscanf(parts[2], "ln=%d,r=%d,p=%d", &logN, &r, &p); err != nil || logN <= 0 || logN > scryptMaxLog {
+		return false, ErrInvalidHashFormat
+	}
+
+	salt, key, err := decodeSaltAndKey(parts[3], parts[4])
+	if err != nil {
+		return false, err
+	}
+
+	actual, err := scrypt.Key(password, salt, 1<<logN, r, p, len(key))
+	if err != nil {
+		return false, fmt.Errorf("scrypt: %w", err)
+	}
+
+	return subtle.ConstantTimeCompare(actual, key) == 1, nil
+}
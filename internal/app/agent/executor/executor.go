@@ -6,13 +6,13 @@ import (
 	"context"
 	"fmt"
 	"sync"
-	"time"
 
 	errors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/agent"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
 	agentPool "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/api/orchestrator"
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/retry"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
@@ -21,31 +21,22 @@ import (
 // и предоставляет механизм повторных попыток при неудачах.
 type OperationExecutor struct {
 	pool           agentPool.AgentPool
-	maxRetries     int
-	retryDelay     time.Duration
+	retryPolicy    retry.Policy
 	mu             sync.RWMutex
 	assignedAgents map[uuid.UUID]string
 }
 
-// NewOperationExecutor создает новый экземпляр OperationExecutor с указанными параметрами.
+// NewOperationExecutor создает новый экземпляр OperationExecutor с указанной политикой
+// повторных попыток. Недопустимые значения политики заменяются значениями по умолчанию.
 // Возвращает nil, если pool равен nil.
-func NewOperationExecutor(pool agentPool.AgentPool, maxRetries int, retryDelay time.Duration) *OperationExecutor {
+func NewOperationExecutor(pool agentPool.AgentPool, retryPolicy retry.Policy) *OperationExecutor {
 	if pool == nil {
 		return nil
 	}
 
-	// Ensure sane defaults
-	if maxRetries < 0 {
-		maxRetries = 0
-	}
-	if retryDelay <= 0 {
-		retryDelay = 100 * time.Millisecond
-	}
-
 	return &OperationExecutor{
 		pool:           pool,
-		maxRetries:     maxRetries,
-		retryDelay:     retryDelay,
+		retryPolicy:    retryPolicy.Normalize(),
 		assignedAgents: make(map[uuid.UUID]string),
 	}
 }
@@ -68,7 +59,7 @@ func (e *OperationExecutor) ExecuteOperation(ctx context.Context, operation *orc
 
 	var lastError error
 
-	for attempt := 0; attempt <= e.maxRetries; attempt++ {
+	for attempt := 0; attempt < e.retryPolicy.MaxAttempts; attempt++ {
 		select {
 		case <-ctx.Done():
 			e.removeAgentAssignment(operation.ID)
@@ -77,15 +68,15 @@ func (e *OperationExecutor) ExecuteOperation(ctx context.Context, operation *orc
 		}
 
 		if attempt > 0 {
+			backoff := e.retryPolicy.Backoff(attempt)
 			log.Info("Retrying operation execution",
 				zap.Int("attempt", attempt),
+				zap.Duration("backoff", backoff),
 				zap.Error(lastError))
 
-			select {
-			case <-ctx.Done():
+			if err := retry.Sleep(ctx, backoff); err != nil {
 				e.removeAgentAssignment(operation.ID)
-				return fmt.Errorf("%w: %w", errors.ErrContextCanceled, ctx.Err())
-			case <-time.After(e.retryDelay):
+				return fmt.Errorf("%w: %w", errors.ErrContextCanceled, err)
 			}
 		}
 
@@ -104,7 +95,7 @@ func (e *OperationExecutor) ExecuteOperation(ctx context.Context, operation *orc
 
 		e.recordAgentAssignment(operation.ID, agent.ID)
 
-		err = e.pool.AssignOperation(agent.ID, operation)
+		err = e.pool.AssignOperation(ctx, agent.ID, operation)
 		if err != nil {
 			log.Warn("Failed to assign operation to agent",
 				zap.String("agent_id", agent.ID),
@@ -121,7 +112,7 @@ func (e *OperationExecutor) ExecuteOperation(ctx context.Context, operation *orc
 	}
 
 	log.Error("All attempts to execute operation failed",
-		zap.Int("max_retries", e.maxRetries),
+		zap.Int("max_attempts", e.retryPolicy.MaxAttempts),
 		zap.Error(lastError))
 	return fmt.Errorf("%w: %w", errors.ErrMaxRetriesExceeded, lastError)
 }
@@ -8,11 +8,16 @@ import (
 	"testing"
 	"time"
 
+	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/agent"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/retry"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
 )
 
 type MockAgentPool struct {
@@ -35,7 +40,7 @@ func (m *MockAgentPool) GetAvailableAgent(operationType int) (*agent.Agent, erro
 	return args.Get(0).(*agent.Agent), args.Error(1)
 }
 
-func (m *MockAgentPool) AssignOperation(agentID string, operation *orchestrator.Operation) error {
+func (m *MockAgentPool) AssignOperation(_ context.Context, agentID string, operation *orchestrator.Operation) error {
 	args := m.Called(agentID, operation)
 	return args.Error(0)
 }
@@ -56,37 +61,97 @@ func (m *MockAgentPool) ListAgents() ([]*agent.Agent, error) {
 	return args.Get(0).([]*agent.Agent), args.Error(1)
 }
 
+func (m *MockAgentPool) SetAgentCapacity(agentID string, capacity int) (*agent.AgentStats, error) {
+	args := m.Called(agentID, capacity)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*agent.AgentStats), args.Error(1)
+}
+
+func (m *MockAgentPool) GetPoolStats() (*agent.PoolStats, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*agent.PoolStats), args.Error(1)
+}
+
+func (m *MockAgentPool) GetTimingProfile() (*agent.TimingProfile, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*agent.TimingProfile), args.Error(1)
+}
+
+var testRetryPolicy = retry.Policy{MaxAttempts: 4, BaseDelay: 100 * time.Millisecond, Multiplier: 1}
+
 func TestNewOperationExecutor(t *testing.T) {
 	t.Run("Valid parameters", func(t *testing.T) {
 		pool := &MockAgentPool{}
-		executor := NewOperationExecutor(pool, 3, 200*time.Millisecond)
+		policy := retry.Policy{MaxAttempts: 3, BaseDelay: 200 * time.Millisecond, Multiplier: 2, MaxDelay: time.Second, Jitter: 0.1}
+		executor := NewOperationExecutor(pool, policy)
 
 		assert.NotNil(t, executor)
 		assert.Equal(t, pool, executor.pool)
-		assert.Equal(t, 3, executor.maxRetries)
-		assert.Equal(t, 200*time.Millisecond, executor.retryDelay)
+		assert.Equal(t, policy, executor.retryPolicy)
 		assert.NotNil(t, executor.assignedAgents)
 	})
 
 	t.Run("Nil pool", func(t *testing.T) {
-		executor := NewOperationExecutor(nil, 3, 200*time.Millisecond)
+		executor := NewOperationExecutor(nil, testRetryPolicy)
 		assert.Nil(t, executor)
 	})
 
 	t.Run("Negative parameters", func(t *testing.T) {
 		pool := &MockAgentPool{}
-		executor := NewOperationExecutor(pool, -1, -100*time.Millisecond)
+		executor := NewOperationExecutor(pool, retry.Policy{MaxAttempts: -1, BaseDelay: -100 * time.Millisecond})
 
 		assert.NotNil(t, executor)
-		assert.Equal(t, 0, executor.maxRetries)
-		assert.Equal(t, 100*time.Millisecond, executor.retryDelay)
+		assert.Equal(t, 1, executor.retryPolicy.MaxAttempts)
+		assert.Equal(t, 100*time.Millisecond, executor.retryPolicy.BaseDelay)
+	})
+}
+
+func TestExecuteOperationRetryPolicy(t *testing.T) {
+	ctx := logger.WithLogger(context.Background(), logger.New(zapcore.NewNopCore()))
+	operation := &orchestrator.Operation{ID: uuid.New(), OperationType: orchestrator.OperationTypeAddition}
+
+	t.Run("Retries with exponential backoff", func(t *testing.T) {
+		pool := new(MockAgentPool)
+		pool.On("GetAvailableAgent", int(orchestrator.OperationTypeAddition)).Return(nil, errors.New("busy"))
+		executor := NewOperationExecutor(pool, retry.Policy{MaxAttempts: 3, BaseDelay: 20 * time.Millisecond, Multiplier: 2})
+
+		start := time.Now()
+		err := executor.ExecuteOperation(ctx, operation)
+
+		require.ErrorIs(t, err, domainerrors.ErrMaxRetriesExceeded)
+		assert.GreaterOrEqual(t, time.Since(start), 60*time.Millisecond)
+		pool.AssertNumberOfCalls(t, "GetAvailableAgent", 3)
+	})
+
+	t.Run("Backoff respects context cancellation", func(t *testing.T) {
+		pool := new(MockAgentPool)
+		pool.On("GetAvailableAgent", int(orchestrator.OperationTypeAddition)).Return(nil, errors.New("busy"))
+		executor := NewOperationExecutor(pool, retry.Policy{MaxAttempts: 3, BaseDelay: time.Minute, Multiplier: 2})
+
+		cancelCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		err := executor.ExecuteOperation(cancelCtx, operation)
+
+		require.ErrorIs(t, err, domainerrors.ErrContextCanceled)
+		assert.Less(t, time.Since(start), time.Second)
+		pool.AssertNumberOfCalls(t, "GetAvailableAgent", 1)
 	})
 }
 
 func TestOperationAgentMapping(t *testing.T) {
 	t.Run("Assign and retrieve agent", func(t *testing.T) {
 		pool := new(MockAgentPool)
-		executor := NewOperationExecutor(pool, 3, 100*time.Millisecond)
+		executor := NewOperationExecutor(pool, testRetryPolicy)
 
 		operationID := uuid.New()
 		agentID := "test-agent-1"
@@ -100,7 +165,7 @@ func TestOperationAgentMapping(t *testing.T) {
 
 	t.Run("Search for non-existent operation", func(t *testing.T) {
 		pool := new(MockAgentPool)
-		executor := NewOperationExecutor(pool, 3, 100*time.Millisecond)
+		executor := NewOperationExecutor(pool, testRetryPolicy)
 
 		_, found := executor.GetOperationAgent(uuid.New())
 		assert.False(t, found)
@@ -108,7 +173,7 @@ func TestOperationAgentMapping(t *testing.T) {
 
 	t.Run("Search for nil UUID", func(t *testing.T) {
 		pool := new(MockAgentPool)
-		executor := NewOperationExecutor(pool, 3, 100*time.Millisecond)
+		executor := NewOperationExecutor(pool, testRetryPolicy)
 
 		agentID, found := executor.GetOperationAgent(uuid.Nil)
 		assert.False(t, found)
@@ -117,7 +182,7 @@ func TestOperationAgentMapping(t *testing.T) {
 
 	t.Run("Remove assignment", func(t *testing.T) {
 		pool := new(MockAgentPool)
-		executor := NewOperationExecutor(pool, 3, 100*time.Millisecond)
+		executor := NewOperationExecutor(pool, testRetryPolicy)
 
 		operationID := uuid.New()
 		agentID := "test-agent-1"
@@ -131,7 +196,7 @@ func TestOperationAgentMapping(t *testing.T) {
 
 	t.Run("ReleaseOperation removes assignment", func(t *testing.T) {
 		pool := new(MockAgentPool)
-		executor := NewOperationExecutor(pool, 3, 100*time.Millisecond)
+		executor := NewOperationExecutor(pool, testRetryPolicy)
 
 		operationID := uuid.New()
 		agentID := "test-agent-1"
@@ -145,7 +210,7 @@ func TestOperationAgentMapping(t *testing.T) {
 
 	t.Run("Concurrent access to assignedAgents", func(t *testing.T) {
 		pool := new(MockAgentPool)
-		executor := NewOperationExecutor(pool, 3, 100*time.Millisecond)
+		executor := NewOperationExecutor(pool, testRetryPolicy)
 
 		concurrentOperations := 100
 		var wg sync.WaitGroup
@@ -177,7 +242,7 @@ func TestOperationAgentMapping(t *testing.T) {
 func TestGetAgentsStatus(t *testing.T) {
 	t.Run("Successfully retrieve agents list", func(t *testing.T) {
 		pool := new(MockAgentPool)
-		executor := NewOperationExecutor(pool, 3, 100*time.Millisecond)
+		executor := NewOperationExecutor(pool, testRetryPolicy)
 
 		mockAgents := []*agent.Agent{
 			{ID: "agent-1", Status: agent.AgentStatusOnline},
@@ -195,7 +260,7 @@ func TestGetAgentsStatus(t *testing.T) {
 
 	t.Run("Error retrieving agents list", func(t *testing.T) {
 		pool := new(MockAgentPool)
-		executor := NewOperationExecutor(pool, 3, 100*time.Millisecond)
+		executor := NewOperationExecutor(pool, testRetryPolicy)
 
 		expectedErr := errors.New("database error")
 		pool.On("ListAgents").Return(nil, expectedErr)
@@ -210,7 +275,7 @@ func TestGetAgentsStatus(t *testing.T) {
 
 	t.Run("Context cancellation", func(t *testing.T) {
 		pool := new(MockAgentPool)
-		executor := NewOperationExecutor(pool, 3, 100*time.Millisecond)
+		executor := NewOperationExecutor(pool, testRetryPolicy)
 
 		ctx, cancel := context.WithCancel(context.Background())
 		cancel()
@@ -226,7 +291,7 @@ func TestGetAgentsStatus(t *testing.T) {
 func TestGetAssignedOperationsCount(t *testing.T) {
 	t.Run("Count assigned operations", func(t *testing.T) {
 		pool := new(MockAgentPool)
-		executor := NewOperationExecutor(pool, 3, 100*time.Millisecond)
+		executor := NewOperationExecutor(pool, testRetryPolicy)
 
 		count := executor.GetAssignedOperationsCount()
 		assert.Equal(t, 0, count)
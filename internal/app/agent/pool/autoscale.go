@@ -0,0 +1,193 @@
+package pool
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/app/agent/worker"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// defaultAutoscaleInterval - период проверки очереди, если интервал не задан.
+const defaultAutoscaleInterval = time.Second
+
+// AutoscaleConfig задает автомасштабирование пула по количеству ожидающих операций.
+// Нулевой MaxWorkers отключает масштабирование: пул работает с числом воркеров,
+// заданным при создании.
+type AutoscaleConfig struct {
+	// MinWorkers - меньше этого числа воркеров пул не сокращается.
+	MinWorkers int
+	// MaxWorkers - больше этого числа воркеров пул не растет.
+	MaxWorkers int
+	// BacklogThreshold - количество ожидающих операций, при превышении которого пул растет.
+	BacklogThreshold int
+	// SustainFor - сколько очередь должна непрерывно превышать порог, чтобы добавился воркер.
+	SustainFor time.Duration
+	// IdleFor - сколько воркер должен простаивать без операций, чтобы его остановили.
+	IdleFor time.Duration
+	// Interval - период проверки очереди.
+	Interval time.Duration
+}
+
+func (c AutoscaleConfig) enabled() bool {
+	return c.MaxWorkers > 0
+}
+
+// SetAutoscale включает автомасштабирование: пока ожидающих операций больше BacklogThreshold
+// дольше SustainFor, пул добавляет по одному воркеру до MaxWorkers; когда очередь ниже порога,
+// воркеры без операций дольше IdleFor останавливаются по одному до MinWorkers.
+// MinWorkers приводится к [1, MaxWorkers]. Вызывается до Start.
+func (p *AgentPool) SetAutoscale(cfg AutoscaleConfig) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if cfg.MaxWorkers <= 0 {
+		p.autoscale = AutoscaleConfig{}
+		return
+	}
+	cfg.MinWorkers = min(max(cfg.MinWorkers, 1), cfg.MaxWorkers)
+	cfg.BacklogThreshold = max(cfg.BacklogThreshold, 0)
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultAutoscaleInterval
+	}
+	p.autoscale = cfg
+}
+
+// WorkerCount возвращает текущее количество воркеров пула.
+func (p *AgentPool) WorkerCount() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return len(p.workers)
+}
+
+// runAutoscaler периодически сравнивает очередь ожидающих операций с порогом
+// и меняет количество воркеров до завершения ctx или остановки пула.
+func (p *AgentPool) runAutoscaler(ctx context.Context) {
+	p.mu.RLock()
+	interval := p.autoscale.Interval
+	p.mu.RUnlock()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var overSince time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.ctx.Done():
+			return
+		case now := <-ticker.C:
+			overSince = p.autoscaleStep(ctx, overSince, now)
+		}
+	}
+}
+
+// autoscaleStep выполняет одну проверку очереди. overSince - момент, с которого очередь
+// непрерывно превышает порог (нулевой, если не превышает); возвращается его новое значение.
+func (p *AgentPool) autoscaleStep(ctx context.Context, overSince, now time.Time) time.Time {
+	p.mu.RLock()
+	cfg := p.autoscale
+	active := p.running && !p.draining
+	workers := len(p.workers)
+	p.mu.RUnlock()
+
+	if !active {
+		return time.Time{}
+	}
+
+	log := logger.ContextLogger(ctx, nil)
+
+	backlog, err := p.backlog(ctx)
+	if err != nil {
+		log.Warn("Failed to count pending operations for autoscaling", zap.Error(err))
+		return overSince
+	}
+
+	if backlog <= cfg.BacklogThreshold {
+		p.retireIdleWorker(ctx, cfg, now)
+		return time.Time{}
+	}
+
+	if overSince.IsZero() {
+		overSince = now
+	}
+	if now.Sub(overSince) < cfg.SustainFor || workers >= cfg.MaxWorkers {
+		return overSince
+	}
+
+	agentID := fmt.Sprintf("agent-%s-%d", uuid.New().String()[:8], workers)
+	if p.startWorker(ctx, agentID) {
+		log.Info("Scaled agent pool up",
+			zap.String("agent_id", agentID),
+			zap.Int("backlog", backlog),
+			zap.Int("workers", workers+1))
+	}
+
+	// Следующий воркер добавляется, если очередь продержится выше порога еще SustainFor
+	return now
+}
+
+// retireIdleWorker останавливает один воркер без операций, простаивающий дольше IdleFor,
+// если воркеров больше MinWorkers. Воркер сначала убирается из пула, чтобы ему не назначили
+// новых операций, а операции, успевшие попасть в его очередь, возвращаются в статус PENDING.
+func (p *AgentPool) retireIdleWorker(ctx context.Context, cfg AutoscaleConfig, now time.Time) {
+	p.mu.Lock()
+	if len(p.workers) <= cfg.MinWorkers {
+		p.mu.Unlock()
+		return
+	}
+
+	ids := make([]string, 0, len(p.workers))
+	for id := range p.workers {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var (
+		retiredID string
+		retired   *worker.Worker
+	)
+	for _, id := range ids {
+		w := p.workers[id]
+		if w == nil || w.QueueDepth() > 0 {
+			continue
+		}
+
+		status := w.GetStatus()
+		if status == nil || status.CurrentLoad > 0 || now.Sub(status.LastOperationAt) < cfg.IdleFor {
+			continue
+		}
+
+		retiredID, retired = id, w
+		delete(p.workers, id)
+		break
+	}
+	remaining := len(p.workers)
+	p.mu.Unlock()
+
+	if retired == nil {
+		return
+	}
+
+	log := logger.ContextLogger(ctx, nil)
+
+	retired.Stop()
+	requeueCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), requeueTimeout)
+	defer cancel()
+	requeued := retired.RequeueQueued(requeueCtx)
+
+	if err := p.storage.Remove(retiredID); err != nil {
+		log.Warn("Failed to remove retired agent from storage", zap.String("agent_id", retiredID), zap.Error(err))
+	}
+
+	log.Info("Scaled agent pool down",
+		zap.String("agent_id", retiredID),
+		zap.Int("requeued", requeued),
+		zap.Int("workers", remaining))
+}
@@ -0,0 +1,84 @@
+package pool
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	memAgent "github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/db/memory/agent"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSetAutoscale(t *testing.T) {
+	pool, err := NewAgentPool(memAgent.NewAgentStorage(), new(MockOperationRepository), nil, 1)
+	require.NoError(t, err)
+
+	pool.SetAutoscale(AutoscaleConfig{MinWorkers: 5, MaxWorkers: 3, BacklogThreshold: -1})
+	assert.Equal(t, AutoscaleConfig{MinWorkers: 3, MaxWorkers: 3, Interval: defaultAutoscaleInterval}, pool.autoscale)
+
+	pool.SetAutoscale(AutoscaleConfig{MinWorkers: 0, MaxWorkers: 2, Interval: time.Second})
+	assert.Equal(t, 1, pool.autoscale.MinWorkers)
+
+	pool.SetAutoscale(AutoscaleConfig{MinWorkers: 1})
+	assert.False(t, pool.autoscale.enabled())
+}
+
+func TestAutoscale(t *testing.T) {
+	ctx, cancel := context.WithCancel(logger.WithLogger(context.Background(), logger.New(zapcore.NewNopCore())))
+	t.Cleanup(cancel)
+
+	pool, err := NewAgentPool(memAgent.NewAgentStorage(), new(MockOperationRepository), nil, 1)
+	require.NoError(t, err)
+	pool.SetDeterministic(true)
+	pool.SetAutoscale(AutoscaleConfig{
+		MinWorkers:       1,
+		MaxWorkers:       3,
+		BacklogThreshold: 5,
+		SustainFor:       80 * time.Millisecond,
+		IdleFor:          30 * time.Millisecond,
+		Interval:         10 * time.Millisecond,
+	})
+
+	// Синтетическая очередь ожидающих операций вместо обращения к репозиторию
+	var backlog atomic.Int64
+	pool.backlog = func(context.Context) (int, error) {
+		return int(backlog.Load()), nil
+	}
+
+	pool.Start(ctx)
+	t.Cleanup(func() { pool.Stop(context.WithoutCancel(ctx)) })
+	require.Equal(t, 1, pool.WorkerCount())
+
+	t.Run("Short backlog spike does not scale", func(t *testing.T) {
+		backlog.Store(10)
+		time.Sleep(15 * time.Millisecond)
+		backlog.Store(0)
+		assert.Never(t, func() bool { return pool.WorkerCount() > 1 }, 100*time.Millisecond, 5*time.Millisecond)
+	})
+
+	t.Run("Sustained backlog grows pool up to max", func(t *testing.T) {
+		backlog.Store(10)
+		require.Eventually(t, func() bool { return pool.WorkerCount() == 3 }, 3*time.Second, 5*time.Millisecond)
+		assert.Never(t, func() bool { return pool.WorkerCount() > 3 }, 150*time.Millisecond, 5*time.Millisecond)
+
+		stats, err := pool.GetPoolStats()
+		require.NoError(t, err)
+		assert.Equal(t, 3, stats.Workers)
+		assert.Len(t, stats.Agents, 3)
+	})
+
+	t.Run("Idle workers retire down to min", func(t *testing.T) {
+		backlog.Store(0)
+		require.Eventually(t, func() bool { return pool.WorkerCount() == 1 }, 3*time.Second, 5*time.Millisecond)
+		assert.Never(t, func() bool { return pool.WorkerCount() < 1 }, 150*time.Millisecond, 5*time.Millisecond)
+
+		stats, err := pool.GetPoolStats()
+		require.NoError(t, err)
+		assert.Equal(t, 1, stats.Workers)
+		assert.Len(t, stats.Agents, 1)
+	})
+}
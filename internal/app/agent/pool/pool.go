@@ -3,8 +3,8 @@ package pool
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -12,6 +12,7 @@ import (
 	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/agent"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	orchapi "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/api/orchestrator"
 	agentRepo "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/repository/agent"
 	orchestratorRepo "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/repository/orchestrator"
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
@@ -19,17 +20,47 @@ import (
 	"go.uber.org/zap"
 )
 
+// maxOperationTime ограничивает время выполнения одной операции сверху,
+// чтобы опечатка в конфигурации не подвешивала воркеров надолго.
+const maxOperationTime = 10 * time.Minute
+
+const (
+	// defaultDrainTimeout ограничивает ожидание завершения операций при остановке,
+	// если у переданного контекста нет дедлайна.
+	defaultDrainTimeout = 5 * time.Second
+	// drainPollInterval - период проверки нагрузки агентов во время остановки.
+	drainPollInterval = 50 * time.Millisecond
+	// requeueTimeout ограничивает возврат недовыполненных операций в статус PENDING.
+	requeueTimeout = 5 * time.Second
+	// workerCapacity - емкость агента, с которой запускается каждый воркер пула.
+	workerCapacity = 3
+)
+
 // AgentPool управляет пулом агентов-воркеров для выполнения вычислительных операций.
 type AgentPool struct {
-	workers        map[string]*worker.Worker            // карта активных воркеров
-	storage        agentRepo.AgentStorage               // хранилище агентов
-	operationTimes map[string]time.Duration             // время выполнения различных операций
-	operationRepo  orchestratorRepo.OperationRepository // репозиторий операций
-	capacity       int                                  // максимальное количество агентов
-	mu             sync.RWMutex                         // мьютекс для безопасного доступа к полям
-	ctx            context.Context                      // контекст для отмены операций
-	cancel         context.CancelFunc                   // функция для отмены контекста
-	running        bool                                 // флаг работы пула
+	workers        map[string]*worker.Worker              // карта активных воркеров
+	remotes        map[string]*remoteAgent                // агенты, работающие в отдельных процессах
+	storage        agentRepo.AgentStorage                 // хранилище агентов
+	operationTimes map[string]time.Duration               // время выполнения различных операций
+	operationRepo  orchestratorRepo.OperationRepository   // репозиторий операций
+	capacity       int                                    // максимальное количество агентов
+	mu             sync.RWMutex                           // мьютекс для безопасного доступа к полям
+	ctx            context.Context                        // контекст для отмены операций
+	cancel         context.CancelFunc                     // функция для отмены контекста
+	running        bool                                   // флаг работы пула
+	draining       bool                                   // пул останавливается и не принимает новые операции
+	cancellation   orchapi.OperationCancellation          // реестр отмененных операций
+	deterministic  bool                                   // выполнять операции без имитации задержки
+	operationCosts map[string]int                         // стоимость операций в единицах емкости агента
+	timeouts       map[string]time.Duration               // срок выполнения операций по типам
+	arithmetic     orchestrator.Arithmetic                // представление чисел при вычислениях
+	counters       worker.Counters                        // счетчики операций всего пула
+	statusBatcher  *worker.StatusBatcher                  // пакетная запись статусов операций (может быть nil)
+	statusInterval time.Duration                          // интервал записи пакета статусов
+	heartbeat      time.Duration                          // интервал сигналов о работе агентов
+	heartbeatTTL   time.Duration                          // время без сигналов до вытеснения агента (0 - не вытеснять)
+	autoscale      AutoscaleConfig                        // границы и пороги автомасштабирования
+	backlog        func(ctx context.Context) (int, error) // размер очереди ожидающих операций
 }
 
 // NewAgentPool создает новый пул агентов с заданными параметрами.
@@ -51,21 +82,147 @@ func NewAgentPool(storage agentRepo.AgentStorage, operationRepo orchestratorRepo
 			"subtraction":    1 * time.Second,
 			"multiplication": 2 * time.Second,
 			"division":       2 * time.Second,
+			"modulo":         2 * time.Second,
+			"factorial":      2 * time.Second,
 		}
 	}
+	if err := validateOperationTimes(operationTimes); err != nil {
+		return nil, err
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 	return &AgentPool{
 		workers:        make(map[string]*worker.Worker),
+		remotes:        make(map[string]*remoteAgent),
 		storage:        storage,
 		operationRepo:  operationRepo,
 		operationTimes: operationTimes,
 		capacity:       capacity,
+		arithmetic:     orchestrator.FloatArithmetic,
 		ctx:            ctx,
 		cancel:         cancel,
+		backlog: func(ctx context.Context) (int, error) {
+			return operationRepo.CountByStatus(ctx, orchestrator.OperationStatusPending)
+		},
 	}, nil
 }
 
+// validateOperationTimes проверяет, что время каждой операции положительно и не превышает maxOperationTime.
+func validateOperationTimes(operationTimes map[string]time.Duration) error {
+	for operation, duration := range operationTimes {
+		if duration <= 0 || duration > maxOperationTime {
+			return fmt.Errorf("%w: %s=%s (must be in (0, %s])",
+				domainerrors.ErrInvalidOperationTime, operation, duration, maxOperationTime)
+		}
+	}
+	return nil
+}
+
+// SetDeterministic включает или выключает детерминированный режим для всех текущих и будущих воркеров.
+func (p *AgentPool) SetDeterministic(deterministic bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.deterministic = deterministic
+	for _, w := range p.workers {
+		w.SetDeterministic(deterministic)
+	}
+}
+
+// SetOperationCosts задает стоимость операций в единицах емкости агента
+// для текущих и будущих воркеров. Стоимость больше емкости агента отклоняется:
+// такую операцию не принял бы ни один агент.
+func (p *AgentPool) SetOperationCosts(costs map[string]int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := validateOperationCosts(costs, workerCapacity); err != nil {
+		return err
+	}
+
+	p.operationCosts = costs
+	for _, w := range p.workers {
+		w.SetOperationCosts(costs)
+	}
+	return nil
+}
+
+// validateOperationCosts проверяет, что стоимость каждой операции не превышает емкость агента.
+func validateOperationCosts(costs map[string]int, capacity int) error {
+	for operation, cost := range costs {
+		if cost > capacity {
+			return fmt.Errorf("%w: %s costs %d, agent capacity is %d",
+				domainerrors.ErrInvalidCapacity, operation, cost, capacity)
+		}
+	}
+	return nil
+}
+
+// SetOperationTimeouts задает срок выполнения операций по типам для текущих и будущих воркеров.
+func (p *AgentPool) SetOperationTimeouts(timeouts map[string]time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.timeouts = timeouts
+	for _, w := range p.workers {
+		w.SetOperationTimeouts(timeouts)
+	}
+}
+
+// SetArithmetic задает представление чисел при вычислениях для текущих и будущих воркеров.
+func (p *AgentPool) SetArithmetic(arithmetic orchestrator.Arithmetic) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.arithmetic = arithmetic
+	for _, w := range p.workers {
+		w.SetArithmetic(arithmetic)
+	}
+}
+
+// SetCancellation задает реестр отмененных операций для всех текущих и будущих воркеров.
+func (p *AgentPool) SetCancellation(cancellation orchapi.OperationCancellation) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.cancellation = cancellation
+	for _, w := range p.workers {
+		w.SetCancellation(cancellation)
+	}
+}
+
+// SetStatusBatching включает пакетную запись статусов выполненных операций: статусы всех
+// агентов пула накапливаются и записываются одним запросом каждые interval или при накоплении
+// maxBatch статусов. Неположительный interval возвращает запись статуса сразу после операции.
+// Вызывается до Start.
+func (p *AgentPool) SetStatusBatching(interval time.Duration, maxBatch int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if interval <= 0 {
+		p.statusBatcher = nil
+		p.statusInterval = 0
+		return
+	}
+	p.statusBatcher = worker.NewStatusBatcher(p.operationRepo, maxBatch)
+	p.statusInterval = interval
+}
+
+// SetHeartbeat включает сигналы о работе агентов с интервалом interval и вытеснение агентов,
+// от которых не было сигнала дольше timeout: агент переводится в offline, перестает получать
+// операции, а операции из его очереди возвращаются в статус PENDING. Неположительные значения
+// отключают сигналы и вытеснение. Вызывается до Start.
+func (p *AgentPool) SetHeartbeat(interval, timeout time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if interval <= 0 || timeout <= 0 {
+		p.heartbeat, p.heartbeatTTL = 0, 0
+		return
+	}
+	p.heartbeat, p.heartbeatTTL = interval, timeout
+}
+
 // Start запускает пул агентов с использованием переданного контекста.
 func (p *AgentPool) Start(parentCtx context.Context) { //nolint:contextcheck
 	if parentCtx == nil {
@@ -82,95 +239,222 @@ func (p *AgentPool) Start(parentCtx context.Context) { //nolint:contextcheck
 		return
 	}
 	p.running = true
+	// После Stop контекст пула отменен, повторный запуск получает новый
+	if p.ctx.Err() != nil {
+		p.ctx, p.cancel = context.WithCancel(context.Background())
+	}
 	p.mu.Unlock()
 
 	// Создаем и запускаем воркеров.
 	for i := range p.capacity {
-		agentID := fmt.Sprintf("agent-%s-%d", uuid.New().String()[:8], i)
-		w, err := worker.NewWorker(agentID, 3, p.operationTimes, p.operationRepo)
-		if err != nil {
-			log.Error("Failed to create worker", zap.String("agent_id", agentID), zap.Error(err))
-			continue
-		}
-
-		p.mu.Lock()
-		p.workers[agentID] = w
-		p.mu.Unlock()
-
-		w.Start(parentCtx)
-
-		// Регистрируем агента в хранилище.
-		agentStatus := w.GetStatus()
-		if agentStatus == nil {
-			log.Error("Failed to get agent status, using default values", zap.String("agent_id", agentID))
-			agentStatus = &agent.Agent{
-				ID:          agentID,
-				Status:      agent.AgentStatusOnline,
-				MaxCapacity: 3,
-			}
-		}
-		p.storage.Add(agentStatus)
-		log.Info("Started agent worker", zap.String("agent_id", agentID), zap.Int("capacity", agentStatus.MaxCapacity), zap.String("status", string(agentStatus.Status)))
+		p.startWorker(parentCtx, fmt.Sprintf("agent-%s-%d", uuid.New().String()[:8], i))
 	}
 
 	// Запускаем фоновое обновление статусов.
 	go p.updateAgentStatuses(parentCtx)
+	if p.autoscale.enabled() {
+		go p.runAutoscaler(parentCtx)
+	}
+	if p.statusBatcher != nil {
+		// Запись статусов останавливается вместе с пулом, но пишет в журнал из parentCtx
+		batchCtx, cancel := context.WithCancel(parentCtx)
+		context.AfterFunc(p.ctx, cancel)
+		go p.statusBatcher.Run(batchCtx, p.statusInterval)
+	}
 	log.Info("Agent pool started successfully", zap.Int("worker_count", p.capacity), zap.Int("operation_types", len(p.operationTimes)))
 }
 
-// Stop останавливает пул агентов и освобождает ресурсы.
-func (p *AgentPool) Stop(ctx context.Context) {
+// startWorker создает воркера с настройками пула, запускает его и регистрирует агента
+// в хранилище. Возвращает false, если воркера создать не удалось.
+func (p *AgentPool) startWorker(ctx context.Context, agentID string) bool {
 	log := logger.ContextLogger(ctx, nil)
+
+	w, err := worker.NewWorker(agentID, workerCapacity, p.operationTimes, p.operationRepo)
+	if err != nil {
+		log.Error("Failed to create worker", zap.String("agent_id", agentID), zap.Error(err))
+		return false
+	}
+
 	p.mu.Lock()
-	defer p.mu.Unlock()
+	w.SetCancellation(p.cancellation)
+	w.SetDeterministic(p.deterministic)
+	w.SetOperationCosts(p.operationCosts)
+	w.SetOperationTimeouts(p.timeouts)
+	w.SetArithmetic(p.arithmetic)
+	w.SetCounters(&p.counters)
+	w.SetStatusBatcher(p.statusBatcher)
+	w.SetHeartbeatInterval(p.heartbeat)
+	p.workers[agentID] = w
+	// Выполнение операций прерывается как отменой ctx, так и остановкой пула
+	workerCtx, cancel := context.WithCancel(ctx)
+	context.AfterFunc(p.ctx, cancel)
+	p.mu.Unlock()
 
-	if !p.running {
+	w.Start(workerCtx)
+
+	// Регистрируем агента в хранилище.
+	agentStatus := w.GetStatus()
+	if agentStatus == nil {
+		log.Error("Failed to get agent status, using default values", zap.String("agent_id", agentID))
+		agentStatus = &agent.Agent{
+			ID:          agentID,
+			Status:      agent.AgentStatusOnline,
+			MaxCapacity: 3,
+		}
+	}
+	p.storage.Add(agentStatus)
+	log.Info("Started agent worker", zap.String("agent_id", agentID), zap.Int("capacity", agentStatus.MaxCapacity), zap.String("status", string(agentStatus.Status)))
+	return true
+}
+
+// Stop плавно останавливает пул агентов: новые операции перестают приниматься,
+// пул ждет, пока нагрузка каждого агента не упадет до нуля, и переводит агентов в offline.
+// Ожидание ограничено дедлайном ctx (defaultDrainTimeout, если дедлайн не задан).
+// Операции, которые к этому моменту остались в очередях агентов или еще выполнялись,
+// возвращаются в статус PENDING. Обращения к базе выполняются без блокировки пула.
+func (p *AgentPool) Stop(ctx context.Context) {
+	log := logger.ContextLogger(ctx, nil)
+
+	p.mu.Lock()
+	if !p.running || p.draining {
+		p.mu.Unlock()
 		log.Debug("Agent pool is already stopped")
 		return
 	}
+	p.draining = true
+	workers := make([]*worker.Worker, 0, len(p.workers))
+	for _, w := range p.workers {
+		if w != nil {
+			workers = append(workers, w)
+		}
+	}
+	p.mu.Unlock()
 
-	log.Info("Stopping agent pool")
-	stopCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel()
+	log.Info("Draining agent pool", zap.Int("worker_count", len(workers)))
+
+	drainCtx := ctx
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		drainCtx, cancel = context.WithTimeout(ctx, defaultDrainTimeout)
+		defer cancel()
+	}
 
+	if err := waitForDrain(drainCtx, workers); err != nil {
+		log.Warn("Agent pool drain interrupted, requeueing queued operations", zap.Error(err))
+	}
+
+	// Отменяем выполнение и забираем агентов из пула под блокировкой, а останавливаем их
+	// и возвращаем операции в очередь уже без нее, чтобы запись в базу не блокировала пул.
+	p.mu.Lock()
 	if p.cancel != nil {
 		p.cancel()
 	}
+	stopping := p.workers
+	remotes := p.remotes
+	var remoteOps []*orchestrator.Operation
+	for _, remote := range remotes {
+		remoteOps = append(remoteOps, remote.operations()...)
+	}
+	p.workers = make(map[string]*worker.Worker)
+	p.remotes = make(map[string]*remoteAgent)
+	batcher := p.statusBatcher
+	p.mu.Unlock()
 
-	// Останавливаем всех воркеров и удаляем из хранилища.
-	var stopErrors []error
-	for id, w := range p.workers {
+	// Дедлайн ctx к этому моменту мог истечь, а вернуть операции в очередь нужно в любом случае.
+	requeueCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), requeueTimeout)
+	defer cancel()
+
+	// Останавливаем всех воркеров. Прерванные операции воркер сам возвращает в PENDING,
+	// поэтому очереди забираются после завершения его цикла обработки.
+	requeued := 0
+	for _, w := range stopping {
 		if w != nil {
 			w.Stop()
-			if err := p.storage.Remove(id); err != nil {
-				stopErrors = append(stopErrors, fmt.Errorf("failed to remove agent %s: %w", id, err))
-				log.Warn("Failed to remove agent from storage", zap.String("agent_id", id), zap.Error(err))
-			} else {
-				log.Debug("Agent removed successfully", zap.String("agent_id", id))
-			}
 		}
 	}
+	for id, w := range stopping {
+		if w == nil {
+			continue
+		}
 
-	p.workers = make(map[string]*worker.Worker)
+		if err := w.Wait(requeueCtx); err != nil {
+			log.Warn("Agent worker did not stop in time", zap.String("agent_id", id), zap.Error(err))
+		}
+		requeued += w.RequeueQueued(requeueCtx)
+	}
+
+	// Операции агентов в отдельных процессах возвращаются в очередь целиком: их результаты
+	// после остановки пула уже не принимаются.
+	requeued += p.requeueOperations(requeueCtx, remoteOps)
+
+	// Статусы операций, выполненных до остановки, записываются до удаления агентов
+	if batcher != nil {
+		batcher.Flush(requeueCtx)
+	}
+
+	// Удаляем агентов из хранилища.
+	var stopErrors []error
+	for id, w := range stopping {
+		if w == nil {
+			continue
+		}
+
+		if err := p.storage.Remove(id); err != nil {
+			stopErrors = append(stopErrors, fmt.Errorf("failed to remove agent %s: %w", id, err))
+			log.Warn("Failed to remove agent from storage", zap.String("agent_id", id), zap.Error(err))
+		} else {
+			log.Debug("Agent removed successfully", zap.String("agent_id", id))
+		}
+	}
+
+	for id := range remotes {
+		if err := p.storage.Remove(id); err != nil {
+			stopErrors = append(stopErrors, fmt.Errorf("failed to remove agent %s: %w", id, err))
+			log.Warn("Failed to remove agent from storage", zap.String("agent_id", id), zap.Error(err))
+		}
+	}
+
+	p.mu.Lock()
 	p.running = false
+	p.draining = false
+	p.mu.Unlock()
 
 	// Логируем результат остановки.
 	if len(stopErrors) > 0 {
-		log.Warn("Agent pool stopped with errors", zap.Int("error_count", len(stopErrors)), zap.Error(fmt.Errorf("first error: %w", stopErrors[0])))
+		log.Warn("Agent pool stopped with errors", zap.Int("error_count", len(stopErrors)), zap.Int("requeued", requeued), zap.Error(fmt.Errorf("first error: %w", stopErrors[0])))
 	} else {
-		log.Info("Agent pool stopped successfully")
+		log.Info("Agent pool stopped successfully", zap.Int("requeued", requeued))
 	}
+}
+
+// waitForDrain ждет, пока нагрузка всех воркеров не станет нулевой, либо завершения ctx.
+func waitForDrain(ctx context.Context, workers []*worker.Worker) error {
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
 
-	// Ждем завершения очистки ресурсов.
-	select {
-	case <-stopCtx.Done():
-		if errors.Is(stopCtx.Err(), context.DeadlineExceeded) {
-			log.Warn("Timeout waiting for all agent pool resources to clean up")
+	for {
+		if isDrained(workers) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("wait for agents to drain: %w", ctx.Err())
+		case <-ticker.C:
 		}
-	case <-time.After(100 * time.Millisecond):
 	}
 }
 
+// isDrained сообщает, что ни один воркер не выполняет и не держит в очереди операций.
+func isDrained(workers []*worker.Worker) bool {
+	for _, w := range workers {
+		if w.CurrentLoad() > 0 {
+			return false
+		}
+	}
+	return true
+}
+
 // GetAvailableAgent возвращает агента с наименьшей текущей нагрузкой для выполнения операции.
 func (p *AgentPool) GetAvailableAgent(operationType int) (*agent.Agent, error) {
 	p.mu.RLock()
@@ -180,48 +464,54 @@ func (p *AgentPool) GetAvailableAgent(operationType int) (*agent.Agent, error) {
 		return nil, domainerrors.ErrPoolNotRunning
 	}
 
-	if len(p.workers) == 0 {
+	if p.draining {
+		return nil, domainerrors.ErrPoolDraining
+	}
+
+	if len(p.workers)+len(p.remotes) == 0 {
 		return nil, domainerrors.ErrNoAgentsAvailable
 	}
 
-	// Ищем воркера с наименьшей нагрузкой.
-	var bestWorker *worker.Worker
-	var lowestLoad = -1
+	// Ищем агента с наименьшей нагрузкой, у которого хватает емкости на операцию этого типа.
+	operationName := orchestrator.OperationType(operationType).Name()
+	var best *agent.Agent
 	for _, w := range p.workers {
 		if w == nil || !w.IsRunning() {
 			continue
 		}
 
-		load := w.CurrentLoad()
 		status := w.GetStatus()
 		if status == nil {
 			continue
 		}
 
-		if load >= status.MaxCapacity {
+		if status.RemainingCapacityFor(operationName) <= 0 {
 			continue
 		}
 
-		if lowestLoad == -1 || load < lowestLoad {
-			bestWorker = w
-			lowestLoad = load
+		if best == nil || status.CurrentLoad < best.CurrentLoad {
+			best = status
 		}
 	}
+	for _, remote := range p.remotes {
+		if remote.agent.RemainingCapacityFor(operationName) <= 0 {
+			continue
+		}
 
-	if bestWorker == nil {
-		return nil, fmt.Errorf("%w: no workers available", domainerrors.ErrNoAgentsAvailable)
+		if best == nil || remote.agent.CurrentLoad < best.CurrentLoad {
+			best = remote.snapshot()
+		}
 	}
 
-	status := bestWorker.GetStatus()
-	if status == nil {
-		return nil, fmt.Errorf("%w: worker returned nil status", domainerrors.ErrNoAgentsAvailable)
+	if best == nil {
+		return nil, fmt.Errorf("%w: no workers available", domainerrors.ErrNoAgentsAvailable)
 	}
 
-	return status, nil
+	return best, nil
 }
 
 // AssignOperation назначает операцию агенту с указанным ID.
-func (p *AgentPool) AssignOperation(agentID string, operation *orchestrator.Operation) error {
+func (p *AgentPool) AssignOperation(ctx context.Context, agentID string, operation *orchestrator.Operation) error {
 	if operation == nil {
 		return domainerrors.ErrNilOperation
 	}
@@ -238,8 +528,18 @@ func (p *AgentPool) AssignOperation(agentID string, operation *orchestrator.Oper
 	// Находим воркера по ID.
 	p.mu.RLock()
 	w, exists := p.workers[agentID]
+	_, remote := p.remotes[agentID]
+	draining := p.draining
 	p.mu.RUnlock()
 
+	if draining {
+		return fmt.Errorf("%w: agent %s", domainerrors.ErrPoolDraining, agentID)
+	}
+
+	if remote {
+		return p.assignRemote(ctx, agentID, operation)
+	}
+
 	if !exists || w == nil {
 		return fmt.Errorf("%w: agent %s", domainerrors.ErrAgentNotFound, agentID)
 	}
@@ -248,18 +548,19 @@ func (p *AgentPool) AssignOperation(agentID string, operation *orchestrator.Oper
 		return fmt.Errorf("%w: agent %s is not running", domainerrors.ErrOperationAssignment, agentID)
 	}
 
-	log := logger.ContextLogger(context.Background(), nil).With(
+	log := logger.GetZapLogger(logger.ContextLogger(ctx, nil)).With(
 		zap.String("operation_id", operation.ID.String()),
 		zap.String("agent_id", agentID),
 	)
 	log.Info("Assigning operation to agent")
 
 	// Выполняем операцию.
-	_, err := w.PerformOperation(operation)
+	_, err := w.PerformOperation(ctx, operation)
 	if err != nil {
 		log.Error("Failed to assign operation to agent", zap.Error(err))
 		return fmt.Errorf("%w: %w", domainerrors.ErrOperationAssignment, err)
 	}
+	p.counters.AddDispatched()
 
 	return nil
 }
@@ -272,6 +573,10 @@ func (p *AgentPool) GetAgentStatus(agentID string) (*agent.Agent, error) {
 
 	p.mu.RLock()
 	w, exists := p.workers[agentID]
+	if remote, ok := p.remotes[agentID]; ok {
+		defer p.mu.RUnlock()
+		return remote.snapshot(), nil
+	}
 	p.mu.RUnlock()
 
 	if !exists || w == nil {
@@ -286,6 +591,41 @@ func (p *AgentPool) GetAgentStatus(agentID string) (*agent.Agent, error) {
 	return status, nil
 }
 
+// SetAgentCapacity меняет емкость работающего агента и сразу отражает ее в хранилище,
+// чтобы выбор агента для следующих операций учитывал новое значение.
+func (p *AgentPool) SetAgentCapacity(agentID string, capacity int) (*agent.AgentStats, error) {
+	p.mu.RLock()
+	w, exists := p.workers[agentID]
+	p.mu.RUnlock()
+
+	if !exists || w == nil {
+		return nil, fmt.Errorf("%w: agent %s", domainerrors.ErrAgentNotFound, agentID)
+	}
+
+	if err := w.SetCapacity(capacity); err != nil {
+		return nil, fmt.Errorf("agent %s: %w", agentID, err)
+	}
+
+	status := w.GetStatus()
+	if status == nil {
+		return nil, fmt.Errorf("%w: agent %s", domainerrors.ErrNilWorkerStatus, agentID)
+	}
+
+	if err := p.storage.UpdateStatus(agentID, status.Status, status.CurrentLoad, status.MaxCapacity); err != nil {
+		return nil, fmt.Errorf("updating agent %s in storage: %w", agentID, err)
+	}
+
+	return &agent.AgentStats{
+		ID:          status.ID,
+		Status:      status.Status,
+		CurrentLoad: status.CurrentLoad,
+		MaxCapacity: status.MaxCapacity,
+		Completed:   status.OperationsStats.Completed,
+		Failed:      status.OperationsStats.Failed,
+		QueueDepth:  w.QueueDepth(),
+	}, nil
+}
+
 // ListAgents возвращает список всех агентов.
 func (p *AgentPool) ListAgents() ([]*agent.Agent, error) {
 	agents := p.storage.List()
@@ -295,6 +635,78 @@ func (p *AgentPool) ListAgents() ([]*agent.Agent, error) {
 	return agents, nil
 }
 
+// GetPoolStats возвращает нагрузку, статус и счетчики операций каждого агента
+// вместе с агрегированными значениями по пулу. Для агентов с активным воркером
+// используются актуальные данные воркера, а не последний снимок из хранилища.
+func (p *AgentPool) GetPoolStats() (*agent.PoolStats, error) {
+	agents := p.storage.List()
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	stats := &agent.PoolStats{Agents: make([]agent.AgentStats, 0, len(agents))}
+	for _, a := range agents {
+		if a == nil {
+			continue
+		}
+
+		queueDepth := 0
+		if w, exists := p.workers[a.ID]; exists && w != nil {
+			if status := w.GetStatus(); status != nil {
+				a = status
+			}
+			queueDepth = w.QueueDepth()
+		} else if remote, exists := p.remotes[a.ID]; exists {
+			a = remote.snapshot()
+			queueDepth = len(remote.queued)
+		}
+
+		stats.Add(a, queueDepth)
+	}
+
+	sort.Slice(stats.Agents, func(i, j int) bool {
+		return stats.Agents[i].ID < stats.Agents[j].ID
+	})
+	stats.Throughput = p.PoolStats()
+	stats.Workers = len(p.workers)
+
+	return stats, nil
+}
+
+// GetTimingProfile возвращает настроенное время выполнения операций и фактические
+// значения, применяемые каждым воркером с учетом детерминированного режима.
+// Агенты отсортированы по идентификатору.
+func (p *AgentPool) GetTimingProfile() (*agent.TimingProfile, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	profile := &agent.TimingProfile{
+		ConfiguredMs: make(map[string]int64, len(p.operationTimes)),
+		Agents:       make([]agent.AgentTiming, 0, len(p.workers)),
+	}
+	for operation, duration := range p.operationTimes {
+		profile.ConfiguredMs[operation] = duration.Milliseconds()
+	}
+	for _, w := range p.workers {
+		if w == nil {
+			continue
+		}
+		profile.Agents = append(profile.Agents, w.Timing())
+	}
+
+	sort.Slice(profile.Agents, func(i, j int) bool {
+		return profile.Agents[i].ID < profile.Agents[j].ID
+	})
+
+	return profile, nil
+}
+
+// PoolStats возвращает счетчики переданных агентам, выполненных и завершившихся ошибкой
+// операций всего пула. Счетчики читаются атомарно, без блокировки пула.
+func (p *AgentPool) PoolStats() agent.Throughput {
+	return p.counters.Snapshot()
+}
+
 // IsRunning возвращает состояние пула агентов (запущен или нет).
 func (p *AgentPool) IsRunning() bool {
 	p.mu.RLock()
@@ -351,8 +763,80 @@ func (p *AgentPool) updateAgentStatuses(ctx context.Context) {
 					if err := p.storage.UpdateStatus(id, status.Status, status.CurrentLoad, status.MaxCapacity); err != nil {
 						log.Warn("Failed to update agent status", zap.String("agent_id", id), zap.Error(err))
 					}
+					if !status.LastHeartbeat.IsZero() {
+						if err := p.storage.Heartbeat(id, status.LastHeartbeat); err != nil {
+							log.Warn("Failed to record agent heartbeat", zap.String("agent_id", id), zap.Error(err))
+						}
+					}
+				}
+
+				// Сигналы агентов в отдельных процессах записываются при получении операций
+				for id, remote := range p.remotes {
+					status := remote.snapshot()
+					if err := p.storage.UpdateStatus(id, status.Status, status.CurrentLoad, status.MaxCapacity); err != nil {
+						log.Warn("Failed to update agent status", zap.String("agent_id", id), zap.Error(err))
+					}
 				}
 			}()
+
+			p.evictStaleAgents(ctx)
+		}
+	}
+}
+
+// evictStaleAgents вытесняет агентов, от которых не было сигнала дольше heartbeatTTL:
+// хранилище переводит их в offline, пул останавливает их воркеров и возвращает операции
+// из их очередей в статус PENDING, чтобы их выполнили другие агенты.
+func (p *AgentPool) evictStaleAgents(ctx context.Context) {
+	p.mu.RLock()
+	timeout := p.heartbeatTTL
+	active := p.running && !p.draining
+	p.mu.RUnlock()
+
+	if timeout <= 0 || !active {
+		return
+	}
+
+	evicted := p.storage.EvictStale(timeout, time.Now())
+	if len(evicted) == 0 {
+		return
+	}
+
+	log := logger.ContextLogger(ctx, nil)
+
+	p.mu.Lock()
+	workers := make(map[string]*worker.Worker, len(evicted))
+	remotes := make(map[string]*remoteAgent)
+	for _, id := range evicted {
+		if w, exists := p.workers[id]; exists && w != nil {
+			workers[id] = w
+			delete(p.workers, id)
+		}
+		if remote, exists := p.remotes[id]; exists {
+			remotes[id] = remote
+			delete(p.remotes, id)
 		}
 	}
+	p.mu.Unlock()
+
+	requeueCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), requeueTimeout)
+	defer cancel()
+
+	for id, w := range workers {
+		w.Stop()
+		requeued := w.RequeueQueued(requeueCtx)
+		log.Warn("Evicted agent without heartbeat",
+			zap.String("agent_id", id),
+			zap.Duration("timeout", timeout),
+			zap.Int("requeued", requeued))
+	}
+
+	// Вытесненный агент в отдельном процессе должен зарегистрироваться заново
+	for id, remote := range remotes {
+		requeued := p.requeueOperations(requeueCtx, remote.operations())
+		log.Warn("Evicted remote agent without heartbeat",
+			zap.String("agent_id", id),
+			zap.Duration("timeout", timeout),
+			zap.Int("requeued", requeued))
+	}
 }
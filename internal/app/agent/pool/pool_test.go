@@ -2,15 +2,21 @@ package pool
 
 import (
 	"context"
+	"sync"
 	"testing"
 	"time"
 
+	memAgent "github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/db/memory/agent"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/app/agent/worker"
 	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/agent"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
 )
 
 type MockAgentStorage struct {
@@ -42,6 +48,19 @@ func (m *MockAgentStorage) UpdateStatus(id string, status agent.AgentStatus, loa
 	return args.Error(0)
 }
 
+func (m *MockAgentStorage) Heartbeat(id string, at time.Time) error {
+	args := m.Called(id, at)
+	return args.Error(0)
+}
+
+func (m *MockAgentStorage) EvictStale(timeout time.Duration, now time.Time) []string {
+	args := m.Called(timeout, now)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).([]string)
+}
+
 func (m *MockAgentStorage) UpdateStats(id string, completed bool, failed bool) error {
 	args := m.Called(id, completed, failed)
 	return args.Error(0)
@@ -77,6 +96,14 @@ func (m *MockOperationRepository) CreateBatch(ctx context.Context, operations []
 	return args.Error(0)
 }
 
+func (m *MockOperationRepository) CreateBatchPartial(ctx context.Context, operations []*orchestrator.Operation) ([]orchestrator.OperationInsertResult, error) {
+	args := m.Called(ctx, operations)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]orchestrator.OperationInsertResult), args.Error(1)
+}
+
 func (m *MockOperationRepository) FindByID(ctx context.Context, id uuid.UUID) (*orchestrator.Operation, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -111,11 +138,31 @@ func (m *MockOperationRepository) UpdateStatus(ctx context.Context, id uuid.UUID
 	return args.Error(0)
 }
 
+func (m *MockOperationRepository) UpdateStatusBatch(ctx context.Context, updates []orchestrator.OperationStatusUpdate) error {
+	args := m.Called(ctx, updates)
+	return args.Error(0)
+}
+
 func (m *MockOperationRepository) AssignAgent(ctx context.Context, operationID uuid.UUID, agentID string) error {
 	args := m.Called(ctx, operationID, agentID)
 	return args.Error(0)
 }
 
+func (m *MockOperationRepository) CountByStatus(ctx context.Context, status orchestrator.OperationStatus) (int, error) {
+	args := m.Called(ctx, status)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockOperationRepository) CountPendingByUserID(ctx context.Context, userID uuid.UUID) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockOperationRepository) DeleteByCalculationID(ctx context.Context, calculationID uuid.UUID) (int, error) {
+	args := m.Called(ctx, calculationID)
+	return args.Int(0), args.Error(1)
+}
+
 type MockWorker struct {
 	mock.Mock
 }
@@ -232,8 +279,37 @@ func TestNewAgentPool(t *testing.T) {
 		assert.NoError(t, err)
 		assert.NotNil(t, pool)
 		assert.NotNil(t, pool.operationTimes)
-		assert.Len(t, pool.operationTimes, 4)
+		assert.Len(t, pool.operationTimes, 6)
 	})
+
+	invalidTimes := []struct {
+		name     string
+		duration time.Duration
+	}{
+		{name: "Zero operation time", duration: 0},
+		{name: "Negative operation time", duration: -time.Second},
+		{name: "Operation time above maximum", duration: maxOperationTime + time.Second},
+	}
+
+	for _, tc := range invalidTimes {
+		t.Run(tc.name, func(t *testing.T) {
+			storage := new(MockAgentStorage)
+			operationRepo := new(MockOperationRepository)
+			operationTimes := map[string]time.Duration{
+				"addition":       1 * time.Second,
+				"subtraction":    1 * time.Second,
+				"multiplication": tc.duration,
+				"division":       2 * time.Second,
+			}
+
+			pool, err := NewAgentPool(storage, operationRepo, operationTimes, 5)
+
+			assert.Error(t, err)
+			assert.Nil(t, pool)
+			assert.ErrorIs(t, err, domainerrors.ErrInvalidOperationTime)
+			assert.Contains(t, err.Error(), "multiplication")
+		})
+	}
 }
 
 func TestGetAvailableAgent(t *testing.T) {
@@ -270,7 +346,7 @@ func TestAssignOperation(t *testing.T) {
 		operationRepo := new(MockOperationRepository)
 		pool, _ := NewAgentPool(storage, operationRepo, nil, 5)
 
-		err := pool.AssignOperation("agent1", nil)
+		err := pool.AssignOperation(context.Background(), "agent1", nil)
 
 		assert.Error(t, err)
 		assert.ErrorIs(t, err, domainerrors.ErrNilOperation)
@@ -282,7 +358,7 @@ func TestAssignOperation(t *testing.T) {
 		pool, _ := NewAgentPool(storage, operationRepo, nil, 5)
 
 		operation := &orchestrator.Operation{ID: uuid.New()}
-		err := pool.AssignOperation("", operation)
+		err := pool.AssignOperation(context.Background(), "", operation)
 
 		assert.Error(t, err)
 		assert.ErrorContains(t, err, "empty agent ID")
@@ -294,7 +370,7 @@ func TestAssignOperation(t *testing.T) {
 		pool, _ := NewAgentPool(storage, operationRepo, nil, 5)
 
 		operation := &orchestrator.Operation{ID: uuid.New()}
-		err := pool.AssignOperation("non-existent-agent", operation)
+		err := pool.AssignOperation(context.Background(), "non-existent-agent", operation)
 
 		assert.Error(t, err)
 		assert.ErrorContains(t, err, "agent not found")
@@ -397,3 +473,451 @@ func TestHelperMethods(t *testing.T) {
 		assert.Equal(t, 5, pool.GetCapacity())
 	})
 }
+
+func TestGetPoolStats(t *testing.T) {
+	t.Run("Aggregates agents from storage", func(t *testing.T) {
+		storage := memAgent.NewAgentStorage()
+		operationRepo := new(MockOperationRepository)
+
+		storage.Add(&agent.Agent{
+			ID: "agent-b", Status: agent.AgentStatusBusy, CurrentLoad: 3, MaxCapacity: 3,
+			OperationsStats: agent.OperationsStats{Completed: 10, Failed: 2, Total: 12},
+		})
+		storage.Add(&agent.Agent{
+			ID: "agent-a", Status: agent.AgentStatusOnline, CurrentLoad: 1, MaxCapacity: 3,
+			OperationsStats: agent.OperationsStats{Completed: 5, Failed: 1, Total: 6},
+		})
+		storage.Add(&agent.Agent{
+			ID: "agent-c", Status: agent.AgentStatusOffline, MaxCapacity: 2,
+			OperationsStats: agent.OperationsStats{Completed: 1, Total: 1},
+		})
+
+		pool, _ := NewAgentPool(storage, operationRepo, nil, 3)
+
+		stats, err := pool.GetPoolStats()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, stats.TotalAgents)
+		assert.Equal(t, 1, stats.OnlineAgents)
+		assert.Equal(t, 1, stats.BusyAgents)
+		assert.Equal(t, 4, stats.TotalLoad)
+		assert.Equal(t, 8, stats.TotalCapacity)
+		assert.Equal(t, int64(16), stats.Completed)
+		assert.Equal(t, int64(3), stats.Failed)
+		assert.Zero(t, stats.QueueDepth)
+
+		assert.Len(t, stats.Agents, 3)
+		assert.Equal(t, "agent-a", stats.Agents[0].ID)
+		assert.Equal(t, "agent-b", stats.Agents[1].ID)
+		assert.Equal(t, "agent-c", stats.Agents[2].ID)
+		assert.Equal(t, agent.AgentStatusBusy, stats.Agents[1].Status)
+		assert.Equal(t, int64(2), stats.Agents[1].Failed)
+	})
+
+	t.Run("Empty storage", func(t *testing.T) {
+		pool, _ := NewAgentPool(memAgent.NewAgentStorage(), new(MockOperationRepository), nil, 3)
+
+		stats, err := pool.GetPoolStats()
+
+		assert.NoError(t, err)
+		assert.NotNil(t, stats.Agents)
+		assert.Empty(t, stats.Agents)
+		assert.Zero(t, stats.TotalAgents)
+	})
+
+	t.Run("Prefers live worker status over stored snapshot", func(t *testing.T) {
+		storage := memAgent.NewAgentStorage()
+		operationRepo := new(MockOperationRepository)
+
+		storage.Add(&agent.Agent{
+			ID: "agent-live", Status: agent.AgentStatusBusy, CurrentLoad: 3, MaxCapacity: 3,
+		})
+		storage.Add(&agent.Agent{
+			ID: "agent-stored", Status: agent.AgentStatusOnline, CurrentLoad: 1, MaxCapacity: 3,
+		})
+
+		pool, _ := NewAgentPool(storage, operationRepo, nil, 2)
+
+		w, err := worker.NewWorker("agent-live", 4, nil, operationRepo)
+		assert.NoError(t, err)
+		pool.workers["agent-live"] = w
+
+		stats, err := pool.GetPoolStats()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, stats.TotalAgents)
+		assert.Equal(t, agent.AgentStatusOffline, stats.Agents[0].Status)
+		assert.Equal(t, 4, stats.Agents[0].MaxCapacity)
+		assert.Zero(t, stats.Agents[0].CurrentLoad)
+		assert.Equal(t, 1, stats.TotalLoad)
+		assert.Equal(t, 7, stats.TotalCapacity)
+		assert.Equal(t, 1, stats.OnlineAgents)
+		assert.Zero(t, stats.BusyAgents)
+	})
+}
+
+// expectNotCancelled разрешает воркерам проверять сохраненный статус операции
+// перед выполнением: репозиторий сообщает, что операция не отменена.
+func expectNotCancelled(repo *MockOperationRepository) {
+	repo.On("FindByID", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+}
+
+func newStartedTestPool(t *testing.T, operationRepo *MockOperationRepository, additionTime time.Duration) (context.Context, *AgentPool) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(logger.WithLogger(context.Background(), logger.New(zapcore.NewNopCore())))
+	t.Cleanup(cancel)
+
+	pool, err := NewAgentPool(memAgent.NewAgentStorage(), operationRepo, map[string]time.Duration{
+		"addition": additionTime,
+	}, 1)
+	assert.NoError(t, err)
+
+	expectNotCancelled(operationRepo)
+	pool.Start(ctx)
+	return ctx, pool
+}
+
+func assignAddition(t *testing.T, pool *AgentPool) *orchestrator.Operation {
+	t.Helper()
+
+	available, err := pool.GetAvailableAgent(int(orchestrator.OperationTypeAddition))
+	assert.NoError(t, err)
+
+	op := &orchestrator.Operation{
+		ID:            uuid.New(),
+		OperationType: orchestrator.OperationTypeAddition,
+		Operand1:      "1",
+		Operand2:      "2",
+	}
+	_, err = pool.workers[available.ID].PerformOperation(context.Background(), op)
+	assert.NoError(t, err)
+	return op
+}
+
+func TestGetAvailableAgentOperationCosts(t *testing.T) {
+	operationRepo := new(MockOperationRepository)
+	operationRepo.On("UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	_, pool := newStartedTestPool(t, operationRepo, time.Second)
+	require.NoError(t, pool.SetOperationCosts(map[string]int{"division": 3}))
+
+	// Агент емкостью 3 занят сложением: на деление стоимостью 3 места уже нет.
+	assignAddition(t, pool)
+
+	_, err := pool.GetAvailableAgent(int(orchestrator.OperationTypeDivision))
+	assert.ErrorIs(t, err, domainerrors.ErrNoAgentsAvailable)
+
+	available, err := pool.GetAvailableAgent(int(orchestrator.OperationTypeAddition))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, available.TypeCapacity["addition"])
+	assert.Equal(t, 0, available.TypeCapacity["division"])
+}
+
+func TestStopDrain(t *testing.T) {
+	t.Run("Waits for in-flight operations", func(t *testing.T) {
+		operationRepo := new(MockOperationRepository)
+		operationRepo.On("UpdateStatus", mock.Anything, mock.Anything, orchestrator.OperationStatusCompleted, mock.Anything, "").Return(nil)
+
+		ctx, pool := newStartedTestPool(t, operationRepo, 50*time.Millisecond)
+		first := assignAddition(t, pool)
+		second := assignAddition(t, pool)
+
+		stopCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+		defer cancel()
+		pool.Stop(stopCtx)
+
+		assert.False(t, pool.IsRunning())
+		assert.Zero(t, pool.GetWorkerCount())
+		operationRepo.AssertCalled(t, "UpdateStatus", mock.Anything, first.ID, orchestrator.OperationStatusCompleted, mock.Anything, "")
+		operationRepo.AssertCalled(t, "UpdateStatus", mock.Anything, second.ID, orchestrator.OperationStatusCompleted, mock.Anything, "")
+		operationRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, orchestrator.OperationStatusPending, "", "")
+	})
+
+	t.Run("Requeues queued and in-flight operations when deadline elapses", func(t *testing.T) {
+		operationRepo := new(MockOperationRepository)
+		operationRepo.On("UpdateStatus", mock.Anything, mock.Anything, orchestrator.OperationStatusPending, "", "").Return(nil)
+
+		ctx, pool := newStartedTestPool(t, operationRepo, time.Second)
+		inFlight := assignAddition(t, pool)
+		queued := []*orchestrator.Operation{assignAddition(t, pool), assignAddition(t, pool)}
+
+		stopCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+
+		startTime := time.Now()
+		pool.Stop(stopCtx)
+
+		assert.Less(t, time.Since(startTime), 500*time.Millisecond)
+		assert.False(t, pool.IsRunning())
+		for _, op := range append(queued, inFlight) {
+			operationRepo.AssertCalled(t, "UpdateStatus", mock.Anything, op.ID, orchestrator.OperationStatusPending, "", "")
+		}
+		operationRepo.AssertNumberOfCalls(t, "UpdateStatus", 3)
+	})
+
+	t.Run("Rejects new operations while draining", func(t *testing.T) {
+		pool, _ := NewAgentPool(new(MockAgentStorage), new(MockOperationRepository), nil, 1)
+		pool.running = true
+		pool.draining = true
+
+		_, err := pool.GetAvailableAgent(int(orchestrator.OperationTypeAddition))
+		assert.ErrorIs(t, err, domainerrors.ErrPoolDraining)
+
+		err = pool.AssignOperation(context.Background(), "agent-1", &orchestrator.Operation{ID: uuid.New()})
+		assert.ErrorIs(t, err, domainerrors.ErrPoolDraining)
+	})
+}
+
+func TestPoolStatsConcurrentOperations(t *testing.T) {
+	operationRepo := new(MockOperationRepository)
+	operationRepo.On("UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	ctx, cancel := context.WithCancel(logger.WithLogger(context.Background(), logger.New(zapcore.NewNopCore())))
+	t.Cleanup(cancel)
+
+	pool, err := NewAgentPool(memAgent.NewAgentStorage(), operationRepo, nil, 3)
+	require.NoError(t, err)
+	pool.SetDeterministic(true)
+	expectNotCancelled(operationRepo)
+	pool.Start(ctx)
+
+	// Каждая четвертая операция делит на ноль и завершается ошибкой.
+	const operations = 40
+	var wg sync.WaitGroup
+	for i := range operations {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			op := &orchestrator.Operation{
+				ID:            uuid.New(),
+				OperationType: orchestrator.OperationTypeAddition,
+				Operand1:      "1",
+				Operand2:      "2",
+			}
+			if i%4 == 0 {
+				op.OperationType = orchestrator.OperationTypeDivision
+				op.Operand2 = "0"
+			}
+
+			for {
+				available, err := pool.GetAvailableAgent(int(op.OperationType))
+				if err == nil && pool.AssignOperation(ctx, available.ID, op) == nil {
+					return
+				}
+				time.Sleep(time.Millisecond)
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Eventually(t, func() bool {
+		throughput := pool.PoolStats()
+		return throughput.Completed+throughput.Failed == operations
+	}, 2*time.Second, 10*time.Millisecond)
+
+	throughput := pool.PoolStats()
+	assert.EqualValues(t, operations, throughput.Dispatched)
+	assert.EqualValues(t, operations/4, throughput.Failed)
+	assert.EqualValues(t, operations-operations/4, throughput.Completed)
+
+	stats, err := pool.GetPoolStats()
+	require.NoError(t, err)
+	assert.Equal(t, throughput, stats.Throughput)
+}
+
+func TestSetAgentCapacity(t *testing.T) {
+	operationRepo := new(MockOperationRepository)
+	operationRepo.On("UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	_, pool := newStartedTestPool(t, operationRepo, time.Second)
+	agents, err := pool.ListAgents()
+	require.NoError(t, err)
+	require.Len(t, agents, 1)
+	agentID := agents[0].ID
+
+	t.Run("Capacity change takes effect", func(t *testing.T) {
+		stats, err := pool.SetAgentCapacity(agentID, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 1, stats.MaxCapacity)
+
+		stored, err := pool.storage.GetByID(agentID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, stored.MaxCapacity)
+
+		assignAddition(t, pool)
+		_, err = pool.GetAvailableAgent(int(orchestrator.OperationTypeAddition))
+		assert.ErrorIs(t, err, domainerrors.ErrNoAgentsAvailable)
+	})
+
+	t.Run("Invalid capacity", func(t *testing.T) {
+		_, err := pool.SetAgentCapacity(agentID, 0)
+		assert.ErrorIs(t, err, domainerrors.ErrInvalidCapacity)
+	})
+
+	t.Run("Capacity below operation cost", func(t *testing.T) {
+		require.NoError(t, pool.SetOperationCosts(map[string]int{"division": 2}))
+
+		_, err := pool.SetAgentCapacity(agentID, 1)
+		assert.ErrorIs(t, err, domainerrors.ErrInvalidCapacity)
+
+		stats, err := pool.SetAgentCapacity(agentID, 2)
+		require.NoError(t, err)
+		assert.Equal(t, 2, stats.MaxCapacity)
+	})
+
+	t.Run("Unknown agent", func(t *testing.T) {
+		_, err := pool.SetAgentCapacity("missing", 2)
+		assert.ErrorIs(t, err, domainerrors.ErrAgentNotFound)
+	})
+}
+
+func TestGetTimingProfile(t *testing.T) {
+	ctx, cancel := context.WithCancel(logger.WithLogger(context.Background(), logger.New(zapcore.NewNopCore())))
+	t.Cleanup(cancel)
+
+	pool, err := NewAgentPool(memAgent.NewAgentStorage(), new(MockOperationRepository), map[string]time.Duration{
+		"addition":       1500 * time.Millisecond,
+		"multiplication": 3 * time.Second,
+	}, 2)
+	require.NoError(t, err)
+	pool.Start(ctx)
+
+	agents, err := pool.ListAgents()
+	require.NoError(t, err)
+	require.Len(t, agents, 2)
+
+	configured := map[string]int64{"addition": 1500, "multiplication": 3000}
+
+	t.Run("Reports configured timings for every agent", func(t *testing.T) {
+		profile, err := pool.GetTimingProfile()
+		require.NoError(t, err)
+
+		assert.Equal(t, configured, profile.ConfiguredMs)
+		require.Len(t, profile.Agents, 2)
+		assert.Less(t, profile.Agents[0].ID, profile.Agents[1].ID)
+		for _, a := range profile.Agents {
+			assert.False(t, a.Deterministic)
+			assert.Equal(t, configured, a.OperationTimesMs)
+		}
+	})
+
+	t.Run("Reports per-agent deterministic override", func(t *testing.T) {
+		overridden := agents[0].ID
+		pool.workers[overridden].SetDeterministic(true)
+		t.Cleanup(func() { pool.workers[overridden].SetDeterministic(false) })
+
+		profile, err := pool.GetTimingProfile()
+		require.NoError(t, err)
+
+		assert.Equal(t, configured, profile.ConfiguredMs)
+		for _, a := range profile.Agents {
+			if a.ID == overridden {
+				assert.True(t, a.Deterministic)
+				assert.Equal(t, map[string]int64{"addition": 0, "multiplication": 0}, a.OperationTimesMs)
+				continue
+			}
+			assert.False(t, a.Deterministic)
+			assert.Equal(t, configured, a.OperationTimesMs)
+		}
+	})
+
+	t.Run("Reports pool-wide deterministic mode", func(t *testing.T) {
+		pool.SetDeterministic(true)
+		t.Cleanup(func() { pool.SetDeterministic(false) })
+
+		profile, err := pool.GetTimingProfile()
+		require.NoError(t, err)
+
+		assert.Equal(t, configured, profile.ConfiguredMs)
+		for _, a := range profile.Agents {
+			assert.True(t, a.Deterministic)
+			assert.Equal(t, map[string]int64{"addition": 0, "multiplication": 0}, a.OperationTimesMs)
+		}
+	})
+}
+
+func TestHeartbeatEviction(t *testing.T) {
+	newPool := func(t *testing.T, operationRepo *MockOperationRepository, interval, timeout time.Duration) *AgentPool {
+		t.Helper()
+
+		ctx, cancel := context.WithCancel(logger.WithLogger(context.Background(), logger.New(zapcore.NewNopCore())))
+		t.Cleanup(cancel)
+
+		pool, err := NewAgentPool(memAgent.NewAgentStorage(), operationRepo, map[string]time.Duration{
+			"addition": time.Minute,
+		}, 1)
+		require.NoError(t, err)
+		pool.SetHeartbeat(interval, timeout)
+		expectNotCancelled(operationRepo)
+		pool.Start(ctx)
+		return pool
+	}
+
+	t.Run("Missed heartbeat evicts agent and requeues its operations", func(t *testing.T) {
+		operationRepo := new(MockOperationRepository)
+		requeued := make(chan uuid.UUID, 4)
+		operationRepo.On("UpdateStatus", mock.Anything, mock.Anything, orchestrator.OperationStatusPending, "", "").
+			Run(func(args mock.Arguments) { requeued <- args.Get(1).(uuid.UUID) }).Return(nil).Maybe()
+		operationRepo.On("UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+
+		// Сигналы реже таймаута: после первого сигнала при запуске агент пропускает следующие
+		pool := newPool(t, operationRepo, time.Hour, 100*time.Millisecond)
+		agents, err := pool.ListAgents()
+		require.NoError(t, err)
+		require.Len(t, agents, 1)
+		agentID := agents[0].ID
+
+		assignAddition(t, pool)
+		queued := assignAddition(t, pool)
+
+		select {
+		case id := <-requeued:
+			assert.Equal(t, queued.ID, id)
+		case <-time.After(3 * time.Second):
+			t.Fatal("queued operation of evicted agent was not requeued")
+		}
+
+		stored, err := pool.storage.GetByID(agentID)
+		require.NoError(t, err)
+		assert.Equal(t, agent.AgentStatusOffline, stored.Status)
+
+		pool.mu.RLock()
+		_, exists := pool.workers[agentID]
+		pool.mu.RUnlock()
+		assert.False(t, exists)
+
+		_, err = pool.GetAvailableAgent(int(orchestrator.OperationTypeAddition))
+		assert.ErrorIs(t, err, domainerrors.ErrNoAgentsAvailable)
+	})
+
+	t.Run("Regular heartbeats keep agent online", func(t *testing.T) {
+		operationRepo := new(MockOperationRepository)
+		pool := newPool(t, operationRepo, 20*time.Millisecond, 300*time.Millisecond)
+		agents, err := pool.ListAgents()
+		require.NoError(t, err)
+		require.Len(t, agents, 1)
+		agentID := agents[0].ID
+
+		assert.Never(t, func() bool {
+			stored, err := pool.storage.GetByID(agentID)
+			return err != nil || stored.Status == agent.AgentStatusOffline
+		}, 1200*time.Millisecond, 50*time.Millisecond)
+
+		stored, err := pool.storage.GetByID(agentID)
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now(), stored.LastHeartbeat, time.Second)
+	})
+}
+
+func TestSetOperationCostsExceedingCapacity(t *testing.T) {
+	pool, err := NewAgentPool(memAgent.NewAgentStorage(), new(MockOperationRepository), nil, 1)
+	require.NoError(t, err)
+
+	err = pool.SetOperationCosts(map[string]int{"addition": 1, "division": workerCapacity + 1})
+	assert.ErrorIs(t, err, domainerrors.ErrInvalidCapacity)
+	assert.Nil(t, pool.operationCosts)
+
+	assert.NoError(t, pool.SetOperationCosts(map[string]int{"division": workerCapacity}))
+}
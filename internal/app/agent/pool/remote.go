@@ -0,0 +1,306 @@
+package pool
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/app/agent/worker"
+	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/agent"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	agentapi "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/api/agent"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var _ agentapi.RemoteAgentRegistry = (*AgentPool)(nil)
+
+// remoteAgent - агент, работающий в отдельном процессе. Назначенные ему операции ждут
+// в queued, пока агент их не заберет, и остаются в inFlight до отчета о выполнении.
+// Поля защищены мьютексом пула.
+type remoteAgent struct {
+	agent    agent.Agent
+	queued   []*orchestrator.Operation
+	inFlight map[uuid.UUID]*orchestrator.Operation
+}
+
+// snapshot возвращает копию состояния агента с актуальными динамическими полями.
+func (r *remoteAgent) snapshot() *agent.Agent {
+	agentCopy := r.agent
+	agentCopy.OperationCosts = maps.Clone(r.agent.OperationCosts)
+	agentCopy.UptimeSeconds = int64(time.Since(r.agent.StartedAt).Seconds())
+
+	agentCopy.TypeCapacity = make(map[string]int, len(agentCopy.OperationCosts))
+	for operation := range agentCopy.OperationCosts {
+		agentCopy.TypeCapacity[operation] = agentCopy.RemainingCapacityFor(operation)
+	}
+
+	if agentCopy.CurrentLoad >= agentCopy.MaxCapacity {
+		agentCopy.Status = agent.AgentStatusBusy
+	} else {
+		agentCopy.Status = agent.AgentStatusOnline
+	}
+
+	return &agentCopy
+}
+
+// operations возвращает все невыполненные операции агента: ожидающие выдачи и выданные.
+func (r *remoteAgent) operations() []*orchestrator.Operation {
+	ops := make([]*orchestrator.Operation, 0, len(r.queued)+len(r.inFlight))
+	ops = append(ops, r.queued...)
+	for _, op := range r.inFlight {
+		ops = append(ops, op)
+	}
+	return ops
+}
+
+// release освобождает емкость агента, занятую операцией.
+func (r *remoteAgent) release(op *orchestrator.Operation) {
+	r.agent.CurrentLoad = max(r.agent.CurrentLoad-r.agent.OperationCost(op.OperationType.Name()), 0)
+}
+
+// RegisterRemote регистрирует агента, работающего в отдельном процессе. Агент получает
+// операции наравне с воркерами пула, но забирает их сам через FetchOperations.
+// Повторная регистрация с тем же ID сбрасывает состояние агента и возвращает его
+// невыполненные операции в статус PENDING.
+func (p *AgentPool) RegisterRemote(ctx context.Context, agentID string, capacity int) (*agent.Agent, error) {
+	if capacity <= 0 {
+		return nil, fmt.Errorf("%w: %d", domainerrors.ErrInvalidCapacity, capacity)
+	}
+
+	if agentID == "" {
+		agentID = "remote-" + uuid.New().String()[:8]
+	}
+
+	p.mu.Lock()
+	if !p.running {
+		p.mu.Unlock()
+		return nil, domainerrors.ErrPoolNotRunning
+	}
+	if p.draining {
+		p.mu.Unlock()
+		return nil, domainerrors.ErrPoolDraining
+	}
+	if _, exists := p.workers[agentID]; exists {
+		p.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s", domainerrors.ErrAgentAlreadyExists, agentID)
+	}
+
+	var stale []*orchestrator.Operation
+	if previous, exists := p.remotes[agentID]; exists {
+		stale = previous.operations()
+	}
+
+	now := time.Now()
+	remote := &remoteAgent{
+		agent: agent.Agent{
+			ID:             agentID,
+			Status:         agent.AgentStatusOnline,
+			MaxCapacity:    capacity,
+			OperationCosts: maps.Clone(p.operationCosts),
+			StartedAt:      now,
+			LastHeartbeat:  now,
+			Remote:         true,
+		},
+		inFlight: make(map[uuid.UUID]*orchestrator.Operation),
+	}
+	p.remotes[agentID] = remote
+	status := remote.snapshot()
+	p.mu.Unlock()
+
+	p.storage.Add(status)
+
+	requeued := p.requeueOperations(ctx, stale)
+	logger.ContextLogger(ctx, nil).Info("Registered remote agent",
+		zap.String("agent_id", agentID),
+		zap.Int("capacity", capacity),
+		zap.Int("requeued", requeued))
+
+	return status, nil
+}
+
+// FetchOperations выдает агенту до limit назначенных ему операций (все при limit <= 0)
+// со ссылками, замененными результатами операций, и отмечает сигнал о его работе.
+// Операция, ссылку в которой разрешить не удалось, сразу завершается ошибкой.
+func (p *AgentPool) FetchOperations(ctx context.Context, agentID string, limit int) ([]orchestrator.AgentTask, error) {
+	now := time.Now()
+
+	p.mu.Lock()
+	remote, exists := p.remotes[agentID]
+	if !exists {
+		p.mu.Unlock()
+		return nil, fmt.Errorf("%w: agent %s", domainerrors.ErrAgentNotFound, agentID)
+	}
+
+	remote.agent.LastHeartbeat = now
+	n := len(remote.queued)
+	if limit > 0 && limit < n {
+		n = limit
+	}
+	ops := make([]*orchestrator.Operation, n)
+	copy(ops, remote.queued)
+	remote.queued = remote.queued[n:]
+	for _, op := range ops {
+		remote.inFlight[op.ID] = op
+	}
+	deterministic := p.deterministic
+	p.mu.Unlock()
+
+	log := logger.ContextLogger(ctx, nil).With(zap.String("agent_id", agentID))
+	if err := p.storage.Heartbeat(agentID, now); err != nil {
+		log.Warn("Failed to record agent heartbeat", zap.Error(err))
+	}
+
+	tasks := make([]orchestrator.AgentTask, 0, len(ops))
+	for _, op := range ops {
+		task, err := p.newAgentTask(ctx, op, deterministic)
+		if err != nil {
+			log.Warn("Failed to resolve operation operands", zap.String("operation_id", op.ID.String()), zap.Error(err))
+			if reportErr := p.ReportResult(ctx, agentID, op.ID, "", err.Error()); reportErr != nil {
+				log.Warn("Failed to fail operation", zap.String("operation_id", op.ID.String()), zap.Error(reportErr))
+			}
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, nil
+}
+
+// newAgentTask готовит операцию к выдаче агенту: разрешает ссылки в операндах
+// и определяет время имитации выполнения.
+func (p *AgentPool) newAgentTask(ctx context.Context, op *orchestrator.Operation, deterministic bool) (orchestrator.AgentTask, error) {
+	resolved := *op
+
+	var err error
+	if resolved.Operand1, err = worker.ResolveOperand(ctx, p.operationRepo, op.Operand1); err != nil {
+		return orchestrator.AgentTask{}, err
+	}
+	if resolved.Operand2, err = worker.ResolveOperand(ctx, p.operationRepo, op.Operand2); err != nil {
+		return orchestrator.AgentTask{}, err
+	}
+
+	task := orchestrator.AgentTask{Operation: &resolved}
+	if !deterministic {
+		task.Duration = time.Second
+		if duration, ok := p.operationTimes[op.OperationType.Name()]; ok {
+			task.Duration = duration
+		}
+	}
+	return task, nil
+}
+
+// ReportResult записывает результат операции, выданной агенту через FetchOperations,
+// и освобождает занятую ею емкость. Результат отмененной операции не записывается.
+func (p *AgentPool) ReportResult(ctx context.Context, agentID string, operationID uuid.UUID, result, errorMessage string) error {
+	now := time.Now()
+
+	p.mu.Lock()
+	remote, exists := p.remotes[agentID]
+	if !exists {
+		p.mu.Unlock()
+		return fmt.Errorf("%w: agent %s", domainerrors.ErrAgentNotFound, agentID)
+	}
+
+	op, inFlight := remote.inFlight[operationID]
+	if !inFlight {
+		p.mu.Unlock()
+		return fmt.Errorf("%w: operation %s is not assigned to agent %s", domainerrors.ErrOperationNotFound, operationID, agentID)
+	}
+
+	failed := errorMessage != ""
+	delete(remote.inFlight, operationID)
+	remote.release(op)
+	remote.agent.LastHeartbeat = now
+	remote.agent.LastOperationAt = now
+	remote.agent.OperationsStats.Total++
+	if failed {
+		remote.agent.OperationsStats.Failed++
+	} else {
+		remote.agent.OperationsStats.Completed++
+	}
+	cancellation := p.cancellation
+	batcher := p.statusBatcher
+	p.mu.Unlock()
+
+	p.counters.AddResult(failed)
+
+	log := logger.ContextLogger(ctx, nil).With(
+		zap.String("agent_id", agentID),
+		zap.String("operation_id", operationID.String()))
+	if err := p.storage.UpdateStats(agentID, !failed, failed); err != nil {
+		log.Warn("Failed to update agent stats", zap.Error(err))
+	}
+	if err := p.storage.Heartbeat(agentID, now); err != nil {
+		log.Warn("Failed to record agent heartbeat", zap.Error(err))
+	}
+
+	// Статус отмененной операции уже записан при отмене
+	if cancellation != nil && cancellation.IsCancelled(operationID) {
+		log.Debug("Skipping result of cancelled operation")
+		return nil
+	}
+
+	opStatus := orchestrator.OperationStatusCompleted
+	if failed {
+		opStatus = orchestrator.OperationStatusError
+		result = ""
+	}
+
+	if batcher != nil {
+		batcher.Add(orchestrator.OperationStatusUpdate{ID: operationID, Status: opStatus, Result: result, ErrorMessage: errorMessage})
+		return nil
+	}
+	if err := p.operationRepo.UpdateStatus(ctx, operationID, opStatus, result, errorMessage); err != nil {
+		log.Error("Failed to update operation status", zap.Error(err))
+		return fmt.Errorf("updating operation %s status: %w", operationID, err)
+	}
+
+	return nil
+}
+
+// assignRemote ставит операцию в очередь агента, работающего в отдельном процессе.
+func (p *AgentPool) assignRemote(ctx context.Context, agentID string, operation *orchestrator.Operation) error {
+	p.mu.Lock()
+	remote, exists := p.remotes[agentID]
+	if !exists {
+		p.mu.Unlock()
+		return fmt.Errorf("%w: agent %s", domainerrors.ErrAgentNotFound, agentID)
+	}
+
+	operationName := operation.OperationType.Name()
+	if remote.agent.RemainingCapacityFor(operationName) <= 0 {
+		p.mu.Unlock()
+		return fmt.Errorf("%w: %w: agent %s", domainerrors.ErrOperationAssignment, domainerrors.ErrAgentAtCapacity, agentID)
+	}
+
+	remote.queued = append(remote.queued, operation)
+	remote.agent.CurrentLoad += remote.agent.OperationCost(operationName)
+	p.mu.Unlock()
+
+	p.counters.AddDispatched()
+	logger.ContextLogger(ctx, nil).Info("Queued operation for remote agent",
+		zap.String("operation_id", operation.ID.String()),
+		zap.String("agent_id", agentID))
+
+	return nil
+}
+
+// requeueOperations возвращает операции в статус PENDING и возвращает количество
+// успешно возвращенных.
+func (p *AgentPool) requeueOperations(ctx context.Context, ops []*orchestrator.Operation) int {
+	requeued := 0
+	for _, op := range ops {
+		if err := p.operationRepo.UpdateStatus(ctx, op.ID, orchestrator.OperationStatusPending, "", ""); err != nil {
+			logger.ContextLogger(ctx, nil).Warn("Failed to requeue operation",
+				zap.String("operation_id", op.ID.String()),
+				zap.Error(err))
+			continue
+		}
+		requeued++
+	}
+	return requeued
+}
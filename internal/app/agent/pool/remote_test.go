@@ -0,0 +1,257 @@
+package pool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	memAgent "github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/db/memory/agent"
+	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/agent"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// newRemoteTestPool запускает пул на хранилище в памяти и убирает его воркеров,
+// чтобы операции доставались только агентам в отдельных процессах.
+func newRemoteTestPool(t *testing.T, operationRepo *MockOperationRepository) (context.Context, *AgentPool) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(logger.WithLogger(context.Background(), logger.New(zapcore.NewNopCore())))
+	t.Cleanup(cancel)
+
+	pool, err := NewAgentPool(memAgent.NewAgentStorage(), operationRepo, map[string]time.Duration{
+		"addition":       200 * time.Millisecond,
+		"multiplication": 300 * time.Millisecond,
+	}, 1)
+	require.NoError(t, err)
+	pool.Start(ctx)
+
+	pool.mu.Lock()
+	for id, w := range pool.workers {
+		w.Stop()
+		delete(pool.workers, id)
+		require.NoError(t, pool.storage.Remove(id))
+	}
+	pool.mu.Unlock()
+
+	return ctx, pool
+}
+
+func newRemoteOperation(opType orchestrator.OperationType, operand1, operand2 string) *orchestrator.Operation {
+	return &orchestrator.Operation{
+		ID:            uuid.New(),
+		CalculationID: uuid.New(),
+		OperationType: opType,
+		Operand1:      operand1,
+		Operand2:      operand2,
+		Status:        orchestrator.OperationStatusInProgress,
+	}
+}
+
+func TestRemoteAgentRoundTrip(t *testing.T) {
+	t.Run("Register, assign, fetch and report", func(t *testing.T) {
+		operationRepo := new(MockOperationRepository)
+		ctx, pool := newRemoteTestPool(t, operationRepo)
+
+		registered, err := pool.RegisterRemote(ctx, "remote-1", 2)
+		require.NoError(t, err)
+		assert.Equal(t, "remote-1", registered.ID)
+		assert.True(t, registered.Remote)
+
+		stored, err := pool.storage.GetByID("remote-1")
+		require.NoError(t, err)
+		assert.Equal(t, agent.AgentStatusOnline, stored.Status)
+		assert.Equal(t, 2, stored.MaxCapacity)
+		assert.True(t, stored.Remote)
+
+		available, err := pool.GetAvailableAgent(int(orchestrator.OperationTypeMultiplication))
+		require.NoError(t, err)
+		assert.Equal(t, "remote-1", available.ID)
+
+		// Первый операнд ссылается на результат завершенной операции
+		ref := &orchestrator.Operation{ID: uuid.New(), Status: orchestrator.OperationStatusCompleted, Result: "4"}
+		operationRepo.On("FindByID", mock.Anything, ref.ID).Return(ref, nil)
+		op := newRemoteOperation(orchestrator.OperationTypeMultiplication, "ref:"+ref.ID.String(), "3")
+		require.NoError(t, pool.AssignOperation(ctx, "remote-1", op))
+
+		stats, err := pool.GetPoolStats()
+		require.NoError(t, err)
+		require.Len(t, stats.Agents, 1)
+		assert.Equal(t, 1, stats.Agents[0].CurrentLoad)
+		assert.Equal(t, 1, stats.Agents[0].QueueDepth)
+
+		tasks, err := pool.FetchOperations(ctx, "remote-1", 0)
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
+		assert.Equal(t, op.ID, tasks[0].Operation.ID)
+		assert.Equal(t, "4", tasks[0].Operation.Operand1)
+		assert.Equal(t, "3", tasks[0].Operation.Operand2)
+		assert.Equal(t, 300*time.Millisecond, tasks[0].Duration)
+		assert.Equal(t, "ref:"+ref.ID.String(), op.Operand1, "stored operation keeps its reference")
+
+		// Выданная операция занимает емкость до отчета, но в очереди ее уже нет
+		again, err := pool.FetchOperations(ctx, "remote-1", 0)
+		require.NoError(t, err)
+		assert.Empty(t, again)
+		status, err := pool.GetAgentStatus("remote-1")
+		require.NoError(t, err)
+		assert.Equal(t, 1, status.CurrentLoad)
+
+		operationRepo.On("UpdateStatus", mock.Anything, op.ID, orchestrator.OperationStatusCompleted, "12", "").Return(nil).Once()
+		require.NoError(t, pool.ReportResult(ctx, "remote-1", op.ID, "12", ""))
+		operationRepo.AssertExpectations(t)
+
+		stored, err = pool.storage.GetByID("remote-1")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), stored.OperationsStats.Completed)
+
+		status, err = pool.GetAgentStatus("remote-1")
+		require.NoError(t, err)
+		assert.Equal(t, 0, status.CurrentLoad)
+		assert.Equal(t, int64(1), status.OperationsStats.Completed)
+		assert.Equal(t, agent.Throughput{Dispatched: 1, Completed: 1}, pool.PoolStats())
+
+		err = pool.ReportResult(ctx, "remote-1", op.ID, "12", "")
+		assert.ErrorIs(t, err, domainerrors.ErrOperationNotFound)
+	})
+
+	t.Run("Failed operation is recorded as error", func(t *testing.T) {
+		operationRepo := new(MockOperationRepository)
+		ctx, pool := newRemoteTestPool(t, operationRepo)
+		pool.SetDeterministic(true)
+
+		_, err := pool.RegisterRemote(ctx, "remote-1", 1)
+		require.NoError(t, err)
+
+		op := newRemoteOperation(orchestrator.OperationTypeAddition, "1", "2")
+		require.NoError(t, pool.AssignOperation(ctx, "remote-1", op))
+
+		tasks, err := pool.FetchOperations(ctx, "remote-1", 1)
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
+		assert.Zero(t, tasks[0].Duration, "deterministic mode has no emulated delay")
+
+		operationRepo.On("UpdateStatus", mock.Anything, op.ID, orchestrator.OperationStatusError, "", "numeric overflow").Return(nil).Once()
+		require.NoError(t, pool.ReportResult(ctx, "remote-1", op.ID, "ignored", "numeric overflow"))
+		operationRepo.AssertExpectations(t)
+		assert.Equal(t, int64(1), pool.PoolStats().Failed)
+	})
+
+	t.Run("Unresolvable reference fails operation on fetch", func(t *testing.T) {
+		operationRepo := new(MockOperationRepository)
+		ctx, pool := newRemoteTestPool(t, operationRepo)
+
+		_, err := pool.RegisterRemote(ctx, "remote-1", 2)
+		require.NoError(t, err)
+
+		ref := &orchestrator.Operation{ID: uuid.New(), Status: orchestrator.OperationStatusPending}
+		operationRepo.On("FindByID", mock.Anything, ref.ID).Return(ref, nil)
+		op := newRemoteOperation(orchestrator.OperationTypeAddition, "1", "ref:"+ref.ID.String())
+		require.NoError(t, pool.AssignOperation(ctx, "remote-1", op))
+
+		operationRepo.On("UpdateStatus", mock.Anything, op.ID, orchestrator.OperationStatusError, "", mock.MatchedBy(func(msg string) bool {
+			return msg != ""
+		})).Return(nil).Once()
+
+		tasks, err := pool.FetchOperations(ctx, "remote-1", 0)
+		require.NoError(t, err)
+		assert.Empty(t, tasks)
+		operationRepo.AssertExpectations(t)
+
+		status, err := pool.GetAgentStatus("remote-1")
+		require.NoError(t, err)
+		assert.Equal(t, 0, status.CurrentLoad)
+	})
+
+	t.Run("Re-registration requeues unfinished operations", func(t *testing.T) {
+		operationRepo := new(MockOperationRepository)
+		ctx, pool := newRemoteTestPool(t, operationRepo)
+
+		_, err := pool.RegisterRemote(ctx, "remote-1", 2)
+		require.NoError(t, err)
+
+		fetched := newRemoteOperation(orchestrator.OperationTypeAddition, "1", "2")
+		queued := newRemoteOperation(orchestrator.OperationTypeAddition, "3", "4")
+		require.NoError(t, pool.AssignOperation(ctx, "remote-1", fetched))
+		_, err = pool.FetchOperations(ctx, "remote-1", 0)
+		require.NoError(t, err)
+		require.NoError(t, pool.AssignOperation(ctx, "remote-1", queued))
+
+		operationRepo.On("UpdateStatus", mock.Anything, fetched.ID, orchestrator.OperationStatusPending, "", "").Return(nil).Once()
+		operationRepo.On("UpdateStatus", mock.Anything, queued.ID, orchestrator.OperationStatusPending, "", "").Return(nil).Once()
+
+		registered, err := pool.RegisterRemote(ctx, "remote-1", 3)
+		require.NoError(t, err)
+		assert.Equal(t, 3, registered.MaxCapacity)
+		assert.Equal(t, 0, registered.CurrentLoad)
+		operationRepo.AssertExpectations(t)
+
+		err = pool.ReportResult(ctx, "remote-1", fetched.ID, "3", "")
+		assert.ErrorIs(t, err, domainerrors.ErrOperationNotFound)
+	})
+
+	t.Run("Validation errors", func(t *testing.T) {
+		operationRepo := new(MockOperationRepository)
+		ctx, pool := newRemoteTestPool(t, operationRepo)
+
+		_, err := pool.RegisterRemote(ctx, "remote-1", 0)
+		assert.ErrorIs(t, err, domainerrors.ErrInvalidCapacity)
+
+		registered, err := pool.RegisterRemote(ctx, "", 1)
+		require.NoError(t, err)
+		assert.NotEmpty(t, registered.ID)
+
+		require.NoError(t, pool.AssignOperation(ctx, registered.ID, newRemoteOperation(orchestrator.OperationTypeAddition, "1", "2")))
+		err = pool.AssignOperation(ctx, registered.ID, newRemoteOperation(orchestrator.OperationTypeAddition, "1", "2"))
+		assert.ErrorIs(t, err, domainerrors.ErrAgentAtCapacity)
+
+		_, err = pool.FetchOperations(ctx, "missing", 0)
+		assert.ErrorIs(t, err, domainerrors.ErrAgentNotFound)
+
+		err = pool.ReportResult(ctx, "missing", uuid.New(), "1", "")
+		assert.ErrorIs(t, err, domainerrors.ErrAgentNotFound)
+	})
+
+	t.Run("Worker ID cannot be taken", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(logger.WithLogger(context.Background(), logger.New(zapcore.NewNopCore())))
+		t.Cleanup(cancel)
+
+		pool, err := NewAgentPool(memAgent.NewAgentStorage(), new(MockOperationRepository), nil, 1)
+		require.NoError(t, err)
+		pool.Start(ctx)
+
+		agents, err := pool.ListAgents()
+		require.NoError(t, err)
+		require.Len(t, agents, 1)
+
+		_, err = pool.RegisterRemote(ctx, agents[0].ID, 1)
+		assert.ErrorIs(t, err, domainerrors.ErrAgentAlreadyExists)
+	})
+
+	t.Run("Stop requeues operations and removes agent", func(t *testing.T) {
+		operationRepo := new(MockOperationRepository)
+		ctx, pool := newRemoteTestPool(t, operationRepo)
+
+		_, err := pool.RegisterRemote(ctx, "remote-1", 1)
+		require.NoError(t, err)
+
+		op := newRemoteOperation(orchestrator.OperationTypeAddition, "1", "2")
+		require.NoError(t, pool.AssignOperation(ctx, "remote-1", op))
+		operationRepo.On("UpdateStatus", mock.Anything, op.ID, orchestrator.OperationStatusPending, "", "").Return(nil).Once()
+
+		pool.Stop(ctx)
+		operationRepo.AssertExpectations(t)
+
+		_, err = pool.storage.GetByID("remote-1")
+		assert.Error(t, err)
+
+		_, err = pool.RegisterRemote(ctx, "remote-1", 1)
+		assert.ErrorIs(t, err, domainerrors.ErrPoolNotRunning)
+	})
+}
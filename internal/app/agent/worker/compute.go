@@ -0,0 +1,103 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	orchestratorRepo "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/repository/orchestrator"
+	"github.com/google/uuid"
+)
+
+// Compute вычисляет операцию над операндами, ссылки в которых уже разрешены, в заданном
+// представлении чисел. Результат не округляется: округляется только итог вычисления.
+// Время выполнения не имитирует. Используется воркерами пула и агентами, работающими
+// в отдельных процессах.
+func Compute(opType orchestrator.OperationType, operand1Str, operand2Str string, arithmetic orchestrator.Arithmetic) (string, error) {
+	// В десятичном режиме операнды не переводятся в float64, чтобы не терять точность
+	if arithmetic.Backend == orchestrator.ArithmeticDecimal {
+		result, scale, err := calculateDecimal(opType, operand1Str, operand2Str, arithmetic.DivisionPrecision)
+		if err != nil {
+			return "", err
+		}
+		if !arithmetic.PreserveScale {
+			scale = 0
+		}
+		return formatDecimalResult(result, scale), nil
+	}
+
+	operand1, err := strconv.ParseFloat(operand1Str, 64)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", domainerrors.ErrInvalidOperand, operand1Str)
+	}
+
+	operand2, err := strconv.ParseFloat(operand2Str, 64)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", domainerrors.ErrInvalidOperand, operand2Str)
+	}
+
+	var result float64
+	switch opType {
+	case orchestrator.OperationTypeAddition:
+		result = operand1 + operand2
+	case orchestrator.OperationTypeSubtraction:
+		result = operand1 - operand2
+	case orchestrator.OperationTypeMultiplication:
+		result = operand1 * operand2
+	case orchestrator.OperationTypeDivision:
+		result, err = arithmetic.DivisionByZero.Divide(operand1, operand2)
+		if err != nil {
+			return "", err
+		}
+	case orchestrator.OperationTypeModulo:
+		result, err = arithmetic.DivisionByZero.Mod(operand1, operand2)
+		if err != nil {
+			return "", err
+		}
+	case orchestrator.OperationTypeFactorial:
+		result, err = orchestrator.Factorial(operand1)
+		if err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("%w: %d", domainerrors.ErrUnsupportedOp, opType)
+	}
+
+	return formatNumericResult(result), nil
+}
+
+// ResolveOperand заменяет ссылку вида "ref:UUID" результатом указанной операции, которая
+// должна быть успешно завершена. Операнды без ссылки возвращаются без изменений.
+func ResolveOperand(ctx context.Context, operationRepo orchestratorRepo.OperationRepository, operand string) (string, error) {
+	if !strings.HasPrefix(operand, "ref:") {
+		return operand, nil
+	}
+
+	if operationRepo == nil {
+		return "", domainerrors.ErrRepoNotInitialized
+	}
+
+	refID := strings.TrimPrefix(operand, "ref:")
+	uid, err := uuid.Parse(refID)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", domainerrors.ErrInvalidReferenceID, refID)
+	}
+
+	refOp, err := operationRepo.FindByID(ctx, uid)
+	if err != nil {
+		return "", fmt.Errorf("reference lookup failed: %w", err)
+	}
+
+	if refOp == nil {
+		return "", fmt.Errorf("%w: %s", domainerrors.ErrReferenceNotFound, refID)
+	}
+
+	if refOp.Status != orchestrator.OperationStatusCompleted {
+		return "", fmt.Errorf("%w: %s", domainerrors.ErrRefNotCompleted, refID)
+	}
+
+	return refOp.Result, nil
+}
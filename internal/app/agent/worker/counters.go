@@ -0,0 +1,38 @@
+package worker
+
+import (
+	"sync/atomic"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/agent"
+)
+
+// Counters - общие для нескольких воркеров счетчики операций. Обновляются атомарно,
+// без блокировок, поэтому их можно разделять между воркерами пула.
+type Counters struct {
+	dispatched atomic.Int64
+	completed  atomic.Int64
+	failed     atomic.Int64
+}
+
+// AddDispatched учитывает операцию, переданную воркеру.
+func (c *Counters) AddDispatched() {
+	c.dispatched.Add(1)
+}
+
+// AddResult учитывает выполненную операцию: успешную или завершившуюся ошибкой.
+func (c *Counters) AddResult(failed bool) {
+	if failed {
+		c.failed.Add(1)
+		return
+	}
+	c.completed.Add(1)
+}
+
+// Snapshot возвращает текущие значения счетчиков.
+func (c *Counters) Snapshot() agent.Throughput {
+	return agent.Throughput{
+		Dispatched: c.dispatched.Load(),
+		Completed:  c.completed.Load(),
+		Failed:     c.failed.Load(),
+	}
+}
@@ -0,0 +1,86 @@
+package worker
+
+import (
+	"fmt"
+	"strings"
+
+	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	"github.com/shopspring/decimal"
+)
+
+// calculateDecimal выполняет операцию в десятичной арифметике.
+// Частное округляется до divisionPrecision знаков после запятой, остальные операции точны.
+// Вторым значением возвращается масштаб результата, выведенный из масштабов операндов
+// так же, как это делают электронные таблицы: для сложения, вычитания, деления и остатка -
+// наибольший масштаб операндов, для умножения - сумма масштабов.
+func calculateDecimal(opType orchestrator.OperationType, operand1Str, operand2Str string, divisionPrecision int32) (decimal.Decimal, int32, error) {
+	operand1, err := decimal.NewFromString(operand1Str)
+	if err != nil {
+		return decimal.Zero, 0, fmt.Errorf("%w: %s", domainerrors.ErrInvalidOperand, operand1Str)
+	}
+
+	operand2, err := decimal.NewFromString(operand2Str)
+	if err != nil {
+		return decimal.Zero, 0, fmt.Errorf("%w: %s", domainerrors.ErrInvalidOperand, operand2Str)
+	}
+
+	scale := max(decimalScale(operand1), decimalScale(operand2))
+
+	switch opType {
+	case orchestrator.OperationTypeAddition:
+		return operand1.Add(operand2), scale, nil
+	case orchestrator.OperationTypeSubtraction:
+		return operand1.Sub(operand2), scale, nil
+	case orchestrator.OperationTypeMultiplication:
+		return operand1.Mul(operand2), decimalScale(operand1) + decimalScale(operand2), nil
+	case orchestrator.OperationTypeDivision:
+		if operand2.IsZero() {
+			return decimal.Zero, 0, domainerrors.ErrDivisionByZero
+		}
+		return operand1.DivRound(operand2, divisionPrecision), scale, nil
+	case orchestrator.OperationTypeModulo:
+		if operand2.IsZero() {
+			return decimal.Zero, 0, domainerrors.ErrDivisionByZero
+		}
+		return operand1.Mod(operand2), scale, nil
+	case orchestrator.OperationTypeFactorial:
+		return factorialDecimal(operand1)
+	default:
+		return decimal.Zero, 0, fmt.Errorf("%w: %d", domainerrors.ErrUnsupportedOp, opType)
+	}
+}
+
+// factorialDecimal вычисляет n! точно. Операнды больше MaxFactorialOperand отклоняются
+// с ErrNumericOverflow, как и в float64, чтобы результат не зависел от представления чисел.
+func factorialDecimal(n decimal.Decimal) (decimal.Decimal, int32, error) {
+	if n.Sign() < 0 || !n.IsInteger() {
+		return decimal.Zero, 0, fmt.Errorf("%w: %s", domainerrors.ErrFactorialOperand, n.String())
+	}
+	if n.GreaterThan(decimal.NewFromInt(orchestrator.MaxFactorialOperand)) {
+		return decimal.Zero, 0, fmt.Errorf("%w: %s!", domainerrors.ErrNumericOverflow, n.String())
+	}
+
+	result := decimal.NewFromInt(1)
+	for i := int64(2); i <= n.IntPart(); i++ {
+		result = result.Mul(decimal.NewFromInt(i))
+	}
+	return result, 0, nil
+}
+
+// decimalScale возвращает количество знаков после запятой в записи числа, включая незначащие нули.
+func decimalScale(value decimal.Decimal) int32 {
+	return max(-value.Exponent(), 0)
+}
+
+// formatDecimalResult переводит десятичный результат в строку. Незначащие нули в дробной
+// части отбрасываются, но в записи остается не меньше minScale знаков после запятой,
+// поэтому при minScale = 2 результат 5 выводится как 5.00.
+func formatDecimalResult(result decimal.Decimal, minScale int32) string {
+	text := result.String()
+	_, fraction, _ := strings.Cut(text, ".")
+	if int(minScale) > len(fraction) {
+		return result.StringFixed(minScale)
+	}
+	return text
+}
@@ -0,0 +1,122 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	orchestratorRepo "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/repository/orchestrator"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// defaultStatusBatchSize - количество накопленных статусов, при котором пакет
+// записывается, не дожидаясь интервала.
+const defaultStatusBatchSize = 100
+
+// flushTimeout ограничивает запись одного пакета статусов.
+const flushTimeout = 5 * time.Second
+
+// StatusBatcher накапливает статусы выполненных операций от воркеров пула и записывает их
+// одним пакетом по интервалу или при заполнении пакета. Если пакет записать не удалось,
+// статусы записываются по одному, чтобы ошибка одной операции не потеряла остальные.
+type StatusBatcher struct {
+	operationRepo orchestratorRepo.OperationRepository
+	maxBatch      int
+
+	mu      sync.Mutex
+	pending []orchestrator.OperationStatusUpdate
+	full    chan struct{}
+}
+
+// NewStatusBatcher создает накопитель статусов. Неположительный maxBatch заменяется
+// значением по умолчанию.
+func NewStatusBatcher(operationRepo orchestratorRepo.OperationRepository, maxBatch int) *StatusBatcher {
+	if maxBatch <= 0 {
+		maxBatch = defaultStatusBatchSize
+	}
+	return &StatusBatcher{
+		operationRepo: operationRepo,
+		maxBatch:      maxBatch,
+		full:          make(chan struct{}, 1),
+	}
+}
+
+// Add добавляет статус операции в следующий пакет.
+func (b *StatusBatcher) Add(update orchestrator.OperationStatusUpdate) {
+	b.mu.Lock()
+	b.pending = append(b.pending, update)
+	full := len(b.pending) >= b.maxBatch
+	b.mu.Unlock()
+
+	if full {
+		select {
+		case b.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Run записывает накопленные статусы каждые interval, пока не завершится ctx.
+// Перед выходом записывает то, что осталось.
+func (b *StatusBatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), flushTimeout)
+			b.Flush(flushCtx)
+			cancel()
+			return
+		case <-ticker.C:
+		case <-b.full:
+		}
+
+		flushCtx, cancel := context.WithTimeout(ctx, flushTimeout)
+		b.Flush(flushCtx)
+		cancel()
+	}
+}
+
+// Flush записывает накопленные статусы и возвращает количество записанных.
+func (b *StatusBatcher) Flush(ctx context.Context) int {
+	b.mu.Lock()
+	updates := b.pending
+	b.pending = nil
+	b.mu.Unlock()
+
+	switch len(updates) {
+	case 0:
+		return 0
+	case 1:
+		return b.writeEach(ctx, updates)
+	}
+
+	err := b.operationRepo.UpdateStatusBatch(ctx, updates)
+	if err == nil {
+		return len(updates)
+	}
+
+	logger.Warn(ctx, nil, "Failed to write operation statuses batch, writing one by one",
+		zap.Int("count", len(updates)),
+		zap.Error(err))
+	return b.writeEach(ctx, updates)
+}
+
+// writeEach записывает статусы по одному и возвращает количество записанных.
+func (b *StatusBatcher) writeEach(ctx context.Context, updates []orchestrator.OperationStatusUpdate) int {
+	written := 0
+	for _, update := range updates {
+		if err := b.operationRepo.UpdateStatus(ctx, update.ID, update.Status, update.Result, update.ErrorMessage); err != nil {
+			logger.Error(ctx, nil, "Failed to update operation status",
+				zap.String("operation_id", update.ID.String()),
+				zap.Error(err))
+			continue
+		}
+		written++
+	}
+	return written
+}
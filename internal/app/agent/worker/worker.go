@@ -3,9 +3,9 @@ package worker
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"math"
-	"strconv"
+	"maps"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -14,21 +14,42 @@ import (
 	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/agent"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	orchapi "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/api/orchestrator"
 	orchestratorRepo "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/repository/orchestrator"
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/tracing"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 )
 
+// spanExecuteOperation - имя спана выполнения одной операции.
+const spanExecuteOperation = "worker.executeOperation"
+
+// queuedOperation - операция в очереди агента вместе с идентификатором запроса,
+// в рамках которого она была назначена, чтобы журналы выполнения можно было связать с ним.
+type queuedOperation struct {
+	op        *orchestrator.Operation
+	requestID string
+}
+
 // Worker представляет исполнителя операций с собственным состоянием и очередью заданий.
 type Worker struct {
 	agent           *agent.Agent                         // состояние агента
 	operationTimes  map[string]time.Duration             // время выполнения различных типов операций
-	operationsQueue chan *orchestrator.Operation         // очередь операций для обработки
+	timeouts        map[string]time.Duration             // срок выполнения различных типов операций (нет - без срока)
+	operationsQueue chan queuedOperation                 // очередь операций для обработки
 	stopCh          chan struct{}                        // канал для сигнала остановки
+	done            chan struct{}                        // закрывается по завершении цикла обработки
 	running         int32                                // флаг работы (используется атомарно)
 	mu              sync.RWMutex                         // мьютекс для безопасного доступа к полям
 	operationRepo   orchestratorRepo.OperationRepository // репозиторий для сохранения операций
+	cancellation    orchapi.OperationCancellation        // реестр отмененных операций (может быть nil)
+	deterministic   bool                                 // выполнять операции без имитации задержки
+	arithmetic      orchestrator.Arithmetic              // представление чисел при вычислениях
+	counters        *Counters                            // общие счетчики операций пула (может быть nil)
+	statusBatcher   *StatusBatcher                       // пакетная запись статусов (nil - запись сразу)
+	heartbeat       time.Duration                        // интервал сигналов о работе агента (0 - без сигналов)
 }
 
 // NewWorker создает нового воркера с указанными параметрами.
@@ -48,6 +69,8 @@ func NewWorker(id string, capacity int, operationTimes map[string]time.Duration,
 			"subtraction":    time.Second,
 			"multiplication": 2 * time.Second,
 			"division":       2 * time.Second,
+			"modulo":         2 * time.Second,
+			"factorial":      2 * time.Second,
 		}
 	}
 
@@ -64,6 +87,8 @@ func NewWorker(id string, capacity int, operationTimes map[string]time.Duration,
 				"subtraction":    1,
 				"multiplication": 1,
 				"division":       1,
+				"modulo":         1,
+				"factorial":      1,
 			},
 			OperationsStats: agent.OperationsStats{
 				Completed: 0,
@@ -75,12 +100,167 @@ func NewWorker(id string, capacity int, operationTimes map[string]time.Duration,
 			UptimeSeconds:   0,
 		},
 		operationTimes:  operationTimes,
-		operationsQueue: make(chan *orchestrator.Operation, queueSize),
+		operationsQueue: make(chan queuedOperation, queueSize),
 		stopCh:          make(chan struct{}),
+		done:            make(chan struct{}),
 		operationRepo:   operationRepo,
+		arithmetic:      orchestrator.FloatArithmetic,
 	}, nil
 }
 
+// SetCancellation задает реестр отмененных операций, который проверяется перед выполнением.
+func (w *Worker) SetCancellation(cancellation orchapi.OperationCancellation) {
+	if w == nil {
+		return
+	}
+
+	w.mu.Lock()
+	w.cancellation = cancellation
+	w.mu.Unlock()
+}
+
+// SetCounters задает общие счетчики, в которых воркер учитывает выполненные операции.
+func (w *Worker) SetCounters(counters *Counters) {
+	if w == nil {
+		return
+	}
+
+	w.mu.Lock()
+	w.counters = counters
+	w.mu.Unlock()
+}
+
+// SetStatusBatcher задает накопитель, через который воркер записывает статусы выполненных
+// операций. Nil возвращает запись статуса сразу после выполнения операции.
+func (w *Worker) SetStatusBatcher(batcher *StatusBatcher) {
+	if w == nil {
+		return
+	}
+
+	w.mu.Lock()
+	w.statusBatcher = batcher
+	w.mu.Unlock()
+}
+
+// SetDeterministic включает детерминированный режим: операции выполняются сразу,
+// без имитации времени выполнения, независимо от настроенных длительностей.
+// Предназначен для тестов и демонстраций.
+func (w *Worker) SetDeterministic(deterministic bool) {
+	if w == nil {
+		return
+	}
+
+	w.mu.Lock()
+	w.deterministic = deterministic
+	w.mu.Unlock()
+}
+
+// SetOperationTimeouts задает срок выполнения операций по типам. Операция, не уложившаяся
+// в срок, завершается ошибкой domainerrors.ErrOperationTimeout. Для типов без срока
+// или с неположительным сроком время выполнения не ограничивается.
+func (w *Worker) SetOperationTimeouts(timeouts map[string]time.Duration) {
+	if w == nil {
+		return
+	}
+
+	w.mu.Lock()
+	w.timeouts = maps.Clone(timeouts)
+	w.mu.Unlock()
+}
+
+// SetHeartbeatInterval включает сигналы о работе агента: пока работает цикл обработки
+// операций, агент обновляет LastHeartbeat с интервалом interval. Неположительный интервал
+// отключает сигналы. Вызывается до Start.
+func (w *Worker) SetHeartbeatInterval(interval time.Duration) {
+	if w == nil {
+		return
+	}
+
+	w.mu.Lock()
+	w.heartbeat = max(interval, 0)
+	w.mu.Unlock()
+}
+
+// SetArithmetic задает представление чисел при выполнении операций.
+// Неизвестное представление заменяется на ArithmeticFloat, неположительная точность
+// деления - на DefaultDivisionPrecision, неизвестная политика деления на ноль -
+// на DivisionByZeroError.
+func (w *Worker) SetArithmetic(arithmetic orchestrator.Arithmetic) {
+	if w == nil {
+		return
+	}
+
+	if !arithmetic.Backend.IsValid() {
+		arithmetic.Backend = orchestrator.ArithmeticFloat
+	}
+	if arithmetic.DivisionPrecision <= 0 {
+		arithmetic.DivisionPrecision = orchestrator.DefaultDivisionPrecision
+	}
+	if !arithmetic.DivisionByZero.IsValid() {
+		arithmetic.DivisionByZero = orchestrator.DivisionByZeroError
+	}
+
+	w.mu.Lock()
+	w.arithmetic = arithmetic
+	w.mu.Unlock()
+}
+
+// SetOperationCosts задает стоимость операций в единицах емкости агента.
+// Неположительные значения игнорируются, для таких операций остается прежняя стоимость.
+func (w *Worker) SetOperationCosts(costs map[string]int) {
+	if w == nil {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.agent == nil {
+		return
+	}
+
+	for operation, cost := range costs {
+		if cost > 0 {
+			w.agent.OperationCosts[operation] = cost
+		}
+	}
+}
+
+// costLocked возвращает стоимость операции для агента. Вызывается под w.mu.
+func (w *Worker) costLocked(op *orchestrator.Operation) int {
+	if w.agent == nil {
+		return 1
+	}
+	return w.agent.OperationCost(op.OperationType.Name())
+}
+
+// isDeterministic проверяет, включен ли детерминированный режим.
+func (w *Worker) isDeterministic() bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.deterministic
+}
+
+// isCancelled проверяет, была ли операция отменена. Реестр отмен знает только об
+// отменах, выполненных этим процессом, поэтому дополнительно проверяется статус,
+// сохраненный в репозитории. Ошибка чтения не считается отменой.
+func (w *Worker) isCancelled(ctx context.Context, operationID uuid.UUID) bool {
+	w.mu.RLock()
+	cancellation := w.cancellation
+	w.mu.RUnlock()
+
+	if cancellation != nil && cancellation.IsCancelled(operationID) {
+		return true
+	}
+	if w.operationRepo == nil {
+		return false
+	}
+
+	stored, err := w.operationRepo.FindByID(ctx, operationID)
+	return err == nil && stored != nil && stored.Status == orchestrator.OperationStatusCancelled
+}
+
 // Start запускает обработку операций в фоновом режиме.
 // Переводит агента в статус Online.
 func (w *Worker) Start(ctx context.Context) {
@@ -96,6 +276,9 @@ func (w *Worker) Start(ctx context.Context) {
 	w.mu.Lock()
 	if w.agent != nil {
 		w.agent.Status = agent.AgentStatusOnline
+		if w.heartbeat > 0 {
+			w.agent.LastHeartbeat = time.Now()
+		}
 	}
 	w.mu.Unlock()
 
@@ -115,6 +298,17 @@ func (w *Worker) Start(ctx context.Context) {
 	go w.processOperations(ctx)
 }
 
+// Wait ждет завершения цикла обработки операций запущенного агента.
+// Возвращает ошибку ctx, если цикл не завершился до его отмены.
+func (w *Worker) Wait(ctx context.Context) error {
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // Stop останавливает обработку операций и переводит агента в статус Offline.
 func (w *Worker) Stop() {
 	if w == nil {
@@ -135,9 +329,10 @@ func (w *Worker) Stop() {
 	w.mu.Unlock()
 }
 
-// PerformOperation добавляет операцию в очередь на выполнение.
+// PerformOperation добавляет операцию в очередь на выполнение. Идентификатор запроса из ctx
+// сохраняется вместе с операцией и попадает в журналы ее выполнения.
 // Возвращает ошибку, если агент недоступен или перегружен.
-func (w *Worker) PerformOperation(operation *orchestrator.Operation) (*orchestrator.Operation, error) {
+func (w *Worker) PerformOperation(ctx context.Context, operation *orchestrator.Operation) (*orchestrator.Operation, error) {
 	if w == nil {
 		return nil, fmt.Errorf("worker is nil")
 	}
@@ -167,7 +362,7 @@ func (w *Worker) PerformOperation(operation *orchestrator.Operation) (*orchestra
 	if w.agent != nil {
 		agentID = w.agent.ID
 		isOnline = w.agent.Status == agent.AgentStatusOnline
-		atCapacity = w.agent.CurrentLoad >= w.agent.MaxCapacity
+		atCapacity = w.agent.CurrentLoad+w.costLocked(operation) > w.agent.MaxCapacity
 	}
 	w.mu.RUnlock()
 
@@ -179,16 +374,17 @@ func (w *Worker) PerformOperation(operation *orchestrator.Operation) (*orchestra
 		return nil, fmt.Errorf("%w: agent %s", domainerrors.ErrAgentAtCapacity, agentID)
 	}
 
+	requestID, _ := logger.RequestID(ctx)
+
 	// Пытаемся добавить операцию в очередь с таймаутом
 	select {
-	case w.operationsQueue <- operation:
+	case w.operationsQueue <- queuedOperation{op: operation, requestID: requestID}:
 		w.mu.Lock()
 		if w.agent != nil {
-			w.agent.CurrentLoad++
+			w.agent.CurrentLoad += w.costLocked(operation)
 		}
 
 		operationID := operation.ID.String()
-		ctx := context.Background()
 		ctxLogger := logger.ContextLogger(ctx, nil)
 		if ctxLogger != nil && w.agent != nil {
 			ctxLogger.Debug("Agent capacity updated",
@@ -219,10 +415,17 @@ func (w *Worker) GetStatus() *agent.Agent {
 
 	// Создаем копию для потокобезопасности
 	agentCopy := *w.agent
+	agentCopy.OperationCosts = maps.Clone(w.agent.OperationCosts)
 
 	// Обновляем динамические поля
 	agentCopy.UptimeSeconds = int64(time.Since(w.agent.StartedAt).Seconds())
 
+	// Сколько операций каждого типа агент еще может принять с учетом их стоимости
+	agentCopy.TypeCapacity = make(map[string]int, len(agentCopy.OperationCosts))
+	for operation := range agentCopy.OperationCosts {
+		agentCopy.TypeCapacity[operation] = agentCopy.RemainingCapacityFor(operation)
+	}
+
 	// Определяем актуальный статус на основе текущей нагрузки
 	if atomic.LoadInt32(&w.running) == 1 {
 		if agentCopy.CurrentLoad >= agentCopy.MaxCapacity {
@@ -237,6 +440,36 @@ func (w *Worker) GetStatus() *agent.Agent {
 	return &agentCopy
 }
 
+// SetCapacity меняет емкость работающего агента. Новая емкость учитывается при следующих
+// постановках операций в очередь; уже принятые операции выполняются как обычно, даже если
+// их суммарная стоимость превышает новую емкость. Емкость ограничена размером очереди воркера
+// и не может быть меньше стоимости какой-либо операции.
+func (w *Worker) SetCapacity(capacity int) error {
+	if w == nil {
+		return fmt.Errorf("worker is nil")
+	}
+
+	if limit := cap(w.operationsQueue); capacity <= 0 || capacity > limit {
+		return fmt.Errorf("%w: %d (must be in [1, %d])", domainerrors.ErrInvalidCapacity, capacity, limit)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.agent == nil {
+		return domainerrors.ErrNilWorkerStatus
+	}
+	// Емкость меньше стоимости операции не позволила бы агенту принять такую операцию
+	for operation, cost := range w.agent.OperationCosts {
+		if cost > capacity {
+			return fmt.Errorf("%w: %d (operation %s costs %d)", domainerrors.ErrInvalidCapacity, capacity, operation, cost)
+		}
+	}
+	w.agent.MaxCapacity = capacity
+
+	return nil
+}
+
 // UpdateStatus обновляет статус и нагрузку агента.
 // Отрицательная нагрузка будет скорректирована до нуля.
 func (w *Worker) UpdateStatus(status agent.AgentStatus, load int) {
@@ -285,12 +518,86 @@ func (w *Worker) CurrentLoad() int {
 	return w.agent.CurrentLoad
 }
 
+// QueueDepth возвращает количество операций, ожидающих в очереди воркера.
+func (w *Worker) QueueDepth() int {
+	if w == nil {
+		return 0
+	}
+	return len(w.operationsQueue)
+}
+
+// Timing возвращает фактическое время выполнения операций агентом в миллисекундах.
+// В детерминированном режиме задержка не имитируется, и все значения равны нулю.
+func (w *Worker) Timing() agent.AgentTiming {
+	if w == nil {
+		return agent.AgentTiming{OperationTimesMs: map[string]int64{}}
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	timing := agent.AgentTiming{
+		Deterministic:    w.deterministic,
+		OperationTimesMs: make(map[string]int64, len(w.operationTimes)),
+	}
+	if w.agent != nil {
+		timing.ID = w.agent.ID
+	}
+	for operation := range w.operationTimes {
+		if w.deterministic {
+			timing.OperationTimesMs[operation] = 0
+			continue
+		}
+		timing.OperationTimesMs[operation] = w.getOperationTime(operation).Milliseconds()
+	}
+
+	return timing
+}
+
+// startHeartbeat запускает периодическую отправку сигналов о работе агента
+// до завершения ctx или остановки агента.
+func (w *Worker) startHeartbeat(ctx context.Context) {
+	w.mu.RLock()
+	interval := w.heartbeat
+	w.mu.RUnlock()
+
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-w.stopCh:
+				return
+			case <-ticker.C:
+				w.mu.Lock()
+				if w.agent != nil {
+					w.agent.LastHeartbeat = time.Now()
+				}
+				w.mu.Unlock()
+			}
+		}
+	}()
+}
+
 // processOperations - основной цикл обработки операций из очереди.
 // Выполняется в отдельной горутине до получения сигнала остановки.
 func (w *Worker) processOperations(ctx context.Context) {
 	if w == nil || ctx == nil {
 		return
 	}
+	defer close(w.done)
+
+	// Сигналы о работе прекращаются вместе с циклом обработки
+	heartbeatCtx, stopHeartbeat := context.WithCancel(ctx)
+	defer stopHeartbeat()
+	w.startHeartbeat(heartbeatCtx)
 
 	var log *zap.Logger
 	agentID := "unknown"
@@ -319,7 +626,8 @@ func (w *Worker) processOperations(ctx context.Context) {
 				log.Debug("Stop signal received, stopping operation processing")
 			}
 			return
-		case op := <-w.operationsQueue:
+		case item := <-w.operationsQueue:
+			op := item.op
 			if op == nil {
 				if log != nil {
 					log.Warn("Received nil operation, skipping")
@@ -333,17 +641,51 @@ func (w *Worker) processOperations(ctx context.Context) {
 
 			opID := op.ID.String()
 
-			if log != nil {
-				log.Debug("Processing operation",
+			// Журналы выполнения несут идентификатор запроса, в рамках которого назначена операция
+			opCtx, opLog := ctx, log
+			if item.requestID != "" {
+				opCtx = logger.WithRequestID(ctx, item.requestID)
+				if log != nil {
+					opLog = log.With(zap.String(logger.RequestIDField, item.requestID))
+				}
+			}
+
+			// Остановленный агент не начинает новых операций, а возвращает их в ожидание
+			if atomic.LoadInt32(&w.running) == 0 {
+				w.requeue(context.WithoutCancel(opCtx), op)
+				continue
+			}
+
+			if opLog != nil {
+				opLog.Debug("Processing operation",
 					zap.String("operation_id", opID),
 					zap.Int("operation_type", int(op.OperationType)))
 			}
 
+			// Отмененные операции не выполняются, их статус уже записан при отмене
+			if w.isCancelled(opCtx, op.ID) {
+				w.releaseCancelled(op, opLog)
+				continue
+			}
+
 			var result string
 			var err error
 
-			// Выполняем операцию
-			result, err = w.executeOperation(ctx, op)
+			// Выполняем операцию в пределах ее срока
+			result, err = w.executeWithTimeout(opCtx, op)
+
+			// Операция могла быть отменена во время выполнения
+			if w.isCancelled(opCtx, op.ID) {
+				w.releaseCancelled(op, opLog)
+				continue
+			}
+
+			// Выполнение прервано остановкой агента, а не ошибкой вычисления:
+			// операция возвращается в ожидание и будет выполнена после перезапуска
+			if err != nil && ctx.Err() != nil {
+				w.requeue(context.WithoutCancel(opCtx), op)
+				continue
+			}
 
 			// Определяем статус операции после выполнения
 			opStatus := orchestrator.OperationStatusCompleted
@@ -353,10 +695,16 @@ func (w *Worker) processOperations(ctx context.Context) {
 				errMsg = err.Error()
 			}
 
+			w.mu.RLock()
+			batcher := w.statusBatcher
+			w.mu.RUnlock()
+
 			// Обновляем статус операции в репозитории
-			if w.operationRepo != nil {
-				if updateErr := w.operationRepo.UpdateStatus(ctx, op.ID, opStatus, result, errMsg); updateErr != nil && log != nil {
-					log.Error("Failed to update operation status",
+			if batcher != nil {
+				batcher.Add(orchestrator.OperationStatusUpdate{ID: op.ID, Status: opStatus, Result: result, ErrorMessage: errMsg})
+			} else if w.operationRepo != nil {
+				if updateErr := w.operationRepo.UpdateStatus(opCtx, op.ID, opStatus, result, errMsg); updateErr != nil && opLog != nil {
+					opLog.Error("Failed to update operation status",
 						zap.String("operation_id", opID),
 						zap.Error(updateErr))
 				}
@@ -365,12 +713,8 @@ func (w *Worker) processOperations(ctx context.Context) {
 			// Обновляем статистику агента
 			w.mu.Lock()
 			if w.agent != nil {
-				w.agent.CurrentLoad--
-				if w.agent.CurrentLoad < 0 {
-					w.agent.CurrentLoad = 0
-					if log != nil {
-						log.Warn("Corrected negative agent load", zap.String("agent_id", agentID))
-					}
+				if w.releaseLocked(op) && opLog != nil {
+					opLog.Warn("Corrected negative agent load", zap.String("agent_id", agentID))
 				}
 
 				w.agent.LastOperationAt = time.Now()
@@ -382,15 +726,18 @@ func (w *Worker) processOperations(ctx context.Context) {
 					w.agent.OperationsStats.Completed++
 				}
 			}
+			if w.counters != nil {
+				w.counters.AddResult(err != nil)
+			}
 			w.mu.Unlock()
 
 			// Логируем результат выполнения
-			if err != nil && log != nil {
-				log.Error("Failed to execute operation",
+			if err != nil && opLog != nil {
+				opLog.Error("Failed to execute operation",
 					zap.String("operation_id", opID),
 					zap.Error(err))
-			} else if log != nil {
-				log.Debug("Operation executed successfully",
+			} else if opLog != nil {
+				opLog.Debug("Operation executed successfully",
 					zap.String("operation_id", opID),
 					zap.String("result", result))
 			}
@@ -398,60 +745,136 @@ func (w *Worker) processOperations(ctx context.Context) {
 	}
 }
 
-// resolveReference разрешает ссылки на результаты других операций.
-// Поддерживает формат "ref:UUID" для получения результата предыдущей операции.
-func (w *Worker) resolveReference(ctx context.Context, refStr string, log *zap.Logger) (string, error) {
-	if w == nil || ctx == nil {
-		return "", fmt.Errorf("worker or context is nil")
+// releaseLocked освобождает емкость агента, занятую операцией. Вызывается под w.mu.
+// Возвращает true, если нагрузка ушла в минус и была скорректирована до нуля.
+func (w *Worker) releaseLocked(op *orchestrator.Operation) bool {
+	if w.agent == nil {
+		return false
 	}
 
-	refID := strings.TrimPrefix(refStr, "ref:")
+	w.agent.CurrentLoad -= w.costLocked(op)
+	if w.agent.CurrentLoad < 0 {
+		w.agent.CurrentLoad = 0
+		return true
+	}
+	return false
+}
 
-	if w.operationRepo == nil {
-		return "", domainerrors.ErrRepoNotInitialized
+// releaseCancelled освобождает емкость агента, занятую отмененной операцией.
+func (w *Worker) releaseCancelled(op *orchestrator.Operation, log *zap.Logger) {
+	w.mu.Lock()
+	w.releaseLocked(op)
+	w.mu.Unlock()
+
+	if log != nil {
+		log.Debug("Skipping cancelled operation", zap.String("operation_id", op.ID.String()))
 	}
+}
 
-	// Парсим UUID из ссылки
-	uid, err := uuid.Parse(refID)
-	if err != nil {
-		if log != nil {
-			log.Error("Failed to parse reference ID",
-				zap.String("ref_id", refID), zap.Error(err))
-		}
-		return "", fmt.Errorf("%w: %s", domainerrors.ErrInvalidReferenceID, refID)
+// RequeueQueued забирает из очереди операции, которые агент еще не начал выполнять,
+// и возвращает их в статус PENDING, чтобы их подхватил следующий запуск.
+// Возвращает количество возвращенных операций.
+func (w *Worker) RequeueQueued(ctx context.Context) int {
+	if w == nil {
+		return 0
 	}
 
-	// Ищем связанную операцию в репозитории
-	refOp, err := w.operationRepo.FindByID(ctx, uid)
-	if err != nil {
-		if log != nil {
-			log.Error("Failed to lookup referenced operation",
-				zap.String("ref_id", refID), zap.Error(err))
+	requeued := 0
+	for {
+		select {
+		case item := <-w.operationsQueue:
+			if item.op == nil {
+				continue
+			}
+			w.requeue(ctx, item.op)
+			requeued++
+		default:
+			return requeued
 		}
-		return "", fmt.Errorf("reference lookup failed: %w", err)
 	}
+}
 
-	if refOp == nil {
-		return "", fmt.Errorf("%w: %s", domainerrors.ErrReferenceNotFound, refID)
+// requeue освобождает емкость агента и возвращает операцию в статус PENDING.
+func (w *Worker) requeue(ctx context.Context, op *orchestrator.Operation) {
+	w.mu.Lock()
+	w.releaseLocked(op)
+	w.mu.Unlock()
+
+	if w.operationRepo == nil {
+		return
 	}
 
-	// Проверяем, что связанная операция завершена успешно
-	if refOp.Status != orchestrator.OperationStatusCompleted {
-		return "", fmt.Errorf("%w: %s", domainerrors.ErrRefNotCompleted, refID)
+	if err := w.operationRepo.UpdateStatus(ctx, op.ID, orchestrator.OperationStatusPending, "", ""); err != nil {
+		logger.ContextLogger(ctx, nil).Warn("Failed to requeue operation",
+			zap.String("operation_id", op.ID.String()),
+			zap.Error(err))
+	}
+}
+
+// resolveReference разрешает ссылки на результаты других операций.
+// Поддерживает формат "ref:UUID" для получения результата предыдущей операции.
+func (w *Worker) resolveReference(ctx context.Context, refStr string, log *zap.Logger) (string, error) {
+	if w == nil || ctx == nil {
+		return "", fmt.Errorf("worker or context is nil")
+	}
+
+	result, err := ResolveOperand(ctx, w.operationRepo, refStr)
+	if err != nil {
+		if log != nil {
+			log.Error("Failed to resolve operation reference",
+				zap.String("ref", refStr), zap.Error(err))
+		}
+		return "", err
 	}
 
 	if log != nil {
 		log.Debug("Resolved operation reference",
-			zap.String("ref_id", refID),
-			zap.String("result", refOp.Result))
+			zap.String("ref", refStr),
+			zap.String("result", result))
+	}
+
+	return result, nil
+}
+
+// executeWithTimeout выполняет операцию, ограничивая время выполнения сроком для ее типа.
+// Операция, не уложившаяся в срок, завершается ошибкой domainerrors.ErrOperationTimeout,
+// чтобы клиенты могли отличить ее от ошибки вычисления.
+func (w *Worker) executeWithTimeout(ctx context.Context, op *orchestrator.Operation) (string, error) {
+	timeout := w.getOperationTimeout(op.OperationType.Name())
+	if timeout <= 0 {
+		return w.executeOperation(ctx, op)
 	}
 
-	return refOp.Result, nil
+	execCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result, err := w.executeOperation(execCtx, op)
+	if err != nil && ctx.Err() == nil && errors.Is(execCtx.Err(), context.DeadlineExceeded) {
+		return "", domainerrors.ErrOperationTimeout
+	}
+	return result, err
 }
 
 // executeOperation выполняет конкретную математическую операцию.
 // Поддерживает базовые операции: сложение, вычитание, умножение и деление.
+// Вычисления ведутся в float64 или в десятичном представлении, см. SetArithmetic.
 func (w *Worker) executeOperation(ctx context.Context, op *orchestrator.Operation) (string, error) {
+	ctx, span := tracing.Start(ctx, spanExecuteOperation)
+	defer span.End()
+
+	result, err := w.computeOperation(ctx, op)
+	if op != nil {
+		span.SetAttributes(
+			attribute.String(tracing.AttrOperationID, op.ID.String()),
+			attribute.String(tracing.AttrCalculationID, op.CalculationID.String()),
+			attribute.String(tracing.AttrOperationType, op.OperationType.Name()),
+		)
+	}
+	tracing.RecordError(span, err)
+	return result, err
+}
+
+func (w *Worker) computeOperation(ctx context.Context, op *orchestrator.Operation) (string, error) {
 	if w == nil || ctx == nil {
 		return "", fmt.Errorf("worker or context is nil")
 	}
@@ -501,71 +924,47 @@ func (w *Worker) executeOperation(ctx context.Context, op *orchestrator.Operatio
 		}
 	}
 
-	// Преобразуем строковые операнды в числа
-	operand1, err := strconv.ParseFloat(operand1Str, 64)
-	if err != nil {
-		return "", fmt.Errorf("%w: %s", domainerrors.ErrInvalidOperand, operand1Str)
+	if zapLog != nil {
+		zapLog.Debug("Performing operation",
+			zap.String("operation", op.OperationType.Name()),
+			zap.String("operand1", operand1Str),
+			zap.String("operand2", operand2Str))
 	}
 
-	operand2, err := strconv.ParseFloat(operand2Str, 64)
+	result, err := Compute(op.OperationType, operand1Str, operand2Str, w.getArithmetic())
 	if err != nil {
-		return "", fmt.Errorf("%w: %s", domainerrors.ErrInvalidOperand, operand2Str)
+		return "", err
 	}
 
-	var operationTime time.Duration
-	var result float64
+	if err := w.emulateOperationTime(ctx, w.getOperationTime(op.OperationType.Name())); err != nil {
+		return "", err
+	}
 
-	// Выполняем математическую операцию в зависимости от типа
-	switch op.OperationType {
-	case orchestrator.OperationTypeAddition:
-		if zapLog != nil {
-			zapLog.Debug("Performing addition",
-				zap.Float64("operand1", operand1),
-				zap.Float64("operand2", operand2))
-		}
-		operationTime = w.getOperationTime("addition")
-		result = operand1 + operand2
-	case orchestrator.OperationTypeSubtraction:
-		if zapLog != nil {
-			zapLog.Debug("Performing subtraction",
-				zap.Float64("operand1", operand1),
-				zap.Float64("operand2", operand2))
-		}
-		operationTime = w.getOperationTime("subtraction")
-		result = operand1 - operand2
-	case orchestrator.OperationTypeMultiplication:
-		if zapLog != nil {
-			zapLog.Debug("Performing multiplication",
-				zap.Float64("operand1", operand1),
-				zap.Float64("operand2", operand2))
-		}
-		operationTime = w.getOperationTime("multiplication")
-		result = operand1 * operand2
-	case orchestrator.OperationTypeDivision:
-		if zapLog != nil {
-			zapLog.Debug("Performing division",
-				zap.Float64("operand1", operand1),
-				zap.Float64("operand2", operand2))
-		}
-		operationTime = w.getOperationTime("division")
+	return result, nil
+}
 
-		if operand2 == 0 {
-			return "", domainerrors.ErrDivisionByZero
+// emulateOperationTime эмулирует время выполнения операции; в детерминированном режиме задержки нет.
+func (w *Worker) emulateOperationTime(ctx context.Context, operationTime time.Duration) error {
+	if w.isDeterministic() {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("%w: %w", domainerrors.ErrContextCanceled, err)
 		}
-
-		result = operand1 / operand2
-	default:
-		return "", fmt.Errorf("%w: %d", domainerrors.ErrUnsupportedOp, op.OperationType)
+		return nil
 	}
 
-	// Эмулируем время выполнения операции
 	select {
 	case <-ctx.Done():
-		return "", fmt.Errorf("%w: %w", domainerrors.ErrContextCanceled, ctx.Err())
+		return fmt.Errorf("%w: %w", domainerrors.ErrContextCanceled, ctx.Err())
 	case <-time.After(operationTime):
+		return nil
 	}
+}
 
-	return formatNumericResult(result), nil
+// getArithmetic возвращает представление чисел, в котором выполняются операции.
+func (w *Worker) getArithmetic() orchestrator.Arithmetic {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.arithmetic
 }
 
 // getOperationTime возвращает время выполнения операции указанного типа.
@@ -582,12 +981,18 @@ func (w *Worker) getOperationTime(operation string) time.Duration {
 	return time.Second
 }
 
-// formatNumericResult форматирует числовой результат в удобочитаемую строку.
-// Если результат целочисленный, убирает десятичную часть.
-func formatNumericResult(result float64) string {
-	if result == math.Trunc(result) {
-		return fmt.Sprintf("%.0f", result)
-	}
+// getOperationTimeout возвращает срок выполнения операции указанного типа; ноль - без срока.
+func (w *Worker) getOperationTimeout(operation string) time.Duration {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.timeouts[operation]
+}
 
-	return strconv.FormatFloat(result, 'f', -1, 64)
+// formatNumericResult форматирует числовой результат в удобочитаемую строку без
+// округления: результаты операций служат операндами следующих, а округляется только
+// результат вычисления. Если результат целочисленный, убирает десятичную часть.
+// Бесконечность и NaN, полученные делением на ноль или переполнением, записываются
+// как inf, -inf и nan.
+func formatNumericResult(result float64) string {
+	return orchestrator.NoRounding.Format(result)
 }
@@ -9,10 +9,19 @@ import (
 	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/agent"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/tracing"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 type MockOperationRepository struct {
@@ -32,6 +41,14 @@ func (m *MockOperationRepository) CreateBatch(ctx context.Context, operations []
 	return args.Error(0)
 }
 
+func (m *MockOperationRepository) CreateBatchPartial(ctx context.Context, operations []*orchestrator.Operation) ([]orchestrator.OperationInsertResult, error) {
+	args := m.Called(ctx, operations)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]orchestrator.OperationInsertResult), args.Error(1)
+}
+
 func (m *MockOperationRepository) FindByID(ctx context.Context, id uuid.UUID) (*orchestrator.Operation, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -66,13 +83,40 @@ func (m *MockOperationRepository) UpdateStatus(ctx context.Context, id uuid.UUID
 	return args.Error(0)
 }
 
+func (m *MockOperationRepository) UpdateStatusBatch(ctx context.Context, updates []orchestrator.OperationStatusUpdate) error {
+	args := m.Called(ctx, updates)
+	return args.Error(0)
+}
+
 func (m *MockOperationRepository) AssignAgent(ctx context.Context, operationID uuid.UUID, agentID string) error {
 	args := m.Called(ctx, operationID, agentID)
 	return args.Error(0)
 }
 
+func (m *MockOperationRepository) CountByStatus(ctx context.Context, status orchestrator.OperationStatus) (int, error) {
+	args := m.Called(ctx, status)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockOperationRepository) CountPendingByUserID(ctx context.Context, userID uuid.UUID) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockOperationRepository) DeleteByCalculationID(ctx context.Context, calculationID uuid.UUID) (int, error) {
+	args := m.Called(ctx, calculationID)
+	return args.Int(0), args.Error(1)
+}
+
+// expectNotCancelled разрешает воркеру проверять сохраненный статус операции
+// перед выполнением: репозиторий сообщает, что операция не отменена.
+func expectNotCancelled(repo *MockOperationRepository) {
+	repo.On("FindByID", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+}
+
 func TestStartStop(t *testing.T) {
 	repo := new(MockOperationRepository)
+	expectNotCancelled(repo)
 	w, err := NewWorker("agent-test", 3, nil, repo)
 	require.NoError(t, err)
 
@@ -182,6 +226,7 @@ func TestPerformOperation(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			repo := new(MockOperationRepository)
+			expectNotCancelled(repo)
 			w, err := NewWorker("agent-test", tc.maxCapacity, nil, repo)
 			require.NoError(t, err)
 
@@ -193,7 +238,7 @@ func TestPerformOperation(t *testing.T) {
 
 			w.UpdateStatus(tc.agentStatus, tc.currentLoad)
 
-			result, err := w.PerformOperation(tc.operation)
+			result, err := w.PerformOperation(context.Background(), tc.operation)
 
 			if tc.expectError {
 				assert.Error(t, err)
@@ -253,6 +298,7 @@ func TestGetStatus(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			repo := new(MockOperationRepository)
+			expectNotCancelled(repo)
 			w, err := NewWorker("agent-test", tc.maxCapacity, nil, repo)
 			require.NoError(t, err)
 
@@ -277,6 +323,143 @@ func TestGetStatus(t *testing.T) {
 	}
 }
 
+func TestGetStatusTypeCapacity(t *testing.T) {
+	tests := []struct {
+		name        string
+		currentLoad int
+		expected    map[string]int
+	}{
+		{
+			name:        "Idle agent",
+			currentLoad: 0,
+			expected:    map[string]int{"addition": 6, "subtraction": 6, "multiplication": 3, "division": 2, "modulo": 2, "factorial": 6},
+		},
+		{
+			name:        "Partially loaded agent",
+			currentLoad: 2,
+			expected:    map[string]int{"addition": 4, "subtraction": 4, "multiplication": 2, "division": 1, "modulo": 1, "factorial": 4},
+		},
+		{
+			name:        "Remaining capacity below operation cost",
+			currentLoad: 5,
+			expected:    map[string]int{"addition": 1, "subtraction": 1, "multiplication": 0, "division": 0, "modulo": 0, "factorial": 1},
+		},
+		{
+			name:        "Overloaded agent",
+			currentLoad: 7,
+			expected:    map[string]int{"addition": 0, "subtraction": 0, "multiplication": 0, "division": 0, "modulo": 0, "factorial": 0},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w, err := NewWorker("agent-test", 6, nil, new(MockOperationRepository))
+			require.NoError(t, err)
+
+			w.SetOperationCosts(map[string]int{"multiplication": 2, "division": 3, "modulo": 3, "addition": 0})
+
+			w.mu.Lock()
+			w.agent.CurrentLoad = tc.currentLoad
+			w.mu.Unlock()
+
+			status := w.GetStatus()
+
+			assert.Equal(t, tc.expected, status.TypeCapacity)
+			assert.Equal(t, 1, status.OperationCosts["addition"], "non-positive cost must be ignored")
+		})
+	}
+}
+
+func TestPerformOperationWeightedLoad(t *testing.T) {
+	repo := new(MockOperationRepository)
+	expectNotCancelled(repo)
+	repo.On("UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	w, err := NewWorker("agent-test", 4, map[string]time.Duration{
+		"addition":       time.Second,
+		"multiplication": time.Second,
+		"division":       time.Second,
+	}, repo)
+	require.NoError(t, err)
+	w.SetOperationCosts(map[string]int{"multiplication": 2, "division": 3})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w.Start(ctx)
+	defer w.Stop()
+
+	_, err = w.PerformOperation(ctx, &orchestrator.Operation{
+		ID: uuid.New(), OperationType: orchestrator.OperationTypeMultiplication, Operand1: "2", Operand2: "3",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, w.CurrentLoad())
+
+	status := w.GetStatus()
+	assert.Equal(t, 2, status.TypeCapacity["addition"])
+	assert.Equal(t, 0, status.TypeCapacity["division"])
+
+	_, err = w.PerformOperation(ctx, &orchestrator.Operation{
+		ID: uuid.New(), OperationType: orchestrator.OperationTypeDivision, Operand1: "6", Operand2: "3",
+	})
+	assert.ErrorIs(t, err, domainerrors.ErrAgentAtCapacity)
+
+	_, err = w.PerformOperation(ctx, &orchestrator.Operation{
+		ID: uuid.New(), OperationType: orchestrator.OperationTypeAddition, Operand1: "1", Operand2: "2",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, w.CurrentLoad())
+}
+
+func TestSetCapacity(t *testing.T) {
+	repo := new(MockOperationRepository)
+	expectNotCancelled(repo)
+	repo.On("UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	w, err := NewWorker("agent-test", 3, map[string]time.Duration{"addition": time.Second}, repo)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w.Start(ctx)
+	defer w.Stop()
+
+	addition := func() error {
+		_, err := w.PerformOperation(ctx, &orchestrator.Operation{
+			ID: uuid.New(), OperationType: orchestrator.OperationTypeAddition, Operand1: "1", Operand2: "2",
+		})
+		return err
+	}
+
+	t.Run("Lowered capacity rejects next operation", func(t *testing.T) {
+		require.NoError(t, w.SetCapacity(1))
+		assert.Equal(t, 1, w.GetStatus().MaxCapacity)
+
+		require.NoError(t, addition())
+		assert.ErrorIs(t, addition(), domainerrors.ErrAgentAtCapacity)
+	})
+
+	t.Run("Raised capacity accepts next operation", func(t *testing.T) {
+		require.NoError(t, w.SetCapacity(2))
+
+		require.NoError(t, addition())
+		assert.Equal(t, 2, w.CurrentLoad())
+	})
+
+	t.Run("Invalid capacity", func(t *testing.T) {
+		for _, capacity := range []int{0, -1, 7} {
+			assert.ErrorIs(t, w.SetCapacity(capacity), domainerrors.ErrInvalidCapacity, "capacity %d", capacity)
+		}
+		assert.Equal(t, 2, w.GetStatus().MaxCapacity)
+	})
+
+	t.Run("Capacity below operation cost", func(t *testing.T) {
+		w.SetOperationCosts(map[string]int{"division": 2})
+
+		assert.ErrorIs(t, w.SetCapacity(1), domainerrors.ErrInvalidCapacity)
+		assert.Equal(t, 2, w.GetStatus().MaxCapacity)
+	})
+}
+
 func TestUpdateStatus(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -400,6 +583,40 @@ func TestExecuteOperation(t *testing.T) {
 			expectError:     true,
 			expectedErrorIs: domainerrors.ErrDivisionByZero,
 		},
+		{
+			name: "Integer modulo operation",
+			operation: &orchestrator.Operation{
+				ID:            uuid.New(),
+				OperationType: orchestrator.OperationTypeModulo,
+				Operand1:      "7",
+				Operand2:      "3",
+			},
+			expectedResult: "1",
+			expectError:    false,
+		},
+		{
+			name: "Floating modulo operation",
+			operation: &orchestrator.Operation{
+				ID:            uuid.New(),
+				OperationType: orchestrator.OperationTypeModulo,
+				Operand1:      "7.5",
+				Operand2:      "2",
+			},
+			expectedResult: "1.5",
+			expectError:    false,
+		},
+		{
+			name: "Modulo by zero",
+			operation: &orchestrator.Operation{
+				ID:            uuid.New(),
+				OperationType: orchestrator.OperationTypeModulo,
+				Operand1:      "5",
+				Operand2:      "0",
+			},
+			expectedResult:  "",
+			expectError:     true,
+			expectedErrorIs: domainerrors.ErrDivisionByZero,
+		},
 		{
 			name: "Invalid operand",
 			operation: &orchestrator.Operation{
@@ -492,6 +709,97 @@ func TestExecuteOperation(t *testing.T) {
 	}
 }
 
+func TestExecuteOperationDeterministic(t *testing.T) {
+	const slowOperation = 10 * time.Second
+
+	operationTimes := map[string]time.Duration{
+		"addition":       slowOperation,
+		"subtraction":    slowOperation,
+		"multiplication": slowOperation,
+		"division":       slowOperation,
+		"modulo":         slowOperation,
+	}
+
+	tests := []struct {
+		name           string
+		operationType  orchestrator.OperationType
+		expectedResult string
+	}{
+		{name: "Addition", operationType: orchestrator.OperationTypeAddition, expectedResult: "9"},
+		{name: "Subtraction", operationType: orchestrator.OperationTypeSubtraction, expectedResult: "3"},
+		{name: "Multiplication", operationType: orchestrator.OperationTypeMultiplication, expectedResult: "18"},
+		{name: "Division", operationType: orchestrator.OperationTypeDivision, expectedResult: "2"},
+		{name: "Modulo", operationType: orchestrator.OperationTypeModulo, expectedResult: "0"},
+	}
+
+	w, err := NewWorker("agent-test", 3, operationTimes, new(MockOperationRepository))
+	require.NoError(t, err)
+	w.SetDeterministic(true)
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			start := time.Now()
+			result, err := w.executeOperation(context.Background(), &orchestrator.Operation{
+				ID:            uuid.New(),
+				OperationType: tc.operationType,
+				Operand1:      "6",
+				Operand2:      "3",
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedResult, result)
+			assert.Less(t, time.Since(start), 100*time.Millisecond)
+		})
+	}
+
+	t.Run("Cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := w.executeOperation(ctx, &orchestrator.Operation{
+			ID:            uuid.New(),
+			OperationType: orchestrator.OperationTypeAddition,
+			Operand1:      "1",
+			Operand2:      "2",
+		})
+		assert.ErrorIs(t, err, domainerrors.ErrContextCanceled)
+	})
+
+	t.Run("Completes queued operation without delay", func(t *testing.T) {
+		repo := new(MockOperationRepository)
+		expectNotCancelled(repo)
+		completed := make(chan string, 1)
+		opID := uuid.New()
+		repo.On("UpdateStatus", mock.Anything, opID, orchestrator.OperationStatusCompleted, mock.Anything, "").
+			Run(func(args mock.Arguments) { completed <- args.String(3) }).
+			Return(nil)
+
+		queued, err := NewWorker("agent-queue", 3, operationTimes, repo)
+		require.NoError(t, err)
+		queued.SetDeterministic(true)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		queued.Start(ctx)
+		defer queued.Stop()
+
+		_, err = queued.PerformOperation(ctx, &orchestrator.Operation{
+			ID:            opID,
+			OperationType: orchestrator.OperationTypeMultiplication,
+			Operand1:      "4",
+			Operand2:      "5",
+		})
+		require.NoError(t, err)
+
+		select {
+		case result := <-completed:
+			assert.Equal(t, "20", result)
+		case <-time.After(time.Second):
+			t.Fatal("operation was not completed without delay")
+		}
+	})
+}
+
 func TestFormatNumericResult(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -528,6 +836,21 @@ func TestFormatNumericResult(t *testing.T) {
 			input:          -5.5,
 			expectedOutput: "-5.5",
 		},
+		{
+			name:           "Very large integer stays in fixed notation",
+			input:          1e21,
+			expectedOutput: "1000000000000000000000",
+		},
+		{
+			name:           "Very small decimal stays in fixed notation",
+			input:          1.5e-10,
+			expectedOutput: "0.00000000015",
+		},
+		{
+			name:           "Negative small decimal",
+			input:          -2e-7,
+			expectedOutput: "-0.0000002",
+		},
 		{
 			name:           "Zero",
 			input:          0.0,
@@ -543,8 +866,106 @@ func TestFormatNumericResult(t *testing.T) {
 	}
 }
 
+func TestExecuteOperationKeepsPrecision(t *testing.T) {
+	w, err := NewWorker("agent-test", 3, nil, new(MockOperationRepository))
+	require.NoError(t, err)
+	w.SetDeterministic(true)
+
+	divide := func(operand1, operand2 string) string {
+		t.Helper()
+		result, err := w.executeOperation(context.Background(), &orchestrator.Operation{
+			ID:            uuid.New(),
+			OperationType: orchestrator.OperationTypeDivision,
+			Operand1:      operand1,
+			Operand2:      operand2,
+		})
+		require.NoError(t, err)
+		return result
+	}
+
+	// Результаты операций не округляются: они служат операндами следующих операций
+	assert.Equal(t, "0.3333333333333333", divide("1", "3"))
+	assert.Equal(t, "-0.6666666666666666", divide("-2", "3"))
+	assert.Equal(t, "4", divide("8", "2"))
+}
+
+func TestExecuteOperationScientificNotation(t *testing.T) {
+	w, err := NewWorker("agent-test", 3, nil, new(MockOperationRepository))
+	require.NoError(t, err)
+	w.SetDeterministic(true)
+
+	execute := func(operationType orchestrator.OperationType, operand1, operand2 string) string {
+		t.Helper()
+		result, err := w.executeOperation(context.Background(), &orchestrator.Operation{
+			ID:            uuid.New(),
+			OperationType: operationType,
+			Operand1:      operand1,
+			Operand2:      operand2,
+		})
+		require.NoError(t, err)
+		return result
+	}
+
+	for _, arithmetic := range []orchestrator.Arithmetic{
+		{Backend: orchestrator.ArithmeticFloat},
+		{Backend: orchestrator.ArithmeticDecimal, DivisionPrecision: 16},
+	} {
+		t.Run(string(arithmetic.Backend), func(t *testing.T) {
+			w.SetArithmetic(arithmetic)
+
+			assert.Equal(t, "0.003", execute(orchestrator.OperationTypeMultiplication, "1.5e-3", "2"))
+			assert.Equal(t, "1001", execute(orchestrator.OperationTypeAddition, "1e3", "1"))
+			assert.Equal(t, "-2500", execute(orchestrator.OperationTypeSubtraction, "-2E3", "5e+2"))
+			assert.Equal(t, "0.00000025", execute(orchestrator.OperationTypeDivision, "5e-7", "2"))
+			assert.Equal(t, "100000000000000000000", execute(orchestrator.OperationTypeMultiplication, "1e10", "1e10"))
+		})
+	}
+}
+
+func TestExecuteOperationTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	w, err := NewWorker("agent-test", 1, nil, new(MockOperationRepository))
+	require.NoError(t, err)
+	w.SetDeterministic(true)
+
+	op := &orchestrator.Operation{
+		ID:            uuid.New(),
+		CalculationID: uuid.New(),
+		OperationType: orchestrator.OperationTypeDivision,
+		Operand1:      "1",
+		Operand2:      "0",
+	}
+
+	ctx, root := provider.Tracer("test").Start(context.Background(), "processOperations")
+	_, err = w.executeOperation(ctx, op)
+	root.End()
+	require.Error(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+
+	opSpan := spans[0]
+	assert.Equal(t, spanExecuteOperation, opSpan.Name)
+	assert.Equal(t, spans[1].SpanContext.SpanID(), opSpan.Parent.SpanID())
+	assert.Equal(t, codes.Error, opSpan.Status.Code)
+
+	attrs := make(map[attribute.Key]string)
+	for _, attr := range opSpan.Attributes {
+		attrs[attr.Key] = attr.Value.Emit()
+	}
+	assert.Equal(t, op.ID.String(), attrs[tracing.AttrOperationID])
+	assert.Equal(t, op.CalculationID.String(), attrs[tracing.AttrCalculationID])
+	assert.Equal(t, "division", attrs[tracing.AttrOperationType])
+}
+
 func TestIsRunningAndCurrentLoad(t *testing.T) {
 	repo := new(MockOperationRepository)
+	expectNotCancelled(repo)
 	w, err := NewWorker("agent-test", 3, nil, repo)
 	require.NoError(t, err)
 
@@ -577,3 +998,490 @@ func TestIsRunningAndCurrentLoad(t *testing.T) {
 		assert.Equal(t, 0, w.CurrentLoad())
 	})
 }
+
+func TestExecuteOperationFactorial(t *testing.T) {
+	floatWorker, err := NewWorker("agent-float", 3, nil, new(MockOperationRepository))
+	require.NoError(t, err)
+	floatWorker.SetDeterministic(true)
+
+	decimalWorker, err := NewWorker("agent-decimal", 3, nil, new(MockOperationRepository))
+	require.NoError(t, err)
+	decimalWorker.SetDeterministic(true)
+	decimalWorker.SetArithmetic(orchestrator.Arithmetic{Backend: orchestrator.ArithmeticDecimal})
+
+	testCases := []struct {
+		name           string
+		operand        string
+		expectedResult string
+		expectedErr    error
+	}{
+		{name: "Factorial of five", operand: "5", expectedResult: "120"},
+		{name: "Factorial of zero", operand: "0", expectedResult: "1"},
+		{name: "Factorial exact in float64", operand: "20", expectedResult: "2432902008176640000"},
+		{name: "Overflow", operand: "171", expectedErr: domainerrors.ErrNumericOverflow},
+		{name: "Negative operand", operand: "-3", expectedErr: domainerrors.ErrFactorialOperand},
+		{name: "Fractional operand", operand: "2.5", expectedErr: domainerrors.ErrFactorialOperand},
+	}
+
+	for _, tc := range testCases {
+		for _, w := range []*Worker{floatWorker, decimalWorker} {
+			t.Run(tc.name+"/"+w.agent.ID, func(t *testing.T) {
+				result, err := w.executeOperation(context.Background(), &orchestrator.Operation{
+					ID:            uuid.New(),
+					OperationType: orchestrator.OperationTypeFactorial,
+					Operand1:      tc.operand,
+					Operand2:      "0",
+				})
+				if tc.expectedErr != nil {
+					require.ErrorIs(t, err, tc.expectedErr)
+					assert.Empty(t, result)
+					return
+				}
+
+				require.NoError(t, err)
+				assert.Equal(t, tc.expectedResult, result)
+			})
+		}
+	}
+}
+
+func TestExecuteOperationDecimal(t *testing.T) {
+	floatWorker, err := NewWorker("agent-float", 3, nil, new(MockOperationRepository))
+	require.NoError(t, err)
+	floatWorker.SetDeterministic(true)
+
+	decimalWorker, err := NewWorker("agent-decimal", 3, nil, new(MockOperationRepository))
+	require.NoError(t, err)
+	decimalWorker.SetDeterministic(true)
+	decimalWorker.SetArithmetic(orchestrator.Arithmetic{Backend: orchestrator.ArithmeticDecimal})
+
+	execute := func(w *Worker, opType orchestrator.OperationType, operand1, operand2 string) (string, error) {
+		return w.executeOperation(context.Background(), &orchestrator.Operation{
+			ID:            uuid.New(),
+			OperationType: opType,
+			Operand1:      operand1,
+			Operand2:      operand2,
+		})
+	}
+
+	testCases := []struct {
+		name          string
+		opType        orchestrator.OperationType
+		operand1      string
+		operand2      string
+		floatResult   string
+		decimalResult string
+	}{
+		{"Decimal fractions addition", orchestrator.OperationTypeAddition, "0.1", "0.2", "0.30000000000000004", "0.3"},
+		{"Decimal fractions subtraction", orchestrator.OperationTypeSubtraction, "0.3", "0.1", "0.19999999999999998", "0.2"},
+		{"Decimal fractions multiplication", orchestrator.OperationTypeMultiplication, "1.1", "1.1", "1.2100000000000002", "1.21"},
+		{"Large integer beyond float64 precision", orchestrator.OperationTypeAddition, "9007199254740993", "0", "9007199254740992", "9007199254740993"},
+		{"Modulo of fractions", orchestrator.OperationTypeModulo, "5.5", "2.2", "1.0999999999999996", "1.1"},
+		{"Trailing zeros are trimmed", orchestrator.OperationTypeMultiplication, "1.50", "2", "3", "3"},
+		{"Division uses configured precision", orchestrator.OperationTypeDivision, "2", "3", "0.6666666666666666", "0.6666666666666667"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			floatResult, err := execute(floatWorker, tc.opType, tc.operand1, tc.operand2)
+			require.NoError(t, err)
+			assert.Equal(t, tc.floatResult, floatResult)
+
+			decimalResult, err := execute(decimalWorker, tc.opType, tc.operand1, tc.operand2)
+			require.NoError(t, err)
+			assert.Equal(t, tc.decimalResult, decimalResult)
+		})
+	}
+
+	t.Run("Division by zero", func(t *testing.T) {
+		_, err := execute(decimalWorker, orchestrator.OperationTypeDivision, "1", "0.0")
+		require.ErrorIs(t, err, domainerrors.ErrDivisionByZero)
+
+		_, err = execute(decimalWorker, orchestrator.OperationTypeModulo, "1", "0")
+		require.ErrorIs(t, err, domainerrors.ErrDivisionByZero)
+	})
+
+	t.Run("Invalid operand", func(t *testing.T) {
+		_, err := execute(decimalWorker, orchestrator.OperationTypeAddition, "+Inf", "1")
+		require.ErrorIs(t, err, domainerrors.ErrInvalidOperand)
+	})
+
+	t.Run("Invalid settings fall back to defaults", func(t *testing.T) {
+		w, err := NewWorker("agent-fallback", 3, nil, new(MockOperationRepository))
+		require.NoError(t, err)
+
+		w.SetArithmetic(orchestrator.Arithmetic{Backend: "bigfloat", DivisionPrecision: -1})
+		assert.Equal(t, orchestrator.FloatArithmetic, w.getArithmetic())
+	})
+}
+
+func TestExecuteOperationDecimalScale(t *testing.T) {
+	newDecimalWorker := func(preserveScale bool) *Worker {
+		w, err := NewWorker("agent-decimal", 3, nil, new(MockOperationRepository))
+		require.NoError(t, err)
+		w.SetDeterministic(true)
+		w.SetArithmetic(orchestrator.Arithmetic{Backend: orchestrator.ArithmeticDecimal, PreserveScale: preserveScale})
+		return w
+	}
+	preserving, trimming := newDecimalWorker(true), newDecimalWorker(false)
+
+	execute := func(w *Worker, opType orchestrator.OperationType, operand1, operand2 string) string {
+		result, err := w.executeOperation(context.Background(), &orchestrator.Operation{
+			ID:            uuid.New(),
+			OperationType: opType,
+			Operand1:      operand1,
+			Operand2:      operand2,
+		})
+		require.NoError(t, err)
+		return result
+	}
+
+	testCases := []struct {
+		name      string
+		opType    orchestrator.OperationType
+		operand1  string
+		operand2  string
+		preserved string
+		trimmed   string
+	}{
+		{"Addition keeps two places", orchestrator.OperationTypeAddition, "2.50", "2.50", "5.00", "5"},
+		{"Addition takes the larger scale", orchestrator.OperationTypeAddition, "1.5", "2.25", "3.75", "3.75"},
+		{"Addition pads to the larger scale", orchestrator.OperationTypeAddition, "1.10", "2", "3.10", "3.1"},
+		{"Subtraction to zero", orchestrator.OperationTypeSubtraction, "2.50", "2.50", "0.00", "0"},
+		{"Multiplication sums scales", orchestrator.OperationTypeMultiplication, "1.50", "2.0", "3.000", "3"},
+		{"Integers stay integers", orchestrator.OperationTypeAddition, "2", "3", "5", "5"},
+		{"Division keeps significant digits", orchestrator.OperationTypeDivision, "1.00", "8", "0.125", "0.125"},
+		{"Division pads to operand scale", orchestrator.OperationTypeDivision, "5.00", "2", "2.50", "2.5"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.preserved, execute(preserving, tc.opType, tc.operand1, tc.operand2))
+			assert.Equal(t, tc.trimmed, execute(trimming, tc.opType, tc.operand1, tc.operand2))
+		})
+	}
+}
+
+func TestStatusBatcher(t *testing.T) {
+	newUpdates := func(n int) []orchestrator.OperationStatusUpdate {
+		updates := make([]orchestrator.OperationStatusUpdate, n)
+		for i := range updates {
+			updates[i] = orchestrator.OperationStatusUpdate{ID: uuid.New(), Status: orchestrator.OperationStatusCompleted, Result: "1"}
+		}
+		return updates
+	}
+
+	t.Run("Flush writes coalesced statuses in one batch", func(t *testing.T) {
+		repo := new(MockOperationRepository)
+		batcher := NewStatusBatcher(repo, 10)
+		updates := newUpdates(3)
+		for _, update := range updates {
+			batcher.Add(update)
+		}
+
+		repo.On("UpdateStatusBatch", mock.Anything, updates).Return(nil).Once()
+
+		assert.Equal(t, 3, batcher.Flush(context.Background()))
+		assert.Zero(t, batcher.Flush(context.Background()))
+		repo.AssertExpectations(t)
+		repo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failed batch falls back to single updates", func(t *testing.T) {
+		repo := new(MockOperationRepository)
+		batcher := NewStatusBatcher(repo, 10)
+		updates := newUpdates(3)
+		for _, update := range updates {
+			batcher.Add(update)
+		}
+
+		repo.On("UpdateStatusBatch", mock.Anything, updates).Return(errors.New("operation not found")).Once()
+		repo.On("UpdateStatus", mock.Anything, updates[0].ID, updates[0].Status, "1", "").Return(nil)
+		repo.On("UpdateStatus", mock.Anything, updates[1].ID, updates[1].Status, "1", "").Return(errors.New("operation not found"))
+		repo.On("UpdateStatus", mock.Anything, updates[2].ID, updates[2].Status, "1", "").Return(nil)
+
+		assert.Equal(t, 2, batcher.Flush(context.Background()))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Full batch flushed before interval", func(t *testing.T) {
+		repo := new(MockOperationRepository)
+		batcher := NewStatusBatcher(repo, 2)
+		flushed := make(chan struct{})
+		repo.On("UpdateStatusBatch", mock.Anything, mock.Anything).Return(nil).Run(func(mock.Arguments) {
+			close(flushed)
+		}).Once()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go batcher.Run(ctx, time.Hour)
+
+		for _, update := range newUpdates(2) {
+			batcher.Add(update)
+		}
+
+		select {
+		case <-flushed:
+		case <-time.After(time.Second):
+			t.Fatal("Expected full batch to be flushed")
+		}
+	})
+
+	t.Run("Worker adds statuses to batcher", func(t *testing.T) {
+		repo := new(MockOperationRepository)
+		expectNotCancelled(repo)
+		w, err := NewWorker("agent-batch", 3, nil, repo)
+		require.NoError(t, err)
+		w.SetDeterministic(true)
+		batcher := NewStatusBatcher(repo, 10)
+		w.SetStatusBatcher(batcher)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		w.Start(ctx)
+		defer w.Stop()
+
+		op := &orchestrator.Operation{ID: uuid.New(), OperationType: orchestrator.OperationTypeAddition, Operand1: "2", Operand2: "3"}
+		_, err = w.PerformOperation(ctx, op)
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool { return w.CurrentLoad() == 0 }, time.Second, 10*time.Millisecond)
+		repo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+		repo.On("UpdateStatus", mock.Anything, op.ID, orchestrator.OperationStatusCompleted, "5", "").Return(nil).Once()
+		assert.Equal(t, 1, batcher.Flush(context.Background()))
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestPerformOperationSkipsPersistedCancellation(t *testing.T) {
+	repo := new(MockOperationRepository)
+	w, err := NewWorker("agent-cancel", 3, nil, repo)
+	require.NoError(t, err)
+	w.SetDeterministic(true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w.Start(ctx)
+	defer w.Stop()
+
+	// Отмена записана другим экземпляром оркестратора: в реестре отмен ее нет
+	op := &orchestrator.Operation{ID: uuid.New(), OperationType: orchestrator.OperationTypeAddition, Operand1: "2", Operand2: "3"}
+	repo.On("FindByID", mock.Anything, op.ID).Return(&orchestrator.Operation{
+		ID:     op.ID,
+		Status: orchestrator.OperationStatusCancelled,
+	}, nil)
+
+	_, err = w.PerformOperation(ctx, op)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return w.CurrentLoad() == 0 }, time.Second, 10*time.Millisecond)
+	repo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.Zero(t, w.GetStatus().OperationsStats.Completed)
+}
+
+func TestPerformOperationPropagatesRequestID(t *testing.T) {
+	const requestID = "req-worker-42"
+
+	repo := new(MockOperationRepository)
+	expectNotCancelled(repo)
+	w, err := NewWorker("agent-request", 3, nil, repo)
+	require.NoError(t, err)
+	w.SetDeterministic(true)
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	ctx, cancel := context.WithCancel(logger.WithLogger(context.Background(), logger.New(core)))
+	defer cancel()
+	w.Start(ctx)
+	defer w.Stop()
+
+	hasRequestID := mock.MatchedBy(func(ctx context.Context) bool {
+		id, ok := logger.RequestID(ctx)
+		return ok && id == requestID
+	})
+
+	op := &orchestrator.Operation{ID: uuid.New(), OperationType: orchestrator.OperationTypeAddition, Operand1: "2", Operand2: "3"}
+	repo.On("UpdateStatus", hasRequestID, op.ID, orchestrator.OperationStatusCompleted, "5", "").Return(nil).Once()
+
+	_, err = w.PerformOperation(logger.WithRequestID(ctx, requestID), op)
+	require.NoError(t, err)
+
+	executed := func() []observer.LoggedEntry {
+		return logs.FilterMessage("Operation executed successfully").All()
+	}
+	require.Eventually(t, func() bool { return len(executed()) == 1 }, time.Second, 10*time.Millisecond)
+
+	for _, message := range []string{"Agent capacity updated", "Processing operation", "Operation executed successfully"} {
+		entries := logs.FilterMessage(message).All()
+		require.Len(t, entries, 1, message)
+		assert.Equal(t, requestID, entries[0].ContextMap()[logger.RequestIDField], message)
+	}
+	repo.AssertExpectations(t)
+
+	t.Run("Without request ID", func(t *testing.T) {
+		other := &orchestrator.Operation{ID: uuid.New(), OperationType: orchestrator.OperationTypeAddition, Operand1: "1", Operand2: "1"}
+		repo.On("UpdateStatus", mock.Anything, other.ID, orchestrator.OperationStatusCompleted, "2", "").Return(nil).Once()
+
+		_, err := w.PerformOperation(ctx, other)
+		require.NoError(t, err)
+		require.Eventually(t, func() bool { return len(executed()) == 2 }, time.Second, 10*time.Millisecond)
+
+		assert.NotContains(t, executed()[1].ContextMap(), logger.RequestIDField)
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestExecuteOperationTimeout(t *testing.T) {
+	repo := new(MockOperationRepository)
+	expectNotCancelled(repo)
+	w, err := NewWorker("agent-timeout", 3, map[string]time.Duration{
+		"addition":       10 * time.Millisecond,
+		"multiplication": time.Second,
+	}, repo)
+	require.NoError(t, err)
+	w.SetOperationTimeouts(map[string]time.Duration{
+		"addition":       time.Second,
+		"multiplication": 50 * time.Millisecond,
+	})
+
+	ctx, cancel := context.WithCancel(logger.WithLogger(context.Background(), logger.New(zapcore.NewNopCore())))
+	defer cancel()
+	w.Start(ctx)
+	defer w.Stop()
+
+	fast := &orchestrator.Operation{ID: uuid.New(), OperationType: orchestrator.OperationTypeAddition, Operand1: "2", Operand2: "3"}
+	slow := &orchestrator.Operation{ID: uuid.New(), OperationType: orchestrator.OperationTypeMultiplication, Operand1: "2", Operand2: "3"}
+
+	done := make(chan struct{}, 2)
+	signal := func(mock.Arguments) { done <- struct{}{} }
+	repo.On("UpdateStatus", mock.Anything, fast.ID, orchestrator.OperationStatusCompleted, "5", "").Return(nil).Run(signal).Once()
+	repo.On("UpdateStatus", mock.Anything, slow.ID, orchestrator.OperationStatusError, "", "timeout").Return(nil).Run(signal).Once()
+
+	start := time.Now()
+	_, err = w.PerformOperation(ctx, slow)
+	require.NoError(t, err)
+	_, err = w.PerformOperation(ctx, fast)
+	require.NoError(t, err)
+
+	for range 2 {
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("operations were not finished")
+		}
+	}
+	assert.Less(t, time.Since(start), time.Second, "slow operation is interrupted at its deadline")
+	repo.AssertExpectations(t)
+
+	// Статистика агента обновляется после записи статуса
+	require.Eventually(t, func() bool {
+		status := w.GetStatus()
+		return status.OperationsStats.Completed == 1 && status.OperationsStats.Failed == 1 && status.CurrentLoad == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestHeartbeat(t *testing.T) {
+	t.Run("Disabled by default", func(t *testing.T) {
+		w, err := NewWorker("agent-silent", 3, nil, new(MockOperationRepository))
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		w.Start(ctx)
+		defer w.Stop()
+
+		assert.True(t, w.GetStatus().LastHeartbeat.IsZero())
+	})
+
+	t.Run("Stops when processing loop exits", func(t *testing.T) {
+		w, err := NewWorker("agent-heartbeat", 3, nil, new(MockOperationRepository))
+		require.NoError(t, err)
+		w.SetHeartbeatInterval(10 * time.Millisecond)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		w.Start(ctx)
+		defer w.Stop()
+
+		started := w.GetStatus().LastHeartbeat
+		require.False(t, started.IsZero())
+		require.Eventually(t, func() bool {
+			return w.GetStatus().LastHeartbeat.After(started)
+		}, time.Second, 5*time.Millisecond)
+
+		// Цикл обработки завершается вместе с контекстом, сигналы прекращаются
+		cancel()
+		time.Sleep(30 * time.Millisecond)
+		last := w.GetStatus().LastHeartbeat
+		time.Sleep(50 * time.Millisecond)
+		assert.Equal(t, last, w.GetStatus().LastHeartbeat)
+	})
+}
+
+func TestComputeDivisionByZeroPolicy(t *testing.T) {
+	withPolicy := func(policy orchestrator.DivisionByZeroPolicy) orchestrator.Arithmetic {
+		arithmetic := orchestrator.FloatArithmetic
+		arithmetic.DivisionByZero = policy
+		return arithmetic
+	}
+
+	testCases := []struct {
+		name     string
+		policy   orchestrator.DivisionByZeroPolicy
+		opType   orchestrator.OperationType
+		operand1 string
+		operand2 string
+		expected string
+	}{
+		{name: "Infinity positive by zero", policy: orchestrator.DivisionByZeroInfinity, opType: orchestrator.OperationTypeDivision, operand1: "1", operand2: "0", expected: "inf"},
+		{name: "Infinity negative by zero", policy: orchestrator.DivisionByZeroInfinity, opType: orchestrator.OperationTypeDivision, operand1: "-1", operand2: "0", expected: "-inf"},
+		{name: "Infinity positive by negative zero", policy: orchestrator.DivisionByZeroInfinity, opType: orchestrator.OperationTypeDivision, operand1: "1", operand2: "-0", expected: "-inf"},
+		{name: "Infinity negative by negative zero", policy: orchestrator.DivisionByZeroInfinity, opType: orchestrator.OperationTypeDivision, operand1: "-1", operand2: "-0", expected: "inf"},
+		{name: "Infinity zero by zero", policy: orchestrator.DivisionByZeroInfinity, opType: orchestrator.OperationTypeDivision, operand1: "0", operand2: "0", expected: "nan"},
+		{name: "Infinity modulo by zero", policy: orchestrator.DivisionByZeroInfinity, opType: orchestrator.OperationTypeModulo, operand1: "5", operand2: "0", expected: "nan"},
+		{name: "Infinity propagates", policy: orchestrator.DivisionByZeroInfinity, opType: orchestrator.OperationTypeAddition, operand1: "inf", operand2: "2", expected: "inf"},
+		{name: "Infinity minus infinity", policy: orchestrator.DivisionByZeroInfinity, opType: orchestrator.OperationTypeSubtraction, operand1: "inf", operand2: "inf", expected: "nan"},
+		{name: "NaN positive by zero", policy: orchestrator.DivisionByZeroNaN, opType: orchestrator.OperationTypeDivision, operand1: "1", operand2: "0", expected: "nan"},
+		{name: "NaN negative by zero", policy: orchestrator.DivisionByZeroNaN, opType: orchestrator.OperationTypeDivision, operand1: "-1", operand2: "-0", expected: "nan"},
+		{name: "NaN zero by zero", policy: orchestrator.DivisionByZeroNaN, opType: orchestrator.OperationTypeDivision, operand1: "0", operand2: "0", expected: "nan"},
+		{name: "NaN modulo by zero", policy: orchestrator.DivisionByZeroNaN, opType: orchestrator.OperationTypeModulo, operand1: "-5", operand2: "0", expected: "nan"},
+		{name: "NaN propagates", policy: orchestrator.DivisionByZeroNaN, opType: orchestrator.OperationTypeMultiplication, operand1: "nan", operand2: "2", expected: "nan"},
+		{name: "Non-zero divisor unaffected", policy: orchestrator.DivisionByZeroNaN, opType: orchestrator.OperationTypeDivision, operand1: "-6", operand2: "3", expected: "-2"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := Compute(tc.opType, tc.operand1, tc.operand2, withPolicy(tc.policy))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+
+	t.Run("Error policy", func(t *testing.T) {
+		for _, operands := range [][2]string{{"1", "0"}, {"-1", "0"}, {"1", "-0"}, {"0", "0"}} {
+			_, err := Compute(orchestrator.OperationTypeDivision, operands[0], operands[1], withPolicy(orchestrator.DivisionByZeroError))
+			assert.ErrorIs(t, err, domainerrors.ErrDivisionByZero, "%s/%s", operands[0], operands[1])
+		}
+		_, err := Compute(orchestrator.OperationTypeModulo, "5", "0", withPolicy(orchestrator.DivisionByZeroError))
+		assert.ErrorIs(t, err, domainerrors.ErrDivisionByZero)
+	})
+
+	t.Run("Strict by default", func(t *testing.T) {
+		_, err := Compute(orchestrator.OperationTypeDivision, "1", "0", orchestrator.FloatArithmetic)
+		assert.ErrorIs(t, err, domainerrors.ErrDivisionByZero)
+
+		_, err = Compute(orchestrator.OperationTypeDivision, "1", "0", orchestrator.Arithmetic{Backend: orchestrator.ArithmeticFloat})
+		assert.ErrorIs(t, err, domainerrors.ErrDivisionByZero, "zero policy is treated as error")
+	})
+
+	t.Run("Worker normalizes unknown policy", func(t *testing.T) {
+		w, err := NewWorker("agent-test", 1, nil, new(MockOperationRepository))
+		require.NoError(t, err)
+
+		w.SetArithmetic(withPolicy("explode"))
+		assert.Equal(t, orchestrator.DivisionByZeroError, w.getArithmetic().DivisionByZero)
+
+		w.SetArithmetic(withPolicy(orchestrator.DivisionByZeroNaN))
+		assert.Equal(t, orchestrator.DivisionByZeroNaN, w.getArithmetic().DivisionByZero)
+	})
+}
@@ -0,0 +1,120 @@
+// Package cleanup реализует периодическое удаление истекших токенов сервиса аутентификации.
+package cleanup
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/retry"
+	"go.uber.org/zap"
+)
+
+// TokenCleaner удаляет истекшие токены и возвращает их количество.
+type TokenCleaner interface {
+	CleanupExpiredTokens(ctx context.Context) (int64, error)
+}
+
+// Scheduler каждые interval удаляет истекшие токены. Каждый запуск откладывается на случайную
+// долю периода не больше jitter, чтобы экземпляры сервиса не нагружали базу одновременно.
+type Scheduler struct {
+	cleaner  TokenCleaner
+	interval time.Duration
+	jitter   float64
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewScheduler создает планировщик удаления истекших токенов. Jitter вне диапазона [0, 1]
+// приводится к ближайшей границе.
+func NewScheduler(cleaner TokenCleaner, interval time.Duration, jitter float64) *Scheduler {
+	if cleaner == nil {
+		panic(fmt.Sprintf("%v: token cleaner", domainerrors.ErrNilDependency))
+	}
+
+	return &Scheduler{
+		cleaner:  cleaner,
+		interval: interval,
+		jitter:   min(max(jitter, 0), 1),
+	}
+}
+
+// Start запускает удаление до вызова Stop или завершения ctx. Неположительный интервал
+// отключает удаление. Повторный вызов до Stop ничего не делает.
+func (s *Scheduler) Start(ctx context.Context) {
+	if s.interval <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel != nil {
+		return
+	}
+
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.done = make(chan struct{})
+
+	go func(done chan struct{}) {
+		defer close(done)
+		s.run(ctx)
+	}(s.done)
+}
+
+// Stop останавливает удаление и ждет завершения текущего запуска. Возвращает ошибку ctx,
+// если запуск не завершился раньше.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	cancel, done := s.cancel, s.done
+	s.cancel, s.done = nil, nil
+	s.mu.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("wait for token cleanup to stop: %w", ctx.Err())
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context) {
+	log := logger.ContextLogger(ctx, nil).With(zap.String("op", "Scheduler.run"))
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		delay := time.Duration(rand.Float64() * s.jitter * float64(s.interval))
+		if err := retry.Sleep(ctx, delay); err != nil {
+			return
+		}
+
+		deleted, err := s.cleaner.CleanupExpiredTokens(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Warn("Failed to clean up expired tokens", zap.Error(err))
+			continue
+		}
+		log.Info("Expired tokens cleaned up", zap.Int64("deleted", deleted))
+	}
+}
@@ -0,0 +1,94 @@
+package cleanup_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/app/auth/cleanup"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+type stubCleaner struct {
+	calls atomic.Int32
+	err   error
+}
+
+func (c *stubCleaner) CleanupExpiredTokens(context.Context) (int64, error) {
+	c.calls.Add(1)
+	if c.err != nil {
+		return 0, c.err
+	}
+	return 2, nil
+}
+
+func testContext() context.Context {
+	return logger.WithLogger(context.Background(), logger.New(zapcore.NewNopCore()))
+}
+
+func TestScheduler(t *testing.T) {
+	t.Run("Runs cleanup periodically until stopped", func(t *testing.T) {
+		cleaner := &stubCleaner{}
+		scheduler := cleanup.NewScheduler(cleaner, 10*time.Millisecond, 0.5)
+
+		scheduler.Start(testContext())
+		require.Eventually(t, func() bool { return cleaner.calls.Load() >= 2 }, time.Second, 5*time.Millisecond)
+
+		require.NoError(t, scheduler.Stop(context.Background()))
+		calls := cleaner.calls.Load()
+		time.Sleep(50 * time.Millisecond)
+		assert.Equal(t, calls, cleaner.calls.Load(), "no cleanup after Stop")
+	})
+
+	t.Run("Keeps running after cleanup error", func(t *testing.T) {
+		cleaner := &stubCleaner{err: errors.New("db error")}
+		scheduler := cleanup.NewScheduler(cleaner, 10*time.Millisecond, 0)
+
+		scheduler.Start(testContext())
+		require.Eventually(t, func() bool { return cleaner.calls.Load() >= 2 }, time.Second, 5*time.Millisecond)
+		require.NoError(t, scheduler.Stop(context.Background()))
+	})
+
+	t.Run("Stops with context", func(t *testing.T) {
+		cleaner := &stubCleaner{}
+		scheduler := cleanup.NewScheduler(cleaner, 10*time.Millisecond, 0)
+
+		ctx, cancel := context.WithCancel(testContext())
+		scheduler.Start(ctx)
+		require.Eventually(t, func() bool { return cleaner.calls.Load() >= 1 }, time.Second, 5*time.Millisecond)
+		cancel()
+
+		require.NoError(t, scheduler.Stop(context.Background()))
+	})
+
+	t.Run("Disabled with non-positive interval", func(t *testing.T) {
+		cleaner := &stubCleaner{}
+		scheduler := cleanup.NewScheduler(cleaner, 0, 0)
+
+		scheduler.Start(testContext())
+		time.Sleep(30 * time.Millisecond)
+		assert.Zero(t, cleaner.calls.Load())
+		assert.NoError(t, scheduler.Stop(context.Background()))
+	})
+
+	t.Run("Start and Stop are idempotent", func(t *testing.T) {
+		cleaner := &stubCleaner{}
+		scheduler := cleanup.NewScheduler(cleaner, time.Hour, 0)
+
+		assert.NoError(t, scheduler.Stop(context.Background()), "stop before start")
+		scheduler.Start(testContext())
+		scheduler.Start(testContext())
+		assert.NoError(t, scheduler.Stop(context.Background()))
+		assert.NoError(t, scheduler.Stop(context.Background()))
+		assert.Zero(t, cleaner.calls.Load())
+	})
+
+	t.Run("Nil cleaner panics", func(t *testing.T) {
+		assert.Panics(t, func() { cleanup.NewScheduler(nil, time.Minute, 0) })
+	})
+}
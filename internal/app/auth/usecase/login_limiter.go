@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"sync"
+	"time"
+)
+
+// maxDelayShift ограничивает показатель степени прогрессивной задержки, чтобы сдвиг не переполнился.
+const maxDelayShift = 30
+
+// loginLimiter считает неудачные попытки входа по ключу (логину или IP-адресу)
+// в скользящем окне. Ключ IP-адреса блокируется, пока число попыток в окне
+// не опустится ниже порога; для ключа логина после порога вводится задержка,
+// удваивающаяся с каждой следующей неудачной попыткой.
+type loginLimiter struct {
+	mu          sync.Mutex
+	maxFailures int
+	window      time.Duration
+	delayBase   time.Duration
+	maxDelay    time.Duration
+	failures    map[string][]time.Time
+	lastSweep   time.Time
+	now         func() time.Time
+}
+
+// newLoginLimiter создает ограничитель неудачных попыток входа.
+// Возвращает nil, если ограничение отключено нулевым порогом или окном.
+func newLoginLimiter(maxFailures int, window, delayBase, maxDelay time.Duration) *loginLimiter {
+	if maxFailures <= 0 || window <= 0 {
+		return nil
+	}
+
+	return &loginLimiter{
+		maxFailures: maxFailures,
+		window:      window,
+		delayBase:   delayBase,
+		maxDelay:    maxDelay,
+		failures:    make(map[string][]time.Time),
+		now:         time.Now,
+	}
+}
+
+// blocked сообщает, исчерпан ли лимит неудачных попыток для ключа.
+func (l *loginLimiter) blocked(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return len(l.prune(key, l.now())) >= l.maxFailures
+}
+
+// retryAfter возвращает, сколько осталось ждать до следующей попытки для ключа.
+// После maxFailures неудачных попыток в окне задержка отсчитывается от последней
+// из них и равна delayBase, удваиваясь с каждой следующей попыткой до maxDelay.
+// Нулевая delayBase отключает задержку.
+func (l *loginLimiter) retryAfter(key string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	attempts := l.prune(key, now)
+	if l.delayBase <= 0 || len(attempts) < l.maxFailures {
+		return 0
+	}
+
+	delay := l.delayBase << min(len(attempts)-l.maxFailures, maxDelayShift)
+	if l.maxDelay > 0 && (delay > l.maxDelay || delay <= 0) {
+		delay = l.maxDelay
+	}
+
+	if wait := attempts[len(attempts)-1].Add(delay).Sub(now); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+// fail учитывает неудачную попытку входа для каждого из непустых ключей.
+// Не чаще раза в окно удаляет из карты все устаревшие ключи.
+func (l *loginLimiter) fail(keys ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Sub(l.lastSweep) >= l.window {
+		for key := range l.failures {
+			l.prune(key, now)
+		}
+		l.lastSweep = now
+	}
+
+	for _, key := range keys {
+		if key != "" {
+			l.failures[key] = append(l.prune(key, now), now)
+		}
+	}
+}
+
+// reset сбрасывает счетчик неудачных попыток для ключа.
+func (l *loginLimiter) reset(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.failures, key)
+}
+
+// prune удаляет попытки, вышедшие за пределы окна, и возвращает оставшиеся.
+// Ключи без попыток удаляются, чтобы карта не росла неограниченно.
+func (l *loginLimiter) prune(key string, now time.Time) []time.Time {
+	attempts := l.failures[key]
+	cutoff := now.Add(-l.window)
+
+	idx := 0
+	for idx < len(attempts) && !attempts[idx].After(cutoff) {
+		idx++
+	}
+	attempts = attempts[idx:]
+
+	if len(attempts) == 0 {
+		delete(l.failures, key)
+		return nil
+	}
+	l.failures[key] = attempts
+	return attempts
+}
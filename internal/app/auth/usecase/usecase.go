@@ -5,13 +5,18 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
 	authmodels "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/auth"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/system"
 	authapi "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/api/auth"
 	authrepo "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/repository/auth"
+	systemrepo "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/repository/system"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/repository/transaction"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/service/jwt"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/service/password"
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
@@ -28,8 +33,27 @@ type AuthUseCase struct {
 	tokenRepo   authrepo.TokenRepository // Репозиторий для работы с токенами аутентификации
 	passwordSvc password.Service         // Сервис для хеширования и проверки паролей
 	jwtSvc      jwt.Service              // Сервис для создания и валидации JWT токенов
+
+	detectRefreshReuse     bool // Отзывать все токены пользователя при повторном использовании отозванного refresh токена
+	revokeOnPasswordChange bool // Отзывать все refresh токены пользователя после смены пароля
+
+	dbPoolStats systemrepo.DBPoolStatsProvider // Источник состояния пула соединений для статистики
+
+	txManager transaction.TxManager // Менеджер транзакций, nil - обращения к хранилищу выполняются по отдельности
+
+	loginLimiter *loginLimiter // Ограничитель неудачных попыток входа, nil - ограничение отключено
+
+	refreshMaxAge time.Duration // Наибольший возраст семейства refresh токенов, ноль - без ограничения
 }
 
+const (
+	// sessionsPageSize - количество токенов, загружаемых за один запрос при выводе сессий.
+	sessionsPageSize = 100
+
+	// maxRetries - количество попыток чтения из хранилища при временных ошибках.
+	maxRetries = 3
+)
+
 // Проверка, что AuthUseCase реализует интерфейс UseCaseUser
 var _ authapi.UseCaseUser = (*AuthUseCase)(nil)
 
@@ -42,6 +66,8 @@ var _ authapi.UseCaseUser = (*AuthUseCase)(nil)
 //   - tokenRepo: репозиторий для работы с токенами
 //   - passwordSvc: сервис для работы с паролями
 //   - jwtSvc: сервис для работы с JWT токенами
+//   - detectRefreshReuse: при предъявлении уже отозванного refresh токена
+//     отзывать все токены пользователя (обнаружение повторного использования)
 //
 // Возвращает:
 //   - экземпляр AuthUseCase, готовый к использованию
@@ -50,15 +76,53 @@ func NewAuthUseCase(
 	tokenRepo authrepo.TokenRepository,
 	passwordSvc password.Service,
 	jwtSvc jwt.Service,
+	detectRefreshReuse bool,
 ) *AuthUseCase {
 	return &AuthUseCase{
-		userRepo:    userRepo,
-		tokenRepo:   tokenRepo,
-		passwordSvc: passwordSvc,
-		jwtSvc:      jwtSvc,
+		userRepo:           userRepo,
+		tokenRepo:          tokenRepo,
+		passwordSvc:        passwordSvc,
+		jwtSvc:             jwtSvc,
+		detectRefreshReuse: detectRefreshReuse,
 	}
 }
 
+// SetRevokeOnPasswordChange включает отзыв всех refresh токенов пользователя
+// после успешной смены пароля, что завершает сессии на других устройствах.
+func (uc *AuthUseCase) SetRevokeOnPasswordChange(revoke bool) {
+	uc.revokeOnPasswordChange = revoke
+}
+
+// SetDBPoolStatsProvider задает источник состояния пула соединений, включаемого в статистику сервиса.
+func (uc *AuthUseCase) SetDBPoolStatsProvider(provider systemrepo.DBPoolStatsProvider) {
+	uc.dbPoolStats = provider
+}
+
+// SetTxManager задает менеджер транзакций для операций, изменяющих пользователя и его токены вместе.
+func (uc *AuthUseCase) SetTxManager(txManager transaction.TxManager) {
+	uc.txManager = txManager
+}
+
+// SetLoginLimit включает ограничение неудачных попыток входа. После maxFailures
+// неудачных попыток в течение window с одного IP-адреса вход с него возвращает
+// ErrTooManyAttempts, пока старые попытки не выйдут за пределы окна. Для логина
+// после maxFailures попыток вводится задержка delayBase, удваивающаяся с каждой
+// следующей неудачной попыткой до maxDelay: пока она не истекла, вход возвращает
+// ErrTooManyAttempts, поэтому подбор пароля чужого логина не блокирует владельца надолго.
+// Успешный вход сбрасывает счетчик логина. Нулевой порог или окно отключают ограничение,
+// нулевая delayBase - задержку для логина.
+func (uc *AuthUseCase) SetLoginLimit(maxFailures int, window, delayBase, maxDelay time.Duration) {
+	uc.loginLimiter = newLoginLimiter(maxFailures, window, delayBase, maxDelay)
+}
+
+// SetRefreshTokenMaxAge ограничивает возраст семейства refresh токенов - цепочки обновлений,
+// начатой одним входом. Когда с момента входа прошло больше maxAge, обновление отклоняется
+// с ErrSessionTooOld, даже если предъявленный токен действителен, и пользователь должен
+// войти заново. Ноль или отрицательное значение отключают ограничение.
+func (uc *AuthUseCase) SetRefreshTokenMaxAge(maxAge time.Duration) {
+	uc.refreshMaxAge = max(maxAge, 0)
+}
+
 // Register регистрирует нового пользователя в системе.
 // Процесс включает проверку существования пользователя с таким логином,
 // хеширование пароля и сохранение данных нового пользователя в базе данных.
@@ -121,10 +185,12 @@ func (uc *AuthUseCase) Register(ctx context.Context, login, password string) (uu
 // в базу данных для последующего использования и возможности его отзыва.
 //
 // Процесс аутентификации:
+//  0. Проверка лимита неудачных попыток входа для логина и IP-адреса клиента
 //  1. Поиск пользователя по логину
 //  2. Проверка хешированного пароля
 //  3. Генерация пары токенов доступа
-//  4. Сохранение refresh токена в базе данных
+//  4. Сохранение refresh токена в базе данных вместе со сведениями о клиенте
+//     (user agent и IP из контекста), которые затем отображаются в списке сессий
 //
 // Параметры:
 //   - ctx: контекст выполнения операции
@@ -138,6 +204,12 @@ func (uc *AuthUseCase) Login(ctx context.Context, login, password string) (*auth
 	const op = "AuthUseCase.Login"
 	log := logger.ContextLogger(ctx, nil).With(zap.String("op", op), zap.String("login", login))
 
+	clientInfo := authmodels.ClientInfoFromContext(ctx)
+	loginKey, ipKey := loginLimitKeys(login, clientInfo.IP)
+	if err := uc.checkLoginLimit(log, loginKey, ipKey, clientInfo.IP); err != nil {
+		return nil, err
+	}
+
 	user, err := uc.userRepo.FindByLogin(ctx, login)
 	if err != nil {
 		log.Error("Failed to find user", zap.Error(err))
@@ -146,6 +218,7 @@ func (uc *AuthUseCase) Login(ctx context.Context, login, password string) (*auth
 
 	if user == nil {
 		log.Warn("User not found")
+		uc.recordLoginFailure(loginKey, ipKey)
 		return nil, domainerrors.ErrInvalidCredentials
 	}
 
@@ -157,22 +230,31 @@ func (uc *AuthUseCase) Login(ctx context.Context, login, password string) (*auth
 
 	if !valid {
 		log.Warn("Invalid password")
+		uc.recordLoginFailure(loginKey, ipKey)
 		return nil, domainerrors.ErrInvalidCredentials
 	}
 
+	if uc.loginLimiter != nil {
+		uc.loginLimiter.reset(loginKey)
+	}
+
 	tokenPair, err := uc.jwtSvc.GenerateTokens(ctx, user.ID, user.Login)
 	if err != nil {
 		log.Error("Failed to generate tokens", zap.Error(err))
 		return nil, fmt.Errorf("%s: %w", op, domainerrors.ErrInternalServerError)
 	}
 
+	now := time.Now()
 	token := &authmodels.Token{
-		ID:        uuid.New(),
-		UserID:    user.ID,
-		TokenStr:  tokenPair.RefreshToken,
-		ExpiresAt: time.Now().Add(uc.jwtSvc.GetRefreshTokenTTL()),
-		CreatedAt: time.Now(),
-		IsRevoked: false,
+		ID:             uuid.New(),
+		UserID:         user.ID,
+		TokenStr:       tokenPair.RefreshToken,
+		ExpiresAt:      now.Add(uc.jwtSvc.GetRefreshTokenTTL()),
+		CreatedAt:      now,
+		IsRevoked:      false,
+		UserAgent:      clientInfo.UserAgent,
+		IP:             clientInfo.IP,
+		FamilyIssuedAt: now,
 	}
 
 	if err := uc.tokenRepo.Store(ctx, token); err != nil {
@@ -184,6 +266,41 @@ func (uc *AuthUseCase) Login(ctx context.Context, login, password string) (*auth
 	return tokenPair, nil
 }
 
+// loginLimitKeys возвращает ключи ограничителя попыток входа для логина и IP-адреса.
+// Ключ IP-адреса пуст, если адрес неизвестен.
+func loginLimitKeys(login, ip string) (loginKey, ipKey string) {
+	loginKey = "login:" + login
+	if ip != "" {
+		ipKey = "ip:" + ip
+	}
+	return loginKey, ipKey
+}
+
+// checkLoginLimit возвращает ErrTooManyAttempts, если проверка пароля для логина или IP-адреса
+// временно заблокирована ограничителем неудачных попыток входа.
+func (uc *AuthUseCase) checkLoginLimit(log logger.Logger, loginKey, ipKey, ip string) error {
+	if uc.loginLimiter == nil {
+		return nil
+	}
+	if ipKey != "" && uc.loginLimiter.blocked(ipKey) {
+		log.Warn("Too many failed login attempts from IP", zap.String("ip", ip))
+		return domainerrors.ErrTooManyAttempts
+	}
+	if wait := uc.loginLimiter.retryAfter(loginKey); wait > 0 {
+		log.Warn("Login attempt before progressive delay expired",
+			zap.String("ip", ip), zap.Duration("retryAfter", wait))
+		return domainerrors.ErrTooManyAttempts
+	}
+	return nil
+}
+
+// recordLoginFailure учитывает неудачную попытку входа, если ограничение включено.
+func (uc *AuthUseCase) recordLoginFailure(keys ...string) {
+	if uc.loginLimiter != nil {
+		uc.loginLimiter.fail(keys...)
+	}
+}
+
 // ValidateToken проверяет действительность access токена и возвращает ID пользователя.
 // Выполняет криптографическую проверку подписи токена и проверяет существование
 // пользователя в системе.
@@ -231,7 +348,9 @@ func (uc *AuthUseCase) ValidateToken(ctx context.Context, tokenStr string) (uuid
 //
 // Процесс обновления включает:
 //  1. Парсинг refresh токена и извлечение идентификатора пользователя
-//  2. Поиск токена в базе данных и проверка его статуса (не отозван, не просрочен)
+//  2. Поиск токена в базе данных и проверка его статуса (не отозван, не просрочен).
+//     Если включено обнаружение повторного использования, предъявление отозванного
+//     токена приводит к отзыву всех токенов пользователя
 //  3. Отзыв старого токена
 //  4. Генерация новой пары токенов
 //  5. Сохранение нового refresh токена в базе данных
@@ -277,8 +396,7 @@ func (uc *AuthUseCase) RefreshToken(ctx context.Context, refreshTokenStr string)
 	}
 
 	if token.IsRevoked {
-		log.Debug("Token is revoked")
-		return nil, domainerrors.ErrTokenRevoked
+		return nil, uc.handleRefreshReuse(ctx, log, op, token)
 	}
 
 	if token.ExpiresAt.Before(time.Now()) {
@@ -286,6 +404,19 @@ func (uc *AuthUseCase) RefreshToken(ctx context.Context, refreshTokenStr string)
 		return nil, domainerrors.ErrTokenExpired
 	}
 
+	familyIssuedAt := token.FamilyIssuedAt
+	if familyIssuedAt.IsZero() {
+		familyIssuedAt = token.CreatedAt
+	}
+
+	if uc.refreshMaxAge > 0 && time.Since(familyIssuedAt) > uc.refreshMaxAge {
+		log.Info("Refresh token family exceeded maximum age",
+			zap.String("userId", token.UserID.String()),
+			zap.Time("familyIssuedAt", familyIssuedAt),
+			zap.Duration("maxAge", uc.refreshMaxAge))
+		return nil, domainerrors.ErrSessionTooOld
+	}
+
 	user, err := uc.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		log.Error("Failed to find user", zap.Error(err))
@@ -297,24 +428,35 @@ func (uc *AuthUseCase) RefreshToken(ctx context.Context, refreshTokenStr string)
 		return nil, domainerrors.ErrUserNotFound
 	}
 
-	if err := uc.tokenRepo.RevokeToken(ctx, refreshTokenStr); err != nil {
+	// Токен отзывается условно: если параллельный запрос с тем же токеном успел
+	// отозвать его после проверки выше, это повторное использование
+	revoked, err := uc.tokenRepo.RevokeIfActive(ctx, refreshTokenStr)
+	if err != nil {
 		log.Error("Failed to revoke old token", zap.Error(err))
 		return nil, fmt.Errorf("%s: %w", op, domainerrors.ErrInternalServerError)
 	}
 
+	if !revoked {
+		return nil, uc.handleRefreshReuse(ctx, log, op, token)
+	}
+
 	newTokenPair, err := uc.jwtSvc.GenerateTokens(ctx, user.ID, user.Login)
 	if err != nil {
 		log.Error("Failed to generate new tokens", zap.Error(err))
 		return nil, fmt.Errorf("%s: %w", op, domainerrors.ErrInternalServerError)
 	}
 
+	// Новый токен продолжает ту же сессию, поэтому наследует данные клиента
 	newToken := &authmodels.Token{
-		ID:        uuid.New(),
-		UserID:    user.ID,
-		TokenStr:  newTokenPair.RefreshToken,
-		ExpiresAt: time.Now().Add(uc.jwtSvc.GetRefreshTokenTTL()),
-		CreatedAt: time.Now(),
-		IsRevoked: false,
+		ID:             uuid.New(),
+		UserID:         user.ID,
+		TokenStr:       newTokenPair.RefreshToken,
+		ExpiresAt:      time.Now().Add(uc.jwtSvc.GetRefreshTokenTTL()),
+		CreatedAt:      time.Now(),
+		IsRevoked:      false,
+		UserAgent:      token.UserAgent,
+		IP:             token.IP,
+		FamilyIssuedAt: familyIssuedAt,
 	}
 
 	if err := uc.tokenRepo.Store(ctx, newToken); err != nil {
@@ -326,6 +468,25 @@ func (uc *AuthUseCase) RefreshToken(ctx context.Context, refreshTokenStr string)
 	return newTokenPair, nil
 }
 
+// handleRefreshReuse обрабатывает предъявление уже отозванного refresh токена. При включенном
+// обнаружении повторного использования токен считается похищенным, и все сессии пользователя
+// отзываются. Возвращает ErrTokenRevoked или ошибку отзыва.
+func (uc *AuthUseCase) handleRefreshReuse(ctx context.Context, log logger.Logger, op string, token *authmodels.Token) error {
+	if !uc.detectRefreshReuse {
+		log.Debug("Token is revoked")
+		return domainerrors.ErrTokenRevoked
+	}
+
+	log.Warn("Revoked refresh token reuse detected, revoking all user tokens",
+		zap.String("userId", token.UserID.String()),
+		zap.String("tokenId", token.ID.String()))
+	if err := uc.tokenRepo.RevokeAllUserTokens(ctx, token.UserID); err != nil {
+		log.Error("Failed to revoke user tokens after reuse detection", zap.Error(err))
+		return fmt.Errorf("%s: %w", op, domainerrors.ErrInternalServerError)
+	}
+	return domainerrors.ErrTokenRevoked
+}
+
 // Logout завершает сессию пользователя путем отзыва refresh токена.
 // После успешного выхода токен становится недействительным и не может быть
 // использован для обновления пары токенов.
@@ -377,6 +538,324 @@ func (uc *AuthUseCase) Logout(ctx context.Context, tokenStr string) error {
 	return nil
 }
 
+// ChangePassword меняет пароль пользователя после проверки текущего пароля.
+//
+// Процесс смены пароля включает:
+//  1. Проверку нового пароля на соответствие политике и отличие от текущего
+//  2. Проверку текущего пароля с учетом ограничителя попыток входа, как в DeleteUser
+//  3. Хеширование и сохранение нового пароля
+//  4. Отзыв всех refresh токенов пользователя, если это включено
+//
+// Параметры:
+//   - ctx: контекст выполнения операции
+//   - userID: идентификатор пользователя
+//   - oldPassword: текущий пароль в открытом виде
+//   - newPassword: новый пароль в открытом виде
+//
+// Возвращает:
+//   - error: ErrInvalidCredentials при неверном текущем пароле, ErrTooManyAttempts при превышении
+//     лимита попыток, ошибка операции или nil при успехе
+func (uc *AuthUseCase) ChangePassword(ctx context.Context, userID uuid.UUID, oldPassword, newPassword string) error {
+	const op = "AuthUseCase.ChangePassword"
+	log := logger.ContextLogger(ctx, nil).With(zap.String("op", op), zap.String("userId", userID.String()))
+
+	if len(newPassword) < password.MinPasswordLength {
+		log.Debug("New password does not meet the policy")
+		return fmt.Errorf("%w: minimum length is %d", domainerrors.ErrWeakPassword, password.MinPasswordLength)
+	}
+
+	if newPassword == oldPassword {
+		log.Debug("New password matches the old one")
+		return domainerrors.ErrSamePassword
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		log.Error("Failed to find user", zap.Error(err))
+		return fmt.Errorf("%s: %w", op, domainerrors.ErrInternalServerError)
+	}
+
+	if user == nil {
+		log.Warn("User not found")
+		return domainerrors.ErrUserNotFound
+	}
+
+	clientInfo := authmodels.ClientInfoFromContext(ctx)
+	loginKey, ipKey := loginLimitKeys(user.Login, clientInfo.IP)
+	if err := uc.checkLoginLimit(log, loginKey, ipKey, clientInfo.IP); err != nil {
+		return err
+	}
+
+	valid, err := uc.passwordSvc.Verify(ctx, oldPassword, user.PasswordHash)
+	if err != nil {
+		log.Error("Password verification error", zap.Error(err))
+		return fmt.Errorf("%s: %w", op, domainerrors.ErrInternalServerError)
+	}
+
+	if !valid {
+		log.Warn("Invalid old password")
+		uc.recordLoginFailure(loginKey, ipKey)
+		return domainerrors.ErrInvalidCredentials
+	}
+
+	hashedPassword, err := uc.passwordSvc.Hash(ctx, newPassword)
+	if err != nil {
+		log.Error("Failed to hash password", zap.Error(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	user.PasswordHash = hashedPassword
+	user.UpdatedAt = time.Now()
+
+	if err := uc.userRepo.Update(ctx, user); err != nil {
+		log.Error("Failed to update user", zap.Error(err))
+		return fmt.Errorf("%s: %w", op, domainerrors.ErrInternalServerError)
+	}
+
+	if uc.revokeOnPasswordChange {
+		if err := uc.tokenRepo.RevokeAllUserTokens(ctx, user.ID); err != nil {
+			log.Error("Failed to revoke user tokens", zap.Error(err))
+			return fmt.Errorf("%s: %w", op, domainerrors.ErrInternalServerError)
+		}
+	}
+
+	log.Info("Password changed successfully")
+	return nil
+}
+
+// GetUser возвращает профиль пользователя. Хеш пароля в результат не попадает.
+//
+// Параметры:
+//   - ctx: контекст выполнения операции
+//   - userID: идентификатор пользователя
+//
+// Возвращает:
+//   - *authmodels.User: логин и время создания и изменения пользователя
+//   - error: ErrUserNotFound если пользователь не найден, ошибка операции или nil при успехе
+func (uc *AuthUseCase) GetUser(ctx context.Context, userID uuid.UUID) (*authmodels.User, error) {
+	const op = "AuthUseCase.GetUser"
+	log := logger.ContextLogger(ctx, nil).With(zap.String("op", op), zap.String("userId", userID.String()))
+
+	if userID == uuid.Nil {
+		return nil, domainerrors.ErrUserNotFound
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		log.Error("Failed to find user", zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", op, domainerrors.ErrInternalServerError)
+	}
+
+	if user == nil {
+		log.Warn("User not found")
+		return nil, domainerrors.ErrUserNotFound
+	}
+
+	return &authmodels.User{
+		ID:        user.ID,
+		Login:     user.Login,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}, nil
+}
+
+// DeleteUser удаляет учетную запись пользователя после повторной проверки пароля.
+// Проверка пароля учитывается ограничителем попыток входа наравне с Login, поэтому
+// украденный токен доступа не позволяет подбирать пароль без ограничений.
+// Все refresh токены пользователя отзываются, а запись удаляется в одной транзакции,
+// поэтому при ошибке удаления сессии остаются действующими. Выданные ранее access токены
+// перестают проходить ValidateToken, так как пользователь больше не находится.
+//
+// Параметры:
+//   - ctx: контекст выполнения операции
+//   - userID: идентификатор пользователя
+//   - password: текущий пароль в открытом виде для подтверждения
+//
+// Возвращает:
+//   - error: ErrInvalidCredentials при неверном пароле, ErrTooManyAttempts при превышении
+//     лимита попыток, ErrUserNotFound если пользователь не найден, ошибка операции или nil при успехе
+func (uc *AuthUseCase) DeleteUser(ctx context.Context, userID uuid.UUID, password string) error {
+	const op = "AuthUseCase.DeleteUser"
+	log := logger.ContextLogger(ctx, nil).With(zap.String("op", op), zap.String("userId", userID.String()))
+
+	if userID == uuid.Nil {
+		return domainerrors.ErrUserNotFound
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		log.Error("Failed to find user", zap.Error(err))
+		return fmt.Errorf("%s: %w", op, domainerrors.ErrInternalServerError)
+	}
+
+	if user == nil {
+		log.Warn("User not found")
+		return domainerrors.ErrUserNotFound
+	}
+
+	clientInfo := authmodels.ClientInfoFromContext(ctx)
+	loginKey, ipKey := loginLimitKeys(user.Login, clientInfo.IP)
+	if err := uc.checkLoginLimit(log, loginKey, ipKey, clientInfo.IP); err != nil {
+		return err
+	}
+
+	valid, err := uc.passwordSvc.Verify(ctx, password, user.PasswordHash)
+	if err != nil {
+		log.Error("Password verification error", zap.Error(err))
+		return fmt.Errorf("%s: %w", op, domainerrors.ErrInternalServerError)
+	}
+
+	if !valid {
+		log.Warn("Invalid password confirmation")
+		uc.recordLoginFailure(loginKey, ipKey)
+		return domainerrors.ErrInvalidCredentials
+	}
+
+	err = uc.withinTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.tokenRepo.RevokeAllUserTokens(ctx, user.ID); err != nil {
+			return fmt.Errorf("revoke user tokens: %w", err)
+		}
+		if err := uc.userRepo.Delete(ctx, user.ID); err != nil {
+			return fmt.Errorf("delete user: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Error("Failed to delete user", zap.Error(err))
+		return fmt.Errorf("%s: %w", op, domainerrors.ErrInternalServerError)
+	}
+
+	log.Info("User deleted")
+	return nil
+}
+
+// ListSessions возвращает активные сессии пользователя: refresh токены,
+// которые не отозваны и не истекли. Сами значения токенов не раскрываются.
+//
+// Параметры:
+//   - ctx: контекст выполнения операции
+//   - userID: идентификатор пользователя
+//
+// Возвращает:
+//   - []*authmodels.Session: активные сессии, начиная с самых новых
+//   - error: ошибка операции или nil при успехе
+func (uc *AuthUseCase) ListSessions(ctx context.Context, userID uuid.UUID) ([]*authmodels.Session, error) {
+	const op = "AuthUseCase.ListSessions"
+	log := logger.ContextLogger(ctx, nil).With(zap.String("op", op), zap.String("userId", userID.String()))
+
+	if userID == uuid.Nil {
+		return nil, domainerrors.ErrUserNotFound
+	}
+
+	now := time.Now()
+	sessions := make([]*authmodels.Session, 0)
+	for offset := 0; ; offset += sessionsPageSize {
+		tokens, err := uc.findUserTokensWithRetry(ctx, userID, sessionsPageSize, offset)
+		if err != nil {
+			log.Error("Failed to find user tokens", zap.Error(err))
+			return nil, fmt.Errorf("%s: %w", op, domainerrors.ErrInternalServerError)
+		}
+
+		for _, token := range tokens {
+			if token == nil || token.IsRevoked || !token.ExpiresAt.After(now) {
+				continue
+			}
+			sessions = append(sessions, authmodels.SessionFromToken(token))
+		}
+
+		if len(tokens) < sessionsPageSize {
+			break
+		}
+	}
+
+	log.Debug("Active sessions listed", zap.Int("count", len(sessions)))
+	return sessions, nil
+}
+
+// RevokeSession завершает одну сессию пользователя, аннулируя соответствующий
+// refresh токен. Перед отзывом проверяется, что сессия принадлежит пользователю;
+// чужая сессия не отличается от несуществующей, чтобы не раскрывать ее наличие.
+//
+// Параметры:
+//   - ctx: контекст выполнения операции
+//   - userID: идентификатор пользователя, выполняющего запрос
+//   - tokenID: идентификатор сессии (refresh токена)
+//
+// Возвращает:
+//   - error: ErrTokenNotFound если сессия не найдена или принадлежит другому пользователю,
+//     ошибка операции или nil при успехе
+func (uc *AuthUseCase) RevokeSession(ctx context.Context, userID, tokenID uuid.UUID) error {
+	const op = "AuthUseCase.RevokeSession"
+	log := logger.ContextLogger(ctx, nil).With(
+		zap.String("op", op),
+		zap.String("userId", userID.String()),
+		zap.String("tokenId", tokenID.String()),
+	)
+
+	if userID == uuid.Nil {
+		return domainerrors.ErrUserNotFound
+	}
+
+	if tokenID == uuid.Nil {
+		return domainerrors.ErrTokenNotFound
+	}
+
+	token, err := uc.tokenRepo.FindByID(ctx, tokenID)
+	if err != nil {
+		log.Error("Failed to find token", zap.Error(err))
+		return fmt.Errorf("%s: %w", op, domainerrors.ErrInternalServerError)
+	}
+
+	if token == nil {
+		log.Debug("Session not found")
+		return domainerrors.ErrTokenNotFound
+	}
+
+	if token.UserID != userID {
+		log.Warn("Attempt to revoke another user's session", zap.String("ownerId", token.UserID.String()))
+		return domainerrors.ErrTokenNotFound
+	}
+
+	if token.IsRevoked {
+		log.Debug("Session already revoked")
+		return nil
+	}
+
+	if err := uc.tokenRepo.RevokeToken(ctx, token.TokenStr); err != nil {
+		log.Error("Failed to revoke token", zap.Error(err))
+		return fmt.Errorf("%s: %w", op, domainerrors.ErrInternalServerError)
+	}
+
+	log.Info("Session revoked successfully")
+	return nil
+}
+
+// RevokeAllSessions завершает все сессии пользователя (выход на всех устройствах),
+// аннулируя все его refresh токены.
+//
+// Параметры:
+//   - ctx: контекст выполнения операции
+//   - userID: идентификатор пользователя
+//
+// Возвращает:
+//   - error: ошибка операции или nil при успехе
+func (uc *AuthUseCase) RevokeAllSessions(ctx context.Context, userID uuid.UUID) error {
+	const op = "AuthUseCase.RevokeAllSessions"
+	log := logger.ContextLogger(ctx, nil).With(zap.String("op", op), zap.String("userId", userID.String()))
+
+	if userID == uuid.Nil {
+		return domainerrors.ErrUserNotFound
+	}
+
+	if err := uc.tokenRepo.RevokeAllUserTokens(ctx, userID); err != nil {
+		log.Error("Failed to revoke user tokens", zap.Error(err))
+		return fmt.Errorf("%s: %w", op, domainerrors.ErrInternalServerError)
+	}
+
+	log.Info("All sessions revoked successfully")
+	return nil
+}
+
 // CleanupExpiredTokens выполняет очистку истекших токенов из базы данных.
 // Эта операция может выполняться периодически для поддержания базы данных в актуальном
 // состоянии и предотвращения её избыточного роста.
@@ -385,18 +864,20 @@ func (uc *AuthUseCase) Logout(ctx context.Context, tokenStr string) error {
 //   - ctx: контекст выполнения операции
 //
 // Возвращает:
+//   - int64: количество удаленных токенов
 //   - error: ошибка операции или nil при успешной очистке
-func (uc *AuthUseCase) CleanupExpiredTokens(ctx context.Context) error {
+func (uc *AuthUseCase) CleanupExpiredTokens(ctx context.Context) (int64, error) {
 	const op = "AuthUseCase.CleanupExpiredTokens"
 	log := logger.ContextLogger(ctx, nil).With(zap.String("op", op))
 
-	if err := uc.tokenRepo.DeleteExpiredTokens(ctx, time.Now()); err != nil {
+	deleted, err := uc.tokenRepo.DeleteExpiredTokens(ctx, time.Now())
+	if err != nil {
 		log.Error("Failed to delete expired tokens", zap.Error(err))
-		return fmt.Errorf("%s: %w", op, domainerrors.ErrInternalServerError)
+		return 0, fmt.Errorf("%s: %w", op, domainerrors.ErrInternalServerError)
 	}
 
-	log.Info("Expired tokens cleaned up successfully")
-	return nil
+	log.Debug("Expired tokens cleaned up successfully", zap.Int64("deleted", deleted))
+	return deleted, nil
 }
 
 // Close освобождает ресурсы, используемые AuthUseCase. В текущей реализации
@@ -408,3 +889,77 @@ func (uc *AuthUseCase) CleanupExpiredTokens(ctx context.Context) error {
 func (uc *AuthUseCase) Close() error {
 	return nil
 }
+
+// GetStats возвращает сводную статистику сервиса: количество пользователей
+// и состояние пула соединений с базой данных.
+//
+// Возвращает:
+//   - *system.AuthStats: статистика сервиса
+//   - error: ошибка операции или nil при успехе
+func (uc *AuthUseCase) GetStats(ctx context.Context) (*system.AuthStats, error) {
+	const op = "AuthUseCase.GetStats"
+	log := logger.ContextLogger(ctx, nil).With(zap.String("op", op))
+
+	totalUsers, err := uc.userRepo.Count(ctx)
+	if err != nil {
+		log.Error("Failed to count users", zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", op, domainerrors.ErrInternalServerError)
+	}
+
+	stats := &system.AuthStats{TotalUsers: totalUsers}
+	if uc.dbPoolStats != nil {
+		stats.DBPool = uc.dbPoolStats.PoolStats()
+	}
+
+	return stats, nil
+}
+
+// withinTransaction выполняет fn в транзакции, если задан менеджер транзакций.
+func (uc *AuthUseCase) withinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if uc.txManager == nil {
+		return fn(ctx)
+	}
+	return uc.txManager.WithinTransaction(ctx, fn)
+}
+
+// findUserTokensWithRetry получает страницу токенов пользователя с повторными попытками
+// при временных ошибках хранилища.
+func (uc *AuthUseCase) findUserTokensWithRetry(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*authmodels.Token, error) {
+	var lastErr error
+
+	// Повторные попытки с экспоненциальной задержкой
+	for attempt := 0; attempt < maxRetries; attempt++ {
+		if attempt > 0 {
+			backoffDuration := time.Duration(100*(1<<attempt)) * time.Millisecond
+			select {
+			case <-ctx.Done():
+				return nil, fmt.Errorf("context cancelled during retry: %w", ctx.Err())
+			case <-time.After(backoffDuration):
+			}
+		}
+
+		tokens, err := uc.tokenRepo.FindByUserID(ctx, userID, limit, offset)
+		if err == nil {
+			return tokens, nil
+		}
+
+		lastErr = err
+		if !isTransientError(err) {
+			break
+		}
+	}
+
+	return nil, lastErr
+}
+
+// isTransientError сообщает, что ошибка хранилища может не повториться при следующей попытке.
+func isTransientError(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return true
+	}
+
+	errMsg := err.Error()
+	return strings.Contains(errMsg, "timeout") ||
+		strings.Contains(errMsg, "temporary") ||
+		strings.Contains(errMsg, "retriable")
+}
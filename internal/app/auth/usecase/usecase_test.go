@@ -8,10 +8,12 @@ import (
 
 	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
 	authmodels "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/auth"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/system"
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
 
@@ -109,6 +111,11 @@ func (m *MockUserRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return args.Error(0)
 }
 
+func (m *MockUserRepository) Count(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *MockUserRepository) Update(ctx context.Context, user *authmodels.User) error {
 	args := m.Called(ctx, user)
 	return args.Error(0)
@@ -139,8 +146,8 @@ func (m *MockTokenRepository) FindByTokenString(ctx context.Context, tokenStr st
 	return args.Get(0).(*authmodels.Token), args.Error(1)
 }
 
-func (m *MockTokenRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*authmodels.Token, error) {
-	args := m.Called(ctx, userID)
+func (m *MockTokenRepository) FindByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*authmodels.Token, error) {
+	args := m.Called(ctx, userID, limit, offset)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -152,9 +159,14 @@ func (m *MockTokenRepository) RevokeToken(ctx context.Context, tokenStr string)
 	return args.Error(0)
 }
 
-func (m *MockTokenRepository) DeleteExpiredTokens(ctx context.Context, before time.Time) error {
+func (m *MockTokenRepository) RevokeIfActive(ctx context.Context, tokenStr string) (bool, error) {
+	args := m.Called(ctx, tokenStr)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTokenRepository) DeleteExpiredTokens(ctx context.Context, before time.Time) (int64, error) {
 	args := m.Called(ctx, before)
-	return args.Error(0)
+	return args.Get(0).(int64), args.Error(1)
 }
 
 func (m *MockTokenRepository) RevokeAllUserTokens(ctx context.Context, userID uuid.UUID) error {
@@ -293,7 +305,7 @@ func TestRegister(t *testing.T) {
 
 			tt.mockSetup(userRepo, passwordSvc)
 
-			uc := NewAuthUseCase(userRepo, tokenRepo, passwordSvc, jwtSvc)
+			uc := NewAuthUseCase(userRepo, tokenRepo, passwordSvc, jwtSvc, false)
 
 			userID, err := uc.Register(ctx, tt.login, tt.password)
 
@@ -406,7 +418,7 @@ func TestLogin(t *testing.T) {
 
 			tt.mockSetup(userRepo, passwordSvc, jwtSvc, tokenRepo)
 
-			uc := NewAuthUseCase(userRepo, tokenRepo, passwordSvc, jwtSvc)
+			uc := NewAuthUseCase(userRepo, tokenRepo, passwordSvc, jwtSvc, false)
 
 			tokenPair, err := uc.Login(ctx, tt.login, tt.password)
 
@@ -483,7 +495,7 @@ func TestValidateToken(t *testing.T) {
 
 			tt.mockSetup(jwtSvc, userRepo)
 
-			uc := NewAuthUseCase(userRepo, tokenRepo, passwordSvc, jwtSvc)
+			uc := NewAuthUseCase(userRepo, tokenRepo, passwordSvc, jwtSvc, false)
 
 			resultUserID, err := uc.ValidateToken(ctx, tt.token)
 
@@ -524,6 +536,8 @@ func TestRefreshToken(t *testing.T) {
 					TokenStr:  "valid-refresh-token",
 					ExpiresAt: expirationTime,
 					IsRevoked: false,
+					UserAgent: "Mozilla/5.0",
+					IP:        "203.0.113.7",
 				}, nil)
 
 				userRepo.On("FindByID", mock.Anything, userID).Return(&authmodels.User{
@@ -531,7 +545,7 @@ func TestRefreshToken(t *testing.T) {
 					Login: "testuser",
 				}, nil)
 
-				tokenRepo.On("RevokeToken", mock.Anything, "valid-refresh-token").Return(nil)
+				tokenRepo.On("RevokeIfActive", mock.Anything, "valid-refresh-token").Return(true, nil)
 
 				jwtSvc.On("GenerateTokens", mock.Anything, userID, "testuser").Return(&authmodels.TokenPair{
 					AccessToken:  "new-access-token",
@@ -541,7 +555,8 @@ func TestRefreshToken(t *testing.T) {
 				jwtSvc.On("GetRefreshTokenTTL").Return(24 * time.Hour)
 
 				tokenRepo.On("Store", mock.Anything, mock.MatchedBy(func(token *authmodels.Token) bool {
-					return token.UserID == userID && token.TokenStr == "new-refresh-token" && !token.IsRevoked
+					return token.UserID == userID && token.TokenStr == "new-refresh-token" && !token.IsRevoked &&
+						token.UserAgent == "Mozilla/5.0" && token.IP == "203.0.113.7"
 				})).Return(nil)
 			},
 			expectedError: nil,
@@ -608,7 +623,7 @@ func TestRefreshToken(t *testing.T) {
 
 			tt.mockSetup(jwtSvc, tokenRepo, userRepo)
 
-			uc := NewAuthUseCase(userRepo, tokenRepo, passwordSvc, jwtSvc)
+			uc := NewAuthUseCase(userRepo, tokenRepo, passwordSvc, jwtSvc, false)
 
 			tokenPair, err := uc.RefreshToken(ctx, tt.token)
 
@@ -630,6 +645,1040 @@ func TestRefreshToken(t *testing.T) {
 	}
 }
 
+func TestRefreshTokenMaxAge(t *testing.T) {
+	userID := uuid.New()
+	maxAge := 7 * 24 * time.Hour
+
+	tests := []struct {
+		name           string
+		familyIssuedAt time.Time
+		expectedError  error
+	}{
+		{
+			name:           "Family older than max age",
+			familyIssuedAt: time.Now().Add(-maxAge - time.Hour),
+			expectedError:  domainerrors.ErrSessionTooOld,
+		},
+		{
+			name:           "Family within max age",
+			familyIssuedAt: time.Now().Add(-maxAge + time.Hour),
+			expectedError:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := setupTestContext()
+			userRepo := new(MockUserRepository)
+			tokenRepo := new(MockTokenRepository)
+			passwordSvc := new(MockPasswordService)
+			jwtSvc := new(MockJWTService)
+
+			// Сам токен действителен и не истек, отличается только возраст семейства
+			jwtSvc.On("ParseToken", mock.Anything, "family-token").Return(map[string]interface{}{"user_id": userID.String()}, nil)
+			tokenRepo.On("FindByTokenString", mock.Anything, "family-token").Return(&authmodels.Token{
+				ID:             uuid.New(),
+				UserID:         userID,
+				TokenStr:       "family-token",
+				ExpiresAt:      time.Now().Add(24 * time.Hour),
+				CreatedAt:      time.Now().Add(-time.Hour),
+				FamilyIssuedAt: tt.familyIssuedAt,
+			}, nil)
+
+			if tt.expectedError == nil {
+				userRepo.On("FindByID", mock.Anything, userID).Return(&authmodels.User{ID: userID, Login: "testuser"}, nil)
+				tokenRepo.On("RevokeIfActive", mock.Anything, "family-token").Return(true, nil)
+				jwtSvc.On("GenerateTokens", mock.Anything, userID, "testuser").Return(&authmodels.TokenPair{
+					AccessToken:  "new-access-token",
+					RefreshToken: "new-refresh-token",
+				}, nil)
+				jwtSvc.On("GetRefreshTokenTTL").Return(24 * time.Hour)
+				tokenRepo.On("Store", mock.Anything, mock.MatchedBy(func(token *authmodels.Token) bool {
+					return token.TokenStr == "new-refresh-token" && token.FamilyIssuedAt.Equal(tt.familyIssuedAt)
+				})).Return(nil)
+			}
+
+			uc := NewAuthUseCase(userRepo, tokenRepo, passwordSvc, jwtSvc, false)
+			uc.SetRefreshTokenMaxAge(maxAge)
+
+			tokenPair, err := uc.RefreshToken(ctx, "family-token")
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, tokenPair)
+				tokenRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+				tokenRepo.AssertNotCalled(t, "RevokeIfActive", mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "new-refresh-token", tokenPair.RefreshToken)
+			}
+
+			userRepo.AssertExpectations(t)
+			tokenRepo.AssertExpectations(t)
+			jwtSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRefreshTokenReuseDetection(t *testing.T) {
+	userID := uuid.New()
+	revokedToken := &authmodels.Token{
+		ID:        uuid.New(),
+		UserID:    userID,
+		TokenStr:  "replayed-token",
+		ExpiresAt: time.Now().Add(24 * time.Hour),
+		IsRevoked: true,
+	}
+
+	tests := []struct {
+		name          string
+		revokeAllErr  error
+		expectedError error
+	}{
+		{
+			name:          "Replay revokes token family",
+			revokeAllErr:  nil,
+			expectedError: domainerrors.ErrTokenRevoked,
+		},
+		{
+			name:          "Revoke all fails",
+			revokeAllErr:  errors.New("database error"),
+			expectedError: domainerrors.ErrInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := setupTestContext()
+			userRepo := new(MockUserRepository)
+			tokenRepo := new(MockTokenRepository)
+			passwordSvc := new(MockPasswordService)
+			jwtSvc := new(MockJWTService)
+
+			jwtSvc.On("ParseToken", mock.Anything, "replayed-token").Return(map[string]interface{}{"user_id": userID.String()}, nil)
+			tokenRepo.On("FindByTokenString", mock.Anything, "replayed-token").Return(revokedToken, nil)
+			tokenRepo.On("RevokeAllUserTokens", mock.Anything, userID).Return(tt.revokeAllErr).Once()
+
+			uc := NewAuthUseCase(userRepo, tokenRepo, passwordSvc, jwtSvc, true)
+
+			tokenPair, err := uc.RefreshToken(ctx, "replayed-token")
+
+			assert.Nil(t, tokenPair)
+			assert.ErrorIs(t, err, tt.expectedError)
+
+			tokenRepo.AssertExpectations(t)
+			tokenRepo.AssertNotCalled(t, "RevokeIfActive", mock.Anything, mock.Anything)
+			tokenRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+			jwtSvc.AssertNotCalled(t, "GenerateTokens", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestRefreshTokenConcurrentReuse(t *testing.T) {
+	userID := uuid.New()
+
+	for name, detectReuse := range map[string]bool{"DetectionEnabled": true, "DetectionDisabled": false} {
+		t.Run(name, func(t *testing.T) {
+			ctx, _ := setupTestContext()
+			userRepo := new(MockUserRepository)
+			tokenRepo := new(MockTokenRepository)
+			jwtSvc := new(MockJWTService)
+
+			// Токен еще действителен при чтении, но параллельный запрос успевает отозвать его первым
+			jwtSvc.On("ParseToken", mock.Anything, "raced-token").Return(map[string]interface{}{"user_id": userID.String()}, nil)
+			tokenRepo.On("FindByTokenString", mock.Anything, "raced-token").Return(&authmodels.Token{
+				ID:        uuid.New(),
+				UserID:    userID,
+				TokenStr:  "raced-token",
+				ExpiresAt: time.Now().Add(24 * time.Hour),
+			}, nil)
+			userRepo.On("FindByID", mock.Anything, userID).Return(&authmodels.User{ID: userID, Login: "testuser"}, nil)
+			tokenRepo.On("RevokeIfActive", mock.Anything, "raced-token").Return(false, nil)
+			if detectReuse {
+				tokenRepo.On("RevokeAllUserTokens", mock.Anything, userID).Return(nil).Once()
+			}
+
+			uc := NewAuthUseCase(userRepo, tokenRepo, new(MockPasswordService), jwtSvc, detectReuse)
+
+			tokenPair, err := uc.RefreshToken(ctx, "raced-token")
+
+			assert.Nil(t, tokenPair)
+			assert.ErrorIs(t, err, domainerrors.ErrTokenRevoked)
+			tokenRepo.AssertExpectations(t)
+			if !detectReuse {
+				tokenRepo.AssertNotCalled(t, "RevokeAllUserTokens", mock.Anything, mock.Anything)
+			}
+			tokenRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+			jwtSvc.AssertNotCalled(t, "GenerateTokens", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	userID := uuid.New()
+
+	tests := []struct {
+		name          string
+		oldPassword   string
+		newPassword   string
+		revokeAll     bool
+		mockSetup     func(*MockUserRepository, *MockTokenRepository, *MockPasswordService)
+		expectedError error
+	}{
+		{
+			name:        "Success",
+			oldPassword: "old-password",
+			newPassword: "new-password",
+			revokeAll:   true,
+			mockSetup: func(userRepo *MockUserRepository, tokenRepo *MockTokenRepository, passwordSvc *MockPasswordService) {
+				userRepo.On("FindByID", mock.Anything, userID).Return(&authmodels.User{
+					ID:           userID,
+					Login:        "testuser",
+					PasswordHash: "old-hash",
+				}, nil)
+				passwordSvc.On("Verify", mock.Anything, "old-password", "old-hash").Return(true, nil)
+				passwordSvc.On("Hash", mock.Anything, "new-password").Return("new-hash", nil)
+				userRepo.On("Update", mock.Anything, mock.MatchedBy(func(user *authmodels.User) bool {
+					return user.ID == userID && user.PasswordHash == "new-hash"
+				})).Return(nil)
+				tokenRepo.On("RevokeAllUserTokens", mock.Anything, userID).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:        "SuccessWithoutRevoke",
+			oldPassword: "old-password",
+			newPassword: "new-password",
+			revokeAll:   false,
+			mockSetup: func(userRepo *MockUserRepository, tokenRepo *MockTokenRepository, passwordSvc *MockPasswordService) {
+				userRepo.On("FindByID", mock.Anything, userID).Return(&authmodels.User{
+					ID:           userID,
+					PasswordHash: "old-hash",
+				}, nil)
+				passwordSvc.On("Verify", mock.Anything, "old-password", "old-hash").Return(true, nil)
+				passwordSvc.On("Hash", mock.Anything, "new-password").Return("new-hash", nil)
+				userRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:        "WrongOldPassword",
+			oldPassword: "wrong-password",
+			newPassword: "new-password",
+			revokeAll:   true,
+			mockSetup: func(userRepo *MockUserRepository, tokenRepo *MockTokenRepository, passwordSvc *MockPasswordService) {
+				userRepo.On("FindByID", mock.Anything, userID).Return(&authmodels.User{
+					ID:           userID,
+					PasswordHash: "old-hash",
+				}, nil)
+				passwordSvc.On("Verify", mock.Anything, "wrong-password", "old-hash").Return(false, nil)
+			},
+			expectedError: domainerrors.ErrInvalidCredentials,
+		},
+		{
+			name:          "SameAsOld",
+			oldPassword:   "same-password",
+			newPassword:   "same-password",
+			revokeAll:     true,
+			mockSetup:     func(*MockUserRepository, *MockTokenRepository, *MockPasswordService) {},
+			expectedError: domainerrors.ErrSamePassword,
+		},
+		{
+			name:          "WeakNewPassword",
+			oldPassword:   "old-password",
+			newPassword:   "short",
+			revokeAll:     true,
+			mockSetup:     func(*MockUserRepository, *MockTokenRepository, *MockPasswordService) {},
+			expectedError: domainerrors.ErrWeakPassword,
+		},
+		{
+			name:        "UserNotFound",
+			oldPassword: "old-password",
+			newPassword: "new-password",
+			revokeAll:   true,
+			mockSetup: func(userRepo *MockUserRepository, tokenRepo *MockTokenRepository, passwordSvc *MockPasswordService) {
+				userRepo.On("FindByID", mock.Anything, userID).Return(nil, nil)
+			},
+			expectedError: domainerrors.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := setupTestContext()
+			userRepo := new(MockUserRepository)
+			tokenRepo := new(MockTokenRepository)
+			passwordSvc := new(MockPasswordService)
+			jwtSvc := new(MockJWTService)
+
+			tt.mockSetup(userRepo, tokenRepo, passwordSvc)
+
+			uc := NewAuthUseCase(userRepo, tokenRepo, passwordSvc, jwtSvc, false)
+			uc.SetRevokeOnPasswordChange(tt.revokeAll)
+
+			err := uc.ChangePassword(ctx, userID, tt.oldPassword, tt.newPassword)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				userRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			if !tt.revokeAll {
+				tokenRepo.AssertNotCalled(t, "RevokeAllUserTokens", mock.Anything, mock.Anything)
+			}
+
+			userRepo.AssertExpectations(t)
+			tokenRepo.AssertExpectations(t)
+			passwordSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListSessions(t *testing.T) {
+	userID := uuid.New()
+	activeID := uuid.New()
+	now := time.Now()
+
+	tests := []struct {
+		name          string
+		userID        uuid.UUID
+		mockSetup     func(*MockTokenRepository)
+		expectedIDs   []uuid.UUID
+		expectedError error
+	}{
+		{
+			name:   "FiltersRevokedAndExpired",
+			userID: userID,
+			mockSetup: func(tokenRepo *MockTokenRepository) {
+				tokenRepo.On("FindByUserID", mock.Anything, userID, sessionsPageSize, 0).Return([]*authmodels.Token{
+					{
+						ID:        activeID,
+						UserID:    userID,
+						TokenStr:  "active-token",
+						CreatedAt: now.Add(-time.Hour),
+						ExpiresAt: now.Add(time.Hour),
+						UserAgent: "Mozilla/5.0",
+						IP:        "203.0.113.7",
+					},
+					{
+						ID:        uuid.New(),
+						UserID:    userID,
+						TokenStr:  "revoked-token",
+						ExpiresAt: now.Add(time.Hour),
+						IsRevoked: true,
+					},
+					{
+						ID:        uuid.New(),
+						UserID:    userID,
+						TokenStr:  "expired-token",
+						ExpiresAt: now.Add(-time.Minute),
+					},
+				}, nil)
+			},
+			expectedIDs: []uuid.UUID{activeID},
+		},
+		{
+			name:   "NoTokens",
+			userID: userID,
+			mockSetup: func(tokenRepo *MockTokenRepository) {
+				tokenRepo.On("FindByUserID", mock.Anything, userID, sessionsPageSize, 0).Return([]*authmodels.Token{}, nil)
+			},
+			expectedIDs: []uuid.UUID{},
+		},
+		{
+			name:   "RepositoryError",
+			userID: userID,
+			mockSetup: func(tokenRepo *MockTokenRepository) {
+				tokenRepo.On("FindByUserID", mock.Anything, userID, sessionsPageSize, 0).Return(nil, errors.New("database error"))
+			},
+			expectedError: domainerrors.ErrInternalServerError,
+		},
+		{
+			name:          "NilUserID",
+			userID:        uuid.Nil,
+			mockSetup:     func(*MockTokenRepository) {},
+			expectedError: domainerrors.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := setupTestContext()
+			tokenRepo := new(MockTokenRepository)
+
+			tt.mockSetup(tokenRepo)
+
+			uc := NewAuthUseCase(new(MockUserRepository), tokenRepo, new(MockPasswordService), new(MockJWTService), false)
+
+			sessions, err := uc.ListSessions(ctx, tt.userID)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, sessions)
+			} else {
+				assert.NoError(t, err)
+				ids := make([]uuid.UUID, 0, len(sessions))
+				for _, session := range sessions {
+					ids = append(ids, session.ID)
+				}
+				assert.Equal(t, tt.expectedIDs, ids)
+			}
+
+			tokenRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListSessionsPaging(t *testing.T) {
+	ctx, _ := setupTestContext()
+	userID := uuid.New()
+	expiresAt := time.Now().Add(time.Hour)
+
+	fullPage := make([]*authmodels.Token, sessionsPageSize)
+	for i := range fullPage {
+		fullPage[i] = &authmodels.Token{ID: uuid.New(), UserID: userID, ExpiresAt: expiresAt}
+	}
+	last := &authmodels.Token{ID: uuid.New(), UserID: userID, ExpiresAt: expiresAt}
+
+	tokenRepo := new(MockTokenRepository)
+	tokenRepo.On("FindByUserID", mock.Anything, userID, sessionsPageSize, 0).Return(fullPage, nil).Once()
+	tokenRepo.On("FindByUserID", mock.Anything, userID, sessionsPageSize, sessionsPageSize).Return([]*authmodels.Token{last}, nil).Once()
+
+	uc := NewAuthUseCase(new(MockUserRepository), tokenRepo, new(MockPasswordService), new(MockJWTService), false)
+
+	sessions, err := uc.ListSessions(ctx, userID)
+	require.NoError(t, err)
+	assert.Len(t, sessions, sessionsPageSize+1)
+	assert.Equal(t, last.ID, sessions[sessionsPageSize].ID)
+	tokenRepo.AssertExpectations(t)
+}
+
+func TestListSessionsRetry(t *testing.T) {
+	userID := uuid.New()
+	token := &authmodels.Token{ID: uuid.New(), UserID: userID, ExpiresAt: time.Now().Add(time.Hour)}
+
+	t.Run("TransientErrorIsRetried", func(t *testing.T) {
+		ctx, _ := setupTestContext()
+		tokenRepo := new(MockTokenRepository)
+		tokenRepo.On("FindByUserID", mock.Anything, userID, sessionsPageSize, 0).Return(nil, errors.New("temporary network error")).Once()
+		tokenRepo.On("FindByUserID", mock.Anything, userID, sessionsPageSize, 0).Return([]*authmodels.Token{token}, nil).Once()
+
+		uc := NewAuthUseCase(new(MockUserRepository), tokenRepo, new(MockPasswordService), new(MockJWTService), false)
+
+		sessions, err := uc.ListSessions(ctx, userID)
+		require.NoError(t, err)
+		require.Len(t, sessions, 1)
+		assert.Equal(t, token.ID, sessions[0].ID)
+		tokenRepo.AssertExpectations(t)
+	})
+
+	t.Run("PersistentTransientErrorGivesUp", func(t *testing.T) {
+		ctx, _ := setupTestContext()
+		tokenRepo := new(MockTokenRepository)
+		tokenRepo.On("FindByUserID", mock.Anything, userID, sessionsPageSize, 0).Return(nil, errors.New("connection timeout")).Times(maxRetries)
+
+		uc := NewAuthUseCase(new(MockUserRepository), tokenRepo, new(MockPasswordService), new(MockJWTService), false)
+
+		_, err := uc.ListSessions(ctx, userID)
+		assert.ErrorIs(t, err, domainerrors.ErrInternalServerError)
+		tokenRepo.AssertExpectations(t)
+	})
+
+	t.Run("PermanentErrorIsNotRetried", func(t *testing.T) {
+		ctx, _ := setupTestContext()
+		tokenRepo := new(MockTokenRepository)
+		tokenRepo.On("FindByUserID", mock.Anything, userID, sessionsPageSize, 0).Return(nil, errors.New("relation does not exist")).Once()
+
+		uc := NewAuthUseCase(new(MockUserRepository), tokenRepo, new(MockPasswordService), new(MockJWTService), false)
+
+		_, err := uc.ListSessions(ctx, userID)
+		assert.ErrorIs(t, err, domainerrors.ErrInternalServerError)
+		tokenRepo.AssertExpectations(t)
+	})
+}
+
+func TestGetUser(t *testing.T) {
+	userID := uuid.New()
+	createdAt := time.Now().Add(-24 * time.Hour)
+	updatedAt := time.Now()
+
+	tests := []struct {
+		name          string
+		userID        uuid.UUID
+		mockSetup     func(*MockUserRepository)
+		expectedError error
+	}{
+		{
+			name:   "Found",
+			userID: userID,
+			mockSetup: func(userRepo *MockUserRepository) {
+				userRepo.On("FindByID", mock.Anything, userID).Return(&authmodels.User{
+					ID:           userID,
+					Login:        "testuser",
+					PasswordHash: "$argon2id$v=19$m=65536,t=1,p=4$salt$hash",
+					CreatedAt:    createdAt,
+					UpdatedAt:    updatedAt,
+				}, nil)
+			},
+		},
+		{
+			name:   "NotFound",
+			userID: userID,
+			mockSetup: func(userRepo *MockUserRepository) {
+				userRepo.On("FindByID", mock.Anything, userID).Return(nil, nil)
+			},
+			expectedError: domainerrors.ErrUserNotFound,
+		},
+		{
+			name:   "RepositoryError",
+			userID: userID,
+			mockSetup: func(userRepo *MockUserRepository) {
+				userRepo.On("FindByID", mock.Anything, userID).Return(nil, errors.New("database error"))
+			},
+			expectedError: domainerrors.ErrInternalServerError,
+		},
+		{
+			name:          "NilUserID",
+			userID:        uuid.Nil,
+			mockSetup:     func(*MockUserRepository) {},
+			expectedError: domainerrors.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := setupTestContext()
+			userRepo := new(MockUserRepository)
+
+			tt.mockSetup(userRepo)
+
+			uc := NewAuthUseCase(userRepo, new(MockTokenRepository), new(MockPasswordService), new(MockJWTService), false)
+
+			user, err := uc.GetUser(ctx, tt.userID)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, user)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, userID, user.ID)
+				assert.Equal(t, "testuser", user.Login)
+				assert.Equal(t, createdAt, user.CreatedAt)
+				assert.Equal(t, updatedAt, user.UpdatedAt)
+				assert.Empty(t, user.PasswordHash)
+			}
+
+			userRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// stubTxManager выполняет fn без транзакции и считает вызовы.
+type stubTxManager struct {
+	calls int
+}
+
+func (m *stubTxManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.calls++
+	return fn(ctx)
+}
+
+func TestDeleteUser(t *testing.T) {
+	userID := uuid.New()
+	user := &authmodels.User{ID: userID, Login: "testuser", PasswordHash: "hashed_password"}
+
+	tests := []struct {
+		name          string
+		userID        uuid.UUID
+		password      string
+		mockSetup     func(*MockUserRepository, *MockTokenRepository, *MockPasswordService)
+		expectedError error
+		expectTx      bool
+	}{
+		{
+			name:     "Success",
+			userID:   userID,
+			password: "password123",
+			mockSetup: func(userRepo *MockUserRepository, tokenRepo *MockTokenRepository, passwordSvc *MockPasswordService) {
+				userRepo.On("FindByID", mock.Anything, userID).Return(user, nil)
+				passwordSvc.On("Verify", mock.Anything, "password123", "hashed_password").Return(true, nil)
+				tokenRepo.On("RevokeAllUserTokens", mock.Anything, userID).Return(nil)
+				userRepo.On("Delete", mock.Anything, userID).Return(nil)
+			},
+			expectTx: true,
+		},
+		{
+			name:     "WrongPassword",
+			userID:   userID,
+			password: "wrong",
+			mockSetup: func(userRepo *MockUserRepository, _ *MockTokenRepository, passwordSvc *MockPasswordService) {
+				userRepo.On("FindByID", mock.Anything, userID).Return(user, nil)
+				passwordSvc.On("Verify", mock.Anything, "wrong", "hashed_password").Return(false, nil)
+			},
+			expectedError: domainerrors.ErrInvalidCredentials,
+		},
+		{
+			name:     "UserNotFound",
+			userID:   userID,
+			password: "password123",
+			mockSetup: func(userRepo *MockUserRepository, _ *MockTokenRepository, _ *MockPasswordService) {
+				userRepo.On("FindByID", mock.Anything, userID).Return(nil, nil)
+			},
+			expectedError: domainerrors.ErrUserNotFound,
+		},
+		{
+			name:     "DeleteError",
+			userID:   userID,
+			password: "password123",
+			mockSetup: func(userRepo *MockUserRepository, tokenRepo *MockTokenRepository, passwordSvc *MockPasswordService) {
+				userRepo.On("FindByID", mock.Anything, userID).Return(user, nil)
+				passwordSvc.On("Verify", mock.Anything, "password123", "hashed_password").Return(true, nil)
+				tokenRepo.On("RevokeAllUserTokens", mock.Anything, userID).Return(nil)
+				userRepo.On("Delete", mock.Anything, userID).Return(errors.New("database error"))
+			},
+			expectedError: domainerrors.ErrInternalServerError,
+			expectTx:      true,
+		},
+		{
+			name:          "NilUserID",
+			userID:        uuid.Nil,
+			mockSetup:     func(*MockUserRepository, *MockTokenRepository, *MockPasswordService) {},
+			expectedError: domainerrors.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := setupTestContext()
+			userRepo := new(MockUserRepository)
+			tokenRepo := new(MockTokenRepository)
+			passwordSvc := new(MockPasswordService)
+			txManager := &stubTxManager{}
+
+			tt.mockSetup(userRepo, tokenRepo, passwordSvc)
+
+			uc := NewAuthUseCase(userRepo, tokenRepo, passwordSvc, new(MockJWTService), false)
+			uc.SetTxManager(txManager)
+
+			err := uc.DeleteUser(ctx, tt.userID, tt.password)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			if tt.expectTx {
+				assert.Equal(t, 1, txManager.calls)
+			} else {
+				assert.Zero(t, txManager.calls)
+				tokenRepo.AssertNotCalled(t, "RevokeAllUserTokens", mock.Anything, mock.Anything)
+				userRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+			}
+
+			userRepo.AssertExpectations(t)
+			tokenRepo.AssertExpectations(t)
+			passwordSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeletedUserTokensStopValidating(t *testing.T) {
+	ctx, _ := setupTestContext()
+	userID := uuid.New()
+	userRepo := new(MockUserRepository)
+	tokenRepo := new(MockTokenRepository)
+	passwordSvc := new(MockPasswordService)
+	jwtSvc := new(MockJWTService)
+
+	userRepo.On("FindByID", mock.Anything, userID).Return(&authmodels.User{ID: userID, PasswordHash: "hashed_password"}, nil).Twice()
+	passwordSvc.On("Verify", mock.Anything, "password123", "hashed_password").Return(true, nil)
+	tokenRepo.On("RevokeAllUserTokens", mock.Anything, userID).Return(nil)
+	userRepo.On("Delete", mock.Anything, userID).Return(nil)
+	jwtSvc.On("ValidateToken", mock.Anything, "access-token").Return(userID, nil)
+
+	uc := NewAuthUseCase(userRepo, tokenRepo, passwordSvc, jwtSvc, false)
+
+	validated, err := uc.ValidateToken(ctx, "access-token")
+	require.NoError(t, err)
+	require.Equal(t, userID, validated)
+
+	require.NoError(t, uc.DeleteUser(ctx, userID, "password123"))
+
+	// После удаления пользователь больше не находится, и подписанный токен отклоняется
+	userRepo.On("FindByID", mock.Anything, userID).Return(nil, nil)
+	validated, err = uc.ValidateToken(ctx, "access-token")
+	assert.ErrorIs(t, err, domainerrors.ErrUserNotFound)
+	assert.Equal(t, uuid.Nil, validated)
+	tokenRepo.AssertCalled(t, "RevokeAllUserTokens", mock.Anything, userID)
+}
+
+func TestLoginStoresClientInfo(t *testing.T) {
+	ctx, _ := setupTestContext()
+	ctx = authmodels.WithClientInfo(ctx, authmodels.ClientInfo{UserAgent: "curl/8.0", IP: "198.51.100.1"})
+
+	userID := uuid.New()
+	userRepo := new(MockUserRepository)
+	tokenRepo := new(MockTokenRepository)
+	passwordSvc := new(MockPasswordService)
+	jwtSvc := new(MockJWTService)
+
+	userRepo.On("FindByLogin", mock.Anything, "testuser").Return(&authmodels.User{
+		ID:           userID,
+		Login:        "testuser",
+		PasswordHash: "hash",
+	}, nil)
+	passwordSvc.On("Verify", mock.Anything, "password123", "hash").Return(true, nil)
+	jwtSvc.On("GenerateTokens", mock.Anything, userID, "testuser").Return(&authmodels.TokenPair{
+		AccessToken:  "access-token",
+		RefreshToken: "refresh-token",
+	}, nil)
+	jwtSvc.On("GetRefreshTokenTTL").Return(24 * time.Hour)
+	tokenRepo.On("Store", mock.Anything, mock.MatchedBy(func(token *authmodels.Token) bool {
+		return token.UserAgent == "curl/8.0" && token.IP == "198.51.100.1"
+	})).Return(nil)
+
+	uc := NewAuthUseCase(userRepo, tokenRepo, passwordSvc, jwtSvc, false)
+
+	_, err := uc.Login(ctx, "testuser", "password123")
+	assert.NoError(t, err)
+
+	tokenRepo.AssertExpectations(t)
+}
+
+func TestLoginRateLimit(t *testing.T) {
+	userID := uuid.New()
+	user := &authmodels.User{ID: userID, Login: "testuser", PasswordHash: "hash"}
+
+	newUseCase := func() (*AuthUseCase, *MockUserRepository, *time.Time) {
+		userRepo := new(MockUserRepository)
+		tokenRepo := new(MockTokenRepository)
+		passwordSvc := new(MockPasswordService)
+		jwtSvc := new(MockJWTService)
+
+		userRepo.On("FindByLogin", mock.Anything, "testuser").Return(user, nil)
+		userRepo.On("FindByLogin", mock.Anything, mock.Anything).Return(nil, nil)
+		passwordSvc.On("Verify", mock.Anything, "password123", "hash").Return(true, nil)
+		passwordSvc.On("Verify", mock.Anything, mock.Anything, "hash").Return(false, nil)
+		jwtSvc.On("GenerateTokens", mock.Anything, userID, "testuser").Return(&authmodels.TokenPair{
+			UserID:       userID,
+			AccessToken:  "access-token",
+			RefreshToken: "refresh-token",
+		}, nil)
+		jwtSvc.On("GetRefreshTokenTTL").Return(24 * time.Hour)
+		tokenRepo.On("Store", mock.Anything, mock.Anything).Return(nil)
+
+		uc := NewAuthUseCase(userRepo, tokenRepo, passwordSvc, jwtSvc, false)
+		uc.SetLoginLimit(3, time.Minute, time.Second, 4*time.Second)
+
+		now := time.Now()
+		uc.loginLimiter.now = func() time.Time { return now }
+		return uc, userRepo, &now
+	}
+
+	withIP := func(ip string) context.Context {
+		ctx, _ := setupTestContext()
+		return authmodels.WithClientInfo(ctx, authmodels.ClientInfo{IP: ip})
+	}
+
+	t.Run("ProgressiveDelayForLogin", func(t *testing.T) {
+		uc, userRepo, now := newUseCase()
+
+		for _, ip := range []string{"198.51.100.1", "198.51.100.2", "198.51.100.3"} {
+			_, err := uc.Login(withIP(ip), "testuser", "wrong")
+			assert.ErrorIs(t, err, domainerrors.ErrInvalidCredentials)
+		}
+
+		_, err := uc.Login(withIP("203.0.113.7"), "testuser", "password123")
+		assert.ErrorIs(t, err, domainerrors.ErrTooManyAttempts, "correct password must wait for the delay")
+		userRepo.AssertNumberOfCalls(t, "FindByLogin", 3)
+
+		*now = now.Add(time.Second)
+		_, err = uc.Login(withIP("198.51.100.4"), "testuser", "wrong")
+		assert.ErrorIs(t, err, domainerrors.ErrInvalidCredentials, "attempt must be allowed once the delay expired")
+
+		*now = now.Add(time.Second)
+		_, err = uc.Login(withIP("203.0.113.7"), "testuser", "password123")
+		assert.ErrorIs(t, err, domainerrors.ErrTooManyAttempts, "delay must double after the next failure")
+
+		*now = now.Add(time.Second)
+		tokens, err := uc.Login(withIP("203.0.113.7"), "testuser", "password123")
+		if assert.NoError(t, err, "login must not stay locked for the whole window") {
+			assert.Equal(t, "access-token", tokens.AccessToken)
+		}
+	})
+
+	t.Run("DelayCappedAtMax", func(t *testing.T) {
+		uc, _, now := newUseCase()
+
+		for i := range 10 {
+			uc.loginLimiter.fail("login:testuser")
+			if i < 9 {
+				*now = now.Add(time.Millisecond)
+			}
+		}
+
+		assert.Equal(t, 4*time.Second, uc.loginLimiter.retryAfter("login:testuser"))
+	})
+
+	t.Run("SuccessResetsLoginCounter", func(t *testing.T) {
+		uc, _, _ := newUseCase()
+
+		for range 2 {
+			_, err := uc.Login(withIP("198.51.100.1"), "testuser", "wrong")
+			assert.ErrorIs(t, err, domainerrors.ErrInvalidCredentials)
+		}
+		_, err := uc.Login(withIP("203.0.113.7"), "testuser", "password123")
+		assert.NoError(t, err)
+
+		for range 2 {
+			_, err = uc.Login(withIP("192.0.2.10"), "testuser", "wrong")
+			assert.ErrorIs(t, err, domainerrors.ErrInvalidCredentials)
+		}
+		_, err = uc.Login(withIP("192.0.2.20"), "testuser", "password123")
+		assert.NoError(t, err, "counter must restart after a successful login")
+	})
+
+	t.Run("LockoutByIP", func(t *testing.T) {
+		uc, _, now := newUseCase()
+		ctx := withIP("198.51.100.1")
+
+		for _, login := range []string{"alice", "bob", "carol"} {
+			_, err := uc.Login(ctx, login, "guess")
+			assert.ErrorIs(t, err, domainerrors.ErrInvalidCredentials)
+		}
+
+		_, err := uc.Login(ctx, "testuser", "password123")
+		assert.ErrorIs(t, err, domainerrors.ErrTooManyAttempts)
+
+		_, err = uc.Login(withIP("203.0.113.7"), "testuser", "password123")
+		assert.NoError(t, err, "other IPs must not be affected")
+
+		*now = now.Add(time.Minute + time.Second)
+		_, err = uc.Login(ctx, "testuser", "password123")
+		assert.NoError(t, err, "IP must be unblocked after the window")
+	})
+
+	t.Run("DeleteUserConfirmation", func(t *testing.T) {
+		uc, userRepo, now := newUseCase()
+		userRepo.On("FindByID", mock.Anything, userID).Return(user, nil)
+		ctx := withIP("198.51.100.1")
+
+		for range 3 {
+			assert.ErrorIs(t, uc.DeleteUser(ctx, userID, "wrong"), domainerrors.ErrInvalidCredentials)
+		}
+
+		assert.ErrorIs(t, uc.DeleteUser(ctx, userID, "password123"), domainerrors.ErrTooManyAttempts)
+		_, err := uc.Login(withIP("203.0.113.7"), "testuser", "password123")
+		assert.ErrorIs(t, err, domainerrors.ErrTooManyAttempts, "failed confirmations must count toward the login limit")
+		userRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+
+		*now = now.Add(time.Second)
+		_, err = uc.Login(withIP("203.0.113.7"), "testuser", "password123")
+		assert.NoError(t, err, "login must be allowed after the delay")
+	})
+
+	t.Run("ChangePasswordConfirmation", func(t *testing.T) {
+		uc, userRepo, _ := newUseCase()
+		userRepo.On("FindByID", mock.Anything, userID).Return(user, nil)
+		ctx := withIP("198.51.100.1")
+
+		for range 3 {
+			assert.ErrorIs(t, uc.ChangePassword(ctx, userID, "wrong", "new-password"), domainerrors.ErrInvalidCredentials)
+		}
+
+		assert.ErrorIs(t, uc.ChangePassword(ctx, userID, "password123", "new-password"), domainerrors.ErrTooManyAttempts)
+		_, err := uc.Login(withIP("203.0.113.7"), "testuser", "password123")
+		assert.ErrorIs(t, err, domainerrors.ErrTooManyAttempts, "failed confirmations must count toward the login limit")
+		userRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		uc, _, _ := newUseCase()
+		uc.SetLoginLimit(0, time.Minute, time.Second, 4*time.Second)
+		ctx := withIP("198.51.100.1")
+
+		for range 10 {
+			_, err := uc.Login(ctx, "testuser", "wrong")
+			assert.ErrorIs(t, err, domainerrors.ErrInvalidCredentials)
+		}
+		_, err := uc.Login(ctx, "testuser", "password123")
+		assert.NoError(t, err)
+	})
+}
+
+func TestRevokeSession(t *testing.T) {
+	userID := uuid.New()
+	otherUserID := uuid.New()
+	sessionID := uuid.New()
+
+	tests := []struct {
+		name          string
+		userID        uuid.UUID
+		sessionID     uuid.UUID
+		mockSetup     func(*MockTokenRepository)
+		expectedError error
+	}{
+		{
+			name:      "Success",
+			userID:    userID,
+			sessionID: sessionID,
+			mockSetup: func(tokenRepo *MockTokenRepository) {
+				tokenRepo.On("FindByID", mock.Anything, sessionID).Return(&authmodels.Token{
+					ID:       sessionID,
+					UserID:   userID,
+					TokenStr: "refresh-token",
+				}, nil)
+				tokenRepo.On("RevokeToken", mock.Anything, "refresh-token").Return(nil)
+			},
+		},
+		{
+			name:      "AnotherUsersSession",
+			userID:    userID,
+			sessionID: sessionID,
+			mockSetup: func(tokenRepo *MockTokenRepository) {
+				tokenRepo.On("FindByID", mock.Anything, sessionID).Return(&authmodels.Token{
+					ID:       sessionID,
+					UserID:   otherUserID,
+					TokenStr: "foreign-token",
+				}, nil)
+			},
+			expectedError: domainerrors.ErrTokenNotFound,
+		},
+		{
+			name:      "AlreadyRevoked",
+			userID:    userID,
+			sessionID: sessionID,
+			mockSetup: func(tokenRepo *MockTokenRepository) {
+				tokenRepo.On("FindByID", mock.Anything, sessionID).Return(&authmodels.Token{
+					ID:        sessionID,
+					UserID:    userID,
+					TokenStr:  "refresh-token",
+					IsRevoked: true,
+				}, nil)
+			},
+		},
+		{
+			name:      "SessionNotFound",
+			userID:    userID,
+			sessionID: sessionID,
+			mockSetup: func(tokenRepo *MockTokenRepository) {
+				tokenRepo.On("FindByID", mock.Anything, sessionID).Return(nil, nil)
+			},
+			expectedError: domainerrors.ErrTokenNotFound,
+		},
+		{
+			name:      "RepositoryError",
+			userID:    userID,
+			sessionID: sessionID,
+			mockSetup: func(tokenRepo *MockTokenRepository) {
+				tokenRepo.On("FindByID", mock.Anything, sessionID).Return(nil, errors.New("database error"))
+			},
+			expectedError: domainerrors.ErrInternalServerError,
+		},
+		{
+			name:      "RevokeError",
+			userID:    userID,
+			sessionID: sessionID,
+			mockSetup: func(tokenRepo *MockTokenRepository) {
+				tokenRepo.On("FindByID", mock.Anything, sessionID).Return(&authmodels.Token{
+					ID:       sessionID,
+					UserID:   userID,
+					TokenStr: "refresh-token",
+				}, nil)
+				tokenRepo.On("RevokeToken", mock.Anything, "refresh-token").Return(errors.New("database error"))
+			},
+			expectedError: domainerrors.ErrInternalServerError,
+		},
+		{
+			name:          "NilSessionID",
+			userID:        userID,
+			sessionID:     uuid.Nil,
+			mockSetup:     func(*MockTokenRepository) {},
+			expectedError: domainerrors.ErrTokenNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := setupTestContext()
+			tokenRepo := new(MockTokenRepository)
+
+			tt.mockSetup(tokenRepo)
+
+			uc := NewAuthUseCase(new(MockUserRepository), tokenRepo, new(MockPasswordService), new(MockJWTService), false)
+
+			err := uc.RevokeSession(ctx, tt.userID, tt.sessionID)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			tokenRepo.AssertExpectations(t)
+			if errors.Is(tt.expectedError, domainerrors.ErrTokenNotFound) {
+				tokenRepo.AssertNotCalled(t, "RevokeToken", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestRevokeAllSessions(t *testing.T) {
+	userID := uuid.New()
+
+	tests := []struct {
+		name          string
+		userID        uuid.UUID
+		mockSetup     func(*MockTokenRepository)
+		expectedError error
+	}{
+		{
+			name:   "Success",
+			userID: userID,
+			mockSetup: func(tokenRepo *MockTokenRepository) {
+				tokenRepo.On("RevokeAllUserTokens", mock.Anything, userID).Return(nil)
+			},
+		},
+		{
+			name:   "RepositoryError",
+			userID: userID,
+			mockSetup: func(tokenRepo *MockTokenRepository) {
+				tokenRepo.On("RevokeAllUserTokens", mock.Anything, userID).Return(errors.New("database error"))
+			},
+			expectedError: domainerrors.ErrInternalServerError,
+		},
+		{
+			name:          "NilUserID",
+			userID:        uuid.Nil,
+			mockSetup:     func(*MockTokenRepository) {},
+			expectedError: domainerrors.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := setupTestContext()
+			tokenRepo := new(MockTokenRepository)
+
+			tt.mockSetup(tokenRepo)
+
+			uc := NewAuthUseCase(new(MockUserRepository), tokenRepo, new(MockPasswordService), new(MockJWTService), false)
+
+			err := uc.RevokeAllSessions(ctx, tt.userID)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			tokenRepo.AssertExpectations(t)
+		})
+	}
+}
+
 func TestLogout(t *testing.T) {
 	userID := uuid.New()
 
@@ -684,7 +1733,7 @@ func TestLogout(t *testing.T) {
 
 			tt.mockSetup(jwtSvc, tokenRepo)
 
-			uc := NewAuthUseCase(userRepo, tokenRepo, passwordSvc, jwtSvc)
+			uc := NewAuthUseCase(userRepo, tokenRepo, passwordSvc, jwtSvc, false)
 
 			err := uc.Logout(ctx, tt.token)
 
@@ -703,9 +1752,10 @@ func TestLogout(t *testing.T) {
 
 func TestCleanupExpiredTokens(t *testing.T) {
 	tests := []struct {
-		name          string
-		mockSetup     func(*MockTokenRepository)
-		expectedError error
+		name            string
+		mockSetup       func(*MockTokenRepository)
+		expectedDeleted int64
+		expectedError   error
 	}{
 		{
 			name: "Success",
@@ -714,14 +1764,15 @@ func TestCleanupExpiredTokens(t *testing.T) {
 					now := time.Now()
 					diff := now.Sub(t)
 					return diff >= 0 && diff < time.Second
-				})).Return(nil)
+				})).Return(int64(3), nil)
 			},
-			expectedError: nil,
+			expectedDeleted: 3,
+			expectedError:   nil,
 		},
 		{
 			name: "Error",
 			mockSetup: func(tokenRepo *MockTokenRepository) {
-				tokenRepo.On("DeleteExpiredTokens", mock.Anything, mock.Anything).Return(errors.New("db error"))
+				tokenRepo.On("DeleteExpiredTokens", mock.Anything, mock.Anything).Return(int64(0), errors.New("db error"))
 			},
 			expectedError: domainerrors.ErrInternalServerError,
 		},
@@ -740,9 +1791,9 @@ func TestCleanupExpiredTokens(t *testing.T) {
 
 			tt.mockSetup(tokenRepo)
 
-			uc := NewAuthUseCase(userRepo, tokenRepo, passwordSvc, jwtSvc)
+			uc := NewAuthUseCase(userRepo, tokenRepo, passwordSvc, jwtSvc, false)
 
-			err := uc.CleanupExpiredTokens(ctx)
+			deleted, err := uc.CleanupExpiredTokens(ctx)
 
 			if tt.expectedError != nil {
 				assert.Error(t, err)
@@ -750,8 +1801,47 @@ func TestCleanupExpiredTokens(t *testing.T) {
 			} else {
 				assert.NoError(t, err)
 			}
+			assert.Equal(t, tt.expectedDeleted, deleted)
 
 			tokenRepo.AssertExpectations(t)
 		})
 	}
 }
+
+type stubDBPoolStats struct {
+	stats system.DBPoolStats
+}
+
+func (s stubDBPoolStats) PoolStats() system.DBPoolStats {
+	return s.stats
+}
+
+func TestGetStats(t *testing.T) {
+	t.Run("CountsUsersAndPool", func(t *testing.T) {
+		ctx, _ := setupTestContext()
+		userRepo := new(MockUserRepository)
+		userRepo.On("Count", mock.Anything).Return(42, nil)
+
+		uc := NewAuthUseCase(userRepo, new(MockTokenRepository), new(MockPasswordService), new(MockJWTService), false)
+		dbPool := system.DBPoolStats{TotalConns: 3, IdleConns: 2, AcquiredConns: 1, MaxConns: 10}
+		uc.SetDBPoolStatsProvider(stubDBPoolStats{stats: dbPool})
+
+		stats, err := uc.GetStats(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 42, stats.TotalUsers)
+		assert.Equal(t, dbPool, stats.DBPool)
+		userRepo.AssertExpectations(t)
+	})
+
+	t.Run("RepositoryError", func(t *testing.T) {
+		ctx, _ := setupTestContext()
+		userRepo := new(MockUserRepository)
+		userRepo.On("Count", mock.Anything).Return(0, errors.New("db down"))
+
+		uc := NewAuthUseCase(userRepo, new(MockTokenRepository), new(MockPasswordService), new(MockJWTService), false)
+
+		stats, err := uc.GetStats(ctx)
+		assert.ErrorIs(t, err, domainerrors.ErrInternalServerError)
+		assert.Nil(t, stats)
+	})
+}
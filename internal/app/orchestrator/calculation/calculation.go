@@ -5,16 +5,24 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
 	"time"
 
 	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/agent"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/system"
 	orchapi "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/api/orchestrator"
 	orchrepo "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/repository/orchestrator"
+	systemrepo "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/repository/system"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/repository/transaction"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/service/parser"
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/tracing"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 )
 
@@ -23,10 +31,18 @@ const (
 	defaultTimeout    = 10 * time.Second
 	validationTimeout = 5 * time.Second
 	parsingTimeout    = 30 * time.Second
+	parseTimeoutMsg   = "Expression parsing timed out"
 	statusTimeout     = 5 * time.Second
 	maxRetries        = 3
 	maxErrorLength    = 500
 	maxOperations     = 500
+	defaultListLimit  = 20
+	maxListLimit      = 100
+
+	cancelledByUserMsg = "cancelled by user"
+	unsavedSiblingMsg  = "cancelled: another operation of the calculation was not saved"
+
+	spanCalculateExpression = "calculation.CalculateExpression"
 )
 
 // UseCaseImpl реализует логику вычисления математических выражений
@@ -34,6 +50,72 @@ type UseCaseImpl struct {
 	calculationRepo orchrepo.CalculationRepository
 	operationRepo   orchrepo.OperationRepository
 	parser          parser.ExpressionParser
+	cancellation    orchapi.OperationCancellation
+	agentPool       orchapi.AgentPool
+	txManager       transaction.TxManager
+
+	// staleRecomputeAfter - порог, после которого вычисление в IN_PROGRESS
+	// пересчитывается при чтении. Ноль отключает пересчет.
+	staleRecomputeAfter time.Duration
+
+	// emptyOperationsGrace - время после создания вычисления, в течение которого
+	// отсутствие операций не считается ошибкой (CreateBatch мог еще не завершиться).
+	emptyOperationsGrace time.Duration
+
+	// parsingTimeout - максимальное время разбора выражения на операции.
+	parsingTimeout time.Duration
+
+	// operationTimes - настроенное время выполнения операций агентами,
+	// по нему оценивается время до завершения нового вычисления.
+	operationTimes map[string]time.Duration
+
+	// dbPoolStats - источник состояния пула соединений для сводной статистики.
+	dbPoolStats systemrepo.DBPoolStatsProvider
+
+	// dbHealth - состояние базы данных. Пока база недоступна, новые вычисления отклоняются.
+	dbHealth systemrepo.DBHealthState
+
+	// partialBatchInsert - сохранять операции по отдельности, не отменяя пакет
+	// из-за ошибки одной операции.
+	partialBatchInsert bool
+
+	// rejectDuplicateOperationIDs - отклонять разбор с повторяющимися ID операций
+	// вместо выдачи повторам новых ID.
+	rejectDuplicateOperationIDs bool
+
+	// agentEstimate - добавлять в ответ на создание вычисления агентов, которые
+	// вероятно возьмут его операции.
+	agentEstimate bool
+
+	// calculationReferences - подставлять в выражение результаты прошлых вычислений
+	// по ссылкам вида calc:{uuid}.
+	calculationReferences bool
+
+	// supportedOperations - имена типов операций, которые умеют выполнять агенты.
+	// Если задано, выражения с другими операциями отклоняются до сохранения вычисления.
+	supportedOperations map[string]struct{}
+
+	// pendingOperationsBudget - наибольшее количество невыполненных операций пользователя
+	// вместе с операциями нового выражения. Ноль отключает проверку.
+	pendingOperationsBudget int
+
+	// listResultCap - наибольшее количество вычислений в одном ответе списка, независимо
+	// от запрошенного лимита. Ноль отключает ограничение.
+	listResultCap int
+
+	// resultCache - общий кэш результатов выражений.
+	resultCache orchrepo.ResultCache
+
+	// resultRounding - округление результата вычисления, в том числе вычисленного при
+	// прогреве кэша. Результаты отдельных операций не округляются.
+	resultRounding orchestrator.Rounding
+
+	// displayRounding - округление результата вычисления для отображения. При отрицательной
+	// точности округленный результат не сохраняется.
+	displayRounding orchestrator.Rounding
+
+	// statusBroker - рассылка смен статуса подписчикам SubscribeCalculation.
+	statusBroker *statusBroker
 }
 
 // Проверка соответствия интерфейсу
@@ -49,16 +131,175 @@ func NewUseCase(
 		calculationRepo: calculationRepo,
 		operationRepo:   operationRepo,
 		parser:          parser,
+		parsingTimeout:  parsingTimeout,
+		resultRounding:  orchestrator.NoRounding,
+		displayRounding: orchestrator.NoRounding,
+		statusBroker:    newStatusBroker(),
+	}
+}
+
+// SetOperationCancellation задает получателя уведомлений об отмененных операциях.
+// Вызывается после создания процессора, так как процессор сам зависит от сервиса вычислений.
+func (uc *UseCaseImpl) SetOperationCancellation(cancellation orchapi.OperationCancellation) {
+	uc.cancellation = cancellation
+}
+
+// SetTxManager задает менеджер транзакций для изменений, затрагивающих несколько репозиториев.
+// Без него такие изменения выполняются без общей транзакции.
+func (uc *UseCaseImpl) SetTxManager(txManager transaction.TxManager) {
+	uc.txManager = txManager
+}
+
+// SetAgentPool задает пул агентов, метрики которого отдает GetPoolStats.
+func (uc *UseCaseImpl) SetAgentPool(agentPool orchapi.AgentPool) {
+	uc.agentPool = agentPool
+}
+
+// SetStaleRecompute включает пересчет статуса в GetCalculation для вычислений,
+// которые находятся в IN_PROGRESS дольше threshold. Неположительное значение отключает пересчет.
+func (uc *UseCaseImpl) SetStaleRecompute(threshold time.Duration) {
+	if threshold < 0 {
+		threshold = 0
+	}
+	uc.staleRecomputeAfter = threshold
+}
+
+// SetEmptyOperationsGrace задает окно, в течение которого только что созданное вычисление
+// без операций остается в ожидании, а не помечается ошибкой. Неположительное значение отключает окно.
+func (uc *UseCaseImpl) SetEmptyOperationsGrace(grace time.Duration) {
+	if grace < 0 {
+		grace = 0
 	}
+	uc.emptyOperationsGrace = grace
+}
+
+// SetParsingTimeout задает максимальное время валидации и разбора выражения.
+// По истечении времени разбор прерывается, а вычисление помечается ошибкой.
+// Неположительное значение возвращает значение по умолчанию.
+func (uc *UseCaseImpl) SetParsingTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		timeout = parsingTimeout
+	}
+	uc.parsingTimeout = timeout
+}
+
+// SetOperationTimes задает время выполнения операций, по которому оценивается
+// время до завершения нового вычисления.
+func (uc *UseCaseImpl) SetOperationTimes(operationTimes map[string]time.Duration) {
+	uc.operationTimes = operationTimes
+}
+
+// SetDBPoolStatsProvider задает источник состояния пула соединений, включаемого в статистику сервиса.
+func (uc *UseCaseImpl) SetDBPoolStatsProvider(provider systemrepo.DBPoolStatsProvider) {
+	uc.dbPoolStats = provider
+}
+
+// SetDBHealth задает источник состояния базы данных. Пока он сообщает о недоступности,
+// CalculateExpression сразу отклоняет новые вычисления с ErrDatabaseUnavailable, а не ждет
+// истечения времени записи в базу. Чтение вычислений не блокируется.
+func (uc *UseCaseImpl) SetDBHealth(state systemrepo.DBHealthState) {
+	uc.dbHealth = state
+}
+
+// SetPartialBatchInsert включает сохранение операций по отдельности: ошибка одной операции
+// не откатывает уже сохраненные. Поскольку итоговая операция выражения через ссылки зависит
+// от всех остальных, вычисление без любой из них не может завершиться: сохраненные операции
+// отменяются, и вычисление переводится в ERROR.
+func (uc *UseCaseImpl) SetPartialBatchInsert(enabled bool) {
+	uc.partialBatchInsert = enabled
+}
+
+// SetRejectDuplicateOperationIDs задает реакцию на повторяющиеся ID операций после разбора.
+// По умолчанию повторы получают новые ID, а ссылки на них исправляются; при enabled
+// вычисление помечается ошибкой, и операции не сохраняются.
+func (uc *UseCaseImpl) SetRejectDuplicateOperationIDs(enabled bool) {
+	uc.rejectDuplicateOperationIDs = enabled
+}
+
+// SetAgentEstimate включает в ответе на создание вычисления список агентов, которые
+// при текущем состоянии пула взяли бы его операции. Оценка требует пула агентов (SetAgentPool)
+// и не резервирует агентов: фактическое распределение может отличаться.
+func (uc *UseCaseImpl) SetAgentEstimate(enabled bool) {
+	uc.agentEstimate = enabled
+}
+
+// SetCalculationReferences разрешает ссылаться в выражении на результаты прошлых вычислений
+// пользователя в виде calc:{uuid}. Ссылки подставляются до разбора, в записи вычисления
+// сохраняется исходное выражение.
+func (uc *UseCaseImpl) SetCalculationReferences(enabled bool) {
+	uc.calculationReferences = enabled
+}
+
+// SetSupportedOperations задает типы операций (addition, subtraction, ...), которые умеют выполнять
+// агенты. Выражение, разбор которого дает операцию другого типа, отклоняется с ErrUnsupportedOp
+// до создания записи вычисления, а не падает у агента. Пустой список отключает проверку.
+func (uc *UseCaseImpl) SetSupportedOperations(names []string) {
+	if len(names) == 0 {
+		uc.supportedOperations = nil
+		return
+	}
+
+	uc.supportedOperations = make(map[string]struct{}, len(names))
+	for _, name := range names {
+		uc.supportedOperations[strings.ToLower(strings.TrimSpace(name))] = struct{}{}
+	}
+}
+
+// SetPendingOperationsBudget ограничивает количество операций, которые пользователь может
+// держать в очереди: выражение отклоняется с ErrPendingOpsBudget, если его операции вместе
+// с уже ожидающими и выполняемыми операциями пользователя превысят budget. Так один пользователь
+// не может занять всю очередь. Неположительное значение отключает проверку.
+func (uc *UseCaseImpl) SetPendingOperationsBudget(budget int) {
+	uc.pendingOperationsBudget = max(budget, 0)
+}
+
+// SetListResultCap ограничивает количество вычислений, которое возвращает один запрос списка,
+// даже если клиент запросил больше: так выгрузка истории требует много запросов.
+// Срабатывание ограничения записывается в журнал. Неположительное значение отключает ограничение.
+func (uc *UseCaseImpl) SetListResultCap(limit int) {
+	uc.listResultCap = max(limit, 0)
+}
+
+// SetResultRounding задает количество знаков после запятой и способ округления результата
+// вычисления. Точность выбирается по последней операции выражения. Результаты промежуточных
+// операций хранятся без округления, чтобы ошибка округления не накапливалась.
+// Неизвестный способ округления заменяется на RoundingHalfEven.
+func (uc *UseCaseImpl) SetResultRounding(rounding orchestrator.Rounding) {
+	if !rounding.Mode.IsValid() {
+		rounding.Mode = orchestrator.RoundingHalfEven
+	}
+	uc.resultRounding = rounding
+}
+
+// SetDisplayRounding включает сохранение результата, округленного для отображения, рядом
+// с точным. Предназначена для десятичного режима без округления операций: Result остается
+// точным для ссылок и дальнейших вычислений, а клиенты показывают DisplayResult.
+// Отрицательная точность отключает сохранение.
+func (uc *UseCaseImpl) SetDisplayRounding(rounding orchestrator.Rounding) {
+	uc.displayRounding = rounding
 }
 
 // CalculateExpression вычисляет математическое выражение
-// Создает запись вычисления, разбирает выражение на операции и запускает их выполнение
-func (uc *UseCaseImpl) CalculateExpression(ctx context.Context, userID uuid.UUID, expression string) (*orchestrator.Calculation, error) {
+// Создает запись вычисления, разбирает выражение на операции и запускает их выполнение.
+// Пустой source считается веб-каналом.
+func (uc *UseCaseImpl) CalculateExpression(ctx context.Context, userID uuid.UUID, expression string, source orchestrator.CalculationSource) (*orchestrator.Calculation, error) {
+	ctx, span := tracing.Start(ctx, spanCalculateExpression, attribute.String(tracing.AttrUserID, userID.String()))
+	defer span.End()
+
+	calc, err := uc.calculateExpression(ctx, userID, expression, source)
+	if calc != nil {
+		span.SetAttributes(attribute.String(tracing.AttrCalculationID, calc.ID.String()))
+	}
+	tracing.RecordError(span, err)
+	return calc, err
+}
+
+func (uc *UseCaseImpl) calculateExpression(ctx context.Context, userID uuid.UUID, expression string, source orchestrator.CalculationSource) (*orchestrator.Calculation, error) {
 	log := logger.ContextLogger(ctx, nil).With(
 		zap.String("op", "CalculationUseCase.CalculateExpression"),
 		zap.String("user_id", userID.String()),
 		zap.String("expression", expression),
+		zap.String("source", string(source)),
 	)
 
 	// Проверка корректности входных данных
@@ -70,41 +311,154 @@ func (uc *UseCaseImpl) CalculateExpression(ctx context.Context, userID uuid.UUID
 		return nil, fmt.Errorf("%w: expression cannot be empty", domainerrors.ErrInvalidExpression)
 	}
 
+	if source == "" {
+		source = orchestrator.CalculationSourceWeb
+	}
+
+	if !source.IsValid() {
+		return nil, fmt.Errorf("%w: %s", domainerrors.ErrInvalidSource, source)
+	}
+
+	if priority := orchestrator.PriorityFromContext(ctx); !orchestrator.ValidPriority(priority) {
+		return nil, fmt.Errorf("%w: %d, allowed 0..%d", domainerrors.ErrInvalidPriority, priority, orchestrator.MaxPriority)
+	}
+
+	if uc.dbHealth != nil {
+		if err := uc.dbHealth.Err(); err != nil {
+			log.Warn("Rejecting calculation while database is unavailable", zap.Error(err))
+			return nil, fmt.Errorf("%w: %v", domainerrors.ErrDatabaseUnavailable, err)
+		}
+	}
+
+	// Повтор отправки с тем же ключом идемпотентности возвращает уже созданное вычисление
+	idempotencyKey := orchestrator.IdempotencyKeyFromContext(ctx)
+	if len(idempotencyKey) > orchestrator.MaxIdempotencyKeyLength {
+		return nil, fmt.Errorf("%w: longer than %d bytes", domainerrors.ErrInvalidIdempotencyKey, orchestrator.MaxIdempotencyKeyLength)
+	}
+	if idempotencyKey != "" {
+		existing, err := uc.calculationRepo.FindByIdempotencyKey(ctx, userID, idempotencyKey)
+		if err != nil {
+			log.Error("Failed to find calculation by idempotency key", zap.Error(err))
+			return nil, fmt.Errorf("%w: %v", domainerrors.ErrInternalError, err)
+		}
+		if existing != nil {
+			log.Info("Calculation replayed by idempotency key", zap.String("calculation_id", existing.ID.String()))
+			return existing, nil
+		}
+	}
+
+	// Подстановка результатов вычислений, на которые ссылается выражение
+	resolved := expression
+	if uc.calculationReferences {
+		var err error
+		if resolved, err = uc.resolveReferences(ctx, userID, expression); err != nil {
+			log.Warn("Failed to resolve calculation references", zap.Error(err))
+			return nil, err
+		}
+	}
+
 	// Валидация выражения
-	validationCtx, cancel := context.WithTimeout(ctx, validationTimeout)
+	validationCtx, cancel := context.WithTimeout(ctx, min(validationTimeout, uc.parsingTimeout))
 	defer cancel()
 
-	if err := uc.parser.Validate(validationCtx, expression); err != nil {
+	if err := uc.parser.Validate(validationCtx, resolved); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			log.Warn("Expression validation timed out")
+			return nil, fmt.Errorf("%w: %v", domainerrors.ErrParseTimeout, err)
+		}
 		return nil, fmt.Errorf("%w: %v", domainerrors.ErrInvalidExpression, err)
 	}
 
+	// Выражение разбирается не более одного раза и только когда операции нужны:
+	// проверкам перед сохранением вычисления или самому сохранению
+	var (
+		parsedOps []*orchestrator.Operation
+		parsedErr error
+		parsed    bool
+	)
+	parse := func() ([]*orchestrator.Operation, error) {
+		if !parsed {
+			parseCtx, cancel := context.WithTimeout(ctx, uc.parsingTimeout)
+			defer cancel()
+
+			parsedOps, parsedErr = uc.parser.Parse(parseCtx, resolved)
+			parsed = true
+		}
+		return parsedOps, parsedErr
+	}
+
+	if uc.supportedOperations != nil {
+		if err := uc.checkSupportedOperations(parse()); err != nil {
+			log.Warn("Expression contains unsupported operations", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	zapLogger := logger.GetZapLogger(log)
+	if zapLogger == nil {
+		zapLogger = zap.L()
+	}
+
+	// Результат выражения уже известен: вычисление сохраняется завершенным
+	if result, found := uc.cachedResult(ctx, zapLogger, resolved); found {
+		createCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+		defer cancel()
+
+		cachedCalc := newCachedCalculation(userID, expression, source, result)
+		cachedCalc.ClientVersion = orchestrator.ClientVersionFromContext(ctx)
+		cachedCalc.DisplayResult = uc.displayResult(result)
+		cachedCalc.IdempotencyKey = idempotencyKey
+
+		savedCalc, replayed, err := uc.createCalculation(createCtx, cachedCalc)
+		if err != nil {
+			log.Error("Failed to create calculation", zap.Error(err))
+			return nil, fmt.Errorf("%w: %v", domainerrors.ErrInternalError, err)
+		}
+		if replayed {
+			return savedCalc, nil
+		}
+		log.Debug("Calculation result served from cache", zap.String("calculation_id", savedCalc.ID.String()))
+		return savedCalc, nil
+	}
+
+	if uc.pendingOperationsBudget > 0 {
+		operations, parseErr := parse()
+		if err := uc.checkPendingOperationsBudget(ctx, zapLogger, userID, operations, parseErr); err != nil {
+			log.Warn("Pending operations budget exceeded", zap.Error(err))
+			return nil, err
+		}
+	}
+
 	// Создание записи вычисления
 	calc := &orchestrator.Calculation{
 		ID:         uuid.New(),
 		UserID:     userID,
 		Expression: expression,
 		Status:     orchestrator.CalculationStatusPending,
+		Source:     source,
+		// Версию клиента передает шлюз, если ее сохранение включено
+		ClientVersion:  orchestrator.ClientVersionFromContext(ctx),
+		IdempotencyKey: idempotencyKey,
 	}
 
 	createCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 
-	savedCalc, err := uc.calculationRepo.Create(createCtx, calc)
+	savedCalc, replayed, err := uc.createCalculation(createCtx, calc)
 	if err != nil {
 		log.Error("Failed to create calculation", zap.Error(err))
 		return nil, fmt.Errorf("%w: %v", domainerrors.ErrInternalError, err)
 	}
+	if replayed {
+		return savedCalc, nil
+	}
 
-	// Разбор выражения на операции
-	parseCtx, cancel := context.WithTimeout(ctx, parsingTimeout)
+	// Сохранение операций, полученных при разборе
+	saveCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 
-	zapLogger := logger.GetZapLogger(log)
-	if zapLogger == nil {
-		zapLogger = zap.L()
-	}
-
-	_, err = uc.parseExpression(parseCtx, zapLogger, savedCalc.ID, expression)
+	operations, parseErr := parse()
+	operations, err = uc.saveOperations(saveCtx, zapLogger, savedCalc.ID, operations, parseErr)
 	if err != nil {
 		// Возвращаем результат с ошибкой, если она есть
 		updatedCalc, findErr := uc.calculationRepo.FindByID(ctx, savedCalc.ID)
@@ -122,23 +476,127 @@ func (uc *UseCaseImpl) CalculateExpression(ctx context.Context, userID uuid.UUID
 		log.Error("Failed to update calculation status", zap.Error(err))
 	}
 
+	estimate := orchestrator.EstimateDuration(operations, uc.operationTimes)
+	likelyAgents := uc.likelyAgents(zapLogger, operations)
+
 	// Получаем обновленный расчет
 	result, err := uc.calculationRepo.FindByID(ctx, savedCalc.ID)
-	if err != nil {
+	if err != nil || result == nil {
+		savedCalc.EstimatedDuration = estimate
+		savedCalc.LikelyAgents = likelyAgents
 		return savedCalc, nil
 	}
 
+	result.EstimatedDuration = estimate
+	result.LikelyAgents = likelyAgents
 	return result, nil
 }
 
-// parseExpression разбирает выражение на операции и сохраняет их в БД
-func (uc *UseCaseImpl) parseExpression(ctx context.Context, log *zap.Logger, calculationID uuid.UUID, expression string) ([]*orchestrator.Operation, error) {
+// createCalculation сохраняет вычисление. Если параллельный запрос с тем же ключом
+// идемпотентности успел сохранить свое вычисление, возвращает его и replayed = true.
+func (uc *UseCaseImpl) createCalculation(ctx context.Context, calc *orchestrator.Calculation) (*orchestrator.Calculation, bool, error) {
+	saved, err := uc.calculationRepo.Create(ctx, calc)
+	if err == nil {
+		return saved, false, nil
+	}
+	if !errors.Is(err, domainerrors.ErrIdempotencyKeyConflict) {
+		return nil, false, err
+	}
+
+	existing, findErr := uc.calculationRepo.FindByIdempotencyKey(ctx, calc.UserID, calc.IdempotencyKey)
+	if findErr != nil {
+		return nil, false, findErr
+	}
+	if existing == nil {
+		return nil, false, err
+	}
+	return existing, true, nil
+}
+
+// checkSupportedOperations проверяет, что все операции выражения входят в настроенный набор.
+// Ошибка разбора здесь не возвращается: ее записывает в вычисление saveOperations.
+func (uc *UseCaseImpl) checkSupportedOperations(operations []*orchestrator.Operation, parseErr error) error {
+	if uc.supportedOperations == nil || parseErr != nil {
+		return nil
+	}
+
+	for _, op := range operations {
+		if op == nil {
+			continue
+		}
+		name := op.OperationType.Name()
+		if _, ok := uc.supportedOperations[name]; ok {
+			continue
+		}
+		if name == "" {
+			name = strconv.Itoa(int(op.OperationType))
+		}
+		return fmt.Errorf("%w: %w: %s", domainerrors.ErrInvalidExpression, domainerrors.ErrUnsupportedOp, name)
+	}
+	return nil
+}
+
+// checkPendingOperationsBudget проверяет, что операции выражения вместе с невыполненными
+// операциями пользователя укладываются в бюджет. Ошибка разбора здесь не возвращается, а
+// ошибка подсчета только записывается в журнал: проверка не должна мешать вычислениям.
+func (uc *UseCaseImpl) checkPendingOperationsBudget(ctx context.Context, log *zap.Logger, userID uuid.UUID, operations []*orchestrator.Operation, parseErr error) error {
+	if uc.pendingOperationsBudget <= 0 || parseErr != nil || len(operations) == 0 {
+		return nil
+	}
+
+	countCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	pending, err := uc.operationRepo.CountPendingByUserID(countCtx, userID)
+	if err != nil {
+		log.Warn("Failed to count pending operations", zap.Error(err))
+		return nil
+	}
+
+	if pending+len(operations) > uc.pendingOperationsBudget {
+		return fmt.Errorf("%w: %d pending and %d new operations, budget is %d",
+			domainerrors.ErrPendingOpsBudget, pending, len(operations), uc.pendingOperationsBudget)
+	}
+	return nil
+}
+
+// likelyAgents оценивает по снимку пула, какие агенты возьмут операции нового вычисления.
+// Назначение не выполняется. Если оценка выключена, пул не задан или свободных агентов нет,
+// возвращается nil.
+func (uc *UseCaseImpl) likelyAgents(log *zap.Logger, operations []*orchestrator.Operation) []string {
+	if !uc.agentEstimate || uc.agentPool == nil || len(operations) == 0 {
+		return nil
+	}
+
+	agents, err := uc.agentPool.ListAgents()
+	if err != nil {
+		log.Warn("Failed to list agents for estimate", zap.Error(err))
+		return nil
+	}
+
+	return orchestrator.PlannedAgents(orchestrator.PlanDispatch(operations, agents, nil))
+}
+
+// saveOperations сохраняет в БД операции, полученные при разборе выражения.
+// Ошибка разбора parseErr записывается в вычисление.
+func (uc *UseCaseImpl) saveOperations(ctx context.Context, log *zap.Logger, calculationID uuid.UUID, operations []*orchestrator.Operation, parseErr error) ([]*orchestrator.Operation, error) {
 	if log == nil {
 		log = zap.L()
 	}
 
-	// Парсинг выражения в операции
-	operations, err := uc.parser.Parse(ctx, expression)
+	err := parseErr
+	if errors.Is(err, context.DeadlineExceeded) {
+		// Разбор не уложился в отведенное время, поэтому статус обновляется в отдельном контексте
+		statusCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), statusTimeout)
+		defer cancel()
+
+		log.Warn("Expression parsing timed out", zap.String("calculation_id", calculationID.String()))
+		updateErr := uc.calculationRepo.UpdateStatus(statusCtx, calculationID, orchestrator.CalculationStatusError, "", parseTimeoutMsg)
+		if updateErr != nil {
+			log.Error("Failed to update calculation status", zap.Error(updateErr))
+		}
+		return nil, fmt.Errorf("%w: %v", domainerrors.ErrParseTimeout, err)
+	}
 	if err != nil {
 		updateErr := uc.calculationRepo.UpdateStatus(ctx, calculationID, orchestrator.CalculationStatusError, "", err.Error())
 		if updateErr != nil {
@@ -161,11 +619,29 @@ func (uc *UseCaseImpl) parseExpression(ctx context.Context, log *zap.Logger, cal
 		return nil, domainerrors.ErrTooManyOps
 	}
 
+	// Повторяющиеся ID нарушили бы первичный ключ при сохранении
+	if err := uc.ensureUniqueOperationIDs(ctx, log, calculationID, operations); err != nil {
+		return nil, err
+	}
+
 	// Привязка операций к расчету
 	uc.parser.SetCalculationID(operations, calculationID)
 
+	// Приоритет вычисления, заданный при отправке, получают все его операции
+	priority := orchestrator.PriorityFromContext(ctx)
+	for _, op := range operations {
+		if op != nil {
+			op.Priority = priority
+		}
+	}
+
 	// Сохранение операций
-	if err = uc.operationRepo.CreateBatch(ctx, operations); err != nil {
+	if uc.partialBatchInsert {
+		operations, err = uc.createOperationsPartial(ctx, log, calculationID, operations)
+	} else {
+		err = uc.operationRepo.CreateBatch(ctx, operations)
+	}
+	if err != nil {
 		errMsg := "Failed to create operations"
 		updateErr := uc.calculationRepo.UpdateStatus(ctx, calculationID, orchestrator.CalculationStatusError, "", errMsg)
 		if updateErr != nil {
@@ -177,6 +653,290 @@ func (uc *UseCaseImpl) parseExpression(ctx context.Context, log *zap.Logger, cal
 	return operations, nil
 }
 
+// ensureUniqueOperationIDs проверяет, что разбор выдал операциям разные ID. Повторы либо
+// получают новые ID, либо, если это запрещено настройкой, переводят вычисление в ERROR.
+func (uc *UseCaseImpl) ensureUniqueOperationIDs(ctx context.Context, log *zap.Logger, calculationID uuid.UUID, operations []*orchestrator.Operation) error {
+	duplicates := orchestrator.DuplicateOperationIDs(operations)
+	if len(duplicates) == 0 {
+		return nil
+	}
+
+	if uc.rejectDuplicateOperationIDs {
+		log.Error("Parser returned duplicate operation IDs",
+			zap.String("calculation_id", calculationID.String()),
+			zap.Int("duplicates", len(duplicates)))
+		errMsg := "Failed to create operations: duplicate operation IDs"
+		if updateErr := uc.calculationRepo.UpdateStatus(ctx, calculationID, orchestrator.CalculationStatusError, "", errMsg); updateErr != nil {
+			log.Error("Failed to update calculation status", zap.Error(updateErr))
+		}
+		return fmt.Errorf("%w: %s", domainerrors.ErrDuplicateOperationID, duplicates[0])
+	}
+
+	reassigned := orchestrator.ReassignDuplicateIDs(operations, uuid.New)
+	log.Warn("Parser returned duplicate operation IDs, new IDs assigned",
+		zap.String("calculation_id", calculationID.String()),
+		zap.Int("duplicates", len(duplicates)),
+		zap.Int("reassigned", reassigned))
+	return nil
+}
+
+// createOperationsPartial сохраняет операции по отдельности. Если какая-либо операция не
+// сохранилась, сохраненные отменяются, ведь итоговая операция через ссылки зависит от всех
+// остальных, и возвращается ошибка, по которой вычисление переводится в ERROR.
+func (uc *UseCaseImpl) createOperationsPartial(ctx context.Context, log *zap.Logger, calculationID uuid.UUID, operations []*orchestrator.Operation) ([]*orchestrator.Operation, error) {
+	if len(operations) == 0 {
+		return operations, nil
+	}
+
+	results, err := uc.operationRepo.CreateBatchPartial(ctx, operations)
+	if err != nil {
+		return nil, err
+	}
+
+	failed := orchestrator.FailedInserts(results)
+	if len(failed) == 0 {
+		return operations, nil
+	}
+
+	skip := make(map[int]struct{}, len(failed))
+	for _, result := range failed {
+		skip[result.Index] = struct{}{}
+		log.Warn("Operation was not saved",
+			zap.String("calculation_id", calculationID.String()),
+			zap.Int("index", result.Index),
+			zap.String("operation_id", result.OperationID.String()),
+			zap.Error(result.Err))
+	}
+
+	cancelled := make([]orchestrator.OperationStatusUpdate, 0, len(operations)-len(failed))
+	for i, operation := range operations {
+		if _, ok := skip[i]; !ok {
+			cancelled = append(cancelled, orchestrator.OperationStatusUpdate{
+				ID:           operation.ID,
+				Status:       orchestrator.OperationStatusCancelled,
+				ErrorMessage: unsavedSiblingMsg,
+			})
+		}
+	}
+	if len(cancelled) > 0 {
+		if err := uc.operationRepo.UpdateStatusBatch(ctx, cancelled); err != nil {
+			log.Error("Failed to cancel saved operations", zap.Error(err))
+		}
+	}
+
+	return nil, fmt.Errorf("%d of %d operations were not saved: %w", len(failed), len(operations), failed[0].Err)
+}
+
+// CompareExpressions вычисляет оба выражения синхронно, не создавая вычислений и операций,
+// и сравнивает результаты. Выражения ограничены тем же временем разбора, что и обычные вычисления.
+func (uc *UseCaseImpl) CompareExpressions(ctx context.Context, expressionA, expressionB string, tolerance float64) (*orchestrator.ExpressionComparison, error) {
+	log := logger.ContextLogger(ctx, nil).With(
+		zap.String("op", "CalculationUseCase.CompareExpressions"),
+		zap.String("expression_a", expressionA),
+		zap.String("expression_b", expressionB),
+	)
+
+	if strings.TrimSpace(expressionA) == "" || strings.TrimSpace(expressionB) == "" {
+		return nil, fmt.Errorf("%w: expression cannot be empty", domainerrors.ErrInvalidExpression)
+	}
+
+	if tolerance < 0 || math.IsNaN(tolerance) || math.IsInf(tolerance, 0) {
+		return nil, domainerrors.ErrInvalidTolerance
+	}
+
+	resultA, err := uc.evaluate(ctx, expressionA)
+	if err != nil {
+		log.Debug("Failed to evaluate first expression", zap.Error(err))
+		return nil, err
+	}
+
+	resultB, err := uc.evaluate(ctx, expressionB)
+	if err != nil {
+		log.Debug("Failed to evaluate second expression", zap.Error(err))
+		return nil, err
+	}
+
+	comparison := orchestrator.CompareResults(expressionA, expressionB, resultA, resultB, tolerance)
+	log.Debug("Expressions compared", zap.Bool("equal", comparison.Equal))
+
+	return comparison, nil
+}
+
+// PreviewExpression возвращает выражение с явными скобками вокруг каждой вложенной операции.
+// Разбор ограничен тем же временем, что и для обычных вычислений.
+func (uc *UseCaseImpl) PreviewExpression(ctx context.Context, expression string, options orchestrator.PreviewOptions) (*orchestrator.ExpressionPreview, error) {
+	if strings.TrimSpace(expression) == "" {
+		return nil, fmt.Errorf("%w: expression cannot be empty", domainerrors.ErrInvalidExpression)
+	}
+
+	previewCtx, cancel := context.WithTimeout(ctx, uc.parsingTimeout)
+	defer cancel()
+
+	grouped, err := uc.parser.Parenthesize(previewCtx, expression, options)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w: %v", domainerrors.ErrParseTimeout, err)
+		}
+		return nil, fmt.Errorf("%w: %v", domainerrors.ErrInvalidExpression, err)
+	}
+
+	return &orchestrator.ExpressionPreview{
+		Expression: expression,
+		Grouped:    grouped,
+	}, nil
+}
+
+// ValidateExpression проверяет синтаксис выражения, не создавая вычисления. Некорректное
+// выражение - не ошибка: результат содержит Valid = false, позицию и описание первой ошибки.
+// Ошибка возвращается, только если проверка не уложилась во время разбора.
+func (uc *UseCaseImpl) ValidateExpression(ctx context.Context, expression string) (*orchestrator.ExpressionValidation, error) {
+	validationCtx, cancel := context.WithTimeout(ctx, min(validationTimeout, uc.parsingTimeout))
+	defer cancel()
+
+	result := &orchestrator.ExpressionValidation{Expression: expression}
+
+	err := uc.parser.Validate(validationCtx, expression)
+	if err == nil {
+		result.Valid = true
+		return result, nil
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return nil, fmt.Errorf("%w: %v", domainerrors.ErrParseTimeout, err)
+	}
+
+	offset := 0
+	result.ErrorMessage = err.Error()
+	var syntaxErr *orchestrator.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		offset = syntaxErr.Offset
+		result.ErrorMessage = syntaxErr.Message
+	}
+	result.ErrorOffset = &offset
+	return result, nil
+}
+
+// GetPoolStats возвращает метрики пула агентов.
+func (uc *UseCaseImpl) GetPoolStats(ctx context.Context) (*agent.PoolStats, error) {
+	if uc.agentPool == nil {
+		return nil, domainerrors.ErrNilPool
+	}
+
+	stats, err := uc.agentPool.GetPoolStats()
+	if err != nil {
+		logger.ContextLogger(ctx, nil).Error("Failed to get agent pool stats",
+			zap.String("op", "CalculationUseCase.GetPoolStats"), zap.Error(err))
+		return nil, fmt.Errorf("failed to get agent pool stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// GetTimingProfile возвращает настроенное и фактически применяемое агентами время выполнения операций.
+func (uc *UseCaseImpl) GetTimingProfile(ctx context.Context) (*agent.TimingProfile, error) {
+	if uc.agentPool == nil {
+		return nil, domainerrors.ErrNilPool
+	}
+
+	profile, err := uc.agentPool.GetTimingProfile()
+	if err != nil {
+		logger.ContextLogger(ctx, nil).Error("Failed to get agent timing profile",
+			zap.String("op", "CalculationUseCase.GetTimingProfile"), zap.Error(err))
+		return nil, fmt.Errorf("failed to get agent timing profile: %w", err)
+	}
+
+	return profile, nil
+}
+
+// SetAgentCapacity меняет емкость работающего агента. Новая емкость действует для следующих
+// операций, назначаемых агенту.
+func (uc *UseCaseImpl) SetAgentCapacity(ctx context.Context, agentID string, capacity int) (*agent.AgentStats, error) {
+	const op = "CalculationUseCase.SetAgentCapacity"
+
+	if uc.agentPool == nil {
+		return nil, domainerrors.ErrNilPool
+	}
+
+	log := logger.ContextLogger(ctx, nil)
+
+	stats, err := uc.agentPool.SetAgentCapacity(agentID, capacity)
+	if err != nil {
+		log.Warn("Failed to set agent capacity",
+			zap.String("op", op),
+			zap.String("agent_id", agentID),
+			zap.Int("capacity", capacity),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to set agent capacity: %w", err)
+	}
+
+	log.Info("Agent capacity changed",
+		zap.String("agent_id", agentID),
+		zap.Int("capacity", stats.MaxCapacity),
+		zap.Int("current_load", stats.CurrentLoad))
+
+	return stats, nil
+}
+
+// GetSystemStats собирает сводную статистику сервиса: количество вычислений по статусам,
+// ожидающие операции, агентов и состояние пула соединений. Если пул агентов не задан,
+// счетчики агентов остаются нулевыми.
+func (uc *UseCaseImpl) GetSystemStats(ctx context.Context) (*system.OrchestratorStats, error) {
+	const op = "CalculationUseCase.GetSystemStats"
+	log := logger.ContextLogger(ctx, nil).With(zap.String("op", op))
+
+	byStatus, err := uc.calculationRepo.CountByStatus(ctx)
+	if err != nil {
+		log.Error("Failed to count calculations", zap.Error(err))
+		return nil, fmt.Errorf("failed to count calculations: %w", err)
+	}
+
+	pending, err := uc.operationRepo.CountByStatus(ctx, orchestrator.OperationStatusPending)
+	if err != nil {
+		log.Error("Failed to count pending operations", zap.Error(err))
+		return nil, fmt.Errorf("failed to count pending operations: %w", err)
+	}
+
+	stats := &system.OrchestratorStats{
+		CalculationsByStatus: make(map[string]int, len(byStatus)),
+		PendingOperations:    pending,
+	}
+	for status, count := range byStatus {
+		stats.CalculationsByStatus[string(status)] = count
+		stats.TotalCalculations += count
+	}
+
+	if uc.agentPool != nil {
+		poolStats, err := uc.agentPool.GetPoolStats()
+		if err != nil {
+			log.Error("Failed to get agent pool stats", zap.Error(err))
+			return nil, fmt.Errorf("failed to get agent pool stats: %w", err)
+		}
+		stats.TotalAgents = poolStats.TotalAgents
+		stats.ActiveAgents = poolStats.OnlineAgents + poolStats.BusyAgents
+	}
+
+	if uc.dbPoolStats != nil {
+		stats.DBPool = uc.dbPoolStats.PoolStats()
+	}
+
+	return stats, nil
+}
+
+// evaluate вычисляет выражение с ограничением времени разбора.
+func (uc *UseCaseImpl) evaluate(ctx context.Context, expression string) (float64, error) {
+	evalCtx, cancel := context.WithTimeout(ctx, uc.parsingTimeout)
+	defer cancel()
+
+	result, err := uc.parser.Evaluate(evalCtx, expression)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return 0, fmt.Errorf("%w: %v", domainerrors.ErrParseTimeout, err)
+		}
+		return 0, fmt.Errorf("%w: %s: %v", domainerrors.ErrInvalidExpression, expression, err)
+	}
+
+	return result, nil
+}
+
 // GetCalculation получает информацию о вычислении с указанным ID
 // Проверяет права доступа и обогащает результат данными об операциях
 func (uc *UseCaseImpl) GetCalculation(ctx context.Context, calculationID uuid.UUID, userID uuid.UUID) (*orchestrator.Calculation, error) {
@@ -201,6 +961,10 @@ func (uc *UseCaseImpl) GetCalculation(ctx context.Context, calculationID uuid.UU
 		return nil, domainerrors.ErrUnauthorizedAccess
 	}
 
+	if uc.isStale(calc) {
+		calc = uc.recomputeStale(ctx, log, calc)
+	}
+
 	// Обогащение данными об операциях
 	zapLogger := logger.GetZapLogger(log)
 	calc, err = uc.enrichCalculationWithOperations(ctx, zapLogger, calc)
@@ -211,8 +975,122 @@ func (uc *UseCaseImpl) GetCalculation(ctx context.Context, calculationID uuid.UU
 	return calc, nil
 }
 
+// DiffCalculations сравнивает два вычисления пользователя: совпадают ли выражения и результаты
+// и на сколько второй результат отличается от первого. Оба вычисления должны принадлежать пользователю.
+func (uc *UseCaseImpl) DiffCalculations(ctx context.Context, calculationIDA, calculationIDB uuid.UUID, userID uuid.UUID) (*orchestrator.CalculationDiff, error) {
+	log := logger.ContextLogger(ctx, nil).With(
+		zap.String("op", "CalculationUseCase.DiffCalculations"),
+		zap.String("calculation_id_a", calculationIDA.String()),
+		zap.String("calculation_id_b", calculationIDB.String()),
+		zap.String("user_id", userID.String()),
+	)
+
+	calcA, err := uc.findOwnedCalculation(ctx, calculationIDA, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	calcB, err := uc.findOwnedCalculation(ctx, calculationIDB, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := orchestrator.DiffCalculations(calcA, calcB)
+	log.Debug("Calculations compared",
+		zap.Bool("same_expression", diff.SameExpression),
+		zap.Bool("same_result", diff.SameResult))
+
+	return diff, nil
+}
+
+// findOwnedCalculation находит вычисление и проверяет, что оно принадлежит пользователю.
+func (uc *UseCaseImpl) findOwnedCalculation(ctx context.Context, calculationID uuid.UUID, userID uuid.UUID) (*orchestrator.Calculation, error) {
+	calc, err := uc.calculationRepo.FindByID(ctx, calculationID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domainerrors.ErrInternalError, err)
+	}
+
+	if calc == nil {
+		return nil, domainerrors.ErrCalculationNotFound
+	}
+
+	if calc.UserID != userID {
+		return nil, domainerrors.ErrUnauthorizedAccess
+	}
+
+	return calc, nil
+}
+
+// GetOperation возвращает операцию по ID для отладки. Доступ проверяется по владельцу
+// вычисления, которому принадлежит операция; операция удаленного вычисления не найдется.
+func (uc *UseCaseImpl) GetOperation(ctx context.Context, operationID uuid.UUID, userID uuid.UUID) (*orchestrator.Operation, error) {
+	op, err := uc.operationRepo.FindByID(ctx, operationID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domainerrors.ErrInternalError, err)
+	}
+
+	if op == nil {
+		return nil, domainerrors.ErrOperationNotFound
+	}
+
+	if _, err := uc.findOwnedCalculation(ctx, op.CalculationID, userID); err != nil {
+		if errors.Is(err, domainerrors.ErrCalculationNotFound) {
+			return nil, domainerrors.ErrOperationNotFound
+		}
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// AdminGetCalculation возвращает вычисление любого пользователя, включая удаленные,
+// вместе с версией клиента. Предназначена для администраторов и поддержки.
+func (uc *UseCaseImpl) AdminGetCalculation(ctx context.Context, calculationID uuid.UUID) (*orchestrator.Calculation, error) {
+	calc, err := uc.calculationRepo.FindByID(ctx, calculationID, orchestrator.IncludeDeleted())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domainerrors.ErrInternalError, err)
+	}
+
+	if calc == nil {
+		return nil, domainerrors.ErrCalculationNotFound
+	}
+
+	return calc, nil
+}
+
+// isStale сообщает, нужно ли пересчитать статус вычисления перед возвратом клиенту.
+func (uc *UseCaseImpl) isStale(calc *orchestrator.Calculation) bool {
+	return uc.staleRecomputeAfter > 0 &&
+		calc.Status == orchestrator.CalculationStatusInProgress &&
+		time.Since(calc.UpdatedAt) > uc.staleRecomputeAfter
+}
+
+// recomputeStale пересчитывает статус зависшего вычисления и перечитывает его.
+// При ошибке возвращается исходное вычисление: пересчет не должен ломать чтение.
+func (uc *UseCaseImpl) recomputeStale(ctx context.Context, log logger.Logger, calc *orchestrator.Calculation) *orchestrator.Calculation {
+	log.Debug("Recomputing stale calculation status", zap.Time("updated_at", calc.UpdatedAt))
+
+	if err := uc.UpdateCalculationStatus(ctx, calc.ID); err != nil {
+		log.Warn("Failed to recompute stale calculation status", zap.Error(err))
+		return calc
+	}
+
+	refreshed, err := uc.calculationRepo.FindByID(ctx, calc.ID)
+	if err != nil || refreshed == nil {
+		log.Warn("Failed to reload recomputed calculation", zap.Error(err))
+		return calc
+	}
+
+	return refreshed
+}
+
 // enrichCalculationWithOperations добавляет данные об операциях в объект вычисления
 func (uc *UseCaseImpl) enrichCalculationWithOperations(ctx context.Context, log *zap.Logger, calc *orchestrator.Calculation) (*orchestrator.Calculation, error) {
+	// Операции сжатого вычисления прочитаны из архива вместе с ним
+	if calc.CompactedAt != nil {
+		return calc, nil
+	}
+
 	operations, err := uc.operationRepo.FindByCalculationID(ctx, calc.ID)
 	if err != nil {
 		if log != nil {
@@ -231,24 +1109,231 @@ func (uc *UseCaseImpl) enrichCalculationWithOperations(ctx context.Context, log
 	return calc, nil
 }
 
-// ListCalculations возвращает список всех вычислений пользователя
-func (uc *UseCaseImpl) ListCalculations(ctx context.Context, userID uuid.UUID) ([]*orchestrator.Calculation, error) {
+// ListCalculations возвращает страницу вычислений пользователя.
+// Нулевой лимит заменяется значением по умолчанию, лимит выше maxListLimit считается ошибкой.
+// Лимит выше настроенного SetListResultCap уменьшается до него.
+func (uc *UseCaseImpl) ListCalculations(ctx context.Context, userID uuid.UUID, filter orchestrator.CalculationFilter) (*orchestrator.CalculationPage, error) {
 	log := logger.ContextLogger(ctx, nil).With(
 		zap.String("op", "CalculationUseCase.ListCalculations"),
 		zap.String("user_id", userID.String()),
+		zap.Int("limit", filter.Limit),
+		zap.Int("offset", filter.Offset),
+		zap.String("status", string(filter.Status)),
 	)
 
 	if userID == uuid.Nil {
 		return nil, domainerrors.ErrInvalidUserID
 	}
 
-	calculations, err := uc.calculationRepo.FindByUserID(ctx, userID)
+	if filter.Limit == 0 {
+		filter.Limit = defaultListLimit
+	}
+
+	if filter.Limit < 0 || filter.Limit > maxListLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", domainerrors.ErrInvalidPagination, maxListLimit)
+	}
+
+	if filter.Offset < 0 {
+		return nil, fmt.Errorf("%w: offset cannot be negative", domainerrors.ErrInvalidPagination)
+	}
+
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return nil, fmt.Errorf("%w: %s", domainerrors.ErrInvalidStatusFilter, filter.Status)
+	}
+
+	if uc.listResultCap > 0 && filter.Limit > uc.listResultCap {
+		log.Warn("List limit exceeds result cap", zap.Int("cap", uc.listResultCap))
+		filter.Limit = uc.listResultCap
+	}
+
+	calculations, total, err := uc.calculationRepo.FindByUserID(ctx, userID, filter)
 	if err != nil {
 		log.Error("Failed to fetch user calculations", zap.Error(err))
 		return nil, fmt.Errorf("%w: %v", domainerrors.ErrInternalError, err)
 	}
 
-	return calculations, nil
+	// Хранилище не должно вернуть больше лимита, но ограничение проверяется и по результату
+	if uc.listResultCap > 0 && len(calculations) > uc.listResultCap {
+		log.Warn("List result truncated to cap",
+			zap.Int("cap", uc.listResultCap),
+			zap.Int("returned", len(calculations)))
+		calculations = calculations[:uc.listResultCap]
+	}
+
+	return &orchestrator.CalculationPage{
+		Calculations: calculations,
+		Total:        total,
+		Limit:        filter.Limit,
+		Offset:       filter.Offset,
+	}, nil
+}
+
+// GetResultStats возвращает количество, сумму, среднее, минимум и максимум числовых
+// результатов завершенных вычислений пользователя.
+func (uc *UseCaseImpl) GetResultStats(ctx context.Context, userID uuid.UUID) (*orchestrator.ResultStats, error) {
+	log := logger.ContextLogger(ctx, nil).With(
+		zap.String("op", "CalculationUseCase.GetResultStats"),
+		zap.String("user_id", userID.String()),
+	)
+
+	if userID == uuid.Nil {
+		return nil, domainerrors.ErrInvalidUserID
+	}
+
+	stats, err := uc.calculationRepo.ResultStats(ctx, userID)
+	if err != nil {
+		log.Error("Failed to aggregate calculation results", zap.Error(err))
+		return nil, fmt.Errorf("%w: %v", domainerrors.ErrInternalError, err)
+	}
+
+	return stats, nil
+}
+
+// CancelCalculation отменяет вычисление и все его ожидающие или выполняющиеся операции.
+// Завершенные вычисления отменить нельзя. Статусы операций и вычисления меняются
+// в одной транзакции; подписчики и реестр отмен уведомляются после ее фиксации.
+func (uc *UseCaseImpl) CancelCalculation(ctx context.Context, calculationID uuid.UUID, userID uuid.UUID) error {
+	log := logger.ContextLogger(ctx, nil).With(
+		zap.String("op", "CalculationUseCase.CancelCalculation"),
+		zap.String("calculation_id", calculationID.String()),
+		zap.String("user_id", userID.String()),
+	)
+
+	if userID == uuid.Nil {
+		return domainerrors.ErrInvalidUserID
+	}
+
+	calc, err := uc.calculationRepo.FindByID(ctx, calculationID)
+	if err != nil {
+		return fmt.Errorf("%w: %v", domainerrors.ErrInternalError, err)
+	}
+
+	if calc == nil {
+		return domainerrors.ErrCalculationNotFound
+	}
+
+	if calc.UserID != userID {
+		return domainerrors.ErrUnauthorizedAccess
+	}
+
+	if calc.Status != orchestrator.CalculationStatusPending && calc.Status != orchestrator.CalculationStatusInProgress {
+		return fmt.Errorf("%w: %s", domainerrors.ErrCalcNotCancellable, calc.Status)
+	}
+
+	operations, err := uc.operationRepo.FindByCalculationID(ctx, calculationID)
+	if err != nil {
+		log.Error("Failed to fetch operations", zap.Error(err))
+		return fmt.Errorf("%w: %v", domainerrors.ErrInternalError, err)
+	}
+
+	var cancelledIDs []uuid.UUID
+	err = uc.withinTransaction(ctx, func(ctx context.Context) error {
+		cancelledIDs = make([]uuid.UUID, 0, len(operations))
+		for _, op := range operations {
+			if op == nil {
+				continue
+			}
+			if op.Status != orchestrator.OperationStatusPending && op.Status != orchestrator.OperationStatusInProgress {
+				continue
+			}
+
+			if err := uc.operationRepo.UpdateStatus(ctx, op.ID, orchestrator.OperationStatusCancelled, "", cancelledByUserMsg); err != nil {
+				return fmt.Errorf("cancel operation %s: %w", op.ID, err)
+			}
+			cancelledIDs = append(cancelledIDs, op.ID)
+		}
+
+		if err := uc.calculationRepo.UpdateStatus(ctx, calculationID, orchestrator.CalculationStatusCancelled, "", cancelledByUserMsg); err != nil {
+			return fmt.Errorf("update calculation status: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Error("Failed to cancel calculation", zap.Error(err))
+		return fmt.Errorf("%w: %v", domainerrors.ErrInternalError, err)
+	}
+
+	uc.publishStatus(calculationID, orchestrator.CalculationStatusCancelled, "", cancelledByUserMsg)
+
+	if uc.cancellation != nil && len(cancelledIDs) > 0 {
+		uc.cancellation.CancelOperations(cancelledIDs...)
+	}
+
+	log.Info("Calculation cancelled", zap.Int("cancelled_operations", len(cancelledIDs)))
+	return nil
+}
+
+// DeleteCalculation удаляет вычисление пользователя вместе с его операциями.
+// Операции удаляются раньше вычисления, оба удаления выполняются в одной транзакции.
+// Незавершенные операции отмечаются отмененными, чтобы агенты не начинали их выполнение.
+func (uc *UseCaseImpl) DeleteCalculation(ctx context.Context, calculationID uuid.UUID, userID uuid.UUID) error {
+	log := logger.ContextLogger(ctx, nil).With(
+		zap.String("op", "CalculationUseCase.DeleteCalculation"),
+		zap.String("calculation_id", calculationID.String()),
+		zap.String("user_id", userID.String()),
+	)
+
+	if userID == uuid.Nil {
+		return domainerrors.ErrInvalidUserID
+	}
+
+	calc, err := uc.calculationRepo.FindByID(ctx, calculationID)
+	if err != nil {
+		return fmt.Errorf("%w: %v", domainerrors.ErrInternalError, err)
+	}
+
+	if calc == nil {
+		return domainerrors.ErrCalculationNotFound
+	}
+
+	if calc.UserID != userID {
+		return domainerrors.ErrUnauthorizedAccess
+	}
+
+	operations, err := uc.operationRepo.FindByCalculationID(ctx, calculationID)
+	if err != nil {
+		log.Error("Failed to fetch operations", zap.Error(err))
+		return fmt.Errorf("%w: %v", domainerrors.ErrInternalError, err)
+	}
+
+	activeIDs := make([]uuid.UUID, 0, len(operations))
+	for _, op := range operations {
+		if op != nil && (op.Status == orchestrator.OperationStatusPending || op.Status == orchestrator.OperationStatusInProgress) {
+			activeIDs = append(activeIDs, op.ID)
+		}
+	}
+
+	var deletedOperations int
+	err = uc.withinTransaction(ctx, func(ctx context.Context) error {
+		deletedOperations, err = uc.operationRepo.DeleteByCalculationID(ctx, calculationID)
+		if err != nil {
+			return fmt.Errorf("delete operations: %w", err)
+		}
+
+		if err = uc.calculationRepo.Delete(ctx, calculationID); err != nil {
+			return fmt.Errorf("delete calculation: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Error("Failed to delete calculation", zap.Error(err))
+		return fmt.Errorf("%w: %v", domainerrors.ErrInternalError, err)
+	}
+
+	if uc.cancellation != nil && len(activeIDs) > 0 {
+		uc.cancellation.CancelOperations(activeIDs...)
+	}
+
+	log.Info("Calculation deleted", zap.Int("deleted_operations", deletedOperations))
+	return nil
+}
+
+// withinTransaction выполняет fn в транзакции, если задан менеджер транзакций.
+func (uc *UseCaseImpl) withinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if uc.txManager == nil {
+		return fn(ctx)
+	}
+	return uc.txManager.WithinTransaction(ctx, fn)
 }
 
 // ProcessPendingOperations заглушка для обработки ожидающих операций
@@ -284,11 +1369,23 @@ func (uc *UseCaseImpl) UpdateCalculationStatus(ctx context.Context, calculationI
 	}
 
 	// Получение вычисления с повторными попытками
-	_, err := uc.getCalculationWithRetry(timeoutCtx, calculationID, log)
+	calc, err := uc.getCalculationWithRetry(timeoutCtx, calculationID, log)
 	if err != nil {
 		return err
 	}
 
+	// Отмененное вычисление не пересчитывается по статусам операций
+	if calc.Status == orchestrator.CalculationStatusCancelled {
+		log.Debug("Calculation is cancelled, skipping status update")
+		return nil
+	}
+
+	// Строки операций сжатого вычисления удалены, его статус окончательный
+	if calc.CompactedAt != nil {
+		log.Debug("Calculation operations are compacted, skipping status update")
+		return nil
+	}
+
 	// Получение операций с повторными попытками
 	operations, err := uc.getOperationsWithRetry(timeoutCtx, calculationID, log)
 	if err != nil {
@@ -297,6 +1394,13 @@ func (uc *UseCaseImpl) UpdateCalculationStatus(ctx context.Context, calculationI
 
 	// Проверка наличия операций
 	if len(operations) == 0 {
+		if age := time.Since(calc.CreatedAt); age < uc.emptyOperationsGrace {
+			log.Debug("No operations yet, calculation is within grace window",
+				zap.Duration("age", age),
+				zap.Duration("grace", uc.emptyOperationsGrace))
+			return nil
+		}
+
 		updateErr := uc.calculationRepo.UpdateStatus(
 			timeoutCtx,
 			calculationID,
@@ -307,18 +1411,88 @@ func (uc *UseCaseImpl) UpdateCalculationStatus(ctx context.Context, calculationI
 		if updateErr != nil {
 			return fmt.Errorf("failed to update calculation status: %w", updateErr)
 		}
+		uc.publishStatus(calculationID, orchestrator.CalculationStatusError, "", "No operations found")
 		return nil
 	}
 
 	// Определение статуса вычисления на основе статусов операций
 	status, result, errorMsg := uc.determineCalculationStatus(operations)
+	if status == orchestrator.CalculationStatusCompleted {
+		result = uc.roundResult(operations, result)
+	}
 	log.Info("Determined calculation status",
 		zap.String("status", string(status)),
 		zap.String("result", result),
 		zap.String("error_message", errorMsg))
 
 	// Обновление статуса вычисления
-	return uc.updateCalculationStatusWithRetry(timeoutCtx, calculationID, status, result, errorMsg, log)
+	if err := uc.updateCalculationStatusWithRetry(timeoutCtx, calculationID, status, result, errorMsg, log); err != nil {
+		return err
+	}
+
+	// Подписчики уведомляются только о смене статуса: процессор пересчитывает статус
+	// после каждой операции, и большинство пересчетов его не меняют
+	if status != calc.Status {
+		var warnings []string
+		if status == orchestrator.CalculationStatusCompleted {
+			uc.saveDisplayResult(timeoutCtx, log, calculationID, result)
+			warnings = uc.saveWarnings(timeoutCtx, log, calculationID, operations)
+		}
+		uc.publishStatus(calculationID, status, result, errorMsg)
+		// Результат с предупреждениями не кэшируется: вычисление из кэша их бы потеряло
+		if status == orchestrator.CalculationStatusCompleted && len(warnings) == 0 {
+			uc.cacheResult(timeoutCtx, log, calculationID, calc.Expression, result)
+		}
+	}
+	return nil
+}
+
+// saveWarnings сохраняет предупреждения к результату завершенного вычисления и возвращает их.
+// Ошибка записи попадает в журнал: результат уже сохранен и остается верным.
+func (uc *UseCaseImpl) saveWarnings(ctx context.Context, log logger.Logger, calculationID uuid.UUID, operations []*orchestrator.Operation) []string {
+	warnings := orchestrator.ResultWarnings(operations)
+	if len(warnings) == 0 {
+		return nil
+	}
+
+	log.Info("Calculation completed with warnings", zap.Strings("warnings", warnings))
+	if err := uc.calculationRepo.UpdateWarnings(ctx, calculationID, warnings); err != nil {
+		log.Warn("Failed to save calculation warnings", zap.Error(err))
+	}
+	return warnings
+}
+
+// roundResult округляет результат завершенного вычисления с точностью его последней операции.
+func (uc *UseCaseImpl) roundResult(operations []*orchestrator.Operation, result string) string {
+	final := orchestrator.OperationTypeUnspecified
+	for _, op := range operations {
+		if op != nil {
+			final = op.OperationType
+		}
+	}
+	return uc.resultRounding.ForOperation(final).FormatText(result)
+}
+
+// displayResult возвращает результат, округленный для отображения, или пустую строку,
+// если сохранение округленного результата выключено.
+func (uc *UseCaseImpl) displayResult(result string) string {
+	if uc.displayRounding.Precision < 0 || result == "" {
+		return ""
+	}
+	return uc.displayRounding.FormatText(result)
+}
+
+// saveDisplayResult сохраняет округленный для отображения результат завершенного вычисления.
+// Ошибка записывается в журнал: точный результат уже сохранен, и клиент может показать его.
+func (uc *UseCaseImpl) saveDisplayResult(ctx context.Context, log logger.Logger, calculationID uuid.UUID, result string) {
+	display := uc.displayResult(result)
+	if display == "" {
+		return
+	}
+
+	if err := uc.calculationRepo.UpdateDisplayResult(ctx, calculationID, display); err != nil {
+		log.Warn("Failed to save calculation display result", zap.Error(err))
+	}
 }
 
 // getCalculationWithRetry получает вычисление с повторными попытками при ошибках
@@ -3,17 +3,35 @@ package calculation_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/alicebob/miniredis/v2"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/db/memory/cache"
+	rediscache "github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/db/redis"
+	parsersvc "github.com/flexer2006/y.lms-final-task-calc-go/internal/adapters/services/parser"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/app/orchestrator/calculation"
 	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/agent"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/system"
+	orchapi "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/api/orchestrator"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/health"
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/tracing"
 	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 type MockCalculationRepository struct {
@@ -28,7 +46,7 @@ func (m *MockCalculationRepository) Create(ctx context.Context, calculation *orc
 	return args.Get(0).(*orchestrator.Calculation), args.Error(1)
 }
 
-func (m *MockCalculationRepository) FindByID(ctx context.Context, id uuid.UUID) (*orchestrator.Calculation, error) {
+func (m *MockCalculationRepository) FindByID(ctx context.Context, id uuid.UUID, _ ...orchestrator.FindOption) (*orchestrator.Calculation, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -36,12 +54,12 @@ func (m *MockCalculationRepository) FindByID(ctx context.Context, id uuid.UUID)
 	return args.Get(0).(*orchestrator.Calculation), args.Error(1)
 }
 
-func (m *MockCalculationRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*orchestrator.Calculation, error) {
-	args := m.Called(ctx, userID)
+func (m *MockCalculationRepository) FindByUserID(ctx context.Context, userID uuid.UUID, filter orchestrator.CalculationFilter) ([]*orchestrator.Calculation, int, error) {
+	args := m.Called(ctx, userID, filter)
 	if args.Get(0) == nil {
-		return nil, args.Error(1)
+		return nil, args.Int(1), args.Error(2)
 	}
-	return args.Get(0).([]*orchestrator.Calculation), args.Error(1)
+	return args.Get(0).([]*orchestrator.Calculation), args.Int(1), args.Error(2)
 }
 
 func (m *MockCalculationRepository) Update(ctx context.Context, calculation *orchestrator.Calculation) error {
@@ -54,11 +72,45 @@ func (m *MockCalculationRepository) UpdateStatus(ctx context.Context, id uuid.UU
 	return args.Error(0)
 }
 
+func (m *MockCalculationRepository) FindByIdempotencyKey(ctx context.Context, userID uuid.UUID, key string) (*orchestrator.Calculation, error) {
+	args := m.Called(ctx, userID, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*orchestrator.Calculation), args.Error(1)
+}
+
+func (m *MockCalculationRepository) UpdateWarnings(ctx context.Context, id uuid.UUID, warnings []string) error {
+	args := m.Called(ctx, id, warnings)
+	return args.Error(0)
+}
+
+func (m *MockCalculationRepository) UpdateDisplayResult(ctx context.Context, id uuid.UUID, displayResult string) error {
+	args := m.Called(ctx, id, displayResult)
+	return args.Error(0)
+}
+
 func (m *MockCalculationRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
+func (m *MockCalculationRepository) CountByStatus(ctx context.Context) (map[orchestrator.CalculationStatus]int, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[orchestrator.CalculationStatus]int), args.Error(1)
+}
+
+func (m *MockCalculationRepository) ResultStats(ctx context.Context, userID uuid.UUID) (*orchestrator.ResultStats, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*orchestrator.ResultStats), args.Error(1)
+}
+
 type MockOperationRepository struct {
 	mock.Mock
 }
@@ -76,6 +128,14 @@ func (m *MockOperationRepository) CreateBatch(ctx context.Context, operations []
 	return args.Error(0)
 }
 
+func (m *MockOperationRepository) CreateBatchPartial(ctx context.Context, operations []*orchestrator.Operation) ([]orchestrator.OperationInsertResult, error) {
+	args := m.Called(ctx, operations)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]orchestrator.OperationInsertResult), args.Error(1)
+}
+
 func (m *MockOperationRepository) FindByID(ctx context.Context, id uuid.UUID) (*orchestrator.Operation, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -110,11 +170,31 @@ func (m *MockOperationRepository) UpdateStatus(ctx context.Context, id uuid.UUID
 	return args.Error(0)
 }
 
+func (m *MockOperationRepository) UpdateStatusBatch(ctx context.Context, updates []orchestrator.OperationStatusUpdate) error {
+	args := m.Called(ctx, updates)
+	return args.Error(0)
+}
+
 func (m *MockOperationRepository) AssignAgent(ctx context.Context, operationID uuid.UUID, agentID string) error {
 	args := m.Called(ctx, operationID, agentID)
 	return args.Error(0)
 }
 
+func (m *MockOperationRepository) CountByStatus(ctx context.Context, status orchestrator.OperationStatus) (int, error) {
+	args := m.Called(ctx, status)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockOperationRepository) CountPendingByUserID(ctx context.Context, userID uuid.UUID) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockOperationRepository) DeleteByCalculationID(ctx context.Context, calculationID uuid.UUID) (int, error) {
+	args := m.Called(ctx, calculationID)
+	return args.Int(0), args.Error(1)
+}
+
 type MockExpressionParser struct {
 	mock.Mock
 }
@@ -132,6 +212,16 @@ func (m *MockExpressionParser) Validate(ctx context.Context, expression string)
 	return args.Error(0)
 }
 
+func (m *MockExpressionParser) Evaluate(ctx context.Context, expression string) (float64, error) {
+	args := m.Called(ctx, expression)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockExpressionParser) Parenthesize(ctx context.Context, expression string, options orchestrator.PreviewOptions) (string, error) {
+	args := m.Called(ctx, expression, options)
+	return args.String(0), args.Error(1)
+}
+
 func (m *MockExpressionParser) SetCalculationID(operations []*orchestrator.Operation, calculationID uuid.UUID) {
 	m.Called(operations, calculationID)
 }
@@ -213,11 +303,13 @@ func setupTestContext() context.Context {
 	mockLog := new(MockLogger)
 	mockLog.On("With", mock.Anything).Return(mockLog).Maybe()
 	mockLog.On("Debug", mock.Anything, mock.Anything).Maybe()
+	mockLog.On("Debug", mock.Anything, mock.Anything, mock.Anything).Maybe()
 	mockLog.On("Info", mock.Anything, mock.Anything).Maybe()
 	mockLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Maybe()
 	mockLog.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
 	mockLog.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
 	mockLog.On("Warn", mock.Anything, mock.Anything).Maybe()
+	mockLog.On("Warn", mock.Anything, mock.Anything, mock.Anything).Maybe()
 	mockLog.On("Error", mock.Anything, mock.Anything).Maybe()
 	mockLog.On("RawLogger").Return(zap.NewNop()).Maybe()
 
@@ -358,7 +450,7 @@ func TestCalculateExpression(t *testing.T) {
 
 			uc := calculation.NewUseCase(calcRepo, opRepo, parser)
 
-			result, err := uc.CalculateExpression(ctx, tc.userID, tc.expression)
+			result, err := uc.CalculateExpression(ctx, tc.userID, tc.expression, "")
 
 			if tc.expectedError != nil {
 				assert.Error(t, err)
@@ -380,76 +472,42 @@ func TestCalculateExpression(t *testing.T) {
 	}
 }
 
-func TestGetCalculation(t *testing.T) {
-	calculationID := uuid.New()
-	userID := uuid.New()
-	otherUserID := uuid.New()
-
+func TestCalculateExpressionSource(t *testing.T) {
 	testCases := []struct {
-		name          string
-		calculationID uuid.UUID
-		userID        uuid.UUID
-		setupMocks    func(*MockCalculationRepository, *MockOperationRepository)
-		expectedError error
+		name           string
+		source         orchestrator.CalculationSource
+		expectedSource orchestrator.CalculationSource
+		expectedError  error
 	}{
 		{
-			name:          "Success case",
-			calculationID: calculationID,
-			userID:        userID,
-			setupMocks: func(calcRepo *MockCalculationRepository, opRepo *MockOperationRepository) {
-				calcRepo.On("FindByID", mock.Anything, calculationID).Return(&orchestrator.Calculation{
-					ID:         calculationID,
-					UserID:     userID,
-					Expression: "1+2",
-					Result:     "3",
-					Status:     orchestrator.CalculationStatusCompleted,
-				}, nil)
-
-				operations := []*orchestrator.Operation{
-					{
-						ID:            uuid.New(),
-						CalculationID: calculationID,
-						OperationType: orchestrator.OperationTypeAddition,
-						Operand1:      "1",
-						Operand2:      "2",
-						Result:        "3",
-						Status:        orchestrator.OperationStatusCompleted,
-					},
-				}
-
-				opRepo.On("FindByCalculationID", mock.Anything, calculationID).Return(operations, nil)
-			},
-			expectedError: nil,
+			name:           "Web channel",
+			source:         orchestrator.CalculationSourceWeb,
+			expectedSource: orchestrator.CalculationSourceWeb,
 		},
 		{
-			name:          "Calculation not found",
-			calculationID: calculationID,
-			userID:        userID,
-			setupMocks: func(calcRepo *MockCalculationRepository, opRepo *MockOperationRepository) {
-				calcRepo.On("FindByID", mock.Anything, calculationID).Return(nil, nil)
-			},
-			expectedError: domainerrors.ErrCalculationNotFound,
+			name:           "CLI channel",
+			source:         orchestrator.CalculationSourceCLI,
+			expectedSource: orchestrator.CalculationSourceCLI,
 		},
 		{
-			name:          "Repository error",
-			calculationID: calculationID,
-			userID:        userID,
-			setupMocks: func(calcRepo *MockCalculationRepository, opRepo *MockOperationRepository) {
-				calcRepo.On("FindByID", mock.Anything, calculationID).Return(nil, errors.New("database error"))
-			},
-			expectedError: domainerrors.ErrInternalError,
+			name:           "API token channel",
+			source:         orchestrator.CalculationSourceAPIToken,
+			expectedSource: orchestrator.CalculationSourceAPIToken,
 		},
 		{
-			name:          "Unauthorized access",
-			calculationID: calculationID,
-			userID:        otherUserID,
-			setupMocks: func(calcRepo *MockCalculationRepository, opRepo *MockOperationRepository) {
-				calcRepo.On("FindByID", mock.Anything, calculationID).Return(&orchestrator.Calculation{
-					ID:     calculationID,
-					UserID: userID,
-				}, nil)
-			},
-			expectedError: domainerrors.ErrUnauthorizedAccess,
+			name:           "Batch channel",
+			source:         orchestrator.CalculationSourceBatch,
+			expectedSource: orchestrator.CalculationSourceBatch,
+		},
+		{
+			name:           "Empty source defaults to web",
+			source:         "",
+			expectedSource: orchestrator.CalculationSourceWeb,
+		},
+		{
+			name:          "Unknown source",
+			source:        "fax",
+			expectedError: domainerrors.ErrInvalidSource,
 		},
 	}
 
@@ -461,246 +519,1233 @@ func TestGetCalculation(t *testing.T) {
 			opRepo := new(MockOperationRepository)
 			parser := new(MockExpressionParser)
 
-			tc.setupMocks(calcRepo, opRepo)
+			if tc.expectedError == nil {
+				calcID := uuid.New()
+				parser.On("Validate", mock.Anything, "1+2").Return(nil)
+				calcRepo.On("Create", mock.Anything, mock.MatchedBy(func(calc *orchestrator.Calculation) bool {
+					return calc.Source == tc.expectedSource
+				})).Return(&orchestrator.Calculation{
+					ID:     calcID,
+					Status: orchestrator.CalculationStatusPending,
+					Source: tc.expectedSource,
+				}, nil)
+
+				operations := []*orchestrator.Operation{{ID: uuid.New(), OperationType: orchestrator.OperationTypeAddition}}
+				parser.On("Parse", mock.Anything, "1+2").Return(operations, nil)
+				parser.On("SetCalculationID", operations, calcID).Return()
+				opRepo.On("CreateBatch", mock.Anything, operations).Return(nil)
+				calcRepo.On("UpdateStatus", mock.Anything, calcID, orchestrator.CalculationStatusInProgress, "", "").Return(nil)
+				calcRepo.On("FindByID", mock.Anything, calcID).Return(&orchestrator.Calculation{
+					ID:     calcID,
+					Status: orchestrator.CalculationStatusInProgress,
+					Source: tc.expectedSource,
+				}, nil)
+			}
 
 			uc := calculation.NewUseCase(calcRepo, opRepo, parser)
 
-			result, err := uc.GetCalculation(ctx, tc.calculationID, tc.userID)
+			result, err := uc.CalculateExpression(ctx, uuid.New(), "1+2", tc.source)
 
 			if tc.expectedError != nil {
-				assert.Error(t, err)
-				assert.True(t, errors.Is(err, tc.expectedError) ||
-					strings.Contains(err.Error(), tc.expectedError.Error()),
-					"expected error containing %v, got %v", tc.expectedError, err)
+				assert.ErrorIs(t, err, tc.expectedError)
 				assert.Nil(t, result)
 			} else {
 				assert.NoError(t, err)
-				assert.NotNil(t, result)
-				assert.Equal(t, tc.calculationID, result.ID)
-				assert.Equal(t, tc.userID, result.UserID)
+				assert.Equal(t, tc.expectedSource, result.Source)
 			}
 
 			calcRepo.AssertExpectations(t)
 			opRepo.AssertExpectations(t)
+			parser.AssertExpectations(t)
 		})
 	}
 }
 
-func TestListCalculations(t *testing.T) {
+func TestCalculateExpressionClientVersion(t *testing.T) {
+	testCases := []struct {
+		name            string
+		clientVersion   string
+		expectedVersion string
+	}{
+		{name: "Version stored", clientVersion: "calc-cli/2.1.0", expectedVersion: "calc-cli/2.1.0"},
+		{name: "Version trimmed", clientVersion: "  1.4.0 ", expectedVersion: "1.4.0"},
+		{name: "Long version truncated", clientVersion: strings.Repeat("v", 100), expectedVersion: strings.Repeat("v", orchestrator.MaxClientVersionLength)},
+		{name: "No version", expectedVersion: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := setupTestContext()
+			if tc.clientVersion != "" {
+				ctx = orchestrator.WithClientVersion(ctx, tc.clientVersion)
+			}
+
+			calcRepo := new(MockCalculationRepository)
+			opRepo := new(MockOperationRepository)
+			parser := new(MockExpressionParser)
+			calcID := uuid.New()
+
+			parser.On("Validate", mock.Anything, "1+2").Return(nil)
+			calcRepo.On("Create", mock.Anything, mock.MatchedBy(func(calc *orchestrator.Calculation) bool {
+				return calc.ClientVersion == tc.expectedVersion
+			})).Return(&orchestrator.Calculation{ID: calcID, Status: orchestrator.CalculationStatusPending}, nil)
+			parser.On("Parse", mock.Anything, "1+2").Return([]*orchestrator.Operation{}, nil)
+			parser.On("SetCalculationID", mock.Anything, calcID).Return()
+			opRepo.On("CreateBatch", mock.Anything, mock.Anything).Return(nil)
+			calcRepo.On("UpdateStatus", mock.Anything, calcID, orchestrator.CalculationStatusInProgress, "", "").Return(nil)
+			calcRepo.On("FindByID", mock.Anything, calcID).Return(&orchestrator.Calculation{
+				ID:            calcID,
+				Status:        orchestrator.CalculationStatusInProgress,
+				ClientVersion: tc.expectedVersion,
+			}, nil)
+
+			uc := calculation.NewUseCase(calcRepo, opRepo, parser)
+			_, err := uc.CalculateExpression(ctx, uuid.New(), "1+2", orchestrator.CalculationSourceCLI)
+
+			require.NoError(t, err)
+			calcRepo.AssertExpectations(t)
+		})
+	}
+}
+
+type stubDBPinger struct {
+	err error
+}
+
+func (p *stubDBPinger) Ping(context.Context) error {
+	return p.err
+}
+
+func TestCalculateExpressionDBHealth(t *testing.T) {
+	ctx := setupTestContext()
+	pinger := &stubDBPinger{err: errors.New("dial tcp 10.0.0.5:5432: connection refused")}
+	monitor := health.NewMonitor(pinger, 0, 1)
+	readiness := health.NewChecker()
+	readiness.AddPinger("database", monitor)
+
+	calcRepo := new(MockCalculationRepository)
+	opRepo := new(MockOperationRepository)
+	parser := new(MockExpressionParser)
+	uc := calculation.NewUseCase(calcRepo, opRepo, parser)
+	uc.SetDBHealth(monitor)
+
+	// Проверка готовности отмечает базу недоступной и сообщает причину
+	report := readiness.Check(ctx)
+	assert.False(t, report.Healthy())
+	assert.Equal(t, pinger.err.Error(), report.Checks["database"].Error)
+
+	start := time.Now()
+	result, err := uc.CalculateExpression(ctx, uuid.New(), "1+2", orchestrator.CalculationSourceWeb)
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+	assert.ErrorIs(t, err, domainerrors.ErrDatabaseUnavailable)
+	assert.ErrorContains(t, err, "connection refused")
+	assert.Nil(t, result)
+	calcRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	parser.AssertNotCalled(t, "Validate", mock.Anything, mock.Anything)
+
+	// После успешной проверки прием вычислений возобновляется
+	pinger.err = nil
+	require.True(t, readiness.Check(ctx).Healthy())
+
+	calcID := uuid.New()
+	parser.On("Validate", mock.Anything, "1+2").Return(nil)
+	calcRepo.On("Create", mock.Anything, mock.Anything).Return(&orchestrator.Calculation{ID: calcID, Status: orchestrator.CalculationStatusPending}, nil)
+	parser.On("Parse", mock.Anything, "1+2").Return([]*orchestrator.Operation{}, nil)
+	parser.On("SetCalculationID", mock.Anything, calcID).Return()
+	opRepo.On("CreateBatch", mock.Anything, mock.Anything).Return(nil)
+	calcRepo.On("UpdateStatus", mock.Anything, calcID, orchestrator.CalculationStatusInProgress, "", "").Return(nil)
+	calcRepo.On("FindByID", mock.Anything, calcID).Return(&orchestrator.Calculation{ID: calcID, Status: orchestrator.CalculationStatusInProgress}, nil)
+
+	result, err = uc.CalculateExpression(ctx, uuid.New(), "1+2", orchestrator.CalculationSourceWeb)
+	require.NoError(t, err)
+	assert.Equal(t, calcID, result.ID)
+}
+
+func TestCalculateExpressionIdempotencyKey(t *testing.T) {
+	t.Run("Replay returns first calculation", func(t *testing.T) {
+		ctx := orchestrator.WithIdempotencyKey(setupTestContext(), "retry-42")
+		calcRepo := new(MockCalculationRepository)
+		opRepo := new(MockOperationRepository)
+		parser := new(MockExpressionParser)
+		userID := uuid.New()
+		calcID := uuid.New()
+		stored := &orchestrator.Calculation{ID: calcID, UserID: userID, Status: orchestrator.CalculationStatusInProgress, IdempotencyKey: "retry-42"}
+
+		calcRepo.On("FindByIdempotencyKey", mock.Anything, userID, "retry-42").Return(nil, nil).Once()
+		parser.On("Validate", mock.Anything, "1+2").Return(nil)
+		calcRepo.On("Create", mock.Anything, mock.MatchedBy(func(calc *orchestrator.Calculation) bool {
+			return calc.IdempotencyKey == "retry-42"
+		})).Return(&orchestrator.Calculation{ID: calcID, UserID: userID, Status: orchestrator.CalculationStatusPending}, nil).Once()
+		parser.On("Parse", mock.Anything, "1+2").Return([]*orchestrator.Operation{}, nil)
+		parser.On("SetCalculationID", mock.Anything, calcID).Return()
+		opRepo.On("CreateBatch", mock.Anything, mock.Anything).Return(nil)
+		calcRepo.On("UpdateStatus", mock.Anything, calcID, orchestrator.CalculationStatusInProgress, "", "").Return(nil)
+		calcRepo.On("FindByID", mock.Anything, calcID).Return(stored, nil)
+
+		uc := calculation.NewUseCase(calcRepo, opRepo, parser)
+		first, err := uc.CalculateExpression(ctx, userID, "1+2", orchestrator.CalculationSourceWeb)
+		require.NoError(t, err)
+
+		calcRepo.On("FindByIdempotencyKey", mock.Anything, userID, "retry-42").Return(stored, nil).Once()
+		replayed, err := uc.CalculateExpression(ctx, userID, "1+2", orchestrator.CalculationSourceWeb)
+		require.NoError(t, err)
+
+		assert.Equal(t, calcID, first.ID)
+		assert.Equal(t, first.ID, replayed.ID)
+		calcRepo.AssertNumberOfCalls(t, "Create", 1)
+		calcRepo.AssertExpectations(t)
+	})
+
+	t.Run("Concurrent replay resolves insert conflict", func(t *testing.T) {
+		ctx := orchestrator.WithIdempotencyKey(setupTestContext(), "retry-42")
+		calcRepo := new(MockCalculationRepository)
+		opRepo := new(MockOperationRepository)
+		parser := new(MockExpressionParser)
+		userID := uuid.New()
+		existing := &orchestrator.Calculation{ID: uuid.New(), UserID: userID, Status: orchestrator.CalculationStatusInProgress}
+
+		calcRepo.On("FindByIdempotencyKey", mock.Anything, userID, "retry-42").Return(nil, nil).Once()
+		parser.On("Validate", mock.Anything, "1+2").Return(nil)
+		calcRepo.On("Create", mock.Anything, mock.Anything).
+			Return(nil, fmt.Errorf("insert: %w", domainerrors.ErrIdempotencyKeyConflict)).Once()
+		calcRepo.On("FindByIdempotencyKey", mock.Anything, userID, "retry-42").Return(existing, nil).Once()
+
+		uc := calculation.NewUseCase(calcRepo, opRepo, parser)
+		result, err := uc.CalculateExpression(ctx, userID, "1+2", orchestrator.CalculationSourceWeb)
+
+		require.NoError(t, err)
+		assert.Equal(t, existing.ID, result.ID)
+		parser.AssertNotCalled(t, "Parse", mock.Anything, mock.Anything)
+		calcRepo.AssertExpectations(t)
+	})
+
+	t.Run("Too long key rejected", func(t *testing.T) {
+		ctx := orchestrator.WithIdempotencyKey(setupTestContext(), strings.Repeat("k", orchestrator.MaxIdempotencyKeyLength+1))
+		calcRepo := new(MockCalculationRepository)
+
+		uc := calculation.NewUseCase(calcRepo, new(MockOperationRepository), new(MockExpressionParser))
+		_, err := uc.CalculateExpression(ctx, uuid.New(), "1+2", orchestrator.CalculationSourceWeb)
+
+		require.ErrorIs(t, err, domainerrors.ErrInvalidIdempotencyKey)
+		calcRepo.AssertNotCalled(t, "FindByIdempotencyKey", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestAdminGetCalculation(t *testing.T) {
+	t.Run("Calculation of any user returned with client version", func(t *testing.T) {
+		calcRepo := new(MockCalculationRepository)
+		calcID := uuid.New()
+		calcRepo.On("FindByID", mock.Anything, calcID).Return(&orchestrator.Calculation{
+			ID:            calcID,
+			UserID:        uuid.New(),
+			Status:        orchestrator.CalculationStatusCompleted,
+			ClientVersion: "calc-cli/2.1.0",
+		}, nil)
+
+		uc := calculation.NewUseCase(calcRepo, new(MockOperationRepository), new(MockExpressionParser))
+		calc, err := uc.AdminGetCalculation(setupTestContext(), calcID)
+
+		require.NoError(t, err)
+		assert.Equal(t, "calc-cli/2.1.0", calc.ClientVersion)
+	})
+
+	t.Run("Calculation not found", func(t *testing.T) {
+		calcRepo := new(MockCalculationRepository)
+		calcRepo.On("FindByID", mock.Anything, mock.Anything).Return(nil, nil)
+
+		uc := calculation.NewUseCase(calcRepo, new(MockOperationRepository), new(MockExpressionParser))
+		_, err := uc.AdminGetCalculation(setupTestContext(), uuid.New())
+
+		require.ErrorIs(t, err, domainerrors.ErrCalculationNotFound)
+	})
+}
+
+func TestGetOperation(t *testing.T) {
 	userID := uuid.New()
+	calcID := uuid.New()
+	operationID := uuid.New()
+	operation := &orchestrator.Operation{
+		ID:            operationID,
+		CalculationID: calcID,
+		OperationType: orchestrator.OperationTypeDivision,
+		Operand1:      "1",
+		Operand2:      "0",
+		Status:        orchestrator.OperationStatusError,
+		ErrorMessage:  "division by zero",
+		AgentID:       "agent-1",
+	}
 
 	testCases := []struct {
 		name          string
-		userID        uuid.UUID
-		setupMocks    func(*MockCalculationRepository)
-		expectedCount int
+		operation     *orchestrator.Operation
+		operationErr  error
+		calculation   *orchestrator.Calculation
 		expectedError error
 	}{
 		{
-			name:   "Success case with calculations",
-			userID: userID,
-			setupMocks: func(calcRepo *MockCalculationRepository) {
-				calculations := []*orchestrator.Calculation{
-					{
-						ID:         uuid.New(),
-						UserID:     userID,
-						Expression: "1+2",
-						Result:     "3",
-						Status:     orchestrator.CalculationStatusCompleted,
-					},
-					{
-						ID:         uuid.New(),
-						UserID:     userID,
-						Expression: "3*4",
-						Result:     "12",
-						Status:     orchestrator.CalculationStatusCompleted,
-					},
-				}
-
-				calcRepo.On("FindByUserID", mock.Anything, userID).Return(calculations, nil)
-			},
-			expectedCount: 2,
-			expectedError: nil,
+			name:        "Operation of own calculation",
+			operation:   operation,
+			calculation: &orchestrator.Calculation{ID: calcID, UserID: userID},
 		},
 		{
-			name:   "Success case no calculations",
-			userID: userID,
-			setupMocks: func(calcRepo *MockCalculationRepository) {
-				calcRepo.On("FindByUserID", mock.Anything, userID).Return([]*orchestrator.Calculation{}, nil)
-			},
-			expectedCount: 0,
-			expectedError: nil,
+			name:          "Operation of another user's calculation",
+			operation:     operation,
+			calculation:   &orchestrator.Calculation{ID: calcID, UserID: uuid.New()},
+			expectedError: domainerrors.ErrUnauthorizedAccess,
 		},
 		{
-			name:   "Invalid user ID",
-			userID: uuid.Nil,
-			setupMocks: func(calcRepo *MockCalculationRepository) {
-			},
-			expectedCount: 0,
-			expectedError: domainerrors.ErrInvalidUserID,
+			name:          "Operation not found",
+			expectedError: domainerrors.ErrOperationNotFound,
 		},
 		{
-			name:   "Repository error",
-			userID: userID,
-			setupMocks: func(calcRepo *MockCalculationRepository) {
-				calcRepo.On("FindByUserID", mock.Anything, userID).Return(nil, errors.New("database error"))
-			},
-			expectedCount: 0,
+			name:          "Calculation of operation deleted",
+			operation:     operation,
+			expectedError: domainerrors.ErrOperationNotFound,
+		},
+		{
+			name:          "Repository error",
+			operationErr:  errors.New("connection refused"),
 			expectedError: domainerrors.ErrInternalError,
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			ctx := setupTestContext()
-
 			calcRepo := new(MockCalculationRepository)
 			opRepo := new(MockOperationRepository)
-			parser := new(MockExpressionParser)
-
-			tc.setupMocks(calcRepo)
-
-			uc := calculation.NewUseCase(calcRepo, opRepo, parser)
+			opRepo.On("FindByID", mock.Anything, operationID).Return(tc.operation, tc.operationErr)
+			calcRepo.On("FindByID", mock.Anything, calcID).Return(tc.calculation, nil).Maybe()
 
-			result, err := uc.ListCalculations(ctx, tc.userID)
+			uc := calculation.NewUseCase(calcRepo, opRepo, new(MockExpressionParser))
+			op, err := uc.GetOperation(setupTestContext(), operationID, userID)
 
 			if tc.expectedError != nil {
-				assert.Error(t, err)
-				assert.True(t, errors.Is(err, tc.expectedError) ||
-					strings.Contains(err.Error(), tc.expectedError.Error()),
-					"expected error containing %v, got %v", tc.expectedError, err)
-				assert.Nil(t, result)
-			} else {
-				assert.NoError(t, err)
-				assert.NotNil(t, result)
-				assert.Len(t, result, tc.expectedCount)
+				require.ErrorIs(t, err, tc.expectedError)
+				assert.Nil(t, op)
+				return
 			}
 
-			calcRepo.AssertExpectations(t)
+			require.NoError(t, err)
+			assert.Equal(t, operation, op)
 		})
 	}
 }
 
-func TestUpdateCalculationStatus(t *testing.T) {
-	calculationID := uuid.New()
+func TestCalculateExpressionReferences(t *testing.T) {
+	userID := uuid.New()
+	referencedID := uuid.New()
+	expression := "calc:" + referencedID.String() + "+5"
 
 	testCases := []struct {
 		name          string
-		calculationID uuid.UUID
-		setupMocks    func(*MockCalculationRepository, *MockOperationRepository)
+		enabled       bool
+		referenced    *orchestrator.Calculation
 		expectedError error
 	}{
 		{
-			name:          "Success case - completed",
-			calculationID: calculationID,
-			setupMocks: func(calcRepo *MockCalculationRepository, opRepo *MockOperationRepository) {
-				calcRepo.On("FindByID", mock.Anything, calculationID).Return(&orchestrator.Calculation{
-					ID: calculationID,
-				}, nil)
-
-				operations := []*orchestrator.Operation{
-					{
-						ID:            uuid.New(),
-						CalculationID: calculationID,
-						Result:        "3",
-						Status:        orchestrator.OperationStatusCompleted,
-					},
-				}
-
-				opRepo.On("FindByCalculationID", mock.Anything, calculationID).Return(operations, nil)
-
-				calcRepo.On("UpdateStatus", mock.Anything, calculationID,
-					orchestrator.CalculationStatusCompleted, "3", "").Return(nil)
-			},
-			expectedError: nil,
+			name:       "Completed calculation result is substituted",
+			enabled:    true,
+			referenced: &orchestrator.Calculation{ID: referencedID, UserID: userID, Status: orchestrator.CalculationStatusCompleted, Result: "-3"},
 		},
 		{
-			name:          "Success case - in progress",
-			calculationID: calculationID,
-			setupMocks: func(calcRepo *MockCalculationRepository, opRepo *MockOperationRepository) {
-				calcRepo.On("FindByID", mock.Anything, calculationID).Return(&orchestrator.Calculation{
-					ID: calculationID,
+			name:          "Calculation in progress cannot be referenced",
+			enabled:       true,
+			referenced:    &orchestrator.Calculation{ID: referencedID, UserID: userID, Status: orchestrator.CalculationStatusInProgress},
+			expectedError: domainerrors.ErrReferenceNotCompleted,
+		},
+		{
+			name:          "Failed calculation cannot be referenced",
+			enabled:       true,
+			referenced:    &orchestrator.Calculation{ID: referencedID, UserID: userID, Status: orchestrator.CalculationStatusError, ErrorMessage: "division by zero"},
+			expectedError: domainerrors.ErrReferenceNotCompleted,
+		},
+		{
+			name:          "Another user's calculation cannot be referenced",
+			enabled:       true,
+			referenced:    &orchestrator.Calculation{ID: referencedID, UserID: uuid.New(), Status: orchestrator.CalculationStatusCompleted, Result: "4"},
+			expectedError: domainerrors.ErrUnauthorizedAccess,
+		},
+		{
+			name:          "Missing calculation cannot be referenced",
+			enabled:       true,
+			expectedError: domainerrors.ErrCalculationNotFound,
+		},
+		{
+			name:          "References are rejected by parser when disabled",
+			enabled:       false,
+			expectedError: domainerrors.ErrInvalidExpression,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := setupTestContext()
+
+			calcRepo := new(MockCalculationRepository)
+			opRepo := new(MockOperationRepository)
+			parser := new(MockExpressionParser)
+
+			if tc.enabled {
+				calcRepo.On("FindByID", mock.Anything, referencedID).Return(tc.referenced, nil).Once()
+			} else {
+				parser.On("Validate", mock.Anything, expression).Return(errors.New("unexpected character 'c'"))
+			}
+
+			if tc.expectedError == nil {
+				calcID := uuid.New()
+				resolved := "(-3)+5"
+
+				parser.On("Validate", mock.Anything, resolved).Return(nil)
+				calcRepo.On("Create", mock.Anything, mock.MatchedBy(func(calc *orchestrator.Calculation) bool {
+					return calc.Expression == expression
+				})).Return(&orchestrator.Calculation{ID: calcID, UserID: userID, Expression: expression, Status: orchestrator.CalculationStatusPending}, nil)
+
+				operations := []*orchestrator.Operation{{ID: uuid.New(), OperationType: orchestrator.OperationTypeAddition}}
+				parser.On("Parse", mock.Anything, resolved).Return(operations, nil)
+				parser.On("SetCalculationID", operations, calcID).Return()
+				opRepo.On("CreateBatch", mock.Anything, operations).Return(nil)
+				calcRepo.On("UpdateStatus", mock.Anything, calcID, orchestrator.CalculationStatusInProgress, "", "").Return(nil)
+				calcRepo.On("FindByID", mock.Anything, calcID).Return(&orchestrator.Calculation{
+					ID:         calcID,
+					UserID:     userID,
+					Expression: expression,
+					Status:     orchestrator.CalculationStatusInProgress,
 				}, nil)
+			}
 
-				operations := []*orchestrator.Operation{
-					{
-						ID:            uuid.New(),
-						CalculationID: calculationID,
-						Status:        orchestrator.OperationStatusInProgress,
-					},
-				}
+			uc := calculation.NewUseCase(calcRepo, opRepo, parser)
+			uc.SetCalculationReferences(tc.enabled)
 
-				opRepo.On("FindByCalculationID", mock.Anything, calculationID).Return(operations, nil)
+			result, err := uc.CalculateExpression(ctx, userID, expression, orchestrator.CalculationSourceWeb)
 
-				calcRepo.On("UpdateStatus", mock.Anything, calculationID,
-					orchestrator.CalculationStatusInProgress, "", "").Return(nil)
-			},
-			expectedError: nil,
-		},
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				assert.Nil(t, result)
+				calcRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, expression, result.Expression)
+			}
+
+			calcRepo.AssertExpectations(t)
+			opRepo.AssertExpectations(t)
+			parser.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCalculateExpressionEstimatedDuration(t *testing.T) {
+	ctx := setupTestContext()
+
+	calcRepo := new(MockCalculationRepository)
+	opRepo := new(MockOperationRepository)
+	parser := new(MockExpressionParser)
+
+	calcID := uuid.New()
+	parser.On("Validate", mock.Anything, "2*3+4").Return(nil)
+	calcRepo.On("Create", mock.Anything, mock.Anything).Return(&orchestrator.Calculation{
+		ID:     calcID,
+		Status: orchestrator.CalculationStatusPending,
+	}, nil)
+
+	operations := []*orchestrator.Operation{
+		{ID: uuid.New(), OperationType: orchestrator.OperationTypeMultiplication},
+		{ID: uuid.New(), OperationType: orchestrator.OperationTypeAddition},
+	}
+	parser.On("Parse", mock.Anything, "2*3+4").Return(operations, nil)
+	parser.On("SetCalculationID", operations, calcID).Return()
+	opRepo.On("CreateBatch", mock.Anything, operations).Return(nil)
+	calcRepo.On("UpdateStatus", mock.Anything, calcID, orchestrator.CalculationStatusInProgress, "", "").Return(nil)
+	calcRepo.On("FindByID", mock.Anything, calcID).Return(&orchestrator.Calculation{
+		ID:     calcID,
+		Status: orchestrator.CalculationStatusInProgress,
+	}, nil)
+
+	uc := calculation.NewUseCase(calcRepo, opRepo, parser)
+	uc.SetOperationTimes(map[string]time.Duration{
+		"addition":       time.Second,
+		"multiplication": 2 * time.Second,
+	})
+
+	result, err := uc.CalculateExpression(ctx, uuid.New(), "2*3+4", orchestrator.CalculationSourceWeb)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3*time.Second, result.EstimatedDuration)
+}
+
+func TestCalculateExpressionLikelyAgents(t *testing.T) {
+	run := func(t *testing.T, agents []*agent.Agent, enabled bool) *orchestrator.Calculation {
+		t.Helper()
+
+		calcRepo := new(MockCalculationRepository)
+		opRepo := new(MockOperationRepository)
+		parser := new(MockExpressionParser)
+
+		calcID := uuid.New()
+		parser.On("Validate", mock.Anything, "2*3+4*5").Return(nil)
+		calcRepo.On("Create", mock.Anything, mock.Anything).Return(&orchestrator.Calculation{
+			ID:     calcID,
+			Status: orchestrator.CalculationStatusPending,
+		}, nil)
+
+		operations := []*orchestrator.Operation{
+			{ID: uuid.New(), OperationType: orchestrator.OperationTypeMultiplication},
+			{ID: uuid.New(), OperationType: orchestrator.OperationTypeMultiplication},
+			{ID: uuid.New(), OperationType: orchestrator.OperationTypeAddition},
+		}
+		parser.On("Parse", mock.Anything, "2*3+4*5").Return(operations, nil)
+		parser.On("SetCalculationID", operations, calcID).Return()
+		opRepo.On("CreateBatch", mock.Anything, operations).Return(nil)
+		calcRepo.On("UpdateStatus", mock.Anything, calcID, orchestrator.CalculationStatusInProgress, "", "").Return(nil)
+		calcRepo.On("FindByID", mock.Anything, calcID).Return(&orchestrator.Calculation{
+			ID:     calcID,
+			Status: orchestrator.CalculationStatusInProgress,
+		}, nil)
+
+		pool := &stubAgentPool{agents: agents}
+		uc := calculation.NewUseCase(calcRepo, opRepo, parser)
+		uc.SetAgentPool(pool)
+		uc.SetAgentEstimate(enabled)
+
+		result, err := uc.CalculateExpression(setupTestContext(), uuid.New(), "2*3+4*5", orchestrator.CalculationSourceWeb)
+		require.NoError(t, err)
+		return result
+	}
+
+	newAgents := func() []*agent.Agent {
+		return []*agent.Agent{
+			{ID: "agent-1", Status: agent.AgentStatusOnline, MaxCapacity: 2, CurrentLoad: 1},
+			{ID: "agent-2", Status: agent.AgentStatusOnline, MaxCapacity: 2},
+			{ID: "agent-3", Status: agent.AgentStatusOffline, MaxCapacity: 10},
+		}
+	}
+
+	t.Run("Agents available", func(t *testing.T) {
+		agents := newAgents()
+		result := run(t, agents, true)
+
+		assert.Equal(t, []string{"agent-2", "agent-1"}, result.LikelyAgents)
+		assert.Equal(t, 1, agents[0].CurrentLoad, "estimate must not change the pool")
+		assert.Zero(t, agents[1].CurrentLoad, "estimate must not change the pool")
+	})
+
+	t.Run("No agent with free capacity", func(t *testing.T) {
+		result := run(t, []*agent.Agent{
+			{ID: "agent-1", Status: agent.AgentStatusBusy, MaxCapacity: 1, CurrentLoad: 1},
+			{ID: "agent-2", Status: agent.AgentStatusOffline, MaxCapacity: 1},
+		}, true)
+
+		assert.Nil(t, result.LikelyAgents)
+	})
+
+	t.Run("Estimate disabled", func(t *testing.T) {
+		result := run(t, newAgents(), false)
+
+		assert.Nil(t, result.LikelyAgents)
+	})
+}
+
+func TestCalculateExpressionTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	calcRepo := new(MockCalculationRepository)
+	opRepo := new(MockOperationRepository)
+	parser := new(MockExpressionParser)
+
+	userID := uuid.New()
+	calcID := uuid.New()
+	parser.On("Validate", mock.Anything, "2+2").Return(nil)
+	calcRepo.On("Create", mock.Anything, mock.Anything).Return(&orchestrator.Calculation{ID: calcID, UserID: userID, Status: orchestrator.CalculationStatusPending}, nil)
+
+	operations := []*orchestrator.Operation{{ID: uuid.New(), OperationType: orchestrator.OperationTypeAddition}}
+	parser.On("Parse", mock.Anything, "2+2").Return(operations, nil)
+	parser.On("SetCalculationID", operations, calcID).Return()
+	opRepo.On("CreateBatch", mock.Anything, operations).Return(nil)
+	calcRepo.On("UpdateStatus", mock.Anything, calcID, orchestrator.CalculationStatusInProgress, "", "").Return(nil)
+	calcRepo.On("FindByID", mock.Anything, calcID).Return(&orchestrator.Calculation{ID: calcID, UserID: userID, Status: orchestrator.CalculationStatusInProgress}, nil)
+
+	uc := calculation.NewUseCase(calcRepo, opRepo, parser)
+
+	// Корневой спан играет роль спана gRPC запроса, внутри которого вызывается сценарий
+	ctx := logger.WithLogger(context.Background(), logger.New(zapcore.NewNopCore()))
+	ctx = logger.WithRequestID(ctx, "req-42")
+	ctx, root := provider.Tracer("test").Start(ctx, "OrchestratorService/Calculate")
+	_, err := uc.CalculateExpression(ctx, userID, "2+2", orchestrator.CalculationSourceWeb)
+	root.End()
+	require.NoError(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+
+	calcSpan, rootSpan := spans[0], spans[1]
+	assert.Equal(t, "calculation.CalculateExpression", calcSpan.Name)
+	assert.Equal(t, rootSpan.SpanContext.SpanID(), calcSpan.Parent.SpanID())
+	assert.Equal(t, rootSpan.SpanContext.TraceID(), calcSpan.SpanContext.TraceID())
+
+	attrs := make(map[attribute.Key]string)
+	for _, attr := range calcSpan.Attributes {
+		attrs[attr.Key] = attr.Value.Emit()
+	}
+	assert.Equal(t, calcID.String(), attrs[tracing.AttrCalculationID])
+	assert.Equal(t, userID.String(), attrs[tracing.AttrUserID])
+	assert.Equal(t, "req-42", attrs[tracing.AttrRequestID])
+}
+
+func TestCalculateExpressionPartialBatchInsert(t *testing.T) {
+	setup := func(operations []*orchestrator.Operation, results []orchestrator.OperationInsertResult) (*calculation.UseCaseImpl, *MockCalculationRepository, *MockOperationRepository, uuid.UUID) {
+		calcRepo := new(MockCalculationRepository)
+		opRepo := new(MockOperationRepository)
+		parser := new(MockExpressionParser)
+
+		calcID := uuid.New()
+		parser.On("Validate", mock.Anything, "2*3+4").Return(nil)
+		calcRepo.On("Create", mock.Anything, mock.Anything).Return(&orchestrator.Calculation{
+			ID:     calcID,
+			Status: orchestrator.CalculationStatusPending,
+		}, nil)
+		parser.On("Parse", mock.Anything, "2*3+4").Return(operations, nil)
+		parser.On("SetCalculationID", operations, calcID).Return()
+		opRepo.On("CreateBatchPartial", mock.Anything, operations).Return(results, nil)
+
+		uc := calculation.NewUseCase(calcRepo, opRepo, parser)
+		uc.SetPartialBatchInsert(true)
+		uc.SetOperationTimes(map[string]time.Duration{
+			"addition":       time.Second,
+			"multiplication": 2 * time.Second,
+		})
+		return uc, calcRepo, opRepo, calcID
+	}
+
+	t.Run("Failed operation fails calculation and cancels saved ones", func(t *testing.T) {
+		operations := []*orchestrator.Operation{
+			{ID: uuid.New(), OperationType: orchestrator.OperationTypeMultiplication},
+			{ID: uuid.New(), OperationType: orchestrator.OperationTypeAddition},
+		}
+		results := []orchestrator.OperationInsertResult{
+			{Index: 0, OperationID: operations[0].ID, Err: errors.New("insert failed")},
+			{Index: 1, OperationID: operations[1].ID},
+		}
+		uc, calcRepo, opRepo, calcID := setup(operations, results)
+
+		opRepo.On("UpdateStatusBatch", mock.Anything, []orchestrator.OperationStatusUpdate{{
+			ID:           operations[1].ID,
+			Status:       orchestrator.OperationStatusCancelled,
+			ErrorMessage: "cancelled: another operation of the calculation was not saved",
+		}}).Return(nil).Once()
+		calcRepo.On("UpdateStatus", mock.Anything, calcID, orchestrator.CalculationStatusError, "", "Failed to create operations").Return(nil).Once()
+		calcRepo.On("FindByID", mock.Anything, calcID).Return(&orchestrator.Calculation{
+			ID:     calcID,
+			Status: orchestrator.CalculationStatusError,
+		}, nil)
+
+		result, err := uc.CalculateExpression(setupTestContext(), uuid.New(), "2*3+4", orchestrator.CalculationSourceWeb)
+
+		require.NoError(t, err)
+		assert.Equal(t, orchestrator.CalculationStatusError, result.Status)
+		opRepo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+		calcRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, calcID, orchestrator.CalculationStatusInProgress, "", "")
+		opRepo.AssertExpectations(t)
+		calcRepo.AssertExpectations(t)
+	})
+
+	t.Run("All operations failed", func(t *testing.T) {
+		operations := []*orchestrator.Operation{
+			{ID: uuid.New(), OperationType: orchestrator.OperationTypeAddition},
+		}
+		results := []orchestrator.OperationInsertResult{
+			{Index: 0, OperationID: operations[0].ID, Err: errors.New("insert failed")},
+		}
+		uc, calcRepo, _, calcID := setup(operations, results)
+
+		calcRepo.On("UpdateStatus", mock.Anything, calcID, orchestrator.CalculationStatusError, "", "Failed to create operations").Return(nil)
+		calcRepo.On("FindByID", mock.Anything, calcID).Return(&orchestrator.Calculation{
+			ID:     calcID,
+			Status: orchestrator.CalculationStatusError,
+		}, nil)
+
+		result, err := uc.CalculateExpression(setupTestContext(), uuid.New(), "2*3+4", orchestrator.CalculationSourceWeb)
+
+		require.NoError(t, err)
+		assert.Equal(t, orchestrator.CalculationStatusError, result.Status)
+		calcRepo.AssertExpectations(t)
+	})
+}
+
+func TestCalculateExpressionDuplicateOperationIDs(t *testing.T) {
+	// 2*3 + 4*5 с парсером, который выдал обоим умножениям один ID.
+	setup := func() (*calculation.UseCaseImpl, *MockCalculationRepository, *MockOperationRepository, uuid.UUID, []*orchestrator.Operation) {
+		calcRepo := new(MockCalculationRepository)
+		opRepo := new(MockOperationRepository)
+		parser := new(MockExpressionParser)
+
+		duplicate := uuid.New()
+		operations := []*orchestrator.Operation{
+			{ID: duplicate, OperationType: orchestrator.OperationTypeMultiplication, Operand1: "2", Operand2: "3"},
+			{ID: duplicate, OperationType: orchestrator.OperationTypeMultiplication, Operand1: "4", Operand2: "5"},
+			{ID: uuid.New(), OperationType: orchestrator.OperationTypeAddition, Operand1: "ref:" + duplicate.String(), Operand2: "ref:" + duplicate.String()},
+		}
+
+		calcID := uuid.New()
+		parser.On("Validate", mock.Anything, "2*3+4*5").Return(nil)
+		calcRepo.On("Create", mock.Anything, mock.Anything).Return(&orchestrator.Calculation{
+			ID:     calcID,
+			Status: orchestrator.CalculationStatusPending,
+		}, nil)
+		parser.On("Parse", mock.Anything, "2*3+4*5").Return(operations, nil)
+		parser.On("SetCalculationID", operations, calcID).Return()
+
+		return calculation.NewUseCase(calcRepo, opRepo, parser), calcRepo, opRepo, calcID, operations
+	}
+
+	t.Run("Duplicates get new IDs", func(t *testing.T) {
+		uc, calcRepo, opRepo, calcID, operations := setup()
+		first := operations[0].ID
+
+		opRepo.On("CreateBatch", mock.Anything, operations).Return(nil)
+		calcRepo.On("UpdateStatus", mock.Anything, calcID, orchestrator.CalculationStatusInProgress, "", "").Return(nil)
+		calcRepo.On("FindByID", mock.Anything, calcID).Return(&orchestrator.Calculation{
+			ID:     calcID,
+			Status: orchestrator.CalculationStatusInProgress,
+		}, nil)
+
+		result, err := uc.CalculateExpression(setupTestContext(), uuid.New(), "2*3+4*5", orchestrator.CalculationSourceWeb)
+		require.NoError(t, err)
+		assert.Equal(t, orchestrator.CalculationStatusInProgress, result.Status)
+
+		assert.Empty(t, orchestrator.DuplicateOperationIDs(operations))
+		assert.Equal(t, first, operations[0].ID)
+		assert.NotEqual(t, first, operations[1].ID)
+		assert.Equal(t, "ref:"+operations[1].ID.String(), operations[2].Operand1)
+		assert.Equal(t, "ref:"+operations[1].ID.String(), operations[2].Operand2)
+		opRepo.AssertExpectations(t)
+	})
+
+	t.Run("Duplicates rejected", func(t *testing.T) {
+		uc, calcRepo, opRepo, calcID, _ := setup()
+		uc.SetRejectDuplicateOperationIDs(true)
+
+		calcRepo.On("UpdateStatus", mock.Anything, calcID, orchestrator.CalculationStatusError, "", "Failed to create operations: duplicate operation IDs").Return(nil)
+		calcRepo.On("FindByID", mock.Anything, calcID).Return(&orchestrator.Calculation{
+			ID:     calcID,
+			Status: orchestrator.CalculationStatusError,
+		}, nil)
+
+		result, err := uc.CalculateExpression(setupTestContext(), uuid.New(), "2*3+4*5", orchestrator.CalculationSourceWeb)
+		require.NoError(t, err)
+		assert.Equal(t, orchestrator.CalculationStatusError, result.Status)
+		opRepo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+		calcRepo.AssertExpectations(t)
+	})
+}
+
+func TestCalculateExpressionSupportedOperations(t *testing.T) {
+	// 7%3 + 1 разбирается на остаток и сложение.
+	setup := func(supported ...string) (*calculation.UseCaseImpl, *MockCalculationRepository, *MockOperationRepository, *MockExpressionParser) {
+		calcRepo := new(MockCalculationRepository)
+		opRepo := new(MockOperationRepository)
+		parser := new(MockExpressionParser)
+
+		modulo := uuid.New()
+		operations := []*orchestrator.Operation{
+			{ID: modulo, OperationType: orchestrator.OperationTypeModulo, Operand1: "7", Operand2: "3"},
+			{ID: uuid.New(), OperationType: orchestrator.OperationTypeAddition, Operand1: "ref:" + modulo.String(), Operand2: "1"},
+		}
+		parser.On("Validate", mock.Anything, "7%3+1").Return(nil)
+		parser.On("Parse", mock.Anything, "7%3+1").Return(operations, nil)
+
+		uc := calculation.NewUseCase(calcRepo, opRepo, parser)
+		uc.SetSupportedOperations(supported)
+		return uc, calcRepo, opRepo, parser
+	}
+
+	t.Run("Unsupported operation rejected before saving", func(t *testing.T) {
+		uc, calcRepo, opRepo, _ := setup("addition", "subtraction", "multiplication", "division")
+
+		result, err := uc.CalculateExpression(setupTestContext(), uuid.New(), "7%3+1", orchestrator.CalculationSourceWeb)
+
+		require.ErrorIs(t, err, domainerrors.ErrUnsupportedOp)
+		assert.ErrorIs(t, err, domainerrors.ErrInvalidExpression)
+		assert.Contains(t, err.Error(), "modulo")
+		assert.Nil(t, result)
+		calcRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		opRepo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Supported operations accepted", func(t *testing.T) {
+		uc, calcRepo, opRepo, parser := setup(" Addition", "modulo ")
+		calcID := uuid.New()
+
+		calcRepo.On("Create", mock.Anything, mock.Anything).Return(&orchestrator.Calculation{
+			ID:     calcID,
+			Status: orchestrator.CalculationStatusPending,
+		}, nil)
+		parser.On("SetCalculationID", mock.Anything, calcID).Return()
+		opRepo.On("CreateBatch", mock.Anything, mock.Anything).Return(nil)
+		calcRepo.On("UpdateStatus", mock.Anything, calcID, orchestrator.CalculationStatusInProgress, "", "").Return(nil)
+		calcRepo.On("FindByID", mock.Anything, calcID).Return(&orchestrator.Calculation{
+			ID:     calcID,
+			Status: orchestrator.CalculationStatusInProgress,
+		}, nil)
+
+		result, err := uc.CalculateExpression(setupTestContext(), uuid.New(), "7%3+1", orchestrator.CalculationSourceWeb)
+
+		require.NoError(t, err)
+		assert.Equal(t, orchestrator.CalculationStatusInProgress, result.Status)
+		opRepo.AssertExpectations(t)
+	})
+
+	t.Run("Check disabled by default", func(t *testing.T) {
+		uc, calcRepo, _, parser := setup()
+
+		calcRepo.On("Create", mock.Anything, mock.Anything).Return(nil, errors.New("db is down"))
+
+		_, err := uc.CalculateExpression(setupTestContext(), uuid.New(), "7%3+1", orchestrator.CalculationSourceWeb)
+
+		require.ErrorIs(t, err, domainerrors.ErrInternalError)
+		parser.AssertNotCalled(t, "Parse", mock.Anything, mock.Anything)
+	})
+}
+
+func TestWarmResultCache(t *testing.T) {
+	resultCache := cache.NewResultCache(10)
+	uc := calculation.NewUseCase(new(MockCalculationRepository), new(MockOperationRepository), parsersvc.NewService(100))
+	uc.SetResultCache(resultCache)
+	uc.SetResultRounding(orchestrator.Rounding{Precision: 2, Mode: orchestrator.RoundingHalfEven})
+
+	warmed := uc.WarmResultCache(setupTestContext(), []string{"2+2*2", " 10 / 4 ", "1/3", "2+", ""})
+
+	assert.Equal(t, 3, warmed)
+	assert.Equal(t, 3, resultCache.Len())
+
+	for expression, expected := range map[string]string{"2+2*2": "6", "10/4": "2.5", "1/3": "0.33"} {
+		result, found, err := resultCache.Get(context.Background(), expression)
+		require.NoError(t, err)
+		assert.True(t, found, expression)
+		assert.Equal(t, expected, result, expression)
+	}
+
+	t.Run("Without cache", func(t *testing.T) {
+		uc := calculation.NewUseCase(new(MockCalculationRepository), new(MockOperationRepository), parsersvc.NewService(100))
+		assert.Zero(t, uc.WarmResultCache(setupTestContext(), []string{"2+2"}))
+	})
+
+	t.Run("Precision by final operation", func(t *testing.T) {
+		resultCache := cache.NewResultCache(10)
+		uc := calculation.NewUseCase(new(MockCalculationRepository), new(MockOperationRepository), parsersvc.NewService(100))
+		uc.SetResultCache(resultCache)
+		uc.SetResultRounding(orchestrator.Rounding{
+			Precision:          2,
+			Mode:               orchestrator.RoundingHalfEven,
+			OperationPrecision: map[string]int{"division": 5, "addition": 0},
+		})
+
+		assert.Equal(t, 3, uc.WarmResultCache(setupTestContext(), []string{"1/3", "1/3+0.2", "7"}))
+
+		for expression, expected := range map[string]string{"1/3": "0.33333", "1/3+0.2": "1", "7": "7"} {
+			result, found, err := resultCache.Get(context.Background(), expression)
+			require.NoError(t, err)
+			assert.True(t, found, expression)
+			assert.Equal(t, expected, result, expression)
+		}
+	})
+}
+
+func TestCalculateExpressionPendingOperationsBudget(t *testing.T) {
+	// 7%3 + 1 разбирается на две операции.
+	setup := func(budget int) (*calculation.UseCaseImpl, *MockCalculationRepository, *MockOperationRepository, *MockExpressionParser) {
+		calcRepo := new(MockCalculationRepository)
+		opRepo := new(MockOperationRepository)
+		parser := new(MockExpressionParser)
+
+		modulo := uuid.New()
+		operations := []*orchestrator.Operation{
+			{ID: modulo, OperationType: orchestrator.OperationTypeModulo, Operand1: "7", Operand2: "3"},
+			{ID: uuid.New(), OperationType: orchestrator.OperationTypeAddition, Operand1: "ref:" + modulo.String(), Operand2: "1"},
+		}
+		parser.On("Validate", mock.Anything, "7%3+1").Return(nil)
+		parser.On("Parse", mock.Anything, "7%3+1").Return(operations, nil)
+
+		uc := calculation.NewUseCase(calcRepo, opRepo, parser)
+		uc.SetPendingOperationsBudget(budget)
+		return uc, calcRepo, opRepo, parser
+	}
+
+	accept := func(calcRepo *MockCalculationRepository, opRepo *MockOperationRepository, parser *MockExpressionParser) {
+		calcID := uuid.New()
+		calcRepo.On("Create", mock.Anything, mock.Anything).Return(&orchestrator.Calculation{
+			ID:     calcID,
+			Status: orchestrator.CalculationStatusPending,
+		}, nil)
+		parser.On("SetCalculationID", mock.Anything, calcID).Return()
+		opRepo.On("CreateBatch", mock.Anything, mock.Anything).Return(nil)
+		calcRepo.On("UpdateStatus", mock.Anything, calcID, orchestrator.CalculationStatusInProgress, "", "").Return(nil)
+		calcRepo.On("FindByID", mock.Anything, calcID).Return(&orchestrator.Calculation{
+			ID:     calcID,
+			Status: orchestrator.CalculationStatusInProgress,
+		}, nil)
+	}
+
+	t.Run("Rejected when combined count exceeds budget", func(t *testing.T) {
+		uc, calcRepo, opRepo, _ := setup(10)
+		userID := uuid.New()
+		opRepo.On("CountPendingByUserID", mock.Anything, userID).Return(9, nil)
+
+		result, err := uc.CalculateExpression(setupTestContext(), userID, "7%3+1", orchestrator.CalculationSourceWeb)
+
+		require.ErrorIs(t, err, domainerrors.ErrPendingOpsBudget)
+		assert.Contains(t, err.Error(), "9 pending and 2 new operations, budget is 10")
+		assert.Nil(t, result)
+		calcRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		opRepo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Accepted when combined count fits budget", func(t *testing.T) {
+		uc, calcRepo, opRepo, parser := setup(10)
+		userID := uuid.New()
+		opRepo.On("CountPendingByUserID", mock.Anything, userID).Return(8, nil)
+		accept(calcRepo, opRepo, parser)
+
+		result, err := uc.CalculateExpression(setupTestContext(), userID, "7%3+1", orchestrator.CalculationSourceWeb)
+
+		require.NoError(t, err)
+		assert.Equal(t, orchestrator.CalculationStatusInProgress, result.Status)
+		opRepo.AssertExpectations(t)
+	})
+
+	t.Run("Count failure does not block calculation", func(t *testing.T) {
+		uc, calcRepo, opRepo, parser := setup(1)
+		opRepo.On("CountPendingByUserID", mock.Anything, mock.Anything).Return(0, errors.New("db is down"))
+		accept(calcRepo, opRepo, parser)
+
+		_, err := uc.CalculateExpression(setupTestContext(), uuid.New(), "7%3+1", orchestrator.CalculationSourceWeb)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("Check disabled by default", func(t *testing.T) {
+		uc, calcRepo, opRepo, parser := setup(0)
+		accept(calcRepo, opRepo, parser)
+
+		_, err := uc.CalculateExpression(setupTestContext(), uuid.New(), "7%3+1", orchestrator.CalculationSourceWeb)
+
+		require.NoError(t, err)
+		opRepo.AssertNotCalled(t, "CountPendingByUserID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Expression parsed once with all checks enabled", func(t *testing.T) {
+		uc, calcRepo, opRepo, parser := setup(10)
+		uc.SetSupportedOperations([]string{"modulo", "addition"})
+		opRepo.On("CountPendingByUserID", mock.Anything, mock.Anything).Return(0, nil)
+		accept(calcRepo, opRepo, parser)
+
+		_, err := uc.CalculateExpression(setupTestContext(), uuid.New(), "7%3+1", orchestrator.CalculationSourceWeb)
+
+		require.NoError(t, err)
+		parser.AssertNumberOfCalls(t, "Parse", 1)
+		opRepo.AssertCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+	})
+}
+
+func TestCalculateExpressionPriority(t *testing.T) {
+	setup := func() (*calculation.UseCaseImpl, *MockCalculationRepository, *MockOperationRepository) {
+		calcRepo := new(MockCalculationRepository)
+		opRepo := new(MockOperationRepository)
+		parser := new(MockExpressionParser)
+
+		calcID := uuid.New()
+		operations := []*orchestrator.Operation{
+			{ID: uuid.New(), OperationType: orchestrator.OperationTypeAddition, Operand1: "2", Operand2: "3"},
+		}
+		parser.On("Validate", mock.Anything, "2+3").Return(nil)
+		parser.On("Parse", mock.Anything, "2+3").Return(operations, nil)
+		parser.On("SetCalculationID", mock.Anything, calcID).Return()
+		calcRepo.On("Create", mock.Anything, mock.Anything).Return(&orchestrator.Calculation{
+			ID:     calcID,
+			Status: orchestrator.CalculationStatusPending,
+		}, nil)
+		calcRepo.On("UpdateStatus", mock.Anything, calcID, orchestrator.CalculationStatusInProgress, "", "").Return(nil)
+		calcRepo.On("FindByID", mock.Anything, calcID).Return(&orchestrator.Calculation{
+			ID:     calcID,
+			Status: orchestrator.CalculationStatusInProgress,
+		}, nil)
+
+		return calculation.NewUseCase(calcRepo, opRepo, parser), calcRepo, opRepo
+	}
+
+	t.Run("Operations inherit submission priority", func(t *testing.T) {
+		uc, _, opRepo := setup()
+		opRepo.On("CreateBatch", mock.Anything, mock.MatchedBy(func(ops []*orchestrator.Operation) bool {
+			return len(ops) == 1 && ops[0].Priority == 7
+		})).Return(nil)
+
+		ctx := orchestrator.WithPriority(setupTestContext(), 7)
+		_, err := uc.CalculateExpression(ctx, uuid.New(), "2+3", orchestrator.CalculationSourceWeb)
+
+		require.NoError(t, err)
+		opRepo.AssertExpectations(t)
+	})
+
+	t.Run("Default priority is zero", func(t *testing.T) {
+		uc, _, opRepo := setup()
+		opRepo.On("CreateBatch", mock.Anything, mock.MatchedBy(func(ops []*orchestrator.Operation) bool {
+			return len(ops) == 1 && ops[0].Priority == 0
+		})).Return(nil)
+
+		_, err := uc.CalculateExpression(setupTestContext(), uuid.New(), "2+3", orchestrator.CalculationSourceWeb)
+
+		require.NoError(t, err)
+		opRepo.AssertExpectations(t)
+	})
+
+	for _, priority := range []int{-1, orchestrator.MaxPriority + 1} {
+		t.Run(fmt.Sprintf("Priority %d rejected", priority), func(t *testing.T) {
+			uc, calcRepo, opRepo := setup()
+
+			ctx := orchestrator.WithPriority(setupTestContext(), priority)
+			result, err := uc.CalculateExpression(ctx, uuid.New(), "2+3", orchestrator.CalculationSourceWeb)
+
+			require.ErrorIs(t, err, domainerrors.ErrInvalidPriority)
+			assert.Nil(t, result)
+			calcRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			opRepo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestCalculateExpressionResultCache(t *testing.T) {
+	t.Run("Cached expression saved as completed", func(t *testing.T) {
+		calcRepo := new(MockCalculationRepository)
+		opRepo := new(MockOperationRepository)
+		parser := new(MockExpressionParser)
+		resultCache := cache.NewResultCache(10)
+		require.NoError(t, resultCache.Set(context.Background(), "2+2*2", "6"))
+
+		uc := calculation.NewUseCase(calcRepo, opRepo, parser)
+		uc.SetResultCache(resultCache)
+
+		parser.On("Validate", mock.Anything, "2 + 2 * 2").Return(nil)
+		calcRepo.On("Create", mock.Anything, mock.MatchedBy(func(calc *orchestrator.Calculation) bool {
+			return calc.Status == orchestrator.CalculationStatusCompleted && calc.Result == "6" && calc.Expression == "2 + 2 * 2"
+		})).Return(&orchestrator.Calculation{
+			ID:         uuid.New(),
+			Expression: "2 + 2 * 2",
+			Result:     "6",
+			Status:     orchestrator.CalculationStatusCompleted,
+		}, nil)
+
+		result, err := uc.CalculateExpression(setupTestContext(), uuid.New(), "2 + 2 * 2", orchestrator.CalculationSourceWeb)
+
+		require.NoError(t, err)
+		assert.Equal(t, orchestrator.CalculationStatusCompleted, result.Status)
+		assert.Equal(t, "6", result.Result)
+		parser.AssertNotCalled(t, "Parse", mock.Anything, mock.Anything)
+		opRepo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Completed calculation result cached", func(t *testing.T) {
+		calcRepo := new(MockCalculationRepository)
+		opRepo := new(MockOperationRepository)
+		resultCache := cache.NewResultCache(10)
+		calculationID := uuid.New()
+
+		uc := calculation.NewUseCase(calcRepo, opRepo, new(MockExpressionParser))
+		uc.SetResultCache(resultCache)
+
+		calcRepo.On("FindByID", mock.Anything, calculationID).Return(&orchestrator.Calculation{
+			ID:         calculationID,
+			Expression: "1 + 2",
+			Status:     orchestrator.CalculationStatusInProgress,
+		}, nil)
+		opRepo.On("FindByCalculationID", mock.Anything, calculationID).Return([]*orchestrator.Operation{
+			{ID: uuid.New(), CalculationID: calculationID, Result: "3", Status: orchestrator.OperationStatusCompleted},
+		}, nil)
+		calcRepo.On("UpdateStatus", mock.Anything, calculationID, orchestrator.CalculationStatusCompleted, "3", "").Return(nil)
+
+		require.NoError(t, uc.UpdateCalculationStatus(setupTestContext(), calculationID))
+
+		result, found, err := resultCache.Get(context.Background(), "1+2")
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, "3", result)
+	})
+
+	t.Run("Redis outage falls back to agents", func(t *testing.T) {
+		calcRepo := new(MockCalculationRepository)
+		opRepo := new(MockOperationRepository)
+		parser := new(MockExpressionParser)
+
+		server := miniredis.RunT(t)
+		client := goredis.NewClient(&goredis.Options{Addr: server.Addr(), MaxRetries: -1})
+		t.Cleanup(func() { _ = client.Close() })
+		resultCache := rediscache.NewResultCache(client, "calc:result:", time.Hour)
+		require.NoError(t, resultCache.Set(context.Background(), "1+2", "3"))
+		server.Close()
+
+		uc := calculation.NewUseCase(calcRepo, opRepo, parser)
+		uc.SetResultCache(resultCache)
+
+		calculationID := uuid.New()
+		operations := []*orchestrator.Operation{
+			{ID: uuid.New(), OperationType: orchestrator.OperationTypeAddition, Operand1: "1", Operand2: "2", Status: orchestrator.OperationStatusPending},
+		}
+		parser.On("Validate", mock.Anything, "1+2").Return(nil)
+		calcRepo.On("Create", mock.Anything, mock.MatchedBy(func(calc *orchestrator.Calculation) bool {
+			return calc.Status == orchestrator.CalculationStatusPending
+		})).Return(&orchestrator.Calculation{ID: calculationID, Expression: "1+2", Status: orchestrator.CalculationStatusPending}, nil)
+		parser.On("Parse", mock.Anything, "1+2").Return(operations, nil)
+		parser.On("SetCalculationID", operations, calculationID).Return()
+		opRepo.On("CreateBatch", mock.Anything, operations).Return(nil)
+		calcRepo.On("UpdateStatus", mock.Anything, calculationID, orchestrator.CalculationStatusInProgress, "", "").Return(nil)
+		calcRepo.On("FindByID", mock.Anything, calculationID).Return(&orchestrator.Calculation{
+			ID:         calculationID,
+			Expression: "1+2",
+			Status:     orchestrator.CalculationStatusInProgress,
+		}, nil)
+
+		result, err := uc.CalculateExpression(setupTestContext(), uuid.New(), "1+2", orchestrator.CalculationSourceWeb)
+
+		require.NoError(t, err)
+		assert.Equal(t, orchestrator.CalculationStatusInProgress, result.Status)
+		opRepo.AssertCalled(t, "CreateBatch", mock.Anything, operations)
+	})
+}
+
+func TestCalculateExpressionParsingTimeout(t *testing.T) {
+	ctx := setupTestContext()
+	calcRepo := new(MockCalculationRepository)
+	opRepo := new(MockOperationRepository)
+	parser := new(MockExpressionParser)
+
+	calcID := uuid.New()
+	parseErr := fmt.Errorf("parse: %w", context.DeadlineExceeded)
+
+	parser.On("Validate", mock.Anything, "1+2").Return(nil)
+	calcRepo.On("Create", mock.Anything, mock.Anything).Return(&orchestrator.Calculation{
+		ID:     calcID,
+		Status: orchestrator.CalculationStatusPending,
+	}, nil)
+	parser.On("Parse", mock.Anything, "1+2").Run(func(args mock.Arguments) {
+		// Имитируем разбор, который не укладывается в отведенное время
+		<-args.Get(0).(context.Context).Done()
+	}).Return(nil, parseErr)
+	calcRepo.On("UpdateStatus", mock.MatchedBy(func(ctx context.Context) bool {
+		return ctx.Err() == nil
+	}), calcID, orchestrator.CalculationStatusError, "", "Expression parsing timed out").Return(nil)
+	calcRepo.On("FindByID", mock.Anything, calcID).Return(&orchestrator.Calculation{
+		ID:           calcID,
+		Status:       orchestrator.CalculationStatusError,
+		ErrorMessage: "Expression parsing timed out",
+	}, nil)
+
+	uc := calculation.NewUseCase(calcRepo, opRepo, parser)
+	uc.SetParsingTimeout(20 * time.Millisecond)
+
+	start := time.Now()
+	result, err := uc.CalculateExpression(ctx, uuid.New(), "1+2", orchestrator.CalculationSourceWeb)
+	elapsed := time.Since(start)
+
+	require.NoError(t, err)
+	assert.Equal(t, orchestrator.CalculationStatusError, result.Status)
+	assert.Less(t, elapsed, time.Second)
+
+	calcRepo.AssertExpectations(t)
+	opRepo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+	parser.AssertExpectations(t)
+}
+
+func TestCalculateExpressionValidationTimeout(t *testing.T) {
+	ctx := setupTestContext()
+	calcRepo := new(MockCalculationRepository)
+	parser := new(MockExpressionParser)
+
+	parser.On("Validate", mock.Anything, "1+2").Run(func(args mock.Arguments) {
+		<-args.Get(0).(context.Context).Done()
+	}).Return(fmt.Errorf("validate: %w", context.DeadlineExceeded))
+
+	uc := calculation.NewUseCase(calcRepo, new(MockOperationRepository), parser)
+	uc.SetParsingTimeout(20 * time.Millisecond)
+
+	result, err := uc.CalculateExpression(ctx, uuid.New(), "1+2", orchestrator.CalculationSourceWeb)
+
+	assert.ErrorIs(t, err, domainerrors.ErrParseTimeout)
+	assert.Nil(t, result)
+	calcRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestGetCalculation(t *testing.T) {
+	calculationID := uuid.New()
+	userID := uuid.New()
+	otherUserID := uuid.New()
+
+	testCases := []struct {
+		name          string
+		calculationID uuid.UUID
+		userID        uuid.UUID
+		setupMocks    func(*MockCalculationRepository, *MockOperationRepository)
+		expectedError error
+	}{
 		{
-			name:          "Success case - error",
+			name:          "Success case",
 			calculationID: calculationID,
+			userID:        userID,
 			setupMocks: func(calcRepo *MockCalculationRepository, opRepo *MockOperationRepository) {
 				calcRepo.On("FindByID", mock.Anything, calculationID).Return(&orchestrator.Calculation{
-					ID: calculationID,
+					ID:         calculationID,
+					UserID:     userID,
+					Expression: "1+2",
+					Result:     "3",
+					Status:     orchestrator.CalculationStatusCompleted,
 				}, nil)
 
 				operations := []*orchestrator.Operation{
 					{
 						ID:            uuid.New(),
 						CalculationID: calculationID,
-						Status:        orchestrator.OperationStatusError,
-						ErrorMessage:  "calculation error",
+						OperationType: orchestrator.OperationTypeAddition,
+						Operand1:      "1",
+						Operand2:      "2",
+						Result:        "3",
+						Status:        orchestrator.OperationStatusCompleted,
 					},
 				}
 
 				opRepo.On("FindByCalculationID", mock.Anything, calculationID).Return(operations, nil)
-
-				calcRepo.On("UpdateStatus", mock.Anything, calculationID,
-					orchestrator.CalculationStatusError, "", "calculation error").Return(nil)
 			},
 			expectedError: nil,
 		},
 		{
-			name:          "Invalid calculation ID",
-			calculationID: uuid.Nil,
-			setupMocks:    func(calcRepo *MockCalculationRepository, opRepo *MockOperationRepository) {},
-			expectedError: domainerrors.ErrSpecificCalcNotFound,
-		},
-		{
-			name:          "No operations",
+			name:          "Calculation not found",
 			calculationID: calculationID,
+			userID:        userID,
 			setupMocks: func(calcRepo *MockCalculationRepository, opRepo *MockOperationRepository) {
-				calcRepo.On("FindByID", mock.Anything, calculationID).Return(&orchestrator.Calculation{
-					ID: calculationID,
-				}, nil)
-
-				opRepo.On("FindByCalculationID", mock.Anything, calculationID).Return([]*orchestrator.Operation{}, nil)
-
-				calcRepo.On("UpdateStatus", mock.Anything, calculationID,
-					orchestrator.CalculationStatusError, "", "No operations found").Return(nil)
+				calcRepo.On("FindByID", mock.Anything, calculationID).Return(nil, nil)
 			},
-			expectedError: nil,
+			expectedError: domainerrors.ErrCalculationNotFound,
 		},
 		{
-			name:          "Calculation not found",
+			name:          "Repository error",
 			calculationID: calculationID,
+			userID:        userID,
 			setupMocks: func(calcRepo *MockCalculationRepository, opRepo *MockOperationRepository) {
-				calcRepo.On("FindByID", mock.Anything, calculationID).Return(nil, domainerrors.ErrCalculationNotFound)
+				calcRepo.On("FindByID", mock.Anything, calculationID).Return(nil, errors.New("database error"))
 			},
-			expectedError: domainerrors.ErrCalculationNotFound,
+			expectedError: domainerrors.ErrInternalError,
 		},
 		{
-			name:          "Error fetching operations",
+			name:          "Unauthorized access",
 			calculationID: calculationID,
+			userID:        otherUserID,
 			setupMocks: func(calcRepo *MockCalculationRepository, opRepo *MockOperationRepository) {
 				calcRepo.On("FindByID", mock.Anything, calculationID).Return(&orchestrator.Calculation{
-					ID: calculationID,
+					ID:     calculationID,
+					UserID: userID,
 				}, nil)
-
-				opRepo.On("FindByCalculationID", mock.Anything, calculationID).Return(nil, errors.New("database error"))
 			},
-			expectedError: errors.New("failed to fetch operations"),
+			expectedError: domainerrors.ErrUnauthorizedAccess,
 		},
 	}
 
@@ -716,15 +1761,19 @@ func TestUpdateCalculationStatus(t *testing.T) {
 
 			uc := calculation.NewUseCase(calcRepo, opRepo, parser)
 
-			err := uc.UpdateCalculationStatus(ctx, tc.calculationID)
+			result, err := uc.GetCalculation(ctx, tc.calculationID, tc.userID)
 
 			if tc.expectedError != nil {
 				assert.Error(t, err)
 				assert.True(t, errors.Is(err, tc.expectedError) ||
 					strings.Contains(err.Error(), tc.expectedError.Error()),
 					"expected error containing %v, got %v", tc.expectedError, err)
+				assert.Nil(t, result)
 			} else {
 				assert.NoError(t, err)
+				assert.NotNil(t, result)
+				assert.Equal(t, tc.calculationID, result.ID)
+				assert.Equal(t, tc.userID, result.UserID)
 			}
 
 			calcRepo.AssertExpectations(t)
@@ -732,3 +1781,1567 @@ func TestUpdateCalculationStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestGetCalculationStaleRecompute(t *testing.T) {
+	calculationID := uuid.New()
+	userID := uuid.New()
+
+	operations := []*orchestrator.Operation{
+		{
+			ID:            uuid.New(),
+			CalculationID: calculationID,
+			OperationType: orchestrator.OperationTypeAddition,
+			Result:        "3",
+			Status:        orchestrator.OperationStatusCompleted,
+		},
+	}
+
+	calcWithStatus := func(status orchestrator.CalculationStatus, updatedAt time.Time) *orchestrator.Calculation {
+		return &orchestrator.Calculation{
+			ID:         calculationID,
+			UserID:     userID,
+			Expression: "1+2",
+			Status:     status,
+			UpdatedAt:  updatedAt,
+		}
+	}
+
+	testCases := []struct {
+		name           string
+		threshold      time.Duration
+		setupMocks     func(*MockCalculationRepository, *MockOperationRepository)
+		expectedStatus orchestrator.CalculationStatus
+	}{
+		{
+			name:      "Stale in-progress calculation is recomputed when enabled",
+			threshold: time.Minute,
+			setupMocks: func(calcRepo *MockCalculationRepository, opRepo *MockOperationRepository) {
+				stale := calcWithStatus(orchestrator.CalculationStatusInProgress, time.Now().Add(-time.Hour))
+				calcRepo.On("FindByID", mock.Anything, calculationID).Return(stale, nil).Twice()
+				calcRepo.On("UpdateStatus", mock.Anything, calculationID,
+					orchestrator.CalculationStatusCompleted, "3", "").Return(nil).Once()
+				calcRepo.On("FindByID", mock.Anything, calculationID).
+					Return(calcWithStatus(orchestrator.CalculationStatusCompleted, time.Now()), nil).Once()
+				opRepo.On("FindByCalculationID", mock.Anything, calculationID).Return(operations, nil)
+			},
+			expectedStatus: orchestrator.CalculationStatusCompleted,
+		},
+		{
+			name:      "Fresh in-progress calculation is not recomputed",
+			threshold: time.Minute,
+			setupMocks: func(calcRepo *MockCalculationRepository, opRepo *MockOperationRepository) {
+				calcRepo.On("FindByID", mock.Anything, calculationID).
+					Return(calcWithStatus(orchestrator.CalculationStatusInProgress, time.Now()), nil).Once()
+				opRepo.On("FindByCalculationID", mock.Anything, calculationID).Return(operations, nil).Once()
+			},
+			expectedStatus: orchestrator.CalculationStatusInProgress,
+		},
+		{
+			name:      "Stale calculation is not recomputed when disabled",
+			threshold: 0,
+			setupMocks: func(calcRepo *MockCalculationRepository, opRepo *MockOperationRepository) {
+				calcRepo.On("FindByID", mock.Anything, calculationID).
+					Return(calcWithStatus(orchestrator.CalculationStatusInProgress, time.Now().Add(-time.Hour)), nil).Once()
+				opRepo.On("FindByCalculationID", mock.Anything, calculationID).Return(operations, nil).Once()
+			},
+			expectedStatus: orchestrator.CalculationStatusInProgress,
+		},
+		{
+			name:      "Recompute failure returns the stored calculation",
+			threshold: time.Minute,
+			setupMocks: func(calcRepo *MockCalculationRepository, opRepo *MockOperationRepository) {
+				stale := calcWithStatus(orchestrator.CalculationStatusInProgress, time.Now().Add(-time.Hour))
+				calcRepo.On("FindByID", mock.Anything, calculationID).Return(stale, nil).Twice()
+				calcRepo.On("UpdateStatus", mock.Anything, calculationID,
+					orchestrator.CalculationStatusCompleted, "3", "").Return(errors.New("database error"))
+				opRepo.On("FindByCalculationID", mock.Anything, calculationID).Return(operations, nil)
+			},
+			expectedStatus: orchestrator.CalculationStatusInProgress,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := setupTestContext()
+
+			calcRepo := new(MockCalculationRepository)
+			opRepo := new(MockOperationRepository)
+			parser := new(MockExpressionParser)
+
+			tc.setupMocks(calcRepo, opRepo)
+
+			uc := calculation.NewUseCase(calcRepo, opRepo, parser)
+			uc.SetStaleRecompute(tc.threshold)
+
+			result, err := uc.GetCalculation(ctx, calculationID, userID)
+
+			assert.NoError(t, err)
+			assert.NotNil(t, result)
+			assert.Equal(t, tc.expectedStatus, result.Status)
+
+			calcRepo.AssertExpectations(t)
+			opRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListCalculations(t *testing.T) {
+	userID := uuid.New()
+	defaultFilter := orchestrator.CalculationFilter{Limit: 20}
+
+	testCases := []struct {
+		name           string
+		userID         uuid.UUID
+		filter         orchestrator.CalculationFilter
+		setupMocks     func(*MockCalculationRepository)
+		expectedCount  int
+		expectedTotal  int
+		expectedLimit  int
+		expectedOffset int
+		expectedError  error
+	}{
+		{
+			name:   "Success case with calculations",
+			userID: userID,
+			setupMocks: func(calcRepo *MockCalculationRepository) {
+				calculations := []*orchestrator.Calculation{
+					{
+						ID:         uuid.New(),
+						UserID:     userID,
+						Expression: "1+2",
+						Result:     "3",
+						Status:     orchestrator.CalculationStatusCompleted,
+					},
+					{
+						ID:         uuid.New(),
+						UserID:     userID,
+						Expression: "3*4",
+						Result:     "12",
+						Status:     orchestrator.CalculationStatusCompleted,
+					},
+				}
+
+				calcRepo.On("FindByUserID", mock.Anything, userID, defaultFilter).Return(calculations, 2, nil)
+			},
+			expectedCount: 2,
+			expectedTotal: 2,
+			expectedLimit: 20,
+			expectedError: nil,
+		},
+		{
+			name:   "Success case no calculations",
+			userID: userID,
+			setupMocks: func(calcRepo *MockCalculationRepository) {
+				calcRepo.On("FindByUserID", mock.Anything, userID, defaultFilter).Return([]*orchestrator.Calculation{}, 0, nil)
+			},
+			expectedCount: 0,
+			expectedLimit: 20,
+			expectedError: nil,
+		},
+		{
+			name:   "Page with status filter",
+			userID: userID,
+			filter: orchestrator.CalculationFilter{Limit: 1, Offset: 1, Status: orchestrator.CalculationStatusError},
+			setupMocks: func(calcRepo *MockCalculationRepository) {
+				filter := orchestrator.CalculationFilter{Limit: 1, Offset: 1, Status: orchestrator.CalculationStatusError}
+				calculations := []*orchestrator.Calculation{
+					{ID: uuid.New(), UserID: userID, Status: orchestrator.CalculationStatusError},
+				}
+				calcRepo.On("FindByUserID", mock.Anything, userID, filter).Return(calculations, 5, nil)
+			},
+			expectedCount:  1,
+			expectedTotal:  5,
+			expectedLimit:  1,
+			expectedOffset: 1,
+			expectedError:  nil,
+		},
+		{
+			name:          "Negative limit",
+			userID:        userID,
+			filter:        orchestrator.CalculationFilter{Limit: -1},
+			setupMocks:    func(calcRepo *MockCalculationRepository) {},
+			expectedError: domainerrors.ErrInvalidPagination,
+		},
+		{
+			name:          "Limit above maximum",
+			userID:        userID,
+			filter:        orchestrator.CalculationFilter{Limit: 101},
+			setupMocks:    func(calcRepo *MockCalculationRepository) {},
+			expectedError: domainerrors.ErrInvalidPagination,
+		},
+		{
+			name:          "Negative offset",
+			userID:        userID,
+			filter:        orchestrator.CalculationFilter{Limit: 10, Offset: -5},
+			setupMocks:    func(calcRepo *MockCalculationRepository) {},
+			expectedError: domainerrors.ErrInvalidPagination,
+		},
+		{
+			name:          "Unknown status filter",
+			userID:        userID,
+			filter:        orchestrator.CalculationFilter{Status: "DONE"},
+			setupMocks:    func(calcRepo *MockCalculationRepository) {},
+			expectedError: domainerrors.ErrInvalidStatusFilter,
+		},
+		{
+			name:   "Invalid user ID",
+			userID: uuid.Nil,
+			setupMocks: func(calcRepo *MockCalculationRepository) {
+			},
+			expectedCount: 0,
+			expectedError: domainerrors.ErrInvalidUserID,
+		},
+		{
+			name:   "Repository error",
+			userID: userID,
+			setupMocks: func(calcRepo *MockCalculationRepository) {
+				calcRepo.On("FindByUserID", mock.Anything, userID, defaultFilter).Return(nil, 0, errors.New("database error"))
+			},
+			expectedCount: 0,
+			expectedError: domainerrors.ErrInternalError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := setupTestContext()
+
+			calcRepo := new(MockCalculationRepository)
+			opRepo := new(MockOperationRepository)
+			parser := new(MockExpressionParser)
+
+			tc.setupMocks(calcRepo)
+
+			uc := calculation.NewUseCase(calcRepo, opRepo, parser)
+
+			result, err := uc.ListCalculations(ctx, tc.userID, tc.filter)
+
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.True(t, errors.Is(err, tc.expectedError) ||
+					strings.Contains(err.Error(), tc.expectedError.Error()),
+					"expected error containing %v, got %v", tc.expectedError, err)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, result)
+				assert.Len(t, result.Calculations, tc.expectedCount)
+				assert.Equal(t, tc.expectedTotal, result.Total)
+				assert.Equal(t, tc.expectedLimit, result.Limit)
+				assert.Equal(t, tc.expectedOffset, result.Offset)
+			}
+
+			calcRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListCalculationsResultCap(t *testing.T) {
+	userID := uuid.New()
+
+	newCalculations := func(n int) []*orchestrator.Calculation {
+		calculations := make([]*orchestrator.Calculation, n)
+		for i := range calculations {
+			calculations[i] = &orchestrator.Calculation{ID: uuid.New(), UserID: userID}
+		}
+		return calculations
+	}
+
+	t.Run("Requested limit reduced to cap", func(t *testing.T) {
+		calcRepo := new(MockCalculationRepository)
+		uc := calculation.NewUseCase(calcRepo, new(MockOperationRepository), new(MockExpressionParser))
+		uc.SetListResultCap(5)
+
+		capped := orchestrator.CalculationFilter{Limit: 5, Offset: 10}
+		calcRepo.On("FindByUserID", mock.Anything, userID, capped).Return(newCalculations(5), 50, nil)
+
+		page, err := uc.ListCalculations(setupTestContext(), userID, orchestrator.CalculationFilter{Limit: 100, Offset: 10})
+
+		require.NoError(t, err)
+		assert.Len(t, page.Calculations, 5)
+		assert.Equal(t, 5, page.Limit)
+		assert.Equal(t, 50, page.Total)
+		calcRepo.AssertExpectations(t)
+	})
+
+	t.Run("Oversized repository result truncated", func(t *testing.T) {
+		calcRepo := new(MockCalculationRepository)
+		uc := calculation.NewUseCase(calcRepo, new(MockOperationRepository), new(MockExpressionParser))
+		uc.SetListResultCap(3)
+
+		calcRepo.On("FindByUserID", mock.Anything, userID, mock.Anything).Return(newCalculations(10), 10, nil)
+
+		page, err := uc.ListCalculations(setupTestContext(), userID, orchestrator.CalculationFilter{Limit: 50})
+
+		require.NoError(t, err)
+		assert.Len(t, page.Calculations, 3)
+	})
+
+	t.Run("Limit below cap unchanged", func(t *testing.T) {
+		calcRepo := new(MockCalculationRepository)
+		uc := calculation.NewUseCase(calcRepo, new(MockOperationRepository), new(MockExpressionParser))
+		uc.SetListResultCap(10)
+
+		filter := orchestrator.CalculationFilter{Limit: 2}
+		calcRepo.On("FindByUserID", mock.Anything, userID, filter).Return(newCalculations(2), 2, nil)
+
+		page, err := uc.ListCalculations(setupTestContext(), userID, filter)
+
+		require.NoError(t, err)
+		assert.Len(t, page.Calculations, 2)
+		assert.Equal(t, 2, page.Limit)
+	})
+}
+
+func TestUpdateCalculationStatus(t *testing.T) {
+	calculationID := uuid.New()
+
+	testCases := []struct {
+		name          string
+		calculationID uuid.UUID
+		setupMocks    func(*MockCalculationRepository, *MockOperationRepository)
+		expectedError error
+	}{
+		{
+			name:          "Success case - completed",
+			calculationID: calculationID,
+			setupMocks: func(calcRepo *MockCalculationRepository, opRepo *MockOperationRepository) {
+				calcRepo.On("FindByID", mock.Anything, calculationID).Return(&orchestrator.Calculation{
+					ID: calculationID,
+				}, nil)
+
+				operations := []*orchestrator.Operation{
+					{
+						ID:            uuid.New(),
+						CalculationID: calculationID,
+						Result:        "3",
+						Status:        orchestrator.OperationStatusCompleted,
+					},
+				}
+
+				opRepo.On("FindByCalculationID", mock.Anything, calculationID).Return(operations, nil)
+
+				calcRepo.On("UpdateStatus", mock.Anything, calculationID,
+					orchestrator.CalculationStatusCompleted, "3", "").Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:          "Success case - in progress",
+			calculationID: calculationID,
+			setupMocks: func(calcRepo *MockCalculationRepository, opRepo *MockOperationRepository) {
+				calcRepo.On("FindByID", mock.Anything, calculationID).Return(&orchestrator.Calculation{
+					ID: calculationID,
+				}, nil)
+
+				operations := []*orchestrator.Operation{
+					{
+						ID:            uuid.New(),
+						CalculationID: calculationID,
+						Status:        orchestrator.OperationStatusInProgress,
+					},
+				}
+
+				opRepo.On("FindByCalculationID", mock.Anything, calculationID).Return(operations, nil)
+
+				calcRepo.On("UpdateStatus", mock.Anything, calculationID,
+					orchestrator.CalculationStatusInProgress, "", "").Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:          "Success case - error",
+			calculationID: calculationID,
+			setupMocks: func(calcRepo *MockCalculationRepository, opRepo *MockOperationRepository) {
+				calcRepo.On("FindByID", mock.Anything, calculationID).Return(&orchestrator.Calculation{
+					ID: calculationID,
+				}, nil)
+
+				operations := []*orchestrator.Operation{
+					{
+						ID:            uuid.New(),
+						CalculationID: calculationID,
+						Status:        orchestrator.OperationStatusError,
+						ErrorMessage:  "calculation error",
+					},
+				}
+
+				opRepo.On("FindByCalculationID", mock.Anything, calculationID).Return(operations, nil)
+
+				calcRepo.On("UpdateStatus", mock.Anything, calculationID,
+					orchestrator.CalculationStatusError, "", "calculation error").Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:          "Success case - operation timeout",
+			calculationID: calculationID,
+			setupMocks: func(calcRepo *MockCalculationRepository, opRepo *MockOperationRepository) {
+				calcRepo.On("FindByID", mock.Anything, calculationID).Return(&orchestrator.Calculation{
+					ID: calculationID,
+				}, nil)
+
+				operations := []*orchestrator.Operation{
+					{
+						ID:            uuid.New(),
+						CalculationID: calculationID,
+						Result:        "3",
+						Status:        orchestrator.OperationStatusCompleted,
+					},
+					{
+						ID:            uuid.New(),
+						CalculationID: calculationID,
+						Status:        orchestrator.OperationStatusError,
+						ErrorMessage:  domainerrors.ErrOperationTimeout.Error(),
+					},
+				}
+
+				opRepo.On("FindByCalculationID", mock.Anything, calculationID).Return(operations, nil)
+
+				calcRepo.On("UpdateStatus", mock.Anything, calculationID,
+					orchestrator.CalculationStatusError, "", "timeout").Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:          "Invalid calculation ID",
+			calculationID: uuid.Nil,
+			setupMocks:    func(calcRepo *MockCalculationRepository, opRepo *MockOperationRepository) {},
+			expectedError: domainerrors.ErrSpecificCalcNotFound,
+		},
+		{
+			name:          "No operations",
+			calculationID: calculationID,
+			setupMocks: func(calcRepo *MockCalculationRepository, opRepo *MockOperationRepository) {
+				calcRepo.On("FindByID", mock.Anything, calculationID).Return(&orchestrator.Calculation{
+					ID: calculationID,
+				}, nil)
+
+				opRepo.On("FindByCalculationID", mock.Anything, calculationID).Return([]*orchestrator.Operation{}, nil)
+
+				calcRepo.On("UpdateStatus", mock.Anything, calculationID,
+					orchestrator.CalculationStatusError, "", "No operations found").Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:          "Cancelled calculation is left untouched",
+			calculationID: calculationID,
+			setupMocks: func(calcRepo *MockCalculationRepository, opRepo *MockOperationRepository) {
+				calcRepo.On("FindByID", mock.Anything, calculationID).Return(&orchestrator.Calculation{
+					ID:     calculationID,
+					Status: orchestrator.CalculationStatusCancelled,
+				}, nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:          "Calculation not found",
+			calculationID: calculationID,
+			setupMocks: func(calcRepo *MockCalculationRepository, opRepo *MockOperationRepository) {
+				calcRepo.On("FindByID", mock.Anything, calculationID).Return(nil, domainerrors.ErrCalculationNotFound)
+			},
+			expectedError: domainerrors.ErrCalculationNotFound,
+		},
+		{
+			name:          "Error fetching operations",
+			calculationID: calculationID,
+			setupMocks: func(calcRepo *MockCalculationRepository, opRepo *MockOperationRepository) {
+				calcRepo.On("FindByID", mock.Anything, calculationID).Return(&orchestrator.Calculation{
+					ID: calculationID,
+				}, nil)
+
+				opRepo.On("FindByCalculationID", mock.Anything, calculationID).Return(nil, errors.New("database error"))
+			},
+			expectedError: errors.New("failed to fetch operations"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := setupTestContext()
+
+			calcRepo := new(MockCalculationRepository)
+			opRepo := new(MockOperationRepository)
+			parser := new(MockExpressionParser)
+
+			tc.setupMocks(calcRepo, opRepo)
+
+			uc := calculation.NewUseCase(calcRepo, opRepo, parser)
+
+			err := uc.UpdateCalculationStatus(ctx, tc.calculationID)
+
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.True(t, errors.Is(err, tc.expectedError) ||
+					strings.Contains(err.Error(), tc.expectedError.Error()),
+					"expected error containing %v, got %v", tc.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			calcRepo.AssertExpectations(t)
+			opRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSubscribeCalculation(t *testing.T) {
+	calculationID := uuid.New()
+	userID := uuid.New()
+
+	t.Run("Status change is delivered to subscriber", func(t *testing.T) {
+		ctx := setupTestContext()
+
+		calcRepo := new(MockCalculationRepository)
+		opRepo := new(MockOperationRepository)
+
+		calcRepo.On("FindByID", mock.Anything, calculationID).Return(&orchestrator.Calculation{
+			ID:     calculationID,
+			UserID: userID,
+			Status: orchestrator.CalculationStatusInProgress,
+		}, nil)
+		opRepo.On("FindByCalculationID", mock.Anything, calculationID).Return([]*orchestrator.Operation{
+			{ID: uuid.New(), CalculationID: calculationID, Result: "7", Status: orchestrator.OperationStatusCompleted},
+		}, nil)
+		calcRepo.On("UpdateStatus", mock.Anything, calculationID,
+			orchestrator.CalculationStatusCompleted, "7", "").Return(nil)
+
+		uc := calculation.NewUseCase(calcRepo, opRepo, new(MockExpressionParser))
+
+		subCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		events, err := uc.SubscribeCalculation(subCtx, calculationID, userID)
+		require.NoError(t, err)
+
+		initial := receiveEvent(t, events)
+		assert.Equal(t, orchestrator.CalculationStatusInProgress, initial.Status)
+
+		require.NoError(t, uc.UpdateCalculationStatus(ctx, calculationID))
+
+		final := receiveEvent(t, events)
+		assert.Equal(t, calculationID, final.CalculationID)
+		assert.Equal(t, orchestrator.CalculationStatusCompleted, final.Status)
+		assert.Equal(t, "7", final.Result)
+
+		select {
+		case _, ok := <-events:
+			assert.False(t, ok, "stream must close after final status")
+		case <-time.After(time.Second):
+			t.Fatal("stream was not closed after final status")
+		}
+	})
+
+	t.Run("Cancelling context closes subscription", func(t *testing.T) {
+		calcRepo := new(MockCalculationRepository)
+		calcRepo.On("FindByID", mock.Anything, calculationID).Return(&orchestrator.Calculation{
+			ID:     calculationID,
+			UserID: userID,
+			Status: orchestrator.CalculationStatusPending,
+		}, nil)
+
+		uc := calculation.NewUseCase(calcRepo, new(MockOperationRepository), new(MockExpressionParser))
+
+		subCtx, cancel := context.WithCancel(setupTestContext())
+		events, err := uc.SubscribeCalculation(subCtx, calculationID, userID)
+		require.NoError(t, err)
+
+		receiveEvent(t, events)
+		cancel()
+
+		select {
+		case _, ok := <-events:
+			assert.False(t, ok)
+		case <-time.After(time.Second):
+			t.Fatal("stream was not closed after context cancellation")
+		}
+	})
+
+	errorCases := []struct {
+		name          string
+		calculation   *orchestrator.Calculation
+		expectedError error
+	}{
+		{name: "Calculation not found", calculation: nil, expectedError: domainerrors.ErrCalculationNotFound},
+		{
+			name:          "Another user's calculation",
+			calculation:   &orchestrator.Calculation{ID: calculationID, UserID: uuid.New()},
+			expectedError: domainerrors.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			calcRepo := new(MockCalculationRepository)
+			calcRepo.On("FindByID", mock.Anything, calculationID).Return(tc.calculation, nil)
+
+			uc := calculation.NewUseCase(calcRepo, new(MockOperationRepository), new(MockExpressionParser))
+
+			events, err := uc.SubscribeCalculation(setupTestContext(), calculationID, userID)
+			assert.ErrorIs(t, err, tc.expectedError)
+			assert.Nil(t, events)
+		})
+	}
+}
+
+func receiveEvent(t *testing.T, events <-chan orchestrator.CalculationEvent) orchestrator.CalculationEvent {
+	t.Helper()
+
+	select {
+	case event, ok := <-events:
+		require.True(t, ok, "stream closed unexpectedly")
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("calculation event was not received")
+		return orchestrator.CalculationEvent{}
+	}
+}
+
+type MockOperationCancellation struct {
+	mock.Mock
+}
+
+func (m *MockOperationCancellation) CancelOperations(operationIDs ...uuid.UUID) {
+	m.Called(operationIDs)
+}
+
+func (m *MockOperationCancellation) IsCancelled(operationID uuid.UUID) bool {
+	args := m.Called(operationID)
+	return args.Bool(0)
+}
+
+func TestUpdateCalculationStatusDisplayResult(t *testing.T) {
+	const exact = "0.1250000000000000000001"
+
+	testCases := []struct {
+		name     string
+		rounding orchestrator.Rounding
+		display  string
+	}{
+		{
+			name:     "Display result honors precision",
+			rounding: orchestrator.Rounding{Precision: 2, Mode: orchestrator.RoundingHalfEven},
+			display:  "0.13",
+		},
+		{
+			name:     "Display result honors rounding mode",
+			rounding: orchestrator.Rounding{Precision: 3, Mode: orchestrator.RoundingDown},
+			display:  "0.125",
+		},
+		{
+			name:     "Display result disabled",
+			rounding: orchestrator.NoRounding,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			calculationID := uuid.New()
+			calcRepo := new(MockCalculationRepository)
+			opRepo := new(MockOperationRepository)
+
+			calcRepo.On("FindByID", mock.Anything, calculationID).Return(&orchestrator.Calculation{
+				ID:     calculationID,
+				Status: orchestrator.CalculationStatusInProgress,
+			}, nil)
+			opRepo.On("FindByCalculationID", mock.Anything, calculationID).Return([]*orchestrator.Operation{
+				{ID: uuid.New(), CalculationID: calculationID, Result: exact, Status: orchestrator.OperationStatusCompleted},
+			}, nil)
+			calcRepo.On("UpdateStatus", mock.Anything, calculationID,
+				orchestrator.CalculationStatusCompleted, exact, "").Return(nil).Once()
+			if tc.display != "" {
+				calcRepo.On("UpdateDisplayResult", mock.Anything, calculationID, tc.display).Return(nil).Once()
+			}
+
+			uc := calculation.NewUseCase(calcRepo, opRepo, new(MockExpressionParser))
+			uc.SetDisplayRounding(tc.rounding)
+
+			require.NoError(t, uc.UpdateCalculationStatus(setupTestContext(), calculationID))
+			calcRepo.AssertExpectations(t)
+			if tc.display == "" {
+				calcRepo.AssertNotCalled(t, "UpdateDisplayResult", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestUpdateCalculationStatusResultRounding(t *testing.T) {
+	twoPlaces := orchestrator.Rounding{Precision: 2, Mode: orchestrator.RoundingHalfEven}
+
+	testCases := []struct {
+		name       string
+		rounding   orchestrator.Rounding
+		operations []*orchestrator.Operation
+		result     string
+	}{
+		{
+			// (1/3)*3: при округлении каждой операции получилось бы 0.33*3 = 0.99
+			name:     "Only final result is rounded",
+			rounding: twoPlaces,
+			operations: []*orchestrator.Operation{
+				{OperationType: orchestrator.OperationTypeDivision, Result: "0.3333333333333333"},
+				{OperationType: orchestrator.OperationTypeMultiplication, Result: "0.9999999999999999"},
+			},
+			result: "1",
+		},
+		{
+			name: "Precision of final operation",
+			rounding: orchestrator.Rounding{
+				Precision:          2,
+				Mode:               orchestrator.RoundingHalfEven,
+				OperationPrecision: map[string]int{"division": 5},
+			},
+			operations: []*orchestrator.Operation{
+				{OperationType: orchestrator.OperationTypeAddition, Result: "2"},
+				{OperationType: orchestrator.OperationTypeDivision, Result: "0.6666666666666666"},
+			},
+			result: "0.66667",
+		},
+		{
+			name:     "Rounding disabled",
+			rounding: orchestrator.NoRounding,
+			operations: []*orchestrator.Operation{
+				{OperationType: orchestrator.OperationTypeDivision, Result: "0.3333333333333333"},
+			},
+			result: "0.3333333333333333",
+		},
+		{
+			name:     "Infinity is kept",
+			rounding: twoPlaces,
+			operations: []*orchestrator.Operation{
+				{OperationType: orchestrator.OperationTypeDivision, Result: "inf"},
+			},
+			result: "inf",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			calculationID := uuid.New()
+			calcRepo := new(MockCalculationRepository)
+			opRepo := new(MockOperationRepository)
+
+			for _, op := range tc.operations {
+				op.ID, op.CalculationID, op.Status = uuid.New(), calculationID, orchestrator.OperationStatusCompleted
+			}
+			calcRepo.On("FindByID", mock.Anything, calculationID).Return(&orchestrator.Calculation{
+				ID:     calculationID,
+				Status: orchestrator.CalculationStatusInProgress,
+			}, nil)
+			opRepo.On("FindByCalculationID", mock.Anything, calculationID).Return(tc.operations, nil)
+			calcRepo.On("UpdateStatus", mock.Anything, calculationID,
+				orchestrator.CalculationStatusCompleted, tc.result, "").Return(nil).Once()
+			calcRepo.On("UpdateWarnings", mock.Anything, calculationID, mock.Anything).Return(nil).Maybe()
+
+			uc := calculation.NewUseCase(calcRepo, opRepo, new(MockExpressionParser))
+			uc.SetResultRounding(tc.rounding)
+
+			require.NoError(t, uc.UpdateCalculationStatus(setupTestContext(), calculationID))
+			calcRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateCalculationStatusWarnings(t *testing.T) {
+	calculationID := uuid.New()
+	calcRepo := new(MockCalculationRepository)
+	opRepo := new(MockOperationRepository)
+	division := &orchestrator.Operation{
+		ID: uuid.New(), CalculationID: calculationID, OperationType: orchestrator.OperationTypeDivision,
+		Operand1: "1", Operand2: "0", Result: "+Inf", Status: orchestrator.OperationStatusCompleted,
+	}
+	// Сложение лишь передает бесконечность дальше и своего предупреждения не добавляет
+	addition := &orchestrator.Operation{
+		ID: uuid.New(), CalculationID: calculationID, OperationType: orchestrator.OperationTypeAddition,
+		Operand1: "ref:" + division.ID.String(), Operand2: "2", Result: "+Inf", Status: orchestrator.OperationStatusCompleted,
+	}
+
+	calcRepo.On("FindByID", mock.Anything, calculationID).Return(&orchestrator.Calculation{
+		ID:     calculationID,
+		Status: orchestrator.CalculationStatusInProgress,
+	}, nil)
+	opRepo.On("FindByCalculationID", mock.Anything, calculationID).Return([]*orchestrator.Operation{division, addition}, nil)
+	calcRepo.On("UpdateStatus", mock.Anything, calculationID,
+		orchestrator.CalculationStatusCompleted, "+Inf", "").Return(nil).Once()
+	calcRepo.On("UpdateWarnings", mock.Anything, calculationID,
+		[]string{"division of 1 by zero gave +Inf"}).Return(nil).Once()
+
+	uc := calculation.NewUseCase(calcRepo, opRepo, new(MockExpressionParser))
+
+	require.NoError(t, uc.UpdateCalculationStatus(setupTestContext(), calculationID))
+	calcRepo.AssertExpectations(t)
+}
+
+func TestUpdateCalculationStatusEmptyOperationsGrace(t *testing.T) {
+	calculationID := uuid.New()
+	grace := time.Minute
+
+	testCases := []struct {
+		name       string
+		createdAt  time.Time
+		setupMocks func(*MockCalculationRepository)
+	}{
+		{
+			name:       "Just-created calculation stays pending within grace window",
+			createdAt:  time.Now(),
+			setupMocks: func(*MockCalculationRepository) {},
+		},
+		{
+			name:      "Calculation past grace window is marked errored",
+			createdAt: time.Now().Add(-2 * grace),
+			setupMocks: func(calcRepo *MockCalculationRepository) {
+				calcRepo.On("UpdateStatus", mock.Anything, calculationID,
+					orchestrator.CalculationStatusError, "", "No operations found").Return(nil)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := setupTestContext()
+
+			calcRepo := new(MockCalculationRepository)
+			opRepo := new(MockOperationRepository)
+			parser := new(MockExpressionParser)
+
+			calcRepo.On("FindByID", mock.Anything, calculationID).Return(&orchestrator.Calculation{
+				ID:        calculationID,
+				Status:    orchestrator.CalculationStatusPending,
+				CreatedAt: tc.createdAt,
+			}, nil)
+			opRepo.On("FindByCalculationID", mock.Anything, calculationID).Return([]*orchestrator.Operation{}, nil)
+			tc.setupMocks(calcRepo)
+
+			uc := calculation.NewUseCase(calcRepo, opRepo, parser)
+			uc.SetEmptyOperationsGrace(grace)
+
+			assert.NoError(t, uc.UpdateCalculationStatus(ctx, calculationID))
+
+			calcRepo.AssertExpectations(t)
+			opRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCompactedCalculation(t *testing.T) {
+	ctx := setupTestContext()
+	userID := uuid.New()
+	calculationID := uuid.New()
+	compactedAt := time.Now()
+
+	archived := []orchestrator.Operation{
+		{ID: uuid.New(), CalculationID: calculationID, OperationType: orchestrator.OperationTypeAddition,
+			Operand1: "2", Operand2: "3", Result: "5", Status: orchestrator.OperationStatusCompleted},
+		{ID: uuid.New(), CalculationID: calculationID, OperationType: orchestrator.OperationTypeMultiplication,
+			Operand1: "5", Operand2: "4", Result: "20", Status: orchestrator.OperationStatusCompleted},
+	}
+
+	calcRepo := new(MockCalculationRepository)
+	opRepo := new(MockOperationRepository)
+	calcRepo.On("FindByID", mock.Anything, calculationID).Return(&orchestrator.Calculation{
+		ID:          calculationID,
+		UserID:      userID,
+		Expression:  "(2+3)*4",
+		Result:      "20",
+		Status:      orchestrator.CalculationStatusCompleted,
+		CompactedAt: &compactedAt,
+		Operations:  archived,
+	}, nil)
+
+	uc := calculation.NewUseCase(calcRepo, opRepo, new(MockExpressionParser))
+
+	t.Run("Operations are read from the archive", func(t *testing.T) {
+		calc, err := uc.GetCalculation(ctx, calculationID, userID)
+		require.NoError(t, err)
+		assert.Equal(t, archived, calc.Operations)
+	})
+
+	t.Run("Status is not recomputed without operation rows", func(t *testing.T) {
+		assert.NoError(t, uc.UpdateCalculationStatus(ctx, calculationID))
+	})
+
+	calcRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	opRepo.AssertNotCalled(t, "FindByCalculationID", mock.Anything, mock.Anything)
+}
+
+func TestCancelCalculation(t *testing.T) {
+	userID := uuid.New()
+	calculationID := uuid.New()
+	pendingOpID := uuid.New()
+	inProgressOpID := uuid.New()
+
+	testCases := []struct {
+		name          string
+		userID        uuid.UUID
+		setupMocks    func(*MockCalculationRepository, *MockOperationRepository, *MockOperationCancellation)
+		expectedError error
+		expectedCalls []string
+	}{
+		{
+			name:   "Success case",
+			userID: userID,
+			setupMocks: func(calcRepo *MockCalculationRepository, opRepo *MockOperationRepository, cancellation *MockOperationCancellation) {
+				calcRepo.On("FindByID", mock.Anything, calculationID).Return(&orchestrator.Calculation{
+					ID:     calculationID,
+					UserID: userID,
+					Status: orchestrator.CalculationStatusInProgress,
+				}, nil)
+
+				opRepo.On("FindByCalculationID", mock.Anything, calculationID).Return([]*orchestrator.Operation{
+					{ID: uuid.New(), CalculationID: calculationID, Status: orchestrator.OperationStatusCompleted, Result: "3"},
+					{ID: inProgressOpID, CalculationID: calculationID, Status: orchestrator.OperationStatusInProgress},
+					{ID: pendingOpID, CalculationID: calculationID, Status: orchestrator.OperationStatusPending},
+				}, nil)
+
+				opRepo.On("UpdateStatus", mock.Anything, inProgressOpID,
+					orchestrator.OperationStatusCancelled, "", mock.Anything).Return(nil)
+				opRepo.On("UpdateStatus", mock.Anything, pendingOpID,
+					orchestrator.OperationStatusCancelled, "", mock.Anything).Return(nil)
+
+				calcRepo.On("UpdateStatus", mock.Anything, calculationID,
+					orchestrator.CalculationStatusCancelled, "", mock.Anything).Return(nil)
+
+				cancellation.On("CancelOperations", []uuid.UUID{inProgressOpID, pendingOpID}).Return()
+			},
+			expectedError: nil,
+			expectedCalls: []string{"begin", "commit"},
+		},
+		{
+			name:   "Status update failure rolls back operation cancels",
+			userID: userID,
+			setupMocks: func(calcRepo *MockCalculationRepository, opRepo *MockOperationRepository, _ *MockOperationCancellation) {
+				calcRepo.On("FindByID", mock.Anything, calculationID).Return(&orchestrator.Calculation{
+					ID:     calculationID,
+					UserID: userID,
+					Status: orchestrator.CalculationStatusPending,
+				}, nil)
+
+				opRepo.On("FindByCalculationID", mock.Anything, calculationID).Return([]*orchestrator.Operation{
+					{ID: pendingOpID, CalculationID: calculationID, Status: orchestrator.OperationStatusPending},
+				}, nil)
+				opRepo.On("UpdateStatus", mock.Anything, pendingOpID,
+					orchestrator.OperationStatusCancelled, "", mock.Anything).Return(nil)
+
+				calcRepo.On("UpdateStatus", mock.Anything, calculationID,
+					orchestrator.CalculationStatusCancelled, "", mock.Anything).Return(errors.New("connection reset"))
+			},
+			expectedError: domainerrors.ErrInternalError,
+			expectedCalls: []string{"begin", "rollback"},
+		},
+		{
+			name:          "Invalid user ID",
+			userID:        uuid.Nil,
+			setupMocks:    func(*MockCalculationRepository, *MockOperationRepository, *MockOperationCancellation) {},
+			expectedError: domainerrors.ErrInvalidUserID,
+		},
+		{
+			name:   "Calculation not found",
+			userID: userID,
+			setupMocks: func(calcRepo *MockCalculationRepository, opRepo *MockOperationRepository, cancellation *MockOperationCancellation) {
+				calcRepo.On("FindByID", mock.Anything, calculationID).Return(nil, nil)
+			},
+			expectedError: domainerrors.ErrCalculationNotFound,
+		},
+		{
+			name:   "Calculation owned by another user",
+			userID: userID,
+			setupMocks: func(calcRepo *MockCalculationRepository, opRepo *MockOperationRepository, cancellation *MockOperationCancellation) {
+				calcRepo.On("FindByID", mock.Anything, calculationID).Return(&orchestrator.Calculation{
+					ID:     calculationID,
+					UserID: uuid.New(),
+					Status: orchestrator.CalculationStatusPending,
+				}, nil)
+			},
+			expectedError: domainerrors.ErrUnauthorizedAccess,
+		},
+		{
+			name:   "Completed calculation cannot be cancelled",
+			userID: userID,
+			setupMocks: func(calcRepo *MockCalculationRepository, opRepo *MockOperationRepository, cancellation *MockOperationCancellation) {
+				calcRepo.On("FindByID", mock.Anything, calculationID).Return(&orchestrator.Calculation{
+					ID:     calculationID,
+					UserID: userID,
+					Status: orchestrator.CalculationStatusCompleted,
+				}, nil)
+			},
+			expectedError: domainerrors.ErrCalcNotCancellable,
+		},
+		{
+			name:   "Cancelled calculation cannot be cancelled again",
+			userID: userID,
+			setupMocks: func(calcRepo *MockCalculationRepository, opRepo *MockOperationRepository, cancellation *MockOperationCancellation) {
+				calcRepo.On("FindByID", mock.Anything, calculationID).Return(&orchestrator.Calculation{
+					ID:     calculationID,
+					UserID: userID,
+					Status: orchestrator.CalculationStatusCancelled,
+				}, nil)
+			},
+			expectedError: domainerrors.ErrCalcNotCancellable,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := setupTestContext()
+
+			calcRepo := new(MockCalculationRepository)
+			opRepo := new(MockOperationRepository)
+			parser := new(MockExpressionParser)
+			cancellation := new(MockOperationCancellation)
+			var calls []string
+
+			tc.setupMocks(calcRepo, opRepo, cancellation)
+
+			uc := calculation.NewUseCase(calcRepo, opRepo, parser)
+			uc.SetOperationCancellation(cancellation)
+			uc.SetTxManager(recordingTxManager{calls: &calls})
+
+			err := uc.CancelCalculation(ctx, calculationID, tc.userID)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				cancellation.AssertNotCalled(t, "CancelOperations", mock.Anything)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedCalls, calls)
+
+			calcRepo.AssertExpectations(t)
+			opRepo.AssertExpectations(t)
+			cancellation.AssertExpectations(t)
+		})
+	}
+}
+
+type recordingTxManager struct {
+	calls *[]string
+}
+
+func (m recordingTxManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	*m.calls = append(*m.calls, "begin")
+	if err := fn(ctx); err != nil {
+		*m.calls = append(*m.calls, "rollback")
+		return err
+	}
+	*m.calls = append(*m.calls, "commit")
+	return nil
+}
+
+func TestDeleteCalculation(t *testing.T) {
+	userID := uuid.New()
+	calculationID := uuid.New()
+	pendingOpID := uuid.New()
+
+	ownCalculation := &orchestrator.Calculation{
+		ID:     calculationID,
+		UserID: userID,
+		Status: orchestrator.CalculationStatusInProgress,
+	}
+	operations := []*orchestrator.Operation{
+		{ID: uuid.New(), CalculationID: calculationID, Status: orchestrator.OperationStatusCompleted, Result: "3"},
+		{ID: pendingOpID, CalculationID: calculationID, Status: orchestrator.OperationStatusPending},
+	}
+
+	testCases := []struct {
+		name          string
+		userID        uuid.UUID
+		setupMocks    func(*MockCalculationRepository, *MockOperationRepository, *MockOperationCancellation, *[]string)
+		expectedCalls []string
+		expectedError error
+	}{
+		{
+			name:   "Operations are deleted before calculation",
+			userID: userID,
+			setupMocks: func(calcRepo *MockCalculationRepository, opRepo *MockOperationRepository, cancellation *MockOperationCancellation, calls *[]string) {
+				calcRepo.On("FindByID", mock.Anything, calculationID).Return(ownCalculation, nil)
+				opRepo.On("FindByCalculationID", mock.Anything, calculationID).Return(operations, nil)
+				opRepo.On("DeleteByCalculationID", mock.Anything, calculationID).
+					Run(func(mock.Arguments) { *calls = append(*calls, "delete operations") }).
+					Return(len(operations), nil)
+				calcRepo.On("Delete", mock.Anything, calculationID).
+					Run(func(mock.Arguments) { *calls = append(*calls, "delete calculation") }).
+					Return(nil)
+				cancellation.On("CancelOperations", []uuid.UUID{pendingOpID}).Return()
+			},
+			expectedCalls: []string{"begin", "delete operations", "delete calculation", "commit"},
+		},
+		{
+			name:   "Failed operations delete keeps calculation",
+			userID: userID,
+			setupMocks: func(calcRepo *MockCalculationRepository, opRepo *MockOperationRepository, _ *MockOperationCancellation, calls *[]string) {
+				calcRepo.On("FindByID", mock.Anything, calculationID).Return(ownCalculation, nil)
+				opRepo.On("FindByCalculationID", mock.Anything, calculationID).Return(operations, nil)
+				opRepo.On("DeleteByCalculationID", mock.Anything, calculationID).
+					Run(func(mock.Arguments) { *calls = append(*calls, "delete operations") }).
+					Return(0, errors.New("db error"))
+			},
+			expectedCalls: []string{"begin", "delete operations", "rollback"},
+			expectedError: domainerrors.ErrInternalError,
+		},
+		{
+			name:   "Failed calculation delete rolls back",
+			userID: userID,
+			setupMocks: func(calcRepo *MockCalculationRepository, opRepo *MockOperationRepository, _ *MockOperationCancellation, calls *[]string) {
+				calcRepo.On("FindByID", mock.Anything, calculationID).Return(ownCalculation, nil)
+				opRepo.On("FindByCalculationID", mock.Anything, calculationID).Return(operations, nil)
+				opRepo.On("DeleteByCalculationID", mock.Anything, calculationID).
+					Run(func(mock.Arguments) { *calls = append(*calls, "delete operations") }).
+					Return(len(operations), nil)
+				calcRepo.On("Delete", mock.Anything, calculationID).
+					Run(func(mock.Arguments) { *calls = append(*calls, "delete calculation") }).
+					Return(errors.New("db error"))
+			},
+			expectedCalls: []string{"begin", "delete operations", "delete calculation", "rollback"},
+			expectedError: domainerrors.ErrInternalError,
+		},
+		{
+			name:          "Invalid user ID",
+			userID:        uuid.Nil,
+			setupMocks:    func(*MockCalculationRepository, *MockOperationRepository, *MockOperationCancellation, *[]string) {},
+			expectedError: domainerrors.ErrInvalidUserID,
+		},
+		{
+			name:   "Calculation not found",
+			userID: userID,
+			setupMocks: func(calcRepo *MockCalculationRepository, _ *MockOperationRepository, _ *MockOperationCancellation, _ *[]string) {
+				calcRepo.On("FindByID", mock.Anything, calculationID).Return(nil, nil)
+			},
+			expectedError: domainerrors.ErrCalculationNotFound,
+		},
+		{
+			name:   "Calculation owned by another user",
+			userID: userID,
+			setupMocks: func(calcRepo *MockCalculationRepository, _ *MockOperationRepository, _ *MockOperationCancellation, _ *[]string) {
+				calcRepo.On("FindByID", mock.Anything, calculationID).Return(&orchestrator.Calculation{
+					ID:     calculationID,
+					UserID: uuid.New(),
+					Status: orchestrator.CalculationStatusCompleted,
+				}, nil)
+			},
+			expectedError: domainerrors.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := setupTestContext()
+
+			calcRepo := new(MockCalculationRepository)
+			opRepo := new(MockOperationRepository)
+			parser := new(MockExpressionParser)
+			cancellation := new(MockOperationCancellation)
+			var calls []string
+
+			tc.setupMocks(calcRepo, opRepo, cancellation, &calls)
+
+			uc := calculation.NewUseCase(calcRepo, opRepo, parser)
+			uc.SetOperationCancellation(cancellation)
+			uc.SetTxManager(recordingTxManager{calls: &calls})
+
+			err := uc.DeleteCalculation(ctx, calculationID, tc.userID)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedCalls, calls)
+
+			calcRepo.AssertExpectations(t)
+			opRepo.AssertExpectations(t)
+			cancellation.AssertExpectations(t)
+			if tc.expectedError != nil {
+				cancellation.AssertNotCalled(t, "CancelOperations", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestPreviewExpression(t *testing.T) {
+	ctx := setupTestContext()
+	options := orchestrator.PreviewOptions{Spaced: true}
+
+	t.Run("Success", func(t *testing.T) {
+		parser := new(MockExpressionParser)
+		parser.On("Parenthesize", mock.Anything, "2+3*4", options).Return("2 + (3 * 4)", nil)
+
+		uc := calculation.NewUseCase(new(MockCalculationRepository), new(MockOperationRepository), parser)
+
+		preview, err := uc.PreviewExpression(ctx, "2+3*4", options)
+		require.NoError(t, err)
+		assert.Equal(t, "2+3*4", preview.Expression)
+		assert.Equal(t, "2 + (3 * 4)", preview.Grouped)
+		parser.AssertExpectations(t)
+	})
+
+	t.Run("Empty expression", func(t *testing.T) {
+		parser := new(MockExpressionParser)
+		uc := calculation.NewUseCase(new(MockCalculationRepository), new(MockOperationRepository), parser)
+
+		_, err := uc.PreviewExpression(ctx, "  ", options)
+		require.ErrorIs(t, err, domainerrors.ErrInvalidExpression)
+		parser.AssertNotCalled(t, "Parenthesize", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Invalid expression", func(t *testing.T) {
+		parser := new(MockExpressionParser)
+		parser.On("Parenthesize", mock.Anything, "2+*3", options).Return("", errors.New("invalid expression"))
+
+		uc := calculation.NewUseCase(new(MockCalculationRepository), new(MockOperationRepository), parser)
+
+		_, err := uc.PreviewExpression(ctx, "2+*3", options)
+		require.ErrorIs(t, err, domainerrors.ErrInvalidExpression)
+	})
+}
+
+func TestValidateExpression(t *testing.T) {
+	ctx := setupTestContext()
+
+	t.Run("Valid expression", func(t *testing.T) {
+		parser := new(MockExpressionParser)
+		parser.On("Validate", mock.Anything, "2+3*4").Return(nil)
+		uc := calculation.NewUseCase(new(MockCalculationRepository), new(MockOperationRepository), parser)
+
+		validation, err := uc.ValidateExpression(ctx, "2+3*4")
+		require.NoError(t, err)
+		assert.Equal(t, &orchestrator.ExpressionValidation{Expression: "2+3*4", Valid: true}, validation)
+	})
+
+	t.Run("Syntax error with offset", func(t *testing.T) {
+		parser := new(MockExpressionParser)
+		syntaxErr := &orchestrator.SyntaxError{Offset: 2, Message: "expected operand"}
+		parser.On("Validate", mock.Anything, "2+").Return(fmt.Errorf("invalid expression: %w", syntaxErr))
+		uc := calculation.NewUseCase(new(MockCalculationRepository), new(MockOperationRepository), parser)
+
+		validation, err := uc.ValidateExpression(ctx, "2+")
+		require.NoError(t, err)
+		assert.False(t, validation.Valid)
+		require.NotNil(t, validation.ErrorOffset)
+		assert.Equal(t, 2, *validation.ErrorOffset)
+		assert.Equal(t, "expected operand", validation.ErrorMessage)
+	})
+
+	t.Run("Error without position", func(t *testing.T) {
+		parser := new(MockExpressionParser)
+		parser.On("Validate", mock.Anything, "").Return(errors.New("expression is empty"))
+		uc := calculation.NewUseCase(new(MockCalculationRepository), new(MockOperationRepository), parser)
+
+		validation, err := uc.ValidateExpression(ctx, "")
+		require.NoError(t, err)
+		assert.False(t, validation.Valid)
+		require.NotNil(t, validation.ErrorOffset)
+		assert.Zero(t, *validation.ErrorOffset)
+		assert.Equal(t, "expression is empty", validation.ErrorMessage)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		parser := new(MockExpressionParser)
+		parser.On("Validate", mock.Anything, "1+1").Return(context.DeadlineExceeded)
+		uc := calculation.NewUseCase(new(MockCalculationRepository), new(MockOperationRepository), parser)
+
+		_, err := uc.ValidateExpression(ctx, "1+1")
+		require.ErrorIs(t, err, domainerrors.ErrParseTimeout)
+	})
+}
+
+func TestCompareExpressions(t *testing.T) {
+	testCases := []struct {
+		name          string
+		expressionA   string
+		expressionB   string
+		tolerance     float64
+		expectedEqual bool
+		expectedError error
+	}{
+		{
+			name:          "Fraction equals decimal",
+			expressionA:   "1/2",
+			expressionB:   "0.5",
+			expectedEqual: true,
+		},
+		{
+			name:          "Different results",
+			expressionA:   "2+2",
+			expressionB:   "5",
+			expectedEqual: false,
+		},
+		{
+			name:          "Near-equal within tolerance",
+			expressionA:   "1/3",
+			expressionB:   "0.3333",
+			tolerance:     0.001,
+			expectedEqual: true,
+		},
+		{
+			name:          "Near-equal outside tolerance",
+			expressionA:   "1/3",
+			expressionB:   "0.3333",
+			tolerance:     0.00001,
+			expectedEqual: false,
+		},
+		{
+			name:          "Negative tolerance",
+			expressionA:   "1",
+			expressionB:   "1",
+			tolerance:     -1,
+			expectedError: domainerrors.ErrInvalidTolerance,
+		},
+		{
+			name:          "Empty expression",
+			expressionA:   "",
+			expressionB:   "1",
+			expectedError: domainerrors.ErrInvalidExpression,
+		},
+		{
+			name:          "Invalid expression",
+			expressionA:   "2+",
+			expressionB:   "2",
+			expectedError: domainerrors.ErrInvalidExpression,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := setupTestContext()
+
+			calcRepo := new(MockCalculationRepository)
+			opRepo := new(MockOperationRepository)
+			uc := calculation.NewUseCase(calcRepo, opRepo, parsersvc.NewService(100))
+
+			result, err := uc.CompareExpressions(ctx, tc.expressionA, tc.expressionB, tc.tolerance)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				assert.Nil(t, result)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedEqual, result.Equal)
+			assert.Equal(t, tc.tolerance, result.Tolerance)
+
+			calcRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			opRepo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestDiffCalculations(t *testing.T) {
+	userID := uuid.New()
+	calcA := &orchestrator.Calculation{
+		ID:         uuid.New(),
+		UserID:     userID,
+		Expression: "2 + 2 * 3",
+		Result:     "8",
+		Status:     orchestrator.CalculationStatusCompleted,
+	}
+	calcB := &orchestrator.Calculation{
+		ID:         uuid.New(),
+		UserID:     userID,
+		Expression: "(2+2)*3",
+		Result:     "12.5",
+		Status:     orchestrator.CalculationStatusCompleted,
+	}
+	calcSame := &orchestrator.Calculation{
+		ID:         uuid.New(),
+		UserID:     userID,
+		Expression: "2+2*3",
+		Result:     "8.0",
+		Status:     orchestrator.CalculationStatusCompleted,
+	}
+	calcPending := &orchestrator.Calculation{
+		ID:         uuid.New(),
+		UserID:     userID,
+		Expression: "2+2*3",
+		Status:     orchestrator.CalculationStatusPending,
+	}
+	calcForeign := &orchestrator.Calculation{
+		ID:         uuid.New(),
+		UserID:     uuid.New(),
+		Expression: "1+1",
+		Result:     "2",
+		Status:     orchestrator.CalculationStatusCompleted,
+	}
+
+	newUseCase := func(calcs ...*orchestrator.Calculation) (*calculation.UseCaseImpl, *MockCalculationRepository) {
+		calcRepo := new(MockCalculationRepository)
+		for _, calc := range calcs {
+			calcRepo.On("FindByID", mock.Anything, calc.ID).Return(calc, nil)
+		}
+		return calculation.NewUseCase(calcRepo, new(MockOperationRepository), new(MockExpressionParser)), calcRepo
+	}
+
+	t.Run("Different expressions and results", func(t *testing.T) {
+		uc, calcRepo := newUseCase(calcA, calcB)
+
+		diff, err := uc.DiffCalculations(setupTestContext(), calcA.ID, calcB.ID, userID)
+
+		require.NoError(t, err)
+		assert.Equal(t, calcA.ID, diff.CalculationIDA)
+		assert.Equal(t, calcB.ID, diff.CalculationIDB)
+		assert.False(t, diff.SameExpression)
+		assert.False(t, diff.SameResult)
+		require.NotNil(t, diff.Delta)
+		assert.InDelta(t, 4.5, *diff.Delta, 1e-9)
+		calcRepo.AssertExpectations(t)
+	})
+
+	t.Run("Same expression and numerically equal result", func(t *testing.T) {
+		uc, _ := newUseCase(calcA, calcSame)
+
+		diff, err := uc.DiffCalculations(setupTestContext(), calcA.ID, calcSame.ID, userID)
+
+		require.NoError(t, err)
+		assert.True(t, diff.SameExpression)
+		assert.True(t, diff.SameResult)
+		require.NotNil(t, diff.Delta)
+		assert.Zero(t, *diff.Delta)
+	})
+
+	t.Run("Unfinished calculation has no delta", func(t *testing.T) {
+		uc, _ := newUseCase(calcA, calcPending)
+
+		diff, err := uc.DiffCalculations(setupTestContext(), calcA.ID, calcPending.ID, userID)
+
+		require.NoError(t, err)
+		assert.True(t, diff.SameExpression)
+		assert.False(t, diff.SameResult)
+		assert.Nil(t, diff.Delta)
+	})
+
+	t.Run("Foreign calculation", func(t *testing.T) {
+		uc, _ := newUseCase(calcA, calcForeign)
+
+		diff, err := uc.DiffCalculations(setupTestContext(), calcA.ID, calcForeign.ID, userID)
+
+		assert.ErrorIs(t, err, domainerrors.ErrUnauthorizedAccess)
+		assert.Nil(t, diff)
+	})
+
+	t.Run("Missing calculation", func(t *testing.T) {
+		missingID := uuid.New()
+		uc, calcRepo := newUseCase(calcA)
+		calcRepo.On("FindByID", mock.Anything, missingID).Return(nil, nil)
+
+		diff, err := uc.DiffCalculations(setupTestContext(), calcA.ID, missingID, userID)
+
+		assert.ErrorIs(t, err, domainerrors.ErrCalculationNotFound)
+		assert.Nil(t, diff)
+	})
+}
+
+type stubAgentPool struct {
+	orchapi.AgentPool
+	stats  *agent.PoolStats
+	agents []*agent.Agent
+}
+
+func (s *stubAgentPool) GetPoolStats() (*agent.PoolStats, error) {
+	return s.stats, nil
+}
+
+func (s *stubAgentPool) ListAgents() ([]*agent.Agent, error) {
+	return s.agents, nil
+}
+
+type stubDBPoolStats struct {
+	stats system.DBPoolStats
+}
+
+func (s stubDBPoolStats) PoolStats() system.DBPoolStats {
+	return s.stats
+}
+
+func TestGetSystemStats(t *testing.T) {
+	ctx := setupTestContext()
+
+	t.Run("Aggregates repositories, pool and database stats", func(t *testing.T) {
+		calcRepo := new(MockCalculationRepository)
+		opRepo := new(MockOperationRepository)
+		uc := calculation.NewUseCase(calcRepo, opRepo, new(MockExpressionParser))
+
+		calcRepo.On("CountByStatus", mock.Anything).Return(map[orchestrator.CalculationStatus]int{
+			orchestrator.CalculationStatusCompleted: 7,
+			orchestrator.CalculationStatusPending:   2,
+			orchestrator.CalculationStatusError:     1,
+		}, nil)
+		opRepo.On("CountByStatus", mock.Anything, orchestrator.OperationStatusPending).Return(5, nil)
+
+		uc.SetAgentPool(&stubAgentPool{stats: &agent.PoolStats{TotalAgents: 4, OnlineAgents: 2, BusyAgents: 1}})
+		dbPool := system.DBPoolStats{TotalConns: 5, IdleConns: 1, AcquiredConns: 4, MaxConns: 20}
+		uc.SetDBPoolStatsProvider(stubDBPoolStats{stats: dbPool})
+
+		stats, err := uc.GetSystemStats(ctx)
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]int{"COMPLETED": 7, "PENDING": 2, "ERROR": 1}, stats.CalculationsByStatus)
+		assert.Equal(t, 10, stats.TotalCalculations)
+		assert.Equal(t, 5, stats.PendingOperations)
+		assert.Equal(t, 4, stats.TotalAgents)
+		assert.Equal(t, 3, stats.ActiveAgents)
+		assert.Equal(t, dbPool, stats.DBPool)
+	})
+
+	t.Run("Without agent pool and database stats", func(t *testing.T) {
+		calcRepo := new(MockCalculationRepository)
+		opRepo := new(MockOperationRepository)
+		uc := calculation.NewUseCase(calcRepo, opRepo, new(MockExpressionParser))
+
+		calcRepo.On("CountByStatus", mock.Anything).Return(map[orchestrator.CalculationStatus]int{}, nil)
+		opRepo.On("CountByStatus", mock.Anything, orchestrator.OperationStatusPending).Return(0, nil)
+
+		stats, err := uc.GetSystemStats(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, stats.TotalAgents)
+		assert.Zero(t, stats.ActiveAgents)
+		assert.Equal(t, system.DBPoolStats{}, stats.DBPool)
+	})
+
+	t.Run("Repository error", func(t *testing.T) {
+		calcRepo := new(MockCalculationRepository)
+		uc := calculation.NewUseCase(calcRepo, new(MockOperationRepository), new(MockExpressionParser))
+
+		calcRepo.On("CountByStatus", mock.Anything).Return(nil, errors.New("db down"))
+
+		_, err := uc.GetSystemStats(ctx)
+		assert.Error(t, err)
+	})
+}
+
+func TestGetResultStats(t *testing.T) {
+	ctx := setupTestContext()
+	userID := uuid.New()
+
+	t.Run("Returns repository aggregates", func(t *testing.T) {
+		calcRepo := new(MockCalculationRepository)
+		uc := calculation.NewUseCase(calcRepo, new(MockOperationRepository), new(MockExpressionParser))
+
+		expected := &orchestrator.ResultStats{Count: 4, Sum: "10.5", Mean: "2.625", Min: "-3", Max: "10"}
+		calcRepo.On("ResultStats", mock.Anything, userID).Return(expected, nil)
+
+		stats, err := uc.GetResultStats(ctx, userID)
+		require.NoError(t, err)
+		assert.Equal(t, expected, stats)
+		calcRepo.AssertExpectations(t)
+	})
+
+	t.Run("Invalid user ID", func(t *testing.T) {
+		calcRepo := new(MockCalculationRepository)
+		uc := calculation.NewUseCase(calcRepo, new(MockOperationRepository), new(MockExpressionParser))
+
+		_, err := uc.GetResultStats(ctx, uuid.Nil)
+		require.ErrorIs(t, err, domainerrors.ErrInvalidUserID)
+		calcRepo.AssertNotCalled(t, "ResultStats", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Repository error", func(t *testing.T) {
+		calcRepo := new(MockCalculationRepository)
+		uc := calculation.NewUseCase(calcRepo, new(MockOperationRepository), new(MockExpressionParser))
+
+		calcRepo.On("ResultStats", mock.Anything, userID).Return(nil, errors.New("db down"))
+
+		_, err := uc.GetResultStats(ctx, userID)
+		require.ErrorIs(t, err, domainerrors.ErrInternalError)
+	})
+}
@@ -0,0 +1,70 @@
+package calculation
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	"github.com/google/uuid"
+)
+
+// maxReferences - наибольшее число разных вычислений, на которые может ссылаться выражение.
+const maxReferences = 10
+
+// referencePattern находит ссылки вида calc:{uuid} на результаты прошлых вычислений.
+var referencePattern = regexp.MustCompile(`calc:([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})`)
+
+// resolveReferences подставляет в выражение результаты вычислений, на которые оно ссылается.
+// Каждое вычисление должно принадлежать пользователю и быть завершено успешно.
+// Результат подставляется в скобках, чтобы отрицательное значение не меняло разбор выражения.
+func (uc *UseCaseImpl) resolveReferences(ctx context.Context, userID uuid.UUID, expression string) (string, error) {
+	matches := referencePattern.FindAllStringSubmatch(expression, -1)
+	if len(matches) == 0 {
+		return expression, nil
+	}
+
+	results := make(map[string]string, len(matches))
+	for _, match := range matches {
+		if _, ok := results[match[0]]; ok {
+			continue
+		}
+		if len(results) == maxReferences {
+			return "", fmt.Errorf("%w: at most %d", domainerrors.ErrTooManyReferences, maxReferences)
+		}
+
+		result, err := uc.referencedResult(ctx, userID, uuid.MustParse(match[1]))
+		if err != nil {
+			return "", err
+		}
+		results[match[0]] = result
+	}
+
+	return referencePattern.ReplaceAllStringFunc(expression, func(ref string) string {
+		return "(" + results[ref] + ")"
+	}), nil
+}
+
+// referencedResult возвращает результат завершенного вычисления пользователя.
+func (uc *UseCaseImpl) referencedResult(ctx context.Context, userID, calculationID uuid.UUID) (string, error) {
+	calc, err := uc.calculationRepo.FindByID(ctx, calculationID)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", domainerrors.ErrInternalError, err)
+	}
+
+	if calc == nil {
+		return "", fmt.Errorf("%w: %s", domainerrors.ErrCalculationNotFound, calculationID)
+	}
+
+	if calc.UserID != userID {
+		return "", fmt.Errorf("%w: %s", domainerrors.ErrUnauthorizedAccess, calculationID)
+	}
+
+	if calc.Status != orchestrator.CalculationStatusCompleted || strings.TrimSpace(calc.Result) == "" {
+		return "", fmt.Errorf("%w: %s is %s", domainerrors.ErrReferenceNotCompleted, calculationID, calc.Status)
+	}
+
+	return calc.Result, nil
+}
@@ -0,0 +1,124 @@
+package calculation
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	orchrepo "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/repository/orchestrator"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// SetResultCache задает общий для всех пользователей кэш результатов. Выражение, результат
+// которого уже есть в кэше, сохраняется сразу завершенным, без разбора на операции.
+// Результаты успешно завершенных вычислений без ссылок на другие вычисления попадают в кэш.
+// Результаты прогрева округляются так же, как результаты вычислений (SetResultRounding).
+func (uc *UseCaseImpl) SetResultCache(cache orchrepo.ResultCache) {
+	uc.resultCache = cache
+}
+
+// WarmResultCache заранее вычисляет выражения и сохраняет их результаты в кэш, чтобы первые
+// запросы частых выражений не ждали агентов. Выражения, которые не удалось вычислить,
+// пропускаются с предупреждением в журнале. Возвращает количество сохраненных выражений.
+func (uc *UseCaseImpl) WarmResultCache(ctx context.Context, expressions []string) int {
+	if uc.resultCache == nil {
+		return 0
+	}
+
+	log := logger.ContextLogger(ctx, nil).With(zap.String("op", "CalculationUseCase.WarmResultCache"))
+
+	warmed := 0
+	for _, expression := range expressions {
+		key := cacheKey(expression)
+		if key == "" {
+			continue
+		}
+
+		result, err := uc.evaluate(ctx, expression)
+		if err != nil {
+			log.Warn("Failed to evaluate warmup expression", zap.String("expression", expression), zap.Error(err))
+			continue
+		}
+
+		rounding := uc.resultRounding.ForOperation(uc.finalOperationType(ctx, expression))
+		if err := uc.resultCache.Set(ctx, key, rounding.Format(result)); err != nil {
+			log.Warn("Failed to cache warmup expression", zap.String("expression", expression), zap.Error(err))
+			continue
+		}
+		warmed++
+	}
+
+	log.Info("Result cache warmed", zap.Int("expressions", warmed), zap.Int("configured", len(expressions)))
+	return warmed
+}
+
+// finalOperationType возвращает тип последней операции выражения, по которой округляется
+// результат вычисления. Разбор нужен только при точности, заданной для отдельных операций.
+func (uc *UseCaseImpl) finalOperationType(ctx context.Context, expression string) orchestrator.OperationType {
+	if len(uc.resultRounding.OperationPrecision) == 0 {
+		return orchestrator.OperationTypeUnspecified
+	}
+
+	parseCtx, cancel := context.WithTimeout(ctx, uc.parsingTimeout)
+	defer cancel()
+
+	operations, err := uc.parser.Parse(parseCtx, expression)
+	if err != nil || len(operations) == 0 {
+		return orchestrator.OperationTypeUnspecified
+	}
+	return operations[len(operations)-1].OperationType
+}
+
+// cachedResult возвращает результат выражения из кэша. Ошибка кэша не мешает вычислению
+// и только записывается в журнал.
+func (uc *UseCaseImpl) cachedResult(ctx context.Context, log *zap.Logger, expression string) (string, bool) {
+	if uc.resultCache == nil {
+		return "", false
+	}
+
+	result, found, err := uc.resultCache.Get(ctx, cacheKey(expression))
+	if err != nil {
+		log.Warn("Failed to read result cache", zap.Error(err))
+		return "", false
+	}
+	return result, found
+}
+
+// cacheResult сохраняет результат завершенного вычисления. Выражения со ссылками на другие
+// вычисления зависят от пользователя и не кэшируются.
+func (uc *UseCaseImpl) cacheResult(ctx context.Context, log logger.Logger, calculationID uuid.UUID, expression, result string) {
+	if uc.resultCache == nil || result == "" || referencePattern.MatchString(expression) {
+		return
+	}
+
+	if err := uc.resultCache.Set(ctx, cacheKey(expression), result); err != nil {
+		log.Warn("Failed to cache calculation result",
+			zap.String("calculation_id", calculationID.String()),
+			zap.Error(err))
+	}
+}
+
+// newCachedCalculation создает запись вычисления, результат которого взят из кэша.
+func newCachedCalculation(userID uuid.UUID, expression string, source orchestrator.CalculationSource, result string) *orchestrator.Calculation {
+	return &orchestrator.Calculation{
+		ID:         uuid.New(),
+		UserID:     userID,
+		Expression: expression,
+		Result:     result,
+		Status:     orchestrator.CalculationStatusCompleted,
+		Source:     source,
+	}
+}
+
+// cacheKey приводит выражение к ключу кэша: пробелы не влияют на результат и удаляются.
+func cacheKey(expression string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, expression)
+}
@@ -0,0 +1,149 @@
+package calculation
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	"github.com/google/uuid"
+)
+
+// subscriptionBuffer - число событий, которые подписчик может не успеть прочитать.
+// У вычисления не больше четырех смен статуса, поэтому буфер не переполняется
+// даже у медленного клиента.
+const subscriptionBuffer = 8
+
+// statusSubscription - подписка на изменения статуса одного вычисления.
+type statusSubscription struct {
+	calculationID uuid.UUID
+	events        chan orchestrator.CalculationEvent
+}
+
+// statusBroker рассылает изменения статусов вычислений подписчикам.
+type statusBroker struct {
+	mu   sync.Mutex
+	subs map[uuid.UUID]map[*statusSubscription]struct{}
+}
+
+func newStatusBroker() *statusBroker {
+	return &statusBroker{subs: make(map[uuid.UUID]map[*statusSubscription]struct{})}
+}
+
+// subscribe регистрирует подписку на изменения статуса вычисления.
+func (b *statusBroker) subscribe(calculationID uuid.UUID) *statusSubscription {
+	sub := &statusSubscription{
+		calculationID: calculationID,
+		events:        make(chan orchestrator.CalculationEvent, subscriptionBuffer),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.subs[calculationID] == nil {
+		b.subs[calculationID] = make(map[*statusSubscription]struct{})
+	}
+	b.subs[calculationID][sub] = struct{}{}
+	return sub
+}
+
+// unsubscribe удаляет подписку. Канал подписки не закрывается, так как его читает
+// только владелец подписки.
+func (b *statusBroker) unsubscribe(sub *statusSubscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subs := b.subs[sub.calculationID]
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(b.subs, sub.calculationID)
+	}
+}
+
+// publish доставляет событие всем подписчикам вычисления, не блокируясь:
+// если буфер подписчика заполнен, событие для него отбрасывается.
+func (b *statusBroker) publish(event orchestrator.CalculationEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subs[event.CalculationID] {
+		select {
+		case sub.events <- event:
+		default:
+		}
+	}
+}
+
+// SubscribeCalculation подписывает пользователя на изменения статуса его вычисления.
+// Первым событием канал получает текущее состояние вычисления, затем - каждую смену
+// статуса, зафиксированную UpdateCalculationStatus или CancelCalculation. Канал
+// закрывается после события с конечным статусом или при отмене ctx.
+func (uc *UseCaseImpl) SubscribeCalculation(ctx context.Context, calculationID uuid.UUID, userID uuid.UUID) (<-chan orchestrator.CalculationEvent, error) {
+	// Подписка оформляется до чтения вычисления, чтобы не пропустить смену статуса между ними
+	sub := uc.statusBroker.subscribe(calculationID)
+
+	calc, err := uc.calculationRepo.FindByID(ctx, calculationID)
+	if err != nil {
+		uc.statusBroker.unsubscribe(sub)
+		return nil, fmt.Errorf("%w: %v", domainerrors.ErrInternalError, err)
+	}
+
+	if calc == nil {
+		uc.statusBroker.unsubscribe(sub)
+		return nil, domainerrors.ErrCalculationNotFound
+	}
+
+	if calc.UserID != userID {
+		uc.statusBroker.unsubscribe(sub)
+		return nil, domainerrors.ErrUnauthorizedAccess
+	}
+
+	events := make(chan orchestrator.CalculationEvent, 1)
+	go uc.forwardEvents(ctx, sub, orchestrator.NewCalculationEvent(calc), events)
+	return events, nil
+}
+
+// forwardEvents передает подписчику текущее состояние и последующие смены статуса,
+// пропуская события, не меняющие статус.
+func (uc *UseCaseImpl) forwardEvents(ctx context.Context, sub *statusSubscription, current orchestrator.CalculationEvent, events chan<- orchestrator.CalculationEvent) {
+	defer close(events)
+	defer uc.statusBroker.unsubscribe(sub)
+
+	event := current
+	for {
+		select {
+		case events <- event:
+		case <-ctx.Done():
+			return
+		}
+
+		if event.Status.IsFinal() {
+			return
+		}
+
+		last := event.Status
+		for event.Status == last {
+			select {
+			case event = <-sub.events:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// publishStatus уведомляет подписчиков о новом статусе вычисления.
+func (uc *UseCaseImpl) publishStatus(calculationID uuid.UUID, status orchestrator.CalculationStatus, result, errorMsg string) {
+	if uc.statusBroker == nil {
+		return
+	}
+	uc.statusBroker.publish(orchestrator.CalculationEvent{
+		CalculationID: calculationID,
+		Status:        status,
+		Result:        result,
+		ErrorMessage:  errorMsg,
+		UpdatedAt:     time.Now(),
+	})
+}
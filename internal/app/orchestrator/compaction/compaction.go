@@ -0,0 +1,129 @@
+// Package compaction реализует сжатие операций завершенных вычислений в запись вычисления.
+package compaction
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
+	orchrepo "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/repository/orchestrator"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/repository/transaction"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultInterval - период сжатия, если задан неположительный интервал.
+	defaultInterval = time.Minute
+	// defaultBatchSize - количество вычислений за проход, если задан неположительный размер.
+	defaultBatchSize = 100
+)
+
+// Compactor переносит операции завершенных вычислений в JSON в записи вычисления
+// и удаляет их строки. Ход вычисления остается доступен через Calculation.Operations.
+type Compactor struct {
+	archiveRepo   orchrepo.OperationArchiveRepository
+	operationRepo orchrepo.OperationRepository
+	txManager     transaction.TxManager
+}
+
+// NewCompactor создает сжатие операций. Без txManager сохранение архива и удаление строк
+// выполняются отдельными запросами.
+func NewCompactor(
+	archiveRepo orchrepo.OperationArchiveRepository,
+	operationRepo orchrepo.OperationRepository,
+	txManager transaction.TxManager,
+) *Compactor {
+	if archiveRepo == nil {
+		panic(fmt.Sprintf("%v: operation archive repository", domainerrors.ErrNilDependency))
+	}
+	if operationRepo == nil {
+		panic(fmt.Sprintf("%v: operation repository", domainerrors.ErrNilDependency))
+	}
+
+	return &Compactor{
+		archiveRepo:   archiveRepo,
+		operationRepo: operationRepo,
+		txManager:     txManager,
+	}
+}
+
+// Compact сжимает операции не более limit завершенных вычислений, не менявшихся с момента
+// before, и возвращает количество сжатых вычислений. При ошибке хранилища обработка
+// останавливается, а результат содержит уже сжатые вычисления.
+func (c *Compactor) Compact(ctx context.Context, before time.Time, limit int) (int, error) {
+	ids, err := c.archiveRepo.FindCompactable(ctx, before, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find compactable calculations: %w", err)
+	}
+
+	compacted := 0
+	for _, id := range ids {
+		if err := c.compactOne(ctx, id); err != nil {
+			return compacted, fmt.Errorf("failed to compact calculation %s: %w", id, err)
+		}
+		compacted++
+	}
+
+	return compacted, nil
+}
+
+// compactOne сохраняет операции вычисления в архив и удаляет их строки в одной транзакции.
+func (c *Compactor) compactOne(ctx context.Context, calculationID uuid.UUID) error {
+	operations, err := c.operationRepo.FindByCalculationID(ctx, calculationID)
+	if err != nil {
+		return fmt.Errorf("load operations: %w", err)
+	}
+
+	return c.withinTransaction(ctx, func(ctx context.Context) error {
+		if err := c.archiveRepo.ArchiveOperations(ctx, calculationID, operations); err != nil {
+			return fmt.Errorf("archive operations: %w", err)
+		}
+		if _, err := c.operationRepo.DeleteByCalculationID(ctx, calculationID); err != nil {
+			return fmt.Errorf("delete operations: %w", err)
+		}
+		return nil
+	})
+}
+
+func (c *Compactor) withinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if c.txManager == nil {
+		return fn(ctx)
+	}
+	return c.txManager.WithinTransaction(ctx, fn)
+}
+
+// Run каждые interval сжимает операции вычислений, завершенных не менее delay назад,
+// по limit вычислений за проход, до завершения ctx. Неположительные interval и limit
+// заменяются значениями по умолчанию.
+func (c *Compactor) Run(ctx context.Context, interval, delay time.Duration, limit int) {
+	log := logger.ContextLogger(ctx, nil).With(zap.String("op", "Compactor.Run"))
+
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	if limit <= 0 {
+		limit = defaultBatchSize
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			compacted, err := c.Compact(ctx, time.Now().Add(-delay), limit)
+			if err != nil {
+				log.Warn("Failed to compact operations", zap.Int("compacted", compacted), zap.Error(err))
+				continue
+			}
+			if compacted > 0 {
+				log.Info("Compacted operations of completed calculations", zap.Int("compacted", compacted))
+			}
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package compaction_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/app/orchestrator/compaction"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockArchiveRepository struct {
+	mock.Mock
+}
+
+func (m *MockArchiveRepository) FindCompactable(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error) {
+	args := m.Called(ctx, before, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockArchiveRepository) ArchiveOperations(ctx context.Context, calculationID uuid.UUID, operations []*orchestrator.Operation) error {
+	args := m.Called(ctx, calculationID, operations)
+	return args.Error(0)
+}
+
+type MockOperationRepository struct {
+	mock.Mock
+}
+
+func (m *MockOperationRepository) Create(ctx context.Context, operation *orchestrator.Operation) (*orchestrator.Operation, error) {
+	args := m.Called(ctx, operation)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*orchestrator.Operation), args.Error(1)
+}
+
+func (m *MockOperationRepository) CreateBatch(ctx context.Context, operations []*orchestrator.Operation) error {
+	args := m.Called(ctx, operations)
+	return args.Error(0)
+}
+
+func (m *MockOperationRepository) CreateBatchPartial(ctx context.Context, operations []*orchestrator.Operation) ([]orchestrator.OperationInsertResult, error) {
+	args := m.Called(ctx, operations)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]orchestrator.OperationInsertResult), args.Error(1)
+}
+
+func (m *MockOperationRepository) FindByID(ctx context.Context, id uuid.UUID) (*orchestrator.Operation, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*orchestrator.Operation), args.Error(1)
+}
+
+func (m *MockOperationRepository) FindByCalculationID(ctx context.Context, calculationID uuid.UUID) ([]*orchestrator.Operation, error) {
+	args := m.Called(ctx, calculationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*orchestrator.Operation), args.Error(1)
+}
+
+func (m *MockOperationRepository) GetPendingOperations(ctx context.Context, limit int) ([]*orchestrator.Operation, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*orchestrator.Operation), args.Error(1)
+}
+
+func (m *MockOperationRepository) Update(ctx context.Context, operation *orchestrator.Operation) error {
+	args := m.Called(ctx, operation)
+	return args.Error(0)
+}
+
+func (m *MockOperationRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status orchestrator.OperationStatus, result string, errorMsg string) error {
+	args := m.Called(ctx, id, status, result, errorMsg)
+	return args.Error(0)
+}
+
+func (m *MockOperationRepository) UpdateStatusBatch(ctx context.Context, updates []orchestrator.OperationStatusUpdate) error {
+	args := m.Called(ctx, updates)
+	return args.Error(0)
+}
+
+func (m *MockOperationRepository) AssignAgent(ctx context.Context, operationID uuid.UUID, agentID string) error {
+	args := m.Called(ctx, operationID, agentID)
+	return args.Error(0)
+}
+
+func (m *MockOperationRepository) CountByStatus(ctx context.Context, status orchestrator.OperationStatus) (int, error) {
+	args := m.Called(ctx, status)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockOperationRepository) CountPendingByUserID(ctx context.Context, userID uuid.UUID) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockOperationRepository) DeleteByCalculationID(ctx context.Context, calculationID uuid.UUID) (int, error) {
+	args := m.Called(ctx, calculationID)
+	return args.Int(0), args.Error(1)
+}
+
+// stubTxManager выполняет fn в "транзакции" и запоминает, сколько раз ее откатили.
+type stubTxManager struct {
+	rollbacks int
+}
+
+func (s *stubTxManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := fn(ctx); err != nil {
+		s.rollbacks++
+		return err
+	}
+	return nil
+}
+
+func TestCompact(t *testing.T) {
+	before := time.Now().Add(-time.Hour)
+
+	t.Run("Archives operations and deletes their rows", func(t *testing.T) {
+		ctx := context.Background()
+		first, second := uuid.New(), uuid.New()
+		firstOps := []*orchestrator.Operation{
+			{ID: uuid.New(), CalculationID: first, Operand1: "2", Operand2: "3", Result: "5", Status: orchestrator.OperationStatusCompleted},
+		}
+		secondOps := []*orchestrator.Operation{
+			{ID: uuid.New(), CalculationID: second, Operand1: "1", Operand2: "1", Result: "2", Status: orchestrator.OperationStatusCompleted},
+			{ID: uuid.New(), CalculationID: second, Operand1: "2", Operand2: "4", Result: "8", Status: orchestrator.OperationStatusCompleted},
+		}
+
+		archiveRepo := new(MockArchiveRepository)
+		operationRepo := new(MockOperationRepository)
+		archiveRepo.On("FindCompactable", ctx, before, 10).Return([]uuid.UUID{first, second}, nil)
+		operationRepo.On("FindByCalculationID", ctx, first).Return(firstOps, nil)
+		operationRepo.On("FindByCalculationID", ctx, second).Return(secondOps, nil)
+		archiveRepo.On("ArchiveOperations", ctx, first, firstOps).Return(nil).Once()
+		archiveRepo.On("ArchiveOperations", ctx, second, secondOps).Return(nil).Once()
+		operationRepo.On("DeleteByCalculationID", ctx, first).Return(1, nil).Once()
+		operationRepo.On("DeleteByCalculationID", ctx, second).Return(2, nil).Once()
+
+		compacted, err := compaction.NewCompactor(archiveRepo, operationRepo, &stubTxManager{}).Compact(ctx, before, 10)
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, compacted)
+		archiveRepo.AssertExpectations(t)
+		operationRepo.AssertExpectations(t)
+	})
+
+	t.Run("Failed delete rolls back the archive", func(t *testing.T) {
+		ctx := context.Background()
+		id := uuid.New()
+		ops := []*orchestrator.Operation{{ID: uuid.New(), CalculationID: id, Status: orchestrator.OperationStatusCompleted}}
+
+		archiveRepo := new(MockArchiveRepository)
+		operationRepo := new(MockOperationRepository)
+		archiveRepo.On("FindCompactable", ctx, before, 10).Return([]uuid.UUID{id}, nil)
+		operationRepo.On("FindByCalculationID", ctx, id).Return(ops, nil)
+		archiveRepo.On("ArchiveOperations", ctx, id, ops).Return(nil)
+		operationRepo.On("DeleteByCalculationID", ctx, id).Return(0, errors.New("db unavailable"))
+
+		txManager := &stubTxManager{}
+		compacted, err := compaction.NewCompactor(archiveRepo, operationRepo, txManager).Compact(ctx, before, 10)
+
+		require.Error(t, err)
+		assert.Zero(t, compacted)
+		assert.Equal(t, 1, txManager.rollbacks)
+	})
+
+	t.Run("Lookup error", func(t *testing.T) {
+		ctx := context.Background()
+		archiveRepo := new(MockArchiveRepository)
+		archiveRepo.On("FindCompactable", ctx, before, 10).Return(nil, errors.New("db unavailable"))
+
+		compacted, err := compaction.NewCompactor(archiveRepo, new(MockOperationRepository), nil).Compact(ctx, before, 10)
+
+		require.Error(t, err)
+		assert.Zero(t, compacted)
+	})
+}
+
+func TestNewCompactorPanicsOnNilDependency(t *testing.T) {
+	assert.Panics(t, func() { compaction.NewCompactor(nil, new(MockOperationRepository), nil) })
+	assert.Panics(t, func() { compaction.NewCompactor(new(MockArchiveRepository), nil, nil) })
+}
@@ -0,0 +1,123 @@
+// Package deadletter реализует повторный запуск операций из хранилища недоставленных.
+package deadletter
+
+import (
+	"context"
+	"fmt"
+
+	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	orchrepo "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/repository/orchestrator"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// ReplayResult содержит итоги повторного запуска.
+type ReplayResult struct {
+	// Replayed - операции, возвращенные в очередь на выполнение.
+	Replayed int
+	// Skipped - записи, для которых повторный запуск не нужен: ошибка детерминирована
+	// (деление на ноль, неверный операнд), операция или вычисление удалены, вычисление
+	// отменено или завершено либо операция уже не в статусе ERROR.
+	Skipped int
+}
+
+// Replayer возвращает операции из хранилища недоставленных в статус PENDING,
+// после чего их подхватывает обычный цикл обработки оркестратора.
+type Replayer struct {
+	deadLetterRepo  orchrepo.DeadLetterRepository
+	operationRepo   orchrepo.OperationRepository
+	calculationRepo orchrepo.CalculationRepository
+}
+
+func NewReplayer(
+	deadLetterRepo orchrepo.DeadLetterRepository,
+	operationRepo orchrepo.OperationRepository,
+	calculationRepo orchrepo.CalculationRepository,
+) *Replayer {
+	if deadLetterRepo == nil {
+		panic(fmt.Sprintf("%v: dead letter repository", domainerrors.ErrNilDependency))
+	}
+	if operationRepo == nil {
+		panic(fmt.Sprintf("%v: operation repository", domainerrors.ErrNilDependency))
+	}
+	if calculationRepo == nil {
+		panic(fmt.Sprintf("%v: calculation repository", domainerrors.ErrNilDependency))
+	}
+
+	return &Replayer{
+		deadLetterRepo:  deadLetterRepo,
+		operationRepo:   operationRepo,
+		calculationRepo: calculationRepo,
+	}
+}
+
+// Replay повторно запускает не более limit самых старых записей.
+// При ошибке хранилища обработка останавливается, а результат содержит уже обработанные записи.
+func (r *Replayer) Replay(ctx context.Context, limit int) (ReplayResult, error) {
+	log := logger.ContextLogger(ctx, nil).With(zap.String("op", "Replayer.Replay"))
+
+	var result ReplayResult
+
+	deadLetters, err := r.deadLetterRepo.FindPending(ctx, limit)
+	if err != nil {
+		return result, fmt.Errorf("failed to load dead letters: %w", err)
+	}
+
+	for _, deadLetter := range deadLetters {
+		replayed, err := r.replayOne(ctx, deadLetter)
+		if err != nil {
+			return result, fmt.Errorf("failed to replay dead letter %s: %w", deadLetter.ID, err)
+		}
+
+		if err := r.deadLetterRepo.MarkReplayed(ctx, deadLetter.ID); err != nil {
+			return result, fmt.Errorf("failed to mark dead letter %s replayed: %w", deadLetter.ID, err)
+		}
+
+		if replayed {
+			result.Replayed++
+		} else {
+			result.Skipped++
+		}
+	}
+
+	log.Info("Dead letters replayed",
+		zap.Int("replayed", result.Replayed),
+		zap.Int("skipped", result.Skipped))
+
+	return result, nil
+}
+
+func (r *Replayer) replayOne(ctx context.Context, deadLetter *orchestrator.DeadLetter) (bool, error) {
+	if deadLetter.Deterministic {
+		return false, nil
+	}
+
+	operation, err := r.operationRepo.FindByID(ctx, deadLetter.OperationID)
+	if err != nil {
+		return false, err
+	}
+	if operation == nil || operation.Status != orchestrator.OperationStatusError {
+		return false, nil
+	}
+
+	calc, err := r.calculationRepo.FindByID(ctx, deadLetter.CalculationID)
+	if err != nil {
+		return false, err
+	}
+	if calc == nil || calc.Status == orchestrator.CalculationStatusCancelled || calc.Status == orchestrator.CalculationStatusCompleted {
+		return false, nil
+	}
+
+	if err := r.operationRepo.UpdateStatus(ctx, operation.ID, orchestrator.OperationStatusPending, "", ""); err != nil {
+		return false, err
+	}
+
+	if calc.Status != orchestrator.CalculationStatusInProgress {
+		if err := r.calculationRepo.UpdateStatus(ctx, calc.ID, orchestrator.CalculationStatusInProgress, "", ""); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,317 @@
+package deadletter_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/app/orchestrator/deadletter"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+type MockDeadLetterRepository struct {
+	mock.Mock
+}
+
+func (m *MockDeadLetterRepository) Create(ctx context.Context, deadLetter *orchestrator.DeadLetter) error {
+	args := m.Called(ctx, deadLetter)
+	return args.Error(0)
+}
+
+func (m *MockDeadLetterRepository) FindPending(ctx context.Context, limit int) ([]*orchestrator.DeadLetter, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*orchestrator.DeadLetter), args.Error(1)
+}
+
+func (m *MockDeadLetterRepository) MarkReplayed(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type MockOperationRepository struct {
+	mock.Mock
+}
+
+func (m *MockOperationRepository) Create(ctx context.Context, operation *orchestrator.Operation) (*orchestrator.Operation, error) {
+	args := m.Called(ctx, operation)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*orchestrator.Operation), args.Error(1)
+}
+
+func (m *MockOperationRepository) CreateBatch(ctx context.Context, operations []*orchestrator.Operation) error {
+	args := m.Called(ctx, operations)
+	return args.Error(0)
+}
+
+func (m *MockOperationRepository) CreateBatchPartial(ctx context.Context, operations []*orchestrator.Operation) ([]orchestrator.OperationInsertResult, error) {
+	args := m.Called(ctx, operations)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]orchestrator.OperationInsertResult), args.Error(1)
+}
+
+func (m *MockOperationRepository) FindByID(ctx context.Context, id uuid.UUID) (*orchestrator.Operation, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*orchestrator.Operation), args.Error(1)
+}
+
+func (m *MockOperationRepository) FindByCalculationID(ctx context.Context, calculationID uuid.UUID) ([]*orchestrator.Operation, error) {
+	args := m.Called(ctx, calculationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*orchestrator.Operation), args.Error(1)
+}
+
+func (m *MockOperationRepository) GetPendingOperations(ctx context.Context, limit int) ([]*orchestrator.Operation, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*orchestrator.Operation), args.Error(1)
+}
+
+func (m *MockOperationRepository) Update(ctx context.Context, operation *orchestrator.Operation) error {
+	args := m.Called(ctx, operation)
+	return args.Error(0)
+}
+
+func (m *MockOperationRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status orchestrator.OperationStatus, result string, errorMsg string) error {
+	args := m.Called(ctx, id, status, result, errorMsg)
+	return args.Error(0)
+}
+
+func (m *MockOperationRepository) UpdateStatusBatch(ctx context.Context, updates []orchestrator.OperationStatusUpdate) error {
+	args := m.Called(ctx, updates)
+	return args.Error(0)
+}
+
+func (m *MockOperationRepository) AssignAgent(ctx context.Context, operationID uuid.UUID, agentID string) error {
+	args := m.Called(ctx, operationID, agentID)
+	return args.Error(0)
+}
+
+func (m *MockOperationRepository) CountByStatus(ctx context.Context, status orchestrator.OperationStatus) (int, error) {
+	args := m.Called(ctx, status)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockOperationRepository) CountPendingByUserID(ctx context.Context, userID uuid.UUID) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockOperationRepository) DeleteByCalculationID(ctx context.Context, calculationID uuid.UUID) (int, error) {
+	args := m.Called(ctx, calculationID)
+	return args.Int(0), args.Error(1)
+}
+
+type MockCalculationRepository struct {
+	mock.Mock
+}
+
+func (m *MockCalculationRepository) Create(ctx context.Context, calculation *orchestrator.Calculation) (*orchestrator.Calculation, error) {
+	args := m.Called(ctx, calculation)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*orchestrator.Calculation), args.Error(1)
+}
+
+func (m *MockCalculationRepository) FindByID(ctx context.Context, id uuid.UUID, _ ...orchestrator.FindOption) (*orchestrator.Calculation, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*orchestrator.Calculation), args.Error(1)
+}
+
+func (m *MockCalculationRepository) FindByUserID(ctx context.Context, userID uuid.UUID, filter orchestrator.CalculationFilter) ([]*orchestrator.Calculation, int, error) {
+	args := m.Called(ctx, userID, filter)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*orchestrator.Calculation), args.Int(1), args.Error(2)
+}
+
+func (m *MockCalculationRepository) Update(ctx context.Context, calculation *orchestrator.Calculation) error {
+	args := m.Called(ctx, calculation)
+	return args.Error(0)
+}
+
+func (m *MockCalculationRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status orchestrator.CalculationStatus, result string, errorMsg string) error {
+	args := m.Called(ctx, id, status, result, errorMsg)
+	return args.Error(0)
+}
+
+func (m *MockCalculationRepository) FindByIdempotencyKey(ctx context.Context, userID uuid.UUID, key string) (*orchestrator.Calculation, error) {
+	args := m.Called(ctx, userID, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*orchestrator.Calculation), args.Error(1)
+}
+
+func (m *MockCalculationRepository) UpdateWarnings(ctx context.Context, id uuid.UUID, warnings []string) error {
+	args := m.Called(ctx, id, warnings)
+	return args.Error(0)
+}
+
+func (m *MockCalculationRepository) UpdateDisplayResult(ctx context.Context, id uuid.UUID, displayResult string) error {
+	args := m.Called(ctx, id, displayResult)
+	return args.Error(0)
+}
+
+func (m *MockCalculationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCalculationRepository) CountByStatus(ctx context.Context) (map[orchestrator.CalculationStatus]int, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[orchestrator.CalculationStatus]int), args.Error(1)
+}
+
+func (m *MockCalculationRepository) ResultStats(ctx context.Context, userID uuid.UUID) (*orchestrator.ResultStats, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*orchestrator.ResultStats), args.Error(1)
+}
+
+func setupTestContext() context.Context {
+	return logger.WithLogger(context.Background(), logger.New(zapcore.NewNopCore()))
+}
+
+func newDeadLetter() *orchestrator.DeadLetter {
+	return &orchestrator.DeadLetter{
+		ID:            uuid.New(),
+		OperationID:   uuid.New(),
+		CalculationID: uuid.New(),
+		Expression:    "2+2",
+		OperationType: orchestrator.OperationTypeAddition,
+		Operand1:      "2",
+		Operand2:      "2",
+		ErrorHistory:  []string{"no agent available"},
+	}
+}
+
+func TestReplay(t *testing.T) {
+	t.Run("Returns failed operation to processing", func(t *testing.T) {
+		ctx := setupTestContext()
+		dlRepo := new(MockDeadLetterRepository)
+		opRepo := new(MockOperationRepository)
+		calcRepo := new(MockCalculationRepository)
+
+		dl := newDeadLetter()
+		dlRepo.On("FindPending", mock.Anything, 10).Return([]*orchestrator.DeadLetter{dl}, nil)
+		opRepo.On("FindByID", mock.Anything, dl.OperationID).Return(&orchestrator.Operation{
+			ID:     dl.OperationID,
+			Status: orchestrator.OperationStatusError,
+		}, nil)
+		calcRepo.On("FindByID", mock.Anything, dl.CalculationID).Return(&orchestrator.Calculation{
+			ID:     dl.CalculationID,
+			Status: orchestrator.CalculationStatusError,
+		}, nil)
+		opRepo.On("UpdateStatus", mock.Anything, dl.OperationID, orchestrator.OperationStatusPending, "", "").Return(nil)
+		calcRepo.On("UpdateStatus", mock.Anything, dl.CalculationID, orchestrator.CalculationStatusInProgress, "", "").Return(nil)
+		dlRepo.On("MarkReplayed", mock.Anything, dl.ID).Return(nil)
+
+		result, err := deadletter.NewReplayer(dlRepo, opRepo, calcRepo).Replay(ctx, 10)
+
+		require.NoError(t, err)
+		assert.Equal(t, deadletter.ReplayResult{Replayed: 1}, result)
+		dlRepo.AssertExpectations(t)
+		opRepo.AssertExpectations(t)
+		calcRepo.AssertExpectations(t)
+	})
+
+	t.Run("Skips cancelled calculation and resolved operation", func(t *testing.T) {
+		ctx := setupTestContext()
+		dlRepo := new(MockDeadLetterRepository)
+		opRepo := new(MockOperationRepository)
+		calcRepo := new(MockCalculationRepository)
+
+		cancelled := newDeadLetter()
+		resolved := newDeadLetter()
+		dlRepo.On("FindPending", mock.Anything, 10).Return([]*orchestrator.DeadLetter{cancelled, resolved}, nil)
+		opRepo.On("FindByID", mock.Anything, cancelled.OperationID).Return(&orchestrator.Operation{
+			ID:     cancelled.OperationID,
+			Status: orchestrator.OperationStatusError,
+		}, nil)
+		calcRepo.On("FindByID", mock.Anything, cancelled.CalculationID).Return(&orchestrator.Calculation{
+			ID:     cancelled.CalculationID,
+			Status: orchestrator.CalculationStatusCancelled,
+		}, nil)
+		opRepo.On("FindByID", mock.Anything, resolved.OperationID).Return(&orchestrator.Operation{
+			ID:     resolved.OperationID,
+			Status: orchestrator.OperationStatusCompleted,
+		}, nil)
+		dlRepo.On("MarkReplayed", mock.Anything, cancelled.ID).Return(nil)
+		dlRepo.On("MarkReplayed", mock.Anything, resolved.ID).Return(nil)
+
+		result, err := deadletter.NewReplayer(dlRepo, opRepo, calcRepo).Replay(ctx, 10)
+
+		require.NoError(t, err)
+		assert.Equal(t, deadletter.ReplayResult{Skipped: 2}, result)
+		opRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		calcRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		dlRepo.AssertExpectations(t)
+	})
+
+	t.Run("Skips deterministic failure", func(t *testing.T) {
+		ctx := setupTestContext()
+		dlRepo := new(MockDeadLetterRepository)
+		opRepo := new(MockOperationRepository)
+		calcRepo := new(MockCalculationRepository)
+
+		dl := newDeadLetter()
+		dl.Deterministic = true
+		dlRepo.On("FindPending", mock.Anything, 10).Return([]*orchestrator.DeadLetter{dl}, nil)
+		dlRepo.On("MarkReplayed", mock.Anything, dl.ID).Return(nil)
+
+		result, err := deadletter.NewReplayer(dlRepo, opRepo, calcRepo).Replay(ctx, 10)
+
+		require.NoError(t, err)
+		assert.Equal(t, deadletter.ReplayResult{Skipped: 1}, result)
+		opRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		dlRepo.AssertExpectations(t)
+	})
+
+	t.Run("Stops on repository error", func(t *testing.T) {
+		ctx := setupTestContext()
+		dlRepo := new(MockDeadLetterRepository)
+		opRepo := new(MockOperationRepository)
+		calcRepo := new(MockCalculationRepository)
+
+		dl := newDeadLetter()
+		repoErr := errors.New("db down")
+		dlRepo.On("FindPending", mock.Anything, 10).Return([]*orchestrator.DeadLetter{dl}, nil)
+		opRepo.On("FindByID", mock.Anything, dl.OperationID).Return(nil, repoErr)
+
+		result, err := deadletter.NewReplayer(dlRepo, opRepo, calcRepo).Replay(ctx, 10)
+
+		require.ErrorIs(t, err, repoErr)
+		assert.Equal(t, deadletter.ReplayResult{}, result)
+		dlRepo.AssertNotCalled(t, "MarkReplayed", mock.Anything, mock.Anything)
+	})
+}
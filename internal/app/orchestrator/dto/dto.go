@@ -88,6 +88,10 @@ func GetOperationTypeString(opType orchestrator.OperationType) string {
 		return "MULTIPLICATION"
 	case orchestrator.OperationTypeDivision:
 		return "DIVISION"
+	case orchestrator.OperationTypeModulo:
+		return "MODULO"
+	case orchestrator.OperationTypeFactorial:
+		return "FACTORIAL"
 	default:
 		return "UNSPECIFIED"
 	}
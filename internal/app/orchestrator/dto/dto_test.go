@@ -236,6 +236,16 @@ func TestGetOperationTypeString(t *testing.T) {
 			input:    orchestrator.OperationTypeDivision,
 			expected: "DIVISION",
 		},
+		{
+			name:     "Modulo",
+			input:    orchestrator.OperationTypeModulo,
+			expected: "MODULO",
+		},
+		{
+			name:     "Factorial",
+			input:    orchestrator.OperationTypeFactorial,
+			expected: "FACTORIAL",
+		},
 		{
 			name:     "Invalid",
 			input:    orchestrator.OperationType(99),
@@ -0,0 +1,71 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	"go.uber.org/zap"
+)
+
+// PlanDispatch возвращает, каким агентам были бы назначены ожидающие операции
+// при текущем состоянии пула, не выполняя их и не меняя их статус.
+func (p *OperationProcessor) PlanDispatch(ctx context.Context) ([]orchestrator.DispatchAssignment, error) {
+	operations, err := p.operationRepo.GetPendingOperations(ctx, p.agentConfig.ComputerPower)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending operations: %w", err)
+	}
+	orchestrator.SortByPriority(operations)
+	return p.planDispatch(operations)
+}
+
+// planDispatch распределяет операции по снимку пула агентов, см. orchestrator.PlanDispatch.
+func (p *OperationProcessor) planDispatch(operations []*orchestrator.Operation) ([]orchestrator.DispatchAssignment, error) {
+	agents, err := p.agentPool.ListAgents()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list agents: %w", err)
+	}
+	return orchestrator.PlanDispatch(operations, agents, p.IsCancelled), nil
+}
+
+// logDispatchPlan пишет в журнал распределение операций в режиме пробного распределения.
+// Одинаковое распределение повторно не записывается, чтобы не засорять журнал на каждом тике.
+func (p *OperationProcessor) logDispatchPlan(operations []*orchestrator.Operation, log *zap.Logger) {
+	plan, err := p.planDispatch(operations)
+	if err != nil {
+		log.Error("Failed to plan dispatch", zap.Error(err))
+		return
+	}
+
+	fingerprint := dispatchFingerprint(plan)
+	if fingerprint == p.lastDryRunPlan {
+		return
+	}
+	p.lastDryRunPlan = fingerprint
+
+	for _, assignment := range plan {
+		fields := []zap.Field{
+			zap.String("operation_id", assignment.OperationID.String()),
+			zap.String("calculation_id", assignment.CalculationID.String()),
+			zap.String("operation_type", assignment.OperationType.Name()),
+		}
+		if assignment.AgentID == "" {
+			log.Warn("Dry run: operation would not be dispatched", append(fields, zap.String("reason", assignment.Reason))...)
+			continue
+		}
+		log.Info("Dry run: operation would be dispatched", append(fields, zap.String("agent_id", assignment.AgentID))...)
+	}
+}
+
+func dispatchFingerprint(plan []orchestrator.DispatchAssignment) string {
+	var b strings.Builder
+	for _, assignment := range plan {
+		b.WriteString(assignment.OperationID.String())
+		b.WriteByte('=')
+		b.WriteString(assignment.AgentID)
+		b.WriteString(assignment.Reason)
+		b.WriteByte(';')
+	}
+	return b.String()
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"runtime/debug"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	orchapi "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/api/orchestrator"
 	orchrepo "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/repository/orchestrator"
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/retry"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
@@ -25,6 +27,15 @@ type AgentConfig struct {
 	TimeSubtraction     time.Duration
 	TimeMultiplications time.Duration
 	TimeDivisions       time.Duration
+	TimeModulo          time.Duration
+	TimeFactorial       time.Duration
+	// OperationTimeout - время на назначение операции агенту со всеми повторами.
+	// Операция, не назначенная за это время, завершается ошибкой timeout.
+	OperationTimeout time.Duration
+	// OperationTimeouts переопределяет OperationTimeout для отдельных типов операций по имени.
+	OperationTimeouts map[string]time.Duration
+	// AttemptTimeout - время одной попытки назначения операции агенту.
+	AttemptTimeout time.Duration
 }
 
 type OperationProcessor struct {
@@ -37,8 +48,46 @@ type OperationProcessor struct {
 	running           int32
 	operationExecutor orchapi.OperationExecutor
 	agentPool         orchapi.AgentPool
+	cancelledMu       sync.RWMutex
+	cancelled         map[uuid.UUID]time.Time
+	deadLetterRepo    orchrepo.DeadLetterRepository
+	retryPolicy       retry.Policy
+	dryRun            bool
+	lastDryRunPlan    string
+	readinessCheck    func(ctx context.Context) error
+	readinessInterval time.Duration
 }
 
+// defaultRetryPolicy - политика повторного назначения операции агенту по умолчанию.
+var defaultRetryPolicy = retry.Policy{
+	MaxAttempts: 3,
+	BaseDelay:   100 * time.Millisecond,
+	Multiplier:  2,
+}
+
+// retryError хранит ошибки всех попыток выполнения операции.
+type retryError struct {
+	attempts []error
+}
+
+func (e *retryError) Error() string {
+	return fmt.Sprintf("operation execution failed after %d retries: %v", len(e.attempts), e.attempts[len(e.attempts)-1])
+}
+
+func (e *retryError) Unwrap() []error {
+	return e.attempts
+}
+
+// defaultReadinessInterval - интервал повторной проверки готовности по умолчанию.
+const defaultReadinessInterval = time.Second
+
+// cancelledRetention - время, в течение которого хранится отметка об отмене операции.
+const cancelledRetention = 10 * time.Minute
+
+var _ orchapi.OperationCancellation = (*OperationProcessor)(nil)
+
+// NewProcessor создает процессор операций и паникует, если какая-либо зависимость равна nil.
+// Оставлена для обратной совместимости; новому коду следует использовать NewProcessorSafe.
 func NewProcessor(
 	operationRepo orchrepo.OperationRepository,
 	calculationRepo orchrepo.CalculationRepository,
@@ -47,20 +96,38 @@ func NewProcessor(
 	operationExecutor orchapi.OperationExecutor,
 	agentPool orchapi.AgentPool,
 ) *OperationProcessor {
+	processor, err := NewProcessorSafe(operationRepo, calculationRepo, calcUseCase, agentConfig, operationExecutor, agentPool)
+	if err != nil {
+		panic(err.Error())
+	}
+	return processor
+}
+
+// NewProcessorSafe создает процессор операций. В отличие от NewProcessor, для зависимости,
+// равной nil, она не паникует, а возвращает ErrNilDependency с именем зависимости, чтобы
+// вызывающий код мог обработать ошибку конфигурации.
+func NewProcessorSafe(
+	operationRepo orchrepo.OperationRepository,
+	calculationRepo orchrepo.CalculationRepository,
+	calcUseCase orchapi.UseCaseCalculation,
+	agentConfig AgentConfig,
+	operationExecutor orchapi.OperationExecutor,
+	agentPool orchapi.AgentPool,
+) (*OperationProcessor, error) {
 	if operationRepo == nil {
-		panic(fmt.Sprintf("%v: operation repository", domainerrors.ErrNilDependency))
+		return nil, fmt.Errorf("%w: operation repository", domainerrors.ErrNilDependency)
 	}
 	if calculationRepo == nil {
-		panic(fmt.Sprintf("%v: calculation repository", domainerrors.ErrNilDependency))
+		return nil, fmt.Errorf("%w: calculation repository", domainerrors.ErrNilDependency)
 	}
 	if calcUseCase == nil {
-		panic(fmt.Sprintf("%v: calculation use case", domainerrors.ErrNilDependency))
+		return nil, fmt.Errorf("%w: calculation use case", domainerrors.ErrNilDependency)
 	}
 	if operationExecutor == nil {
-		panic(fmt.Sprintf("%v: operation executor", domainerrors.ErrNilDependency))
+		return nil, fmt.Errorf("%w: operation executor", domainerrors.ErrNilDependency)
 	}
 	if agentPool == nil {
-		panic(fmt.Sprintf("%v: agent pool", domainerrors.ErrNilDependency))
+		return nil, fmt.Errorf("%w: agent pool", domainerrors.ErrNilDependency)
 	}
 
 	if agentConfig.AgentID == "" {
@@ -72,6 +139,10 @@ func NewProcessor(
 	setDefaultIfZero(&agentConfig.TimeSubtraction, 150*time.Millisecond)
 	setDefaultIfZero(&agentConfig.TimeMultiplications, 200*time.Millisecond)
 	setDefaultIfZero(&agentConfig.TimeDivisions, 300*time.Millisecond)
+	setDefaultIfZero(&agentConfig.TimeModulo, 300*time.Millisecond)
+	setDefaultIfZero(&agentConfig.TimeFactorial, 300*time.Millisecond)
+	setDefaultIfZero(&agentConfig.OperationTimeout, 10*time.Second)
+	setDefaultIfZero(&agentConfig.AttemptTimeout, 5*time.Second)
 
 	return &OperationProcessor{
 		operationRepo:     operationRepo,
@@ -83,7 +154,17 @@ func NewProcessor(
 		operationExecutor: operationExecutor,
 		agentPool:         agentPool,
 		running:           0,
+		cancelled:         make(map[uuid.UUID]time.Time),
+		retryPolicy:       defaultRetryPolicy,
+	}, nil
+}
+
+// operationTimeout возвращает время на назначение операции агенту с учетом ее типа.
+func (p *OperationProcessor) operationTimeout(operation *orchestrator.Operation) time.Duration {
+	if timeout := p.agentConfig.OperationTimeouts[operation.OperationType.Name()]; timeout > 0 {
+		return timeout
 	}
+	return p.agentConfig.OperationTimeout
 }
 
 func setDefaultIfZero[T comparable](value *T, defaultValue T) {
@@ -93,6 +174,37 @@ func setDefaultIfZero[T comparable](value *T, defaultValue T) {
 	}
 }
 
+// SetDeadLetterRepository включает копирование операций, не выполненных после всех попыток,
+// в отдельное хранилище. Nil отключает копирование.
+func (p *OperationProcessor) SetDeadLetterRepository(repo orchrepo.DeadLetterRepository) {
+	p.deadLetterRepo = repo
+}
+
+// SetRetryPolicy задает политику повторного назначения операции агенту.
+// Недопустимые значения заменяются значениями по умолчанию.
+func (p *OperationProcessor) SetRetryPolicy(policy retry.Policy) {
+	p.retryPolicy = policy.Normalize()
+}
+
+// SetDryRun включает режим пробного распределения: процессор не выполняет ожидающие операции,
+// а только пишет в журнал, каким агентам они были бы назначены. Операции остаются в статусе
+// PENDING. Режим предназначен для диагностики голодания операций и задается до Start.
+func (p *OperationProcessor) SetDryRun(dryRun bool) {
+	p.dryRun = dryRun
+}
+
+// SetReadinessCheck задает проверку, которую процессор выполняет перед первым опросом
+// ожидающих операций, например что схема базы данных уже мигрирована. Пока проверка
+// возвращает ошибку, процессор ждет interval и повторяет ее. Неположительный interval
+// заменяется значением по умолчанию. Задается до Start.
+func (p *OperationProcessor) SetReadinessCheck(check func(ctx context.Context) error, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultReadinessInterval
+	}
+	p.readinessCheck = check
+	p.readinessInterval = interval
+}
+
 func (p *OperationProcessor) Start(ctx context.Context) error {
 	if ctx == nil {
 		return fmt.Errorf("cannot start processor with nil context")
@@ -123,13 +235,51 @@ func (p *OperationProcessor) Start(ctx context.Context) error {
 			}
 		}()
 
-		p.processOperations(processorCtx)
+		if p.waitUntilReady(processorCtx, log) {
+			p.processOperations(processorCtx)
+		}
 		atomic.StoreInt32(&p.running, 0)
 	}()
 
 	return nil
 }
 
+// waitUntilReady ждет успешной проверки готовности. Возвращает false, если процессор
+// остановили или контекст отменили раньше.
+func (p *OperationProcessor) waitUntilReady(ctx context.Context, log logger.Logger) bool {
+	if p.readinessCheck == nil {
+		return true
+	}
+
+	ticker := time.NewTicker(p.readinessInterval)
+	defer ticker.Stop()
+
+	for attempt := 1; ; attempt++ {
+		err := p.readinessCheck(ctx)
+		if err == nil {
+			if attempt > 1 {
+				log.Info("Operation processor is ready", zap.Int("attempts", attempt))
+			}
+			return true
+		}
+
+		log.Warn("Operation processor is not ready, postponing processing",
+			zap.Int("attempt", attempt),
+			zap.Duration("retry_in", p.readinessInterval),
+			zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+
+		if !p.IsRunning() {
+			return false
+		}
+	}
+}
+
 func (p *OperationProcessor) Stop() {
 	atomic.StoreInt32(&p.running, 0)
 }
@@ -138,6 +288,44 @@ func (p *OperationProcessor) IsRunning() bool {
 	return atomic.LoadInt32(&p.running) == 1
 }
 
+// CancelOperations помечает операции как отмененные, чтобы процессор не отправлял их агентам.
+func (p *OperationProcessor) CancelOperations(operationIDs ...uuid.UUID) {
+	now := time.Now()
+
+	p.cancelledMu.Lock()
+	defer p.cancelledMu.Unlock()
+
+	for _, id := range operationIDs {
+		if id == uuid.Nil {
+			continue
+		}
+		p.cancelled[id] = now
+	}
+}
+
+// IsCancelled сообщает, была ли операция отменена.
+func (p *OperationProcessor) IsCancelled(operationID uuid.UUID) bool {
+	p.cancelledMu.RLock()
+	defer p.cancelledMu.RUnlock()
+
+	_, ok := p.cancelled[operationID]
+	return ok
+}
+
+// pruneCancelled удаляет устаревшие отметки об отмене.
+func (p *OperationProcessor) pruneCancelled() {
+	threshold := time.Now().Add(-cancelledRetention)
+
+	p.cancelledMu.Lock()
+	defer p.cancelledMu.Unlock()
+
+	for id, cancelledAt := range p.cancelled {
+		if cancelledAt.Before(threshold) {
+			delete(p.cancelled, id)
+		}
+	}
+}
+
 func (p *OperationProcessor) processOperations(ctx context.Context) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -183,11 +371,11 @@ func (p *OperationProcessor) processOperations(ctx context.Context) {
 				return
 			}
 
+			// Пакет получает контекст цикла: операции назначаются в фоне и ограничены
+			// собственным сроком, а не временем выборки пакета
 			zapLogger := logger.GetZapLogger(log)
 			if zapLogger != nil {
-				batchCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
-				p.processPendingBatch(batchCtx, zapLogger)
-				cancel()
+				p.processPendingBatch(ctx, zapLogger)
 			} else {
 				log.Warn("Failed to get zap logger for processing batch")
 			}
@@ -213,6 +401,14 @@ func (p *OperationProcessor) processPendingBatch(ctx context.Context, log *zap.L
 		return
 	}
 
+	// Операции с большим приоритетом назначаются первыми
+	orchestrator.SortByPriority(operations)
+
+	if p.dryRun {
+		p.logDispatchPlan(operations, log)
+		return
+	}
+
 	log.Debug("Processing batch of operations", zap.Int("count", len(operations)))
 
 	for _, op := range operations {
@@ -258,6 +454,11 @@ func (p *OperationProcessor) processOperation(ctx context.Context, operation *or
 		return
 	}
 
+	if p.IsCancelled(operation.ID) {
+		log.Debug("Skipping cancelled operation", zap.String("operation_id", operation.ID.String()))
+		return
+	}
+
 	select {
 	case <-ctx.Done():
 		return
@@ -284,7 +485,7 @@ func (p *OperationProcessor) processOperation(ctx context.Context, operation *or
 			zap.String("calculation_id", operation.CalculationID.String()),
 		)
 
-		opCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		opCtx, cancel := context.WithTimeout(ctx, p.operationTimeout(operation))
 		defer cancel()
 
 		err := p.executeWithRetry(opCtx, operation, opLog)
@@ -316,8 +517,8 @@ func (p *OperationProcessor) executeWithRetry(ctx context.Context, operation *or
 		log = getDefaultLogger()
 	}
 
-	const maxRetries = 3
-	var lastErr error
+	policy := p.retryPolicy.Normalize()
+	attemptErrs := make([]error, 0, policy.MaxAttempts)
 
 	opLogger := log.With(
 		zap.String("operation_id", operation.ID.String()),
@@ -325,7 +526,7 @@ func (p *OperationProcessor) executeWithRetry(ctx context.Context, operation *or
 		zap.String("calculation_id", operation.CalculationID.String()),
 	)
 
-	for attempt := 0; attempt < maxRetries; attempt++ {
+	for attempt := 0; attempt < policy.MaxAttempts; attempt++ {
 		select {
 		case <-ctx.Done():
 			return fmt.Errorf("%w: %w", domainerrors.ErrContextDone, ctx.Err())
@@ -333,20 +534,18 @@ func (p *OperationProcessor) executeWithRetry(ctx context.Context, operation *or
 		}
 
 		if attempt > 0 {
-			backoffDuration := time.Duration(50*(1<<attempt)) * time.Millisecond
+			backoffDuration := policy.Backoff(attempt)
 			opLogger.Debug("Retrying operation execution",
 				zap.Int("attempt", attempt+1),
 				zap.Duration("backoff", backoffDuration),
-				zap.Error(lastErr))
+				zap.Error(attemptErrs[len(attemptErrs)-1]))
 
-			select {
-			case <-ctx.Done():
-				return fmt.Errorf("%w: %w", domainerrors.ErrContextDone, ctx.Err())
-			case <-time.After(backoffDuration):
+			if err := retry.Sleep(ctx, backoffDuration); err != nil {
+				return fmt.Errorf("%w: %w", domainerrors.ErrContextDone, err)
 			}
 		}
 
-		execCtx, execCancel := context.WithTimeout(ctx, 5*time.Second)
+		execCtx, execCancel := context.WithTimeout(ctx, p.agentConfig.AttemptTimeout)
 		startTime := time.Now()
 
 		err := func() error {
@@ -379,13 +578,13 @@ func (p *OperationProcessor) executeWithRetry(ctx context.Context, operation *or
 			return fmt.Errorf("context error during execution: %w", err)
 		}
 
-		lastErr = err
+		attemptErrs = append(attemptErrs, err)
 		opLogger.Warn("Failed attempt to execute operation",
 			zap.Int("attempt", attempt+1),
 			zap.Error(err))
 	}
 
-	return fmt.Errorf("operation execution failed after %d retries: %w", maxRetries, lastErr)
+	return &retryError{attempts: attemptErrs}
 }
 
 func (p *OperationProcessor) getAgentForOperation(ctx context.Context, operation *orchestrator.Operation, log *zap.Logger) (*agent.Agent, error) {
@@ -479,7 +678,7 @@ func (p *OperationProcessor) assignOperationToAgent(ctx context.Context, agent *
 			zap.Error(updateErr))
 	}
 
-	err := p.agentPool.AssignOperation(agent.ID, operation)
+	err := p.agentPool.AssignOperation(ctx, agent.ID, operation)
 	if err != nil {
 		opLog.Error("Failed to assign operation to agent",
 			zap.Error(err))
@@ -513,6 +712,11 @@ func (p *OperationProcessor) handleOperationError(ctx context.Context, operation
 	)
 
 	errorMsg := "Failed to assign operation to agent: " + execErr.Error()
+	// Истекший срок записывается отдельным сообщением, чтобы клиенты могли отличить его
+	// от ошибки вычисления
+	if errors.Is(execErr, context.DeadlineExceeded) || errors.Is(execErr, domainerrors.ErrOperationTimeout) {
+		errorMsg = domainerrors.ErrOperationTimeout.Error()
+	}
 
 	updateCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
@@ -534,6 +738,8 @@ func (p *OperationProcessor) handleOperationError(ctx context.Context, operation
 		localLog.Error("Failed to update operation status", zap.Error(err))
 	}
 
+	p.recordDeadLetter(updateCtx, operation, execErr, localLog)
+
 	if operation.CalculationID == uuid.Nil || p.calcUseCase == nil {
 		localLog.Warn("Not updating calculation status - invalid calculation ID or nil calcUseCase")
 		return
@@ -545,6 +751,75 @@ func (p *OperationProcessor) handleOperationError(ctx context.Context, operation
 	safeUpdateStatus(calcCtx, p.calcUseCase, operation.CalculationID, localLog)
 }
 
+// deterministicErrors - ошибки вычисления, которые повторятся при любом повторном запуске операции.
+var deterministicErrors = []error{
+	domainerrors.ErrDivisionByZero,
+	domainerrors.ErrInvalidOperand,
+	domainerrors.ErrNumericOverflow,
+	domainerrors.ErrFactorialOperand,
+	domainerrors.ErrUnsupportedOp,
+	domainerrors.ErrInvalidOperationType,
+	domainerrors.ErrInvalidOperation,
+	domainerrors.ErrInvalidReferenceID,
+	domainerrors.ErrNilOperation,
+	domainerrors.ErrInvalidArgs,
+}
+
+// isDeterministic сообщает, вызвана ли ошибка хотя бы одной из попыток самими данными
+// операции, а не недоступностью агентов или инфраструктуры.
+func isDeterministic(err error) bool {
+	for _, target := range deterministicErrors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// recordDeadLetter копирует операцию вместе с выражением и историей ошибок в хранилище недоставленных.
+// Операции с детерминированной ошибкой помечаются, чтобы повторный запуск их пропускал.
+func (p *OperationProcessor) recordDeadLetter(ctx context.Context, operation *orchestrator.Operation, execErr error, log *zap.Logger) {
+	if p.deadLetterRepo == nil {
+		return
+	}
+
+	deadLetter := &orchestrator.DeadLetter{
+		OperationID:   operation.ID,
+		CalculationID: operation.CalculationID,
+		OperationType: operation.OperationType,
+		Operand1:      operation.Operand1,
+		Operand2:      operation.Operand2,
+		ErrorHistory:  errorHistory(execErr),
+		Deterministic: isDeterministic(execErr),
+	}
+
+	calc, err := p.calculationRepo.FindByID(ctx, operation.CalculationID)
+	switch {
+	case err != nil:
+		log.Warn("Failed to load calculation for dead letter", zap.Error(err))
+	case calc != nil:
+		deadLetter.Expression = calc.Expression
+	}
+
+	if err := p.deadLetterRepo.Create(ctx, deadLetter); err != nil {
+		log.Error("Failed to store dead letter", zap.Error(err))
+	}
+}
+
+// errorHistory возвращает сообщения об ошибках всех попыток выполнения операции.
+func errorHistory(err error) []string {
+	var retryErr *retryError
+	if !errors.As(err, &retryErr) {
+		return []string{err.Error()}
+	}
+
+	history := make([]string, 0, len(retryErr.attempts))
+	for _, attemptErr := range retryErr.attempts {
+		history = append(history, attemptErr.Error())
+	}
+	return history
+}
+
 func safeUpdateStatus(ctx context.Context, calcUseCase orchapi.UseCaseCalculation, calculationID uuid.UUID, logger *zap.Logger) {
 	logger = getLoggerOrDefault(logger)
 
@@ -623,6 +898,8 @@ func (p *OperationProcessor) checkPendingCalculations(ctx context.Context, log *
 
 	log.Debug("Checking for stuck calculations")
 
+	p.pruneCancelled()
+
 	// Получаем список операций, которые в процессе обработки
 	pendingOperations, err := p.operationRepo.GetPendingOperations(ctxWithTimeout, 50)
 	if err != nil {
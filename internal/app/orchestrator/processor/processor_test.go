@@ -3,15 +3,26 @@ package processor_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/app/orchestrator/processor"
 	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/agent"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/system"
+	orchapi "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/api/orchestrator"
+	orchrepo "github.com/flexer2006/y.lms-final-task-calc-go/internal/ports/repository/orchestrator"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/retry"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 type MockOperationRepository struct {
@@ -31,6 +42,14 @@ func (m *MockOperationRepository) CreateBatch(ctx context.Context, operations []
 	return args.Error(0)
 }
 
+func (m *MockOperationRepository) CreateBatchPartial(ctx context.Context, operations []*orchestrator.Operation) ([]orchestrator.OperationInsertResult, error) {
+	args := m.Called(ctx, operations)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]orchestrator.OperationInsertResult), args.Error(1)
+}
+
 func (m *MockOperationRepository) FindByID(ctx context.Context, id uuid.UUID) (*orchestrator.Operation, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -65,11 +84,31 @@ func (m *MockOperationRepository) UpdateStatus(ctx context.Context, id uuid.UUID
 	return args.Error(0)
 }
 
+func (m *MockOperationRepository) UpdateStatusBatch(ctx context.Context, updates []orchestrator.OperationStatusUpdate) error {
+	args := m.Called(ctx, updates)
+	return args.Error(0)
+}
+
 func (m *MockOperationRepository) AssignAgent(ctx context.Context, operationID uuid.UUID, agentID string) error {
 	args := m.Called(ctx, operationID, agentID)
 	return args.Error(0)
 }
 
+func (m *MockOperationRepository) CountByStatus(ctx context.Context, status orchestrator.OperationStatus) (int, error) {
+	args := m.Called(ctx, status)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockOperationRepository) CountPendingByUserID(ctx context.Context, userID uuid.UUID) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockOperationRepository) DeleteByCalculationID(ctx context.Context, calculationID uuid.UUID) (int, error) {
+	args := m.Called(ctx, calculationID)
+	return args.Int(0), args.Error(1)
+}
+
 type MockCalculationRepository struct {
 	mock.Mock
 }
@@ -82,7 +121,7 @@ func (m *MockCalculationRepository) Create(ctx context.Context, calculation *orc
 	return args.Get(0).(*orchestrator.Calculation), args.Error(1)
 }
 
-func (m *MockCalculationRepository) FindByID(ctx context.Context, id uuid.UUID) (*orchestrator.Calculation, error) {
+func (m *MockCalculationRepository) FindByID(ctx context.Context, id uuid.UUID, _ ...orchestrator.FindOption) (*orchestrator.Calculation, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -90,12 +129,12 @@ func (m *MockCalculationRepository) FindByID(ctx context.Context, id uuid.UUID)
 	return args.Get(0).(*orchestrator.Calculation), args.Error(1)
 }
 
-func (m *MockCalculationRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*orchestrator.Calculation, error) {
-	args := m.Called(ctx, userID)
+func (m *MockCalculationRepository) FindByUserID(ctx context.Context, userID uuid.UUID, filter orchestrator.CalculationFilter) ([]*orchestrator.Calculation, int, error) {
+	args := m.Called(ctx, userID, filter)
 	if args.Get(0) == nil {
-		return nil, args.Error(1)
+		return nil, args.Int(1), args.Error(2)
 	}
-	return args.Get(0).([]*orchestrator.Calculation), args.Error(1)
+	return args.Get(0).([]*orchestrator.Calculation), args.Int(1), args.Error(2)
 }
 
 func (m *MockCalculationRepository) Update(ctx context.Context, calculation *orchestrator.Calculation) error {
@@ -108,17 +147,51 @@ func (m *MockCalculationRepository) UpdateStatus(ctx context.Context, id uuid.UU
 	return args.Error(0)
 }
 
+func (m *MockCalculationRepository) FindByIdempotencyKey(ctx context.Context, userID uuid.UUID, key string) (*orchestrator.Calculation, error) {
+	args := m.Called(ctx, userID, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*orchestrator.Calculation), args.Error(1)
+}
+
+func (m *MockCalculationRepository) UpdateWarnings(ctx context.Context, id uuid.UUID, warnings []string) error {
+	args := m.Called(ctx, id, warnings)
+	return args.Error(0)
+}
+
+func (m *MockCalculationRepository) UpdateDisplayResult(ctx context.Context, id uuid.UUID, displayResult string) error {
+	args := m.Called(ctx, id, displayResult)
+	return args.Error(0)
+}
+
 func (m *MockCalculationRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
+func (m *MockCalculationRepository) CountByStatus(ctx context.Context) (map[orchestrator.CalculationStatus]int, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[orchestrator.CalculationStatus]int), args.Error(1)
+}
+
+func (m *MockCalculationRepository) ResultStats(ctx context.Context, userID uuid.UUID) (*orchestrator.ResultStats, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*orchestrator.ResultStats), args.Error(1)
+}
+
 type MockCalcUseCase struct {
 	mock.Mock
 }
 
-func (m *MockCalcUseCase) CalculateExpression(ctx context.Context, userID uuid.UUID, expression string) (*orchestrator.Calculation, error) {
-	args := m.Called(ctx, userID, expression)
+func (m *MockCalcUseCase) CalculateExpression(ctx context.Context, userID uuid.UUID, expression string, source orchestrator.CalculationSource) (*orchestrator.Calculation, error) {
+	args := m.Called(ctx, userID, expression, source)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -133,12 +206,54 @@ func (m *MockCalcUseCase) GetCalculation(ctx context.Context, calculationID uuid
 	return args.Get(0).(*orchestrator.Calculation), args.Error(1)
 }
 
-func (m *MockCalcUseCase) ListCalculations(ctx context.Context, userID uuid.UUID) ([]*orchestrator.Calculation, error) {
+func (m *MockCalcUseCase) AdminGetCalculation(ctx context.Context, calculationID uuid.UUID) (*orchestrator.Calculation, error) {
+	args := m.Called(ctx, calculationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*orchestrator.Calculation), args.Error(1)
+}
+
+func (m *MockCalcUseCase) GetOperation(ctx context.Context, operationID uuid.UUID, userID uuid.UUID) (*orchestrator.Operation, error) {
+	args := m.Called(ctx, operationID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*orchestrator.Operation), args.Error(1)
+}
+
+func (m *MockCalcUseCase) ListCalculations(ctx context.Context, userID uuid.UUID, filter orchestrator.CalculationFilter) (*orchestrator.CalculationPage, error) {
+	args := m.Called(ctx, userID, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*orchestrator.CalculationPage), args.Error(1)
+}
+
+func (m *MockCalcUseCase) GetResultStats(ctx context.Context, userID uuid.UUID) (*orchestrator.ResultStats, error) {
 	args := m.Called(ctx, userID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]*orchestrator.Calculation), args.Error(1)
+	return args.Get(0).(*orchestrator.ResultStats), args.Error(1)
+}
+
+func (m *MockCalcUseCase) CancelCalculation(ctx context.Context, calculationID uuid.UUID, userID uuid.UUID) error {
+	args := m.Called(ctx, calculationID, userID)
+	return args.Error(0)
+}
+
+func (m *MockCalcUseCase) DeleteCalculation(ctx context.Context, calculationID uuid.UUID, userID uuid.UUID) error {
+	args := m.Called(ctx, calculationID, userID)
+	return args.Error(0)
+}
+
+func (m *MockCalcUseCase) SubscribeCalculation(ctx context.Context, calculationID uuid.UUID, userID uuid.UUID) (<-chan orchestrator.CalculationEvent, error) {
+	args := m.Called(ctx, calculationID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(<-chan orchestrator.CalculationEvent), args.Error(1)
 }
 
 func (m *MockCalcUseCase) UpdateCalculationStatus(ctx context.Context, calculationID uuid.UUID) error {
@@ -151,6 +266,70 @@ func (m *MockCalcUseCase) ProcessPendingOperations(ctx context.Context) error {
 	return args.Error(0)
 }
 
+func (m *MockCalcUseCase) CompareExpressions(ctx context.Context, expressionA, expressionB string, tolerance float64) (*orchestrator.ExpressionComparison, error) {
+	args := m.Called(ctx, expressionA, expressionB, tolerance)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*orchestrator.ExpressionComparison), args.Error(1)
+}
+
+func (m *MockCalcUseCase) PreviewExpression(ctx context.Context, expression string, options orchestrator.PreviewOptions) (*orchestrator.ExpressionPreview, error) {
+	args := m.Called(ctx, expression, options)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*orchestrator.ExpressionPreview), args.Error(1)
+}
+
+func (m *MockCalcUseCase) DiffCalculations(ctx context.Context, calculationIDA, calculationIDB uuid.UUID, userID uuid.UUID) (*orchestrator.CalculationDiff, error) {
+	args := m.Called(ctx, calculationIDA, calculationIDB, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*orchestrator.CalculationDiff), args.Error(1)
+}
+
+func (m *MockCalcUseCase) ValidateExpression(ctx context.Context, expression string) (*orchestrator.ExpressionValidation, error) {
+	args := m.Called(ctx, expression)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*orchestrator.ExpressionValidation), args.Error(1)
+}
+
+func (m *MockCalcUseCase) GetPoolStats(ctx context.Context) (*agent.PoolStats, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*agent.PoolStats), args.Error(1)
+}
+
+func (m *MockCalcUseCase) GetTimingProfile(ctx context.Context) (*agent.TimingProfile, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*agent.TimingProfile), args.Error(1)
+}
+
+func (m *MockCalcUseCase) SetAgentCapacity(ctx context.Context, agentID string, capacity int) (*agent.AgentStats, error) {
+	args := m.Called(ctx, agentID, capacity)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*agent.AgentStats), args.Error(1)
+}
+
+func (m *MockCalcUseCase) GetSystemStats(ctx context.Context) (*system.OrchestratorStats, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*system.OrchestratorStats), args.Error(1)
+}
+
 func (m *MockCalcUseCase) Close() error {
 	args := m.Called()
 	return args.Error(0)
@@ -207,7 +386,7 @@ func (m *MockAgentPool) GetAvailableAgent(operationType int) (*agent.Agent, erro
 	return args.Get(0).(*agent.Agent), args.Error(1)
 }
 
-func (m *MockAgentPool) AssignOperation(agentID string, operation *orchestrator.Operation) error {
+func (m *MockAgentPool) AssignOperation(_ context.Context, agentID string, operation *orchestrator.Operation) error {
 	args := m.Called(agentID, operation)
 	return args.Error(0)
 }
@@ -228,6 +407,84 @@ func (m *MockAgentPool) ListAgents() ([]*agent.Agent, error) {
 	return args.Get(0).([]*agent.Agent), args.Error(1)
 }
 
+func (m *MockAgentPool) SetAgentCapacity(agentID string, capacity int) (*agent.AgentStats, error) {
+	args := m.Called(agentID, capacity)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*agent.AgentStats), args.Error(1)
+}
+
+func (m *MockAgentPool) GetPoolStats() (*agent.PoolStats, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*agent.PoolStats), args.Error(1)
+}
+
+func (m *MockAgentPool) GetTimingProfile() (*agent.TimingProfile, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*agent.TimingProfile), args.Error(1)
+}
+
+func TestNewProcessorSafe(t *testing.T) {
+	type deps struct {
+		opRepo      orchrepo.OperationRepository
+		calcRepo    orchrepo.CalculationRepository
+		calcUseCase orchapi.UseCaseCalculation
+		opExecutor  orchapi.OperationExecutor
+		agentPool   orchapi.AgentPool
+	}
+	complete := func() deps {
+		return deps{
+			opRepo:      new(MockOperationRepository),
+			calcRepo:    new(MockCalculationRepository),
+			calcUseCase: new(MockCalcUseCase),
+			opExecutor:  new(MockOperationExecutor),
+			agentPool:   new(MockAgentPool),
+		}
+	}
+
+	testCases := []struct {
+		name       string
+		modify     func(*deps)
+		dependency string
+	}{
+		{name: "Nil operation repository", modify: func(d *deps) { d.opRepo = nil }, dependency: "operation repository"},
+		{name: "Nil calculation repository", modify: func(d *deps) { d.calcRepo = nil }, dependency: "calculation repository"},
+		{name: "Nil calculation use case", modify: func(d *deps) { d.calcUseCase = nil }, dependency: "calculation use case"},
+		{name: "Nil operation executor", modify: func(d *deps) { d.opExecutor = nil }, dependency: "operation executor"},
+		{name: "Nil agent pool", modify: func(d *deps) { d.agentPool = nil }, dependency: "agent pool"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := complete()
+			tc.modify(&d)
+
+			proc, err := processor.NewProcessorSafe(d.opRepo, d.calcRepo, d.calcUseCase, processor.AgentConfig{}, d.opExecutor, d.agentPool)
+
+			require.ErrorIs(t, err, domainerrors.ErrNilDependency)
+			assert.Contains(t, err.Error(), tc.dependency)
+			assert.Nil(t, proc)
+			assert.PanicsWithValue(t, err.Error(), func() {
+				processor.NewProcessor(d.opRepo, d.calcRepo, d.calcUseCase, processor.AgentConfig{}, d.opExecutor, d.agentPool)
+			})
+		})
+	}
+
+	t.Run("All dependencies set", func(t *testing.T) {
+		d := complete()
+		proc, err := processor.NewProcessorSafe(d.opRepo, d.calcRepo, d.calcUseCase, processor.AgentConfig{}, d.opExecutor, d.agentPool)
+		require.NoError(t, err)
+		assert.NotNil(t, proc)
+	})
+}
+
 func TestAssignOperationToAgent(t *testing.T) {
 	operationID := uuid.New()
 
@@ -342,3 +599,444 @@ func TestAssignOperationToAgent(t *testing.T) {
 		})
 	}
 }
+
+func TestCancelOperations(t *testing.T) {
+	calcUseCase := new(MockCalcUseCase)
+	calcUseCase.On("Close").Return(nil)
+
+	proc := processor.NewProcessor(
+		new(MockOperationRepository),
+		new(MockCalculationRepository),
+		calcUseCase,
+		processor.AgentConfig{AgentID: "test-agent", ComputerPower: 5},
+		new(MockOperationExecutor),
+		new(MockAgentPool),
+	)
+
+	cancelledID := uuid.New()
+	otherID := uuid.New()
+
+	assert.False(t, proc.IsCancelled(cancelledID))
+
+	proc.CancelOperations(cancelledID, uuid.Nil)
+
+	assert.True(t, proc.IsCancelled(cancelledID))
+	assert.False(t, proc.IsCancelled(otherID))
+	assert.False(t, proc.IsCancelled(uuid.Nil))
+}
+
+type MockDeadLetterRepository struct {
+	mock.Mock
+}
+
+func (m *MockDeadLetterRepository) Create(ctx context.Context, deadLetter *orchestrator.DeadLetter) error {
+	args := m.Called(ctx, deadLetter)
+	return args.Error(0)
+}
+
+func (m *MockDeadLetterRepository) FindPending(ctx context.Context, limit int) ([]*orchestrator.DeadLetter, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*orchestrator.DeadLetter), args.Error(1)
+}
+
+func (m *MockDeadLetterRepository) MarkReplayed(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func TestHandleOperationErrorDeadLetter(t *testing.T) {
+	operation := &orchestrator.Operation{
+		ID:            uuid.New(),
+		CalculationID: uuid.New(),
+		OperationType: orchestrator.OperationTypeDivision,
+		Operand1:      "6",
+		Operand2:      "ref:" + uuid.NewString(),
+	}
+	execErr := domainerrors.ErrNoAgentAvailable
+
+	newProcessor := func(opRepo *MockOperationRepository, calcRepo *MockCalculationRepository) *processor.OperationProcessor {
+		calcUseCase := new(MockCalcUseCase)
+		calcUseCase.On("UpdateCalculationStatus", mock.Anything, operation.CalculationID).Return(nil)
+
+		opRepo.On("UpdateStatus", mock.Anything, operation.ID, orchestrator.OperationStatusError, "", mock.Anything).Return(nil)
+
+		return processor.NewProcessor(
+			opRepo,
+			calcRepo,
+			calcUseCase,
+			processor.AgentConfig{AgentID: "test-agent", ComputerPower: 1},
+			new(MockOperationExecutor),
+			new(MockAgentPool),
+		)
+	}
+
+	t.Run("Stores dead letter with context", func(t *testing.T) {
+		opRepo := new(MockOperationRepository)
+		calcRepo := new(MockCalculationRepository)
+		deadLetterRepo := new(MockDeadLetterRepository)
+
+		calcRepo.On("FindByID", mock.Anything, operation.CalculationID).Return(&orchestrator.Calculation{
+			ID:         operation.CalculationID,
+			Expression: "6/(2-2)",
+		}, nil)
+		deadLetterRepo.On("Create", mock.Anything, mock.MatchedBy(func(dl *orchestrator.DeadLetter) bool {
+			return dl.OperationID == operation.ID &&
+				dl.CalculationID == operation.CalculationID &&
+				dl.Expression == "6/(2-2)" &&
+				dl.OperationType == operation.OperationType &&
+				dl.Operand1 == operation.Operand1 &&
+				dl.Operand2 == operation.Operand2 &&
+				assert.ObjectsAreEqual([]string{execErr.Error()}, dl.ErrorHistory) &&
+				!dl.Deterministic
+		})).Return(nil)
+
+		proc := newProcessor(opRepo, calcRepo)
+		proc.SetDeadLetterRepository(deadLetterRepo)
+
+		proc.ExportHandleOperationError(context.Background(), operation, execErr)
+
+		deadLetterRepo.AssertExpectations(t)
+		calcRepo.AssertExpectations(t)
+		opRepo.AssertExpectations(t)
+	})
+
+	t.Run("Marks deterministic failure", func(t *testing.T) {
+		opRepo := new(MockOperationRepository)
+		calcRepo := new(MockCalculationRepository)
+		deadLetterRepo := new(MockDeadLetterRepository)
+
+		calcRepo.On("FindByID", mock.Anything, operation.CalculationID).Return(nil, nil)
+		deadLetterRepo.On("Create", mock.Anything, mock.MatchedBy(func(dl *orchestrator.DeadLetter) bool {
+			return dl.OperationID == operation.ID && dl.Deterministic
+		})).Return(nil)
+
+		proc := newProcessor(opRepo, calcRepo)
+		proc.SetDeadLetterRepository(deadLetterRepo)
+
+		proc.ExportHandleOperationError(context.Background(), operation,
+			fmt.Errorf("%w: %w", domainerrors.ErrOperationAssignment, domainerrors.ErrDivisionByZero))
+
+		deadLetterRepo.AssertExpectations(t)
+	})
+
+	t.Run("Stores dead letter when calculation lookup fails", func(t *testing.T) {
+		opRepo := new(MockOperationRepository)
+		calcRepo := new(MockCalculationRepository)
+		deadLetterRepo := new(MockDeadLetterRepository)
+
+		calcRepo.On("FindByID", mock.Anything, operation.CalculationID).Return(nil, errors.New("db down"))
+		deadLetterRepo.On("Create", mock.Anything, mock.MatchedBy(func(dl *orchestrator.DeadLetter) bool {
+			return dl.OperationID == operation.ID && dl.Expression == ""
+		})).Return(nil)
+
+		proc := newProcessor(opRepo, calcRepo)
+		proc.SetDeadLetterRepository(deadLetterRepo)
+
+		proc.ExportHandleOperationError(context.Background(), operation, execErr)
+
+		deadLetterRepo.AssertExpectations(t)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		opRepo := new(MockOperationRepository)
+		calcRepo := new(MockCalculationRepository)
+
+		proc := newProcessor(opRepo, calcRepo)
+
+		proc.ExportHandleOperationError(context.Background(), operation, execErr)
+
+		calcRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+		opRepo.AssertExpectations(t)
+	})
+}
+
+func TestProcessOperationTimeout(t *testing.T) {
+	operation := &orchestrator.Operation{
+		ID:            uuid.New(),
+		CalculationID: uuid.New(),
+		OperationType: orchestrator.OperationTypeAddition,
+		Status:        orchestrator.OperationStatusPending,
+	}
+
+	opRepo := new(MockOperationRepository)
+	calcUseCase := new(MockCalcUseCase)
+	agentPool := new(MockAgentPool)
+
+	opRepo.On("GetPendingOperations", mock.Anything, 1).Return([]*orchestrator.Operation{operation}, nil).Once()
+	opRepo.On("GetPendingOperations", mock.Anything, 1).Return([]*orchestrator.Operation{}, nil)
+	agentPool.On("GetAvailableAgent", int(orchestrator.OperationTypeAddition)).Return(nil, domainerrors.ErrNoAgentAvailable)
+
+	// Срок истекает во время ожидания повторной попытки
+	updated := make(chan string, 1)
+	opRepo.On("UpdateStatus", mock.Anything, operation.ID, orchestrator.OperationStatusError, "", mock.Anything).
+		Return(nil).Run(func(args mock.Arguments) { updated <- args.String(4) }).Once()
+	recomputed := make(chan struct{}, 1)
+	calcUseCase.On("UpdateCalculationStatus", mock.Anything, operation.CalculationID).
+		Return(nil).Run(func(mock.Arguments) { recomputed <- struct{}{} }).Once()
+
+	proc := processor.NewProcessor(
+		opRepo,
+		new(MockCalculationRepository),
+		calcUseCase,
+		processor.AgentConfig{
+			AgentID:           "test-agent",
+			ComputerPower:     1,
+			OperationTimeouts: map[string]time.Duration{"addition": 50 * time.Millisecond},
+		},
+		new(MockOperationExecutor),
+		agentPool,
+	)
+	proc.SetRetryPolicy(retry.Policy{MaxAttempts: 3, BaseDelay: time.Second, Multiplier: 1})
+
+	ctx, cancel := context.WithCancel(logger.WithLogger(context.Background(), logger.New(zapcore.NewNopCore())))
+	defer cancel()
+	require.NoError(t, proc.Start(ctx))
+	defer proc.Stop()
+
+	select {
+	case msg := <-updated:
+		assert.Equal(t, domainerrors.ErrOperationTimeout.Error(), msg)
+	case <-time.After(time.Second):
+		t.Fatal("operation was not failed at its deadline")
+	}
+	select {
+	case <-recomputed:
+	case <-time.After(time.Second):
+		t.Fatal("calculation status was not recomputed")
+	}
+}
+
+func TestDispatchDryRun(t *testing.T) {
+	newOperation := func() *orchestrator.Operation {
+		return &orchestrator.Operation{
+			ID:            uuid.New(),
+			CalculationID: uuid.New(),
+			OperationType: orchestrator.OperationTypeAddition,
+			Status:        orchestrator.OperationStatusPending,
+		}
+	}
+	operations := []*orchestrator.Operation{newOperation(), newOperation(), newOperation(), newOperation(), newOperation()}
+	cancelledOp := operations[4]
+
+	agents := []*agent.Agent{
+		{ID: "agent-1", Status: agent.AgentStatusOnline, CurrentLoad: 0, MaxCapacity: 2},
+		{ID: "agent-2", Status: agent.AgentStatusBusy, CurrentLoad: 1, MaxCapacity: 2},
+		{ID: "agent-3", Status: agent.AgentStatusOffline, CurrentLoad: 0, MaxCapacity: 10},
+	}
+
+	var polls atomic.Int32
+	newProcessor := func() (*processor.OperationProcessor, *MockOperationRepository, *MockOperationExecutor, *MockAgentPool) {
+		opRepo := new(MockOperationRepository)
+		executor := new(MockOperationExecutor)
+		agentPool := new(MockAgentPool)
+
+		polls.Store(0)
+		opRepo.On("GetPendingOperations", mock.Anything, 5).Run(func(mock.Arguments) { polls.Add(1) }).Return(operations, nil)
+		agentPool.On("ListAgents").Return(agents, nil)
+
+		proc := processor.NewProcessor(
+			opRepo,
+			new(MockCalculationRepository),
+			new(MockCalcUseCase),
+			processor.AgentConfig{AgentID: "test-agent", ComputerPower: 5},
+			executor,
+			agentPool,
+		)
+		proc.SetDryRun(true)
+		proc.CancelOperations(cancelledOp.ID)
+		return proc, opRepo, executor, agentPool
+	}
+
+	t.Run("Plan follows least loaded agent with capacity", func(t *testing.T) {
+		proc, opRepo, _, agentPool := newProcessor()
+
+		plan, err := proc.PlanDispatch(context.Background())
+		require.NoError(t, err)
+		require.Len(t, plan, len(operations))
+
+		expected := []struct{ agentID, reason string }{
+			{"agent-1", ""},
+			{"agent-1", ""},
+			{"agent-2", ""},
+			{"", orchestrator.DispatchReasonNoCapacity},
+			{"", orchestrator.DispatchReasonCancelled},
+		}
+		for i, want := range expected {
+			assert.Equal(t, operations[i].ID, plan[i].OperationID)
+			assert.Equal(t, want.agentID, plan[i].AgentID, "operation %d", i)
+			assert.Equal(t, want.reason, plan[i].Reason, "operation %d", i)
+		}
+
+		assert.Equal(t, 0, agents[0].CurrentLoad, "planning must not modify pool snapshot")
+		opRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		opRepo.AssertNotCalled(t, "AssignAgent", mock.Anything, mock.Anything, mock.Anything)
+		agentPool.AssertNotCalled(t, "AssignOperation", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Processor logs plan without executing", func(t *testing.T) {
+		proc, opRepo, executor, agentPool := newProcessor()
+
+		core, logs := observer.New(zapcore.InfoLevel)
+		ctx, cancel := context.WithCancel(logger.WithLogger(context.Background(), logger.New(core)))
+		defer cancel()
+
+		require.NoError(t, proc.Start(ctx))
+		assert.Eventually(t, func() bool {
+			return polls.Load() >= 3
+		}, time.Second, 10*time.Millisecond, "processor must poll pending operations several times")
+		proc.Stop()
+		cancel()
+
+		dispatched := logs.FilterMessage("Dry run: operation would be dispatched").All()
+		skipped := logs.FilterMessage("Dry run: operation would not be dispatched").All()
+		assert.Len(t, dispatched, 3, "unchanged plan must be logged once")
+		assert.Len(t, skipped, 2)
+		assert.Equal(t, "agent-1", dispatched[0].ContextMap()["agent_id"])
+
+		for _, op := range operations {
+			assert.Equal(t, orchestrator.OperationStatusPending, op.Status)
+		}
+		opRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		executor.AssertNotCalled(t, "ExecuteOperation", mock.Anything, mock.Anything)
+		agentPool.AssertNotCalled(t, "AssignOperation", mock.Anything, mock.Anything)
+	})
+}
+
+func TestDispatchPriority(t *testing.T) {
+	newOperation := func(priority int) *orchestrator.Operation {
+		return &orchestrator.Operation{
+			ID:            uuid.New(),
+			CalculationID: uuid.New(),
+			OperationType: orchestrator.OperationTypeAddition,
+			Status:        orchestrator.OperationStatusPending,
+			Priority:      priority,
+		}
+	}
+	low, lower, high := newOperation(0), newOperation(0), newOperation(5)
+
+	newProcessor := func() *processor.OperationProcessor {
+		opRepo := new(MockOperationRepository)
+		agentPool := new(MockAgentPool)
+
+		// Репозиторий возвращает операции не по приоритету: порядок должен восстановить процессор
+		opRepo.On("GetPendingOperations", mock.Anything, 5).Return([]*orchestrator.Operation{low, high, lower}, nil)
+		agentPool.On("ListAgents").Return([]*agent.Agent{
+			{ID: "agent-1", Status: agent.AgentStatusOnline, MaxCapacity: 1},
+		}, nil)
+
+		proc := processor.NewProcessor(
+			opRepo,
+			new(MockCalculationRepository),
+			new(MockCalcUseCase),
+			processor.AgentConfig{AgentID: "test-agent", ComputerPower: 5},
+			new(MockOperationExecutor),
+			agentPool,
+		)
+		proc.SetDryRun(true)
+		return proc
+	}
+
+	t.Run("Plan takes high-priority operation first", func(t *testing.T) {
+		plan, err := newProcessor().PlanDispatch(context.Background())
+		require.NoError(t, err)
+		require.Len(t, plan, 3)
+
+		assert.Equal(t, high.ID, plan[0].OperationID)
+		assert.Equal(t, "agent-1", plan[0].AgentID)
+		assert.Equal(t, []uuid.UUID{low.ID, lower.ID}, []uuid.UUID{plan[1].OperationID, plan[2].OperationID},
+			"operations with equal priority keep repository order")
+		assert.Equal(t, orchestrator.DispatchReasonNoCapacity, plan[1].Reason)
+	})
+
+	t.Run("Processing loop dispatches high-priority operation first", func(t *testing.T) {
+		proc := newProcessor()
+
+		core, logs := observer.New(zapcore.InfoLevel)
+		ctx, cancel := context.WithCancel(logger.WithLogger(context.Background(), logger.New(core)))
+		defer cancel()
+
+		require.NoError(t, proc.Start(ctx))
+		dispatchedLogs := func() []observer.LoggedEntry {
+			return logs.FilterMessage("Dry run: operation would be dispatched").All()
+		}
+		require.Eventually(t, func() bool {
+			return len(dispatchedLogs()) > 0
+		}, time.Second, 10*time.Millisecond)
+		proc.Stop()
+		cancel()
+
+		dispatched := dispatchedLogs()
+		require.Len(t, dispatched, 1)
+		assert.Equal(t, high.ID.String(), dispatched[0].ContextMap()["operation_id"])
+	})
+}
+
+func TestProcessorReadinessCheck(t *testing.T) {
+	newProcessor := func(polls *atomic.Int32) *processor.OperationProcessor {
+		opRepo := new(MockOperationRepository)
+		opRepo.On("GetPendingOperations", mock.Anything, 5).Run(func(mock.Arguments) { polls.Add(1) }).Return([]*orchestrator.Operation{}, nil)
+
+		return processor.NewProcessor(
+			opRepo,
+			new(MockCalculationRepository),
+			new(MockCalcUseCase),
+			processor.AgentConfig{AgentID: "test-agent", ComputerPower: 5},
+			new(MockOperationExecutor),
+			new(MockAgentPool),
+		)
+	}
+
+	t.Run("Processing waits until schema is ready", func(t *testing.T) {
+		var polls, checks atomic.Int32
+		var ready atomic.Bool
+		proc := newProcessor(&polls)
+		proc.SetReadinessCheck(func(context.Context) error {
+			checks.Add(1)
+			if !ready.Load() {
+				return errors.New("schema version 3, expected 6")
+			}
+			return nil
+		}, 10*time.Millisecond)
+
+		core, logs := observer.New(zapcore.InfoLevel)
+		ctx, cancel := context.WithCancel(logger.WithLogger(context.Background(), logger.New(core)))
+		defer cancel()
+
+		require.NoError(t, proc.Start(ctx))
+		assert.Eventually(t, func() bool {
+			return checks.Load() >= 3
+		}, time.Second, 5*time.Millisecond, "readiness must be rechecked")
+		assert.Zero(t, polls.Load(), "pending operations must not be polled before schema is ready")
+
+		ready.Store(true)
+		assert.Eventually(t, func() bool {
+			return polls.Load() > 0
+		}, time.Second, 10*time.Millisecond, "processing must start once schema is ready")
+		proc.Stop()
+
+		assert.NotEmpty(t, logs.FilterMessage("Operation processor is not ready, postponing processing").All())
+		assert.Len(t, logs.FilterMessage("Operation processor is ready").All(), 1)
+	})
+
+	t.Run("Stop while waiting for schema", func(t *testing.T) {
+		var polls atomic.Int32
+		proc := newProcessor(&polls)
+		proc.SetReadinessCheck(func(context.Context) error {
+			return errors.New("schema is not ready")
+		}, 10*time.Millisecond)
+
+		ctx, cancel := context.WithCancel(logger.WithLogger(context.Background(), logger.New(zapcore.NewNopCore())))
+		defer cancel()
+
+		require.NoError(t, proc.Start(ctx))
+		proc.Stop()
+		assert.Eventually(t, func() bool {
+			return !proc.IsRunning()
+		}, time.Second, 10*time.Millisecond)
+		time.Sleep(50 * time.Millisecond)
+		assert.Zero(t, polls.Load())
+	})
+}
@@ -0,0 +1,36 @@
+package errors
+
+import "errors"
+
+// CodeInternal - код ошибок без собственного кода, в том числе ошибок вне домена.
+const CodeInternal = "INTERNAL_ERROR"
+
+// Error - доменная ошибка со стабильным кодом для клиентов API. Ошибки сравниваются
+// по значению переменной через errors.Is, код не участвует в сравнении.
+type Error struct {
+	code    string
+	message string
+}
+
+func newError(code, message string) error {
+	return &Error{code: code, message: message}
+}
+
+func (e *Error) Error() string {
+	return e.message
+}
+
+// Code возвращает стабильный код ошибки, например CALC_NOT_FOUND.
+func (e *Error) Code() string {
+	return e.code
+}
+
+// Code возвращает код первой доменной ошибки в цепочке err или пустую строку,
+// если доменной ошибки в ней нет.
+func Code(err error) string {
+	var coded interface{ Code() string }
+	if errors.As(err, &coded) {
+		return coded.Code()
+	}
+	return ""
+}
@@ -0,0 +1,21 @@
+package errors_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCode(t *testing.T) {
+	assert.Equal(t, "CALC_NOT_FOUND", domainerrors.Code(domainerrors.ErrCalculationNotFound))
+	assert.Equal(t, "AUTH_INVALID_CREDENTIALS", domainerrors.Code(fmt.Errorf("login: %w", domainerrors.ErrInvalidCredentials)))
+	assert.Empty(t, domainerrors.Code(errors.New("plain error")))
+	assert.Empty(t, domainerrors.Code(nil))
+
+	// Ошибки с одинаковым кодом остаются разными ошибками
+	assert.Equal(t, domainerrors.Code(domainerrors.ErrInternalError), domainerrors.Code(domainerrors.ErrInternalServerError))
+	assert.NotErrorIs(t, domainerrors.ErrInternalError, domainerrors.ErrInternalServerError)
+}
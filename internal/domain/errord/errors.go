@@ -1,70 +1,95 @@
-// Package errors содержит ошибки для домена.
+// Package errors содержит ошибки для домена. Каждая ошибка несет стабильный код,
+// по которому клиенты API различают ошибки без разбора сообщений, см. Code.
 package errors
 
-import "errors"
-
 var (
-	ErrNoAgentsAvailable    = errors.New("no agents available for operation")
-	ErrOperationFailed      = errors.New("operation execution failed")
-	ErrMaxRetriesExceeded   = errors.New("maximum retries exceeded")
-	ErrContextCanceled      = errors.New("context canceled")
-	ErrNilOperation         = errors.New("operation cannot be nil")
-	ErrNilPool              = errors.New("agent pool cannot be nil")
-	ErrInvalidOperationID   = errors.New("invalid operation ID")
-	ErrAgentNotFound        = errors.New("agent not found")
-	ErrInvalidCapacity      = errors.New("invalid agent capacity")
-	ErrNilStorage           = errors.New("agent storage cannot be nil")
-	ErrNilOperationRepo     = errors.New("operation repository cannot be nil")
-	ErrPoolAlreadyStopped   = errors.New("agent pool already stopped")
-	ErrOperationAssignment  = errors.New("failed to assign operation to agent")
-	ErrInvalidOperationType = errors.New("invalid operation type")
-	ErrPoolNotRunning       = errors.New("agent pool is not running")
-	ErrNilWorkerStatus      = errors.New("worker returned nil status")
-	ErrAgentNotRunning      = errors.New("agent is not running or not online")
-	ErrAgentAtCapacity      = errors.New("agent is at full capacity")
-	ErrQueueFull            = errors.New("operation queue is full")
-	ErrInvalidOperand       = errors.New("invalid operand")
-	ErrDivisionByZero       = errors.New("division by zero")
-	ErrUnsupportedOp        = errors.New("unsupported operation type")
-	ErrRepoNotInitialized   = errors.New("operation repository not initialized")
-	ErrInvalidReferenceID   = errors.New("invalid reference ID")
-	ErrReferenceNotFound    = errors.New("referenced operation not found")
-	ErrRefNotCompleted      = errors.New("referenced operation not completed")
+	ErrNoAgentsAvailable    = newError("AGENT_UNAVAILABLE", "no agents available for operation")
+	ErrOperationFailed      = newError("OPERATION_FAILED", "operation execution failed")
+	ErrMaxRetriesExceeded   = newError("OPERATION_MAX_RETRIES_EXCEEDED", "maximum retries exceeded")
+	ErrContextCanceled      = newError("CONTEXT_CANCELED", "context canceled")
+	ErrNilOperation         = newError("INTERNAL_ERROR", "operation cannot be nil")
+	ErrNilPool              = newError("INTERNAL_ERROR", "agent pool cannot be nil")
+	ErrInvalidOperationID   = newError("OPERATION_INVALID_ID", "invalid operation ID")
+	ErrAgentNotFound        = newError("AGENT_NOT_FOUND", "agent not found")
+	ErrAgentAlreadyExists   = newError("AGENT_ALREADY_EXISTS", "agent already exists")
+	ErrInvalidCapacity      = newError("AGENT_INVALID_CAPACITY", "invalid agent capacity")
+	ErrNilStorage           = newError("INTERNAL_ERROR", "agent storage cannot be nil")
+	ErrNilOperationRepo     = newError("INTERNAL_ERROR", "operation repository cannot be nil")
+	ErrPoolAlreadyStopped   = newError("AGENT_POOL_STOPPED", "agent pool already stopped")
+	ErrOperationAssignment  = newError("AGENT_ASSIGNMENT_FAILED", "failed to assign operation to agent")
+	ErrInvalidOperationType = newError("OPERATION_INVALID_TYPE", "invalid operation type")
+	ErrPoolNotRunning       = newError("AGENT_POOL_NOT_RUNNING", "agent pool is not running")
+	ErrPoolDraining         = newError("AGENT_POOL_DRAINING", "agent pool is draining")
+	ErrNilWorkerStatus      = newError("INTERNAL_ERROR", "worker returned nil status")
+	ErrAgentNotRunning      = newError("AGENT_NOT_RUNNING", "agent is not running or not online")
+	ErrAgentAtCapacity      = newError("AGENT_AT_CAPACITY", "agent is at full capacity")
+	ErrQueueFull            = newError("AGENT_QUEUE_FULL", "operation queue is full")
+	ErrInvalidOperationTime = newError("OPERATION_INVALID_TIME", "invalid operation time")
+	ErrInvalidOperand       = newError("CALC_INVALID_OPERAND", "invalid operand")
+	ErrDivisionByZero       = newError("CALC_DIVISION_BY_ZERO", "division by zero")
+	ErrNumericOverflow      = newError("CALC_NUMERIC_OVERFLOW", "numeric overflow")
+	ErrFactorialOperand     = newError("CALC_INVALID_FACTORIAL_OPERAND", "factorial operand must be a non-negative integer")
+	ErrUnsupportedOp        = newError("CALC_UNSUPPORTED_OPERATION", "unsupported operation type")
+	ErrRepoNotInitialized   = newError("INTERNAL_ERROR", "operation repository not initialized")
+	ErrInvalidReferenceID   = newError("OPERATION_INVALID_REFERENCE", "invalid reference ID")
+	ErrReferenceNotFound    = newError("OPERATION_REFERENCE_NOT_FOUND", "referenced operation not found")
+	ErrRefNotCompleted      = newError("OPERATION_REFERENCE_NOT_COMPLETED", "referenced operation not completed")
 )
 
 var (
-	ErrUserAlreadyExists   = errors.New("user already exists")
-	ErrInvalidCredentials  = errors.New("invalid login or password")
-	ErrUserNotFound        = errors.New("user not found")
-	ErrInvalidToken        = errors.New("invalid token")
-	ErrTokenExpired        = errors.New("token expired")
-	ErrTokenNotFound       = errors.New("token not found")
-	ErrTokenRevoked        = errors.New("token revoked")
-	ErrInternalServerError = errors.New("internal server error")
+	ErrUserAlreadyExists   = newError("AUTH_USER_ALREADY_EXISTS", "user already exists")
+	ErrInvalidCredentials  = newError("AUTH_INVALID_CREDENTIALS", "invalid login or password")
+	ErrTooManyAttempts     = newError("AUTH_TOO_MANY_ATTEMPTS", "too many failed login attempts")
+	ErrUserNotFound        = newError("AUTH_USER_NOT_FOUND", "user not found")
+	ErrInvalidToken        = newError("AUTH_INVALID_TOKEN", "invalid token")
+	ErrTokenExpired        = newError("AUTH_TOKEN_EXPIRED", "token expired")
+	ErrTokenNotFound       = newError("AUTH_TOKEN_NOT_FOUND", "token not found")
+	ErrTokenRevoked        = newError("AUTH_TOKEN_REVOKED", "token revoked")
+	ErrSessionTooOld       = newError("AUTH_SESSION_TOO_OLD", "session exceeded maximum age, login required")
+	ErrInternalServerError = newError("INTERNAL_ERROR", "internal server error")
+	ErrSamePassword        = newError("AUTH_SAME_PASSWORD", "new password must differ from the old one")
+	ErrWeakPassword        = newError("AUTH_WEAK_PASSWORD", "password does not meet the password policy")
 )
 
 var (
-	ErrInvalidExpression       = errors.New("invalid expression")
-	ErrInvalidUserID           = errors.New("invalid user ID")
-	ErrCalculationNotFound     = errors.New("calculation not found")
-	ErrUnauthorizedAccess      = errors.New("unauthorized access to calculation")
-	ErrOperationCreationFailed = errors.New("failed to create operations")
-	ErrInternalError           = errors.New("internal server error")
-	ErrUseCaseNil              = errors.New("use case is nil")
-	ErrCalcRepoNil             = errors.New("calculation repository is nil")
-	ErrOpRepoNil               = errors.New("operation repository is nil")
-	ErrSpecificCalcNotFound    = errors.New("calculation not found with ID")
-	ErrTooManyOps              = errors.New("expression too complex, too many operations")
-	ErrCreateOps               = errors.New("failed to create operations")
-	ErrInvalidOperation        = errors.New("invalid operation")
-	ErrOperationNotFound       = errors.New("operation not found")
-	ErrNilDependency           = errors.New("nil dependency provided")
-	ErrPanic                   = errors.New("panic in operation")
-	ErrContextDone             = errors.New("context canceled")
-	ErrNilExecutor             = errors.New("operation executor cannot be nil")
-	ErrNilRepository           = errors.New("repository cannot be nil")
-	ErrEvalError               = errors.New("expression evaluation error")
-	ErrPoolAssignFailure       = errors.New("failed to assign operation to agent")
-	ErrNoAgentAvailable        = errors.New("no agent available for operation")
-	ErrInvalidArgs             = errors.New("invalid arguments")
+	ErrInvalidExpression       = newError("CALC_INVALID_EXPRESSION", "invalid expression")
+	ErrInvalidUserID           = newError("CALC_INVALID_USER_ID", "invalid user ID")
+	ErrCalculationNotFound     = newError("CALC_NOT_FOUND", "calculation not found")
+	ErrUnauthorizedAccess      = newError("CALC_FORBIDDEN", "unauthorized access to calculation")
+	ErrOperationCreationFailed = newError("CALC_OPERATIONS_CREATE_FAILED", "failed to create operations")
+	ErrInternalError           = newError("INTERNAL_ERROR", "internal server error")
+	ErrUseCaseNil              = newError("INTERNAL_ERROR", "use case is nil")
+	ErrCalcRepoNil             = newError("INTERNAL_ERROR", "calculation repository is nil")
+	ErrOpRepoNil               = newError("INTERNAL_ERROR", "operation repository is nil")
+	ErrSpecificCalcNotFound    = newError("CALC_NOT_FOUND", "calculation not found with ID")
+	ErrTooManyOps              = newError("CALC_TOO_MANY_OPERATIONS", "expression too complex, too many operations")
+	ErrDuplicateOperationID    = newError("OPERATION_DUPLICATE_ID", "duplicate operation ID")
+	ErrCreateOps               = newError("CALC_OPERATIONS_CREATE_FAILED", "failed to create operations")
+	ErrInvalidOperation        = newError("OPERATION_INVALID", "invalid operation")
+	ErrOperationNotFound       = newError("OPERATION_NOT_FOUND", "operation not found")
+	ErrNilDependency           = newError("INTERNAL_ERROR", "nil dependency provided")
+	ErrPanic                   = newError("INTERNAL_ERROR", "panic in operation")
+	ErrContextDone             = newError("CONTEXT_CANCELED", "context canceled")
+	ErrNilExecutor             = newError("INTERNAL_ERROR", "operation executor cannot be nil")
+	ErrNilRepository           = newError("INTERNAL_ERROR", "repository cannot be nil")
+	ErrEvalError               = newError("CALC_EVALUATION_FAILED", "expression evaluation error")
+	ErrPoolAssignFailure       = newError("AGENT_ASSIGNMENT_FAILED", "failed to assign operation to agent")
+	ErrNoAgentAvailable        = newError("AGENT_UNAVAILABLE", "no agent available for operation")
+	ErrInvalidArgs             = newError("INVALID_ARGUMENT", "invalid arguments")
+	ErrInvalidSource           = newError("CALC_INVALID_SOURCE", "invalid calculation source")
+	ErrInvalidPriority         = newError("CALC_INVALID_PRIORITY", "invalid calculation priority")
+	ErrInvalidIdempotencyKey   = newError("CALC_INVALID_IDEMPOTENCY_KEY", "invalid idempotency key")
+	ErrIdempotencyKeyConflict  = newError("CALC_IDEMPOTENCY_KEY_CONFLICT", "idempotency key is already used")
+	ErrInvalidPagination       = newError("INVALID_PAGINATION", "invalid pagination parameters")
+	ErrInvalidStatusFilter     = newError("CALC_INVALID_STATUS_FILTER", "invalid calculation status filter")
+	ErrCalcNotCancellable      = newError("CALC_NOT_CANCELLABLE", "calculation cannot be cancelled in its current status")
+	ErrInvalidResultFormat     = newError("CALC_INVALID_RESULT_FORMAT", "invalid result format")
+	ErrNonIntegerResult        = newError("CALC_NON_INTEGER_RESULT", "result is not an integer")
+	ErrParseTimeout            = newError("CALC_PARSE_TIMEOUT", "expression parsing timed out")
+	ErrOperationTimeout        = newError("OPERATION_TIMEOUT", "timeout")
+	ErrInvalidTolerance        = newError("CALC_INVALID_TOLERANCE", "tolerance must be a non-negative number")
+	ErrReferenceNotCompleted   = newError("CALC_REFERENCE_NOT_COMPLETED", "referenced calculation is not completed")
+	ErrTooManyReferences       = newError("CALC_TOO_MANY_REFERENCES", "too many calculation references in expression")
+	ErrPendingOpsBudget        = newError("CALC_PENDING_OPERATIONS_LIMIT", "pending operations budget exceeded")
+	ErrDatabaseUnavailable     = newError("DATABASE_UNAVAILABLE", "database is unavailable")
 )
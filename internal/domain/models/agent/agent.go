@@ -28,6 +28,33 @@ type Agent struct {
 	StartedAt       time.Time       `json:"started_at"`
 	LastOperationAt time.Time       `json:"last_operation_at"`
 	UptimeSeconds   int64           `json:"uptime_seconds"`
+	// LastHeartbeat - время последнего сигнала о том, что цикл обработки агента работает.
+	// Нулевое значение означает, что агент сигналов не отправляет.
+	LastHeartbeat time.Time `json:"last_heartbeat"`
+	// TypeCapacity - сколько еще операций каждого типа агент может принять
+	// при текущей нагрузке с учетом OperationCosts.
+	TypeCapacity map[string]int `json:"type_capacity,omitempty"`
+	// Remote - агент работает в отдельном процессе и получает операции через gRPC.
+	Remote bool `json:"remote,omitempty"`
+}
+
+// OperationCost возвращает стоимость операции в единицах емкости агента.
+// Операция без заданной стоимости занимает одну единицу.
+func (a *Agent) OperationCost(operation string) int {
+	if cost, ok := a.OperationCosts[operation]; ok && cost > 0 {
+		return cost
+	}
+	return 1
+}
+
+// RemainingCapacityFor возвращает, сколько еще операций указанного типа
+// агент может принять при текущей нагрузке.
+func (a *Agent) RemainingCapacityFor(operation string) int {
+	free := a.MaxCapacity - a.CurrentLoad
+	if free <= 0 {
+		return 0
+	}
+	return free / a.OperationCost(operation)
 }
 
 // OperationsStats содержит статистику выполненных операций агентом.
@@ -0,0 +1,68 @@
+package agent
+
+// AgentStats содержит метрики отдельного агента пула.
+type AgentStats struct {
+	ID          string      `json:"id"`
+	Status      AgentStatus `json:"status"`
+	CurrentLoad int         `json:"current_load"`
+	MaxCapacity int         `json:"max_capacity"`
+	Completed   int64       `json:"completed"`
+	Failed      int64       `json:"failed"`
+	QueueDepth  int         `json:"queue_depth"`
+}
+
+// PoolStats содержит агрегированные метрики пула агентов.
+type PoolStats struct {
+	Agents        []AgentStats `json:"agents"`
+	TotalAgents   int          `json:"total_agents"`
+	OnlineAgents  int          `json:"online_agents"`
+	BusyAgents    int          `json:"busy_agents"`
+	TotalLoad     int          `json:"total_load"`
+	TotalCapacity int          `json:"total_capacity"`
+	Completed     int64        `json:"completed"`
+	Failed        int64        `json:"failed"`
+	QueueDepth    int          `json:"queue_depth"`
+	Throughput    Throughput   `json:"throughput"`
+	// Workers - текущее количество воркеров пула. При автомасштабировании меняется
+	// в зависимости от очереди ожидающих операций.
+	Workers int `json:"workers"`
+}
+
+// Throughput содержит счетчики операций всего пула с момента его запуска. В отличие от
+// Completed и Failed, которые суммируют статистику текущих агентов, счетчики не теряют
+// операции агентов, покинувших пул.
+type Throughput struct {
+	Dispatched int64 `json:"dispatched"`
+	Completed  int64 `json:"completed"`
+	Failed     int64 `json:"failed"`
+}
+
+// Add учитывает агента и глубину его очереди в агрегированных метриках.
+func (s *PoolStats) Add(a *Agent, queueDepth int) {
+	if a == nil {
+		return
+	}
+
+	s.Agents = append(s.Agents, AgentStats{
+		ID:          a.ID,
+		Status:      a.Status,
+		CurrentLoad: a.CurrentLoad,
+		MaxCapacity: a.MaxCapacity,
+		Completed:   a.OperationsStats.Completed,
+		Failed:      a.OperationsStats.Failed,
+		QueueDepth:  queueDepth,
+	})
+
+	s.TotalAgents++
+	switch a.Status {
+	case AgentStatusOnline:
+		s.OnlineAgents++
+	case AgentStatusBusy:
+		s.BusyAgents++
+	}
+	s.TotalLoad += a.CurrentLoad
+	s.TotalCapacity += a.MaxCapacity
+	s.Completed += a.OperationsStats.Completed
+	s.Failed += a.OperationsStats.Failed
+	s.QueueDepth += queueDepth
+}
@@ -0,0 +1,16 @@
+package agent
+
+// AgentTiming содержит фактическое время выполнения операций отдельного агента в миллисекундах.
+// В детерминированном режиме агент не имитирует задержку, и все значения равны нулю.
+type AgentTiming struct {
+	ID               string           `json:"id"`
+	Deterministic    bool             `json:"deterministic"`
+	OperationTimesMs map[string]int64 `json:"operation_times_ms"`
+}
+
+// TimingProfile содержит настроенное время выполнения операций и фактические значения,
+// применяемые каждым агентом пула.
+type TimingProfile struct {
+	ConfiguredMs map[string]int64 `json:"configured_ms"`
+	Agents       []AgentTiming    `json:"agents"`
+}
@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session представляет активную сессию пользователя, то есть действующий refresh токен.
+type Session struct {
+	ID        uuid.UUID `json:"id"`
+	UserAgent string    `json:"user_agent"`
+	IP        string    `json:"ip"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// SessionFromToken строит описание сессии по refresh токену, не раскрывая сам токен.
+func SessionFromToken(token *Token) *Session {
+	return &Session{
+		ID:        token.ID,
+		UserAgent: token.UserAgent,
+		IP:        token.IP,
+		CreatedAt: token.CreatedAt,
+		ExpiresAt: token.ExpiresAt,
+	}
+}
+
+// ClientInfo содержит сведения о клиенте, выполняющем вход.
+type ClientInfo struct {
+	UserAgent string
+	IP        string
+}
+
+type clientInfoKey struct{}
+
+// WithClientInfo сохраняет сведения о клиенте в контексте.
+func WithClientInfo(ctx context.Context, info ClientInfo) context.Context {
+	return context.WithValue(ctx, clientInfoKey{}, info)
+}
+
+// ClientInfoFromContext возвращает сведения о клиенте из контекста или пустую структуру.
+func ClientInfoFromContext(ctx context.Context) ClientInfo {
+	info, _ := ctx.Value(clientInfoKey{}).(ClientInfo)
+	return info
+}
@@ -15,6 +15,11 @@ type Token struct {
 	ExpiresAt time.Time `json:"expires_at"`
 	CreatedAt time.Time `json:"created_at"`
 	IsRevoked bool      `json:"is_revoked"`
+	UserAgent string    `json:"user_agent"`
+	IP        string    `json:"ip"`
+	// FamilyIssuedAt - время входа, с которого началась цепочка обновлений токена.
+	// Обновленный токен наследует его от предыдущего.
+	FamilyIssuedAt time.Time `json:"family_issued_at"`
 }
 
 // TokenPair содержит пару токенов доступа и обновления.
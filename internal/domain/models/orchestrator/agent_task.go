@@ -0,0 +1,12 @@
+package orchestrator
+
+import "time"
+
+// AgentTask - операция, выданная агенту, работающему в отдельном процессе.
+// Ссылки на результаты других операций в операндах уже заменены значениями.
+type AgentTask struct {
+	Operation *Operation
+	// Duration - время, в течение которого агент имитирует выполнение операции;
+	// нулевое значение в детерминированном режиме.
+	Duration time.Duration
+}
@@ -0,0 +1,96 @@
+package orchestrator
+
+import (
+	"math"
+
+	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
+)
+
+// ArithmeticBackend определяет, в каком представлении агент выполняет операции.
+type ArithmeticBackend string
+
+const (
+	// ArithmeticFloat - вычисления в float64.
+	ArithmeticFloat ArithmeticBackend = "float"
+	// ArithmeticDecimal - вычисления в десятичной арифметике произвольной точности.
+	ArithmeticDecimal ArithmeticBackend = "decimal"
+)
+
+// DefaultDivisionPrecision - количество знаков после запятой при десятичном делении по умолчанию.
+const DefaultDivisionPrecision = 16
+
+// IsValid проверяет, что представление относится к одному из поддерживаемых значений.
+func (b ArithmeticBackend) IsValid() bool {
+	return b == ArithmeticFloat || b == ArithmeticDecimal
+}
+
+// DivisionByZeroPolicy определяет результат деления и остатка от деления на ноль в float64.
+type DivisionByZeroPolicy string
+
+const (
+	// DivisionByZeroError - деление на ноль завершает операцию ошибкой ErrDivisionByZero.
+	DivisionByZeroError DivisionByZeroPolicy = "error"
+	// DivisionByZeroInfinity - результат по IEEE 754: x/0 дает +Inf или -Inf по знакам
+	// делимого и нуля, 0/0 и остаток от деления на ноль - NaN.
+	DivisionByZeroInfinity DivisionByZeroPolicy = "infinity"
+	// DivisionByZeroNaN - деление и остаток от деления на ноль всегда дают NaN.
+	DivisionByZeroNaN DivisionByZeroPolicy = "nan"
+)
+
+// IsValid проверяет, что политика относится к одному из поддерживаемых значений.
+func (p DivisionByZeroPolicy) IsValid() bool {
+	return p == DivisionByZeroError || p == DivisionByZeroInfinity || p == DivisionByZeroNaN
+}
+
+// AllowsDivisionByZero сообщает, что деление на ноль дает значение, а не ошибку.
+// Неизвестная политика считается DivisionByZeroError.
+func (p DivisionByZeroPolicy) AllowsDivisionByZero() bool {
+	return p == DivisionByZeroInfinity || p == DivisionByZeroNaN
+}
+
+// Divide делит dividend на divisor с учетом политики деления на ноль.
+func (p DivisionByZeroPolicy) Divide(dividend, divisor float64) (float64, error) {
+	if divisor != 0 {
+		return dividend / divisor, nil
+	}
+
+	switch p {
+	case DivisionByZeroInfinity:
+		return dividend / divisor, nil
+	case DivisionByZeroNaN:
+		return math.NaN(), nil
+	default:
+		return 0, domainerrors.ErrDivisionByZero
+	}
+}
+
+// Mod возвращает остаток от деления dividend на divisor с учетом политики деления на ноль.
+// Остаток от деления на ноль в обоих нестрогих режимах равен NaN.
+func (p DivisionByZeroPolicy) Mod(dividend, divisor float64) (float64, error) {
+	if divisor == 0 && !p.AllowsDivisionByZero() {
+		return 0, domainerrors.ErrDivisionByZero
+	}
+	return math.Mod(dividend, divisor), nil
+}
+
+// Arithmetic задает представление чисел при выполнении операций.
+type Arithmetic struct {
+	Backend ArithmeticBackend
+	// DivisionPrecision - количество знаков после запятой в частном для ArithmeticDecimal.
+	// Остальные операции в десятичном представлении выполняются точно.
+	DivisionPrecision int32
+	// PreserveScale сохраняет в результате ArithmeticDecimal масштаб операндов:
+	// 2.50+2.50 дает 5.00 вместо 5.
+	PreserveScale bool
+	// DivisionByZero - результат деления на ноль в ArithmeticFloat. В нестрогих режимах
+	// бесконечность или NaN распространяется по выражению, а вычисление завершается
+	// с предупреждением. В ArithmeticDecimal деление на ноль всегда завершается ошибкой.
+	DivisionByZero DivisionByZeroPolicy
+}
+
+// FloatArithmetic - вычисления в float64, используются по умолчанию.
+var FloatArithmetic = Arithmetic{
+	Backend:           ArithmeticFloat,
+	DivisionPrecision: DefaultDivisionPrecision,
+	DivisionByZero:    DivisionByZeroError,
+}
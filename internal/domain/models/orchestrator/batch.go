@@ -0,0 +1,106 @@
+package orchestrator
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// OperationInsertResult - результат сохранения одной операции из пакета.
+type OperationInsertResult struct {
+	// Index - позиция операции в переданном пакете.
+	Index int
+	// OperationID - ID операции; нулевой, если операция не передана.
+	OperationID uuid.UUID
+	// Err - причина, по которой операция не сохранена; nil при успехе.
+	Err error
+}
+
+// FailedInserts возвращает результаты операций, которые не удалось сохранить.
+func FailedInserts(results []OperationInsertResult) []OperationInsertResult {
+	var failed []OperationInsertResult
+	for _, result := range results {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// operandRefPrefix - префикс операнда, который ссылается на результат другой операции.
+const operandRefPrefix = "ref:"
+
+// DuplicateOperationIDs возвращает ID, которые встречаются в пакете больше одного раза,
+// в порядке их первого повтора.
+func DuplicateOperationIDs(operations []*Operation) []uuid.UUID {
+	seen := make(map[uuid.UUID]int, len(operations))
+	var duplicates []uuid.UUID
+	for _, operation := range operations {
+		if operation == nil {
+			continue
+		}
+		seen[operation.ID]++
+		if seen[operation.ID] == 2 {
+			duplicates = append(duplicates, operation.ID)
+		}
+	}
+	return duplicates
+}
+
+// ReassignDuplicateIDs выдает новый ID каждой операции, чей ID уже встречался в пакете
+// раньше, и переводит на него ссылки ref:{id} последующих операций. Операции идут в порядке
+// разбора, поэтому ссылка относится к ближайшей предшествующей операции с этим ID.
+// Возвращает количество переназначенных ID.
+func ReassignDuplicateIDs(operations []*Operation, newID func() uuid.UUID) int {
+	taken := make(map[uuid.UUID]struct{}, len(operations))
+	for _, operation := range operations {
+		if operation != nil {
+			taken[operation.ID] = struct{}{}
+		}
+	}
+
+	seen := make(map[uuid.UUID]struct{}, len(operations))
+	current := make(map[uuid.UUID]uuid.UUID)
+	reassigned := 0
+
+	for _, operation := range operations {
+		if operation == nil {
+			continue
+		}
+
+		operation.Operand1 = remapOperandRef(operation.Operand1, current)
+		operation.Operand2 = remapOperandRef(operation.Operand2, current)
+
+		if _, ok := seen[operation.ID]; !ok {
+			seen[operation.ID] = struct{}{}
+			continue
+		}
+
+		id := newID()
+		for _, exists := taken[id]; exists; _, exists = taken[id] {
+			id = newID()
+		}
+		taken[id] = struct{}{}
+		current[operation.ID] = id
+		operation.ID = id
+		reassigned++
+	}
+
+	return reassigned
+}
+
+func remapOperandRef(operand string, current map[uuid.UUID]uuid.UUID) string {
+	if len(current) == 0 || !strings.HasPrefix(operand, operandRefPrefix) {
+		return operand
+	}
+
+	id, err := uuid.Parse(strings.TrimPrefix(operand, operandRefPrefix))
+	if err != nil {
+		return operand
+	}
+
+	if replacement, ok := current[id]; ok {
+		return operandRefPrefix + replacement.String()
+	}
+	return operand
+}
@@ -0,0 +1,51 @@
+package orchestrator_test
+
+import (
+	"testing"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReassignDuplicateIDs(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	replacement := uuid.New()
+
+	// Первая и третья операции получили один ID a; последняя ссылается на вторую из них.
+	operations := []*orchestrator.Operation{
+		{ID: a, Operand1: "1", Operand2: "2"},
+		{ID: b, Operand1: "ref:" + a.String(), Operand2: "7"},
+		{ID: a, Operand1: "3", Operand2: "4"},
+		{ID: uuid.New(), Operand1: "ref:" + b.String(), Operand2: "ref:" + a.String()},
+	}
+
+	assert.Equal(t, []uuid.UUID{a}, orchestrator.DuplicateOperationIDs(operations))
+
+	ids := []uuid.UUID{b, replacement}
+	newID := func() uuid.UUID {
+		id := ids[0]
+		ids = ids[1:]
+		return id
+	}
+
+	require.Equal(t, 1, orchestrator.ReassignDuplicateIDs(operations, newID))
+
+	assert.Equal(t, a, operations[0].ID)
+	assert.Equal(t, "ref:"+a.String(), operations[1].Operand1, "reference before the duplicate is unchanged")
+	assert.Equal(t, replacement, operations[2].ID, "generated ID already taken in the batch is skipped")
+	assert.Equal(t, "ref:"+b.String(), operations[3].Operand1)
+	assert.Equal(t, "ref:"+replacement.String(), operations[3].Operand2, "reference follows the nearest preceding operation")
+	assert.Empty(t, orchestrator.DuplicateOperationIDs(operations))
+}
+
+func TestReassignDuplicateIDsWithoutDuplicates(t *testing.T) {
+	operations := []*orchestrator.Operation{{ID: uuid.New()}, nil, {ID: uuid.New()}}
+
+	assert.Empty(t, orchestrator.DuplicateOperationIDs(operations))
+	assert.Zero(t, orchestrator.ReassignDuplicateIDs(operations, func() uuid.UUID {
+		t.Fatal("no new IDs expected")
+		return uuid.Nil
+	}))
+}
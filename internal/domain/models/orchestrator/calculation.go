@@ -2,6 +2,8 @@
 package orchestrator
 
 import (
+	"context"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -19,8 +21,93 @@ const (
 	CalculationStatusCompleted CalculationStatus = "COMPLETED"
 	// CalculationStatusError - ошибка выполнения.
 	CalculationStatusError CalculationStatus = "ERROR"
+	// CalculationStatusCancelled - отменено пользователем.
+	CalculationStatusCancelled CalculationStatus = "CANCELLED"
 )
 
+// IsValid проверяет, что статус относится к одному из известных значений.
+func (s CalculationStatus) IsValid() bool {
+	switch s {
+	case CalculationStatusPending, CalculationStatusInProgress, CalculationStatusCompleted,
+		CalculationStatusError, CalculationStatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsFinal сообщает, что вычисление завершено и его статус больше не изменится.
+func (s CalculationStatus) IsFinal() bool {
+	switch s {
+	case CalculationStatusCompleted, CalculationStatusError, CalculationStatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// CalculationSource определяет канал, через который было отправлено вычисление.
+type CalculationSource string
+
+const (
+	// CalculationSourceWeb - веб-интерфейс.
+	CalculationSourceWeb CalculationSource = "web"
+	// CalculationSourceCLI - консольный клиент.
+	CalculationSourceCLI CalculationSource = "cli"
+	// CalculationSourceAPIToken - прямой доступ к API по токену.
+	CalculationSourceAPIToken CalculationSource = "api-token"
+	// CalculationSourceBatch - пакетная загрузка.
+	CalculationSourceBatch CalculationSource = "batch"
+)
+
+// IsValid проверяет, что источник относится к одному из известных каналов.
+func (s CalculationSource) IsValid() bool {
+	switch s {
+	case CalculationSourceWeb, CalculationSourceCLI, CalculationSourceAPIToken, CalculationSourceBatch:
+		return true
+	default:
+		return false
+	}
+}
+
+// MaxClientVersionLength - наибольшая длина сохраняемой версии клиента, более длинная обрезается.
+const MaxClientVersionLength = 64
+
+type clientVersionKey struct{}
+
+// WithClientVersion сохраняет в контексте версию клиента, отправившего запрос.
+// Версия очищается от пробелов по краям и обрезается до MaxClientVersionLength байт.
+func WithClientVersion(ctx context.Context, version string) context.Context {
+	version = strings.TrimSpace(version)
+	if len(version) > MaxClientVersionLength {
+		version = strings.ToValidUTF8(version[:MaxClientVersionLength], "")
+	}
+	return context.WithValue(ctx, clientVersionKey{}, version)
+}
+
+// ClientVersionFromContext возвращает версию клиента из контекста или пустую строку.
+func ClientVersionFromContext(ctx context.Context) string {
+	version, _ := ctx.Value(clientVersionKey{}).(string)
+	return version
+}
+
+// MaxIdempotencyKeyLength - наибольшая длина ключа идемпотентности; более длинный ключ отклоняется.
+const MaxIdempotencyKeyLength = 255
+
+type idempotencyKeyKey struct{}
+
+// WithIdempotencyKey сохраняет в контексте ключ идемпотентности отправки вычисления.
+// Повторная отправка с тем же ключом возвращает уже созданное вычисление.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyKey{}, strings.TrimSpace(key))
+}
+
+// IdempotencyKeyFromContext возвращает ключ идемпотентности из контекста или пустую строку.
+func IdempotencyKeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyKey{}).(string)
+	return key
+}
+
 // Calculation представляет собой вычисление арифметического выражения.
 type Calculation struct {
 	ID           uuid.UUID         `json:"id"`
@@ -29,7 +116,74 @@ type Calculation struct {
 	Result       string            `json:"result"`
 	Status       CalculationStatus `json:"status"`
 	ErrorMessage string            `json:"error_message"`
-	CreatedAt    time.Time         `json:"created_at"`
-	UpdatedAt    time.Time         `json:"updated_at"`
-	Operations   []Operation       `json:"operations,omitempty"`
+	Source       CalculationSource `json:"source"`
+	// DisplayResult - результат, округленный до точности отображения, при точном Result.
+	// Заполняется только в десятичном режиме с заданной точностью отображения.
+	DisplayResult string `json:"display_result,omitempty"`
+	// Warnings - предупреждения к успешному результату, например о делении на ноль
+	// в нестрогом режиме. В отличие от ErrorMessage, не означают ошибку вычисления.
+	Warnings []string `json:"warnings,omitempty"`
+	// ClientVersion - версия клиента, отправившего выражение, по его собственным данным.
+	// Используется поддержкой для отладки и возвращается только администраторам.
+	ClientVersion string `json:"client_version,omitempty"`
+	// IdempotencyKey - ключ идемпотентности, с которым отправлено вычисление; уникален
+	// в пределах пользователя. Клиенту не возвращается.
+	IdempotencyKey string    `json:"-"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	// DeletedAt - время удаления; заполняется только при поиске с IncludeDeleted.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// CompactedAt - время сжатия операций: их строки удалены, а Operations читаются из архива
+	// в записи вычисления.
+	CompactedAt *time.Time  `json:"compacted_at,omitempty"`
+	Operations  []Operation `json:"operations,omitempty"`
+	// FormattedResult - результат в запрошенной системе счисления, не хранится в базе.
+	FormattedResult string `json:"formatted_result,omitempty"`
+	// EstimatedDuration - оценка времени до завершения вычисления, не хранится в базе.
+	EstimatedDuration time.Duration `json:"-"`
+	// LikelyAgents - агенты, которые при текущем состоянии пула взяли бы операции
+	// вычисления. Заполняется только в ответе на создание вычисления, не хранится в базе.
+	LikelyAgents []string `json:"likely_agents,omitempty"`
+}
+
+// CalculationFilter задает параметры постраничной выборки вычислений.
+// Пустой Status означает выборку без фильтра по статусу.
+type CalculationFilter struct {
+	Limit  int
+	Offset int
+	Status CalculationStatus
+}
+
+// FindOptions задает дополнительные условия поиска вычисления.
+type FindOptions struct {
+	// IncludeDeleted - находить и удаленные вычисления.
+	IncludeDeleted bool
+}
+
+// FindOption изменяет условия поиска вычисления.
+type FindOption func(*FindOptions)
+
+// IncludeDeleted включает в поиск удаленные вычисления. Предназначена для администрирования
+// и отладки: обычные выборки удаленные вычисления не возвращают.
+func IncludeDeleted() FindOption {
+	return func(opts *FindOptions) {
+		opts.IncludeDeleted = true
+	}
+}
+
+// NewFindOptions собирает условия поиска из опций.
+func NewFindOptions(opts ...FindOption) FindOptions {
+	var options FindOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// CalculationPage представляет страницу списка вычислений вместе с общим количеством записей.
+type CalculationPage struct {
+	Calculations []*Calculation `json:"calculations"`
+	Total        int            `json:"total"`
+	Limit        int            `json:"limit"`
+	Offset       int            `json:"offset"`
 }
@@ -0,0 +1,28 @@
+package orchestrator
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CalculationEvent - изменение статуса вычисления, доставляемое подписчикам.
+// Для завершенного вычисления содержит итоговый результат или сообщение об ошибке.
+type CalculationEvent struct {
+	CalculationID uuid.UUID         `json:"id"`
+	Status        CalculationStatus `json:"status"`
+	Result        string            `json:"result,omitempty"`
+	ErrorMessage  string            `json:"error_message,omitempty"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+}
+
+// NewCalculationEvent создает событие с текущим состоянием вычисления.
+func NewCalculationEvent(calc *Calculation) CalculationEvent {
+	return CalculationEvent{
+		CalculationID: calc.ID,
+		Status:        calc.Status,
+		Result:        calc.Result,
+		ErrorMessage:  calc.ErrorMessage,
+		UpdatedAt:     calc.UpdatedAt,
+	}
+}
@@ -0,0 +1,81 @@
+package orchestrator
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ExpressionComparison содержит результат сравнения значений двух выражений.
+type ExpressionComparison struct {
+	ExpressionA string  `json:"expression_a"`
+	ExpressionB string  `json:"expression_b"`
+	ResultA     string  `json:"result_a"`
+	ResultB     string  `json:"result_b"`
+	Difference  float64 `json:"difference"`
+	Tolerance   float64 `json:"tolerance"`
+	Equal       bool    `json:"equal"`
+}
+
+// CompareResults сравнивает значения выражений с учетом допустимой абсолютной погрешности.
+// Нулевая погрешность означает точное совпадение.
+func CompareResults(expressionA, expressionB string, resultA, resultB, tolerance float64) *ExpressionComparison {
+	difference := math.Abs(resultA - resultB)
+
+	return &ExpressionComparison{
+		ExpressionA: expressionA,
+		ExpressionB: expressionB,
+		ResultA:     strconv.FormatFloat(resultA, 'f', -1, 64),
+		ResultB:     strconv.FormatFloat(resultB, 'f', -1, 64),
+		Difference:  difference,
+		Tolerance:   tolerance,
+		Equal:       resultA == resultB || difference <= tolerance,
+	}
+}
+
+// CalculationDiff содержит сравнение двух сохраненных вычислений пользователя.
+type CalculationDiff struct {
+	CalculationIDA uuid.UUID `json:"calculation_id_a"`
+	CalculationIDB uuid.UUID `json:"calculation_id_b"`
+	ExpressionA    string    `json:"expression_a"`
+	ExpressionB    string    `json:"expression_b"`
+	ResultA        string    `json:"result_a"`
+	ResultB        string    `json:"result_b"`
+	// SameExpression - выражения совпадают без учета пробелов.
+	SameExpression bool `json:"same_expression"`
+	// SameResult - результаты совпадают: численно, если оба результата числа, иначе посимвольно.
+	SameResult bool `json:"same_result"`
+	// Delta - разница ResultB - ResultA; nil, если хотя бы один из результатов не число,
+	// например вычисление еще не завершено.
+	Delta *float64 `json:"delta,omitempty"`
+}
+
+// DiffCalculations сравнивает выражения и результаты двух вычислений.
+func DiffCalculations(a, b *Calculation) *CalculationDiff {
+	diff := &CalculationDiff{
+		CalculationIDA: a.ID,
+		CalculationIDB: b.ID,
+		ExpressionA:    a.Expression,
+		ExpressionB:    b.Expression,
+		ResultA:        a.Result,
+		ResultB:        b.Result,
+		SameExpression: stripSpaces(a.Expression) == stripSpaces(b.Expression),
+		SameResult:     a.Result == b.Result,
+	}
+
+	valueA, errA := strconv.ParseFloat(strings.TrimSpace(a.Result), 64)
+	valueB, errB := strconv.ParseFloat(strings.TrimSpace(b.Result), 64)
+	if errA == nil && errB == nil {
+		delta := valueB - valueA
+		diff.Delta = &delta
+		diff.SameResult = valueA == valueB
+	}
+
+	return diff
+}
+
+func stripSpaces(s string) string {
+	return strings.Join(strings.Fields(s), "")
+}
@@ -0,0 +1,25 @@
+package orchestrator
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeadLetter - копия операции, которую не удалось выполнить после всех попыток,
+// с контекстом для разбора и повторного запуска.
+type DeadLetter struct {
+	ID            uuid.UUID     `json:"id"`
+	OperationID   uuid.UUID     `json:"operation_id"`
+	CalculationID uuid.UUID     `json:"calculation_id"`
+	Expression    string        `json:"expression"`
+	OperationType OperationType `json:"operation_type"`
+	Operand1      string        `json:"operand1"`
+	Operand2      string        `json:"operand2"`
+	ErrorHistory  []string      `json:"error_history"`
+	CreatedAt     time.Time     `json:"created_at"`
+	ReplayedAt    *time.Time    `json:"replayed_at,omitempty"`
+	// Deterministic - операция завершилась ошибкой вычисления (деление на ноль, неверный
+	// операнд), которую повторный запуск не исправит.
+	Deterministic bool `json:"deterministic"`
+}
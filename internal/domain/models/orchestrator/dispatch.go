@@ -0,0 +1,97 @@
+package orchestrator
+
+import (
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/agent"
+	"github.com/google/uuid"
+)
+
+// DispatchAssignment описывает, какому агенту процессор назначил бы ожидающую операцию
+// при текущем состоянии пула. Используется в режиме пробного распределения (dry-run).
+type DispatchAssignment struct {
+	OperationID   uuid.UUID     `json:"operation_id"`
+	CalculationID uuid.UUID     `json:"calculation_id"`
+	OperationType OperationType `json:"operation_type"`
+	// AgentID - агент, получивший бы операцию. Пусто, если операция не была бы назначена.
+	AgentID string `json:"agent_id,omitempty"`
+	// Reason - причина, по которой операция не была бы назначена.
+	Reason string `json:"reason,omitempty"`
+}
+
+// Причины, по которым операция не была бы назначена агенту.
+const (
+	DispatchReasonCancelled  = "operation cancelled"
+	DispatchReasonNoCapacity = "no online agent with free capacity"
+)
+
+// PlanDispatch распределяет операции по снимку агентов так же, как пул агентов выбирает
+// исполнителя: каждая операция достается онлайн-агенту с наименьшей нагрузкой, у которого
+// хватает емкости на операцию этого типа. Нагрузка назначенных операций учитывается при
+// распределении следующих. Переданные агенты не изменяются. cancelled может быть nil.
+func PlanDispatch(operations []*Operation, agents []*agent.Agent, cancelled func(uuid.UUID) bool) []DispatchAssignment {
+	pool := make([]*agent.Agent, 0, len(agents))
+	for _, a := range agents {
+		if a == nil || a.Status == agent.AgentStatusOffline {
+			continue
+		}
+		snapshot := *a
+		pool = append(pool, &snapshot)
+	}
+
+	plan := make([]DispatchAssignment, 0, len(operations))
+	for _, op := range operations {
+		if op == nil {
+			continue
+		}
+
+		assignment := DispatchAssignment{
+			OperationID:   op.ID,
+			CalculationID: op.CalculationID,
+			OperationType: op.OperationType,
+		}
+
+		if cancelled != nil && cancelled(op.ID) {
+			assignment.Reason = DispatchReasonCancelled
+			plan = append(plan, assignment)
+			continue
+		}
+
+		operationName := op.OperationType.Name()
+		var best *agent.Agent
+		for _, a := range pool {
+			if a.RemainingCapacityFor(operationName) <= 0 {
+				continue
+			}
+			if best == nil || a.CurrentLoad < best.CurrentLoad {
+				best = a
+			}
+		}
+
+		if best == nil {
+			assignment.Reason = DispatchReasonNoCapacity
+		} else {
+			assignment.AgentID = best.ID
+			best.CurrentLoad += best.OperationCost(operationName)
+		}
+		plan = append(plan, assignment)
+	}
+
+	return plan
+}
+
+// PlannedAgents возвращает агентов, которым в плане досталась хотя бы одна операция,
+// в порядке первого назначения.
+func PlannedAgents(plan []DispatchAssignment) []string {
+	var agents []string
+	seen := make(map[string]struct{})
+	for _, assignment := range plan {
+		if assignment.AgentID == "" {
+			continue
+		}
+		if _, ok := seen[assignment.AgentID]; ok {
+			continue
+		}
+		seen[assignment.AgentID] = struct{}{}
+		agents = append(agents, assignment.AgentID)
+	}
+	return agents
+}
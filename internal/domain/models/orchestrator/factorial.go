@@ -0,0 +1,29 @@
+package orchestrator
+
+import (
+	"fmt"
+	"math"
+
+	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
+)
+
+// MaxFactorialOperand - наибольший операнд, факториал которого представим в float64 (170! ≈ 7.3e306).
+const MaxFactorialOperand = 170
+
+// Factorial вычисляет n! последовательным произведением. Операнд должен быть неотрицательным
+// целым, иначе возвращается ErrFactorialOperand; если произведение выходит за пределы float64,
+// возвращается ErrNumericOverflow.
+func Factorial(n float64) (float64, error) {
+	if n < 0 || n != math.Trunc(n) || math.IsInf(n, 0) || math.IsNaN(n) {
+		return 0, fmt.Errorf("%w: %v", domainerrors.ErrFactorialOperand, n)
+	}
+
+	result := 1.0
+	for i := 2.0; i <= n; i++ {
+		result *= i
+		if math.IsInf(result, 0) {
+			return 0, fmt.Errorf("%w: %v!", domainerrors.ErrNumericOverflow, n)
+		}
+	}
+	return result, nil
+}
@@ -2,6 +2,10 @@
 package orchestrator
 
 import (
+	"context"
+	"sort"
+	"time"
+
 	"github.com/google/uuid"
 )
 
@@ -19,8 +23,46 @@ const (
 	OperationTypeMultiplication OperationType = 3
 	// OperationTypeDivision - деление.
 	OperationTypeDivision OperationType = 4
+	// OperationTypeModulo - остаток от деления.
+	OperationTypeModulo OperationType = 5
+	// OperationTypeFactorial - факториал, унарная операция над Operand1.
+	OperationTypeFactorial OperationType = 6
 )
 
+// Name возвращает имя операции, под которым в конфигурации задаются время ее выполнения
+// и стоимость для агента. Для неизвестного типа возвращается пустая строка.
+func (t OperationType) Name() string {
+	switch t {
+	case OperationTypeAddition:
+		return "addition"
+	case OperationTypeSubtraction:
+		return "subtraction"
+	case OperationTypeMultiplication:
+		return "multiplication"
+	case OperationTypeDivision:
+		return "division"
+	case OperationTypeModulo:
+		return "modulo"
+	case OperationTypeFactorial:
+		return "factorial"
+	default:
+		return ""
+	}
+}
+
+// EstimateDuration оценивает время выполнения операций как сумму настроенного времени каждой из них.
+// Операции, для которых время не задано, в оценке не учитываются.
+func EstimateDuration(operations []*Operation, operationTimes map[string]time.Duration) time.Duration {
+	var total time.Duration
+	for _, op := range operations {
+		if op == nil {
+			continue
+		}
+		total += operationTimes[op.OperationType.Name()]
+	}
+	return total
+}
+
 // OperationStatus определяет статус выполнения операции.
 type OperationStatus string
 
@@ -33,6 +75,8 @@ const (
 	OperationStatusCompleted OperationStatus = "COMPLETED"
 	// OperationStatusError - ошибка выполнения операции.
 	OperationStatusError OperationStatus = "ERROR"
+	// OperationStatusCancelled - операция отменена вместе с вычислением.
+	OperationStatusCancelled OperationStatus = "CANCELLED"
 )
 
 // Operation представляет одну арифметическую операцию.
@@ -47,4 +91,50 @@ type Operation struct {
 	ErrorMessage   string          `json:"error_message"`
 	ProcessingTime int64           `json:"processing_time_ms"`
 	AgentID        string          `json:"agent_id,omitempty"`
+	// Priority - приоритет выполнения: операции с большим значением назначаются агентам раньше.
+	Priority int `json:"priority,omitempty"`
+}
+
+// MaxPriority - наибольший допустимый приоритет вычисления. По умолчанию приоритет нулевой.
+const MaxPriority = 10
+
+type priorityKey struct{}
+
+// WithPriority сохраняет в контексте приоритет, с которым отправляется вычисление.
+func WithPriority(ctx context.Context, priority int) context.Context {
+	return context.WithValue(ctx, priorityKey{}, priority)
+}
+
+// PriorityFromContext возвращает приоритет вычисления из контекста или ноль.
+func PriorityFromContext(ctx context.Context) int {
+	priority, _ := ctx.Value(priorityKey{}).(int)
+	return priority
+}
+
+// ValidPriority проверяет, что приоритет лежит в диапазоне от нуля до MaxPriority.
+func ValidPriority(priority int) bool {
+	return priority >= 0 && priority <= MaxPriority
+}
+
+// SortByPriority упорядочивает операции по убыванию приоритета, сохраняя исходный
+// порядок операций с одинаковым приоритетом.
+func SortByPriority(operations []*Operation) {
+	sort.SliceStable(operations, func(i, j int) bool {
+		return operationPriority(operations[i]) > operationPriority(operations[j])
+	})
+}
+
+func operationPriority(op *Operation) int {
+	if op == nil {
+		return 0
+	}
+	return op.Priority
+}
+
+// OperationStatusUpdate - новое состояние операции для пакетного обновления статусов.
+type OperationStatusUpdate struct {
+	ID           uuid.UUID
+	Status       OperationStatus
+	Result       string
+	ErrorMessage string
 }
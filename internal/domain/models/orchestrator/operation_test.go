@@ -0,0 +1,62 @@
+package orchestrator_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateDuration(t *testing.T) {
+	operationTimes := map[string]time.Duration{
+		"addition":       time.Second,
+		"subtraction":    time.Second,
+		"multiplication": 2 * time.Second,
+		"division":       3 * time.Second,
+	}
+
+	testCases := []struct {
+		name       string
+		operations []*orchestrator.Operation
+		expected   time.Duration
+	}{
+		{name: "No operations", operations: nil, expected: 0},
+		{
+			name: "Sum of configured times",
+			operations: []*orchestrator.Operation{
+				{OperationType: orchestrator.OperationTypeAddition},
+				{OperationType: orchestrator.OperationTypeMultiplication},
+				{OperationType: orchestrator.OperationTypeDivision},
+			},
+			expected: 6 * time.Second,
+		},
+		{
+			name: "Unconfigured and nil operations are ignored",
+			operations: []*orchestrator.Operation{
+				{OperationType: orchestrator.OperationTypeSubtraction},
+				{OperationType: orchestrator.OperationTypeModulo},
+				nil,
+			},
+			expected: time.Second,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, orchestrator.EstimateDuration(tc.operations, operationTimes))
+		})
+	}
+}
+
+func TestSortByPriority(t *testing.T) {
+	first := &orchestrator.Operation{Operand1: "first"}
+	second := &orchestrator.Operation{Operand1: "second"}
+	urgent := &orchestrator.Operation{Operand1: "urgent", Priority: 9}
+	medium := &orchestrator.Operation{Operand1: "medium", Priority: 4}
+
+	operations := []*orchestrator.Operation{first, medium, second, nil, urgent}
+	orchestrator.SortByPriority(operations)
+
+	assert.Equal(t, []*orchestrator.Operation{urgent, medium, first, second, nil}, operations)
+}
@@ -0,0 +1,14 @@
+package orchestrator
+
+// ExpressionPreview показывает, в каком порядке будут выполнены операции выражения:
+// каждая вложенная операция заключена в скобки.
+type ExpressionPreview struct {
+	Expression string `json:"expression"`
+	Grouped    string `json:"grouped"`
+}
+
+// PreviewOptions задает вид сгруппированного выражения.
+type PreviewOptions struct {
+	// Spaced добавляет пробелы вокруг бинарных операторов: "2 + (3 * 4)" вместо "2+(3*4)".
+	Spaced bool `json:"spaced"`
+}
@@ -0,0 +1,64 @@
+package orchestrator
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
+)
+
+// ResultFormat определяет систему счисления, в которой отображается результат.
+type ResultFormat string
+
+const (
+	// ResultFormatDecimal - десятичная запись, как результат хранится в базе.
+	ResultFormatDecimal ResultFormat = "decimal"
+	// ResultFormatHex - шестнадцатеричная запись с префиксом 0x.
+	ResultFormatHex ResultFormat = "hex"
+	// ResultFormatBinary - двоичная запись с префиксом 0b.
+	ResultFormatBinary ResultFormat = "binary"
+)
+
+// IsValid проверяет, что формат относится к одному из поддерживаемых значений.
+func (f ResultFormat) IsValid() bool {
+	switch f {
+	case ResultFormatDecimal, ResultFormatHex, ResultFormatBinary:
+		return true
+	default:
+		return false
+	}
+}
+
+// FormatResult переводит десятичный результат в указанную систему счисления.
+// Шестнадцатеричный и двоичный форматы допустимы только для целых результатов.
+func FormatResult(result string, format ResultFormat) (string, error) {
+	if format == "" || format == ResultFormatDecimal {
+		return result, nil
+	}
+
+	var base int
+	var prefix string
+	switch format {
+	case ResultFormatHex:
+		base, prefix = 16, "0x"
+	case ResultFormatBinary:
+		base, prefix = 2, "0b"
+	default:
+		return "", fmt.Errorf("%w: %s", domainerrors.ErrInvalidResultFormat, format)
+	}
+
+	value, ok := new(big.Float).SetString(strings.TrimSpace(result))
+	if !ok || !value.IsInt() {
+		return "", fmt.Errorf("%w: %q", domainerrors.ErrNonIntegerResult, result)
+	}
+
+	integer, _ := value.Int(nil)
+	sign := ""
+	if integer.Sign() < 0 {
+		sign = "-"
+		integer.Neg(integer)
+	}
+
+	return sign + prefix + integer.Text(base), nil
+}
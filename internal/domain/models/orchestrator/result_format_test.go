@@ -0,0 +1,46 @@
+package orchestrator_test
+
+import (
+	"testing"
+
+	domainerrors "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/errord"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatResult(t *testing.T) {
+	testCases := []struct {
+		name     string
+		result   string
+		format   orchestrator.ResultFormat
+		expected string
+	}{
+		{name: "Decimal is returned as is", result: "255", format: orchestrator.ResultFormatDecimal, expected: "255"},
+		{name: "Empty format is decimal", result: "2.5", format: "", expected: "2.5"},
+		{name: "Hex", result: "255", format: orchestrator.ResultFormatHex, expected: "0xff"},
+		{name: "Binary", result: "255", format: orchestrator.ResultFormatBinary, expected: "0b11111111"},
+		{name: "Negative hex", result: "-255", format: orchestrator.ResultFormatHex, expected: "-0xff"},
+		{name: "Zero binary", result: "0", format: orchestrator.ResultFormatBinary, expected: "0b0"},
+		{name: "Integral float", result: "16.0", format: orchestrator.ResultFormatHex, expected: "0x10"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			formatted, err := orchestrator.FormatResult(tc.result, tc.format)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, formatted)
+		})
+	}
+}
+
+func TestFormatResultErrors(t *testing.T) {
+	_, err := orchestrator.FormatResult("2.5", orchestrator.ResultFormatHex)
+	assert.ErrorIs(t, err, domainerrors.ErrNonIntegerResult)
+
+	_, err = orchestrator.FormatResult("", orchestrator.ResultFormatBinary)
+	assert.ErrorIs(t, err, domainerrors.ErrNonIntegerResult)
+
+	_, err = orchestrator.FormatResult("255", orchestrator.ResultFormat("octal"))
+	assert.ErrorIs(t, err, domainerrors.ErrInvalidResultFormat)
+}
@@ -0,0 +1,12 @@
+package orchestrator
+
+// ResultStats - агрегаты по числовым результатам завершенных вычислений пользователя.
+// Значения хранятся строками, как и результаты вычислений, чтобы не терять точность.
+// Если подходящих вычислений нет, Count и Sum равны нулю, а Mean, Min и Max пусты.
+type ResultStats struct {
+	Count int64  `json:"count"`
+	Sum   string `json:"sum"`
+	Mean  string `json:"mean,omitempty"`
+	Min   string `json:"min,omitempty"`
+	Max   string `json:"max,omitempty"`
+}
@@ -0,0 +1,166 @@
+package orchestrator
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// RoundingMode определяет способ округления результата операции.
+type RoundingMode string
+
+const (
+	// RoundingHalfEven - половина округляется к ближайшей четной цифре (банковское округление).
+	RoundingHalfEven RoundingMode = "half_even"
+	// RoundingHalfUp - половина округляется от нуля.
+	RoundingHalfUp RoundingMode = "half_up"
+	// RoundingDown - отбрасывание лишних знаков (округление к нулю).
+	RoundingDown RoundingMode = "down"
+)
+
+// IsValid проверяет, что способ округления относится к одному из поддерживаемых значений.
+func (m RoundingMode) IsValid() bool {
+	switch m {
+	case RoundingHalfEven, RoundingHalfUp, RoundingDown:
+		return true
+	default:
+		return false
+	}
+}
+
+// Rounding задает количество знаков после запятой и способ округления результата.
+// Отрицательное Precision отключает округление.
+type Rounding struct {
+	Precision int
+	Mode      RoundingMode
+	// OperationPrecision переопределяет Precision для результатов операций отдельных типов,
+	// ключ - имя операции (division, addition, ...).
+	OperationPrecision map[string]int
+}
+
+// ForOperation возвращает округление результата операции указанного типа: точность
+// из OperationPrecision, если она задана для этого типа, иначе общую Precision.
+func (r Rounding) ForOperation(operationType OperationType) Rounding {
+	rounding := Rounding{Precision: r.Precision, Mode: r.Mode}
+	if precision, ok := r.OperationPrecision[operationType.Name()]; ok {
+		rounding.Precision = precision
+	}
+	return rounding
+}
+
+// NoRounding - результат выводится с максимальной точностью float64.
+var NoRounding = Rounding{Precision: -1, Mode: RoundingHalfEven}
+
+// Format переводит число в десятичную строку с учетом округления.
+// Округляется кратчайшая десятичная запись числа, поэтому 2.675 при двух знаках
+// и RoundingHalfUp дает 2.68. Целые значения выводятся без десятичной точки,
+// незначащие нули в дробной части отбрасываются. Бесконечность и NaN
+// записываются как inf, -inf и nan.
+func (r Rounding) Format(value float64) string {
+	switch {
+	case math.IsNaN(value):
+		return "nan"
+	case math.IsInf(value, 1):
+		return "inf"
+	case math.IsInf(value, -1):
+		return "-inf"
+	}
+
+	if value == math.Trunc(value) {
+		return strconv.FormatFloat(value, 'f', 0, 64)
+	}
+
+	text := strconv.FormatFloat(math.Abs(value), 'f', -1, 64)
+	if r.Precision >= 0 {
+		text = roundDecimal(text, r.Precision, r.Mode)
+	}
+
+	if value < 0 && strings.Trim(text, "0.") != "" {
+		return "-" + text
+	}
+	return text
+}
+
+// FormatText округляет десятичную запись числа без перевода в float64, поэтому точный
+// результат десятичной арифметики округляется по всем своим знакам. Запись, которая не
+// является десятичным числом (экспоненциальная форма, Inf), возвращается без изменений.
+func (r Rounding) FormatText(value string) string {
+	if r.Precision < 0 {
+		return value
+	}
+
+	text, negative := strings.CutPrefix(strings.TrimPrefix(value, "+"), "-")
+	if !isPlainDecimal(text) {
+		return value
+	}
+
+	text = roundDecimal(text, r.Precision, r.Mode)
+	if negative && strings.Trim(text, "0.") != "" {
+		return "-" + text
+	}
+	return text
+}
+
+// isPlainDecimal сообщает, что text - беззнаковая десятичная запись вида 123 или 123.45.
+func isPlainDecimal(text string) bool {
+	intPart, fracPart, hasPoint := strings.Cut(text, ".")
+	if intPart == "" || (hasPoint && fracPart == "") {
+		return false
+	}
+	for _, c := range intPart + fracPart {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// roundDecimal округляет неотрицательную десятичную запись до precision знаков после точки.
+func roundDecimal(text string, precision int, mode RoundingMode) string {
+	intPart, fracPart, _ := strings.Cut(text, ".")
+	if len(fracPart) <= precision {
+		return text
+	}
+
+	kept, rest := fracPart[:precision], fracPart[precision:]
+	digits, _ := new(big.Int).SetString(intPart+kept, 10)
+
+	if roundUp(digits, rest, mode) {
+		digits.Add(digits, big.NewInt(1))
+	}
+
+	result := digits.String()
+	if precision == 0 {
+		return result
+	}
+
+	if len(result) <= precision {
+		result = strings.Repeat("0", precision-len(result)+1) + result
+	}
+	point := len(result) - precision
+	fraction := strings.TrimRight(result[point:], "0")
+	if fraction == "" {
+		return result[:point]
+	}
+	return result[:point] + "." + fraction
+}
+
+// roundUp определяет, нужно ли увеличить последнюю сохраняемую цифру.
+// rest - отбрасываемые цифры, начиная с первой после сохраняемых.
+func roundUp(kept *big.Int, rest string, mode RoundingMode) bool {
+	switch mode {
+	case RoundingDown:
+		return false
+	case RoundingHalfUp:
+		return rest[0] >= '5'
+	default:
+		if rest[0] != '5' {
+			return rest[0] > '5'
+		}
+		if strings.TrimRight(rest[1:], "0") != "" {
+			return true
+		}
+		return kept.Bit(0) == 1
+	}
+}
@@ -0,0 +1,118 @@
+package orchestrator_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoundingFormat(t *testing.T) {
+	halfEven := func(precision int) orchestrator.Rounding {
+		return orchestrator.Rounding{Precision: precision, Mode: orchestrator.RoundingHalfEven}
+	}
+	halfUp := func(precision int) orchestrator.Rounding {
+		return orchestrator.Rounding{Precision: precision, Mode: orchestrator.RoundingHalfUp}
+	}
+	down := func(precision int) orchestrator.Rounding {
+		return orchestrator.Rounding{Precision: precision, Mode: orchestrator.RoundingDown}
+	}
+
+	testCases := []struct {
+		name     string
+		value    float64
+		rounding orchestrator.Rounding
+		expected string
+	}{
+		{name: "No rounding keeps full precision", value: 1.0 / 3, rounding: orchestrator.NoRounding, expected: "0.3333333333333333"},
+		{name: "Repeating decimal", value: 1.0 / 3, rounding: halfEven(4), expected: "0.3333"},
+		{name: "Repeating decimal rounds up", value: 2.0 / 3, rounding: halfEven(4), expected: "0.6667"},
+		{name: "Integer stays without point", value: 42, rounding: halfEven(2), expected: "42"},
+		{name: "Rounded to integer", value: 2.9999, rounding: halfEven(2), expected: "3"},
+		{name: "Trailing zeros are dropped", value: 1.5001, rounding: halfEven(2), expected: "1.5"},
+		{name: "Short value is unchanged", value: 0.5, rounding: halfEven(3), expected: "0.5"},
+		{name: "Half even rounds down to even", value: 0.125, rounding: halfEven(2), expected: "0.12"},
+		{name: "Half even rounds up to even", value: 0.375, rounding: halfEven(2), expected: "0.38"},
+		{name: "Half even above half", value: 0.1251, rounding: halfEven(2), expected: "0.13"},
+		{name: "Half even to zero places", value: 2.5, rounding: halfEven(0), expected: "2"},
+		{name: "Half even odd to zero places", value: 3.5, rounding: halfEven(0), expected: "4"},
+		{name: "Half up on boundary", value: 0.125, rounding: halfUp(2), expected: "0.13"},
+		{name: "Half up uses shortest decimal", value: 2.675, rounding: halfUp(2), expected: "2.68"},
+		{name: "Half up below half", value: 0.1249, rounding: halfUp(2), expected: "0.12"},
+		{name: "Down truncates", value: 0.129, rounding: down(2), expected: "0.12"},
+		{name: "Carry into integer part", value: 9.995, rounding: halfUp(2), expected: "10"},
+		{name: "Negative repeating decimal", value: -1.0 / 3, rounding: halfEven(2), expected: "-0.33"},
+		{name: "Negative half up rounds away from zero", value: -0.125, rounding: halfUp(2), expected: "-0.13"},
+		{name: "Negative half even", value: -0.125, rounding: halfEven(2), expected: "-0.12"},
+		{name: "Negative down truncates toward zero", value: -0.129, rounding: down(2), expected: "-0.12"},
+		{name: "Negative rounded to zero has no sign", value: -0.001, rounding: halfEven(2), expected: "0"},
+		{name: "Negative integer", value: -5, rounding: halfEven(2), expected: "-5"},
+		{name: "Infinity", value: math.Inf(1), rounding: halfEven(2), expected: "inf"},
+		{name: "Negative infinity", value: math.Inf(-1), rounding: halfEven(2), expected: "-inf"},
+		{name: "Not a number", value: math.NaN(), rounding: halfEven(2), expected: "nan"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.rounding.Format(tc.value))
+		})
+	}
+}
+
+func TestRoundingFormatText(t *testing.T) {
+	halfEven := orchestrator.Rounding{Precision: 2, Mode: orchestrator.RoundingHalfEven}
+	halfUp := orchestrator.Rounding{Precision: 2, Mode: orchestrator.RoundingHalfUp}
+
+	testCases := []struct {
+		name     string
+		value    string
+		rounding orchestrator.Rounding
+		expected string
+	}{
+		{name: "No rounding keeps value", value: "0.3333333333333333333333", rounding: orchestrator.NoRounding, expected: "0.3333333333333333333333"},
+		{name: "Long decimal", value: "0.3333333333333333333333", rounding: halfEven, expected: "0.33"},
+		{name: "Digits beyond float64 are used", value: "0.125000000000000000001", rounding: halfEven, expected: "0.13"},
+		{name: "Half even on boundary", value: "0.125", rounding: halfEven, expected: "0.12"},
+		{name: "Half up on boundary", value: "0.125", rounding: halfUp, expected: "0.13"},
+		{name: "Large integer is exact", value: "12345678901234567890123", rounding: halfEven, expected: "12345678901234567890123"},
+		{name: "Large value with fraction", value: "12345678901234567890.999", rounding: halfEven, expected: "12345678901234567891"},
+		{name: "Short value keeps scale", value: "5.00", rounding: halfEven, expected: "5.00"},
+		{name: "Negative", value: "-2.675", rounding: halfUp, expected: "-2.68"},
+		{name: "Negative rounded to zero has no sign", value: "-0.001", rounding: halfEven, expected: "0"},
+		{name: "Exponent form is unchanged", value: "1e+21", rounding: halfEven, expected: "1e+21"},
+		{name: "Not a number is unchanged", value: "+Inf", rounding: halfEven, expected: "+Inf"},
+		{name: "Empty value", value: "", rounding: halfEven, expected: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.rounding.FormatText(tc.value))
+		})
+	}
+}
+
+func TestRoundingForOperation(t *testing.T) {
+	rounding := orchestrator.Rounding{
+		Precision:          2,
+		Mode:               orchestrator.RoundingHalfUp,
+		OperationPrecision: map[string]int{"division": 6, "addition": 0},
+	}
+
+	division := rounding.ForOperation(orchestrator.OperationTypeDivision)
+	assert.Equal(t, orchestrator.Rounding{Precision: 6, Mode: orchestrator.RoundingHalfUp}, division)
+	assert.Equal(t, "0.666667", division.Format(2.0/3))
+
+	addition := rounding.ForOperation(orchestrator.OperationTypeAddition)
+	assert.Equal(t, "3", addition.Format(2.5))
+
+	assert.Equal(t, "0.67", rounding.ForOperation(orchestrator.OperationTypeMultiplication).Format(2.0/3))
+	assert.Equal(t, "0.67", rounding.ForOperation(orchestrator.OperationTypeUnspecified).Format(2.0/3))
+}
+
+func TestRoundingModeIsValid(t *testing.T) {
+	assert.True(t, orchestrator.RoundingHalfEven.IsValid())
+	assert.True(t, orchestrator.RoundingHalfUp.IsValid())
+	assert.True(t, orchestrator.RoundingDown.IsValid())
+	assert.False(t, orchestrator.RoundingMode("ceil").IsValid())
+}
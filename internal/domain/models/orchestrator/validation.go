@@ -0,0 +1,25 @@
+package orchestrator
+
+import "fmt"
+
+// ExpressionValidation - результат проверки синтаксиса выражения без создания вычисления.
+type ExpressionValidation struct {
+	Expression string `json:"expression"`
+	Valid      bool   `json:"valid"`
+	// ErrorOffset - номер символа с нуля, на котором найдена первая ошибка. Для ошибок,
+	// не привязанных к позиции (пустое или слишком длинное выражение), равен нулю.
+	ErrorOffset  *int   `json:"error_offset,omitempty"`
+	ErrorMessage string `json:"error_message,omitempty"`
+}
+
+// SyntaxError описывает первую синтаксическую ошибку выражения.
+type SyntaxError struct {
+	// Offset - номер символа (не байта) от начала выражения, с нуля, на котором найдена ошибка.
+	// Ошибка в конце выражения (2+) указывает на позицию сразу за последним символом.
+	Offset  int
+	Message string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("offset %d: %s", e.Offset, e.Message)
+}
@@ -0,0 +1,64 @@
+package orchestrator
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ResultWarnings возвращает предупреждения к результату успешно выполненных операций:
+// для каждой операции, которая из конечных операндов получила бесконечность или NaN
+// (деление на ноль в нестрогом режиме, переполнение). Операции, лишь передающие дальше
+// уже нечисловое значение, предупреждений не добавляют.
+func ResultWarnings(operations []*Operation) []string {
+	results := make(map[string]string, len(operations))
+	for _, op := range operations {
+		if op != nil && op.Status == OperationStatusCompleted {
+			results[op.ID.String()] = op.Result
+		}
+	}
+
+	var warnings []string
+	for _, op := range operations {
+		if op == nil || op.Status != OperationStatusCompleted || isFinite(op.Result) {
+			continue
+		}
+
+		operand1 := resolveWarningOperand(op.Operand1, results)
+		operand2 := resolveWarningOperand(op.Operand2, results)
+		if !isFinite(operand1) || (op.OperationType != OperationTypeFactorial && !isFinite(operand2)) {
+			continue
+		}
+
+		if (op.OperationType == OperationTypeDivision || op.OperationType == OperationTypeModulo) && isZero(operand2) {
+			warnings = append(warnings, fmt.Sprintf("%s of %s by zero gave %s", op.OperationType.Name(), operand1, op.Result))
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf("%s of %s and %s overflowed to %s", op.OperationType.Name(), operand1, operand2, op.Result))
+	}
+
+	return warnings
+}
+
+// resolveWarningOperand заменяет ссылку ref:UUID результатом операции, если он известен.
+func resolveWarningOperand(operand string, results map[string]string) string {
+	if id, ok := strings.CutPrefix(operand, "ref:"); ok {
+		if result, found := results[id]; found {
+			return result
+		}
+	}
+	return operand
+}
+
+// isFinite сообщает, что текст не записывает бесконечность или NaN. Нечисловой текст
+// считается конечным: предупреждения касаются только значений IEEE 754.
+func isFinite(text string) bool {
+	value, err := strconv.ParseFloat(text, 64)
+	return err != nil || (!math.IsInf(value, 0) && !math.IsNaN(value))
+}
+
+func isZero(text string) bool {
+	value, err := strconv.ParseFloat(text, 64)
+	return err == nil && value == 0
+}
@@ -0,0 +1,45 @@
+package orchestrator_test
+
+import (
+	"testing"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResultWarnings(t *testing.T) {
+	completed := func(opType orchestrator.OperationType, operand1, operand2, result string) *orchestrator.Operation {
+		return &orchestrator.Operation{
+			ID:            uuid.New(),
+			OperationType: opType,
+			Operand1:      operand1,
+			Operand2:      operand2,
+			Result:        result,
+			Status:        orchestrator.OperationStatusCompleted,
+		}
+	}
+
+	t.Run("Finite results have no warnings", func(t *testing.T) {
+		ops := []*orchestrator.Operation{completed(orchestrator.OperationTypeDivision, "1", "4", "0.25")}
+		assert.Empty(t, orchestrator.ResultWarnings(ops))
+	})
+
+	t.Run("Division by zero is reported once", func(t *testing.T) {
+		division := completed(orchestrator.OperationTypeDivision, "0", "0", "NaN")
+		multiplication := completed(orchestrator.OperationTypeMultiplication, "ref:"+division.ID.String(), "3", "NaN")
+		assert.Equal(t, []string{"division of 0 by zero gave NaN"},
+			orchestrator.ResultWarnings([]*orchestrator.Operation{division, multiplication}))
+	})
+
+	t.Run("Overflow", func(t *testing.T) {
+		ops := []*orchestrator.Operation{completed(orchestrator.OperationTypeMultiplication, "1e308", "10", "+Inf")}
+		assert.Equal(t, []string{"multiplication of 1e308 and 10 overflowed to +Inf"}, orchestrator.ResultWarnings(ops))
+	})
+
+	t.Run("Unfinished operations are ignored", func(t *testing.T) {
+		op := completed(orchestrator.OperationTypeDivision, "1", "0", "")
+		op.Status = orchestrator.OperationStatusError
+		assert.Empty(t, orchestrator.ResultWarnings([]*orchestrator.Operation{op, nil}))
+	})
+}
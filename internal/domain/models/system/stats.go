@@ -0,0 +1,32 @@
+// Package system содержит модели сводной статистики сервисов.
+package system
+
+// DBPoolStats содержит состояние пула соединений с базой данных.
+type DBPoolStats struct {
+	TotalConns    int32 `json:"total_conns"`
+	IdleConns     int32 `json:"idle_conns"`
+	AcquiredConns int32 `json:"acquired_conns"`
+	MaxConns      int32 `json:"max_conns"`
+	// AcquireCount - сколько раз соединение было успешно получено из пула.
+	AcquireCount int64 `json:"acquire_count"`
+	// AcquireDurationMs - суммарное время ожидания полученных соединений в миллисекундах.
+	AcquireDurationMs int64 `json:"acquire_duration_ms"`
+}
+
+// AuthStats содержит статистику сервиса аутентификации.
+type AuthStats struct {
+	TotalUsers int         `json:"total_users"`
+	DBPool     DBPoolStats `json:"db_pool"`
+}
+
+// OrchestratorStats содержит статистику сервиса оркестрации.
+type OrchestratorStats struct {
+	// CalculationsByStatus - количество вычислений в каждом статусе.
+	CalculationsByStatus map[string]int `json:"calculations_by_status"`
+	TotalCalculations    int            `json:"total_calculations"`
+	TotalAgents          int            `json:"total_agents"`
+	// ActiveAgents - агенты в статусе ONLINE или BUSY.
+	ActiveAgents      int         `json:"active_agents"`
+	PendingOperations int         `json:"pending_operations"`
+	DBPool            DBPoolStats `json:"db_pool"`
+}
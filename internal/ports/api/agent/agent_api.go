@@ -6,6 +6,7 @@ import (
 
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/agent"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	"github.com/google/uuid"
 )
 
 // AgentWorker определяет интерфейс для горутины-агента, выполняющей операции.
@@ -16,8 +17,9 @@ type AgentWorker interface {
 	// Stop останавливает горутину агента.
 	Stop()
 
-	// PerformOperation выполняет арифметическую операцию.
-	PerformOperation(operation *orchestrator.Operation) (*orchestrator.Operation, error)
+	// PerformOperation выполняет арифметическую операцию. Идентификатор запроса из ctx
+	// сопровождает операцию в журналах агента.
+	PerformOperation(ctx context.Context, operation *orchestrator.Operation) (*orchestrator.Operation, error)
 
 	// GetStatus возвращает текущий статус агента.
 	GetStatus() *agent.Agent
@@ -25,3 +27,19 @@ type AgentWorker interface {
 	// UpdateStatus обновляет статус агента.
 	UpdateStatus(status agent.AgentStatus, load int)
 }
+
+// RemoteAgentRegistry определяет интерфейс для агентов, работающих в отдельных процессах:
+// агент регистрируется, забирает назначенные ему операции и сообщает результаты.
+type RemoteAgentRegistry interface {
+	// RegisterRemote регистрирует агента с указанной емкостью. Пустой agentID заменяется
+	// сгенерированным. Повторная регистрация возвращает невыполненные операции агента в ожидание.
+	RegisterRemote(ctx context.Context, agentID string, capacity int) (*agent.Agent, error)
+
+	// FetchOperations выдает агенту до limit назначенных ему операций (все при limit <= 0)
+	// и отмечает сигнал о его работе.
+	FetchOperations(ctx context.Context, agentID string, limit int) ([]orchestrator.AgentTask, error)
+
+	// ReportResult записывает результат операции, выданной агенту. Непустой errorMessage
+	// означает, что операция завершилась ошибкой.
+	ReportResult(ctx context.Context, agentID string, operationID uuid.UUID, result, errorMessage string) error
+}
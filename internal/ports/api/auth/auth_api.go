@@ -5,6 +5,7 @@ import (
 	"context"
 
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/auth"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/system"
 	"github.com/google/uuid"
 )
 
@@ -25,6 +26,24 @@ type UseCaseUser interface {
 	// Logout завершает сессию пользователя, аннулируя токен.
 	Logout(ctx context.Context, token string) error
 
+	// GetUser возвращает профиль пользователя без хеша пароля.
+	GetUser(ctx context.Context, userID uuid.UUID) (*auth.User, error)
+
+	// DeleteUser удаляет учетную запись пользователя после проверки пароля и отзывает его токены.
+	DeleteUser(ctx context.Context, userID uuid.UUID, password string) error
+
+	// ListSessions возвращает активные сессии пользователя.
+	ListSessions(ctx context.Context, userID uuid.UUID) ([]*auth.Session, error)
+
+	// RevokeSession завершает сессию пользователя по её идентификатору.
+	RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error
+
+	// RevokeAllSessions завершает все сессии пользователя.
+	RevokeAllSessions(ctx context.Context, userID uuid.UUID) error
+
+	// GetStats возвращает сводную статистику сервиса аутентификации.
+	GetStats(ctx context.Context) (*system.AuthStats, error)
+
 	// Close closes any resources used by this interface implementation
 	Close() error
 }
@@ -19,12 +19,22 @@ type AgentPool interface {
 	// GetAvailableAgent находит доступного агента для операции.
 	GetAvailableAgent(operationType int) (*agent.Agent, error)
 
-	// AssignOperation назначает операцию агенту.
-	AssignOperation(agentID string, operation *orchestrator.Operation) error
+	// AssignOperation назначает операцию агенту. Идентификатор запроса из ctx
+	// сопровождает операцию в журналах агента.
+	AssignOperation(ctx context.Context, agentID string, operation *orchestrator.Operation) error
 
 	// GetAgentStatus получает статус конкретного агента.
 	GetAgentStatus(agentID string) (*agent.Agent, error)
 
+	// SetAgentCapacity меняет емкость работающего агента и возвращает его обновленные метрики.
+	SetAgentCapacity(agentID string, capacity int) (*agent.AgentStats, error)
+
 	// ListAgents возвращает список всех агентов.
 	ListAgents() ([]*agent.Agent, error)
+
+	// GetPoolStats возвращает метрики каждого агента и агрегированные значения по пулу.
+	GetPoolStats() (*agent.PoolStats, error)
+
+	// GetTimingProfile возвращает настроенное и фактически применяемое агентами время выполнения операций.
+	GetTimingProfile() (*agent.TimingProfile, error)
 }
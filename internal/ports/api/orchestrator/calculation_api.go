@@ -4,20 +4,75 @@ package orchestrator
 import (
 	"context"
 
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/agent"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/system"
 	"github.com/google/uuid"
 )
 
 // UseCaseCalculation определяет основной порт для операций вычисления.
 type UseCaseCalculation interface {
 	// CalculateExpression создаёт новое вычисление для выражения.
-	CalculateExpression(ctx context.Context, userID uuid.UUID, expression string) (*orchestrator.Calculation, error)
+	// source указывает канал, через который было отправлено выражение.
+	CalculateExpression(ctx context.Context, userID uuid.UUID, expression string, source orchestrator.CalculationSource) (*orchestrator.Calculation, error)
 
 	// GetCalculation возвращает вычисление по ID.
 	GetCalculation(ctx context.Context, calculationID uuid.UUID, userID uuid.UUID) (*orchestrator.Calculation, error)
 
-	// ListCalculations возвращает список вычислений пользователя.
-	ListCalculations(ctx context.Context, userID uuid.UUID) ([]*orchestrator.Calculation, error)
+	// GetOperation возвращает операцию по ID, если ее вычисление принадлежит пользователю.
+	GetOperation(ctx context.Context, operationID uuid.UUID, userID uuid.UUID) (*orchestrator.Operation, error)
+
+	// AdminGetCalculation возвращает вычисление любого пользователя, включая удаленные,
+	// вместе со служебными данными, например версией клиента. Только для администраторов.
+	AdminGetCalculation(ctx context.Context, calculationID uuid.UUID) (*orchestrator.Calculation, error)
+
+	// ListCalculations возвращает страницу вычислений пользователя с учетом фильтра.
+	ListCalculations(ctx context.Context, userID uuid.UUID, filter orchestrator.CalculationFilter) (*orchestrator.CalculationPage, error)
+
+	// GetResultStats возвращает агрегаты по числовым результатам завершенных вычислений пользователя.
+	GetResultStats(ctx context.Context, userID uuid.UUID) (*orchestrator.ResultStats, error)
+
+	// SubscribeCalculation подписывает пользователя на изменения статуса его вычисления.
+	// Канал получает текущее состояние и каждую смену статуса и закрывается после
+	// конечного статуса или отмены ctx.
+	SubscribeCalculation(ctx context.Context, calculationID uuid.UUID, userID uuid.UUID) (<-chan orchestrator.CalculationEvent, error)
+
+	// CancelCalculation отменяет вычисление пользователя вместе с его незавершенными операциями.
+	CancelCalculation(ctx context.Context, calculationID uuid.UUID, userID uuid.UUID) error
+
+	// DeleteCalculation удаляет вычисление пользователя вместе с его операциями.
+	DeleteCalculation(ctx context.Context, calculationID uuid.UUID, userID uuid.UUID) error
+
+	// CompareExpressions вычисляет два выражения и сравнивает их значения
+	// с учетом допустимой абсолютной погрешности.
+	CompareExpressions(ctx context.Context, expressionA, expressionB string, tolerance float64) (*orchestrator.ExpressionComparison, error)
+
+	// DiffCalculations сравнивает выражения и результаты двух вычислений пользователя
+	// и считает разницу результатов, если оба они числа.
+	DiffCalculations(ctx context.Context, calculationIDA, calculationIDB uuid.UUID, userID uuid.UUID) (*orchestrator.CalculationDiff, error)
+
+	// PreviewExpression показывает, как выражение будет сгруппировано по приоритету операций,
+	// не создавая вычисления.
+	PreviewExpression(ctx context.Context, expression string, options orchestrator.PreviewOptions) (*orchestrator.ExpressionPreview, error)
+
+	// ValidateExpression проверяет синтаксис выражения без создания вычисления и возвращает
+	// позицию и описание первой ошибки.
+	ValidateExpression(ctx context.Context, expression string) (*orchestrator.ExpressionValidation, error)
+
+	// GetPoolStats возвращает метрики пула агентов: нагрузку, статус и счетчики операций
+	// каждого агента, а также суммарную глубину очередей.
+	GetPoolStats(ctx context.Context) (*agent.PoolStats, error)
+
+	// GetTimingProfile возвращает настроенное время выполнения операций и фактические
+	// значения, применяемые каждым агентом пула.
+	GetTimingProfile(ctx context.Context) (*agent.TimingProfile, error)
+
+	// SetAgentCapacity меняет емкость работающего агента пула и возвращает его обновленные метрики.
+	SetAgentCapacity(ctx context.Context, agentID string, capacity int) (*agent.AgentStats, error)
+
+	// GetSystemStats возвращает сводную статистику сервиса: вычисления по статусам,
+	// ожидающие операции, активных агентов и состояние пула соединений с базой данных.
+	GetSystemStats(ctx context.Context) (*system.OrchestratorStats, error)
 
 	// ProcessPendingOperations запускает обработку ожидающих операций.
 	ProcessPendingOperations(ctx context.Context) error
@@ -0,0 +1,14 @@
+// Package orchestrator содержит интерфейс для отмены операций.
+package orchestrator
+
+import "github.com/google/uuid"
+
+// OperationCancellation хранит идентификаторы отмененных операций,
+// чтобы процессор и агенты пропускали их выполнение.
+type OperationCancellation interface {
+	// CancelOperations помечает операции как отмененные.
+	CancelOperations(operationIDs ...uuid.UUID)
+
+	// IsCancelled сообщает, была ли операция отменена.
+	IsCancelled(operationID uuid.UUID) bool
+}
@@ -2,6 +2,8 @@
 package agent
 
 import (
+	"time"
+
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/agent"
 )
 
@@ -22,6 +24,13 @@ type AgentStorage interface {
 	// UpdateStats обновляет статистику выполненных операций агента.
 	UpdateStats(id string, completed bool, failed bool) error
 
+	// Heartbeat записывает время последнего сигнала агента о том, что он работает.
+	Heartbeat(id string, at time.Time) error
+
+	// EvictStale переводит в offline агентов, от которых не было сигнала дольше timeout
+	// к моменту now, и возвращает их ID. Агенты без сигналов не учитываются.
+	EvictStale(timeout time.Duration, now time.Time) []string
+
 	// List возвращает список всех агентов.
 	List() []*agent.Agent
 
@@ -20,12 +20,21 @@ type TokenRepository interface {
 	// FindByID находит токен по его ID.
 	FindByID(ctx context.Context, id uuid.UUID) (*auth.Token, error)
 
+	// FindByUserID возвращает страницу токенов пользователя, начиная с самых новых:
+	// не больше limit токенов после пропуска первых offset. Неположительный limit
+	// возвращает все токены начиная с offset.
+	FindByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*auth.Token, error)
+
 	// RevokeToken аннулирует токен.
 	RevokeToken(ctx context.Context, tokenStr string) error
 
+	// RevokeIfActive аннулирует токен, если он еще не отозван. Возвращает false, если
+	// действующий токен не найден, например его уже отозвал параллельный запрос.
+	RevokeIfActive(ctx context.Context, tokenStr string) (bool, error)
+
 	// RevokeAllUserTokens аннулирует все токены пользователя.
 	RevokeAllUserTokens(ctx context.Context, userID uuid.UUID) error
 
-	// DeleteExpiredTokens удаляет просроченные токены.
-	DeleteExpiredTokens(ctx context.Context, before time.Time) error
+	// DeleteExpiredTokens удаляет токены, истекшие до before, и возвращает их количество.
+	DeleteExpiredTokens(ctx context.Context, before time.Time) (int64, error)
 }
@@ -24,4 +24,6 @@ type UserRepository interface {
 
 	// Delete удаляет пользователя.
 	Delete(ctx context.Context, id uuid.UUID) error
+	// Count возвращает общее количество пользователей.
+	Count(ctx context.Context) (int, error)
 }
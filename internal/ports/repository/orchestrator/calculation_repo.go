@@ -10,14 +10,21 @@ import (
 
 // CalculationRepository определяет интерфейс для работы с хранилищем вычислений.
 type CalculationRepository interface {
-	// Create создаёт новое вычисление.
+	// Create создаёт новое вычисление. Если у пользователя уже есть вычисление с тем же
+	// ключом идемпотентности, возвращает ошибку domainerrors.ErrIdempotencyKeyConflict.
 	Create(ctx context.Context, calculation *orchestrator.Calculation) (*orchestrator.Calculation, error)
 
-	// FindByID находит вычисление по ID.
-	FindByID(ctx context.Context, id uuid.UUID) (*orchestrator.Calculation, error)
+	// FindByID находит вычисление по ID. Удаленные вычисления находятся только
+	// с опцией orchestrator.IncludeDeleted.
+	FindByID(ctx context.Context, id uuid.UUID, opts ...orchestrator.FindOption) (*orchestrator.Calculation, error)
 
-	// FindByUserID находит вычисления пользователя.
-	FindByUserID(ctx context.Context, userID uuid.UUID) ([]*orchestrator.Calculation, error)
+	// FindByIdempotencyKey находит вычисление пользователя по ключу идемпотентности,
+	// включая удаленные. Если вычисления нет, возвращает nil без ошибки.
+	FindByIdempotencyKey(ctx context.Context, userID uuid.UUID, key string) (*orchestrator.Calculation, error)
+
+	// FindByUserID находит страницу вычислений пользователя и возвращает общее количество
+	// вычислений, удовлетворяющих фильтру.
+	FindByUserID(ctx context.Context, userID uuid.UUID, filter orchestrator.CalculationFilter) ([]*orchestrator.Calculation, int, error)
 
 	// Update обновляет вычисление.
 	Update(ctx context.Context, calculation *orchestrator.Calculation) error
@@ -25,6 +32,19 @@ type CalculationRepository interface {
 	// UpdateStatus обновляет статус вычисления.
 	UpdateStatus(ctx context.Context, id uuid.UUID, status orchestrator.CalculationStatus, result string, errorMsg string) error
 
-	// Delete удаляет вычисление.
+	// UpdateDisplayResult сохраняет результат вычисления, округленный для отображения.
+	UpdateDisplayResult(ctx context.Context, id uuid.UUID, displayResult string) error
+
+	// UpdateWarnings сохраняет предупреждения к результату вычисления.
+	UpdateWarnings(ctx context.Context, id uuid.UUID, warnings []string) error
+
+	// Delete помечает вычисление удаленным. Запись остается в хранилище,
+	// но не возвращается обычными выборками.
 	Delete(ctx context.Context, id uuid.UUID) error
+
+	// CountByStatus возвращает количество вычислений в каждом статусе.
+	CountByStatus(ctx context.Context) (map[orchestrator.CalculationStatus]int, error)
+
+	// ResultStats возвращает агрегаты по числовым результатам завершенных вычислений пользователя.
+	ResultStats(ctx context.Context, userID uuid.UUID) (*orchestrator.ResultStats, error)
 }
@@ -0,0 +1,21 @@
+package orchestrator
+
+import (
+	"context"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	"github.com/google/uuid"
+)
+
+// DeadLetterRepository определяет интерфейс для работы с хранилищем операций,
+// не выполненных после всех попыток.
+type DeadLetterRepository interface {
+	// Create сохраняет операцию в хранилище недоставленных.
+	Create(ctx context.Context, deadLetter *orchestrator.DeadLetter) error
+
+	// FindPending возвращает записи, которые еще не запускались повторно, начиная с самых старых.
+	FindPending(ctx context.Context, limit int) ([]*orchestrator.DeadLetter, error)
+
+	// MarkReplayed отмечает запись как повторно запущенную.
+	MarkReplayed(ctx context.Context, id uuid.UUID) error
+}
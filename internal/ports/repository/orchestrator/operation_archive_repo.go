@@ -0,0 +1,20 @@
+package orchestrator
+
+import (
+	"context"
+	"time"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	"github.com/google/uuid"
+)
+
+// OperationArchiveRepository определяет интерфейс для сжатия операций завершенных вычислений
+// в запись вычисления.
+type OperationArchiveRepository interface {
+	// FindCompactable возвращает ID не более limit завершенных вычислений, которые не менялись
+	// с момента before и еще не сжаты, начиная с самых давних.
+	FindCompactable(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error)
+
+	// ArchiveOperations сохраняет операции в записи вычисления и отмечает его сжатым.
+	ArchiveOperations(ctx context.Context, calculationID uuid.UUID, operations []*orchestrator.Operation) error
+}
@@ -16,6 +16,11 @@ type OperationRepository interface {
 	// CreateBatch создаёт несколько операций.
 	CreateBatch(ctx context.Context, operations []*orchestrator.Operation) error
 
+	// CreateBatchPartial сохраняет операции по отдельности: ошибка одной операции не отменяет
+	// сохранение остальных. Возвращает результат для каждой операции в порядке пакета;
+	// ошибка возвращается только если пакет не удалось обработать целиком.
+	CreateBatchPartial(ctx context.Context, operations []*orchestrator.Operation) ([]orchestrator.OperationInsertResult, error)
+
 	// FindByID находит операцию по ID.
 	FindByID(ctx context.Context, id uuid.UUID) (*orchestrator.Operation, error)
 
@@ -31,6 +36,19 @@ type OperationRepository interface {
 	// UpdateStatus обновляет статус операции.
 	UpdateStatus(ctx context.Context, id uuid.UUID, status orchestrator.OperationStatus, result string, errorMsg string) error
 
+	// UpdateStatusBatch обновляет статусы нескольких операций в одной транзакции:
+	// если хотя бы одно обновление не удалось, не применяется ни одно.
+	UpdateStatusBatch(ctx context.Context, updates []orchestrator.OperationStatusUpdate) error
+
 	// AssignAgent назначает агента для выполнения операции.
 	AssignAgent(ctx context.Context, operationID uuid.UUID, agentID string) error
+	// CountByStatus возвращает количество операций в указанном статусе.
+	CountByStatus(ctx context.Context, status orchestrator.OperationStatus) (int, error)
+
+	// CountPendingByUserID возвращает количество ожидающих и выполняемых операций
+	// в вычислениях пользователя.
+	CountPendingByUserID(ctx context.Context, userID uuid.UUID) (int, error)
+
+	// DeleteByCalculationID удаляет все операции вычисления и возвращает их количество.
+	DeleteByCalculationID(ctx context.Context, calculationID uuid.UUID) (int, error)
 }
@@ -0,0 +1,13 @@
+package orchestrator
+
+import "context"
+
+// ResultCache определяет интерфейс общего для всех пользователей кэша результатов выражений.
+// Ключом служит нормализованное выражение без ссылок на другие вычисления.
+type ResultCache interface {
+	// Get возвращает сохраненный результат выражения; found равен false, если результата нет.
+	Get(ctx context.Context, expression string) (result string, found bool, err error)
+
+	// Set сохраняет результат выражения.
+	Set(ctx context.Context, expression string, result string) error
+}
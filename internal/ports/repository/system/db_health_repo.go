@@ -0,0 +1,7 @@
+package system
+
+// DBHealthState определяет интерфейс для получения последнего известного состояния базы данных.
+type DBHealthState interface {
+	// Err возвращает причину недоступности базы данных или nil, если база доступна.
+	Err() error
+}
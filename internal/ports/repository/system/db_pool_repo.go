@@ -0,0 +1,10 @@
+// Package system содержит интерфейс для получения состояния хранилища.
+package system
+
+import "github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/system"
+
+// DBPoolStatsProvider определяет интерфейс для получения состояния пула соединений с базой данных.
+type DBPoolStatsProvider interface {
+	// PoolStats возвращает текущее состояние пула соединений.
+	PoolStats() system.DBPoolStats
+}
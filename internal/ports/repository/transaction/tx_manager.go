@@ -0,0 +1,11 @@
+// Package transaction содержит интерфейс транзакций, общий для репозиториев всех сервисов.
+package transaction
+
+import "context"
+
+// TxManager выполняет несколько обращений к хранилищу в одной транзакции.
+type TxManager interface {
+	// WithinTransaction вызывает fn с контекстом, привязанным к транзакции.
+	// Транзакция фиксируется, если fn вернула nil, и откатывается в противном случае.
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -16,6 +16,13 @@ type ExpressionParser interface {
 	// Validate проверяет корректность выражения.
 	Validate(ctx context.Context, expression string) error
 
+	// Evaluate синхронно вычисляет значение выражения без разбиения на операции.
+	Evaluate(ctx context.Context, expression string) (float64, error)
+
+	// Parenthesize возвращает выражение, в котором каждая вложенная операция заключена в скобки
+	// в соответствии с приоритетом и ассоциативностью операторов.
+	Parenthesize(ctx context.Context, expression string, options orchestrator.PreviewOptions) (string, error)
+
 	// SetCalculationID устанавливает ID вычисления для всех операций.
 	SetCalculationID(operations []*orchestrator.Operation, calculationID uuid.UUID)
 }
@@ -5,6 +5,9 @@ import (
 	"context"
 )
 
+// MinPasswordLength - минимальная длина пароля по политике паролей.
+const MinPasswordLength = 8
+
 // Service определяет интерфейс для работы с паролями.
 type Service interface {
 	// Hash хеширует пароль
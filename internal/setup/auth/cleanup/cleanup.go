@@ -0,0 +1,13 @@
+// Package cleanup содержит конфигурацию периодического удаления истекших токенов.
+package cleanup
+
+import "time"
+
+// Config содержит конфигурацию периодического удаления истекших токенов.
+type Config struct {
+	// Interval - период удаления истекших токенов. Ноль отключает удаление.
+	Interval time.Duration `yaml:"interval" env:"TOKEN_CLEANUP_INTERVAL" env-default:"1h"`
+	// Jitter - доля случайного отклонения периода, от 0 до 1, чтобы экземпляры сервиса
+	// не удаляли токены одновременно.
+	Jitter float64 `yaml:"jitter" env:"TOKEN_CLEANUP_JITTER" env-default:"0.1"`
+}
@@ -5,4 +5,6 @@ package grpc
 type Config struct {
 	Host string `yaml:"host" env:"AUTH_GRPC_HOST" env-default:"0.0.0.0"`
 	Port int    `yaml:"port" env:"AUTH_GRPC_PORT" env-default:"50052"`
+	// HealthPort - порт HTTP сервера проверок /healthz и /readyz. Ноль отключает сервер.
+	HealthPort int `yaml:"health_port" env:"AUTH_HEALTH_PORT" env-default:"8081"`
 }
@@ -0,0 +1,18 @@
+// Package login содержит конфигурацию ограничения неудачных попыток входа.
+package login
+
+import "time"
+
+// Config содержит конфигурацию ограничения неудачных попыток входа.
+type Config struct {
+	// MaxFailures - число неудачных попыток для одного логина или IP-адреса, после которого
+	// вход с IP-адреса блокируется, а для логина вводится задержка. Ноль отключает ограничение.
+	MaxFailures int `yaml:"max_failures" env:"LOGIN_MAX_FAILURES" env-default:"5"`
+	// FailureWindow - окно, в течение которого учитываются неудачные попытки.
+	FailureWindow time.Duration `yaml:"failure_window" env:"LOGIN_FAILURE_WINDOW" env-default:"15m"`
+	// DelayBase - задержка перед следующей попыткой входа в логин после MaxFailures
+	// неудачных попыток; удваивается с каждой следующей неудачей. Ноль отключает задержку.
+	DelayBase time.Duration `yaml:"delay_base" env:"LOGIN_DELAY_BASE" env-default:"1s"`
+	// MaxDelay - верхняя граница задержки для логина.
+	MaxDelay time.Duration `yaml:"max_delay" env:"LOGIN_MAX_DELAY" env-default:"1m"`
+}
@@ -0,0 +1,15 @@
+// Package password содержит конфигурацию хеширования паролей.
+package password
+
+// Config содержит конфигурацию хеширования паролей.
+type Config struct {
+	// Algorithm - алгоритм для новых хешей: bcrypt, argon2id или scrypt.
+	// Хеши, созданные другими алгоритмами, по-прежнему проверяются по своему префиксу.
+	Algorithm string `yaml:"algorithm" env:"PASSWORD_HASH_ALGORITHM" env-default:"bcrypt"`
+	// Argon2Memory - объем памяти Argon2id в КиБ.
+	Argon2Memory uint32 `yaml:"argon2_memory" env:"PASSWORD_ARGON2_MEMORY" env-default:"65536"`
+	// Argon2Time - число проходов Argon2id по памяти.
+	Argon2Time uint32 `yaml:"argon2_time" env:"PASSWORD_ARGON2_TIME" env-default:"1"`
+	// Argon2Parallelism - число потоков Argon2id.
+	Argon2Parallelism uint8 `yaml:"argon2_parallelism" env:"PASSWORD_ARGON2_PARALLELISM" env-default:"4"`
+}
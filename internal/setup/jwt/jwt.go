@@ -9,4 +9,17 @@ type Config struct {
 	AccessTokenTTL  time.Duration `yaml:"access_token_ttl" env:"JWT_ACCESS_TOKEN_TTL" env-default:"15m"`
 	RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl" env:"JWT_REFRESH_TOKEN_TTL" env-default:"24h"`
 	BCryptCost      int           `yaml:"bcrypt_cost" env:"JWT_BCRYPT_COST" env-default:"10"`
+	// RefreshReuseDetection отзывает все токены пользователя при повторном предъявлении отозванного refresh токена.
+	RefreshReuseDetection bool `yaml:"refresh_reuse_detection" env:"JWT_REFRESH_REUSE_DETECTION" env-default:"true"`
+	// RevokeOnPasswordChange отзывает все refresh токены пользователя после смены пароля.
+	RevokeOnPasswordChange bool `yaml:"revoke_on_password_change" env:"JWT_REVOKE_ON_PASSWORD_CHANGE" env-default:"true"`
+	// RefreshMaxAge - наибольший возраст семейства refresh токенов, отсчитываемый от входа.
+	// После него обновление отклоняется даже для действительного токена. Ноль отключает ограничение.
+	RefreshMaxAge time.Duration `yaml:"refresh_max_age" env:"JWT_REFRESH_MAX_AGE" env-default:"0s"`
+	// SigningMethod - алгоритм подписи токенов: HS256 (общий секрет) или RS256 (пара ключей RSA).
+	SigningMethod string `yaml:"signing_method" env:"JWT_SIGNING_METHOD" env-default:"HS256"`
+	// PrivateKeyFile - путь к закрытому ключу RSA в формате PEM (для RS256).
+	PrivateKeyFile string `yaml:"private_key_file" env:"JWT_PRIVATE_KEY_FILE"`
+	// PublicKeyFile - путь к открытому ключу RSA в формате PEM (для RS256).
+	PublicKeyFile string `yaml:"public_key_file" env:"JWT_PUBLIC_KEY_FILE"`
 }
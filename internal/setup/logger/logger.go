@@ -10,4 +10,13 @@ type Config struct {
 	Caller       bool   `env:"LOGGER_CALLER" env-default:"true"`
 	Stacktrace   bool   `env:"LOGGER_STACKTRACE" env-default:"true"`
 	Model        string `env:"LOGGER_MODEL" env-default:"development"`
+	// SamplingInitial и SamplingThereafter задают сэмплирование повторяющихся записей ниже уровня
+	// Error: из записей с одинаковыми уровнем и сообщением за секунду пишутся первые SamplingInitial,
+	// затем каждая SamplingThereafter-я. Ошибки пишутся всегда. Ноль в SamplingInitial отключает
+	// сэмплирование.
+	SamplingInitial    int `env:"LOGGER_SAMPLING_INITIAL" env-default:"100"`
+	SamplingThereafter int `env:"LOGGER_SAMPLING_THEREAFTER" env-default:"100"`
+	// RedactFields - имена полей, значения которых маскируются в журнале при любом формате.
+	// Поле маскируется, если его имя содержит одно из значений без учета регистра.
+	RedactFields []string `env:"LOGGER_REDACT_FIELDS" env-separator:"," env-default:"password,token,secret,authorization"`
 }
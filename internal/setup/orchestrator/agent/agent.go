@@ -8,5 +8,152 @@ type Config struct {
 	TimeSubtraction     time.Duration `env:"TIME_SUBTRACTION" env-default:"1s"`
 	TimeMultiplications time.Duration `env:"TIME_MULTIPLICATIONS" env-default:"2s"`
 	TimeDivisions       time.Duration `env:"TIME_DIVISIONS" env-default:"2s"`
-	MaxOperations       int           `env:"MAX_OPERATIONS" env-default:"100"`
+	TimeModulo          time.Duration `env:"TIME_MODULO" env-default:"2s"`
+	TimeFactorial       time.Duration `env:"TIME_FACTORIAL" env-default:"2s"`
+	// Cost* - доля емкости агента, которую занимает одна операция соответствующего типа.
+	CostAddition       int `env:"COST_ADDITION" env-default:"1"`
+	CostSubtraction    int `env:"COST_SUBTRACTION" env-default:"1"`
+	CostMultiplication int `env:"COST_MULTIPLICATION" env-default:"1"`
+	CostDivision       int `env:"COST_DIVISION" env-default:"1"`
+	CostModulo         int `env:"COST_MODULO" env-default:"1"`
+	CostFactorial      int `env:"COST_FACTORIAL" env-default:"1"`
+	MaxOperations      int `env:"MAX_OPERATIONS" env-default:"100"`
+	// MaxExpressionLength - наибольшая длина выражения в байтах. Более длинное выражение
+	// отклоняется до разбора. Ноль отключает проверку.
+	MaxExpressionLength int `env:"MAX_EXPRESSION_LENGTH" env-default:"10000"`
+	// ImplicitZeroOperand считает недостающий последний операнд нулем: 5+ вычисляется как 5+0.
+	// По умолчанию выражение, оканчивающееся оператором, отклоняется.
+	ImplicitZeroOperand bool `env:"IMPLICIT_ZERO_OPERAND" env-default:"false"`
+	// ResultPrecision - количество знаков после запятой в результате вычисления. Результаты
+	// промежуточных операций не округляются. Отрицательное значение отключает округление.
+	ResultPrecision int `env:"RESULT_PRECISION" env-default:"-1"`
+	// ResultRoundingMode - способ округления: half_even, half_up или down.
+	ResultRoundingMode string `env:"RESULT_ROUNDING_MODE" env-default:"half_even"`
+	// ResultPrecisionByOperation переопределяет ResultPrecision по последней операции
+	// выражения: division:10,addition:0.
+	ResultPrecisionByOperation map[string]int `env:"RESULT_PRECISION_BY_OPERATION" env-separator:","`
+	// ArithmeticBackend - представление чисел при вычислениях: float или decimal.
+	// decimal не теряет точность на больших и дробных операндах, но работает медленнее.
+	ArithmeticBackend string `env:"ARITHMETIC_BACKEND" env-default:"float"`
+	// DecimalDivisionPrecision - количество знаков после запятой в частном для decimal.
+	DecimalDivisionPrecision int32 `env:"DECIMAL_DIVISION_PRECISION" env-default:"16"`
+	// DecimalPreserveScale - сохранять в результате decimal масштаб операндов (2.50+2.50 = 5.00).
+	DecimalPreserveScale bool `env:"DECIMAL_PRESERVE_SCALE" env-default:"false"`
+	// DivisionByZeroPolicy - результат деления на ноль в режиме float: error, infinity
+	// (inf, -inf или nan по IEEE 754) или nan. Нестрогие режимы завершают вычисление
+	// с предупреждением.
+	DivisionByZeroPolicy string `env:"DIVISION_BY_ZERO_POLICY" env-default:"error"`
+	// LenientDivision - устаревший синоним DIVISION_BY_ZERO_POLICY=infinity, действует,
+	// если политика не задана или равна error.
+	LenientDivision bool `env:"LENIENT_DIVISION" env-default:"false"`
+	// ResultDisplayPrecision - количество знаков после запятой в результате вычисления для
+	// отображения, который в режиме decimal сохраняется рядом с точным. Округляется способом
+	// ResultRoundingMode. Отрицательное значение отключает сохранение.
+	ResultDisplayPrecision int `env:"RESULT_DISPLAY_PRECISION" env-default:"-1"`
+	// Retry* - политика повторного назначения операции агенту: задержка перед n-м
+	// повтором равна RetryBaseDelay * RetryMultiplier^(n-1), но не больше RetryMaxDelay,
+	// и случайно смещается в пределах ±RetryJitter от своего значения.
+	RetryMaxAttempts int           `env:"RETRY_MAX_ATTEMPTS" env-default:"3"`
+	RetryBaseDelay   time.Duration `env:"RETRY_BASE_DELAY" env-default:"100ms"`
+	RetryMultiplier  float64       `env:"RETRY_MULTIPLIER" env-default:"2"`
+	RetryMaxDelay    time.Duration `env:"RETRY_MAX_DELAY" env-default:"2s"`
+	RetryJitter      float64       `env:"RETRY_JITTER" env-default:"0.2"`
+	// OperationTimeout - срок операции: за это время она должна быть назначена агенту, и столько же
+	// у агента есть на ее выполнение. Операция, не уложившаяся в срок, завершается ошибкой с
+	// сообщением timeout.
+	OperationTimeout time.Duration `env:"OPERATION_TIMEOUT" env-default:"10s"`
+	// OperationTimeoutByOperation переопределяет OperationTimeout для отдельных типов операций:
+	// factorial:30s,division:15s.
+	OperationTimeoutByOperation map[string]time.Duration `env:"OPERATION_TIMEOUT_BY_OPERATION" env-separator:","`
+	// OperationAttemptTimeout - время одной попытки назначения операции агенту.
+	OperationAttemptTimeout time.Duration `env:"OPERATION_ATTEMPT_TIMEOUT" env-default:"5s"`
+	// StaleRecomputeAfter включает пересчет статуса при чтении вычисления,
+	// находящегося в IN_PROGRESS дольше указанного времени. Ноль отключает пересчет.
+	StaleRecomputeAfter time.Duration `env:"STALE_RECOMPUTE_AFTER" env-default:"0s"`
+	// EmptyOperationsGrace - время после создания вычисления, в течение которого
+	// отсутствие операций не переводит его в ERROR. Ноль отключает окно.
+	EmptyOperationsGrace time.Duration `env:"EMPTY_OPERATIONS_GRACE" env-default:"0s"`
+	// ParsingTimeout - максимальное время разбора выражения; по его истечении
+	// вычисление помечается ошибкой.
+	ParsingTimeout time.Duration `env:"PARSING_TIMEOUT" env-default:"30s"`
+	// PartialBatchInsert сохраняет операции выражения по отдельности: ошибка одной операции
+	// не откатывает остальные, но они отменяются, а вычисление переводится в ERROR.
+	PartialBatchInsert bool `env:"PARTIAL_BATCH_INSERT" env-default:"false"`
+	// RejectDuplicateOperationIDs переводит вычисление в ERROR, если разбор выдал операциям
+	// одинаковые ID. По умолчанию повторам выдаются новые ID.
+	RejectDuplicateOperationIDs bool `env:"REJECT_DUPLICATE_OPERATION_IDS" env-default:"false"`
+	// AgentEstimate добавляет в ответ на создание вычисления агентов, которые при текущем
+	// состоянии пула вероятно выполнят его операции.
+	AgentEstimate bool `env:"CALCULATION_AGENT_ESTIMATE" env-default:"false"`
+	// CalculationReferences разрешает ссылаться в выражении на результаты прошлых
+	// вычислений пользователя: calc:{uuid}+5.
+	CalculationReferences bool `env:"CALCULATION_REFERENCES" env-default:"false"`
+	// SupportedOperations - типы операций, которые выполняют агенты: addition, subtraction,
+	// multiplication, division, modulo, factorial. Выражения с другими операциями отклоняются при
+	// создании вычисления. Пустой список отключает проверку.
+	SupportedOperations []string `env:"SUPPORTED_OPERATIONS" env-separator:","`
+	// StatusFlushInterval включает пакетную запись статусов выполненных операций: статусы
+	// накапливаются и записываются одним запросом с этим интервалом. Ноль - запись сразу.
+	StatusFlushInterval time.Duration `env:"STATUS_FLUSH_INTERVAL" env-default:"0s"`
+	// StatusFlushBatchSize - количество статусов, при котором пакет записывается до интервала.
+	StatusFlushBatchSize int `env:"STATUS_FLUSH_BATCH_SIZE" env-default:"100"`
+	// OperationsCompactionDelay включает сжатие операций: через это время после завершения
+	// вычисления его операции сохраняются в JSON в записи вычисления, а их строки удаляются.
+	// Ноль отключает сжатие.
+	OperationsCompactionDelay time.Duration `env:"OPERATIONS_COMPACTION_DELAY" env-default:"0s"`
+	// OperationsCompactionInterval - период поиска вычислений для сжатия.
+	OperationsCompactionInterval time.Duration `env:"OPERATIONS_COMPACTION_INTERVAL" env-default:"1m"`
+	// OperationsCompactionBatchSize - наибольшее количество вычислений, сжимаемых за один проход.
+	OperationsCompactionBatchSize int `env:"OPERATIONS_COMPACTION_BATCH_SIZE" env-default:"100"`
+	// AutoscaleMaxWorkers включает автомасштабирование пула агентов: пока ожидающих операций
+	// больше AutoscaleBacklogThreshold дольше AutoscaleSustain, добавляются воркеры до этого числа.
+	// Ноль отключает масштабирование, и воркеров всегда COMPUTING_POWER.
+	AutoscaleMaxWorkers int `env:"AUTOSCALE_MAX_WORKERS" env-default:"0"`
+	// AutoscaleMinWorkers - до этого числа останавливаются простаивающие воркеры.
+	AutoscaleMinWorkers int `env:"AUTOSCALE_MIN_WORKERS" env-default:"1"`
+	// AutoscaleBacklogThreshold - количество ожидающих операций, при превышении которого пул растет.
+	AutoscaleBacklogThreshold int `env:"AUTOSCALE_BACKLOG_THRESHOLD" env-default:"20"`
+	// AutoscaleSustain - сколько очередь должна превышать порог, чтобы добавился воркер.
+	AutoscaleSustain time.Duration `env:"AUTOSCALE_SUSTAIN" env-default:"10s"`
+	// AutoscaleIdle - сколько воркер должен простаивать, чтобы его остановили.
+	AutoscaleIdle time.Duration `env:"AUTOSCALE_IDLE" env-default:"1m"`
+	// AutoscaleInterval - период проверки очереди ожидающих операций.
+	AutoscaleInterval time.Duration `env:"AUTOSCALE_INTERVAL" env-default:"1s"`
+	// HeartbeatInterval - интервал сигналов агентов о том, что их цикл обработки работает.
+	HeartbeatInterval time.Duration `env:"AGENT_HEARTBEAT_INTERVAL" env-default:"1s"`
+	// HeartbeatTimeout - время без сигналов, после которого агент переводится в offline,
+	// а операции из его очереди возвращаются в ожидание. Ноль отключает вытеснение.
+	HeartbeatTimeout time.Duration `env:"AGENT_HEARTBEAT_TIMEOUT" env-default:"0s"`
+	// PendingOperationsBudget - наибольшее количество ожидающих и выполняемых операций
+	// пользователя вместе с операциями нового выражения. Выражение сверх бюджета отклоняется.
+	// Ноль отключает проверку.
+	PendingOperationsBudget int `env:"PENDING_OPERATIONS_BUDGET" env-default:"0"`
+	// DBHealthInterval - период проверки базы данных, по результатам которой новые вычисления
+	// отклоняются с кодом 503, пока база недоступна. Ноль отключает проверку.
+	DBHealthInterval time.Duration `env:"SUBMISSION_DB_HEALTH_INTERVAL" env-default:"5s"`
+	// DBHealthFailureThreshold - количество неудачных проверок подряд, после которого база
+	// считается недоступной. Первая успешная проверка снова открывает прием вычислений.
+	DBHealthFailureThreshold int `env:"SUBMISSION_DB_HEALTH_FAILURES" env-default:"2"`
+	// ListResultCap - наибольшее количество вычислений в ответе на запрос списка, даже если
+	// клиент запросил больше. Ноль оставляет только общий предел в 100 записей.
+	ListResultCap int `env:"LIST_RESULT_CAP" env-default:"0"`
+	// ResultCacheSize - количество выражений в общем кэше результатов. Выражение из кэша
+	// сохраняется завершенным без выполнения операций. Ноль отключает кэш.
+	ResultCacheSize int `env:"RESULT_CACHE_SIZE" env-default:"0"`
+	// ResultCacheWarmup - выражения, которые вычисляются при запуске и сразу попадают в кэш.
+	ResultCacheWarmup []string `env:"RESULT_CACHE_WARMUP" env-separator:","`
+	// DeadLetterEnabled включает копирование операций, не выполненных после всех попыток,
+	// в таблицу dead_letter_operations для разбора и повторного запуска.
+	DeadLetterEnabled bool `env:"DEAD_LETTER_ENABLED" env-default:"true"`
+	// Deterministic отключает имитацию времени выполнения операций: агенты считают
+	// результат сразу, независимо от TIME_*. Предназначен для тестов и демонстраций.
+	Deterministic bool `env:"DETERMINISTIC_MODE" env-default:"false"`
+	// DispatchDryRun включает пробное распределение: процессор только пишет в журнал,
+	// каким агентам были бы назначены ожидающие операции, и не выполняет их.
+	DispatchDryRun bool `env:"DISPATCH_DRY_RUN" env-default:"false"`
+	// StorageShards - количество сегментов in-memory хранилища агентов, у каждого своя блокировка.
+	StorageShards int `env:"AGENT_STORAGE_SHARDS" env-default:"16"`
+	// StorageLockWarnThreshold - ожидание блокировки хранилища агентов, после которого
+	// в журнал пишется предупреждение. Ноль отключает предупреждения.
+	StorageLockWarnThreshold time.Duration `env:"AGENT_STORAGE_LOCK_WARN_THRESHOLD" env-default:"100ms"`
 }
@@ -13,4 +13,5 @@ type Config struct {
 	ConnRetryInterval time.Duration `yaml:"timeout_interval" env:"ORCHESTRATOR_POSTGRES_DB_CONNECT_RETRY_INTERVAL" env-default:"5s"`
 	StatementTimeout  time.Duration `yaml:"statement_timeout" env:"ORCHESTRATOR_POSTGRES_DB_STATEMENT_TIMEOUT" env-default:"60s"`
 	ApplicationName   string        `yaml:"application_name" env:"ORCHESTRATOR_POSTGRES_DB_APPLICATION_NAME" env-default:"orchestrator-service"`
+	ReplicaHosts      []string      `yaml:"replica_hosts" env:"ORCHESTRATOR_POSTGRES_DB_REPLICA_HOSTS" env-separator:","`
 }
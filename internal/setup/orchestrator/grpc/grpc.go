@@ -3,4 +3,19 @@ package grpc
 type Config struct {
 	Host string `yaml:"host" env:"ORCHESTRATOR_GRPC_HOST" env-default:"0.0.0.0"`
 	Port int    `yaml:"port" env:"ORCHESTRATOR_GRPC_PORT" env-default:"50053"`
+	// HealthPort - порт HTTP сервера проверок /healthz и /readyz. Ноль отключает сервер.
+	HealthPort int `yaml:"health_port" env:"ORCHESTRATOR_HEALTH_PORT" env-default:"8082"`
+	// AgentPort - порт gRPC сервиса для агентов, работающих в отдельных процессах.
+	// Ноль отключает сервис.
+	AgentPort int `yaml:"agent_port" env:"ORCHESTRATOR_AGENT_GRPC_PORT" env-default:"0"`
+	// AgentHost - интерфейс, на котором слушает сервис агентов. По умолчанию только
+	// локальный, для агентов на других машинах задается адрес внутренней сети.
+	AgentHost string `yaml:"agent_host" env:"ORCHESTRATOR_AGENT_GRPC_HOST" env-default:"127.0.0.1"`
+	// AgentToken - общий токен, который агенты передают с каждым запросом к сервису агентов.
+	// Обязателен, если сервис включен.
+	AgentToken string `yaml:"agent_token" env:"ORCHESTRATOR_AGENT_TOKEN"`
+	// AdminUserIDs - администраторы, которым доступны административные методы gRPC:
+	// метрики и емкость агентов, сводная статистика, уровень журнала. Тот же список,
+	// что и у шлюза.
+	AdminUserIDs []string `yaml:"admin_user_ids" env:"ADMIN_USER_IDS" env-separator:","`
 }
@@ -0,0 +1,19 @@
+// Package redis содержит конфигурацию подключения оркестратора к Redis.
+package redis
+
+import "time"
+
+// Config содержит конфигурацию общего кэша результатов в Redis.
+type Config struct {
+	// Addr - адрес Redis (host:port). Пустой адрес отключает кэш в Redis.
+	Addr     string `env:"REDIS_ADDR"`
+	Password string `env:"REDIS_PASSWORD"`
+	DB       int    `env:"REDIS_DB" env-default:"0"`
+	// KeyPrefix - префикс ключей кэша, чтобы несколько окружений могли делить один Redis.
+	KeyPrefix string `env:"REDIS_KEY_PREFIX" env-default:"calc:result:"`
+	// TTL - время хранения результата. Ноль хранит результат без ограничения.
+	TTL time.Duration `env:"REDIS_CACHE_TTL" env-default:"24h"`
+	// Timeout ограничивает подключение и каждую команду, чтобы недоступный Redis
+	// не задерживал создание вычислений.
+	Timeout time.Duration `env:"REDIS_TIMEOUT" env-default:"200ms"`
+}
@@ -9,4 +9,82 @@ type Config struct {
 	Port         int           `env:"HTTP_PORT" env-default:"8080"`
 	ReadTimeout  time.Duration `env:"HTTP_READ_TIMEOUT" env-default:"5s"`
 	WriteTimeout time.Duration `env:"HTTP_WRITE_TIMEOUT" env-default:"10s"`
+	// MetricsPort - порт внутреннего HTTP сервера с /metrics и проверками /healthz и /readyz.
+	// На основном порту /metrics не отдается. Ноль отключает сервер.
+	MetricsPort int `env:"HTTP_METRICS_PORT" env-default:"9090"`
+	// DefaultSource - канал вычисления, если клиент не передал заголовок X-Client-Source.
+	DefaultSource string `env:"HTTP_DEFAULT_SOURCE" env-default:"web"`
+	// ClientVersionHeader - заголовок, из которого берется версия клиента для сохранения
+	// вместе с вычислением, например X-Client-Version. Пустое значение отключает сохранение.
+	ClientVersionHeader string `env:"HTTP_CLIENT_VERSION_HEADER"`
+	// MaxStreamsPerUser - лимит одновременных потоковых (SSE) соединений одного пользователя.
+	// Ноль отключает лимит.
+	MaxStreamsPerUser int `env:"HTTP_MAX_STREAMS_PER_USER" env-default:"3"`
+	// MaxStreams - общий лимит одновременных потоковых соединений. Ноль отключает лимит.
+	MaxStreams int `env:"HTTP_MAX_STREAMS" env-default:"100"`
+	// MinSubmitInterval - минимальный интервал между отправками вычислений одним пользователем.
+	// Ноль отключает ограничение.
+	MinSubmitInterval time.Duration `env:"HTTP_MIN_SUBMIT_INTERVAL" env-default:"0s"`
+	// MaxRequestBodyBytes - максимальный размер тела запроса в байтах; запросы с большим телом
+	// отклоняются с кодом 413. Ноль отключает лимит.
+	MaxRequestBodyBytes int64 `env:"HTTP_MAX_REQUEST_BODY_BYTES" env-default:"1048576"`
+	// AdminUserIDs - идентификаторы пользователей с ролью администратора,
+	// которым доступны маршруты /api/v1/admin.
+	AdminUserIDs []string `env:"ADMIN_USER_IDS" env-separator:","`
+	// TrustedProxies - адреса или подсети (CIDR) прокси, от которых принимаются заголовки
+	// X-Forwarded-For и X-Real-IP с адресом клиента. Пустой список - адресом клиента
+	// считается адрес соединения.
+	TrustedProxies []string `env:"HTTP_TRUSTED_PROXIES" env-separator:","`
+	// ServerTiming добавляет ко всем ответам заголовок Server-Timing с длительностями
+	// проверки токена, вызовов gRPC и сериализации ответа.
+	ServerTiming bool `env:"HTTP_SERVER_TIMING" env-default:"false"`
+	// ServerTimingHeader - заголовок запроса, которым клиент включает Server-Timing для одного
+	// запроса, например X-Debug-Timing: true. Пустое значение отключает включение по запросу.
+	ServerTimingHeader string `env:"HTTP_SERVER_TIMING_HEADER"`
+	// CORS - правила запросов к API со страниц других источников.
+	CORS CORSConfig
+	// GRPCClient - параметры соединений с сервисами авторизации и оркестрации.
+	GRPCClient GRPCClientConfig
+}
+
+// GRPCClientConfig содержит параметры соединений шлюза с gRPC сервисами.
+type GRPCClientConfig struct {
+	// KeepaliveTime - интервал проверочных пингов соединения, по которым обнаруживается его обрыв.
+	// gRPC не пингует чаще раза в 10 секунд. Ноль отключает пинги.
+	KeepaliveTime time.Duration `env:"GRPC_CLIENT_KEEPALIVE_TIME" env-default:"30s"`
+	// KeepaliveTimeout - время ожидания ответа на пинг, после которого соединение закрывается.
+	KeepaliveTimeout time.Duration `env:"GRPC_CLIENT_KEEPALIVE_TIMEOUT" env-default:"10s"`
+	// BackoffBaseDelay и BackoffMaxDelay - начальная и максимальная задержки между попытками
+	// переподключения.
+	BackoffBaseDelay time.Duration `env:"GRPC_CLIENT_BACKOFF_BASE_DELAY" env-default:"1s"`
+	BackoffMaxDelay  time.Duration `env:"GRPC_CLIENT_BACKOFF_MAX_DELAY" env-default:"30s"`
+	// MinConnectTimeout - минимальное время на одну попытку подключения.
+	MinConnectTimeout time.Duration `env:"GRPC_CLIENT_MIN_CONNECT_TIMEOUT" env-default:"5s"`
+	// ReconnectWait - сколько запрос ждет восстановления оборванного соединения, прежде чем
+	// вернуть ошибку недоступности сервиса. Ноль отключает ожидание.
+	ReconnectWait time.Duration `env:"GRPC_CLIENT_RECONNECT_WAIT" env-default:"1s"`
+	// RetryMaxAttempts - число попыток идемпотентного вызова, включая первую; 1 отключает повторы.
+	// Повторяются только читающие вызовы и вызовы с ключом идемпотентности.
+	RetryMaxAttempts int `env:"GRPC_CLIENT_RETRY_MAX_ATTEMPTS" env-default:"3"`
+	// RetryBaseDelay и RetryMaxDelay - задержка перед первым повтором и ее верхняя граница;
+	// задержка удваивается с каждым повтором.
+	RetryBaseDelay time.Duration `env:"GRPC_CLIENT_RETRY_BASE_DELAY" env-default:"100ms"`
+	RetryMaxDelay  time.Duration `env:"GRPC_CLIENT_RETRY_MAX_DELAY" env-default:"1s"`
+	// RetryCodes - коды gRPC, при которых вызов повторяется, например UNAVAILABLE.
+	RetryCodes []string `env:"GRPC_CLIENT_RETRY_CODES" env-separator:"," env-default:"UNAVAILABLE"`
+}
+
+// CORSConfig содержит настройки CORS. Пока список источников пуст, заголовки CORS
+// не выставляются и браузер отклоняет запросы с чужих страниц.
+type CORSConfig struct {
+	// AllowedOrigins - разрешенные источники, например https://calc.example.com; "*" разрешает любой.
+	AllowedOrigins []string `env:"HTTP_CORS_ALLOWED_ORIGINS" env-separator:","`
+	// AllowedMethods - методы, разрешенные в запросах с чужих страниц.
+	AllowedMethods []string `env:"HTTP_CORS_ALLOWED_METHODS" env-separator:"," env-default:"GET,POST,PUT,DELETE,OPTIONS"`
+	// AllowedHeaders - заголовки, которые страница может передать в запросе.
+	AllowedHeaders []string `env:"HTTP_CORS_ALLOWED_HEADERS" env-separator:"," env-default:"Accept,Authorization,Content-Type,X-CSRF-Token,X-Request-ID,X-Client-Source,Idempotency-Key,X-Debug-Timing"`
+	// AllowCredentials разрешает запросы с cookie и клиентскими сертификатами.
+	AllowCredentials bool `env:"HTTP_CORS_ALLOW_CREDENTIALS" env-default:"false"`
+	// MaxAge - время, на которое браузер кэширует ответ на предварительный запрос.
+	MaxAge time.Duration `env:"HTTP_CORS_MAX_AGE" env-default:"5m"`
 }
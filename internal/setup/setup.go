@@ -2,20 +2,29 @@ package setup
 
 import (
 	"fmt"
+	"net"
+	"strconv"
 	"time"
 
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/domain/models/orchestrator"
+	authcleanup "github.com/flexer2006/y.lms-final-task-calc-go/internal/setup/auth/cleanup"
 	authpgx "github.com/flexer2006/y.lms-final-task-calc-go/internal/setup/auth/db/pgxx"
 	authpg "github.com/flexer2006/y.lms-final-task-calc-go/internal/setup/auth/db/postgres"
 	authgrpc "github.com/flexer2006/y.lms-final-task-calc-go/internal/setup/auth/grpc"
+	authlogin "github.com/flexer2006/y.lms-final-task-calc-go/internal/setup/auth/login"
+	authpassword "github.com/flexer2006/y.lms-final-task-calc-go/internal/setup/auth/password"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/setup/jwt"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/setup/logger"
 	orchagent "github.com/flexer2006/y.lms-final-task-calc-go/internal/setup/orchestrator/agent"
 	orchpgx "github.com/flexer2006/y.lms-final-task-calc-go/internal/setup/orchestrator/db/pgxx"
 	orchpg "github.com/flexer2006/y.lms-final-task-calc-go/internal/setup/orchestrator/db/postgres"
 	orchgrpc "github.com/flexer2006/y.lms-final-task-calc-go/internal/setup/orchestrator/grpc"
+	orchredis "github.com/flexer2006/y.lms-final-task-calc-go/internal/setup/orchestrator/redis"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/setup/server"
 	"github.com/flexer2006/y.lms-final-task-calc-go/internal/setup/shutdown"
+	"github.com/flexer2006/y.lms-final-task-calc-go/internal/setup/tracing"
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/database"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/retry"
 )
 
 // BaseConfig содержит общие поля для всех конфигураций.
@@ -23,6 +32,7 @@ type BaseConfig struct {
 	Logger           logger.Config
 	GracefulShutdown shutdown.Config
 	JWT              jwt.Config
+	Tracing          tracing.Config
 }
 
 // AuthConfig содержит конфигурацию для сервиса аутентификации.
@@ -30,9 +40,13 @@ type AuthConfig struct {
 	Logger           logger.Config
 	GracefulShutdown shutdown.Config
 	JWT              jwt.Config
+	Tracing          tracing.Config
 	AuthGrpc         authgrpc.Config
 	AuthDbPostgres   authpg.Config
 	AuthDbPgx        authpgx.Config
+	AuthPassword     authpassword.Config
+	AuthLogin        authlogin.Config
+	AuthCleanup      authcleanup.Config
 }
 
 // OrchestratorConfig содержит конфигурацию для сервиса оркестрации.
@@ -40,10 +54,12 @@ type OrchestratorConfig struct {
 	Logger           logger.Config
 	GracefulShutdown shutdown.Config
 	JWT              jwt.Config
+	Tracing          tracing.Config
 	OrchGrpc         orchgrpc.Config
 	OrchAgent        orchagent.Config
 	OrchDbPostgres   orchpg.Config
 	OrchDbPgx        orchpgx.Config
+	OrchRedis        orchredis.Config
 }
 
 // ServerConfig содержит конфигурацию для API сервера.
@@ -51,17 +67,84 @@ type ServerConfig struct {
 	Logger           logger.Config
 	GracefulShutdown shutdown.Config
 	JWT              jwt.Config
+	Tracing          tracing.Config
 	Server           server.Config
 	AuthGrpc         authgrpc.Config
 	OrchGrpc         orchgrpc.Config
 	OrchAgent        orchagent.Config
 }
 
+// GetResultRounding возвращает настройки округления результата вычисления.
+func (c *OrchestratorConfig) GetResultRounding() orchestrator.Rounding {
+	return orchestrator.Rounding{
+		Precision:          c.OrchAgent.ResultPrecision,
+		Mode:               orchestrator.RoundingMode(c.OrchAgent.ResultRoundingMode),
+		OperationPrecision: c.OrchAgent.ResultPrecisionByOperation,
+	}
+}
+
+// GetDisplayRounding возвращает округление результата вычисления для отображения. Округленный
+// результат сохраняется только в режиме decimal: в режиме float результат уже округлен
+// по RESULT_PRECISION, поэтому вне decimal сохранение выключено.
+func (c *OrchestratorConfig) GetDisplayRounding() orchestrator.Rounding {
+	if orchestrator.ArithmeticBackend(c.OrchAgent.ArithmeticBackend) != orchestrator.ArithmeticDecimal {
+		return orchestrator.NoRounding
+	}
+	return orchestrator.Rounding{
+		Precision: c.OrchAgent.ResultDisplayPrecision,
+		Mode:      orchestrator.RoundingMode(c.OrchAgent.ResultRoundingMode),
+	}
+}
+
+// GetArithmetic возвращает представление чисел, в котором агенты выполняют операции.
+func (c *OrchestratorConfig) GetArithmetic() orchestrator.Arithmetic {
+	return orchestrator.Arithmetic{
+		Backend:           orchestrator.ArithmeticBackend(c.OrchAgent.ArithmeticBackend),
+		DivisionPrecision: c.OrchAgent.DecimalDivisionPrecision,
+		PreserveScale:     c.OrchAgent.DecimalPreserveScale,
+		DivisionByZero:    c.getDivisionByZeroPolicy(),
+	}
+}
+
+// getDivisionByZeroPolicy возвращает политику деления на ноль с учетом устаревшего
+// LENIENT_DIVISION. Неизвестная политика заменяется на DivisionByZeroError.
+func (c *OrchestratorConfig) getDivisionByZeroPolicy() orchestrator.DivisionByZeroPolicy {
+	// Десятичное представление не выражает бесконечность и NaN
+	if c.OrchAgent.ArithmeticBackend == string(orchestrator.ArithmeticDecimal) {
+		return orchestrator.DivisionByZeroError
+	}
+
+	policy := orchestrator.DivisionByZeroPolicy(c.OrchAgent.DivisionByZeroPolicy)
+	if !policy.IsValid() {
+		policy = orchestrator.DivisionByZeroError
+	}
+	if policy == orchestrator.DivisionByZeroError && c.OrchAgent.LenientDivision {
+		return orchestrator.DivisionByZeroInfinity
+	}
+	return policy
+}
+
+// GetRetryPolicy возвращает политику повторного назначения операций агентам.
+func (c *OrchestratorConfig) GetRetryPolicy() retry.Policy {
+	return retry.Policy{
+		MaxAttempts: c.OrchAgent.RetryMaxAttempts,
+		BaseDelay:   c.OrchAgent.RetryBaseDelay,
+		Multiplier:  c.OrchAgent.RetryMultiplier,
+		MaxDelay:    c.OrchAgent.RetryMaxDelay,
+		Jitter:      c.OrchAgent.RetryJitter,
+	}
+}
+
 // GetLoggerConfig возвращает конфигурацию журнала.
 func (c *BaseConfig) GetLoggerConfig() logger.Config {
 	return c.Logger
 }
 
+// GetTracingConfig возвращает конфигурацию трассировки.
+func (c *BaseConfig) GetTracingConfig() tracing.Config {
+	return c.Tracing
+}
+
 // GetJWTConfig возвращает конфигурацию JWT.
 func (c *BaseConfig) GetJWTConfig() jwt.Config {
 	return c.JWT
@@ -92,6 +175,11 @@ func (c *AuthConfig) GetLoggerConfig() logger.Config {
 	return c.Logger
 }
 
+// GetTracingConfig возвращает конфигурацию трассировки.
+func (c *AuthConfig) GetTracingConfig() tracing.Config {
+	return c.Tracing
+}
+
 // GetJWTConfig возвращает конфигурацию JWT.
 func (c *AuthConfig) GetJWTConfig() jwt.Config {
 	return c.JWT
@@ -112,6 +200,21 @@ func (c *AuthConfig) GetAuthPgxConfig() authpgx.Config {
 	return c.AuthDbPgx
 }
 
+// GetAuthPasswordConfig возвращает конфигурацию хеширования паролей.
+func (c *AuthConfig) GetAuthPasswordConfig() authpassword.Config {
+	return c.AuthPassword
+}
+
+// GetAuthLoginConfig возвращает конфигурацию ограничения неудачных попыток входа.
+func (c *AuthConfig) GetAuthLoginConfig() authlogin.Config {
+	return c.AuthLogin
+}
+
+// GetAuthCleanupConfig возвращает конфигурацию периодического удаления истекших токенов.
+func (c *AuthConfig) GetAuthCleanupConfig() authcleanup.Config {
+	return c.AuthCleanup
+}
+
 // GetShutdownConfig возвращает конфигурацию graceful shutdown.
 func (c *AuthConfig) GetShutdownConfig() shutdown.Config {
 	return c.GracefulShutdown
@@ -156,6 +259,11 @@ func (c *OrchestratorConfig) GetLoggerConfig() logger.Config {
 	return c.Logger
 }
 
+// GetTracingConfig возвращает конфигурацию трассировки.
+func (c *OrchestratorConfig) GetTracingConfig() tracing.Config {
+	return c.Tracing
+}
+
 // GetJWTConfig возвращает конфигурацию JWT.
 func (c *OrchestratorConfig) GetJWTConfig() jwt.Config {
 	return c.JWT
@@ -181,6 +289,11 @@ func (c *OrchestratorConfig) GetOrchestratorPgxConfig() orchpgx.Config {
 	return c.OrchDbPgx
 }
 
+// GetOrchestratorRedisConfig возвращает конфигурацию Redis для сервиса оркестрации.
+func (c *OrchestratorConfig) GetOrchestratorRedisConfig() orchredis.Config {
+	return c.OrchRedis
+}
+
 // GetShutdownConfig возвращает конфигурацию graceful shutdown.
 func (c *OrchestratorConfig) GetShutdownConfig() shutdown.Config {
 	return c.GracefulShutdown
@@ -191,6 +304,11 @@ func (c *OrchestratorConfig) GetOrchestratorGRPCAddress() string {
 	return fmt.Sprintf("%s:%d", c.OrchGrpc.Host, c.OrchGrpc.Port)
 }
 
+// GetOrchestratorAgentGRPCAddress возвращает адрес gRPC сервиса агентов оркестратора.
+func (c *OrchestratorConfig) GetOrchestratorAgentGRPCAddress() string {
+	return fmt.Sprintf("%s:%d", c.OrchGrpc.AgentHost, c.OrchGrpc.AgentPort)
+}
+
 // GetConnectionURL возвращает URL-строку подключения для миграций.
 func (c *OrchestratorConfig) GetConnectionURL() string {
 	pg := c.OrchDbPostgres
@@ -232,6 +350,33 @@ func (c *OrchestratorConfig) GetAgentOperationTimes() map[string]time.Duration {
 		"subtraction":    c.OrchAgent.TimeSubtraction,
 		"multiplication": c.OrchAgent.TimeMultiplications,
 		"division":       c.OrchAgent.TimeDivisions,
+		"modulo":         c.OrchAgent.TimeModulo,
+		"factorial":      c.OrchAgent.TimeFactorial,
+	}
+}
+
+// GetAgentOperationTimeouts возвращает сроки операций с учетом переопределений по типам.
+func (c *OrchestratorConfig) GetAgentOperationTimeouts() map[string]time.Duration {
+	operationTimes := c.GetAgentOperationTimes()
+	timeouts := make(map[string]time.Duration, len(operationTimes))
+	for operation := range operationTimes {
+		timeouts[operation] = c.OrchAgent.OperationTimeout
+		if timeout, ok := c.OrchAgent.OperationTimeoutByOperation[operation]; ok && timeout > 0 {
+			timeouts[operation] = timeout
+		}
+	}
+	return timeouts
+}
+
+// GetAgentOperationCosts возвращает стоимость операций в единицах емкости агента.
+func (c *OrchestratorConfig) GetAgentOperationCosts() map[string]int {
+	return map[string]int{
+		"addition":       c.OrchAgent.CostAddition,
+		"subtraction":    c.OrchAgent.CostSubtraction,
+		"multiplication": c.OrchAgent.CostMultiplication,
+		"division":       c.OrchAgent.CostDivision,
+		"modulo":         c.OrchAgent.CostModulo,
+		"factorial":      c.OrchAgent.CostFactorial,
 	}
 }
 
@@ -240,6 +385,11 @@ func (c *ServerConfig) GetLoggerConfig() logger.Config {
 	return c.Logger
 }
 
+// GetTracingConfig возвращает конфигурацию трассировки.
+func (c *ServerConfig) GetTracingConfig() tracing.Config {
+	return c.Tracing
+}
+
 // GetServerConfig возвращает конфигурацию HTTP сервера.
 func (c *ServerConfig) GetServerConfig() server.Config {
 	return c.Server
@@ -293,37 +443,65 @@ func (c *OrchestratorConfig) GetMaxOperations() int {
 	return c.OrchAgent.MaxOperations
 }
 
+// GetMaxExpressionLength возвращает максимальную длину выражения в байтах.
+func (c *OrchestratorConfig) GetMaxExpressionLength() int {
+	return c.OrchAgent.MaxExpressionLength
+}
+
 // ToPostgresConfig converts AuthConfig's postgres config to database.PostgresConfig.
 func (c *AuthConfig) ToPostgresConfig() database.PostgresConfig {
 	return database.PostgresConfig{
-		Host:            c.AuthDbPostgres.Host,
-		Port:            c.AuthDbPostgres.Port,
-		User:            c.AuthDbPostgres.User,
-		Password:        c.AuthDbPostgres.Password,
-		Database:        c.AuthDbPostgres.Database,
-		SSLMode:         c.AuthDbPostgres.SSLMode,
-		ApplicationName: c.AuthDbPostgres.ApplicationName,
-		ConnTimeout:     c.AuthDbPostgres.ConnRetryInterval,
-		MinConns:        c.AuthDbPgx.PoolMinConns,
-		MaxConns:        c.AuthDbPgx.PoolMaxConns,
+		Host:             c.AuthDbPostgres.Host,
+		Port:             c.AuthDbPostgres.Port,
+		User:             c.AuthDbPostgres.User,
+		Password:         c.AuthDbPostgres.Password,
+		Database:         c.AuthDbPostgres.Database,
+		SSLMode:          c.AuthDbPostgres.SSLMode,
+		ApplicationName:  c.AuthDbPostgres.ApplicationName,
+		StatementTimeout: c.AuthDbPostgres.StatementTimeout,
+		ConnTimeout:      c.AuthDbPostgres.ConnRetryInterval,
+		MinConns:         c.AuthDbPgx.PoolMinConns,
+		MaxConns:         c.AuthDbPgx.PoolMaxConns,
 	}
 }
 
 // ToPostgresConfig converts OrchestratorConfig's postgres config to database.PostgresConfig.
 func (c *OrchestratorConfig) ToPostgresConfig() database.PostgresConfig {
 	return database.PostgresConfig{
-		Host:            c.OrchDbPostgres.Host,
-		Port:            c.OrchDbPostgres.Port,
-		User:            c.OrchDbPostgres.User,
-		Password:        c.OrchDbPostgres.Password,
-		Database:        c.OrchDbPostgres.Database,
-		SSLMode:         c.OrchDbPostgres.SSLMode,
-		ApplicationName: c.OrchDbPostgres.ApplicationName,
-		ConnTimeout:     c.OrchDbPostgres.ConnRetryInterval,
-		MinConns:        c.OrchDbPgx.PoolMinConns,
-		MaxConns:        c.OrchDbPgx.PoolMaxConns,
-		MaxConnLifetime: c.OrchDbPgx.MaxConnLifetime,
-		MaxConnIdleTime: c.OrchDbPgx.MaxConnIdleTime,
-		HealthPeriod:    30 * time.Second,
+		Host:             c.OrchDbPostgres.Host,
+		Port:             c.OrchDbPostgres.Port,
+		User:             c.OrchDbPostgres.User,
+		Password:         c.OrchDbPostgres.Password,
+		Database:         c.OrchDbPostgres.Database,
+		SSLMode:          c.OrchDbPostgres.SSLMode,
+		ApplicationName:  c.OrchDbPostgres.ApplicationName,
+		StatementTimeout: c.OrchDbPostgres.StatementTimeout,
+		ConnTimeout:      c.OrchDbPostgres.ConnRetryInterval,
+		MinConns:         c.OrchDbPgx.PoolMinConns,
+		MaxConns:         c.OrchDbPgx.PoolMaxConns,
+		MaxConnLifetime:  c.OrchDbPgx.MaxConnLifetime,
+		MaxConnIdleTime:  c.OrchDbPgx.MaxConnIdleTime,
+		HealthPeriod:     30 * time.Second,
+	}
+}
+
+// ToReplicaPostgresConfigs возвращает database.PostgresConfig для каждой реплики чтения из списка host[:port].
+// Учетные данные и настройки пула берутся от основной базы, без порта используется ее порт.
+func (c *OrchestratorConfig) ToReplicaPostgresConfigs() ([]database.PostgresConfig, error) {
+	replicas := make([]database.PostgresConfig, 0, len(c.OrchDbPostgres.ReplicaHosts))
+	for _, address := range c.OrchDbPostgres.ReplicaHosts {
+		replica := c.ToPostgresConfig()
+		replica.Host = address
+
+		if host, port, err := net.SplitHostPort(address); err == nil {
+			portNum, err := strconv.Atoi(port)
+			if err != nil {
+				return nil, fmt.Errorf("invalid read replica port in %q: %w", address, err)
+			}
+			replica.Host, replica.Port = host, portNum
+		}
+
+		replicas = append(replicas, replica)
 	}
+	return replicas, nil
 }
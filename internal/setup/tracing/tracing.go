@@ -0,0 +1,12 @@
+// Package tracing содержит конфигурацию для трассировки OpenTelemetry.
+package tracing
+
+// Config содержит конфигурацию для трассировки OpenTelemetry.
+type Config struct {
+	// Endpoint - адрес OTLP/gRPC приемника спанов (host:port). Пустой адрес отключает трассировку.
+	Endpoint string `env:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+	// Insecure отключает TLS при подключении к приемнику.
+	Insecure bool `env:"OTEL_EXPORTER_OTLP_INSECURE" env-default:"true"`
+	// SampleRatio - доля записываемых трасс от 0 до 1.
+	SampleRatio float64 `env:"OTEL_TRACES_SAMPLE_RATIO" env-default:"1"`
+}
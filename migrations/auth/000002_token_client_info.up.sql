@@ -0,0 +1,3 @@
+-- Данные клиента, с которого был выполнен вход (для списка активных сессий).
+ALTER TABLE tokens ADD COLUMN user_agent TEXT NOT NULL DEFAULT '';
+ALTER TABLE tokens ADD COLUMN ip VARCHAR(45) NOT NULL DEFAULT '';
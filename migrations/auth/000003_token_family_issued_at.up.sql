@@ -0,0 +1,5 @@
+-- Время входа, с которого началась цепочка обновлений токена (семейство токенов).
+ALTER TABLE tokens ADD COLUMN family_issued_at TIMESTAMP WITH TIME ZONE;
+UPDATE tokens SET family_issued_at = COALESCE(created_at, CURRENT_TIMESTAMP);
+ALTER TABLE tokens ALTER COLUMN family_issued_at SET DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE tokens ALTER COLUMN family_issued_at SET NOT NULL;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_calculations_source;
+ALTER TABLE calculations DROP COLUMN IF EXISTS source;
\ No newline at end of file
@@ -0,0 +1,5 @@
+-- Канал, через который было отправлено вычисление (web, cli, api-token, batch).
+ALTER TABLE calculations ADD COLUMN source VARCHAR(32) NOT NULL DEFAULT 'web';
+
+-- Индекс для аналитики и лимитов по каналам.
+CREATE INDEX idx_calculations_source ON calculations(source);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS dead_letter_operations;
\ No newline at end of file
@@ -0,0 +1,20 @@
+-- Операции, не выполненные после всех попыток, с контекстом для разбора и повторного запуска.
+-- Внешних ключей нет: записи должны переживать удаление вычислений.
+CREATE TABLE dead_letter_operations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    operation_id UUID NOT NULL,
+    calculation_id UUID NOT NULL,
+    expression TEXT NOT NULL DEFAULT '',
+    operation_type INT NOT NULL,
+    operand1 TEXT NOT NULL,
+    operand2 TEXT NOT NULL,
+    error_history TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    replayed_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Индекс для выборки записей, ожидающих повторного запуска.
+CREATE INDEX idx_dead_letter_operations_pending ON dead_letter_operations(created_at) WHERE replayed_at IS NULL;
+
+-- Индекс для поиска записей по операции.
+CREATE INDEX idx_dead_letter_operations_operation_id ON dead_letter_operations(operation_id);
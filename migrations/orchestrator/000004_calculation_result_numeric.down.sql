@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_calculations_user_result_numeric;
+
+ALTER TABLE calculations DROP COLUMN IF EXISTS result_numeric;
@@ -0,0 +1,14 @@
+-- Числовое значение результата завершенного вычисления для агрегатов по результатам.
+-- Столбец вычисляется из result, поэтому не требует изменений при записи; результаты,
+-- которые не являются конечными числами, в агрегаты не попадают.
+ALTER TABLE calculations ADD COLUMN result_numeric NUMERIC
+    GENERATED ALWAYS AS (
+        CASE
+            WHEN status = 'COMPLETED' AND result ~ '^[-+]?[0-9]+(\.[0-9]+)?([eE][-+]?[0-9]+)?$'
+                THEN result::NUMERIC
+        END
+    ) STORED;
+
+-- Индекс для статистики по результатам пользователя.
+CREATE INDEX idx_calculations_user_result_numeric ON calculations(user_id, result_numeric)
+    WHERE result_numeric IS NOT NULL;
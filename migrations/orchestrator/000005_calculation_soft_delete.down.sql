@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_calculations_user_id_active;
+
+ALTER TABLE calculations DROP COLUMN IF EXISTS deleted_at;
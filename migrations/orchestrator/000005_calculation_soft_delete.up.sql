@@ -0,0 +1,7 @@
+-- Время удаления вычисления пользователем. Удаленные вычисления остаются в таблице
+-- для истории и не попадают в обычные выборки.
+ALTER TABLE calculations ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+
+-- Индекс для выборки действующих вычислений пользователя.
+CREATE INDEX idx_calculations_user_id_active ON calculations(user_id, created_at DESC)
+    WHERE deleted_at IS NULL;
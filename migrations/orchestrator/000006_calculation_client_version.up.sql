@@ -0,0 +1,2 @@
+-- Версия клиента, отправившего выражение, из заголовка запроса. Нужна поддержке для отладки.
+ALTER TABLE calculations ADD COLUMN client_version VARCHAR(64) NOT NULL DEFAULT '';
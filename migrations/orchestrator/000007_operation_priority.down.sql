@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_operations_pending_priority;
+
+ALTER TABLE operations DROP COLUMN IF EXISTS priority;
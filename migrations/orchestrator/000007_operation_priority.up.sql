@@ -0,0 +1,6 @@
+-- Приоритет выполнения операции: операции с большим значением назначаются агентам раньше.
+ALTER TABLE operations ADD COLUMN priority INT NOT NULL DEFAULT 0;
+
+-- Индекс для выборки ожидающих операций в порядке приоритета.
+CREATE INDEX idx_operations_pending_priority ON operations(priority DESC, id)
+    WHERE status = 'PENDING';
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_calculations_compactable;
+
+ALTER TABLE calculations DROP COLUMN IF EXISTS compacted_at;
+
+ALTER TABLE calculations DROP COLUMN IF EXISTS operations_archive;
@@ -0,0 +1,9 @@
+-- Операции завершенного вычисления, сжатые в JSON после удаления их строк из operations.
+ALTER TABLE calculations ADD COLUMN operations_archive JSONB;
+
+-- Время сжатия операций вычисления.
+ALTER TABLE calculations ADD COLUMN compacted_at TIMESTAMP WITH TIME ZONE;
+
+-- Индекс для выборки завершенных вычислений, операции которых еще не сжаты.
+CREATE INDEX idx_calculations_compactable ON calculations(updated_at)
+    WHERE status = 'COMPLETED' AND compacted_at IS NULL AND deleted_at IS NULL;
@@ -0,0 +1,3 @@
+-- Результат вычисления, округленный для отображения. Заполняется в десятичном режиме,
+-- когда задана точность отображения; в result остается точное значение.
+ALTER TABLE calculations ADD COLUMN result_display TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+ALTER TABLE calculations DROP CONSTRAINT IF EXISTS calculations_user_idempotency_key_unique;
+ALTER TABLE calculations DROP COLUMN IF EXISTS idempotency_key;
@@ -0,0 +1,6 @@
+-- Ключ идемпотентности отправки вычисления. Повтор запроса с тем же ключом возвращает
+-- уже созданное вычисление; вычисления без ключа хранят NULL и ограничением не связаны.
+ALTER TABLE calculations ADD COLUMN idempotency_key VARCHAR(255);
+
+ALTER TABLE calculations ADD CONSTRAINT calculations_user_idempotency_key_unique
+    UNIQUE (user_id, idempotency_key);
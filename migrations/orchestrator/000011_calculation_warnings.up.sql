@@ -0,0 +1,3 @@
+-- Предупреждения к успешному результату вычисления, например о делении на ноль
+-- в нестрогом режиме.
+ALTER TABLE calculations ADD COLUMN warnings TEXT[] NOT NULL DEFAULT '{}';
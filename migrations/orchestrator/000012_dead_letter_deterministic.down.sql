@@ -0,0 +1 @@
+ALTER TABLE dead_letter_operations DROP COLUMN IF EXISTS deterministic;
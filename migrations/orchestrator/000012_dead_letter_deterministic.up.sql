@@ -0,0 +1,3 @@
+-- Признак детерминированной ошибки (деление на ноль, неверный операнд): повторный
+-- запуск такой операции даст тот же результат, поэтому она не возвращается в очередь.
+ALTER TABLE dead_letter_operations ADD COLUMN deterministic BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,517 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.30.2
+// source: proto/v1/agent/agent.proto
+
+package agentv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Запрос на регистрацию агента.
+type RegisterRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Идентификатор агента. Если не задан, оркестратор назначит его сам.
+	AgentId string `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	// Емкость агента в единицах стоимости операций.
+	Capacity      int32 `protobuf:"varint,2,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterRequest) Reset() {
+	*x = RegisterRequest{}
+	mi := &file_proto_v1_agent_agent_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterRequest) ProtoMessage() {}
+
+func (x *RegisterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_agent_agent_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterRequest.ProtoReflect.Descriptor instead.
+func (*RegisterRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v1_agent_agent_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RegisterRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *RegisterRequest) GetCapacity() int32 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+// Ответ на регистрацию агента.
+type RegisterResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Идентификатор, под которым агент зарегистрирован.
+	AgentId string `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	// Емкость агента.
+	Capacity      int32 `protobuf:"varint,2,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterResponse) Reset() {
+	*x = RegisterResponse{}
+	mi := &file_proto_v1_agent_agent_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterResponse) ProtoMessage() {}
+
+func (x *RegisterResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_agent_agent_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterResponse.ProtoReflect.Descriptor instead.
+func (*RegisterResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v1_agent_agent_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RegisterResponse) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *RegisterResponse) GetCapacity() int32 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+// Запрос на получение операций.
+type FetchOperationsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Идентификатор агента.
+	AgentId string `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	// Наибольшее количество операций в ответе, 0 - все назначенные.
+	Limit         int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FetchOperationsRequest) Reset() {
+	*x = FetchOperationsRequest{}
+	mi := &file_proto_v1_agent_agent_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FetchOperationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchOperationsRequest) ProtoMessage() {}
+
+func (x *FetchOperationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_agent_agent_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchOperationsRequest.ProtoReflect.Descriptor instead.
+func (*FetchOperationsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v1_agent_agent_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *FetchOperationsRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *FetchOperationsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// Операция, выданная агенту.
+type Task struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Идентификатор операции.
+	OperationId string `protobuf:"bytes,1,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
+	// Тип операции, значение orchestrator.v1.OperationType.
+	OperationType int32 `protobuf:"varint,2,opt,name=operation_type,json=operationType,proto3" json:"operation_type,omitempty"`
+	// Операнды. Ссылки на результаты других операций уже заменены значениями.
+	Operand1 string `protobuf:"bytes,3,opt,name=operand1,proto3" json:"operand1,omitempty"`
+	Operand2 string `protobuf:"bytes,4,opt,name=operand2,proto3" json:"operand2,omitempty"`
+	// Время, в течение которого агент имитирует выполнение операции, в миллисекундах.
+	OperationTimeMs int64 `protobuf:"varint,5,opt,name=operation_time_ms,json=operationTimeMs,proto3" json:"operation_time_ms,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Task) Reset() {
+	*x = Task{}
+	mi := &file_proto_v1_agent_agent_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Task) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Task) ProtoMessage() {}
+
+func (x *Task) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_agent_agent_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Task.ProtoReflect.Descriptor instead.
+func (*Task) Descriptor() ([]byte, []int) {
+	return file_proto_v1_agent_agent_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Task) GetOperationId() string {
+	if x != nil {
+		return x.OperationId
+	}
+	return ""
+}
+
+func (x *Task) GetOperationType() int32 {
+	if x != nil {
+		return x.OperationType
+	}
+	return 0
+}
+
+func (x *Task) GetOperand1() string {
+	if x != nil {
+		return x.Operand1
+	}
+	return ""
+}
+
+func (x *Task) GetOperand2() string {
+	if x != nil {
+		return x.Operand2
+	}
+	return ""
+}
+
+func (x *Task) GetOperationTimeMs() int64 {
+	if x != nil {
+		return x.OperationTimeMs
+	}
+	return 0
+}
+
+// Ответ с операциями агента.
+type FetchOperationsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tasks         []*Task                `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FetchOperationsResponse) Reset() {
+	*x = FetchOperationsResponse{}
+	mi := &file_proto_v1_agent_agent_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FetchOperationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchOperationsResponse) ProtoMessage() {}
+
+func (x *FetchOperationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_agent_agent_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchOperationsResponse.ProtoReflect.Descriptor instead.
+func (*FetchOperationsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v1_agent_agent_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *FetchOperationsResponse) GetTasks() []*Task {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+// Отчет о выполнении операции.
+type ReportResultRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Идентификатор агента.
+	AgentId string `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	// Идентификатор операции.
+	OperationId string `protobuf:"bytes,2,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
+	// Результат операции.
+	Result string `protobuf:"bytes,3,opt,name=result,proto3" json:"result,omitempty"`
+	// Ошибка выполнения. Непустое значение означает, что операция завершилась ошибкой.
+	Error         string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportResultRequest) Reset() {
+	*x = ReportResultRequest{}
+	mi := &file_proto_v1_agent_agent_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportResultRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportResultRequest) ProtoMessage() {}
+
+func (x *ReportResultRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_agent_agent_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportResultRequest.ProtoReflect.Descriptor instead.
+func (*ReportResultRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v1_agent_agent_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ReportResultRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *ReportResultRequest) GetOperationId() string {
+	if x != nil {
+		return x.OperationId
+	}
+	return ""
+}
+
+func (x *ReportResultRequest) GetResult() string {
+	if x != nil {
+		return x.Result
+	}
+	return ""
+}
+
+func (x *ReportResultRequest) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// Ответ на отчет о выполнении операции.
+type ReportResultResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportResultResponse) Reset() {
+	*x = ReportResultResponse{}
+	mi := &file_proto_v1_agent_agent_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportResultResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportResultResponse) ProtoMessage() {}
+
+func (x *ReportResultResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_agent_agent_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportResultResponse.ProtoReflect.Descriptor instead.
+func (*ReportResultResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v1_agent_agent_proto_rawDescGZIP(), []int{6}
+}
+
+var File_proto_v1_agent_agent_proto protoreflect.FileDescriptor
+
+const file_proto_v1_agent_agent_proto_rawDesc = "" +
+	"\n" +
+	"\x1aproto/v1/agent/agent.proto\x12\bagent.v1\"H\n" +
+	"\x0fRegisterRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x1a\n" +
+	"\bcapacity\x18\x02 \x01(\x05R\bcapacity\"I\n" +
+	"\x10RegisterResponse\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x1a\n" +
+	"\bcapacity\x18\x02 \x01(\x05R\bcapacity\"I\n" +
+	"\x16FetchOperationsRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"\xb4\x01\n" +
+	"\x04Task\x12!\n" +
+	"\foperation_id\x18\x01 \x01(\tR\voperationId\x12%\n" +
+	"\x0eoperation_type\x18\x02 \x01(\x05R\roperationType\x12\x1a\n" +
+	"\boperand1\x18\x03 \x01(\tR\boperand1\x12\x1a\n" +
+	"\boperand2\x18\x04 \x01(\tR\boperand2\x12*\n" +
+	"\x11operation_time_ms\x18\x05 \x01(\x03R\x0foperationTimeMs\"?\n" +
+	"\x17FetchOperationsResponse\x12$\n" +
+	"\x05tasks\x18\x01 \x03(\v2\x0e.agent.v1.TaskR\x05tasks\"\x81\x01\n" +
+	"\x13ReportResultRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12!\n" +
+	"\foperation_id\x18\x02 \x01(\tR\voperationId\x12\x16\n" +
+	"\x06result\x18\x03 \x01(\tR\x06result\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"\x16\n" +
+	"\x14ReportResultResponse2\xf8\x01\n" +
+	"\fAgentService\x12A\n" +
+	"\bRegister\x12\x19.agent.v1.RegisterRequest\x1a\x1a.agent.v1.RegisterResponse\x12V\n" +
+	"\x0fFetchOperations\x12 .agent.v1.FetchOperationsRequest\x1a!.agent.v1.FetchOperationsResponse\x12M\n" +
+	"\fReportResult\x12\x1d.agent.v1.ReportResultRequest\x1a\x1e.agent.v1.ReportResultResponseBIZGgithub.com/flexer2006/y.lms-final-task-calc-go/pkg/api/agent/v1;agentv1b\x06proto3"
+
+var (
+	file_proto_v1_agent_agent_proto_rawDescOnce sync.Once
+	file_proto_v1_agent_agent_proto_rawDescData []byte
+)
+
+func file_proto_v1_agent_agent_proto_rawDescGZIP() []byte {
+	file_proto_v1_agent_agent_proto_rawDescOnce.Do(func() {
+		file_proto_v1_agent_agent_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_v1_agent_agent_proto_rawDesc), len(file_proto_v1_agent_agent_proto_rawDesc)))
+	})
+	return file_proto_v1_agent_agent_proto_rawDescData
+}
+
+var file_proto_v1_agent_agent_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_proto_v1_agent_agent_proto_goTypes = []any{
+	(*RegisterRequest)(nil),         // 0: agent.v1.RegisterRequest
+	(*RegisterResponse)(nil),        // 1: agent.v1.RegisterResponse
+	(*FetchOperationsRequest)(nil),  // 2: agent.v1.FetchOperationsRequest
+	(*Task)(nil),                    // 3: agent.v1.Task
+	(*FetchOperationsResponse)(nil), // 4: agent.v1.FetchOperationsResponse
+	(*ReportResultRequest)(nil),     // 5: agent.v1.ReportResultRequest
+	(*ReportResultResponse)(nil),    // 6: agent.v1.ReportResultResponse
+}
+var file_proto_v1_agent_agent_proto_depIdxs = []int32{
+	3, // 0: agent.v1.FetchOperationsResponse.tasks:type_name -> agent.v1.Task
+	0, // 1: agent.v1.AgentService.Register:input_type -> agent.v1.RegisterRequest
+	2, // 2: agent.v1.AgentService.FetchOperations:input_type -> agent.v1.FetchOperationsRequest
+	5, // 3: agent.v1.AgentService.ReportResult:input_type -> agent.v1.ReportResultRequest
+	1, // 4: agent.v1.AgentService.Register:output_type -> agent.v1.RegisterResponse
+	4, // 5: agent.v1.AgentService.FetchOperations:output_type -> agent.v1.FetchOperationsResponse
+	6, // 6: agent.v1.AgentService.ReportResult:output_type -> agent.v1.ReportResultResponse
+	4, // [4:7] is the sub-list for method output_type
+	1, // [1:4] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_proto_v1_agent_agent_proto_init() }
+func file_proto_v1_agent_agent_proto_init() {
+	if File_proto_v1_agent_agent_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_v1_agent_agent_proto_rawDesc), len(file_proto_v1_agent_agent_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_v1_agent_agent_proto_goTypes,
+		DependencyIndexes: file_proto_v1_agent_agent_proto_depIdxs,
+		MessageInfos:      file_proto_v1_agent_agent_proto_msgTypes,
+	}.Build()
+	File_proto_v1_agent_agent_proto = out.File
+	file_proto_v1_agent_agent_proto_goTypes = nil
+	file_proto_v1_agent_agent_proto_depIdxs = nil
+}
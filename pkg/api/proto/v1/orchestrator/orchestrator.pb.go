@@ -10,7 +10,6 @@ import (
 	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
-	emptypb "google.golang.org/protobuf/types/known/emptypb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
@@ -36,6 +35,8 @@ const (
 	CalculationStatus_COMPLETED CalculationStatus = 2
 	// Вычисление завершилось с ошибкой.
 	CalculationStatus_ERROR CalculationStatus = 3
+	// Вычисление отменено пользователем.
+	CalculationStatus_CANCELLED CalculationStatus = 4
 )
 
 // Enum value maps for CalculationStatus.
@@ -45,12 +46,14 @@ var (
 		1: "IN_PROGRESS",
 		2: "COMPLETED",
 		3: "ERROR",
+		4: "CANCELLED",
 	}
 	CalculationStatus_value = map[string]int32{
 		"PENDING":     0,
 		"IN_PROGRESS": 1,
 		"COMPLETED":   2,
 		"ERROR":       3,
+		"CANCELLED":   4,
 	}
 )
 
@@ -93,6 +96,8 @@ const (
 	OperationStatus_OPERATION_COMPLETED OperationStatus = 2
 	// Операция завершилась с ошибкой.
 	OperationStatus_OPERATION_ERROR OperationStatus = 3
+	// Операция отменена вместе с вычислением.
+	OperationStatus_OPERATION_CANCELLED OperationStatus = 4
 )
 
 // Enum value maps for OperationStatus.
@@ -102,12 +107,14 @@ var (
 		1: "OPERATION_IN_PROGRESS",
 		2: "OPERATION_COMPLETED",
 		3: "OPERATION_ERROR",
+		4: "OPERATION_CANCELLED",
 	}
 	OperationStatus_value = map[string]int32{
 		"OPERATION_PENDING":     0,
 		"OPERATION_IN_PROGRESS": 1,
 		"OPERATION_COMPLETED":   2,
 		"OPERATION_ERROR":       3,
+		"OPERATION_CANCELLED":   4,
 	}
 )
 
@@ -152,6 +159,10 @@ const (
 	OperationType_TYPE_MULTIPLICATION OperationType = 3
 	// Деление.
 	OperationType_TYPE_DIVISION OperationType = 4
+	// Остаток от деления.
+	OperationType_TYPE_MODULO OperationType = 5
+	// Факториал.
+	OperationType_TYPE_FACTORIAL OperationType = 6
 )
 
 // Enum value maps for OperationType.
@@ -162,6 +173,8 @@ var (
 		2: "TYPE_SUBTRACTION",
 		3: "TYPE_MULTIPLICATION",
 		4: "TYPE_DIVISION",
+		5: "TYPE_MODULO",
+		6: "TYPE_FACTORIAL",
 	}
 	OperationType_value = map[string]int32{
 		"TYPE_UNSPECIFIED":    0,
@@ -169,6 +182,8 @@ var (
 		"TYPE_SUBTRACTION":    2,
 		"TYPE_MULTIPLICATION": 3,
 		"TYPE_DIVISION":       4,
+		"TYPE_MODULO":         5,
+		"TYPE_FACTORIAL":      6,
 	}
 )
 
@@ -203,9 +218,17 @@ func (OperationType) EnumDescriptor() ([]byte, []int) {
 type CalculateRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Арифметическое выражение для вычисления.
-	Expression    string `protobuf:"bytes,1,opt,name=expression,proto3" json:"expression,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	Expression string `protobuf:"bytes,1,opt,name=expression,proto3" json:"expression,omitempty"`
+	// Канал, через который отправлено выражение (web, cli, api-token, batch).
+	Source string `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	// Версия клиента из заголовка запроса; пустая, если сохранение версии выключено.
+	ClientVersion string `protobuf:"bytes,3,opt,name=client_version,json=clientVersion,proto3" json:"client_version,omitempty"`
+	// Приоритет выполнения от 0 до 10: операции с большим приоритетом назначаются агентам раньше.
+	Priority int32 `protobuf:"varint,4,opt,name=priority,proto3" json:"priority,omitempty"`
+	// Ключ идемпотентности: повторный запрос с тем же ключом возвращает уже созданное вычисление.
+	IdempotencyKey string `protobuf:"bytes,5,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *CalculateRequest) Reset() {
@@ -245,6 +268,34 @@ func (x *CalculateRequest) GetExpression() string {
 	return ""
 }
 
+func (x *CalculateRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *CalculateRequest) GetClientVersion() string {
+	if x != nil {
+		return x.ClientVersion
+	}
+	return ""
+}
+
+func (x *CalculateRequest) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *CalculateRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
 // Ответ с деталями вычисления.
 type CalculateResponse struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -255,7 +306,17 @@ type CalculateResponse struct {
 	// Результат, если вычисление завершено.
 	Result string `protobuf:"bytes,3,opt,name=result,proto3" json:"result,omitempty"`
 	// Сообщение об ошибке, если вычисление не удалось.
-	ErrorMessage  string `protobuf:"bytes,4,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	ErrorMessage string `protobuf:"bytes,4,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	// Канал, через который отправлено выражение.
+	Source string `protobuf:"bytes,5,opt,name=source,proto3" json:"source,omitempty"`
+	// Оценка времени до завершения вычисления в миллисекундах.
+	EstimatedDurationMs int64 `protobuf:"varint,6,opt,name=estimated_duration_ms,json=estimatedDurationMs,proto3" json:"estimated_duration_ms,omitempty"`
+	// Агенты, которые при текущем состоянии пула вероятно выполнят операции вычисления.
+	LikelyAgents []string `protobuf:"bytes,7,rep,name=likely_agents,json=likelyAgents,proto3" json:"likely_agents,omitempty"`
+	// Результат, округленный для отображения, если результат взят из кэша.
+	DisplayResult string `protobuf:"bytes,8,opt,name=display_result,json=displayResult,proto3" json:"display_result,omitempty"`
+	// Предупреждения к успешному результату, например о делении на ноль в нестрогом режиме.
+	Warnings      []string `protobuf:"bytes,9,rep,name=warnings,proto3" json:"warnings,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -318,6 +379,41 @@ func (x *CalculateResponse) GetErrorMessage() string {
 	return ""
 }
 
+func (x *CalculateResponse) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *CalculateResponse) GetEstimatedDurationMs() int64 {
+	if x != nil {
+		return x.EstimatedDurationMs
+	}
+	return 0
+}
+
+func (x *CalculateResponse) GetLikelyAgents() []string {
+	if x != nil {
+		return x.LikelyAgents
+	}
+	return nil
+}
+
+func (x *CalculateResponse) GetDisplayResult() string {
+	if x != nil {
+		return x.DisplayResult
+	}
+	return ""
+}
+
+func (x *CalculateResponse) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
 // Запрос на получение деталей вычисления по ID.
 type GetCalculationRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -382,7 +478,16 @@ type GetCalculationResponse struct {
 	// Время создания.
 	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	// Время последнего обновления.
-	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	UpdatedAt *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// Канал, через который отправлено выражение.
+	Source string `protobuf:"bytes,9,opt,name=source,proto3" json:"source,omitempty"`
+	// Версия клиента, отправившего выражение. Заполняется только для администраторов.
+	ClientVersion string `protobuf:"bytes,10,opt,name=client_version,json=clientVersion,proto3" json:"client_version,omitempty"`
+	// Результат, округленный для отображения, при точном result. Заполняется в режиме decimal,
+	// если задана точность отображения.
+	DisplayResult string `protobuf:"bytes,11,opt,name=display_result,json=displayResult,proto3" json:"display_result,omitempty"`
+	// Предупреждения к успешному результату, например о делении на ноль в нестрогом режиме.
+	Warnings      []string `protobuf:"bytes,12,rep,name=warnings,proto3" json:"warnings,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -473,29 +578,57 @@ func (x *GetCalculationResponse) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
-// Ответ со списком вычислений.
-type ListCalculationsResponse struct {
+func (x *GetCalculationResponse) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *GetCalculationResponse) GetClientVersion() string {
+	if x != nil {
+		return x.ClientVersion
+	}
+	return ""
+}
+
+func (x *GetCalculationResponse) GetDisplayResult() string {
+	if x != nil {
+		return x.DisplayResult
+	}
+	return ""
+}
+
+func (x *GetCalculationResponse) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+// Запрос на получение операции по ID.
+type GetOperationRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// Список вычислений.
-	Calculations  []*GetCalculationResponse `protobuf:"bytes,1,rep,name=calculations,proto3" json:"calculations,omitempty"`
+	// Идентификатор операции.
+	Id            string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListCalculationsResponse) Reset() {
-	*x = ListCalculationsResponse{}
+func (x *GetOperationRequest) Reset() {
+	*x = GetOperationRequest{}
 	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListCalculationsResponse) String() string {
+func (x *GetOperationRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListCalculationsResponse) ProtoMessage() {}
+func (*GetOperationRequest) ProtoMessage() {}
 
-func (x *ListCalculationsResponse) ProtoReflect() protoreflect.Message {
+func (x *GetOperationRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -507,69 +640,2529 @@ func (x *ListCalculationsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListCalculationsResponse.ProtoReflect.Descriptor instead.
-func (*ListCalculationsResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetOperationRequest.ProtoReflect.Descriptor instead.
+func (*GetOperationRequest) Descriptor() ([]byte, []int) {
 	return file_proto_v1_orchestrator_orchestrator_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *ListCalculationsResponse) GetCalculations() []*GetCalculationResponse {
+func (x *GetOperationRequest) GetId() string {
 	if x != nil {
-		return x.Calculations
+		return x.Id
 	}
-	return nil
+	return ""
 }
 
-var File_proto_v1_orchestrator_orchestrator_proto protoreflect.FileDescriptor
+// Ответ с деталями операции.
+type GetOperationResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Идентификатор операции.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Идентификатор вычисления, которому принадлежит операция.
+	CalculationId string `protobuf:"bytes,2,opt,name=calculation_id,json=calculationId,proto3" json:"calculation_id,omitempty"`
+	// Тип операции: 1 - сложение, 2 - вычитание, 3 - умножение, 4 - деление,
+	// 5 - остаток от деления, 6 - факториал.
+	OperationType int32 `protobuf:"varint,3,opt,name=operation_type,json=operationType,proto3" json:"operation_type,omitempty"`
+	// Первый операнд: число или ссылка на результат другой операции.
+	Operand1 string `protobuf:"bytes,4,opt,name=operand1,proto3" json:"operand1,omitempty"`
+	// Второй операнд.
+	Operand2 string `protobuf:"bytes,5,opt,name=operand2,proto3" json:"operand2,omitempty"`
+	// Результат операции.
+	Result string `protobuf:"bytes,6,opt,name=result,proto3" json:"result,omitempty"`
+	// Статус операции (PENDING, IN_PROGRESS, COMPLETED, ERROR).
+	Status string `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
+	// Сообщение об ошибке.
+	ErrorMessage string `protobuf:"bytes,8,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	// Агент, которому назначена операция.
+	AgentId string `protobuf:"bytes,9,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	// Время выполнения операции в миллисекундах.
+	ProcessingTimeMs int64 `protobuf:"varint,10,opt,name=processing_time_ms,json=processingTimeMs,proto3" json:"processing_time_ms,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
 
-const file_proto_v1_orchestrator_orchestrator_proto_rawDesc = "" +
-	"\n" +
-	"(proto/v1/orchestrator/orchestrator.proto\x12\x0forchestrator.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1cgoogle/api/annotations.proto\"2\n" +
-	"\x10CalculateRequest\x12\x1e\n" +
-	"\n" +
-	"expression\x18\x01 \x01(\tR\n" +
-	"expression\"\x9c\x01\n" +
-	"\x11CalculateResponse\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12:\n" +
-	"\x06status\x18\x02 \x01(\x0e2\".orchestrator.v1.CalculationStatusR\x06status\x12\x16\n" +
-	"\x06result\x18\x03 \x01(\tR\x06result\x12#\n" +
-	"\rerror_message\x18\x04 \x01(\tR\ferrorMessage\"'\n" +
-	"\x15GetCalculationRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"\xd0\x02\n" +
-	"\x16GetCalculationResponse\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
-	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1e\n" +
-	"\n" +
-	"expression\x18\x03 \x01(\tR\n" +
-	"expression\x12\x16\n" +
-	"\x06result\x18\x04 \x01(\tR\x06result\x12:\n" +
-	"\x06status\x18\x05 \x01(\x0e2\".orchestrator.v1.CalculationStatusR\x06status\x12#\n" +
-	"\rerror_message\x18\x06 \x01(\tR\ferrorMessage\x129\n" +
-	"\n" +
-	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
-	"\n" +
-	"updated_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"g\n" +
-	"\x18ListCalculationsResponse\x12K\n" +
-	"\fcalculations\x18\x01 \x03(\v2'.orchestrator.v1.GetCalculationResponseR\fcalculations*K\n" +
+func (x *GetOperationResponse) Reset() {
+	*x = GetOperationResponse{}
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOperationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOperationResponse) ProtoMessage() {}
+
+func (x *GetOperationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOperationResponse.ProtoReflect.Descriptor instead.
+func (*GetOperationResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v1_orchestrator_orchestrator_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetOperationResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetOperationResponse) GetCalculationId() string {
+	if x != nil {
+		return x.CalculationId
+	}
+	return ""
+}
+
+func (x *GetOperationResponse) GetOperationType() int32 {
+	if x != nil {
+		return x.OperationType
+	}
+	return 0
+}
+
+func (x *GetOperationResponse) GetOperand1() string {
+	if x != nil {
+		return x.Operand1
+	}
+	return ""
+}
+
+func (x *GetOperationResponse) GetOperand2() string {
+	if x != nil {
+		return x.Operand2
+	}
+	return ""
+}
+
+func (x *GetOperationResponse) GetResult() string {
+	if x != nil {
+		return x.Result
+	}
+	return ""
+}
+
+func (x *GetOperationResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *GetOperationResponse) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *GetOperationResponse) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *GetOperationResponse) GetProcessingTimeMs() int64 {
+	if x != nil {
+		return x.ProcessingTimeMs
+	}
+	return 0
+}
+
+// Запрос администратора на получение вычисления по ID.
+type AdminGetCalculationRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Идентификатор вычисления.
+	Id            string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdminGetCalculationRequest) Reset() {
+	*x = AdminGetCalculationRequest{}
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdminGetCalculationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminGetCalculationRequest) ProtoMessage() {}
+
+func (x *AdminGetCalculationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminGetCalculationRequest.ProtoReflect.Descriptor instead.
+func (*AdminGetCalculationRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v1_orchestrator_orchestrator_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *AdminGetCalculationRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Запрос подписки на изменения статуса вычисления.
+type StreamCalculationRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Идентификатор вычисления.
+	Id            string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamCalculationRequest) Reset() {
+	*x = StreamCalculationRequest{}
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamCalculationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamCalculationRequest) ProtoMessage() {}
+
+func (x *StreamCalculationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamCalculationRequest.ProtoReflect.Descriptor instead.
+func (*StreamCalculationRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v1_orchestrator_orchestrator_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *StreamCalculationRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Изменение статуса вычисления.
+type CalculationEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Идентификатор вычисления.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Новый статус вычисления.
+	Status CalculationStatus `protobuf:"varint,2,opt,name=status,proto3,enum=orchestrator.v1.CalculationStatus" json:"status,omitempty"`
+	// Результат завершенного вычисления.
+	Result string `protobuf:"bytes,3,opt,name=result,proto3" json:"result,omitempty"`
+	// Сообщение об ошибке.
+	ErrorMessage string `protobuf:"bytes,4,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	// Время изменения статуса.
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CalculationEvent) Reset() {
+	*x = CalculationEvent{}
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CalculationEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CalculationEvent) ProtoMessage() {}
+
+func (x *CalculationEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CalculationEvent.ProtoReflect.Descriptor instead.
+func (*CalculationEvent) Descriptor() ([]byte, []int) {
+	return file_proto_v1_orchestrator_orchestrator_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CalculationEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CalculationEvent) GetStatus() CalculationStatus {
+	if x != nil {
+		return x.Status
+	}
+	return CalculationStatus_PENDING
+}
+
+func (x *CalculationEvent) GetResult() string {
+	if x != nil {
+		return x.Result
+	}
+	return ""
+}
+
+func (x *CalculationEvent) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *CalculationEvent) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// Запрос на отмену вычисления.
+type CancelCalculationRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Идентификатор вычисления.
+	Id            string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelCalculationRequest) Reset() {
+	*x = CancelCalculationRequest{}
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelCalculationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelCalculationRequest) ProtoMessage() {}
+
+func (x *CancelCalculationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelCalculationRequest.ProtoReflect.Descriptor instead.
+func (*CancelCalculationRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v1_orchestrator_orchestrator_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CancelCalculationRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Ответ на отмену вычисления.
+type CancelCalculationResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Идентификатор вычисления.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Статус вычисления после отмены.
+	Status        CalculationStatus `protobuf:"varint,2,opt,name=status,proto3,enum=orchestrator.v1.CalculationStatus" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelCalculationResponse) Reset() {
+	*x = CancelCalculationResponse{}
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelCalculationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelCalculationResponse) ProtoMessage() {}
+
+func (x *CancelCalculationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelCalculationResponse.ProtoReflect.Descriptor instead.
+func (*CancelCalculationResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v1_orchestrator_orchestrator_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CancelCalculationResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CancelCalculationResponse) GetStatus() CalculationStatus {
+	if x != nil {
+		return x.Status
+	}
+	return CalculationStatus_PENDING
+}
+
+// Запрос на удаление вычисления.
+type DeleteCalculationRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Идентификатор вычисления.
+	Id            string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteCalculationRequest) Reset() {
+	*x = DeleteCalculationRequest{}
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteCalculationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCalculationRequest) ProtoMessage() {}
+
+func (x *DeleteCalculationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCalculationRequest.ProtoReflect.Descriptor instead.
+func (*DeleteCalculationRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v1_orchestrator_orchestrator_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *DeleteCalculationRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Ответ на удаление вычисления.
+type DeleteCalculationResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Идентификатор удаленного вычисления.
+	Id            string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteCalculationResponse) Reset() {
+	*x = DeleteCalculationResponse{}
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteCalculationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCalculationResponse) ProtoMessage() {}
+
+func (x *DeleteCalculationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCalculationResponse.ProtoReflect.Descriptor instead.
+func (*DeleteCalculationResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v1_orchestrator_orchestrator_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DeleteCalculationResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Запрос на получение списка вычислений.
+type ListCalculationsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Максимальное количество вычислений на странице (0 - значение по умолчанию).
+	Limit int32 `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Количество пропускаемых вычислений.
+	Offset int32 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	// Фильтр по статусу (PENDING, IN_PROGRESS, COMPLETED, ERROR); пустая строка - без фильтра.
+	Status        string `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCalculationsRequest) Reset() {
+	*x = ListCalculationsRequest{}
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCalculationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCalculationsRequest) ProtoMessage() {}
+
+func (x *ListCalculationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCalculationsRequest.ProtoReflect.Descriptor instead.
+func (*ListCalculationsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v1_orchestrator_orchestrator_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListCalculationsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListCalculationsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ListCalculationsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+// Ответ со списком вычислений.
+type ListCalculationsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Список вычислений.
+	Calculations []*GetCalculationResponse `protobuf:"bytes,1,rep,name=calculations,proto3" json:"calculations,omitempty"`
+	// Общее количество вычислений, удовлетворяющих фильтру.
+	Total int32 `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	// Примененный лимит.
+	Limit int32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Примененное смещение.
+	Offset        int32 `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCalculationsResponse) Reset() {
+	*x = ListCalculationsResponse{}
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCalculationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCalculationsResponse) ProtoMessage() {}
+
+func (x *ListCalculationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCalculationsResponse.ProtoReflect.Descriptor instead.
+func (*ListCalculationsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v1_orchestrator_orchestrator_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListCalculationsResponse) GetCalculations() []*GetCalculationResponse {
+	if x != nil {
+		return x.Calculations
+	}
+	return nil
+}
+
+func (x *ListCalculationsResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ListCalculationsResponse) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListCalculationsResponse) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+// Запрос статистики по результатам вычислений.
+type GetResultStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetResultStatsRequest) Reset() {
+	*x = GetResultStatsRequest{}
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetResultStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetResultStatsRequest) ProtoMessage() {}
+
+func (x *GetResultStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetResultStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetResultStatsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v1_orchestrator_orchestrator_proto_rawDescGZIP(), []int{15}
+}
+
+// Агрегаты по числовым результатам завершенных вычислений.
+// Значения передаются строками, чтобы не терять точность.
+type GetResultStatsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Количество учтенных результатов.
+	Count int64 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	// Сумма результатов.
+	Sum string `protobuf:"bytes,2,opt,name=sum,proto3" json:"sum,omitempty"`
+	// Среднее значение; пусто, если результатов нет.
+	Mean string `protobuf:"bytes,3,opt,name=mean,proto3" json:"mean,omitempty"`
+	// Минимальный результат; пусто, если результатов нет.
+	Min string `protobuf:"bytes,4,opt,name=min,proto3" json:"min,omitempty"`
+	// Максимальный результат; пусто, если результатов нет.
+	Max           string `protobuf:"bytes,5,opt,name=max,proto3" json:"max,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetResultStatsResponse) Reset() {
+	*x = GetResultStatsResponse{}
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetResultStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetResultStatsResponse) ProtoMessage() {}
+
+func (x *GetResultStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetResultStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetResultStatsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v1_orchestrator_orchestrator_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetResultStatsResponse) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *GetResultStatsResponse) GetSum() string {
+	if x != nil {
+		return x.Sum
+	}
+	return ""
+}
+
+func (x *GetResultStatsResponse) GetMean() string {
+	if x != nil {
+		return x.Mean
+	}
+	return ""
+}
+
+func (x *GetResultStatsResponse) GetMin() string {
+	if x != nil {
+		return x.Min
+	}
+	return ""
+}
+
+func (x *GetResultStatsResponse) GetMax() string {
+	if x != nil {
+		return x.Max
+	}
+	return ""
+}
+
+// Запрос на сравнение двух выражений.
+type CompareExpressionsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Первое выражение.
+	ExpressionA string `protobuf:"bytes,1,opt,name=expression_a,json=expressionA,proto3" json:"expression_a,omitempty"`
+	// Второе выражение.
+	ExpressionB string `protobuf:"bytes,2,opt,name=expression_b,json=expressionB,proto3" json:"expression_b,omitempty"`
+	// Допустимая абсолютная погрешность (0 - точное совпадение).
+	Tolerance     float64 `protobuf:"fixed64,3,opt,name=tolerance,proto3" json:"tolerance,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompareExpressionsRequest) Reset() {
+	*x = CompareExpressionsRequest{}
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompareExpressionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompareExpressionsRequest) ProtoMessage() {}
+
+func (x *CompareExpressionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompareExpressionsRequest.ProtoReflect.Descriptor instead.
+func (*CompareExpressionsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v1_orchestrator_orchestrator_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *CompareExpressionsRequest) GetExpressionA() string {
+	if x != nil {
+		return x.ExpressionA
+	}
+	return ""
+}
+
+func (x *CompareExpressionsRequest) GetExpressionB() string {
+	if x != nil {
+		return x.ExpressionB
+	}
+	return ""
+}
+
+func (x *CompareExpressionsRequest) GetTolerance() float64 {
+	if x != nil {
+		return x.Tolerance
+	}
+	return 0
+}
+
+// Ответ с результатом сравнения.
+type CompareExpressionsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Значение первого выражения.
+	ResultA string `protobuf:"bytes,1,opt,name=result_a,json=resultA,proto3" json:"result_a,omitempty"`
+	// Значение второго выражения.
+	ResultB string `protobuf:"bytes,2,opt,name=result_b,json=resultB,proto3" json:"result_b,omitempty"`
+	// Абсолютная разница значений.
+	Difference float64 `protobuf:"fixed64,3,opt,name=difference,proto3" json:"difference,omitempty"`
+	// Примененная погрешность.
+	Tolerance float64 `protobuf:"fixed64,4,opt,name=tolerance,proto3" json:"tolerance,omitempty"`
+	// Совпадают ли значения с учетом погрешности.
+	Equal         bool `protobuf:"varint,5,opt,name=equal,proto3" json:"equal,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompareExpressionsResponse) Reset() {
+	*x = CompareExpressionsResponse{}
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompareExpressionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompareExpressionsResponse) ProtoMessage() {}
+
+func (x *CompareExpressionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompareExpressionsResponse.ProtoReflect.Descriptor instead.
+func (*CompareExpressionsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v1_orchestrator_orchestrator_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *CompareExpressionsResponse) GetResultA() string {
+	if x != nil {
+		return x.ResultA
+	}
+	return ""
+}
+
+func (x *CompareExpressionsResponse) GetResultB() string {
+	if x != nil {
+		return x.ResultB
+	}
+	return ""
+}
+
+func (x *CompareExpressionsResponse) GetDifference() float64 {
+	if x != nil {
+		return x.Difference
+	}
+	return 0
+}
+
+func (x *CompareExpressionsResponse) GetTolerance() float64 {
+	if x != nil {
+		return x.Tolerance
+	}
+	return 0
+}
+
+func (x *CompareExpressionsResponse) GetEqual() bool {
+	if x != nil {
+		return x.Equal
+	}
+	return false
+}
+
+// Запрос на сравнение двух вычислений.
+type DiffCalculationsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Идентификатор первого вычисления.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Идентификатор второго вычисления.
+	OtherId       string `protobuf:"bytes,2,opt,name=other_id,json=otherId,proto3" json:"other_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiffCalculationsRequest) Reset() {
+	*x = DiffCalculationsRequest{}
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiffCalculationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffCalculationsRequest) ProtoMessage() {}
+
+func (x *DiffCalculationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffCalculationsRequest.ProtoReflect.Descriptor instead.
+func (*DiffCalculationsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v1_orchestrator_orchestrator_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *DiffCalculationsRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DiffCalculationsRequest) GetOtherId() string {
+	if x != nil {
+		return x.OtherId
+	}
+	return ""
+}
+
+// Ответ со сравнением двух вычислений.
+type DiffCalculationsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Идентификатор первого вычисления.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Идентификатор второго вычисления.
+	OtherId string `protobuf:"bytes,2,opt,name=other_id,json=otherId,proto3" json:"other_id,omitempty"`
+	// Выражение первого вычисления.
+	ExpressionA string `protobuf:"bytes,3,opt,name=expression_a,json=expressionA,proto3" json:"expression_a,omitempty"`
+	// Выражение второго вычисления.
+	ExpressionB string `protobuf:"bytes,4,opt,name=expression_b,json=expressionB,proto3" json:"expression_b,omitempty"`
+	// Результат первого вычисления.
+	ResultA string `protobuf:"bytes,5,opt,name=result_a,json=resultA,proto3" json:"result_a,omitempty"`
+	// Результат второго вычисления.
+	ResultB string `protobuf:"bytes,6,opt,name=result_b,json=resultB,proto3" json:"result_b,omitempty"`
+	// Совпадают ли выражения без учета пробелов.
+	SameExpression bool `protobuf:"varint,7,opt,name=same_expression,json=sameExpression,proto3" json:"same_expression,omitempty"`
+	// Совпадают ли результаты.
+	SameResult bool `protobuf:"varint,8,opt,name=same_result,json=sameResult,proto3" json:"same_result,omitempty"`
+	// Оба результата числа и поле delta заполнено.
+	Numeric bool `protobuf:"varint,9,opt,name=numeric,proto3" json:"numeric,omitempty"`
+	// Разница результатов: result_b - result_a.
+	Delta         float64 `protobuf:"fixed64,10,opt,name=delta,proto3" json:"delta,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiffCalculationsResponse) Reset() {
+	*x = DiffCalculationsResponse{}
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiffCalculationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffCalculationsResponse) ProtoMessage() {}
+
+func (x *DiffCalculationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffCalculationsResponse.ProtoReflect.Descriptor instead.
+func (*DiffCalculationsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v1_orchestrator_orchestrator_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *DiffCalculationsResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DiffCalculationsResponse) GetOtherId() string {
+	if x != nil {
+		return x.OtherId
+	}
+	return ""
+}
+
+func (x *DiffCalculationsResponse) GetExpressionA() string {
+	if x != nil {
+		return x.ExpressionA
+	}
+	return ""
+}
+
+func (x *DiffCalculationsResponse) GetExpressionB() string {
+	if x != nil {
+		return x.ExpressionB
+	}
+	return ""
+}
+
+func (x *DiffCalculationsResponse) GetResultA() string {
+	if x != nil {
+		return x.ResultA
+	}
+	return ""
+}
+
+func (x *DiffCalculationsResponse) GetResultB() string {
+	if x != nil {
+		return x.ResultB
+	}
+	return ""
+}
+
+func (x *DiffCalculationsResponse) GetSameExpression() bool {
+	if x != nil {
+		return x.SameExpression
+	}
+	return false
+}
+
+func (x *DiffCalculationsResponse) GetSameResult() bool {
+	if x != nil {
+		return x.SameResult
+	}
+	return false
+}
+
+func (x *DiffCalculationsResponse) GetNumeric() bool {
+	if x != nil {
+		return x.Numeric
+	}
+	return false
+}
+
+func (x *DiffCalculationsResponse) GetDelta() float64 {
+	if x != nil {
+		return x.Delta
+	}
+	return 0
+}
+
+// Запрос на предпросмотр группировки выражения.
+type PreviewExpressionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Арифметическое выражение.
+	Expression string `protobuf:"bytes,1,opt,name=expression,proto3" json:"expression,omitempty"`
+	// Добавлять пробелы вокруг операторов.
+	Spaced        bool `protobuf:"varint,2,opt,name=spaced,proto3" json:"spaced,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PreviewExpressionRequest) Reset() {
+	*x = PreviewExpressionRequest{}
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PreviewExpressionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PreviewExpressionRequest) ProtoMessage() {}
+
+func (x *PreviewExpressionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PreviewExpressionRequest.ProtoReflect.Descriptor instead.
+func (*PreviewExpressionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v1_orchestrator_orchestrator_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *PreviewExpressionRequest) GetExpression() string {
+	if x != nil {
+		return x.Expression
+	}
+	return ""
+}
+
+func (x *PreviewExpressionRequest) GetSpaced() bool {
+	if x != nil {
+		return x.Spaced
+	}
+	return false
+}
+
+// Выражение с явной группировкой операций.
+type PreviewExpressionResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Исходное выражение.
+	Expression string `protobuf:"bytes,1,opt,name=expression,proto3" json:"expression,omitempty"`
+	// Выражение, в котором каждая вложенная операция заключена в скобки.
+	Grouped       string `protobuf:"bytes,2,opt,name=grouped,proto3" json:"grouped,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PreviewExpressionResponse) Reset() {
+	*x = PreviewExpressionResponse{}
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PreviewExpressionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PreviewExpressionResponse) ProtoMessage() {}
+
+func (x *PreviewExpressionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PreviewExpressionResponse.ProtoReflect.Descriptor instead.
+func (*PreviewExpressionResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v1_orchestrator_orchestrator_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *PreviewExpressionResponse) GetExpression() string {
+	if x != nil {
+		return x.Expression
+	}
+	return ""
+}
+
+func (x *PreviewExpressionResponse) GetGrouped() string {
+	if x != nil {
+		return x.Grouped
+	}
+	return ""
+}
+
+// Запрос на проверку синтаксиса выражения.
+type ValidateExpressionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Арифметическое выражение.
+	Expression    string `protobuf:"bytes,1,opt,name=expression,proto3" json:"expression,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateExpressionRequest) Reset() {
+	*x = ValidateExpressionRequest{}
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateExpressionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateExpressionRequest) ProtoMessage() {}
+
+func (x *ValidateExpressionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateExpressionRequest.ProtoReflect.Descriptor instead.
+func (*ValidateExpressionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v1_orchestrator_orchestrator_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ValidateExpressionRequest) GetExpression() string {
+	if x != nil {
+		return x.Expression
+	}
+	return ""
+}
+
+// Результат проверки синтаксиса выражения.
+type ValidateExpressionResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Исходное выражение.
+	Expression string `protobuf:"bytes,1,opt,name=expression,proto3" json:"expression,omitempty"`
+	// Выражение синтаксически корректно.
+	Valid bool `protobuf:"varint,2,opt,name=valid,proto3" json:"valid,omitempty"`
+	// Номер символа с нуля, на котором найдена первая ошибка; имеет смысл при valid = false.
+	ErrorOffset int32 `protobuf:"varint,3,opt,name=error_offset,json=errorOffset,proto3" json:"error_offset,omitempty"`
+	// Описание первой ошибки.
+	ErrorMessage  string `protobuf:"bytes,4,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateExpressionResponse) Reset() {
+	*x = ValidateExpressionResponse{}
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateExpressionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateExpressionResponse) ProtoMessage() {}
+
+func (x *ValidateExpressionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateExpressionResponse.ProtoReflect.Descriptor instead.
+func (*ValidateExpressionResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v1_orchestrator_orchestrator_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ValidateExpressionResponse) GetExpression() string {
+	if x != nil {
+		return x.Expression
+	}
+	return ""
+}
+
+func (x *ValidateExpressionResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *ValidateExpressionResponse) GetErrorOffset() int32 {
+	if x != nil {
+		return x.ErrorOffset
+	}
+	return 0
+}
+
+func (x *ValidateExpressionResponse) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+// Запрос на получение метрик пула агентов.
+type GetPoolStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPoolStatsRequest) Reset() {
+	*x = GetPoolStatsRequest{}
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPoolStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPoolStatsRequest) ProtoMessage() {}
+
+func (x *GetPoolStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPoolStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetPoolStatsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v1_orchestrator_orchestrator_proto_rawDescGZIP(), []int{25}
+}
+
+// Метрики отдельного агента.
+type AgentStats struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Идентификатор агента.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Статус агента (ONLINE, BUSY, OFFLINE).
+	Status string `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	// Количество операций, выполняемых агентом.
+	CurrentLoad int32 `protobuf:"varint,3,opt,name=current_load,json=currentLoad,proto3" json:"current_load,omitempty"`
+	// Максимальное количество одновременных операций.
+	MaxCapacity int32 `protobuf:"varint,4,opt,name=max_capacity,json=maxCapacity,proto3" json:"max_capacity,omitempty"`
+	// Количество успешно выполненных операций.
+	Completed int64 `protobuf:"varint,5,opt,name=completed,proto3" json:"completed,omitempty"`
+	// Количество операций, завершившихся ошибкой.
+	Failed int64 `protobuf:"varint,6,opt,name=failed,proto3" json:"failed,omitempty"`
+	// Количество операций в очереди агента.
+	QueueDepth    int32 `protobuf:"varint,7,opt,name=queue_depth,json=queueDepth,proto3" json:"queue_depth,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AgentStats) Reset() {
+	*x = AgentStats{}
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentStats) ProtoMessage() {}
+
+func (x *AgentStats) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentStats.ProtoReflect.Descriptor instead.
+func (*AgentStats) Descriptor() ([]byte, []int) {
+	return file_proto_v1_orchestrator_orchestrator_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *AgentStats) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AgentStats) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *AgentStats) GetCurrentLoad() int32 {
+	if x != nil {
+		return x.CurrentLoad
+	}
+	return 0
+}
+
+func (x *AgentStats) GetMaxCapacity() int32 {
+	if x != nil {
+		return x.MaxCapacity
+	}
+	return 0
+}
+
+func (x *AgentStats) GetCompleted() int64 {
+	if x != nil {
+		return x.Completed
+	}
+	return 0
+}
+
+func (x *AgentStats) GetFailed() int64 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *AgentStats) GetQueueDepth() int32 {
+	if x != nil {
+		return x.QueueDepth
+	}
+	return 0
+}
+
+// Ответ с метриками пула агентов.
+type GetPoolStatsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Метрики каждого агента.
+	Agents []*AgentStats `protobuf:"bytes,1,rep,name=agents,proto3" json:"agents,omitempty"`
+	// Общее количество агентов.
+	TotalAgents int32 `protobuf:"varint,2,opt,name=total_agents,json=totalAgents,proto3" json:"total_agents,omitempty"`
+	// Количество агентов в статусе ONLINE.
+	OnlineAgents int32 `protobuf:"varint,3,opt,name=online_agents,json=onlineAgents,proto3" json:"online_agents,omitempty"`
+	// Количество агентов в статусе BUSY.
+	BusyAgents int32 `protobuf:"varint,4,opt,name=busy_agents,json=busyAgents,proto3" json:"busy_agents,omitempty"`
+	// Суммарная нагрузка агентов.
+	TotalLoad int32 `protobuf:"varint,5,opt,name=total_load,json=totalLoad,proto3" json:"total_load,omitempty"`
+	// Суммарная емкость агентов.
+	TotalCapacity int32 `protobuf:"varint,6,opt,name=total_capacity,json=totalCapacity,proto3" json:"total_capacity,omitempty"`
+	// Суммарное количество успешно выполненных операций.
+	Completed int64 `protobuf:"varint,7,opt,name=completed,proto3" json:"completed,omitempty"`
+	// Суммарное количество операций, завершившихся ошибкой.
+	Failed int64 `protobuf:"varint,8,opt,name=failed,proto3" json:"failed,omitempty"`
+	// Суммарная глубина очередей агентов.
+	QueueDepth int32 `protobuf:"varint,9,opt,name=queue_depth,json=queueDepth,proto3" json:"queue_depth,omitempty"`
+	// Счетчики операций всего пула с момента запуска, включая агентов, покинувших пул.
+	Throughput *PoolThroughput `protobuf:"bytes,10,opt,name=throughput,proto3" json:"throughput,omitempty"`
+	// Текущее количество воркеров пула; при автомасштабировании зависит от очереди операций.
+	Workers       int32 `protobuf:"varint,11,opt,name=workers,proto3" json:"workers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPoolStatsResponse) Reset() {
+	*x = GetPoolStatsResponse{}
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPoolStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPoolStatsResponse) ProtoMessage() {}
+
+func (x *GetPoolStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPoolStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetPoolStatsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v1_orchestrator_orchestrator_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *GetPoolStatsResponse) GetAgents() []*AgentStats {
+	if x != nil {
+		return x.Agents
+	}
+	return nil
+}
+
+func (x *GetPoolStatsResponse) GetTotalAgents() int32 {
+	if x != nil {
+		return x.TotalAgents
+	}
+	return 0
+}
+
+func (x *GetPoolStatsResponse) GetOnlineAgents() int32 {
+	if x != nil {
+		return x.OnlineAgents
+	}
+	return 0
+}
+
+func (x *GetPoolStatsResponse) GetBusyAgents() int32 {
+	if x != nil {
+		return x.BusyAgents
+	}
+	return 0
+}
+
+func (x *GetPoolStatsResponse) GetTotalLoad() int32 {
+	if x != nil {
+		return x.TotalLoad
+	}
+	return 0
+}
+
+func (x *GetPoolStatsResponse) GetTotalCapacity() int32 {
+	if x != nil {
+		return x.TotalCapacity
+	}
+	return 0
+}
+
+func (x *GetPoolStatsResponse) GetCompleted() int64 {
+	if x != nil {
+		return x.Completed
+	}
+	return 0
+}
+
+func (x *GetPoolStatsResponse) GetFailed() int64 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *GetPoolStatsResponse) GetQueueDepth() int32 {
+	if x != nil {
+		return x.QueueDepth
+	}
+	return 0
+}
+
+func (x *GetPoolStatsResponse) GetThroughput() *PoolThroughput {
+	if x != nil {
+		return x.Throughput
+	}
+	return nil
+}
+
+func (x *GetPoolStatsResponse) GetWorkers() int32 {
+	if x != nil {
+		return x.Workers
+	}
+	return 0
+}
+
+// Запрос на получение профиля времени выполнения операций.
+type GetTimingProfileRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTimingProfileRequest) Reset() {
+	*x = GetTimingProfileRequest{}
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTimingProfileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTimingProfileRequest) ProtoMessage() {}
+
+func (x *GetTimingProfileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTimingProfileRequest.ProtoReflect.Descriptor instead.
+func (*GetTimingProfileRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v1_orchestrator_orchestrator_proto_rawDescGZIP(), []int{28}
+}
+
+// Фактическое время выполнения операций отдельным агентом.
+type AgentTiming struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Идентификатор агента.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Включен ли детерминированный режим (операции выполняются без задержки).
+	Deterministic bool `protobuf:"varint,2,opt,name=deterministic,proto3" json:"deterministic,omitempty"`
+	// Время выполнения операций по типам в миллисекундах.
+	OperationTimesMs map[string]int64 `protobuf:"bytes,3,rep,name=operation_times_ms,json=operationTimesMs,proto3" json:"operation_times_ms,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *AgentTiming) Reset() {
+	*x = AgentTiming{}
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentTiming) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentTiming) ProtoMessage() {}
+
+func (x *AgentTiming) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentTiming.ProtoReflect.Descriptor instead.
+func (*AgentTiming) Descriptor() ([]byte, []int) {
+	return file_proto_v1_orchestrator_orchestrator_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *AgentTiming) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AgentTiming) GetDeterministic() bool {
+	if x != nil {
+		return x.Deterministic
+	}
+	return false
+}
+
+func (x *AgentTiming) GetOperationTimesMs() map[string]int64 {
+	if x != nil {
+		return x.OperationTimesMs
+	}
+	return nil
+}
+
+// Ответ с профилем времени выполнения операций.
+type GetTimingProfileResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Настроенное время выполнения операций по типам в миллисекундах.
+	ConfiguredMs map[string]int64 `protobuf:"bytes,1,rep,name=configured_ms,json=configuredMs,proto3" json:"configured_ms,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	// Фактическое время выполнения операций каждым агентом.
+	Agents        []*AgentTiming `protobuf:"bytes,2,rep,name=agents,proto3" json:"agents,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTimingProfileResponse) Reset() {
+	*x = GetTimingProfileResponse{}
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTimingProfileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTimingProfileResponse) ProtoMessage() {}
+
+func (x *GetTimingProfileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTimingProfileResponse.ProtoReflect.Descriptor instead.
+func (*GetTimingProfileResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v1_orchestrator_orchestrator_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *GetTimingProfileResponse) GetConfiguredMs() map[string]int64 {
+	if x != nil {
+		return x.ConfiguredMs
+	}
+	return nil
+}
+
+func (x *GetTimingProfileResponse) GetAgents() []*AgentTiming {
+	if x != nil {
+		return x.Agents
+	}
+	return nil
+}
+
+// Запрос на изменение емкости агента.
+type SetAgentCapacityRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Идентификатор агента.
+	AgentId string `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	// Новая емкость агента в единицах стоимости операций.
+	Capacity      int32 `protobuf:"varint,2,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetAgentCapacityRequest) Reset() {
+	*x = SetAgentCapacityRequest{}
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetAgentCapacityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetAgentCapacityRequest) ProtoMessage() {}
+
+func (x *SetAgentCapacityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetAgentCapacityRequest.ProtoReflect.Descriptor instead.
+func (*SetAgentCapacityRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v1_orchestrator_orchestrator_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *SetAgentCapacityRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *SetAgentCapacityRequest) GetCapacity() int32 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+// Метрики агента после изменения емкости.
+type SetAgentCapacityResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Agent         *AgentStats            `protobuf:"bytes,1,opt,name=agent,proto3" json:"agent,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetAgentCapacityResponse) Reset() {
+	*x = SetAgentCapacityResponse{}
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetAgentCapacityResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetAgentCapacityResponse) ProtoMessage() {}
+
+func (x *SetAgentCapacityResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetAgentCapacityResponse.ProtoReflect.Descriptor instead.
+func (*SetAgentCapacityResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v1_orchestrator_orchestrator_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *SetAgentCapacityResponse) GetAgent() *AgentStats {
+	if x != nil {
+		return x.Agent
+	}
+	return nil
+}
+
+// Счетчики операций всего пула.
+type PoolThroughput struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Количество операций, переданных агентам.
+	Dispatched int64 `protobuf:"varint,1,opt,name=dispatched,proto3" json:"dispatched,omitempty"`
+	// Количество успешно выполненных операций.
+	Completed int64 `protobuf:"varint,2,opt,name=completed,proto3" json:"completed,omitempty"`
+	// Количество операций, завершившихся ошибкой.
+	Failed        int64 `protobuf:"varint,3,opt,name=failed,proto3" json:"failed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PoolThroughput) Reset() {
+	*x = PoolThroughput{}
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PoolThroughput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PoolThroughput) ProtoMessage() {}
+
+func (x *PoolThroughput) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PoolThroughput.ProtoReflect.Descriptor instead.
+func (*PoolThroughput) Descriptor() ([]byte, []int) {
+	return file_proto_v1_orchestrator_orchestrator_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *PoolThroughput) GetDispatched() int64 {
+	if x != nil {
+		return x.Dispatched
+	}
+	return 0
+}
+
+func (x *PoolThroughput) GetCompleted() int64 {
+	if x != nil {
+		return x.Completed
+	}
+	return 0
+}
+
+func (x *PoolThroughput) GetFailed() int64 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+// Запрос сводной статистики сервиса.
+type GetSystemStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSystemStatsRequest) Reset() {
+	*x = GetSystemStatsRequest{}
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSystemStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSystemStatsRequest) ProtoMessage() {}
+
+func (x *GetSystemStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSystemStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetSystemStatsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v1_orchestrator_orchestrator_proto_rawDescGZIP(), []int{34}
+}
+
+// Состояние пула соединений с базой данных.
+type DBPoolStats struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Общее количество соединений.
+	TotalConns int32 `protobuf:"varint,1,opt,name=total_conns,json=totalConns,proto3" json:"total_conns,omitempty"`
+	// Количество простаивающих соединений.
+	IdleConns int32 `protobuf:"varint,2,opt,name=idle_conns,json=idleConns,proto3" json:"idle_conns,omitempty"`
+	// Количество занятых соединений.
+	AcquiredConns int32 `protobuf:"varint,3,opt,name=acquired_conns,json=acquiredConns,proto3" json:"acquired_conns,omitempty"`
+	// Максимальный размер пула.
+	MaxConns int32 `protobuf:"varint,4,opt,name=max_conns,json=maxConns,proto3" json:"max_conns,omitempty"`
+	// Количество успешных получений соединения из пула.
+	AcquireCount int64 `protobuf:"varint,5,opt,name=acquire_count,json=acquireCount,proto3" json:"acquire_count,omitempty"`
+	// Суммарное время ожидания полученных соединений в миллисекундах.
+	AcquireDurationMs int64 `protobuf:"varint,6,opt,name=acquire_duration_ms,json=acquireDurationMs,proto3" json:"acquire_duration_ms,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *DBPoolStats) Reset() {
+	*x = DBPoolStats{}
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DBPoolStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DBPoolStats) ProtoMessage() {}
+
+func (x *DBPoolStats) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DBPoolStats.ProtoReflect.Descriptor instead.
+func (*DBPoolStats) Descriptor() ([]byte, []int) {
+	return file_proto_v1_orchestrator_orchestrator_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *DBPoolStats) GetTotalConns() int32 {
+	if x != nil {
+		return x.TotalConns
+	}
+	return 0
+}
+
+func (x *DBPoolStats) GetIdleConns() int32 {
+	if x != nil {
+		return x.IdleConns
+	}
+	return 0
+}
+
+func (x *DBPoolStats) GetAcquiredConns() int32 {
+	if x != nil {
+		return x.AcquiredConns
+	}
+	return 0
+}
+
+func (x *DBPoolStats) GetMaxConns() int32 {
+	if x != nil {
+		return x.MaxConns
+	}
+	return 0
+}
+
+func (x *DBPoolStats) GetAcquireCount() int64 {
+	if x != nil {
+		return x.AcquireCount
+	}
+	return 0
+}
+
+func (x *DBPoolStats) GetAcquireDurationMs() int64 {
+	if x != nil {
+		return x.AcquireDurationMs
+	}
+	return 0
+}
+
+// Ответ со сводной статистикой сервиса.
+type GetSystemStatsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Количество вычислений в каждом статусе.
+	CalculationsByStatus map[string]int64 `protobuf:"bytes,1,rep,name=calculations_by_status,json=calculationsByStatus,proto3" json:"calculations_by_status,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	// Общее количество вычислений.
+	TotalCalculations int64 `protobuf:"varint,2,opt,name=total_calculations,json=totalCalculations,proto3" json:"total_calculations,omitempty"`
+	// Общее количество агентов.
+	TotalAgents int32 `protobuf:"varint,3,opt,name=total_agents,json=totalAgents,proto3" json:"total_agents,omitempty"`
+	// Количество агентов в статусе ONLINE или BUSY.
+	ActiveAgents int32 `protobuf:"varint,4,opt,name=active_agents,json=activeAgents,proto3" json:"active_agents,omitempty"`
+	// Количество операций, ожидающих выполнения.
+	PendingOperations int64 `protobuf:"varint,5,opt,name=pending_operations,json=pendingOperations,proto3" json:"pending_operations,omitempty"`
+	// Состояние пула соединений с базой данных.
+	DbPool        *DBPoolStats `protobuf:"bytes,6,opt,name=db_pool,json=dbPool,proto3" json:"db_pool,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSystemStatsResponse) Reset() {
+	*x = GetSystemStatsResponse{}
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSystemStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSystemStatsResponse) ProtoMessage() {}
+
+func (x *GetSystemStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSystemStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetSystemStatsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v1_orchestrator_orchestrator_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *GetSystemStatsResponse) GetCalculationsByStatus() map[string]int64 {
+	if x != nil {
+		return x.CalculationsByStatus
+	}
+	return nil
+}
+
+func (x *GetSystemStatsResponse) GetTotalCalculations() int64 {
+	if x != nil {
+		return x.TotalCalculations
+	}
+	return 0
+}
+
+func (x *GetSystemStatsResponse) GetTotalAgents() int32 {
+	if x != nil {
+		return x.TotalAgents
+	}
+	return 0
+}
+
+func (x *GetSystemStatsResponse) GetActiveAgents() int32 {
+	if x != nil {
+		return x.ActiveAgents
+	}
+	return 0
+}
+
+func (x *GetSystemStatsResponse) GetPendingOperations() int64 {
+	if x != nil {
+		return x.PendingOperations
+	}
+	return 0
+}
+
+func (x *GetSystemStatsResponse) GetDbPool() *DBPoolStats {
+	if x != nil {
+		return x.DbPool
+	}
+	return nil
+}
+
+// Запрос текущего уровня журнала.
+type GetLogLevelRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLogLevelRequest) Reset() {
+	*x = GetLogLevelRequest{}
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLogLevelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLogLevelRequest) ProtoMessage() {}
+
+func (x *GetLogLevelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLogLevelRequest.ProtoReflect.Descriptor instead.
+func (*GetLogLevelRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v1_orchestrator_orchestrator_proto_rawDescGZIP(), []int{37}
+}
+
+// Запрос на изменение уровня журнала.
+type SetLogLevelRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Новый уровень: debug, info, warn, error или fatal.
+	Level         string `protobuf:"bytes,1,opt,name=level,proto3" json:"level,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetLogLevelRequest) Reset() {
+	*x = SetLogLevelRequest{}
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetLogLevelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetLogLevelRequest) ProtoMessage() {}
+
+func (x *SetLogLevelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetLogLevelRequest.ProtoReflect.Descriptor instead.
+func (*SetLogLevelRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v1_orchestrator_orchestrator_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *SetLogLevelRequest) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+// Текущий уровень журнала.
+type LogLevelResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Level         string                 `protobuf:"bytes,1,opt,name=level,proto3" json:"level,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogLevelResponse) Reset() {
+	*x = LogLevelResponse{}
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogLevelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogLevelResponse) ProtoMessage() {}
+
+func (x *LogLevelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_orchestrator_orchestrator_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogLevelResponse.ProtoReflect.Descriptor instead.
+func (*LogLevelResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v1_orchestrator_orchestrator_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *LogLevelResponse) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+var File_proto_v1_orchestrator_orchestrator_proto protoreflect.FileDescriptor
+
+const file_proto_v1_orchestrator_orchestrator_proto_rawDesc = "" +
+	"\n" +
+	"(proto/v1/orchestrator/orchestrator.proto\x12\x0forchestrator.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1cgoogle/api/annotations.proto\"\xb6\x01\n" +
+	"\x10CalculateRequest\x12\x1e\n" +
+	"\n" +
+	"expression\x18\x01 \x01(\tR\n" +
+	"expression\x12\x16\n" +
+	"\x06source\x18\x02 \x01(\tR\x06source\x12%\n" +
+	"\x0eclient_version\x18\x03 \x01(\tR\rclientVersion\x12\x1a\n" +
+	"\bpriority\x18\x04 \x01(\x05R\bpriority\x12'\n" +
+	"\x0fidempotency_key\x18\x05 \x01(\tR\x0eidempotencyKey\"\xd0\x02\n" +
+	"\x11CalculateResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12:\n" +
+	"\x06status\x18\x02 \x01(\x0e2\".orchestrator.v1.CalculationStatusR\x06status\x12\x16\n" +
+	"\x06result\x18\x03 \x01(\tR\x06result\x12#\n" +
+	"\rerror_message\x18\x04 \x01(\tR\ferrorMessage\x12\x16\n" +
+	"\x06source\x18\x05 \x01(\tR\x06source\x122\n" +
+	"\x15estimated_duration_ms\x18\x06 \x01(\x03R\x13estimatedDurationMs\x12#\n" +
+	"\rlikely_agents\x18\a \x03(\tR\flikelyAgents\x12%\n" +
+	"\x0edisplay_result\x18\b \x01(\tR\rdisplayResult\x12\x1a\n" +
+	"\bwarnings\x18\t \x03(\tR\bwarnings\"'\n" +
+	"\x15GetCalculationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xd2\x03\n" +
+	"\x16GetCalculationResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1e\n" +
+	"\n" +
+	"expression\x18\x03 \x01(\tR\n" +
+	"expression\x12\x16\n" +
+	"\x06result\x18\x04 \x01(\tR\x06result\x12:\n" +
+	"\x06status\x18\x05 \x01(\x0e2\".orchestrator.v1.CalculationStatusR\x06status\x12#\n" +
+	"\rerror_message\x18\x06 \x01(\tR\ferrorMessage\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x16\n" +
+	"\x06source\x18\t \x01(\tR\x06source\x12%\n" +
+	"\x0eclient_version\x18\n" +
+	" \x01(\tR\rclientVersion\x12%\n" +
+	"\x0edisplay_result\x18\v \x01(\tR\rdisplayResult\x12\x1a\n" +
+	"\bwarnings\x18\f \x03(\tR\bwarnings\"%\n" +
+	"\x13GetOperationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xca\x02\n" +
+	"\x14GetOperationResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12%\n" +
+	"\x0ecalculation_id\x18\x02 \x01(\tR\rcalculationId\x12%\n" +
+	"\x0eoperation_type\x18\x03 \x01(\x05R\roperationType\x12\x1a\n" +
+	"\boperand1\x18\x04 \x01(\tR\boperand1\x12\x1a\n" +
+	"\boperand2\x18\x05 \x01(\tR\boperand2\x12\x16\n" +
+	"\x06result\x18\x06 \x01(\tR\x06result\x12\x16\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x12#\n" +
+	"\rerror_message\x18\b \x01(\tR\ferrorMessage\x12\x19\n" +
+	"\bagent_id\x18\t \x01(\tR\aagentId\x12,\n" +
+	"\x12processing_time_ms\x18\n" +
+	" \x01(\x03R\x10processingTimeMs\",\n" +
+	"\x1aAdminGetCalculationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"*\n" +
+	"\x18StreamCalculationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xd6\x01\n" +
+	"\x10CalculationEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12:\n" +
+	"\x06status\x18\x02 \x01(\x0e2\".orchestrator.v1.CalculationStatusR\x06status\x12\x16\n" +
+	"\x06result\x18\x03 \x01(\tR\x06result\x12#\n" +
+	"\rerror_message\x18\x04 \x01(\tR\ferrorMessage\x129\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"*\n" +
+	"\x18CancelCalculationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"g\n" +
+	"\x19CancelCalculationResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12:\n" +
+	"\x06status\x18\x02 \x01(\x0e2\".orchestrator.v1.CalculationStatusR\x06status\"*\n" +
+	"\x18DeleteCalculationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"+\n" +
+	"\x19DeleteCalculationResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"_\n" +
+	"\x17ListCalculationsRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x05R\x06offset\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\"\xab\x01\n" +
+	"\x18ListCalculationsResponse\x12K\n" +
+	"\fcalculations\x18\x01 \x03(\v2'.orchestrator.v1.GetCalculationResponseR\fcalculations\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x04 \x01(\x05R\x06offset\"\x17\n" +
+	"\x15GetResultStatsRequest\"x\n" +
+	"\x16GetResultStatsResponse\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\x03R\x05count\x12\x10\n" +
+	"\x03sum\x18\x02 \x01(\tR\x03sum\x12\x12\n" +
+	"\x04mean\x18\x03 \x01(\tR\x04mean\x12\x10\n" +
+	"\x03min\x18\x04 \x01(\tR\x03min\x12\x10\n" +
+	"\x03max\x18\x05 \x01(\tR\x03max\"\x7f\n" +
+	"\x19CompareExpressionsRequest\x12!\n" +
+	"\fexpression_a\x18\x01 \x01(\tR\vexpressionA\x12!\n" +
+	"\fexpression_b\x18\x02 \x01(\tR\vexpressionB\x12\x1c\n" +
+	"\ttolerance\x18\x03 \x01(\x01R\ttolerance\"\xa6\x01\n" +
+	"\x1aCompareExpressionsResponse\x12\x19\n" +
+	"\bresult_a\x18\x01 \x01(\tR\aresultA\x12\x19\n" +
+	"\bresult_b\x18\x02 \x01(\tR\aresultB\x12\x1e\n" +
+	"\n" +
+	"difference\x18\x03 \x01(\x01R\n" +
+	"difference\x12\x1c\n" +
+	"\ttolerance\x18\x04 \x01(\x01R\ttolerance\x12\x14\n" +
+	"\x05equal\x18\x05 \x01(\bR\x05equal\"D\n" +
+	"\x17DiffCalculationsRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\bother_id\x18\x02 \x01(\tR\aotherId\"\xbb\x02\n" +
+	"\x18DiffCalculationsResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\bother_id\x18\x02 \x01(\tR\aotherId\x12!\n" +
+	"\fexpression_a\x18\x03 \x01(\tR\vexpressionA\x12!\n" +
+	"\fexpression_b\x18\x04 \x01(\tR\vexpressionB\x12\x19\n" +
+	"\bresult_a\x18\x05 \x01(\tR\aresultA\x12\x19\n" +
+	"\bresult_b\x18\x06 \x01(\tR\aresultB\x12'\n" +
+	"\x0fsame_expression\x18\a \x01(\bR\x0esameExpression\x12\x1f\n" +
+	"\vsame_result\x18\b \x01(\bR\n" +
+	"sameResult\x12\x18\n" +
+	"\anumeric\x18\t \x01(\bR\anumeric\x12\x14\n" +
+	"\x05delta\x18\n" +
+	" \x01(\x01R\x05delta\"R\n" +
+	"\x18PreviewExpressionRequest\x12\x1e\n" +
+	"\n" +
+	"expression\x18\x01 \x01(\tR\n" +
+	"expression\x12\x16\n" +
+	"\x06spaced\x18\x02 \x01(\bR\x06spaced\"U\n" +
+	"\x19PreviewExpressionResponse\x12\x1e\n" +
+	"\n" +
+	"expression\x18\x01 \x01(\tR\n" +
+	"expression\x12\x18\n" +
+	"\agrouped\x18\x02 \x01(\tR\agrouped\";\n" +
+	"\x19ValidateExpressionRequest\x12\x1e\n" +
+	"\n" +
+	"expression\x18\x01 \x01(\tR\n" +
+	"expression\"\x9a\x01\n" +
+	"\x1aValidateExpressionResponse\x12\x1e\n" +
+	"\n" +
+	"expression\x18\x01 \x01(\tR\n" +
+	"expression\x12\x14\n" +
+	"\x05valid\x18\x02 \x01(\bR\x05valid\x12!\n" +
+	"\ferror_offset\x18\x03 \x01(\x05R\verrorOffset\x12#\n" +
+	"\rerror_message\x18\x04 \x01(\tR\ferrorMessage\"\x15\n" +
+	"\x13GetPoolStatsRequest\"\xd1\x01\n" +
+	"\n" +
+	"AgentStats\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12!\n" +
+	"\fcurrent_load\x18\x03 \x01(\x05R\vcurrentLoad\x12!\n" +
+	"\fmax_capacity\x18\x04 \x01(\x05R\vmaxCapacity\x12\x1c\n" +
+	"\tcompleted\x18\x05 \x01(\x03R\tcompleted\x12\x16\n" +
+	"\x06failed\x18\x06 \x01(\x03R\x06failed\x12\x1f\n" +
+	"\vqueue_depth\x18\a \x01(\x05R\n" +
+	"queueDepth\"\xac\x03\n" +
+	"\x14GetPoolStatsResponse\x123\n" +
+	"\x06agents\x18\x01 \x03(\v2\x1b.orchestrator.v1.AgentStatsR\x06agents\x12!\n" +
+	"\ftotal_agents\x18\x02 \x01(\x05R\vtotalAgents\x12#\n" +
+	"\ronline_agents\x18\x03 \x01(\x05R\fonlineAgents\x12\x1f\n" +
+	"\vbusy_agents\x18\x04 \x01(\x05R\n" +
+	"busyAgents\x12\x1d\n" +
+	"\n" +
+	"total_load\x18\x05 \x01(\x05R\ttotalLoad\x12%\n" +
+	"\x0etotal_capacity\x18\x06 \x01(\x05R\rtotalCapacity\x12\x1c\n" +
+	"\tcompleted\x18\a \x01(\x03R\tcompleted\x12\x16\n" +
+	"\x06failed\x18\b \x01(\x03R\x06failed\x12\x1f\n" +
+	"\vqueue_depth\x18\t \x01(\x05R\n" +
+	"queueDepth\x12?\n" +
+	"\n" +
+	"throughput\x18\n" +
+	" \x01(\v2\x1f.orchestrator.v1.PoolThroughputR\n" +
+	"throughput\x12\x18\n" +
+	"\aworkers\x18\v \x01(\x05R\aworkers\"\x19\n" +
+	"\x17GetTimingProfileRequest\"\xea\x01\n" +
+	"\vAgentTiming\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12$\n" +
+	"\rdeterministic\x18\x02 \x01(\bR\rdeterministic\x12`\n" +
+	"\x12operation_times_ms\x18\x03 \x03(\v22.orchestrator.v1.AgentTiming.OperationTimesMsEntryR\x10operationTimesMs\x1aC\n" +
+	"\x15OperationTimesMsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"\xf3\x01\n" +
+	"\x18GetTimingProfileResponse\x12`\n" +
+	"\rconfigured_ms\x18\x01 \x03(\v2;.orchestrator.v1.GetTimingProfileResponse.ConfiguredMsEntryR\fconfiguredMs\x124\n" +
+	"\x06agents\x18\x02 \x03(\v2\x1c.orchestrator.v1.AgentTimingR\x06agents\x1a?\n" +
+	"\x11ConfiguredMsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"P\n" +
+	"\x17SetAgentCapacityRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x1a\n" +
+	"\bcapacity\x18\x02 \x01(\x05R\bcapacity\"M\n" +
+	"\x18SetAgentCapacityResponse\x121\n" +
+	"\x05agent\x18\x01 \x01(\v2\x1b.orchestrator.v1.AgentStatsR\x05agent\"f\n" +
+	"\x0ePoolThroughput\x12\x1e\n" +
+	"\n" +
+	"dispatched\x18\x01 \x01(\x03R\n" +
+	"dispatched\x12\x1c\n" +
+	"\tcompleted\x18\x02 \x01(\x03R\tcompleted\x12\x16\n" +
+	"\x06failed\x18\x03 \x01(\x03R\x06failed\"\x17\n" +
+	"\x15GetSystemStatsRequest\"\xe6\x01\n" +
+	"\vDBPoolStats\x12\x1f\n" +
+	"\vtotal_conns\x18\x01 \x01(\x05R\n" +
+	"totalConns\x12\x1d\n" +
+	"\n" +
+	"idle_conns\x18\x02 \x01(\x05R\tidleConns\x12%\n" +
+	"\x0eacquired_conns\x18\x03 \x01(\x05R\racquiredConns\x12\x1b\n" +
+	"\tmax_conns\x18\x04 \x01(\x05R\bmaxConns\x12#\n" +
+	"\racquire_count\x18\x05 \x01(\x03R\facquireCount\x12.\n" +
+	"\x13acquire_duration_ms\x18\x06 \x01(\x03R\x11acquireDurationMs\"\xb7\x03\n" +
+	"\x16GetSystemStatsResponse\x12w\n" +
+	"\x16calculations_by_status\x18\x01 \x03(\v2A.orchestrator.v1.GetSystemStatsResponse.CalculationsByStatusEntryR\x14calculationsByStatus\x12-\n" +
+	"\x12total_calculations\x18\x02 \x01(\x03R\x11totalCalculations\x12!\n" +
+	"\ftotal_agents\x18\x03 \x01(\x05R\vtotalAgents\x12#\n" +
+	"\ractive_agents\x18\x04 \x01(\x05R\factiveAgents\x12-\n" +
+	"\x12pending_operations\x18\x05 \x01(\x03R\x11pendingOperations\x125\n" +
+	"\adb_pool\x18\x06 \x01(\v2\x1c.orchestrator.v1.DBPoolStatsR\x06dbPool\x1aG\n" +
+	"\x19CalculationsByStatusEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"\x14\n" +
+	"\x12GetLogLevelRequest\"*\n" +
+	"\x12SetLogLevelRequest\x12\x14\n" +
+	"\x05level\x18\x01 \x01(\tR\x05level\"(\n" +
+	"\x10LogLevelResponse\x12\x14\n" +
+	"\x05level\x18\x01 \x01(\tR\x05level*Z\n" +
 	"\x11CalculationStatus\x12\v\n" +
 	"\aPENDING\x10\x00\x12\x0f\n" +
 	"\vIN_PROGRESS\x10\x01\x12\r\n" +
 	"\tCOMPLETED\x10\x02\x12\t\n" +
-	"\x05ERROR\x10\x03*q\n" +
+	"\x05ERROR\x10\x03\x12\r\n" +
+	"\tCANCELLED\x10\x04*\x8a\x01\n" +
 	"\x0fOperationStatus\x12\x15\n" +
 	"\x11OPERATION_PENDING\x10\x00\x12\x19\n" +
 	"\x15OPERATION_IN_PROGRESS\x10\x01\x12\x17\n" +
 	"\x13OPERATION_COMPLETED\x10\x02\x12\x13\n" +
-	"\x0fOPERATION_ERROR\x10\x03*z\n" +
+	"\x0fOPERATION_ERROR\x10\x03\x12\x17\n" +
+	"\x13OPERATION_CANCELLED\x10\x04*\x9f\x01\n" +
 	"\rOperationType\x12\x14\n" +
 	"\x10TYPE_UNSPECIFIED\x10\x00\x12\x11\n" +
 	"\rTYPE_ADDITION\x10\x01\x12\x14\n" +
 	"\x10TYPE_SUBTRACTION\x10\x02\x12\x17\n" +
 	"\x13TYPE_MULTIPLICATION\x10\x03\x12\x11\n" +
-	"\rTYPE_DIVISION\x10\x042\x83\x03\n" +
+	"\rTYPE_DIVISION\x10\x04\x12\x0f\n" +
+	"\vTYPE_MODULO\x10\x05\x12\x12\n" +
+	"\x0eTYPE_FACTORIAL\x10\x062\xa7\x15\n" +
 	"\x13OrchestratorService\x12p\n" +
 	"\tCalculate\x12!.orchestrator.v1.CalculateRequest\x1a\".orchestrator.v1.CalculateResponse\"\x1c\x82\xd3\xe4\x93\x02\x16:\x01*\"\x11/api/v1/calculate\x12\x84\x01\n" +
-	"\x0eGetCalculation\x12&.orchestrator.v1.GetCalculationRequest\x1a'.orchestrator.v1.GetCalculationResponse\"!\x82\xd3\xe4\x93\x02\x1b\x12\x19/api/v1/calculations/{id}\x12s\n" +
-	"\x10ListCalculations\x12\x16.google.protobuf.Empty\x1a).orchestrator.v1.ListCalculationsResponse\"\x1c\x82\xd3\xe4\x93\x02\x16\x12\x14/api/v1/calculationsBWZUgithub.com/flexer2006/y.lms-final-task-calc-go/pkg/api/orchestrator/v1;orchestratorv1b\x06proto3"
+	"\x0eGetCalculation\x12&.orchestrator.v1.GetCalculationRequest\x1a'.orchestrator.v1.GetCalculationResponse\"!\x82\xd3\xe4\x93\x02\x1b\x12\x19/api/v1/calculations/{id}\x12|\n" +
+	"\fGetOperation\x12$.orchestrator.v1.GetOperationRequest\x1a%.orchestrator.v1.GetOperationResponse\"\x1f\x82\xd3\xe4\x93\x02\x19\x12\x17/api/v1/operations/{id}\x12\x8d\x01\n" +
+	"\x11StreamCalculation\x12).orchestrator.v1.StreamCalculationRequest\x1a!.orchestrator.v1.CalculationEvent\"(\x82\xd3\xe4\x93\x02\"\x12 /api/v1/calculations/{id}/stream0\x01\x12\x94\x01\n" +
+	"\x11CancelCalculation\x12).orchestrator.v1.CancelCalculationRequest\x1a*.orchestrator.v1.CancelCalculationResponse\"(\x82\xd3\xe4\x93\x02\"\" /api/v1/calculations/{id}/cancel\x12\x8d\x01\n" +
+	"\x11DeleteCalculation\x12).orchestrator.v1.DeleteCalculationRequest\x1a*.orchestrator.v1.DeleteCalculationResponse\"!\x82\xd3\xe4\x93\x02\x1b*\x19/api/v1/calculations/{id}\x12\x85\x01\n" +
+	"\x10ListCalculations\x12(.orchestrator.v1.ListCalculationsRequest\x1a).orchestrator.v1.ListCalculationsResponse\"\x1c\x82\xd3\xe4\x93\x02\x16\x12\x14/api/v1/calculations\x12o\n" +
+	"\x16ListCalculationsStream\x12(.orchestrator.v1.ListCalculationsRequest\x1a).orchestrator.v1.ListCalculationsResponse0\x01\x12\x8d\x01\n" +
+	"\x0eGetResultStats\x12&.orchestrator.v1.GetResultStatsRequest\x1a'.orchestrator.v1.GetResultStatsResponse\"*\x82\xd3\xe4\x93\x02$\x12\"/api/v1/calculations/results/stats\x12\x96\x01\n" +
+	"\x12CompareExpressions\x12*.orchestrator.v1.CompareExpressionsRequest\x1a+.orchestrator.v1.CompareExpressionsResponse\"'\x82\xd3\xe4\x93\x02!:\x01*\"\x1c/api/v1/calculations/compare\x12\x9a\x01\n" +
+	"\x10DiffCalculations\x12(.orchestrator.v1.DiffCalculationsRequest\x1a).orchestrator.v1.DiffCalculationsResponse\"1\x82\xd3\xe4\x93\x02+\x12)/api/v1/calculations/{id}/diff/{other_id}\x12\x93\x01\n" +
+	"\x11PreviewExpression\x12).orchestrator.v1.PreviewExpressionRequest\x1a*.orchestrator.v1.PreviewExpressionResponse\"'\x82\xd3\xe4\x93\x02!:\x01*\"\x1c/api/v1/calculations/preview\x12\x97\x01\n" +
+	"\x12ValidateExpression\x12*.orchestrator.v1.ValidateExpressionRequest\x1a+.orchestrator.v1.ValidateExpressionResponse\"(\x82\xd3\xe4\x93\x02\":\x01*\"\x1d/api/v1/calculations/validate\x12\x7f\n" +
+	"\fGetPoolStats\x12$.orchestrator.v1.GetPoolStatsRequest\x1a%.orchestrator.v1.GetPoolStatsResponse\"\"\x82\xd3\xe4\x93\x02\x1c\x12\x1a/api/v1/admin/agents/stats\x12\x8d\x01\n" +
+	"\x10GetTimingProfile\x12(.orchestrator.v1.GetTimingProfileRequest\x1a).orchestrator.v1.GetTimingProfileResponse\"$\x82\xd3\xe4\x93\x02\x1e\x12\x1c/api/v1/admin/agents/timings\x12\x9c\x01\n" +
+	"\x10SetAgentCapacity\x12(.orchestrator.v1.SetAgentCapacityRequest\x1a).orchestrator.v1.SetAgentCapacityResponse\"3\x82\xd3\xe4\x93\x02-:\x01*\x1a(/api/v1/admin/agents/{agent_id}/capacity\x12~\n" +
+	"\x0eGetSystemStats\x12&.orchestrator.v1.GetSystemStatsRequest\x1a'.orchestrator.v1.GetSystemStatsResponse\"\x1b\x82\xd3\xe4\x93\x02\x15\x12\x13/api/v1/admin/stats\x12\x94\x01\n" +
+	"\x13AdminGetCalculation\x12+.orchestrator.v1.AdminGetCalculationRequest\x1a'.orchestrator.v1.GetCalculationResponse\"'\x82\xd3\xe4\x93\x02!\x12\x1f/api/v1/admin/calculations/{id}\x12U\n" +
+	"\vGetLogLevel\x12#.orchestrator.v1.GetLogLevelRequest\x1a!.orchestrator.v1.LogLevelResponse\x12U\n" +
+	"\vSetLogLevel\x12#.orchestrator.v1.SetLogLevelRequest\x1a!.orchestrator.v1.LogLevelResponseBWZUgithub.com/flexer2006/y.lms-final-task-calc-go/pkg/api/orchestrator/v1;orchestratorv1b\x06proto3"
 
 var (
 	file_proto_v1_orchestrator_orchestrator_proto_rawDescOnce sync.Once
@@ -584,36 +3177,118 @@ func file_proto_v1_orchestrator_orchestrator_proto_rawDescGZIP() []byte {
 }
 
 var file_proto_v1_orchestrator_orchestrator_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
-var file_proto_v1_orchestrator_orchestrator_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_proto_v1_orchestrator_orchestrator_proto_msgTypes = make([]protoimpl.MessageInfo, 43)
 var file_proto_v1_orchestrator_orchestrator_proto_goTypes = []any{
-	(CalculationStatus)(0),           // 0: orchestrator.v1.CalculationStatus
-	(OperationStatus)(0),             // 1: orchestrator.v1.OperationStatus
-	(OperationType)(0),               // 2: orchestrator.v1.OperationType
-	(*CalculateRequest)(nil),         // 3: orchestrator.v1.CalculateRequest
-	(*CalculateResponse)(nil),        // 4: orchestrator.v1.CalculateResponse
-	(*GetCalculationRequest)(nil),    // 5: orchestrator.v1.GetCalculationRequest
-	(*GetCalculationResponse)(nil),   // 6: orchestrator.v1.GetCalculationResponse
-	(*ListCalculationsResponse)(nil), // 7: orchestrator.v1.ListCalculationsResponse
-	(*timestamppb.Timestamp)(nil),    // 8: google.protobuf.Timestamp
-	(*emptypb.Empty)(nil),            // 9: google.protobuf.Empty
+	(CalculationStatus)(0),             // 0: orchestrator.v1.CalculationStatus
+	(OperationStatus)(0),               // 1: orchestrator.v1.OperationStatus
+	(OperationType)(0),                 // 2: orchestrator.v1.OperationType
+	(*CalculateRequest)(nil),           // 3: orchestrator.v1.CalculateRequest
+	(*CalculateResponse)(nil),          // 4: orchestrator.v1.CalculateResponse
+	(*GetCalculationRequest)(nil),      // 5: orchestrator.v1.GetCalculationRequest
+	(*GetCalculationResponse)(nil),     // 6: orchestrator.v1.GetCalculationResponse
+	(*GetOperationRequest)(nil),        // 7: orchestrator.v1.GetOperationRequest
+	(*GetOperationResponse)(nil),       // 8: orchestrator.v1.GetOperationResponse
+	(*AdminGetCalculationRequest)(nil), // 9: orchestrator.v1.AdminGetCalculationRequest
+	(*StreamCalculationRequest)(nil),   // 10: orchestrator.v1.StreamCalculationRequest
+	(*CalculationEvent)(nil),           // 11: orchestrator.v1.CalculationEvent
+	(*CancelCalculationRequest)(nil),   // 12: orchestrator.v1.CancelCalculationRequest
+	(*CancelCalculationResponse)(nil),  // 13: orchestrator.v1.CancelCalculationResponse
+	(*DeleteCalculationRequest)(nil),   // 14: orchestrator.v1.DeleteCalculationRequest
+	(*DeleteCalculationResponse)(nil),  // 15: orchestrator.v1.DeleteCalculationResponse
+	(*ListCalculationsRequest)(nil),    // 16: orchestrator.v1.ListCalculationsRequest
+	(*ListCalculationsResponse)(nil),   // 17: orchestrator.v1.ListCalculationsResponse
+	(*GetResultStatsRequest)(nil),      // 18: orchestrator.v1.GetResultStatsRequest
+	(*GetResultStatsResponse)(nil),     // 19: orchestrator.v1.GetResultStatsResponse
+	(*CompareExpressionsRequest)(nil),  // 20: orchestrator.v1.CompareExpressionsRequest
+	(*CompareExpressionsResponse)(nil), // 21: orchestrator.v1.CompareExpressionsResponse
+	(*DiffCalculationsRequest)(nil),    // 22: orchestrator.v1.DiffCalculationsRequest
+	(*DiffCalculationsResponse)(nil),   // 23: orchestrator.v1.DiffCalculationsResponse
+	(*PreviewExpressionRequest)(nil),   // 24: orchestrator.v1.PreviewExpressionRequest
+	(*PreviewExpressionResponse)(nil),  // 25: orchestrator.v1.PreviewExpressionResponse
+	(*ValidateExpressionRequest)(nil),  // 26: orchestrator.v1.ValidateExpressionRequest
+	(*ValidateExpressionResponse)(nil), // 27: orchestrator.v1.ValidateExpressionResponse
+	(*GetPoolStatsRequest)(nil),        // 28: orchestrator.v1.GetPoolStatsRequest
+	(*AgentStats)(nil),                 // 29: orchestrator.v1.AgentStats
+	(*GetPoolStatsResponse)(nil),       // 30: orchestrator.v1.GetPoolStatsResponse
+	(*GetTimingProfileRequest)(nil),    // 31: orchestrator.v1.GetTimingProfileRequest
+	(*AgentTiming)(nil),                // 32: orchestrator.v1.AgentTiming
+	(*GetTimingProfileResponse)(nil),   // 33: orchestrator.v1.GetTimingProfileResponse
+	(*SetAgentCapacityRequest)(nil),    // 34: orchestrator.v1.SetAgentCapacityRequest
+	(*SetAgentCapacityResponse)(nil),   // 35: orchestrator.v1.SetAgentCapacityResponse
+	(*PoolThroughput)(nil),             // 36: orchestrator.v1.PoolThroughput
+	(*GetSystemStatsRequest)(nil),      // 37: orchestrator.v1.GetSystemStatsRequest
+	(*DBPoolStats)(nil),                // 38: orchestrator.v1.DBPoolStats
+	(*GetSystemStatsResponse)(nil),     // 39: orchestrator.v1.GetSystemStatsResponse
+	(*GetLogLevelRequest)(nil),         // 40: orchestrator.v1.GetLogLevelRequest
+	(*SetLogLevelRequest)(nil),         // 41: orchestrator.v1.SetLogLevelRequest
+	(*LogLevelResponse)(nil),           // 42: orchestrator.v1.LogLevelResponse
+	nil,                                // 43: orchestrator.v1.AgentTiming.OperationTimesMsEntry
+	nil,                                // 44: orchestrator.v1.GetTimingProfileResponse.ConfiguredMsEntry
+	nil,                                // 45: orchestrator.v1.GetSystemStatsResponse.CalculationsByStatusEntry
+	(*timestamppb.Timestamp)(nil),      // 46: google.protobuf.Timestamp
 }
 var file_proto_v1_orchestrator_orchestrator_proto_depIdxs = []int32{
-	0, // 0: orchestrator.v1.CalculateResponse.status:type_name -> orchestrator.v1.CalculationStatus
-	0, // 1: orchestrator.v1.GetCalculationResponse.status:type_name -> orchestrator.v1.CalculationStatus
-	8, // 2: orchestrator.v1.GetCalculationResponse.created_at:type_name -> google.protobuf.Timestamp
-	8, // 3: orchestrator.v1.GetCalculationResponse.updated_at:type_name -> google.protobuf.Timestamp
-	6, // 4: orchestrator.v1.ListCalculationsResponse.calculations:type_name -> orchestrator.v1.GetCalculationResponse
-	3, // 5: orchestrator.v1.OrchestratorService.Calculate:input_type -> orchestrator.v1.CalculateRequest
-	5, // 6: orchestrator.v1.OrchestratorService.GetCalculation:input_type -> orchestrator.v1.GetCalculationRequest
-	9, // 7: orchestrator.v1.OrchestratorService.ListCalculations:input_type -> google.protobuf.Empty
-	4, // 8: orchestrator.v1.OrchestratorService.Calculate:output_type -> orchestrator.v1.CalculateResponse
-	6, // 9: orchestrator.v1.OrchestratorService.GetCalculation:output_type -> orchestrator.v1.GetCalculationResponse
-	7, // 10: orchestrator.v1.OrchestratorService.ListCalculations:output_type -> orchestrator.v1.ListCalculationsResponse
-	8, // [8:11] is the sub-list for method output_type
-	5, // [5:8] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+	0,  // 0: orchestrator.v1.CalculateResponse.status:type_name -> orchestrator.v1.CalculationStatus
+	0,  // 1: orchestrator.v1.GetCalculationResponse.status:type_name -> orchestrator.v1.CalculationStatus
+	46, // 2: orchestrator.v1.GetCalculationResponse.created_at:type_name -> google.protobuf.Timestamp
+	46, // 3: orchestrator.v1.GetCalculationResponse.updated_at:type_name -> google.protobuf.Timestamp
+	0,  // 4: orchestrator.v1.CalculationEvent.status:type_name -> orchestrator.v1.CalculationStatus
+	46, // 5: orchestrator.v1.CalculationEvent.updated_at:type_name -> google.protobuf.Timestamp
+	0,  // 6: orchestrator.v1.CancelCalculationResponse.status:type_name -> orchestrator.v1.CalculationStatus
+	6,  // 7: orchestrator.v1.ListCalculationsResponse.calculations:type_name -> orchestrator.v1.GetCalculationResponse
+	29, // 8: orchestrator.v1.GetPoolStatsResponse.agents:type_name -> orchestrator.v1.AgentStats
+	36, // 9: orchestrator.v1.GetPoolStatsResponse.throughput:type_name -> orchestrator.v1.PoolThroughput
+	43, // 10: orchestrator.v1.AgentTiming.operation_times_ms:type_name -> orchestrator.v1.AgentTiming.OperationTimesMsEntry
+	44, // 11: orchestrator.v1.GetTimingProfileResponse.configured_ms:type_name -> orchestrator.v1.GetTimingProfileResponse.ConfiguredMsEntry
+	32, // 12: orchestrator.v1.GetTimingProfileResponse.agents:type_name -> orchestrator.v1.AgentTiming
+	29, // 13: orchestrator.v1.SetAgentCapacityResponse.agent:type_name -> orchestrator.v1.AgentStats
+	45, // 14: orchestrator.v1.GetSystemStatsResponse.calculations_by_status:type_name -> orchestrator.v1.GetSystemStatsResponse.CalculationsByStatusEntry
+	38, // 15: orchestrator.v1.GetSystemStatsResponse.db_pool:type_name -> orchestrator.v1.DBPoolStats
+	3,  // 16: orchestrator.v1.OrchestratorService.Calculate:input_type -> orchestrator.v1.CalculateRequest
+	5,  // 17: orchestrator.v1.OrchestratorService.GetCalculation:input_type -> orchestrator.v1.GetCalculationRequest
+	7,  // 18: orchestrator.v1.OrchestratorService.GetOperation:input_type -> orchestrator.v1.GetOperationRequest
+	10, // 19: orchestrator.v1.OrchestratorService.StreamCalculation:input_type -> orchestrator.v1.StreamCalculationRequest
+	12, // 20: orchestrator.v1.OrchestratorService.CancelCalculation:input_type -> orchestrator.v1.CancelCalculationRequest
+	14, // 21: orchestrator.v1.OrchestratorService.DeleteCalculation:input_type -> orchestrator.v1.DeleteCalculationRequest
+	16, // 22: orchestrator.v1.OrchestratorService.ListCalculations:input_type -> orchestrator.v1.ListCalculationsRequest
+	16, // 23: orchestrator.v1.OrchestratorService.ListCalculationsStream:input_type -> orchestrator.v1.ListCalculationsRequest
+	18, // 24: orchestrator.v1.OrchestratorService.GetResultStats:input_type -> orchestrator.v1.GetResultStatsRequest
+	20, // 25: orchestrator.v1.OrchestratorService.CompareExpressions:input_type -> orchestrator.v1.CompareExpressionsRequest
+	22, // 26: orchestrator.v1.OrchestratorService.DiffCalculations:input_type -> orchestrator.v1.DiffCalculationsRequest
+	24, // 27: orchestrator.v1.OrchestratorService.PreviewExpression:input_type -> orchestrator.v1.PreviewExpressionRequest
+	26, // 28: orchestrator.v1.OrchestratorService.ValidateExpression:input_type -> orchestrator.v1.ValidateExpressionRequest
+	28, // 29: orchestrator.v1.OrchestratorService.GetPoolStats:input_type -> orchestrator.v1.GetPoolStatsRequest
+	31, // 30: orchestrator.v1.OrchestratorService.GetTimingProfile:input_type -> orchestrator.v1.GetTimingProfileRequest
+	34, // 31: orchestrator.v1.OrchestratorService.SetAgentCapacity:input_type -> orchestrator.v1.SetAgentCapacityRequest
+	37, // 32: orchestrator.v1.OrchestratorService.GetSystemStats:input_type -> orchestrator.v1.GetSystemStatsRequest
+	9,  // 33: orchestrator.v1.OrchestratorService.AdminGetCalculation:input_type -> orchestrator.v1.AdminGetCalculationRequest
+	40, // 34: orchestrator.v1.OrchestratorService.GetLogLevel:input_type -> orchestrator.v1.GetLogLevelRequest
+	41, // 35: orchestrator.v1.OrchestratorService.SetLogLevel:input_type -> orchestrator.v1.SetLogLevelRequest
+	4,  // 36: orchestrator.v1.OrchestratorService.Calculate:output_type -> orchestrator.v1.CalculateResponse
+	6,  // 37: orchestrator.v1.OrchestratorService.GetCalculation:output_type -> orchestrator.v1.GetCalculationResponse
+	8,  // 38: orchestrator.v1.OrchestratorService.GetOperation:output_type -> orchestrator.v1.GetOperationResponse
+	11, // 39: orchestrator.v1.OrchestratorService.StreamCalculation:output_type -> orchestrator.v1.CalculationEvent
+	13, // 40: orchestrator.v1.OrchestratorService.CancelCalculation:output_type -> orchestrator.v1.CancelCalculationResponse
+	15, // 41: orchestrator.v1.OrchestratorService.DeleteCalculation:output_type -> orchestrator.v1.DeleteCalculationResponse
+	17, // 42: orchestrator.v1.OrchestratorService.ListCalculations:output_type -> orchestrator.v1.ListCalculationsResponse
+	17, // 43: orchestrator.v1.OrchestratorService.ListCalculationsStream:output_type -> orchestrator.v1.ListCalculationsResponse
+	19, // 44: orchestrator.v1.OrchestratorService.GetResultStats:output_type -> orchestrator.v1.GetResultStatsResponse
+	21, // 45: orchestrator.v1.OrchestratorService.CompareExpressions:output_type -> orchestrator.v1.CompareExpressionsResponse
+	23, // 46: orchestrator.v1.OrchestratorService.DiffCalculations:output_type -> orchestrator.v1.DiffCalculationsResponse
+	25, // 47: orchestrator.v1.OrchestratorService.PreviewExpression:output_type -> orchestrator.v1.PreviewExpressionResponse
+	27, // 48: orchestrator.v1.OrchestratorService.ValidateExpression:output_type -> orchestrator.v1.ValidateExpressionResponse
+	30, // 49: orchestrator.v1.OrchestratorService.GetPoolStats:output_type -> orchestrator.v1.GetPoolStatsResponse
+	33, // 50: orchestrator.v1.OrchestratorService.GetTimingProfile:output_type -> orchestrator.v1.GetTimingProfileResponse
+	35, // 51: orchestrator.v1.OrchestratorService.SetAgentCapacity:output_type -> orchestrator.v1.SetAgentCapacityResponse
+	39, // 52: orchestrator.v1.OrchestratorService.GetSystemStats:output_type -> orchestrator.v1.GetSystemStatsResponse
+	6,  // 53: orchestrator.v1.OrchestratorService.AdminGetCalculation:output_type -> orchestrator.v1.GetCalculationResponse
+	42, // 54: orchestrator.v1.OrchestratorService.GetLogLevel:output_type -> orchestrator.v1.LogLevelResponse
+	42, // 55: orchestrator.v1.OrchestratorService.SetLogLevel:output_type -> orchestrator.v1.LogLevelResponse
+	36, // [36:56] is the sub-list for method output_type
+	16, // [16:36] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
 }
 
 func init() { file_proto_v1_orchestrator_orchestrator_proto_init() }
@@ -627,7 +3302,7 @@ func file_proto_v1_orchestrator_orchestrator_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_v1_orchestrator_orchestrator_proto_rawDesc), len(file_proto_v1_orchestrator_orchestrator_proto_rawDesc)),
 			NumEnums:      3,
-			NumMessages:   5,
+			NumMessages:   43,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -11,7 +11,6 @@ import (
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
-	emptypb "google.golang.org/protobuf/types/known/emptypb"
 )
 
 // This is a compile-time assertion to ensure that this generated file
@@ -20,9 +19,26 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	OrchestratorService_Calculate_FullMethodName        = "/orchestrator.v1.OrchestratorService/Calculate"
-	OrchestratorService_GetCalculation_FullMethodName   = "/orchestrator.v1.OrchestratorService/GetCalculation"
-	OrchestratorService_ListCalculations_FullMethodName = "/orchestrator.v1.OrchestratorService/ListCalculations"
+	OrchestratorService_Calculate_FullMethodName              = "/orchestrator.v1.OrchestratorService/Calculate"
+	OrchestratorService_GetCalculation_FullMethodName         = "/orchestrator.v1.OrchestratorService/GetCalculation"
+	OrchestratorService_GetOperation_FullMethodName           = "/orchestrator.v1.OrchestratorService/GetOperation"
+	OrchestratorService_StreamCalculation_FullMethodName      = "/orchestrator.v1.OrchestratorService/StreamCalculation"
+	OrchestratorService_CancelCalculation_FullMethodName      = "/orchestrator.v1.OrchestratorService/CancelCalculation"
+	OrchestratorService_DeleteCalculation_FullMethodName      = "/orchestrator.v1.OrchestratorService/DeleteCalculation"
+	OrchestratorService_ListCalculations_FullMethodName       = "/orchestrator.v1.OrchestratorService/ListCalculations"
+	OrchestratorService_ListCalculationsStream_FullMethodName = "/orchestrator.v1.OrchestratorService/ListCalculationsStream"
+	OrchestratorService_GetResultStats_FullMethodName         = "/orchestrator.v1.OrchestratorService/GetResultStats"
+	OrchestratorService_CompareExpressions_FullMethodName     = "/orchestrator.v1.OrchestratorService/CompareExpressions"
+	OrchestratorService_DiffCalculations_FullMethodName       = "/orchestrator.v1.OrchestratorService/DiffCalculations"
+	OrchestratorService_PreviewExpression_FullMethodName      = "/orchestrator.v1.OrchestratorService/PreviewExpression"
+	OrchestratorService_ValidateExpression_FullMethodName     = "/orchestrator.v1.OrchestratorService/ValidateExpression"
+	OrchestratorService_GetPoolStats_FullMethodName           = "/orchestrator.v1.OrchestratorService/GetPoolStats"
+	OrchestratorService_GetTimingProfile_FullMethodName       = "/orchestrator.v1.OrchestratorService/GetTimingProfile"
+	OrchestratorService_SetAgentCapacity_FullMethodName       = "/orchestrator.v1.OrchestratorService/SetAgentCapacity"
+	OrchestratorService_GetSystemStats_FullMethodName         = "/orchestrator.v1.OrchestratorService/GetSystemStats"
+	OrchestratorService_AdminGetCalculation_FullMethodName    = "/orchestrator.v1.OrchestratorService/AdminGetCalculation"
+	OrchestratorService_GetLogLevel_FullMethodName            = "/orchestrator.v1.OrchestratorService/GetLogLevel"
+	OrchestratorService_SetLogLevel_FullMethodName            = "/orchestrator.v1.OrchestratorService/SetLogLevel"
 )
 
 // OrchestratorServiceClient is the client API for OrchestratorService service.
@@ -35,8 +51,45 @@ type OrchestratorServiceClient interface {
 	Calculate(ctx context.Context, in *CalculateRequest, opts ...grpc.CallOption) (*CalculateResponse, error)
 	// Получение статуса вычисления по ID.
 	GetCalculation(ctx context.Context, in *GetCalculationRequest, opts ...grpc.CallOption) (*GetCalculationResponse, error)
-	// Получение списка всех вычислений пользователя.
-	ListCalculations(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListCalculationsResponse, error)
+	// Получение отдельной операции по ID для отладки. Доступно владельцу вычисления.
+	GetOperation(ctx context.Context, in *GetOperationRequest, opts ...grpc.CallOption) (*GetOperationResponse, error)
+	// Подписка на изменения статуса вычисления. Поток начинается с текущего состояния
+	// и завершается после перехода вычисления в конечный статус.
+	StreamCalculation(ctx context.Context, in *StreamCalculationRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CalculationEvent], error)
+	// Отмена вычисления и его незавершенных операций.
+	CancelCalculation(ctx context.Context, in *CancelCalculationRequest, opts ...grpc.CallOption) (*CancelCalculationResponse, error)
+	// Удаление вычисления вместе с его операциями.
+	DeleteCalculation(ctx context.Context, in *DeleteCalculationRequest, opts ...grpc.CallOption) (*DeleteCalculationResponse, error)
+	// Получение постраничного списка вычислений пользователя.
+	ListCalculations(ctx context.Context, in *ListCalculationsRequest, opts ...grpc.CallOption) (*ListCalculationsResponse, error)
+	// Список вычислений пользователя в потоке по страницам: limit задает размер страницы, offset -
+	// начало первой. Каждое сообщение потока содержит одну страницу; поток завершается после
+	// последней, поэтому ни сервер, ни клиент не держат весь список в памяти.
+	ListCalculationsStream(ctx context.Context, in *ListCalculationsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ListCalculationsResponse], error)
+	// Статистика по числовым результатам завершенных вычислений пользователя.
+	GetResultStats(ctx context.Context, in *GetResultStatsRequest, opts ...grpc.CallOption) (*GetResultStatsResponse, error)
+	// Сравнение значений двух выражений.
+	CompareExpressions(ctx context.Context, in *CompareExpressionsRequest, opts ...grpc.CallOption) (*CompareExpressionsResponse, error)
+	// Сравнение выражений и результатов двух сохраненных вычислений пользователя.
+	DiffCalculations(ctx context.Context, in *DiffCalculationsRequest, opts ...grpc.CallOption) (*DiffCalculationsResponse, error)
+	// Выражение с явными скобками, показывающими порядок выполнения операций.
+	PreviewExpression(ctx context.Context, in *PreviewExpressionRequest, opts ...grpc.CallOption) (*PreviewExpressionResponse, error)
+	// Проверка синтаксиса выражения без создания вычисления.
+	ValidateExpression(ctx context.Context, in *ValidateExpressionRequest, opts ...grpc.CallOption) (*ValidateExpressionResponse, error)
+	// Получение метрик пула агентов.
+	GetPoolStats(ctx context.Context, in *GetPoolStatsRequest, opts ...grpc.CallOption) (*GetPoolStatsResponse, error)
+	// Получение настроенного и фактически применяемого агентами времени выполнения операций.
+	GetTimingProfile(ctx context.Context, in *GetTimingProfileRequest, opts ...grpc.CallOption) (*GetTimingProfileResponse, error)
+	// Изменение емкости работающего агента администратором.
+	SetAgentCapacity(ctx context.Context, in *SetAgentCapacityRequest, opts ...grpc.CallOption) (*SetAgentCapacityResponse, error)
+	// Сводная статистика сервиса для администраторов.
+	GetSystemStats(ctx context.Context, in *GetSystemStatsRequest, opts ...grpc.CallOption) (*GetSystemStatsResponse, error)
+	// Получение любого вычисления, включая удаленные, с версией клиента для администраторов.
+	AdminGetCalculation(ctx context.Context, in *AdminGetCalculationRequest, opts ...grpc.CallOption) (*GetCalculationResponse, error)
+	// Текущий уровень журнала сервиса оркестрации для администраторов.
+	GetLogLevel(ctx context.Context, in *GetLogLevelRequest, opts ...grpc.CallOption) (*LogLevelResponse, error)
+	// Изменение уровня журнала сервиса оркестрации администратором без перезапуска.
+	SetLogLevel(ctx context.Context, in *SetLogLevelRequest, opts ...grpc.CallOption) (*LogLevelResponse, error)
 }
 
 type orchestratorServiceClient struct {
@@ -67,7 +120,56 @@ func (c *orchestratorServiceClient) GetCalculation(ctx context.Context, in *GetC
 	return out, nil
 }
 
-func (c *orchestratorServiceClient) ListCalculations(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListCalculationsResponse, error) {
+func (c *orchestratorServiceClient) GetOperation(ctx context.Context, in *GetOperationRequest, opts ...grpc.CallOption) (*GetOperationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetOperationResponse)
+	err := c.cc.Invoke(ctx, OrchestratorService_GetOperation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorServiceClient) StreamCalculation(ctx context.Context, in *StreamCalculationRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CalculationEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &OrchestratorService_ServiceDesc.Streams[0], OrchestratorService_StreamCalculation_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamCalculationRequest, CalculationEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OrchestratorService_StreamCalculationClient = grpc.ServerStreamingClient[CalculationEvent]
+
+func (c *orchestratorServiceClient) CancelCalculation(ctx context.Context, in *CancelCalculationRequest, opts ...grpc.CallOption) (*CancelCalculationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelCalculationResponse)
+	err := c.cc.Invoke(ctx, OrchestratorService_CancelCalculation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorServiceClient) DeleteCalculation(ctx context.Context, in *DeleteCalculationRequest, opts ...grpc.CallOption) (*DeleteCalculationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteCalculationResponse)
+	err := c.cc.Invoke(ctx, OrchestratorService_DeleteCalculation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorServiceClient) ListCalculations(ctx context.Context, in *ListCalculationsRequest, opts ...grpc.CallOption) (*ListCalculationsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ListCalculationsResponse)
 	err := c.cc.Invoke(ctx, OrchestratorService_ListCalculations_FullMethodName, in, out, cOpts...)
@@ -77,6 +179,145 @@ func (c *orchestratorServiceClient) ListCalculations(ctx context.Context, in *em
 	return out, nil
 }
 
+func (c *orchestratorServiceClient) ListCalculationsStream(ctx context.Context, in *ListCalculationsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ListCalculationsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &OrchestratorService_ServiceDesc.Streams[1], OrchestratorService_ListCalculationsStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListCalculationsRequest, ListCalculationsResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OrchestratorService_ListCalculationsStreamClient = grpc.ServerStreamingClient[ListCalculationsResponse]
+
+func (c *orchestratorServiceClient) GetResultStats(ctx context.Context, in *GetResultStatsRequest, opts ...grpc.CallOption) (*GetResultStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetResultStatsResponse)
+	err := c.cc.Invoke(ctx, OrchestratorService_GetResultStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorServiceClient) CompareExpressions(ctx context.Context, in *CompareExpressionsRequest, opts ...grpc.CallOption) (*CompareExpressionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CompareExpressionsResponse)
+	err := c.cc.Invoke(ctx, OrchestratorService_CompareExpressions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorServiceClient) DiffCalculations(ctx context.Context, in *DiffCalculationsRequest, opts ...grpc.CallOption) (*DiffCalculationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DiffCalculationsResponse)
+	err := c.cc.Invoke(ctx, OrchestratorService_DiffCalculations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorServiceClient) PreviewExpression(ctx context.Context, in *PreviewExpressionRequest, opts ...grpc.CallOption) (*PreviewExpressionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PreviewExpressionResponse)
+	err := c.cc.Invoke(ctx, OrchestratorService_PreviewExpression_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorServiceClient) ValidateExpression(ctx context.Context, in *ValidateExpressionRequest, opts ...grpc.CallOption) (*ValidateExpressionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ValidateExpressionResponse)
+	err := c.cc.Invoke(ctx, OrchestratorService_ValidateExpression_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorServiceClient) GetPoolStats(ctx context.Context, in *GetPoolStatsRequest, opts ...grpc.CallOption) (*GetPoolStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetPoolStatsResponse)
+	err := c.cc.Invoke(ctx, OrchestratorService_GetPoolStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorServiceClient) GetTimingProfile(ctx context.Context, in *GetTimingProfileRequest, opts ...grpc.CallOption) (*GetTimingProfileResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTimingProfileResponse)
+	err := c.cc.Invoke(ctx, OrchestratorService_GetTimingProfile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorServiceClient) SetAgentCapacity(ctx context.Context, in *SetAgentCapacityRequest, opts ...grpc.CallOption) (*SetAgentCapacityResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetAgentCapacityResponse)
+	err := c.cc.Invoke(ctx, OrchestratorService_SetAgentCapacity_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorServiceClient) GetSystemStats(ctx context.Context, in *GetSystemStatsRequest, opts ...grpc.CallOption) (*GetSystemStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSystemStatsResponse)
+	err := c.cc.Invoke(ctx, OrchestratorService_GetSystemStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorServiceClient) AdminGetCalculation(ctx context.Context, in *AdminGetCalculationRequest, opts ...grpc.CallOption) (*GetCalculationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCalculationResponse)
+	err := c.cc.Invoke(ctx, OrchestratorService_AdminGetCalculation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorServiceClient) GetLogLevel(ctx context.Context, in *GetLogLevelRequest, opts ...grpc.CallOption) (*LogLevelResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LogLevelResponse)
+	err := c.cc.Invoke(ctx, OrchestratorService_GetLogLevel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorServiceClient) SetLogLevel(ctx context.Context, in *SetLogLevelRequest, opts ...grpc.CallOption) (*LogLevelResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LogLevelResponse)
+	err := c.cc.Invoke(ctx, OrchestratorService_SetLogLevel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // OrchestratorServiceServer is the server API for OrchestratorService service.
 // All implementations must embed UnimplementedOrchestratorServiceServer
 // for forward compatibility.
@@ -87,8 +328,45 @@ type OrchestratorServiceServer interface {
 	Calculate(context.Context, *CalculateRequest) (*CalculateResponse, error)
 	// Получение статуса вычисления по ID.
 	GetCalculation(context.Context, *GetCalculationRequest) (*GetCalculationResponse, error)
-	// Получение списка всех вычислений пользователя.
-	ListCalculations(context.Context, *emptypb.Empty) (*ListCalculationsResponse, error)
+	// Получение отдельной операции по ID для отладки. Доступно владельцу вычисления.
+	GetOperation(context.Context, *GetOperationRequest) (*GetOperationResponse, error)
+	// Подписка на изменения статуса вычисления. Поток начинается с текущего состояния
+	// и завершается после перехода вычисления в конечный статус.
+	StreamCalculation(*StreamCalculationRequest, grpc.ServerStreamingServer[CalculationEvent]) error
+	// Отмена вычисления и его незавершенных операций.
+	CancelCalculation(context.Context, *CancelCalculationRequest) (*CancelCalculationResponse, error)
+	// Удаление вычисления вместе с его операциями.
+	DeleteCalculation(context.Context, *DeleteCalculationRequest) (*DeleteCalculationResponse, error)
+	// Получение постраничного списка вычислений пользователя.
+	ListCalculations(context.Context, *ListCalculationsRequest) (*ListCalculationsResponse, error)
+	// Список вычислений пользователя в потоке по страницам: limit задает размер страницы, offset -
+	// начало первой. Каждое сообщение потока содержит одну страницу; поток завершается после
+	// последней, поэтому ни сервер, ни клиент не держат весь список в памяти.
+	ListCalculationsStream(*ListCalculationsRequest, grpc.ServerStreamingServer[ListCalculationsResponse]) error
+	// Статистика по числовым результатам завершенных вычислений пользователя.
+	GetResultStats(context.Context, *GetResultStatsRequest) (*GetResultStatsResponse, error)
+	// Сравнение значений двух выражений.
+	CompareExpressions(context.Context, *CompareExpressionsRequest) (*CompareExpressionsResponse, error)
+	// Сравнение выражений и результатов двух сохраненных вычислений пользователя.
+	DiffCalculations(context.Context, *DiffCalculationsRequest) (*DiffCalculationsResponse, error)
+	// Выражение с явными скобками, показывающими порядок выполнения операций.
+	PreviewExpression(context.Context, *PreviewExpressionRequest) (*PreviewExpressionResponse, error)
+	// Проверка синтаксиса выражения без создания вычисления.
+	ValidateExpression(context.Context, *ValidateExpressionRequest) (*ValidateExpressionResponse, error)
+	// Получение метрик пула агентов.
+	GetPoolStats(context.Context, *GetPoolStatsRequest) (*GetPoolStatsResponse, error)
+	// Получение настроенного и фактически применяемого агентами времени выполнения операций.
+	GetTimingProfile(context.Context, *GetTimingProfileRequest) (*GetTimingProfileResponse, error)
+	// Изменение емкости работающего агента администратором.
+	SetAgentCapacity(context.Context, *SetAgentCapacityRequest) (*SetAgentCapacityResponse, error)
+	// Сводная статистика сервиса для администраторов.
+	GetSystemStats(context.Context, *GetSystemStatsRequest) (*GetSystemStatsResponse, error)
+	// Получение любого вычисления, включая удаленные, с версией клиента для администраторов.
+	AdminGetCalculation(context.Context, *AdminGetCalculationRequest) (*GetCalculationResponse, error)
+	// Текущий уровень журнала сервиса оркестрации для администраторов.
+	GetLogLevel(context.Context, *GetLogLevelRequest) (*LogLevelResponse, error)
+	// Изменение уровня журнала сервиса оркестрации администратором без перезапуска.
+	SetLogLevel(context.Context, *SetLogLevelRequest) (*LogLevelResponse, error)
 	mustEmbedUnimplementedOrchestratorServiceServer()
 }
 
@@ -105,9 +383,60 @@ func (UnimplementedOrchestratorServiceServer) Calculate(context.Context, *Calcul
 func (UnimplementedOrchestratorServiceServer) GetCalculation(context.Context, *GetCalculationRequest) (*GetCalculationResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetCalculation not implemented")
 }
-func (UnimplementedOrchestratorServiceServer) ListCalculations(context.Context, *emptypb.Empty) (*ListCalculationsResponse, error) {
+func (UnimplementedOrchestratorServiceServer) GetOperation(context.Context, *GetOperationRequest) (*GetOperationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOperation not implemented")
+}
+func (UnimplementedOrchestratorServiceServer) StreamCalculation(*StreamCalculationRequest, grpc.ServerStreamingServer[CalculationEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamCalculation not implemented")
+}
+func (UnimplementedOrchestratorServiceServer) CancelCalculation(context.Context, *CancelCalculationRequest) (*CancelCalculationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelCalculation not implemented")
+}
+func (UnimplementedOrchestratorServiceServer) DeleteCalculation(context.Context, *DeleteCalculationRequest) (*DeleteCalculationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteCalculation not implemented")
+}
+func (UnimplementedOrchestratorServiceServer) ListCalculations(context.Context, *ListCalculationsRequest) (*ListCalculationsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListCalculations not implemented")
 }
+func (UnimplementedOrchestratorServiceServer) ListCalculationsStream(*ListCalculationsRequest, grpc.ServerStreamingServer[ListCalculationsResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ListCalculationsStream not implemented")
+}
+func (UnimplementedOrchestratorServiceServer) GetResultStats(context.Context, *GetResultStatsRequest) (*GetResultStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetResultStats not implemented")
+}
+func (UnimplementedOrchestratorServiceServer) CompareExpressions(context.Context, *CompareExpressionsRequest) (*CompareExpressionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompareExpressions not implemented")
+}
+func (UnimplementedOrchestratorServiceServer) DiffCalculations(context.Context, *DiffCalculationsRequest) (*DiffCalculationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiffCalculations not implemented")
+}
+func (UnimplementedOrchestratorServiceServer) PreviewExpression(context.Context, *PreviewExpressionRequest) (*PreviewExpressionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PreviewExpression not implemented")
+}
+func (UnimplementedOrchestratorServiceServer) ValidateExpression(context.Context, *ValidateExpressionRequest) (*ValidateExpressionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateExpression not implemented")
+}
+func (UnimplementedOrchestratorServiceServer) GetPoolStats(context.Context, *GetPoolStatsRequest) (*GetPoolStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPoolStats not implemented")
+}
+func (UnimplementedOrchestratorServiceServer) GetTimingProfile(context.Context, *GetTimingProfileRequest) (*GetTimingProfileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTimingProfile not implemented")
+}
+func (UnimplementedOrchestratorServiceServer) SetAgentCapacity(context.Context, *SetAgentCapacityRequest) (*SetAgentCapacityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetAgentCapacity not implemented")
+}
+func (UnimplementedOrchestratorServiceServer) GetSystemStats(context.Context, *GetSystemStatsRequest) (*GetSystemStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSystemStats not implemented")
+}
+func (UnimplementedOrchestratorServiceServer) AdminGetCalculation(context.Context, *AdminGetCalculationRequest) (*GetCalculationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdminGetCalculation not implemented")
+}
+func (UnimplementedOrchestratorServiceServer) GetLogLevel(context.Context, *GetLogLevelRequest) (*LogLevelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLogLevel not implemented")
+}
+func (UnimplementedOrchestratorServiceServer) SetLogLevel(context.Context, *SetLogLevelRequest) (*LogLevelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLogLevel not implemented")
+}
 func (UnimplementedOrchestratorServiceServer) mustEmbedUnimplementedOrchestratorServiceServer() {}
 func (UnimplementedOrchestratorServiceServer) testEmbeddedByValue()                             {}
 
@@ -165,8 +494,73 @@ func _OrchestratorService_GetCalculation_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OrchestratorService_GetOperation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOperationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).GetOperation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorService_GetOperation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).GetOperation(ctx, req.(*GetOperationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorService_StreamCalculation_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamCalculationRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OrchestratorServiceServer).StreamCalculation(m, &grpc.GenericServerStream[StreamCalculationRequest, CalculationEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OrchestratorService_StreamCalculationServer = grpc.ServerStreamingServer[CalculationEvent]
+
+func _OrchestratorService_CancelCalculation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelCalculationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).CancelCalculation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorService_CancelCalculation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).CancelCalculation(ctx, req.(*CancelCalculationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorService_DeleteCalculation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteCalculationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).DeleteCalculation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorService_DeleteCalculation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).DeleteCalculation(ctx, req.(*DeleteCalculationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _OrchestratorService_ListCalculations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(emptypb.Empty)
+	in := new(ListCalculationsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -178,7 +572,234 @@ func _OrchestratorService_ListCalculations_Handler(srv interface{}, ctx context.
 		FullMethod: OrchestratorService_ListCalculations_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(OrchestratorServiceServer).ListCalculations(ctx, req.(*emptypb.Empty))
+		return srv.(OrchestratorServiceServer).ListCalculations(ctx, req.(*ListCalculationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorService_ListCalculationsStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListCalculationsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OrchestratorServiceServer).ListCalculationsStream(m, &grpc.GenericServerStream[ListCalculationsRequest, ListCalculationsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OrchestratorService_ListCalculationsStreamServer = grpc.ServerStreamingServer[ListCalculationsResponse]
+
+func _OrchestratorService_GetResultStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetResultStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).GetResultStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorService_GetResultStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).GetResultStats(ctx, req.(*GetResultStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorService_CompareExpressions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompareExpressionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).CompareExpressions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorService_CompareExpressions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).CompareExpressions(ctx, req.(*CompareExpressionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorService_DiffCalculations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiffCalculationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).DiffCalculations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorService_DiffCalculations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).DiffCalculations(ctx, req.(*DiffCalculationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorService_PreviewExpression_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PreviewExpressionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).PreviewExpression(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorService_PreviewExpression_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).PreviewExpression(ctx, req.(*PreviewExpressionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorService_ValidateExpression_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateExpressionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).ValidateExpression(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorService_ValidateExpression_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).ValidateExpression(ctx, req.(*ValidateExpressionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorService_GetPoolStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPoolStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).GetPoolStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorService_GetPoolStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).GetPoolStats(ctx, req.(*GetPoolStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorService_GetTimingProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTimingProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).GetTimingProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorService_GetTimingProfile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).GetTimingProfile(ctx, req.(*GetTimingProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorService_SetAgentCapacity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetAgentCapacityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).SetAgentCapacity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorService_SetAgentCapacity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).SetAgentCapacity(ctx, req.(*SetAgentCapacityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorService_GetSystemStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSystemStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).GetSystemStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorService_GetSystemStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).GetSystemStats(ctx, req.(*GetSystemStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorService_AdminGetCalculation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminGetCalculationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).AdminGetCalculation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorService_AdminGetCalculation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).AdminGetCalculation(ctx, req.(*AdminGetCalculationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorService_GetLogLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLogLevelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).GetLogLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorService_GetLogLevel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).GetLogLevel(ctx, req.(*GetLogLevelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorService_SetLogLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetLogLevelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).SetLogLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorService_SetLogLevel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).SetLogLevel(ctx, req.(*SetLogLevelRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -198,11 +819,82 @@ var OrchestratorService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetCalculation",
 			Handler:    _OrchestratorService_GetCalculation_Handler,
 		},
+		{
+			MethodName: "GetOperation",
+			Handler:    _OrchestratorService_GetOperation_Handler,
+		},
+		{
+			MethodName: "CancelCalculation",
+			Handler:    _OrchestratorService_CancelCalculation_Handler,
+		},
+		{
+			MethodName: "DeleteCalculation",
+			Handler:    _OrchestratorService_DeleteCalculation_Handler,
+		},
 		{
 			MethodName: "ListCalculations",
 			Handler:    _OrchestratorService_ListCalculations_Handler,
 		},
+		{
+			MethodName: "GetResultStats",
+			Handler:    _OrchestratorService_GetResultStats_Handler,
+		},
+		{
+			MethodName: "CompareExpressions",
+			Handler:    _OrchestratorService_CompareExpressions_Handler,
+		},
+		{
+			MethodName: "DiffCalculations",
+			Handler:    _OrchestratorService_DiffCalculations_Handler,
+		},
+		{
+			MethodName: "PreviewExpression",
+			Handler:    _OrchestratorService_PreviewExpression_Handler,
+		},
+		{
+			MethodName: "ValidateExpression",
+			Handler:    _OrchestratorService_ValidateExpression_Handler,
+		},
+		{
+			MethodName: "GetPoolStats",
+			Handler:    _OrchestratorService_GetPoolStats_Handler,
+		},
+		{
+			MethodName: "GetTimingProfile",
+			Handler:    _OrchestratorService_GetTimingProfile_Handler,
+		},
+		{
+			MethodName: "SetAgentCapacity",
+			Handler:    _OrchestratorService_SetAgentCapacity_Handler,
+		},
+		{
+			MethodName: "GetSystemStats",
+			Handler:    _OrchestratorService_GetSystemStats_Handler,
+		},
+		{
+			MethodName: "AdminGetCalculation",
+			Handler:    _OrchestratorService_AdminGetCalculation_Handler,
+		},
+		{
+			MethodName: "GetLogLevel",
+			Handler:    _OrchestratorService_GetLogLevel_Handler,
+		},
+		{
+			MethodName: "SetLogLevel",
+			Handler:    _OrchestratorService_SetLogLevel_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamCalculation",
+			Handler:       _OrchestratorService_StreamCalculation_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListCalculationsStream",
+			Handler:       _OrchestratorService_ListCalculationsStream_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/v1/orchestrator/orchestrator.proto",
 }
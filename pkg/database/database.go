@@ -5,6 +5,8 @@ package database
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
+	"time"
 
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/database/migrate"
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/database/postgres"
@@ -45,6 +47,8 @@ var (
 	ErrForceVersion              = migrate.ErrForceVersion
 	ErrStepMigrations            = migrate.ErrStepMigrations
 	ErrCloseMigrator             = migrate.ErrCloseMigrator
+	ErrSchemaNotReady            = migrate.ErrSchemaNotReady
+	ErrSchemaDirty               = migrate.ErrSchemaDirty
 )
 
 // NewPostgres создает новое соединение с базой данных PostgreSQL.
@@ -70,10 +74,23 @@ func NewMigrator() *Migrator {
 	return migrate.NewMigrator()
 }
 
+// connPool - пул соединений, из которого Handler берет соединения для записи или чтения.
+type connPool interface {
+	AcquireConn(ctx context.Context) (*pgxpool.Conn, error)
+	Close(ctx context.Context)
+}
+
 // Handler объединяет возможности соединения с базой данных и миграции в одной структуре.
+// Если заданы реплики для чтения, AcquireReadConn поочередно выдает соединения с них,
+// а AcquireConn и миграции всегда обращаются к основному серверу.
 type Handler struct {
 	DB       *Database
 	Migrator *Migrator
+
+	// primary, если задан, заменяет DB как пул для записи, что позволяет подставить его в тестах.
+	primary  connPool
+	replicas []connPool
+	next     atomic.Uint64
 }
 
 // NewHandler создает новый обработчик базы данных с соединением и мигратором.
@@ -106,6 +123,26 @@ func NewHandlerWithDSN(ctx context.Context, dsn string, minConn, maxConn int) (*
 	}, nil
 }
 
+// NewHandlerWithReplicas создает обработчик базы данных с основным сервером и репликами для чтения.
+// Без реплик обработчик ведет себя так же, как созданный NewHandler.
+func NewHandlerWithReplicas(ctx context.Context, dbConfig PostgresConfig, replicaConfigs []PostgresConfig, migrateConfig MigrateConfig) (*Handler, error) {
+	handler, err := NewHandler(ctx, dbConfig, migrateConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, replicaConfig := range replicaConfigs {
+		replica, err := NewPostgres(ctx, replicaConfig)
+		if err != nil {
+			handler.Close(ctx)
+			return nil, fmt.Errorf("connecting to read replica %d: %w", i, err)
+		}
+		handler.replicas = append(handler.replicas, replica)
+	}
+
+	return handler, nil
+}
+
 // MigrateUp применяет все доступные миграции.
 func (h *Handler) MigrateUp(ctx context.Context, migrateConfig MigrateConfig) error {
 	dsn := h.DB.GetDSN()
@@ -161,25 +198,108 @@ func (h *Handler) GetMigrationVersion(ctx context.Context, migrateConfig Migrate
 	return version, dirty, nil
 }
 
-// Close закрывает соединение с базой данных.
+// CheckSchemaVersion проверяет, что миграции базы данных применены хотя бы до версии expected.
+// Возвращает ErrSchemaNotReady, пока схема отстает, и ErrSchemaDirty после прерванной миграции.
+func (h *Handler) CheckSchemaVersion(ctx context.Context, migrateConfig MigrateConfig, expected uint) error {
+	version, dirty, err := h.GetMigrationVersion(ctx, migrateConfig)
+	if err != nil {
+		return err
+	}
+	return migrate.CheckVersion(version, dirty, expected)
+}
+
+// Close закрывает соединения с основным сервером и репликами.
 func (h *Handler) Close(ctx context.Context) {
-	h.DB.Close(ctx)
+	for _, replica := range h.replicas {
+		replica.Close(ctx)
+	}
+	h.writer().Close(ctx)
 }
 
-// AcquireConn получает соединение из пула.
+// AcquireConn получает соединение из пула основного сервера.
 func (h *Handler) AcquireConn(ctx context.Context) (*pgxpool.Conn, error) {
-	conn, err := h.DB.AcquireConn(ctx)
+	conn, err := h.writer().AcquireConn(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("acquiring connection from pool: %w", err)
 	}
 	return conn, nil
 }
 
+// AcquireReadConn получает соединение для запросов только на чтение. Реплики выбираются
+// по кругу; если реплик нет или выбранная недоступна, соединение берется с основного сервера.
+// Реплика может отставать от основного сервера, поэтому чтение сразу после записи
+// должно использовать AcquireConn.
+func (h *Handler) AcquireReadConn(ctx context.Context) (*pgxpool.Conn, error) {
+	if len(h.replicas) > 0 {
+		replica := h.replicas[(h.next.Add(1)-1)%uint64(len(h.replicas))]
+		if conn, err := replica.AcquireConn(ctx); err == nil {
+			return conn, nil
+		}
+	}
+	return h.AcquireConn(ctx)
+}
+
+// writer возвращает пул основного сервера.
+func (h *Handler) writer() connPool {
+	if h.primary != nil {
+		return h.primary
+	}
+	return h.DB
+}
+
 // Pool возвращает базовый пул соединений.
 func (h *Handler) Pool() *pgxpool.Pool {
 	return h.DB.Pool()
 }
 
+// PoolStats содержит состояние пула соединений основного сервера.
+type PoolStats struct {
+	// AcquiredConns - соединения, выданные в данный момент.
+	AcquiredConns int32
+	// IdleConns - простаивающие соединения.
+	IdleConns int32
+	// TotalConns - все открытые соединения, включая устанавливаемые.
+	TotalConns int32
+	// MaxConns - максимальный размер пула.
+	MaxConns int32
+	// AcquireCount - сколько раз соединение было успешно получено из пула.
+	AcquireCount int64
+	// AcquireDuration - суммарное время ожидания успешно полученных соединений.
+	AcquireDuration time.Duration
+}
+
+// Stats возвращает состояние пула соединений основного сервера.
+func (h *Handler) Stats(_ context.Context) (PoolStats, error) {
+	if h.DB == nil || h.Pool() == nil {
+		return PoolStats{}, ErrConnectionPoolNil
+	}
+	return statsFromPool(h.Pool()), nil
+}
+
+func statsFromPool(pool *pgxpool.Pool) PoolStats {
+	stat := pool.Stat()
+	return PoolStats{
+		AcquiredConns:   stat.AcquiredConns(),
+		IdleConns:       stat.IdleConns(),
+		TotalConns:      stat.TotalConns(),
+		MaxConns:        stat.MaxConns(),
+		AcquireCount:    stat.AcquireCount(),
+		AcquireDuration: stat.AcquireDuration(),
+	}
+}
+
+// WithStatementTimeout ограничивает ожидание запросов, выполняемых с возвращенным контекстом,
+// на стороне клиента: по истечении срока вызов возвращает ошибку, не дожидаясь ответа.
+// Сам запрос на сервере прерывает statement_timeout соединения, который задается
+// PostgresConfig.StatementTimeout. Более ранний срок вызывающего сохраняется,
+// а нулевой или отрицательный timeout оставляет контекст без изменений.
+func WithStatementTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
 // Ping проверяет доступность базы данных.
 func (h *Handler) Ping(ctx context.Context) error {
 	if err := h.DB.Ping(ctx); err != nil {
@@ -11,6 +11,7 @@ import (
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/database/migrate"
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/database/postgres"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -638,6 +639,41 @@ func TestHandlerCloseBehavior(t *testing.T) {
 	})
 }
 
+func TestWithStatementTimeout(t *testing.T) {
+	t.Run("Timeout sets deadline", func(t *testing.T) {
+		ctx, cancel := database.WithStatementTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		deadline, ok := ctx.Deadline()
+		require.True(t, ok)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Second)
+	})
+
+	t.Run("Earlier caller deadline is kept", func(t *testing.T) {
+		parent, parentCancel := context.WithTimeout(context.Background(), time.Second)
+		defer parentCancel()
+		parentDeadline, _ := parent.Deadline()
+
+		ctx, cancel := database.WithStatementTimeout(parent, time.Hour)
+		defer cancel()
+
+		deadline, ok := ctx.Deadline()
+		require.True(t, ok)
+		assert.Equal(t, parentDeadline, deadline)
+	})
+
+	t.Run("Zero timeout leaves context unchanged", func(t *testing.T) {
+		parent := context.Background()
+
+		ctx, cancel := database.WithStatementTimeout(parent, 0)
+		defer cancel()
+
+		assert.Equal(t, parent, ctx)
+		_, ok := ctx.Deadline()
+		assert.False(t, ok)
+	})
+}
+
 func TestDatabaseIntegration(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping integration tests in short mode")
@@ -684,4 +720,34 @@ func TestDatabaseIntegration(t *testing.T) {
 		pool := handler.Pool()
 		assert.NotNil(t, pool)
 	})
+
+	t.Run("Integration - Stats", func(t *testing.T) {
+		conn, err := handler.AcquireConn(ctx)
+		require.NoError(t, err)
+		defer conn.Release()
+
+		stats, err := handler.Stats(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int32(5), stats.MaxConns)
+		assert.GreaterOrEqual(t, stats.AcquiredConns, int32(1))
+		assert.GreaterOrEqual(t, stats.TotalConns, stats.AcquiredConns)
+		assert.GreaterOrEqual(t, stats.AcquireCount, int64(1))
+
+		assert.Equal(t, 6, testutil.CollectAndCount(database.NewPoolCollector(handler, "test")))
+	})
+
+	t.Run("Integration - Statement timeout", func(t *testing.T) {
+		conn, err := handler.AcquireConn(ctx)
+		require.NoError(t, err)
+		defer conn.Release()
+
+		queryCtx, cancel := database.WithStatementTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err = conn.Exec(queryCtx, "SELECT pg_sleep(5)")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), 2*time.Second)
+	})
 }
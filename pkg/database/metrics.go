@@ -0,0 +1,71 @@
+package database
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// PoolCollector отдает состояние пула соединений Handler в формате Prometheus.
+// Значения читаются из пула при каждом сборе метрик.
+type PoolCollector struct {
+	handler         *Handler
+	acquiredConns   *prometheus.Desc
+	idleConns       *prometheus.Desc
+	totalConns      *prometheus.Desc
+	maxConns        *prometheus.Desc
+	acquireCount    *prometheus.Desc
+	acquireDuration *prometheus.Desc
+}
+
+var _ prometheus.Collector = (*PoolCollector)(nil)
+
+// NewPoolCollector создает сборщик метрик пула соединений с префиксом namespace.
+func NewPoolCollector(handler *Handler, namespace string) *PoolCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", name), help, nil, nil)
+	}
+
+	return &PoolCollector{
+		handler:         handler,
+		acquiredConns:   desc("acquired_conns", "Number of connections currently acquired from the pool."),
+		idleConns:       desc("idle_conns", "Number of idle connections in the pool."),
+		totalConns:      desc("total_conns", "Total number of connections in the pool."),
+		maxConns:        desc("max_conns", "Maximum size of the pool."),
+		acquireCount:    desc("acquires_total", "Total number of successful connection acquisitions."),
+		acquireDuration: desc("acquire_duration_seconds_total", "Total time spent waiting for successfully acquired connections."),
+	}
+}
+
+func (c *PoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.acquiredConns
+	ch <- c.idleConns
+	ch <- c.totalConns
+	ch <- c.maxConns
+	ch <- c.acquireCount
+	ch <- c.acquireDuration
+}
+
+// Collect отдает текущее состояние пула. Если пул недоступен, метрики не отдаются.
+func (c *PoolCollector) Collect(ch chan<- prometheus.Metric) {
+	stats, err := c.handler.Stats(context.Background())
+	if err != nil {
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.acquiredConns, prometheus.GaugeValue, float64(stats.AcquiredConns))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(stats.IdleConns))
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(stats.TotalConns))
+	ch <- prometheus.MustNewConstMetric(c.maxConns, prometheus.GaugeValue, float64(stats.MaxConns))
+	ch <- prometheus.MustNewConstMetric(c.acquireCount, prometheus.CounterValue, float64(stats.AcquireCount))
+	ch <- prometheus.MustNewConstMetric(c.acquireDuration, prometheus.CounterValue, stats.AcquireDuration.Seconds())
+}
+
+// MetricsHandler возвращает обработчик, отдающий метрики пула соединений handler в формате Prometheus.
+func MetricsHandler(handler *Handler, namespace string) http.Handler {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(NewPoolCollector(handler, namespace))
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
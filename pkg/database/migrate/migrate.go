@@ -5,6 +5,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"strconv"
+	"strings"
 
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
 	"github.com/golang-migrate/migrate/v4"
@@ -29,6 +32,9 @@ var (
 	ErrForceVersion              = errors.New("failed to force migration version")
 	ErrStepMigrations            = errors.New("failed to perform step migrations")
 	ErrCloseMigrator             = errors.New("failed to close migrator")
+	ErrReadMigrations            = errors.New("failed to read migrations directory")
+	ErrSchemaNotReady            = errors.New("database schema is not ready")
+	ErrSchemaDirty               = errors.New("database schema is dirty")
 )
 
 // Config содержит настройки для миграций.
@@ -206,6 +212,51 @@ func (m *Migrator) Force(ctx context.Context, dsn string, version int, cfg Confi
 	)
 }
 
+// LatestVersion возвращает номер последней миграции в директории cfg.Path по именам
+// файлов вида 000001_name.up.sql. Для директории без миграций возвращается ноль.
+func LatestVersion(cfg Config) (uint, error) {
+	if cfg.Path == "" {
+		return 0, ErrMigrationPathNotSpecified
+	}
+
+	entries, err := os.ReadDir(cfg.Path)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrReadMigrations, err)
+	}
+
+	var latest uint
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".up.sql") {
+			continue
+		}
+
+		prefix, _, found := strings.Cut(name, "_")
+		if !found {
+			continue
+		}
+
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			continue
+		}
+		latest = max(latest, uint(version))
+	}
+	return latest, nil
+}
+
+// CheckVersion проверяет, что схема базы данных с текущей версией version не ниже expected
+// и последняя миграция завершилась.
+func CheckVersion(version uint, dirty bool, expected uint) error {
+	if dirty {
+		return fmt.Errorf("%w: version %d", ErrSchemaDirty, version)
+	}
+	if version < expected {
+		return fmt.Errorf("%w: version %d, expected %d", ErrSchemaNotReady, version, expected)
+	}
+	return nil
+}
+
 // createMigrator создает новый экземпляр мигратора.
 func (m *Migrator) createMigrator(ctx context.Context, dsn string, path string) (*migrate.Migrate, error) {
 	migrator, err := migrate.New(path, dsn)
@@ -3,6 +3,8 @@ package migrate_test
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/database/migrate"
@@ -426,3 +428,42 @@ func TestEmptyFieldsHandling(t *testing.T) {
 
 	t.Skip("Would require advance mocking to test success path")
 }
+
+func TestLatestVersion(t *testing.T) {
+	t.Run("Highest up migration", func(t *testing.T) {
+		dir := t.TempDir()
+		for _, name := range []string{
+			"000001_init.up.sql", "000001_init.down.sql",
+			"000006_client_version.up.sql", "000006_client_version.down.sql",
+			"000002_source.up.sql", "README.md",
+		} {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0o600))
+		}
+
+		version, err := migrate.LatestVersion(migrate.Config{Path: dir})
+		require.NoError(t, err)
+		assert.Equal(t, uint(6), version)
+	})
+
+	t.Run("Empty directory", func(t *testing.T) {
+		version, err := migrate.LatestVersion(migrate.Config{Path: t.TempDir()})
+		require.NoError(t, err)
+		assert.Zero(t, version)
+	})
+
+	t.Run("Missing path", func(t *testing.T) {
+		_, err := migrate.LatestVersion(migrate.Config{})
+		require.ErrorIs(t, err, migrate.ErrMigrationPathNotSpecified)
+
+		_, err = migrate.LatestVersion(migrate.Config{Path: filepath.Join(t.TempDir(), "missing")})
+		require.ErrorIs(t, err, migrate.ErrReadMigrations)
+	})
+}
+
+func TestCheckVersion(t *testing.T) {
+	require.NoError(t, migrate.CheckVersion(6, false, 6))
+	require.NoError(t, migrate.CheckVersion(7, false, 6))
+	require.ErrorIs(t, migrate.CheckVersion(3, false, 6), migrate.ErrSchemaNotReady)
+	require.ErrorIs(t, migrate.CheckVersion(0, false, 6), migrate.ErrSchemaNotReady)
+	require.ErrorIs(t, migrate.CheckVersion(6, true, 6), migrate.ErrSchemaDirty)
+}
@@ -53,6 +53,9 @@ type PostgresConfig struct {
 	MaxConnLifetime time.Duration
 	MaxConnIdleTime time.Duration
 	HealthPeriod    time.Duration
+	// StatementTimeout - statement_timeout соединений: сервер сам прерывает запросы дольше
+	// этого времени. Ноль оставляет значение сервера.
+	StatementTimeout time.Duration
 }
 
 // Validate проверяет правильность конфигурации базы данных.
@@ -91,6 +94,10 @@ func (c PostgresConfig) DSN() string {
 		params = append(params, fmt.Sprintf("application_name=%s", c.ApplicationName))
 	}
 
+	if c.StatementTimeout > 0 {
+		params = append(params, fmt.Sprintf("statement_timeout=%d", c.StatementTimeout.Milliseconds()))
+	}
+
 	if len(params) > 0 {
 		dsn += "?" + params[0]
 		for i := 1; i < len(params); i++ {
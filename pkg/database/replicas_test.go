@@ -0,0 +1,89 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockPool struct {
+	acquireErr error
+	acquired   int
+	closed     bool
+}
+
+func (m *mockPool) AcquireConn(context.Context) (*pgxpool.Conn, error) {
+	m.acquired++
+	if m.acquireErr != nil {
+		return nil, m.acquireErr
+	}
+	return nil, nil
+}
+
+func (m *mockPool) Close(context.Context) {
+	m.closed = true
+}
+
+func TestHandlerReadReplicas(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Reads are spread across replicas", func(t *testing.T) {
+		primary, first, second := &mockPool{}, &mockPool{}, &mockPool{}
+		handler := &Handler{primary: primary, replicas: []connPool{first, second}}
+
+		for range 4 {
+			_, err := handler.AcquireReadConn(ctx)
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, 2, first.acquired)
+		assert.Equal(t, 2, second.acquired)
+		assert.Zero(t, primary.acquired)
+	})
+
+	t.Run("Writes go to primary", func(t *testing.T) {
+		primary, replica := &mockPool{}, &mockPool{}
+		handler := &Handler{primary: primary, replicas: []connPool{replica}}
+
+		_, err := handler.AcquireConn(ctx)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, primary.acquired)
+		assert.Zero(t, replica.acquired)
+	})
+
+	t.Run("Reads without replicas go to primary", func(t *testing.T) {
+		primary := &mockPool{}
+		handler := &Handler{primary: primary}
+
+		_, err := handler.AcquireReadConn(ctx)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, primary.acquired)
+	})
+
+	t.Run("Unavailable replica falls back to primary", func(t *testing.T) {
+		primary, replica := &mockPool{}, &mockPool{acquireErr: errors.New("replica is down")}
+		handler := &Handler{primary: primary, replicas: []connPool{replica}}
+
+		_, err := handler.AcquireReadConn(ctx)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, replica.acquired)
+		assert.Equal(t, 1, primary.acquired)
+	})
+
+	t.Run("Close closes primary and replicas", func(t *testing.T) {
+		primary, replica := &mockPool{}, &mockPool{}
+		handler := &Handler{primary: primary, replicas: []connPool{replica}}
+
+		handler.Close(ctx)
+
+		assert.True(t, primary.closed)
+		assert.True(t, replica.closed)
+	})
+}
@@ -0,0 +1,86 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+)
+
+// txKey - ключ контекста, под которым хранится текущая транзакция.
+type txKey struct{}
+
+// TxManager выполняет обращения репозиториев в одной транзакции PostgreSQL.
+// Репозитории получают транзакцию из контекста через TxFromContext или Handler.ExecContext.
+type TxManager struct {
+	db *Handler
+}
+
+// NewTxManager создает менеджер транзакций для базы данных db.
+func NewTxManager(db *Handler) *TxManager {
+	return &TxManager{db: db}
+}
+
+// WithinTransaction вызывает fn с контекстом, привязанным к транзакции. Транзакция фиксируется,
+// если fn вернула nil, и откатывается в противном случае. Вложенный вызов продолжает уже
+// открытую транзакцию.
+func (m *TxManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	const op = "TxManager.WithinTransaction"
+
+	if _, ok := TxFromContext(ctx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := m.db.Pool().Begin(ctx)
+	if err != nil {
+		logger.Error(ctx, nil, "Failed to begin transaction", zap.String("op", op), zap.Error(err))
+		return fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+
+	var committed bool
+	defer func() {
+		if !committed {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				logger.Error(ctx, nil, "Failed to rollback transaction",
+					zap.String("op", op),
+					zap.Error(rbErr))
+			}
+		}
+	}()
+
+	if err = fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		logger.Error(ctx, nil, "Failed to commit transaction", zap.String("op", op), zap.Error(err))
+		return fmt.Errorf("%s: commit transaction: %w", op, err)
+	}
+	committed = true
+
+	return nil
+}
+
+// TxFromContext возвращает транзакцию, открытую TxManager, если она есть в контексте.
+func TxFromContext(ctx context.Context) (pgx.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(pgx.Tx)
+	return tx, ok
+}
+
+// ExecContext выполняет запрос в транзакции из контекста, а без нее - на отдельном соединении.
+func (h *Handler) ExecContext(ctx context.Context, query string, args ...any) (pgconn.CommandTag, error) {
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx.Exec(ctx, query, args...)
+	}
+
+	conn, err := h.AcquireConn(ctx)
+	if err != nil {
+		return pgconn.CommandTag{}, fmt.Errorf("acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	return conn.Exec(ctx, query, args...)
+}
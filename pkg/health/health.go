@@ -0,0 +1,182 @@
+// Package health реализует проверки живости (liveness) и готовности (readiness) сервисов:
+// /healthz отвечает, пока процесс работает, /readyz - только когда доступны все зависимости.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// PathLiveness - путь проверки живости.
+	PathLiveness = "/healthz"
+	// PathReadiness - путь проверки готовности.
+	PathReadiness = "/readyz"
+
+	// StatusOK - зависимость или сервис в целом доступны.
+	StatusOK = "ok"
+	// StatusUnavailable - зависимость или сервис в целом недоступны.
+	StatusUnavailable = "unavailable"
+
+	// DefaultTimeout - время, отведенное на все проверки готовности по умолчанию.
+	DefaultTimeout = 2 * time.Second
+)
+
+// Check проверяет доступность одной зависимости.
+type Check func(ctx context.Context) error
+
+// Pinger - зависимость, доступность которой проверяется методом Ping,
+// например database.Handler или gRPC клиент.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// DependencyStatus - результат проверки одной зависимости.
+type DependencyStatus struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Report - результат проверки готовности сервиса.
+type Report struct {
+	Status string                      `json:"status"`
+	Checks map[string]DependencyStatus `json:"checks"`
+}
+
+// Healthy сообщает, прошли ли все проверки.
+func (r Report) Healthy() bool {
+	return r.Status == StatusOK
+}
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Checker хранит проверки зависимостей сервиса и выполняет их параллельно.
+type Checker struct {
+	mu      sync.RWMutex
+	checks  []namedCheck
+	timeout time.Duration
+}
+
+// NewChecker создает набор проверок без зависимостей: такой сервис всегда готов.
+func NewChecker() *Checker {
+	return &Checker{timeout: DefaultTimeout}
+}
+
+// SetTimeout задает время, отведенное на все проверки. Неположительное значение игнорируется.
+func (c *Checker) SetTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	c.timeout = timeout
+	c.mu.Unlock()
+}
+
+// Add добавляет проверку зависимости с именем name.
+func (c *Checker) Add(name string, check Check) {
+	if check == nil {
+		return
+	}
+
+	c.mu.Lock()
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+	c.mu.Unlock()
+}
+
+// AddPinger добавляет проверку зависимости через ее метод Ping.
+func (c *Checker) AddPinger(name string, pinger Pinger) {
+	if pinger == nil {
+		return
+	}
+	c.Add(name, pinger.Ping)
+}
+
+// Check выполняет все проверки параллельно. Проверка, не уложившаяся в отведенное время,
+// считается неуспешной.
+func (c *Checker) Check(ctx context.Context) Report {
+	c.mu.RLock()
+	checks := append([]namedCheck(nil), c.checks...)
+	timeout := c.timeout
+	c.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	results := make([]DependencyStatus, len(checks))
+	var wg sync.WaitGroup
+	for i, nc := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = runCheck(ctx, nc.check)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]DependencyStatus, len(checks))}
+	for i, nc := range checks {
+		report.Checks[nc.name] = results[i]
+		if results[i].Status != StatusOK {
+			report.Status = StatusUnavailable
+		}
+	}
+	return report
+}
+
+// runCheck выполняет проверку, не дожидаясь ее завершения после отмены контекста.
+func runCheck(ctx context.Context, check Check) DependencyStatus {
+	done := make(chan error, 1)
+	go func() { done <- check(ctx) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	if err != nil {
+		return DependencyStatus{Status: StatusUnavailable, Error: err.Error()}
+	}
+	return DependencyStatus{Status: StatusOK}
+}
+
+// LivenessHandler отвечает 200, пока процесс способен обрабатывать запросы.
+func LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, Report{Status: StatusOK, Checks: map[string]DependencyStatus{}})
+	})
+}
+
+// ReadinessHandler отвечает 200, если все проверки прошли, и 503 в противном случае.
+// В теле ответа перечисляется состояние каждой зависимости.
+func (c *Checker) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := c.Check(r.Context())
+
+		status := http.StatusOK
+		if !report.Healthy() {
+			status = http.StatusServiceUnavailable
+		}
+		writeJSON(w, status, report)
+	})
+}
+
+// Routes регистрирует обработчики /healthz и /readyz.
+func (c *Checker) Routes(mux interface{ Handle(string, http.Handler) }) {
+	mux.Handle(PathLiveness, LivenessHandler())
+	mux.Handle(PathReadiness, c.ReadinessHandler())
+}
+
+func writeJSON(w http.ResponseWriter, status int, report Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(report)
+}
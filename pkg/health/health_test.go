@@ -0,0 +1,144 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+type mockPinger struct {
+	err   error
+	delay time.Duration
+}
+
+func (m *mockPinger) Ping(ctx context.Context) error {
+	if m.delay > 0 {
+		select {
+		case <-time.After(m.delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return m.err
+}
+
+func serve(t *testing.T, handler http.Handler) (int, Report) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, PathReadiness, nil))
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var report Report
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&report))
+	return rec.Code, report
+}
+
+func TestReadinessHandler(t *testing.T) {
+	t.Run("All dependencies available", func(t *testing.T) {
+		checker := NewChecker()
+		checker.AddPinger("database", &mockPinger{})
+		checker.AddPinger("auth", &mockPinger{})
+
+		code, report := serve(t, checker.ReadinessHandler())
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, StatusOK, report.Status)
+		assert.Equal(t, map[string]DependencyStatus{
+			"database": {Status: StatusOK},
+			"auth":     {Status: StatusOK},
+		}, report.Checks)
+	})
+
+	t.Run("Database ping error", func(t *testing.T) {
+		checker := NewChecker()
+		checker.AddPinger("database", &mockPinger{err: errors.New("connection refused")})
+		checker.AddPinger("auth", &mockPinger{})
+
+		code, report := serve(t, checker.ReadinessHandler())
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, StatusUnavailable, report.Status)
+		assert.Equal(t, DependencyStatus{Status: StatusUnavailable, Error: "connection refused"}, report.Checks["database"])
+		assert.Equal(t, DependencyStatus{Status: StatusOK}, report.Checks["auth"])
+	})
+
+	t.Run("Slow dependency times out", func(t *testing.T) {
+		checker := NewChecker()
+		checker.SetTimeout(20 * time.Millisecond)
+		checker.AddPinger("orchestrator", &mockPinger{delay: time.Minute})
+
+		start := time.Now()
+		code, report := serve(t, checker.ReadinessHandler())
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["orchestrator"].Error)
+	})
+
+	t.Run("No dependencies", func(t *testing.T) {
+		code, report := serve(t, NewChecker().ReadinessHandler())
+		assert.Equal(t, http.StatusOK, code)
+		assert.Empty(t, report.Checks)
+	})
+}
+
+func TestLivenessIgnoresDependencies(t *testing.T) {
+	checker := NewChecker()
+	checker.AddPinger("database", &mockPinger{err: errors.New("connection refused")})
+
+	mux := http.NewServeMux()
+	checker.Routes(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, PathLiveness, nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, PathReadiness, nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+func TestMonitor(t *testing.T) {
+	t.Run("Trips after threshold and recovers on success", func(t *testing.T) {
+		pinger := &mockPinger{err: errors.New("connection refused")}
+		monitor := NewMonitor(pinger, 0, 2)
+
+		assert.Error(t, monitor.Ping(context.Background()))
+		assert.NoError(t, monitor.Err(), "one failure is below the threshold")
+
+		assert.Error(t, monitor.Ping(context.Background()))
+		assert.EqualError(t, monitor.Err(), "connection refused")
+
+		pinger.err = nil
+		assert.NoError(t, monitor.Ping(context.Background()))
+		assert.NoError(t, monitor.Err())
+	})
+
+	t.Run("Readiness check reports cause and updates state", func(t *testing.T) {
+		monitor := NewMonitor(&mockPinger{err: errors.New("connection refused")}, 0, 1)
+		checker := NewChecker()
+		checker.AddPinger("database", monitor)
+
+		code, report := serve(t, checker.ReadinessHandler())
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, DependencyStatus{Status: StatusUnavailable, Error: "connection refused"}, report.Checks["database"])
+		assert.EqualError(t, monitor.Err(), "connection refused")
+	})
+
+	t.Run("Periodic checks", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(logger.WithLogger(context.Background(), logger.New(zapcore.NewNopCore())))
+		defer cancel()
+
+		monitor := NewMonitor(&mockPinger{err: errors.New("connection refused")}, 5*time.Millisecond, 1)
+		monitor.Start(ctx)
+
+		assert.Eventually(t, func() bool { return monitor.Err() != nil }, time.Second, 5*time.Millisecond)
+	})
+}
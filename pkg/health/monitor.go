@@ -0,0 +1,99 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Monitor периодически проверяет зависимость и хранит результат, чтобы обработчики запросов
+// могли узнать о ее недоступности без обращения к ней. Зависимость считается недоступной
+// после threshold неудачных проверок подряд и снова доступной после первой успешной.
+type Monitor struct {
+	pinger    Pinger
+	interval  time.Duration
+	timeout   time.Duration
+	threshold int
+
+	mu       sync.RWMutex
+	failures int
+	lastErr  error
+}
+
+// NewMonitor создает монитор зависимости pinger с проверкой каждые interval.
+// Порог threshold меньше единицы считается единицей.
+func NewMonitor(pinger Pinger, interval time.Duration, threshold int) *Monitor {
+	return &Monitor{
+		pinger:    pinger,
+		interval:  interval,
+		timeout:   DefaultTimeout,
+		threshold: max(threshold, 1),
+	}
+}
+
+// Ping проверяет зависимость и запоминает результат. Монитор сам реализует Pinger,
+// поэтому проверка готовности через него тоже обновляет состояние.
+func (m *Monitor) Ping(ctx context.Context) error {
+	err := m.pinger.Ping(ctx)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err != nil {
+		m.failures++
+		m.lastErr = err
+		return err
+	}
+	m.failures = 0
+	m.lastErr = nil
+	return nil
+}
+
+// Err возвращает ошибку последней проверки, если порог неудачных проверок достигнут, и nil,
+// пока зависимость считается доступной.
+func (m *Monitor) Err() error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.failures < m.threshold {
+		return nil
+	}
+	return m.lastErr
+}
+
+// Start запускает периодические проверки до завершения ctx. Неположительный интервал
+// отключает проверки, и зависимость остается доступной.
+func (m *Monitor) Start(ctx context.Context) {
+	if m.interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+
+		log := logger.ContextLogger(ctx, nil)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			wasHealthy := m.Err() == nil
+			pingCtx, cancel := context.WithTimeout(ctx, m.timeout)
+			err := m.Ping(pingCtx)
+			cancel()
+
+			switch healthy := m.Err() == nil; {
+			case wasHealthy && !healthy:
+				log.Warn("Dependency became unavailable", zap.Error(err))
+			case !wasHealthy && healthy:
+				log.Info("Dependency is available again")
+			}
+		}
+	}()
+}
@@ -0,0 +1,67 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const readHeaderTimeout = 5 * time.Second
+
+// Server - отдельный HTTP сервер проверок для сервисов без собственного HTTP API,
+// например gRPC сервисов авторизации и оркестрации.
+type Server struct {
+	server *http.Server
+	mux    *http.ServeMux
+}
+
+// NewServer создает HTTP сервер проверок checker по адресу addr.
+func NewServer(addr string, checker *Checker) *Server {
+	mux := http.NewServeMux()
+	checker.Routes(mux)
+
+	return &Server{
+		server: &http.Server{
+			Addr:              addr,
+			Handler:           mux,
+			ReadHeaderTimeout: readHeaderTimeout,
+		},
+		mux: mux,
+	}
+}
+
+// Handle регистрирует на сервере проверок дополнительный служебный обработчик, например /metrics.
+// Вызывается до Start.
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// Start начинает прослушивание адреса и обслуживает запросы в отдельной горутине.
+func (s *Server) Start(ctx context.Context) error {
+	listener, err := (&net.ListenConfig{}).Listen(ctx, "tcp", s.server.Addr)
+	if err != nil {
+		return fmt.Errorf("listening health address %s: %w", s.server.Addr, err)
+	}
+
+	log := logger.ContextLogger(ctx, nil)
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("Health server error", zap.Error(err))
+		}
+	}()
+	return nil
+}
+
+// Stop останавливает сервер, дожидаясь завершения текущих запросов.
+func (s *Server) Stop(ctx context.Context) error {
+	if err := s.server.Shutdown(ctx); err != nil {
+		return fmt.Errorf("shutting down health server: %w", err)
+	}
+	return nil
+}
@@ -3,7 +3,9 @@ package logger
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger/ctxlog"
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger/logging"
@@ -40,6 +42,25 @@ const (
 	FatalLevel = ctxlog.FatalLevel
 )
 
+// ErrUnknownLevel возвращается ParseLevel для строки, не являющейся уровнем логирования.
+var ErrUnknownLevel = errors.New("unknown log level")
+
+// ParseLevel возвращает уровень логирования по имени: debug, info, warn, error или fatal
+// без учета регистра.
+func ParseLevel(name string) (LogLevel, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	lvl := levelPkg.Parse(name)
+	if lvl.String() != name {
+		return InfoLevel, fmt.Errorf("%w: %q", ErrUnknownLevel, name)
+	}
+	return convertFromLoggingLevel(lvl), nil
+}
+
+// LevelName возвращает имя уровня логирования, обратное ParseLevel.
+func LevelName(lvl LogLevel) string {
+	return convertToLoggingLevel(lvl).String()
+}
+
 // Field представляет общий тип для полей лога.
 type Field = ctxlog.Field
 
@@ -189,9 +210,19 @@ func Console(lvl LogLevel, json bool) ZapLogger {
 	}
 }
 
+// Option настраивает журнал, создаваемый Development или Production.
+type Option = logging.Option
+
+// WithSampling включает сэмплирование повторяющихся записей ниже уровня Error: из записей
+// с одинаковыми уровнем и сообщением за секунду пишутся первые initial, затем каждая
+// thereafter-я. Ошибки не сэмплируются. Неположительный initial отключает сэмплирование.
+func WithSampling(initial, thereafter int) Option {
+	return logging.WithSampling(initial, thereafter)
+}
+
 // Development создает журнал для разработки.
-func Development() (ZapLogger, error) {
-	logger, err := logging.Development()
+func Development(opts ...Option) (ZapLogger, error) {
+	logger, err := logging.Development(opts...)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", errMsgCreateDevLogger, err)
 	}
@@ -199,14 +230,25 @@ func Development() (ZapLogger, error) {
 }
 
 // Production создает журнал для продуктовой версии.
-func Production() (ZapLogger, error) {
-	logger, err := logging.Production()
+func Production(opts ...Option) (ZapLogger, error) {
+	logger, err := logging.Production(opts...)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", errMsgCreateDevLogger, err)
 	}
 	return &zapAdapter{logger: logger}, nil
 }
 
+// Redacted возвращает журнал, который заменяет значения полей с именами из fields
+// (без учета регистра, по вхождению подстроки) на "[REDACTED]". Журнал другой
+// реализации возвращается без изменений.
+func Redacted(logger ZapLogger, fields []string) ZapLogger {
+	adapter, ok := logger.(*zapAdapter)
+	if !ok {
+		return logger
+	}
+	return &zapAdapter{logger: adapter.logger.WithRedaction(fields)}
+}
+
 // WithLogger добавляет журнал в контекст.
 func WithLogger(ctx context.Context, logger Logger) context.Context {
 	return ctxlog.WithLogger(ctx, logger)
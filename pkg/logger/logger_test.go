@@ -189,6 +189,30 @@ func TestWithMethod(t *testing.T) {
 	assert.Equal(t, "specific_value", contextMap["specific_key"], "specific field should be present")
 }
 
+func TestRedacted(t *testing.T) {
+	core, observedLogs := observer.New(zapcore.DebugLevel)
+	redacted := logger.Redacted(logger.New(core), []string{"password", "login", "expression"})
+
+	redacted.With(zap.String("op", "AuthUseCase.Register"), zap.String("login", "alice")).
+		Info("User registered", zap.String("password", "secret"), zap.String("user_id", "42"))
+	redacted.Info("Calculation created",
+		zap.String("expression_a", "2+2"), zap.String("source", "api"), zap.Int("operations", 1))
+
+	logs := observedLogs.All()
+	require.Len(t, logs, 2)
+
+	registered := logs[0].ContextMap()
+	assert.Equal(t, "[REDACTED]", registered["login"], "field added with With should be masked")
+	assert.Equal(t, "[REDACTED]", registered["password"])
+	assert.Equal(t, "AuthUseCase.Register", registered["op"])
+	assert.Equal(t, "42", registered["user_id"])
+
+	created := logs[1].ContextMap()
+	assert.Equal(t, "[REDACTED]", created["expression_a"], "key containing a configured name should be masked")
+	assert.Equal(t, "api", created["source"])
+	assert.Equal(t, int64(1), created["operations"])
+}
+
 func TestSetAndGetLevel(t *testing.T) {
 	core, _ := observer.New(zapcore.DebugLevel)
 	zapLogger := logger.New(core)
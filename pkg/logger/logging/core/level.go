@@ -0,0 +1,38 @@
+package core
+
+import "go.uber.org/zap/zapcore"
+
+// leveledCore отбрасывает записи ниже уровня enabler до вложенного ядра.
+type leveledCore struct {
+	zapcore.Core
+	enabler zapcore.LevelEnabler
+}
+
+// Leveled оборачивает ядро так, что записи ниже уровня enabler не пишутся. Уровень enabler
+// можно менять во время работы (например, zap.AtomicLevel), и журнал сразу его учитывает.
+func Leveled(core zapcore.Core, enabler zapcore.LevelEnabler) zapcore.Core {
+	return &leveledCore{Core: core, enabler: enabler}
+}
+
+// Enabled сообщает, что уровень разрешен и оберткой, и вложенным ядром.
+func (c *leveledCore) Enabled(lvl zapcore.Level) bool {
+	return c.enabler.Enabled(lvl) && c.Core.Enabled(lvl)
+}
+
+// Level возвращает минимальный уровень, который пишет журнал.
+func (c *leveledCore) Level() zapcore.Level {
+	return max(zapcore.LevelOf(c.enabler), zapcore.LevelOf(c.Core))
+}
+
+// With добавляет поля к вложенному ядру, сохраняя уровень.
+func (c *leveledCore) With(fields []zapcore.Field) zapcore.Core {
+	return &leveledCore{Core: c.Core.With(fields), enabler: c.enabler}
+}
+
+// Check передает вложенному ядру только записи разрешенного уровня.
+func (c *leveledCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.enabler.Enabled(entry.Level) {
+		return checked
+	}
+	return c.Core.Check(entry, checked)
+}
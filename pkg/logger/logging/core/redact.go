@@ -0,0 +1,85 @@
+package core
+
+import (
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// RedactedValue - значение, которым заменяются чувствительные поля.
+const RedactedValue = "[REDACTED]"
+
+// redactingCore маскирует значения полей с чувствительными именами до того, как запись
+// попадет в кодировщик, поэтому маскирование не зависит от формата вывода.
+type redactingCore struct {
+	zapcore.Core
+	fields []string
+}
+
+// Redact оборачивает ядро так, что значения полей, в имени которых без учета регистра
+// встречается одно из fields (например, password или refresh_token для token),
+// заменяются на RedactedValue. Пустой список возвращает ядро без изменений.
+func Redact(core zapcore.Core, fields []string) zapcore.Core {
+	normalized := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if field = strings.ToLower(strings.TrimSpace(field)); field != "" {
+			normalized = append(normalized, field)
+		}
+	}
+
+	if len(normalized) == 0 {
+		return core
+	}
+	return &redactingCore{Core: core, fields: normalized}
+}
+
+// With добавляет к ядру поля, предварительно замаскировав чувствительные.
+func (c *redactingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactingCore{Core: c.Core.With(c.redact(fields)), fields: c.fields}
+}
+
+// Check регистрирует обертку, а не вложенное ядро, чтобы запись прошла через Write.
+// Решение о записи принимает вложенное ядро, поэтому его сэмплирование сохраняется.
+func (c *redactingCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Core.Check(entry, nil) != nil {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+// Write маскирует чувствительные поля и передает запись вложенному ядру.
+func (c *redactingCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(entry, c.redact(fields))
+}
+
+// redact возвращает поля с замаскированными значениями. Исходный срез не изменяется,
+// копия создается только при наличии чувствительных полей.
+func (c *redactingCore) redact(fields []zapcore.Field) []zapcore.Field {
+	var redacted []zapcore.Field
+	for i, field := range fields {
+		if field.Type == zapcore.NamespaceType || !c.sensitive(field.Key) {
+			continue
+		}
+
+		if redacted == nil {
+			redacted = make([]zapcore.Field, len(fields))
+			copy(redacted, fields)
+		}
+		redacted[i] = zapcore.Field{Key: field.Key, Type: zapcore.StringType, String: RedactedValue}
+	}
+
+	if redacted == nil {
+		return fields
+	}
+	return redacted
+}
+
+func (c *redactingCore) sensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, field := range c.fields {
+		if strings.Contains(key, field) {
+			return true
+		}
+	}
+	return false
+}
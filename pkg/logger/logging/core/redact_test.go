@@ -0,0 +1,58 @@
+package core_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger/logging/core"
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger/logging/level"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestRedact(t *testing.T) {
+	for _, format := range []struct {
+		name string
+		json bool
+	}{
+		{name: "json", json: true},
+		{name: "console", json: false},
+	} {
+		t.Run(format.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			c := core.New(core.CreateEncoder(format.json), zapcore.AddSync(&buf), level.DebugLevel)
+			log := zap.New(core.Redact(c, []string{"password", "token"}))
+
+			log.With(zap.String("refresh_token", "rt-secret")).Info("login",
+				zap.String("login", "alice"),
+				zap.String("password", "hunter2"),
+				zap.String("Password", "Hunter3"),
+				zap.Int("token_ttl", 987654321))
+
+			out := buf.String()
+			assert.Contains(t, out, "alice")
+			assert.Contains(t, out, core.RedactedValue)
+			assert.NotContains(t, out, "hunter2")
+			assert.NotContains(t, out, "Hunter3")
+			assert.NotContains(t, out, "rt-secret")
+			assert.NotContains(t, out, "987654321")
+		})
+	}
+}
+
+func TestRedactKeepsFieldsWithoutSensitiveNames(t *testing.T) {
+	var buf bytes.Buffer
+	c := core.New(core.CreateEncoder(true), zapcore.AddSync(&buf), level.InfoLevel)
+
+	assert.Same(t, c, core.Redact(c, nil), "empty list must not wrap the core")
+
+	log := zap.New(core.Redact(c, []string{" password "}))
+	log.Debug("dropped", zap.String("password", "hunter2"))
+	log.Info("kept", zap.String("user", "alice"))
+
+	out := buf.String()
+	assert.NotContains(t, out, "dropped", "level of the wrapped core must be respected")
+	assert.Contains(t, out, `"user":"alice"`)
+	assert.NotContains(t, out, core.RedactedValue)
+}
@@ -0,0 +1,46 @@
+package core
+
+import (
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// SampleTick - интервал, в пределах которого считаются повторы одного сообщения.
+const SampleTick = time.Second
+
+// samplingCore сэмплирует записи ниже уровня Error и пропускает остальные без ограничений.
+type samplingCore struct {
+	zapcore.Core
+	sampled zapcore.Core
+}
+
+// Sample оборачивает ядро так, что из записей с одинаковыми уровнем и сообщением за SampleTick
+// пишутся первые initial, а затем каждая thereafter-я; при thereafter меньше единицы остальные
+// отбрасываются. Записи уровня Error и выше пишутся всегда. Неположительный initial
+// возвращает ядро без изменений.
+func Sample(core zapcore.Core, initial, thereafter int) zapcore.Core {
+	if initial <= 0 {
+		return core
+	}
+	return &samplingCore{
+		Core:    core,
+		sampled: zapcore.NewSamplerWithOptions(core, SampleTick, initial, max(thereafter, 0)),
+	}
+}
+
+// With добавляет поля к ядру и к его сэмплирующей обертке.
+func (c *samplingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &samplingCore{
+		Core:    c.Core.With(fields),
+		sampled: c.sampled.With(fields),
+	}
+}
+
+// Check передает записи уровня Error и выше вложенному ядру напрямую, остальные - сэмплеру.
+func (c *samplingCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if entry.Level >= zapcore.ErrorLevel {
+		return c.Core.Check(entry, checked)
+	}
+	return c.sampled.Check(entry, checked)
+}
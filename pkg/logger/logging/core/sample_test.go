@@ -0,0 +1,54 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger/logging/core"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestSample(t *testing.T) {
+	t.Run("Repeated debug messages are sampled, errors always pass", func(t *testing.T) {
+		observed, logs := observer.New(zapcore.DebugLevel)
+		log := zap.New(core.Sample(observed, 3, 10)).With(zap.String("component", "processor"))
+
+		for range 25 {
+			log.Debug("tick")
+			log.Error("operation failed")
+		}
+		log.Debug("other message")
+
+		// Первые 3 записи, затем каждая 10-я: 13-я и 23-я
+		assert.Equal(t, 5, logs.FilterMessage("tick").Len())
+		assert.Equal(t, 25, logs.FilterMessage("operation failed").Len())
+		assert.Equal(t, 1, logs.FilterMessage("other message").Len())
+		assert.Equal(t, 31, logs.FilterField(zap.String("component", "processor")).Len())
+	})
+
+	t.Run("Zero thereafter drops repeats after initial", func(t *testing.T) {
+		observed, logs := observer.New(zapcore.DebugLevel)
+		log := zap.New(core.Sample(observed, 2, 0))
+
+		for range 10 {
+			log.Info("tick")
+			log.Error("tick")
+		}
+
+		assert.Equal(t, 2, logs.FilterLevelExact(zapcore.InfoLevel).Len())
+		assert.Equal(t, 10, logs.FilterLevelExact(zapcore.ErrorLevel).Len())
+	})
+
+	t.Run("Non-positive initial disables sampling", func(t *testing.T) {
+		observed, logs := observer.New(zapcore.DebugLevel)
+		assert.Same(t, observed, core.Sample(observed, 0, 10))
+
+		log := zap.New(core.Sample(observed, 0, 10))
+		for range 10 {
+			log.Debug("tick")
+		}
+		assert.Equal(t, 10, logs.Len())
+	})
+}
@@ -24,6 +24,44 @@ const (
 	ErrBuildProdLogger = "failed to build production logger"
 )
 
+// Сэмплирование журнала для продуктовой версии по умолчанию, как в zap.NewProductionConfig.
+const (
+	DefaultSamplingInitial    = 100
+	DefaultSamplingThereafter = 100
+)
+
+// options содержит настройки журналов Development и Production.
+type options struct {
+	samplingInitial    int
+	samplingThereafter int
+}
+
+// Option настраивает журнал Development или Production.
+type Option func(*options)
+
+// WithSampling включает сэмплирование записей ниже уровня Error (см. core.Sample): из записей
+// с одинаковыми уровнем и сообщением за секунду пишутся первые initial, затем каждая
+// thereafter-я. Неположительный initial отключает сэмплирование.
+func WithSampling(initial, thereafter int) Option {
+	return func(o *options) {
+		o.samplingInitial = initial
+		o.samplingThereafter = thereafter
+	}
+}
+
+// build собирает журнал по конфигурации zap, заменяя встроенный сэмплер zap, который
+// сэмплирует и ошибки, на core.Sample.
+func build(cfg zap.Config, o options) (*zap.Logger, error) {
+	cfg.Sampling = nil
+	zapLogger, err := cfg.Build(zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+		return core.Sample(c, o.samplingInitial, o.samplingThereafter)
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("build logger: %w", err)
+	}
+	return zapLogger, nil
+}
+
 // NewLogger создает новый журнал с заданным zap Logger и уровнем.
 func NewLogger(zapLogger *zap.Logger, level zap.AtomicLevel) *Logger {
 	return &Logger{
@@ -32,21 +70,22 @@ func NewLogger(zapLogger *zap.Logger, level zap.AtomicLevel) *Logger {
 	}
 }
 
-// New создает новый журнал с указанными настройками ядра.
-func New(core zapcore.Core) *Logger {
+// New создает новый журнал с указанными настройками ядра. Если ядро сообщает свой уровень
+// методом Level, журнал начинает с него, и изменение уровня через GetAtomicLevel сразу действует
+// на запись. Для остальных ядер уровень журнала - Info, а записи фильтрует само ядро.
+func New(c zapcore.Core) *Logger {
+	atomicLevel := zap.NewAtomicLevelAt(zapcore.InfoLevel)
+	if le, ok := c.(interface{ Level() zapcore.Level }); ok {
+		atomicLevel = zap.NewAtomicLevelAt(le.Level())
+		c = core.Leveled(c, atomicLevel)
+	}
+
 	zapLogger := zap.New(
-		core,
+		c,
 		zap.AddCaller(),
 		zap.AddStacktrace(zapcore.ErrorLevel),
 	)
 
-	var atomicLevel zap.AtomicLevel
-	if le, ok := core.(interface{ Level() zapcore.Level }); ok {
-		atomicLevel = zap.NewAtomicLevelAt(le.Level())
-	} else {
-		atomicLevel = zap.NewAtomicLevelAt(zapcore.InfoLevel)
-	}
-
 	return NewLogger(zapLogger, atomicLevel)
 }
 
@@ -66,26 +105,37 @@ func Console(lvl level.LogLevel, json bool) *Logger {
 	return NewLogger(zapLogger, atomicLevel)
 }
 
-// Development создает журнал для разработки.
-func Development() (*Logger, error) {
+// Development создает журнал для разработки. По умолчанию записи не сэмплируются.
+func Development(opts ...Option) (*Logger, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	cfg := zap.NewDevelopmentConfig()
-	zapLogger, err := cfg.Build()
+	zapLogger, err := build(cfg, o)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", ErrBuildDevLogger, err)
 	}
 
-	return NewLogger(zapLogger, zap.NewAtomicLevelAt(zapcore.DebugLevel)), nil
+	return NewLogger(zapLogger, cfg.Level), nil
 }
 
-// Production создает журнал для релиза продукта.
-func Production() (*Logger, error) {
+// Production создает журнал для релиза продукта. По умолчанию записи ниже уровня Error
+// сэмплируются с DefaultSamplingInitial и DefaultSamplingThereafter.
+func Production(opts ...Option) (*Logger, error) {
+	o := options{samplingInitial: DefaultSamplingInitial, samplingThereafter: DefaultSamplingThereafter}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	cfg := zap.NewProductionConfig()
-	zapLogger, err := cfg.Build()
+	zapLogger, err := build(cfg, o)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", ErrBuildProdLogger, err)
 	}
 
-	return NewLogger(zapLogger, zap.NewAtomicLevelAt(zapcore.InfoLevel)), nil
+	return NewLogger(zapLogger, cfg.Level), nil
 }
 
 // GetZapLogger возвращает нижележащий zap logger.
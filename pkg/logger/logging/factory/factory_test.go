@@ -185,6 +185,18 @@ func TestProduction(t *testing.T) {
 	assert.NotNil(t, logger.GetZapLogger(), "Production should create a non-nil zap logger")
 }
 
+func TestSamplingOption(t *testing.T) {
+	for _, build := range []func(...factory.Option) (*factory.Logger, error){factory.Development, factory.Production} {
+		logger, err := build(factory.WithSampling(10, 5))
+		require.NoError(t, err)
+		assert.NotNil(t, logger.GetZapLogger())
+
+		logger, err = build(factory.WithSampling(0, 0))
+		require.NoError(t, err)
+		assert.NotNil(t, logger.GetZapLogger())
+	}
+}
+
 func TestGetZapLogger(t *testing.T) {
 	zapLogger := zap.NewExample()
 	logger := factory.NewLogger(zapLogger, zap.NewAtomicLevel())
@@ -7,6 +7,7 @@ import (
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger/logging/core"
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger/logging/factory"
 	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger/logging/level"
 )
@@ -56,9 +57,17 @@ func Console(lvl level.LogLevel, json bool) *Logger {
 	}
 }
 
+// Option настраивает журнал Development или Production.
+type Option = factory.Option
+
+// WithSampling включает сэмплирование записей ниже уровня Error (см. factory.WithSampling).
+func WithSampling(initial, thereafter int) Option {
+	return factory.WithSampling(initial, thereafter)
+}
+
 // Development создает журнал для разработки.
-func Development() (*Logger, error) {
-	factoryLogger, err := factory.Development()
+func Development(opts ...Option) (*Logger, error) {
+	factoryLogger, err := factory.Development(opts...)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", factory.ErrBuildDevLogger, err)
 	}
@@ -69,8 +78,8 @@ func Development() (*Logger, error) {
 }
 
 // Production создает журнал для релиза продукта.
-func Production() (*Logger, error) {
-	factoryLogger, err := factory.Production()
+func Production(opts ...Option) (*Logger, error) {
+	factoryLogger, err := factory.Production(opts...)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", factory.ErrBuildProdLogger, err)
 	}
@@ -88,6 +97,17 @@ func (l *Logger) With(fields ...zap.Field) LoggerInterface {
 	}
 }
 
+// WithRedaction создаёт журнал, маскирующий значения полей с чувствительными именами
+// (см. core.Redact) во всех записях, независимо от формата вывода.
+func (l *Logger) WithRedaction(fields []string) *Logger {
+	return &Logger{
+		zapLogger: l.zapLogger.WithOptions(zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+			return core.Redact(c, fields)
+		})),
+		level: l.level,
+	}
+}
+
 // SetLevel изменяет уровень логирования.
 func (l *Logger) SetLevel(lvl level.LogLevel) {
 	l.level.SetLevel(lvl.ToZapLevel())
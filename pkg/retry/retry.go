@@ -0,0 +1,123 @@
+// Package retry описывает политику повторных попыток с экспоненциальной задержкой
+// и случайным разбросом (jitter).
+package retry
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+const (
+	defaultMaxAttempts = 1
+	defaultBaseDelay   = 100 * time.Millisecond
+	defaultMultiplier  = 1.0
+)
+
+// Policy задает количество попыток и способ расчета задержки между ними.
+// Задержка перед попыткой n (n >= 1 - номер повтора) равна
+// BaseDelay * Multiplier^(n-1), ограничена MaxDelay и смещается
+// на случайную величину в пределах ±Jitter от своего значения.
+type Policy struct {
+	// MaxAttempts - общее количество попыток, включая первую.
+	MaxAttempts int
+	// BaseDelay - задержка перед первым повтором.
+	BaseDelay time.Duration
+	// Multiplier - во сколько раз растет задержка с каждым повтором.
+	Multiplier float64
+	// MaxDelay - верхняя граница задержки без учета jitter. Ноль снимает ограничение.
+	MaxDelay time.Duration
+	// Jitter - доля случайного отклонения задержки, от 0 до 1.
+	Jitter float64
+}
+
+// Normalize возвращает копию политики с исправленными недопустимыми значениями.
+func (p Policy) Normalize() Policy {
+	if p.MaxAttempts < 1 {
+		p.MaxAttempts = defaultMaxAttempts
+	}
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = defaultBaseDelay
+	}
+	if p.Multiplier < 1 || math.IsNaN(p.Multiplier) || math.IsInf(p.Multiplier, 0) {
+		p.Multiplier = defaultMultiplier
+	}
+	if p.MaxDelay < 0 {
+		p.MaxDelay = 0
+	}
+	if p.MaxDelay > 0 && p.MaxDelay < p.BaseDelay {
+		p.MaxDelay = p.BaseDelay
+	}
+	switch {
+	case p.Jitter < 0 || math.IsNaN(p.Jitter):
+		p.Jitter = 0
+	case p.Jitter > 1:
+		p.Jitter = 1
+	}
+	return p
+}
+
+// Delay возвращает задержку перед повтором с номером attempt без учета jitter.
+// Для attempt < 1 задержка нулевая.
+func (p Policy) Delay(attempt int) time.Duration {
+	if attempt < 1 {
+		return 0
+	}
+	p = p.Normalize()
+
+	delay := float64(p.BaseDelay) * math.Pow(p.Multiplier, float64(attempt-1))
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		return p.MaxDelay
+	}
+	if delay >= math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(delay)
+}
+
+// Backoff возвращает задержку перед повтором с номером attempt с учетом jitter.
+func (p Policy) Backoff(attempt int) time.Duration {
+	return p.jittered(p.Delay(attempt), rand.Float64())
+}
+
+// jittered смещает delay на долю (2*r-1)*Jitter, где r равномерно распределено в [0, 1).
+func (p Policy) jittered(delay time.Duration, r float64) time.Duration {
+	jitter := p.Normalize().Jitter
+	if delay <= 0 || jitter == 0 {
+		return delay
+	}
+
+	offset := float64(delay) * jitter * (2*r - 1)
+	result := float64(delay) + offset
+	if result >= math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(result)
+}
+
+// Wait ожидает задержку перед повтором с номером attempt. Возвращает ошибку контекста,
+// если он завершился раньше.
+func (p Policy) Wait(ctx context.Context, attempt int) error {
+	return Sleep(ctx, p.Backoff(attempt))
+}
+
+// Sleep ожидает delay с учетом отмены контекста.
+func Sleep(ctx context.Context, delay time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
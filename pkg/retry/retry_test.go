@@ -0,0 +1,98 @@
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	p := Policy{MaxAttempts: 0, BaseDelay: -time.Second, Multiplier: 0.5, MaxDelay: -1, Jitter: 2}.Normalize()
+
+	assert.Equal(t, 1, p.MaxAttempts)
+	assert.Equal(t, 100*time.Millisecond, p.BaseDelay)
+	assert.InDelta(t, 1.0, p.Multiplier, 0)
+	assert.Zero(t, p.MaxDelay)
+	assert.InDelta(t, 1.0, p.Jitter, 0)
+
+	p = Policy{BaseDelay: time.Second, MaxDelay: time.Millisecond, Jitter: -0.5}.Normalize()
+	assert.Equal(t, time.Second, p.MaxDelay)
+	assert.Zero(t, p.Jitter)
+}
+
+func TestDelay(t *testing.T) {
+	p := Policy{MaxAttempts: 6, BaseDelay: 100 * time.Millisecond, Multiplier: 2, MaxDelay: time.Second}
+
+	expected := []time.Duration{
+		0,
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+		time.Second,
+	}
+	for attempt, want := range expected {
+		assert.Equal(t, want, p.Delay(attempt), "attempt %d", attempt)
+	}
+
+	constant := Policy{BaseDelay: 500 * time.Millisecond, Multiplier: 1}
+	assert.Equal(t, 500*time.Millisecond, constant.Delay(1))
+	assert.Equal(t, 500*time.Millisecond, constant.Delay(10))
+
+	unbounded := Policy{BaseDelay: time.Second, Multiplier: 10}
+	assert.Equal(t, time.Duration(1<<63-1), unbounded.Delay(100))
+}
+
+func TestBackoffJitterBounds(t *testing.T) {
+	p := Policy{BaseDelay: 100 * time.Millisecond, Multiplier: 2, Jitter: 0.25}
+
+	assert.Equal(t, 75*time.Millisecond, p.jittered(100*time.Millisecond, 0))
+	assert.Equal(t, 100*time.Millisecond, p.jittered(100*time.Millisecond, 0.5))
+	assert.Equal(t, 125*time.Millisecond, p.jittered(100*time.Millisecond, 1))
+
+	for attempt := 1; attempt <= 5; attempt++ {
+		base := p.Delay(attempt)
+		low := time.Duration(float64(base) * 0.75)
+		high := time.Duration(float64(base) * 1.25)
+		for range 200 {
+			got := p.Backoff(attempt)
+			require.GreaterOrEqual(t, got, low, "attempt %d", attempt)
+			require.LessOrEqual(t, got, high, "attempt %d", attempt)
+		}
+	}
+
+	noJitter := Policy{BaseDelay: 50 * time.Millisecond, Multiplier: 3}
+	assert.Equal(t, 150*time.Millisecond, noJitter.Backoff(2))
+}
+
+func TestWait(t *testing.T) {
+	t.Run("Waits for delay", func(t *testing.T) {
+		p := Policy{BaseDelay: 20 * time.Millisecond, Multiplier: 1}
+
+		start := time.Now()
+		require.NoError(t, p.Wait(context.Background(), 1))
+		assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	})
+
+	t.Run("Respects context cancellation", func(t *testing.T) {
+		p := Policy{BaseDelay: time.Minute, Multiplier: 1}
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		err := p.Wait(ctx, 1)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("Already cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		require.ErrorIs(t, Sleep(ctx, 0), context.Canceled)
+	})
+}
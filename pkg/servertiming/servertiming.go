@@ -0,0 +1,111 @@
+// Package servertiming собирает длительности этапов обработки запроса для заголовка
+// ответа Server-Timing. Этапы записываются в рекордер из контекста запроса; пока рекордера
+// в контексте нет, запись ничего не стоит.
+package servertiming
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// HeaderName - заголовок ответа с длительностями этапов.
+const HeaderName = "Server-Timing"
+
+// Имена этапов в заголовке Server-Timing.
+const (
+	MetricAuth      = "auth"
+	MetricGRPC      = "grpc"
+	MetricSerialize = "serialize"
+	MetricTotal     = "total"
+)
+
+type timingsContextKey struct{}
+
+// Timings накапливает длительности этапов одного запроса. Повторные записи этапа
+// суммируются, этапы выводятся в порядке первой записи.
+type Timings struct {
+	mu        sync.Mutex
+	names     []string
+	durations map[string]time.Duration
+}
+
+// Add прибавляет d к длительности этапа name.
+func (t *Timings) Add(name string, d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.durations == nil {
+		t.durations = make(map[string]time.Duration)
+	}
+	if _, ok := t.durations[name]; !ok {
+		t.names = append(t.names, name)
+	}
+	t.durations[name] += d
+}
+
+// Header возвращает значение заголовка Server-Timing, например
+// "auth;dur=1.20, grpc;dur=3.45". Длительности указываются в миллисекундах.
+func (t *Timings) Header() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	metrics := make([]string, 0, len(t.names))
+	for _, name := range t.names {
+		ms := float64(t.durations[name]) / float64(time.Millisecond)
+		metrics = append(metrics, fmt.Sprintf("%s;dur=%.2f", name, ms))
+	}
+	return strings.Join(metrics, ", ")
+}
+
+// NewContext возвращает контекст с новым рекордером и сам рекордер.
+func NewContext(ctx context.Context) (context.Context, *Timings) {
+	t := &Timings{}
+	return context.WithValue(ctx, timingsContextKey{}, t), t
+}
+
+// FromContext возвращает рекордер из контекста или nil.
+func FromContext(ctx context.Context) *Timings {
+	t, _ := ctx.Value(timingsContextKey{}).(*Timings)
+	return t
+}
+
+// Without возвращает контекст, в котором записи не попадают в рекордер ctx. Нужен,
+// когда вызов записывается целиком под другим именем, например проверка токена
+// по gRPC записывается как auth, а не grpc.
+func Without(ctx context.Context) context.Context {
+	if FromContext(ctx) == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, timingsContextKey{}, (*Timings)(nil))
+}
+
+// Add прибавляет d к длительности этапа name в рекордере из ctx.
+func Add(ctx context.Context, name string, d time.Duration) {
+	if t := FromContext(ctx); t != nil {
+		t.Add(name, d)
+	}
+}
+
+// Since прибавляет к этапу name время, прошедшее с start.
+func Since(ctx context.Context, name string, start time.Time) {
+	Add(ctx, name, time.Since(start))
+}
+
+// UnaryClientInterceptor записывает длительность унарных gRPC вызовов как этап grpc.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if FromContext(ctx) == nil {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		Since(ctx, MetricGRPC, start)
+		return err
+	}
+}
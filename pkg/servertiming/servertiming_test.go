@@ -0,0 +1,51 @@
+package servertiming
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestTimingsHeader(t *testing.T) {
+	var timings Timings
+	assert.Empty(t, timings.Header())
+
+	timings.Add(MetricAuth, 1500*time.Microsecond)
+	timings.Add(MetricGRPC, 2*time.Millisecond)
+	timings.Add(MetricAuth, 500*time.Microsecond)
+
+	assert.Equal(t, "auth;dur=2.00, grpc;dur=2.00", timings.Header())
+}
+
+func TestContext(t *testing.T) {
+	ctx := context.Background()
+	assert.Nil(t, FromContext(ctx))
+	Add(ctx, MetricAuth, time.Millisecond)
+
+	ctx, timings := NewContext(ctx)
+	require.Same(t, timings, FromContext(ctx))
+
+	Add(ctx, MetricSerialize, time.Millisecond)
+	Add(Without(ctx), MetricGRPC, time.Millisecond)
+
+	assert.Equal(t, "serialize;dur=1.00", timings.Header())
+	assert.Nil(t, FromContext(Without(ctx)))
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	interceptor := UnaryClientInterceptor()
+	invoker := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		time.Sleep(time.Millisecond)
+		return nil
+	}
+
+	ctx, timings := NewContext(context.Background())
+	require.NoError(t, interceptor(ctx, "/svc/Method", nil, nil, nil, invoker))
+	require.NoError(t, interceptor(context.Background(), "/svc/Method", nil, nil, nil, invoker))
+
+	assert.Regexp(t, `^grpc;dur=\d+\.\d{2}$`, timings.Header())
+}
@@ -0,0 +1,117 @@
+// Package tracing настраивает распределенную трассировку OpenTelemetry: экспорт спанов
+// по OTLP и вспомогательные функции для создания спанов в коде приложения.
+// Пока адрес экспорта не задан, трассировка отключена и спаны ничего не стоят.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/logger"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Имена атрибутов спанов.
+const (
+	AttrRequestID     = "request_id"
+	AttrUserID        = "user_id"
+	AttrCalculationID = "calculation_id"
+	AttrOperationID   = "operation_id"
+	AttrOperationType = "operation_type"
+)
+
+const instrumentationName = "github.com/flexer2006/y.lms-final-task-calc-go"
+
+// ShutdownFunc отправляет накопленные спаны и останавливает экспорт.
+type ShutdownFunc func(ctx context.Context) error
+
+// Config - настройки экспорта спанов одного сервиса.
+type Config struct {
+	// ServiceName - имя сервиса в трассах.
+	ServiceName string
+	// Endpoint - адрес OTLP/gRPC приемника (host:port). Пустой адрес отключает трассировку.
+	Endpoint string
+	// Insecure отключает TLS при подключении к приемнику.
+	Insecure bool
+	// SampleRatio - доля записываемых трасс от 0 до 1.
+	SampleRatio float64
+}
+
+// Setup регистрирует глобальный провайдер спанов с экспортом по OTLP и распространение
+// контекста трассы в заголовках W3C Trace Context. Если адрес не задан, глобальный
+// провайдер остается пустым, и Setup возвращает ShutdownFunc, которая ничего не делает.
+func Setup(ctx context.Context, cfg Config) (ShutdownFunc, error) {
+	if cfg.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.Endpoint)}
+	if cfg.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("creating OTLP trace exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(cfg.ServiceName)))
+	if err != nil {
+		return nil, fmt.Errorf("creating trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return func(ctx context.Context) error {
+		if err := provider.Shutdown(ctx); err != nil {
+			return fmt.Errorf("shutting down tracer provider: %w", err)
+		}
+		return nil
+	}, nil
+}
+
+// Start начинает дочерний спан текущего спана из ctx. Если в контексте есть
+// идентификатор запроса журнала, он добавляется к спану атрибутом request_id.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	ctx, span := otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+	AnnotateRequestID(ctx)
+	return ctx, span
+}
+
+// AnnotateRequestID добавляет идентификатор запроса из ctx к текущему спану.
+func AnnotateRequestID(ctx context.Context) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+
+	if id, ok := logger.RequestID(ctx); ok && id != "" {
+		span.SetAttributes(attribute.String(AttrRequestID, id))
+	}
+}
+
+// RecordError отмечает спан как завершившийся ошибкой. Отмена контекста ошибкой
+// не считается.
+func RecordError(span trace.Span, err error) {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,57 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/flexer2006/y.lms-final-task-calc-go/pkg/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSetupWithoutEndpoint(t *testing.T) {
+	previous := otel.GetTracerProvider()
+
+	shutdown, err := tracing.Setup(context.Background(), tracing.Config{ServiceName: "test"})
+	require.NoError(t, err)
+	require.NoError(t, shutdown(context.Background()))
+
+	assert.Same(t, previous, otel.GetTracerProvider(), "global provider must stay untouched")
+
+	_, span := tracing.Start(context.Background(), "noop")
+	assert.False(t, span.IsRecording())
+	span.End()
+}
+
+func TestRecordError(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	tracer := provider.Tracer("test")
+
+	for _, tc := range []struct {
+		name     string
+		err      error
+		expected codes.Code
+	}{
+		{name: "No error", err: nil, expected: codes.Unset},
+		{name: "Failure", err: errors.New("boom"), expected: codes.Error},
+		{name: "Cancelled", err: context.Canceled, expected: codes.Unset},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			exporter.Reset()
+
+			_, span := tracer.Start(context.Background(), tc.name)
+			tracing.RecordError(span, tc.err)
+			span.End()
+
+			spans := exporter.GetSpans()
+			require.Len(t, spans, 1)
+			assert.Equal(t, tc.expected, spans[0].Status.Code)
+		})
+	}
+}